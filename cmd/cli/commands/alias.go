@@ -0,0 +1,145 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/docker/cli/cli/command"
+	"github.com/docker/model-runner/cmd/cli/pkg/modelalias"
+	"github.com/spf13/cobra"
+)
+
+// newAliasCmd returns the "docker model alias" parent command. Its
+// subcommands manage model aliases stored on disk, so they do not require a
+// running backend and override PersistentPreRunE accordingly.
+func newAliasCmd(cli *command.DockerCli) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage local model aliases",
+		Long: `Manage local model aliases. An alias is a short name that stands for a full
+model reference wherever a model is pulled, run or inspected. Model IDs always
+take precedence over aliases.`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return initDockerCLI(cmd, args, cli, globalOptions)
+		},
+	}
+
+	c.AddCommand(
+		newAliasAddCmd(),
+		newAliasRmCmd(),
+		newAliasLsCmd(),
+	)
+	return c
+}
+
+// aliasStore opens the alias store using the Docker config directory derived
+// from the current CLI configuration.
+func aliasStore() (*modelalias.Store, error) {
+	dir, err := dockerConfigDir()
+	if err != nil {
+		return nil, fmt.Errorf("unable to determine Docker config directory: %w", err)
+	}
+	return modelalias.New(dir), nil
+}
+
+// resolveModelAlias returns the model reference that model stands for if it
+// is an alias, and model unchanged otherwise.
+func resolveModelAlias(model string) (string, error) {
+	store, err := aliasStore()
+	if err != nil {
+		return "", err
+	}
+	resolved, err := store.Resolve(model)
+	if err != nil {
+		return "", fmt.Errorf("unable to resolve model alias: %w", err)
+	}
+	return resolved, nil
+}
+
+// newAliasAddCmd returns the "alias add" command.
+func newAliasAddCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "add ALIAS MODEL",
+		Short: "Add an alias for a model reference",
+		Args:  requireExactArgs(2, "alias add", "ALIAS MODEL"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := aliasStore()
+			if err != nil {
+				return fmt.Errorf("unable to open alias store: %w", err)
+			}
+			if err := store.Add(args[0], args[1]); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Alias %q added for %s.\n", args[0], args[1])
+			return nil
+		},
+	}
+}
+
+// newAliasRmCmd returns the "alias rm" command.
+func newAliasRmCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "rm ALIAS [ALIAS...]",
+		Aliases: []string{"remove"},
+		Short:   "Remove one or more model aliases",
+		Args:    cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := aliasStore()
+			if err != nil {
+				return fmt.Errorf("unable to open alias store: %w", err)
+			}
+
+			// Attempt removal of all named aliases; collect errors.
+			var errs []error
+			for _, alias := range args {
+				if err := store.Remove(alias); err != nil {
+					errs = append(errs, fmt.Errorf("%s: %w", alias, err))
+					continue
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "Alias %q removed.\n", alias)
+			}
+
+			if len(errs) > 0 {
+				for _, e := range errs {
+					fmt.Fprintln(cmd.ErrOrStderr(), "Error:", e)
+				}
+				return fmt.Errorf("one or more aliases could not be removed")
+			}
+			return nil
+		},
+	}
+}
+
+// newAliasLsCmd returns the "alias ls" command.
+func newAliasLsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:     "ls",
+		Aliases: []string{"list"},
+		Short:   "List model aliases",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := aliasStore()
+			if err != nil {
+				return fmt.Errorf("unable to open alias store: %w", err)
+			}
+
+			aliases, err := store.List()
+			if err != nil {
+				return fmt.Errorf("unable to list aliases: %w", err)
+			}
+
+			var buf bytes.Buffer
+			table := newTable(&buf)
+			table.Header([]string{"ALIAS", "MODEL"})
+			for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+				table.Append([]string{alias, aliases[alias]})
+			}
+			table.Render()
+
+			fmt.Fprint(cmd.OutOrStdout(), buf.String())
+			return nil
+		},
+	}
+}
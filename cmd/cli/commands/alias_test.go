@@ -0,0 +1,81 @@
+package commands
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupAliasTest isolates the alias store for a single test by pointing
+// DOCKER_CONFIG at a temporary directory.
+func setupAliasTest(t *testing.T) {
+	t.Helper()
+	t.Setenv("DOCKER_CONFIG", t.TempDir())
+	dockerCLI = nil // force dockerConfigDir() to use DOCKER_CONFIG
+}
+
+// TestAliasAddResolve verifies that "alias add" stores an alias that model
+// references resolve through, and that model IDs are left untouched.
+func TestAliasAddResolve(t *testing.T) {
+	setupAliasTest(t)
+
+	cmd := newAliasAddCmd()
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"qwen", "hf.co/bartowski/Qwen2.5-7B-Instruct-GGUF:Q4_K_M"})
+	require.NoError(t, cmd.Execute())
+	assert.Contains(t, out.String(), `"qwen"`)
+
+	got, err := resolveModelAlias("qwen")
+	require.NoError(t, err)
+	assert.Equal(t, "hf.co/bartowski/Qwen2.5-7B-Instruct-GGUF:Q4_K_M", got)
+
+	got, err = resolveModelAlias("0123456789ab")
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789ab", got)
+}
+
+// TestAliasAddRejectsID verifies that an alias that looks like a model ID
+// cannot be added.
+func TestAliasAddRejectsID(t *testing.T) {
+	setupAliasTest(t)
+
+	cmd := newAliasAddCmd()
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"0123456789ab", "ai/smollm2"})
+	err := cmd.Execute()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "model IDs")
+}
+
+// TestAliasLsRm verifies that "alias ls" lists aliases and that "alias rm"
+// removes them.
+func TestAliasLsRm(t *testing.T) {
+	setupAliasTest(t)
+	store, err := aliasStore()
+	require.NoError(t, err)
+	require.NoError(t, store.Add("smol", "ai/smollm2:360M-Q4_K_M"))
+
+	ls := newAliasLsCmd()
+	out := new(bytes.Buffer)
+	ls.SetOut(out)
+	ls.SetArgs([]string{})
+	require.NoError(t, ls.Execute())
+	assert.Contains(t, out.String(), "ALIAS")
+	assert.Contains(t, out.String(), "smol")
+	assert.Contains(t, out.String(), "ai/smollm2:360M-Q4_K_M")
+
+	rm := newAliasRmCmd()
+	rm.SetOut(new(bytes.Buffer))
+	rm.SetErr(new(bytes.Buffer))
+	rm.SetArgs([]string{"smol"})
+	require.NoError(t, rm.Execute())
+
+	got, err := resolveModelAlias("smol")
+	require.NoError(t, err)
+	assert.Equal(t, "smol", got)
+}
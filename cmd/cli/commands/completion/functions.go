@@ -1,12 +1,18 @@
 package completion
 
 import (
+	"slices"
 	"strings"
 
 	"github.com/docker/model-runner/cmd/cli/desktop"
+	dmrm "github.com/docker/model-runner/pkg/inference/models"
 	"github.com/spf13/cobra"
 )
 
+// maxModelCandidates caps the number of local model names offered, keeping
+// completion responsive for large stores.
+const maxModelCandidates = 100
+
 func NoComplete(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 	return nil, cobra.ShellCompDirectiveNoFileComp
 }
@@ -24,16 +30,22 @@ func ModelNames(desktopClient func() *desktop.Client, limit int) cobra.Completio
 		if limit > 0 && len(args) >= limit {
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
-		models, err := desktopClient().List()
+		models, err := desktopClient().ListByName(toComplete)
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
-		var names []string
-		for _, m := range models {
-			names = append(names, m.Tags...)
-		}
-		return names, cobra.ShellCompDirectiveNoFileComp
+		return modelNameCandidates(models, toComplete, maxModelCandidates), cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// modelNameCandidates returns the tags of models that start with toComplete,
+// sorted and capped at limit.
+func modelNameCandidates(models []dmrm.Model, toComplete string, limit int) []string {
+	var names []string
+	for _, m := range models {
+		names = append(names, m.Tags...)
 	}
+	return capCandidates(names, toComplete, limit)
 }
 
 // ModelNamesAndTags offers completion that matches the base model name along with its tags.
@@ -51,42 +63,61 @@ func ModelNamesAndTags(desktopClient func() *desktop.Client, limit int) cobra.Co
 			return nil, cobra.ShellCompDirectiveNoFileComp
 		}
 
-		models, err := desktopClient().List()
+		models, err := desktopClient().ListByName(toComplete)
 		if err != nil {
 			return nil, cobra.ShellCompDirectiveError
 		}
+		return modelNameAndTagCandidates(models, toComplete, maxModelCandidates), cobra.ShellCompDirectiveNoSpace
+	}
+}
 
-		var names []string
+// modelNameAndTagCandidates returns the base names and tags of models that
+// start with toComplete, sorted and capped at limit.
+func modelNameAndTagCandidates(models []dmrm.Model, toComplete string, limit int) []string {
+	var names []string
 
-		modelNames := make(map[string]bool)
-		modelTags := make(map[string][]string)
+	modelNames := make(map[string]bool)
+	modelTags := make(map[string][]string)
 
-		for _, m := range models {
-			for _, tag := range m.Tags {
-				// Extract model name (everything before the first colon or the full tag if no colon).
-				modelName, _, _ := strings.Cut(tag, ":")
-				modelNames[modelName] = true
-				modelTags[modelName] = append(modelTags[modelName], tag)
-			}
+	for _, m := range models {
+		for _, tag := range m.Tags {
+			// Extract model name (everything before the first colon or the full tag if no colon).
+			modelName, _, _ := strings.Cut(tag, ":")
+			modelNames[modelName] = true
+			modelTags[modelName] = append(modelTags[modelName], tag)
 		}
+	}
 
-		for name := range modelNames {
-			// If model has multiple tags, suggest the base model name and all specific tags.
-			if len(modelTags[name]) > 1 {
-				names = append(names, name)
-				for _, tag := range modelTags[name] {
-					names = append(names, tag)
-					// If this model doesn't have a tag, also add the :latest variant.
-					if tag == name {
-						names = append(names, tag+":latest")
-					}
+	for name := range modelNames {
+		// If model has multiple tags, suggest the base model name and all specific tags.
+		if len(modelTags[name]) > 1 {
+			names = append(names, name)
+			for _, tag := range modelTags[name] {
+				names = append(names, tag)
+				// If this model doesn't have a tag, also add the :latest variant.
+				if tag == name {
+					names = append(names, tag+":latest")
 				}
-			} else {
-				// If only one tag, just suggest that tag to avoid duplication.
-				names = append(names, modelTags[name][0])
 			}
+		} else {
+			// If only one tag, just suggest that tag to avoid duplication.
+			names = append(names, modelTags[name][0])
 		}
+	}
+
+	return capCandidates(names, toComplete, limit)
+}
 
-		return names, cobra.ShellCompDirectiveNoSpace
+// capCandidates keeps the names starting with toComplete, sorted and without
+// duplicates, and returns at most limit of them.
+func capCandidates(names []string, toComplete string, limit int) []string {
+	names = slices.DeleteFunc(names, func(name string) bool {
+		return !strings.HasPrefix(name, toComplete)
+	})
+	slices.Sort(names)
+	names = slices.Compact(names)
+	if len(names) > limit {
+		names = names[:limit]
 	}
+	return names
 }
@@ -0,0 +1,73 @@
+package completion
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	dmrm "github.com/docker/model-runner/pkg/inference/models"
+)
+
+func testModels() []dmrm.Model {
+	return []dmrm.Model{
+		{ID: "sha256:1", Tags: []string{"ai/smollm2:latest", "ai/smollm2:360M"}},
+		{ID: "sha256:2", Tags: []string{"ai/smolvlm:latest"}},
+		{ID: "sha256:3", Tags: []string{"ai/llama3.2:latest"}},
+	}
+}
+
+func TestModelNameCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{name: "no prefix", toComplete: "", want: []string{"ai/llama3.2:latest", "ai/smollm2:360M", "ai/smollm2:latest", "ai/smolvlm:latest"}},
+		{name: "shared prefix", toComplete: "ai/smo", want: []string{"ai/smollm2:360M", "ai/smollm2:latest", "ai/smolvlm:latest"}},
+		{name: "single model", toComplete: "ai/smollm2:3", want: []string{"ai/smollm2:360M"}},
+		{name: "no match", toComplete: "ai/qwen", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modelNameCandidates(testModels(), tt.toComplete, maxModelCandidates)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("candidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelNameAndTagCandidates(t *testing.T) {
+	tests := []struct {
+		name       string
+		toComplete string
+		want       []string
+	}{
+		{name: "no prefix", toComplete: "", want: []string{"ai/llama3.2:latest", "ai/smollm2", "ai/smollm2:360M", "ai/smollm2:latest", "ai/smolvlm:latest"}},
+		{name: "base name and tags", toComplete: "ai/smoll", want: []string{"ai/smollm2", "ai/smollm2:360M", "ai/smollm2:latest"}},
+		{name: "no match", toComplete: "ai/qwen", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modelNameAndTagCandidates(testModels(), tt.toComplete, maxModelCandidates)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("candidates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestModelCandidatesCapped(t *testing.T) {
+	var models []dmrm.Model
+	for i := range 2 * maxModelCandidates {
+		models = append(models, dmrm.Model{Tags: []string{fmt.Sprintf("ai/model%03d:latest", i)}})
+	}
+
+	if got := modelNameCandidates(models, "ai/", maxModelCandidates); len(got) != maxModelCandidates {
+		t.Errorf("got %d candidates, want %d", len(got), maxModelCandidates)
+	}
+	got := modelNameAndTagCandidates(models, "ai/model00", 3)
+	if want := []string{"ai/model000:latest", "ai/model001:latest", "ai/model002:latest"}; !slices.Equal(got, want) {
+		t.Errorf("candidates = %v, want %v", got, want)
+	}
+}
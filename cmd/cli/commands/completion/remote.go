@@ -0,0 +1,186 @@
+package completion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	// DefaultCatalogURL is the hub catalog queried by RemoteModelNames unless
+	// overridden with the MODEL_RUNNER_CATALOG_URL environment variable.
+	DefaultCatalogURL = "https://hub.docker.com"
+	// catalogURLEnv overrides the hub catalog URL.
+	catalogURLEnv = "MODEL_RUNNER_CATALOG_URL"
+	// defaultCatalogOrg is the organization assumed for references without one.
+	defaultCatalogOrg = "ai"
+	// catalogPageSize is the number of repositories fetched per organization.
+	catalogPageSize = 100
+	// catalogTimeout keeps completion responsive when the hub is slow.
+	catalogTimeout = 2 * time.Second
+	// catalogCacheTTL is how long fetched repository lists are reused.
+	catalogCacheTTL = 5 * time.Minute
+)
+
+// remoteCompleter suggests model references from a hub catalog, caching each
+// organization's repository list on disk since every completion runs in a
+// fresh process.
+type remoteCompleter struct {
+	httpClient *http.Client
+	baseURL    string
+	cacheDir   string
+	ttl        time.Duration
+	now        func() time.Time
+}
+
+func newRemoteCompleter() *remoteCompleter {
+	baseURL := os.Getenv(catalogURLEnv)
+	if baseURL == "" {
+		baseURL = DefaultCatalogURL
+	}
+	var cacheDir string
+	if dir, err := os.UserCacheDir(); err == nil {
+		cacheDir = filepath.Join(dir, "docker-model", "completion")
+	}
+	return &remoteCompleter{
+		httpClient: &http.Client{Timeout: catalogTimeout},
+		baseURL:    baseURL,
+		cacheDir:   cacheDir,
+		ttl:        catalogCacheTTL,
+		now:        time.Now,
+	}
+}
+
+// RemoteModelNames offers completion for model references published in the
+// hub catalog. References without an organization are completed against the
+// default "ai" organization, matching how pull normalizes them. References
+// to other registries (e.g. hf.co/...) are not completed.
+func RemoteModelNames(limit int) cobra.CompletionFunc {
+	return newRemoteCompleter().complete(limit)
+}
+
+func (c *remoteCompleter) complete(limit int) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if limit > 0 && len(args) >= limit {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		org, prefix := defaultCatalogOrg, ""
+		if before, _, found := strings.Cut(toComplete, "/"); found {
+			// Registries other than the hub have a dot in their first component.
+			if strings.ContainsAny(before, ".:") {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			org, prefix = strings.ToLower(before), before+"/"
+		}
+
+		ctx := context.Background()
+		if cmd != nil && cmd.Context() != nil {
+			ctx = cmd.Context()
+		}
+		repos, err := c.repositories(ctx, org)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveError
+		}
+
+		var names []string
+		for _, repo := range repos {
+			name := prefix + repo
+			if strings.HasPrefix(strings.ToLower(name), strings.ToLower(toComplete)) {
+				names = append(names, name)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// catalogCacheEntry is the on-disk cache format for one organization.
+type catalogCacheEntry struct {
+	FetchedAt    time.Time `json:"fetched_at"`
+	Repositories []string  `json:"repositories"`
+}
+
+// repositories returns the public repository names of org, from the cache
+// when it is fresh.
+func (c *remoteCompleter) repositories(ctx context.Context, org string) ([]string, error) {
+	cachePath := c.cachePath(org)
+	if cachePath != "" {
+		if data, err := os.ReadFile(cachePath); err == nil {
+			var entry catalogCacheEntry
+			if json.Unmarshal(data, &entry) == nil && c.now().Sub(entry.FetchedAt) < c.ttl {
+				return entry.Repositories, nil
+			}
+		}
+	}
+
+	repos, err := c.fetchRepositories(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+
+	if cachePath != "" {
+		// Caching is best effort; completion still works without it.
+		if data, err := json.Marshal(catalogCacheEntry{FetchedAt: c.now(), Repositories: repos}); err == nil {
+			if os.MkdirAll(filepath.Dir(cachePath), 0o755) == nil {
+				_ = os.WriteFile(cachePath, data, 0o644)
+			}
+		}
+	}
+	return repos, nil
+}
+
+func (c *remoteCompleter) cachePath(org string) string {
+	if c.cacheDir == "" {
+		return ""
+	}
+	return filepath.Join(c.cacheDir, url.PathEscape(org)+".json")
+}
+
+// fetchRepositories lists the most pulled public repositories of org.
+func (c *remoteCompleter) fetchRepositories(ctx context.Context, org string) ([]string, error) {
+	params := url.Values{}
+	params.Set("page_size", fmt.Sprintf("%d", catalogPageSize))
+	params.Set("ordering", "pull_count")
+	fullURL := fmt.Sprintf("%s/v2/repositories/%s/?%s", strings.TrimSuffix(c.baseURL, "/"), url.PathEscape(org), params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fullURL, http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching catalog: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status from catalog: %s", resp.Status)
+	}
+
+	var response struct {
+		Results []struct {
+			Name      string `json:"name"`
+			IsPrivate bool   `json:"is_private"`
+		} `json:"results"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding catalog: %w", err)
+	}
+
+	repos := make([]string, 0, len(response.Results))
+	for _, repo := range response.Results {
+		if !repo.IsPrivate && repo.Name != "" {
+			repos = append(repos, repo.Name)
+		}
+	}
+	return repos, nil
+}
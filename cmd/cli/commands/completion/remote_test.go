@@ -0,0 +1,127 @@
+package completion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// newMockCatalog serves repository lists per organization and counts requests.
+func newMockCatalog(t *testing.T, repos map[string][]string) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v2/repositories/{org}/", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		names, ok := repos[r.PathValue("org")]
+		if !ok {
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		type repo struct {
+			Name      string `json:"name"`
+			IsPrivate bool   `json:"is_private"`
+		}
+		var results []repo
+		for _, name := range names {
+			results = append(results, repo{Name: name})
+		}
+		results = append(results, repo{Name: "secret", IsPrivate: true})
+		_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(func() {
+		server.Client().CloseIdleConnections()
+		server.Close()
+	})
+	return server, &requests
+}
+
+func newTestCompleter(t *testing.T, server *httptest.Server) *remoteCompleter {
+	t.Helper()
+	return &remoteCompleter{
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		cacheDir:   t.TempDir(),
+		ttl:        time.Minute,
+		now:        time.Now,
+	}
+}
+
+func TestRemoteModelNames(t *testing.T) {
+	server, _ := newMockCatalog(t, map[string][]string{
+		"ai":    {"smollm2", "llama3.2", "smolvlm"},
+		"myorg": {"custom"},
+	})
+	complete := newTestCompleter(t, server).complete(1)
+
+	tests := []struct {
+		name       string
+		args       []string
+		toComplete string
+		want       []string
+	}{
+		{name: "default org", toComplete: "", want: []string{"smollm2", "llama3.2", "smolvlm"}},
+		{name: "default org prefix", toComplete: "smo", want: []string{"smollm2", "smolvlm"}},
+		{name: "explicit org", toComplete: "ai/l", want: []string{"ai/llama3.2"}},
+		{name: "other org", toComplete: "myorg/", want: []string{"myorg/custom"}},
+		{name: "other registry", toComplete: "hf.co/org", want: nil},
+		{name: "arg limit reached", args: []string{"ai/smollm2"}, toComplete: "", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, directive := complete(&cobra.Command{}, tt.args, tt.toComplete)
+			if directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("directive = %v, want %v", directive, cobra.ShellCompDirectiveNoFileComp)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("completions = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteModelNamesCachesResults(t *testing.T) {
+	server, requests := newMockCatalog(t, map[string][]string{"ai": {"smollm2"}})
+	completer := newTestCompleter(t, server)
+	now := time.Now()
+	completer.now = func() time.Time { return now }
+	complete := completer.complete(1)
+
+	for range 3 {
+		if got, _ := complete(&cobra.Command{}, nil, ""); !slices.Equal(got, []string{"smollm2"}) {
+			t.Fatalf("completions = %v, want [smollm2]", got)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("catalog requests = %d, want 1", got)
+	}
+
+	// Once the cache expires, the catalog is queried again.
+	now = now.Add(2 * time.Minute)
+	if _, directive := complete(&cobra.Command{}, nil, ""); directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Fatalf("directive = %v, want %v", directive, cobra.ShellCompDirectiveNoFileComp)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("catalog requests after expiry = %d, want 2", got)
+	}
+}
+
+func TestRemoteModelNamesCatalogError(t *testing.T) {
+	server, _ := newMockCatalog(t, map[string][]string{})
+	complete := newTestCompleter(t, server).complete(1)
+
+	got, directive := complete(&cobra.Command{}, nil, "unknown/")
+	if directive != cobra.ShellCompDirectiveError {
+		t.Errorf("directive = %v, want %v", directive, cobra.ShellCompDirectiveError)
+	}
+	if got != nil {
+		t.Errorf("completions = %v, want none", got)
+	}
+}
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	"github.com/spf13/cobra"
+)
+
+func newDebugCmd() *cobra.Command {
+	c := &cobra.Command{
+		Use:   "debug",
+		Short: "Show information for debugging Docker Model Runner",
+	}
+	c.AddCommand(newDebugConfigCmd())
+	return c
+}
+
+func newDebugConfigCmd() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "config [OPTIONS]",
+		Short: "Show the effective configuration of Docker Model Runner, with credentials redacted",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			config, err := desktopClient.DebugConfig()
+			if err != nil {
+				return handleClientError(err, "Failed to get configuration")
+			}
+			output, err := formatDebugConfig(config, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatDebugConfig renders the configuration as JSON or as a table of
+// variables sorted by name, showing unset variables as "-".
+func formatDebugConfig(config map[string]string, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		return formatter.ToStandardJSON(config)
+	}
+
+	var buf bytes.Buffer
+	table := newTable(&buf)
+	table.Header([]string{"VARIABLE", "VALUE"})
+	for _, key := range slices.Sorted(maps.Keys(config)) {
+		value := config[key]
+		if value == "" {
+			value = "-"
+		}
+		table.Append([]string{key, value})
+	}
+	table.Render()
+	return buf.String(), nil
+}
@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFormatDebugConfig(t *testing.T) {
+	config := map[string]string{
+		"MODELS_PATH":      "/var/lib/models",
+		"DOCKER_PASSWORD":  "<redacted>",
+		"DEFAULT_REGISTRY": "",
+	}
+
+	output, err := formatDebugConfig(config, false)
+	if err != nil {
+		t.Fatalf("formatDebugConfig() error = %v", err)
+	}
+	for _, want := range []string{"VARIABLE", "MODELS_PATH", "/var/lib/models", "<redacted>"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+	if strings.Index(output, "DEFAULT_REGISTRY") > strings.Index(output, "DOCKER_PASSWORD") ||
+		strings.Index(output, "DOCKER_PASSWORD") > strings.Index(output, "MODELS_PATH") {
+		t.Errorf("Expected variables sorted by name, got:\n%s", output)
+	}
+
+	output, err = formatDebugConfig(config, true)
+	if err != nil {
+		t.Fatalf("formatDebugConfig() error = %v", err)
+	}
+	if !strings.Contains(output, `"MODELS_PATH": "/var/lib/models"`) || !strings.Contains(output, `"DEFAULT_REGISTRY": ""`) ||
+		!strings.Contains(output, `"DOCKER_PASSWORD": "<redacted>"`) {
+		t.Errorf("Expected JSON output with every variable, got:\n%s", output)
+	}
+}
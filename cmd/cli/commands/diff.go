@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/docker/go-units"
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	dmrm "github.com/docker/model-runner/pkg/inference/models"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "diff [OPTIONS] MODEL1 MODEL2",
+		Short: "Show the differences between two local models",
+		Long:  "Show how the config, labels, layers and size of MODEL2 differ from those of MODEL1.",
+		Args:  requireExactArgs(2, "diff", "MODEL1 MODEL2"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			diff, err := desktopClient.Diff(args[0], args[1])
+			if err != nil {
+				return handleClientError(err, "Failed to diff models")
+			}
+			if format == "json" {
+				output, err := formatter.ToStandardJSON(diff)
+				if err != nil {
+					return err
+				}
+				fmt.Fprint(cmd.OutOrStdout(), output)
+				return nil
+			}
+			fmt.Fprint(cmd.OutOrStdout(), formatModelDiff(args[0], args[1], diff))
+			return nil
+		},
+		ValidArgsFunction: completion.ModelNames(getDesktopClient, 2),
+	}
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatModelDiff renders a model diff in a unified-diff-like layout, with
+// "-" marking what only MODEL1 has and "+" what only MODEL2 has.
+func formatModelDiff(a, b string, diff dmrm.ModelDiff) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s (%s)\n", a, diff.A)
+	fmt.Fprintf(&sb, "+++ %s (%s)\n", b, diff.B)
+	if diff.Identical() {
+		sb.WriteString("\nModels are identical\n")
+		return sb.String()
+	}
+
+	writeChanges := func(title string, changes []dmrm.FieldChange) {
+		if len(changes) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n%s:\n", title)
+		for _, change := range changes {
+			fmt.Fprintf(&sb, "  %s: %s -> %s\n", change.Field, diffValue(change.A), diffValue(change.B))
+		}
+	}
+	writeChanges("Config", diff.Config)
+	writeChanges("Labels", diff.Labels)
+
+	if len(diff.RemovedLayers) > 0 || len(diff.AddedLayers) > 0 {
+		sb.WriteString("\nLayers:\n")
+		for _, layer := range diff.RemovedLayers {
+			fmt.Fprintf(&sb, "  - %s (%s, %s)\n", layer.Digest, layer.MediaType, diffSize(layer.Size))
+		}
+		for _, layer := range diff.AddedLayers {
+			fmt.Fprintf(&sb, "  + %s (%s, %s)\n", layer.Digest, layer.MediaType, diffSize(layer.Size))
+		}
+	}
+
+	fmt.Fprintf(&sb, "\nSize: %s -> %s\n", diffSize(diff.SizeA), diffSize(diff.SizeB))
+	return sb.String()
+}
+
+func diffValue(value string) string {
+	if value == "" {
+		return "<unset>"
+	}
+	return value
+}
+
+func diffSize(size int64) string {
+	return units.CustomSize("%.2f%s", float64(size), 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"})
+}
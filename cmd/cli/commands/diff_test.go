@@ -0,0 +1,76 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	dmrm "github.com/docker/model-runner/pkg/inference/models"
+)
+
+func TestFormatModelDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		diff     dmrm.ModelDiff
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "context size variant",
+			diff: dmrm.ModelDiff{
+				A:      "sha256:aaa",
+				B:      "sha256:bbb",
+				Config: []dmrm.FieldChange{{Field: "context_size", B: "4096"}},
+				SizeA:  1000,
+				SizeB:  1000,
+			},
+			contains: []string{
+				"--- ai/model (sha256:aaa)\n",
+				"+++ ai/variant (sha256:bbb)\n",
+				"Config:\n  context_size: <unset> -> 4096\n",
+				"Size: 1.00kB -> 1.00kB\n",
+			},
+			excludes: []string{"Labels:", "Layers:", "identical"},
+		},
+		{
+			name: "changed layers and labels",
+			diff: dmrm.ModelDiff{
+				A:             "sha256:aaa",
+				B:             "sha256:bbb",
+				Labels:        []dmrm.FieldChange{{Field: "org.opencontainers.image.licenses", A: "MIT"}},
+				RemovedLayers: []dmrm.LayerSummary{{Digest: "sha256:old", MediaType: "application/vnd.docker.ai.gguf.v3", Size: 2000}},
+				AddedLayers:   []dmrm.LayerSummary{{Digest: "sha256:new", MediaType: "application/vnd.docker.ai.gguf.v3", Size: 1000}},
+				SizeA:         2000,
+				SizeB:         1000,
+			},
+			contains: []string{
+				"Labels:\n  org.opencontainers.image.licenses: MIT -> <unset>\n",
+				"  - sha256:old (application/vnd.docker.ai.gguf.v3, 2.00kB)\n",
+				"  + sha256:new (application/vnd.docker.ai.gguf.v3, 1.00kB)\n",
+				"Size: 2.00kB -> 1.00kB\n",
+			},
+			excludes: []string{"Config:"},
+		},
+		{
+			name:     "identical models",
+			diff:     dmrm.ModelDiff{A: "sha256:aaa", B: "sha256:aaa", SizeA: 1000, SizeB: 1000},
+			contains: []string{"Models are identical\n"},
+			excludes: []string{"Size:"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := formatModelDiff("ai/model", "ai/variant", tt.diff)
+			for _, want := range tt.contains {
+				if !strings.Contains(output, want) {
+					t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(output, unwanted) {
+					t.Errorf("Expected output not to contain %q, got:\n%s", unwanted, output)
+				}
+			}
+		})
+	}
+}
@@ -1,19 +1,16 @@
 package commands
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"io"
 	"net/http"
 	"os"
 	"regexp"
 	"slices"
 	"strings"
-)
 
-// MaxImageSizeBytes is the maximum allowed size for image files (100MB)
-const MaxImageSizeBytes int64 = 100 * 1024 * 1024
+	"github.com/docker/model-runner/cmd/cli/desktop"
+)
 
 // extractImagePaths finds image file paths in the input string using regex
 // Matches paths like: /path/to/file.jpg, ./image.png, C:\photos\pic.webp
@@ -78,59 +75,6 @@ func normalizeFilePath(filePath string) string {
 	).Replace(filePath)
 }
 
-// encodeImageToDataURL reads an image file, validates it, and encodes it to a base64 data URL
-// Returns a data URL like: data:image/jpeg;base64,/9j/4AAQ...
-func encodeImageToDataURL(filePath string) (string, error) {
-	file, err := os.Open(filePath)
-	if err != nil {
-		return "", err
-	}
-	defer file.Close()
-
-	// Read first 512 bytes to detect content type
-	buf := make([]byte, 512)
-	_, err = file.Read(buf)
-	if err != nil {
-		return "", err
-	}
-
-	contentType := http.DetectContentType(buf)
-	// Validate that this is an image file using both content and extension
-	if !isImageFileByContentAndExtension(buf, filePath) {
-		return "", fmt.Errorf("invalid image type for file: %s", filePath)
-	}
-
-	info, err := file.Stat()
-	if err != nil {
-		return "", err
-	}
-
-	// Check if the file size exceeds the maximum limit
-	if info.Size() > MaxImageSizeBytes {
-		return "", fmt.Errorf("file size exceeds maximum limit (%d MB)", MaxImageSizeBytes/(1024*1024))
-	}
-
-	// Read entire file
-	buf = make([]byte, info.Size())
-	_, err = file.Seek(0, 0)
-	if err != nil {
-		return "", err
-	}
-
-	_, err = io.ReadFull(file, buf)
-	if err != nil {
-		return "", err
-	}
-
-	// Encode to base64
-	encoded := base64.StdEncoding.EncodeToString(buf)
-
-	// Create data URL
-	dataURL := fmt.Sprintf("data:%s;base64,%s", contentType, encoded)
-
-	return dataURL, nil
-}
-
 // processImagesInPrompt extracts images from the prompt, encodes them to data URLs,
 // and returns the cleaned prompt text and list of image data URLs
 func processImagesInPrompt(prompt string) (string, []string, error) {
@@ -139,7 +83,7 @@ func processImagesInPrompt(prompt string) (string, []string, error) {
 
 	for _, filePath := range imagePaths {
 		nfp := normalizeFilePath(filePath)
-		dataURL, err := encodeImageToDataURL(nfp)
+		dataURL, err := desktop.EncodeImageFile(nfp)
 		if errors.Is(err, os.ErrNotExist) {
 			// Skip non-existent files (might be false positive from regex)
 			continue
@@ -0,0 +1,80 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/docker/go-units"
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	"github.com/docker/model-runner/pkg/inference/platform"
+	"github.com/spf13/cobra"
+)
+
+func newInfoCmd() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "info [OPTIONS]",
+		Short: "Show the GPUs and inference engines supported by Docker Model Runner",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			info, err := desktopClient.PlatformInfo()
+			if err != nil {
+				return handleClientError(err, "Failed to get platform info")
+			}
+			output, err := formatPlatformInfo(info, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatPlatformInfo renders the platform info as JSON or as a GPU table
+// followed by a table of inference engines and whether each is supported.
+func formatPlatformInfo(info platform.Info, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		return formatter.ToStandardJSON(info)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "Platform: %s/%s\n\n", info.OS, info.Arch)
+
+	if len(info.GPUs) == 0 {
+		buf.WriteString("No GPUs detected\n")
+	} else {
+		table := newTable(&buf)
+		table.Header([]string{"GPU VENDOR", "NAME", "VRAM"})
+		for _, gpu := range info.GPUs {
+			name, vram := gpu.Name, "-"
+			if name == "" {
+				name = "-"
+			}
+			if gpu.VRAM > 0 {
+				vram = units.CustomSize("%.2f%s", float64(gpu.VRAM), 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"})
+			}
+			table.Append([]string{gpu.Vendor, name, vram})
+		}
+		table.Render()
+	}
+	buf.WriteString("\n")
+
+	table := newTable(&buf)
+	table.Header([]string{"ENGINE", "SUPPORTED"})
+	for _, backend := range info.Backends {
+		supported := "no"
+		if backend.Supported {
+			supported = "yes"
+		}
+		table.Append([]string{backend.Name, supported})
+	}
+	table.Render()
+	return buf.String(), nil
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/inference/platform"
+)
+
+func TestFormatPlatformInfo(t *testing.T) {
+	info := platform.Info{
+		OS:   "linux",
+		Arch: "amd64",
+		GPUs: []platform.GPU{
+			{Vendor: "NVIDIA", Name: "NVIDIA GeForce RTX 4090", VRAM: 24_000_000_000},
+			{Vendor: "Intel"},
+		},
+		Backends: []platform.BackendSupport{
+			{Name: "llama.cpp", Supported: true},
+			{Name: "mlx", Supported: false},
+		},
+	}
+
+	output, err := formatPlatformInfo(info, false)
+	if err != nil {
+		t.Fatalf("formatPlatformInfo() error = %v", err)
+	}
+	for _, want := range []string{"Platform: linux/amd64", "NVIDIA GeForce RTX 4090", "24.00GB", "llama.cpp", "yes", "mlx", "no"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+		}
+	}
+
+	output, err = formatPlatformInfo(platform.Info{OS: "darwin", Arch: "amd64"}, false)
+	if err != nil {
+		t.Fatalf("formatPlatformInfo() error = %v", err)
+	}
+	if !strings.Contains(output, "No GPUs detected") {
+		t.Errorf("Expected output to report no GPUs, got:\n%s", output)
+	}
+
+	output, err = formatPlatformInfo(info, true)
+	if err != nil {
+		t.Fatalf("formatPlatformInfo() error = %v", err)
+	}
+	if !strings.Contains(output, `"vram": 24000000000`) || !strings.Contains(output, `"supported": true`) {
+		t.Errorf("Expected JSON output with GPU and backend details, got:\n%s", output)
+	}
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/docker/model-runner/cmd/cli/commands/completion"
@@ -12,6 +13,8 @@ import (
 func newInspectCmd() *cobra.Command {
 	var openai bool
 	var remote bool
+	var verbose bool
+	var manifest bool
 	c := &cobra.Command{
 		Use:   "inspect MODEL",
 		Short: "Display detailed information on one model",
@@ -20,7 +23,25 @@ func newInspectCmd() *cobra.Command {
 			if openai && remote {
 				return fmt.Errorf("--remote flag cannot be used with --openai flag")
 			}
-			inspectedModel, err := inspectModel(args, openai, remote, desktopClient)
+			if openai && verbose {
+				return fmt.Errorf("--verbose flag cannot be used with --openai flag")
+			}
+			if manifest && (openai || remote || verbose) {
+				return fmt.Errorf("--manifest flag cannot be used with --openai, --remote or --verbose flags")
+			}
+			model, err := resolveModelAlias(args[0])
+			if err != nil {
+				return err
+			}
+			if manifest {
+				rawManifest, err := inspectManifest(model, desktopClient)
+				if err != nil {
+					return err
+				}
+				cmd.Print(rawManifest)
+				return nil
+			}
+			inspectedModel, err := inspectModel([]string{model}, openai, remote, verbose, desktopClient)
 			if err != nil {
 				return err
 			}
@@ -31,10 +52,12 @@ func newInspectCmd() *cobra.Command {
 	}
 	c.Flags().BoolVar(&openai, "openai", false, "List model in an OpenAI format")
 	c.Flags().BoolVarP(&remote, "remote", "r", false, "Show info for remote models")
+	c.Flags().BoolVarP(&verbose, "verbose", "v", false, "Include the full format metadata (e.g. GGUF key/value pairs) and creation time")
+	c.Flags().BoolVar(&manifest, "manifest", false, "Show the raw manifest of the local model")
 	return c
 }
 
-func inspectModel(args []string, openai bool, remote bool, desktopClient *desktop.Client) (string, error) {
+func inspectModel(args []string, openai bool, remote bool, verbose bool, desktopClient *desktop.Client) (string, error) {
 	modelName := args[0]
 	if openai {
 		model, err := desktopClient.InspectOpenAI(modelName)
@@ -43,9 +66,26 @@ func inspectModel(args []string, openai bool, remote bool, desktopClient *deskto
 		}
 		return formatter.ToStandardJSON(model)
 	}
-	model, err := desktopClient.Inspect(modelName, remote)
+	inspect := desktopClient.Inspect
+	if verbose {
+		inspect = desktopClient.InspectVerbose
+	}
+	model, err := inspect(modelName, remote)
 	if err != nil {
 		return "", handleClientError(err, "Failed to get model "+modelName)
 	}
 	return formatter.ToStandardJSON(model)
 }
+
+// inspectManifest returns the stored manifest of a local model, indented for
+// display.
+func inspectManifest(modelName string, desktopClient *desktop.Client) (string, error) {
+	raw, err := desktopClient.Manifest(modelName)
+	if err != nil {
+		return "", handleClientError(err, "Failed to get manifest of model "+modelName)
+	}
+	if !json.Valid(raw) {
+		return "", fmt.Errorf("model %s has an invalid manifest", modelName)
+	}
+	return formatter.ToStandardJSON(json.RawMessage(raw))
+}
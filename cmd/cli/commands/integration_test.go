@@ -311,7 +311,7 @@ func createAndPushTestModel(t *testing.T, registryURL, modelRef string, contextS
 func TestIntegration_PullModel(t *testing.T) {
 	env := setupTestEnv(t)
 
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 
 	if len(models) != 0 {
@@ -409,7 +409,7 @@ func TestIntegration_PullModel(t *testing.T) {
 			require.NoError(t, err, "Failed to pull model with reference: %s", tc.ref)
 
 			// List models and verify the expected model is present
-			models, err := listModels(false, env.client, true, false, "")
+			models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 			require.NoError(t, err)
 
 			if len(models) == 0 {
@@ -446,7 +446,7 @@ func TestIntegration_InspectModel(t *testing.T) {
 	env := setupTestEnv(t)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -464,7 +464,7 @@ func TestIntegration_InspectModel(t *testing.T) {
 	require.NoError(t, err, "Failed to pull model")
 
 	// Verify the model was pulled
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	truncatedID := modelID[7:19]
 	require.Equal(t, truncatedID, strings.TrimSpace(models), "Model not found after pull")
@@ -494,7 +494,7 @@ func TestIntegration_InspectModel(t *testing.T) {
 	require.NoError(t, err, "Failed to remove model")
 
 	// Verify model was removed
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.Empty(t, strings.TrimSpace(models), "Model should be removed")
 }
@@ -505,7 +505,7 @@ func TestIntegration_TagModel(t *testing.T) {
 	env := setupTestEnv(t)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -523,7 +523,7 @@ func TestIntegration_TagModel(t *testing.T) {
 	require.NoError(t, err, "Failed to pull model")
 
 	// Verify the model was pulled
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	truncatedID := modelID[7:19]
 	require.Equal(t, truncatedID, strings.TrimSpace(models), "Model not found after pull")
@@ -567,13 +567,6 @@ func TestIntegration_TagModel(t *testing.T) {
 
 	// Test all combinations of source references and target formats
 	for _, srcCase := range sourceRefs {
-
-		if strings.Contains(srcCase.name, "model ID") {
-			// Skip ID-based references for tagging tests
-			// TODO : Support tagging by ID in the future
-			continue
-		}
-
 		// Nested loop - test this source with ALL targets
 		for _, targetFormat := range targetFormats {
 			testCases = append(testCases, tagTestCase{
@@ -592,7 +585,7 @@ func TestIntegration_TagModel(t *testing.T) {
 			t.Logf("Tagging %s as %s", tc.sourceRef, tc.targetRef)
 
 			// Perform the tag operation
-			err := tagModel(newTagCmd(), env.client, tc.sourceRef, tc.targetRef)
+			err := tagModel(newTagCmd(), env.client, tc.sourceRef, tc.targetRef, false, false)
 			require.NoError(t, err, "Failed to tag model with source=%s target=%s", tc.sourceRef, tc.targetRef)
 
 			// Track this tag
@@ -622,7 +615,7 @@ func TestIntegration_TagModel(t *testing.T) {
 
 	// Test error case: tagging non-existent model
 	t.Run("error on non-existent model", func(t *testing.T) {
-		err := tagModel(newTagCmd(), env.client, "non-existent-model:v1", "ai/should-fail:latest")
+		err := tagModel(newTagCmd(), env.client, "non-existent-model:v1", "ai/should-fail:latest", false, false)
 		require.Error(t, err, "Should fail when tagging non-existent model")
 		t.Logf("✓ Correctly failed to tag non-existent model: %v", err)
 	})
@@ -633,7 +626,7 @@ func TestIntegration_TagModel(t *testing.T) {
 	require.NoError(t, err, "Failed to remove model")
 
 	// Verify model was removed
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.Empty(t, strings.TrimSpace(models), "Model should be removed")
 }
@@ -657,7 +650,7 @@ func TestIntegration_PushModel(t *testing.T) {
 	t.Logf("Custom registry available at: %s", customRegistryURL)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -675,7 +668,7 @@ func TestIntegration_PushModel(t *testing.T) {
 	require.NoError(t, err, "Failed to pull model")
 
 	// Verify the model was pulled
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	truncatedID := modelID[7:19]
 	require.Equal(t, truncatedID, strings.TrimSpace(models), "Model not found after pull")
@@ -703,7 +696,7 @@ func TestIntegration_PushModel(t *testing.T) {
 			t.Run(tc.name, func(t *testing.T) {
 				// First tag the model with the desired reference
 				t.Logf("Tagging %s as %s", "tag-test", tc.ref)
-				err := tagModel(newTagCmd(), env.client, "tag-test", tc.ref)
+				err := tagModel(newTagCmd(), env.client, "tag-test", tc.ref, false, false)
 				require.NoError(t, err, "Failed to tag model for custom registry")
 
 				// Push the tagged model
@@ -745,7 +738,7 @@ func TestIntegration_PushModel(t *testing.T) {
 			t.Run(tc.name, func(t *testing.T) {
 				// First tag the model with the custom registry reference
 				t.Logf("Tagging %s as %s", tc.sourceRef, tc.targetRef)
-				err := tagModel(newTagCmd(), env.client, tc.sourceRef, tc.targetRef)
+				err := tagModel(newTagCmd(), env.client, tc.sourceRef, tc.targetRef, false, false)
 				require.NoError(t, err, "Failed to tag model for custom registry")
 
 				// Push the tagged model
@@ -780,7 +773,7 @@ func TestIntegration_PushModel(t *testing.T) {
 	require.NoError(t, err, "Failed to remove model")
 
 	// Verify model was removed
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.Empty(t, strings.TrimSpace(models), "Model should be removed")
 }
@@ -791,7 +784,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 	env := setupTestEnv(t)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -826,7 +819,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 				require.NoError(t, err, "Failed to pull model")
 
 				// Verify model exists
-				models, err := listModels(false, env.client, true, false, "")
+				models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 				require.NoError(t, err)
 				truncatedID := modelID[7:19]
 				require.Equal(t, truncatedID, strings.TrimSpace(models), "Model not found after pull")
@@ -837,7 +830,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 				require.NoError(t, err, "Failed to remove model with reference: %s", tc.ref)
 
 				// Verify model is removed
-				models, err = listModels(false, env.client, true, false, "")
+				models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 				require.NoError(t, err)
 				require.Empty(t, strings.TrimSpace(models), "Model should be removed after rm with reference: %s", tc.ref)
 
@@ -864,7 +857,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 		require.NoError(t, err, "Failed to pull second model")
 
 		// Verify both models exist
-		models, err := listModels(false, env.client, false, false, "")
+		models, err := listModels(false, env.client, false, false, "", sortByName, false, nil, "")
 		require.NoError(t, err)
 		require.Contains(t, models, modelID1[7:19], "First model should exist")
 		require.Contains(t, models, modelID2[7:19], "Second model should exist")
@@ -875,7 +868,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 		require.NoError(t, err, "Failed to remove multiple models")
 
 		// Verify both models are removed
-		models, err = listModels(false, env.client, true, false, "")
+		models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 		require.NoError(t, err)
 		require.Empty(t, strings.TrimSpace(models), "All models should be removed")
 
@@ -891,11 +884,11 @@ func TestIntegration_RemoveModel(t *testing.T) {
 
 		// Add multiple tags to the same model
 		t.Logf("Adding tags v1, v2, and v3 to the model")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v1")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v1", false, false)
 		require.NoError(t, err, "Failed to create v1 tag")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v2")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v2", false, false)
 		require.NoError(t, err, "Failed to create v2 tag")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v3")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:v3", false, false)
 		require.NoError(t, err, "Failed to create v3 tag")
 
 		// Verify all tags exist
@@ -953,11 +946,11 @@ func TestIntegration_RemoveModel(t *testing.T) {
 
 		// Add multiple tags
 		t.Logf("Adding multiple tags to the model")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag1")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag1", false, false)
 		require.NoError(t, err, "Failed to create tag1")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag2")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag2", false, false)
 		require.NoError(t, err, "Failed to create tag2")
-		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag3")
+		err = tagModel(newTagCmd(), env.client, "rm-test", "rm-test:tag3", false, false)
 		require.NoError(t, err, "Failed to create tag3")
 
 		// Verify tags exist
@@ -988,7 +981,7 @@ func TestIntegration_RemoveModel(t *testing.T) {
 		require.NoError(t, err, "Failed to remove with force flag")
 
 		// Verify model is removed
-		models, err := listModels(false, env.client, true, false, "")
+		models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 		require.NoError(t, err)
 		require.Empty(t, strings.TrimSpace(models), "Model should be removed with force flag")
 
@@ -1030,7 +1023,7 @@ func TestIntegration_PackageModel(t *testing.T) {
 	env := setupTestEnv(t)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -1062,7 +1055,7 @@ func TestIntegration_PackageModel(t *testing.T) {
 
 		// Verify the model was loaded and tagged
 		t.Logf("Verifying model was loaded and tagged")
-		models, err := listModels(false, env.client, false, false, "")
+		models, err := listModels(false, env.client, false, false, "", sortByName, false, nil, "")
 		require.NoError(t, err)
 		require.NotEmpty(t, models, "No models found after packaging")
 
@@ -1143,7 +1136,7 @@ func TestIntegration_PackageModel(t *testing.T) {
 	})
 
 	// Verify all models are cleaned up
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.Empty(t, strings.TrimSpace(models), "All models should be removed after cleanup")
 }
@@ -1195,7 +1188,7 @@ func TestIntegration_PullFromDockerHub(t *testing.T) {
 	env := setupDockerHubTestEnv(t)
 
 	// Ensure no models exist initially
-	models, err := listModels(false, env.client, true, false, "")
+	models, err := listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	if len(models) != 0 {
 		t.Fatal("Expected no initial models, but found some")
@@ -1211,7 +1204,7 @@ func TestIntegration_PullFromDockerHub(t *testing.T) {
 
 	// Verify the model was pulled
 	t.Log("Verifying model was pulled successfully")
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.NotEmpty(t, strings.TrimSpace(models), "Model should exist after pull from Docker Hub")
 
@@ -1228,7 +1221,7 @@ func TestIntegration_PullFromDockerHub(t *testing.T) {
 	require.NoError(t, err, "Failed to remove model")
 
 	// Verify model was removed
-	models, err = listModels(false, env.client, true, false, "")
+	models, err = listModels(false, env.client, true, false, "", sortByName, false, nil, "")
 	require.NoError(t, err)
 	require.Empty(t, strings.TrimSpace(models), "Model should be removed after cleanup")
 }
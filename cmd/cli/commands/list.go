@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -21,8 +22,8 @@ import (
 )
 
 func newListCmd() *cobra.Command {
-	var jsonFormat, openai, quiet bool
-	var openaiURL string
+	var jsonFormat, openai, quiet, runnable, compact bool
+	var openaiURL, sortBy, columnList, groupBy string
 	c := &cobra.Command{
 		Use:     "list [OPTIONS] [MODEL]",
 		Aliases: []string{"ls"},
@@ -32,6 +33,25 @@ func newListCmd() *cobra.Command {
 			if openai && quiet {
 				return fmt.Errorf("--quiet flag cannot be used with --openai flag or OpenAI backend")
 			}
+			if runnable && (openai || openaiURL != "") {
+				return fmt.Errorf("--runnable flag cannot be used with --openai or --openaiurl flags")
+			}
+			if sortBy != sortByName && sortBy != sortByLastUsed {
+				return fmt.Errorf("invalid --sort value %q: must be %q or %q", sortBy, sortByName, sortByLastUsed)
+			}
+			if compact && columnList != "" {
+				return fmt.Errorf("--compact flag cannot be used with --columns flag")
+			}
+			if groupBy != "" && groupBy != groupByFamily {
+				return fmt.Errorf("invalid --group-by value %q: must be %q", groupBy, groupByFamily)
+			}
+			if groupBy != "" && (jsonFormat || quiet || openai || openaiURL != "") {
+				return fmt.Errorf("--group-by flag cannot be used with --json, --quiet, --openai or --openaiurl flags")
+			}
+			columns, err := parseListColumns(columnList, compact)
+			if err != nil {
+				return err
+			}
 
 			// Handle --openaiurl flag for external OpenAI endpoints
 			if openaiURL != "" {
@@ -86,7 +106,7 @@ func newListCmd() *cobra.Command {
 			if len(args) > 0 {
 				modelFilter = args[0]
 			}
-			models, err := listModels(openai, desktopClient, quiet, jsonFormat, modelFilter)
+			models, err := listModels(openai, desktopClient, quiet, jsonFormat, modelFilter, sortBy, runnable, columns, groupBy)
 			if err != nil {
 				return err
 			}
@@ -99,9 +119,79 @@ func newListCmd() *cobra.Command {
 	c.Flags().BoolVar(&openai, "openai", false, "List models in an OpenAI format")
 	c.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only show model IDs")
 	c.Flags().StringVar(&openaiURL, "openaiurl", "", "OpenAI-compatible API endpoint URL to list models from")
+	c.Flags().StringVar(&sortBy, "sort", sortByName, "Sort models by \"name\" or \"last-used\"")
+	c.Flags().BoolVar(&runnable, "runnable", false, "Only show models that an installed inference engine can run")
+	c.Flags().StringVar(&columnList, "columns", "", "Comma-separated list of columns to show ("+strings.Join(listColumnNames(), ", ")+")")
+	c.Flags().BoolVar(&compact, "compact", false, "Hide the quantization and architecture columns")
+	c.Flags().StringVar(&groupBy, "group-by", "", "Group variants of a model under a row for their family (\"family\")")
 	return c
 }
 
+const (
+	// sortByName sorts listed models by their display name.
+	sortByName = "name"
+	// sortByLastUsed sorts listed models by when they were last used, most
+	// recent first.
+	sortByLastUsed = "last-used"
+)
+
+// groupByFamily groups listed models that share a name, without the tag,
+// under a row for their family.
+const groupByFamily = "family"
+
+// listColumns are the columns of the model table in their default order,
+// named as they are selected with --columns.
+var listColumns = []struct{ name, header string }{
+	{"name", "MODEL NAME"},
+	{"parameters", "PARAMETERS"},
+	{"quantization", "QUANTIZATION"},
+	{"architecture", "ARCHITECTURE"},
+	{"id", "MODEL ID"},
+	{"created", "CREATED"},
+	{"last-used", "LAST USED"},
+	{"context", "CONTEXT"},
+	{"size", "SIZE"},
+}
+
+// compactHiddenColumns are the columns --compact leaves out.
+var compactHiddenColumns = []string{"quantization", "architecture"}
+
+// listColumnNames returns the names of every column of the model table.
+func listColumnNames() []string {
+	names := make([]string, 0, len(listColumns))
+	for _, column := range listColumns {
+		names = append(names, column.name)
+	}
+	return names
+}
+
+// parseListColumns returns the names of the columns to show for a
+// comma-separated --columns value, or nil for every column. With compact, the
+// default columns are shown except those in compactHiddenColumns.
+func parseListColumns(columnList string, compact bool) ([]string, error) {
+	valid := listColumnNames()
+	if compact {
+		return slices.DeleteFunc(valid, func(name string) bool {
+			return slices.Contains(compactHiddenColumns, name)
+		}), nil
+	}
+	if columnList == "" {
+		return nil, nil
+	}
+	var columns []string
+	for _, name := range strings.Split(columnList, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(valid, name) {
+			return nil, fmt.Errorf("invalid --columns value %q: must be one of %s", name, strings.Join(valid, ", "))
+		}
+		if slices.Contains(columns, name) {
+			return nil, fmt.Errorf("invalid --columns value: %q is listed more than once", name)
+		}
+		columns = append(columns, name)
+	}
+	return columns, nil
+}
+
 func normalizeModelFilter(filter string) string {
 	if !strings.Contains(filter, "/") {
 		return "ai/" + filter
@@ -117,7 +207,7 @@ func matchesModelFilter(tag, filter string) bool {
 	return repository == filter
 }
 
-func listModels(openai bool, desktopClient *desktop.Client, quiet bool, jsonFormat bool, modelFilter string) (string, error) {
+func listModels(openai bool, desktopClient *desktop.Client, quiet bool, jsonFormat bool, modelFilter string, sortBy string, runnable bool, columns []string, groupBy string) (string, error) {
 	if openai {
 		models, err := desktopClient.ListOpenAI()
 		if err != nil {
@@ -136,7 +226,7 @@ func listModels(openai bool, desktopClient *desktop.Client, quiet bool, jsonForm
 		return formatter.ToStandardJSON(models)
 	}
 
-	models, err := desktopClient.List()
+	models, err := desktopClient.ListWithOptions(desktop.ListOptions{Runnable: runnable})
 	if err != nil {
 		return "", handleClientError(err, "Failed to list models")
 	}
@@ -159,6 +249,11 @@ func listModels(openai bool, desktopClient *desktop.Client, quiet bool, jsonForm
 		}
 		models = filteredModels
 	}
+	if sortBy == sortByLastUsed {
+		sort.SliceStable(models, func(i, j int) bool {
+			return models[i].LastUsed > models[j].LastUsed
+		})
+	}
 	if jsonFormat {
 		return formatter.ToStandardJSON(models)
 	}
@@ -173,21 +268,34 @@ func listModels(openai bool, desktopClient *desktop.Client, quiet bool, jsonForm
 		}
 		return modelIDs, nil
 	}
-	return prettyPrintModels(models), nil
+	return prettyPrintModels(models, sortBy, columns, groupBy), nil
 }
 
-func prettyPrintModels(models []dmrm.Model) string {
-	type displayRow struct {
-		displayName string
-		tag         string
-		model       dmrm.Model
+// modelRow is a row of the model table: a model listed under one of its tags.
+type modelRow struct {
+	displayName string
+	tag         string
+	model       dmrm.Model
+}
+
+// splitDisplayName splits a display name into its base name and variant, on
+// the last ':'.
+func splitDisplayName(displayName string) (base, variant string) {
+	if idx := strings.LastIndex(displayName, ":"); idx != -1 {
+		return displayName[:idx], displayName[idx+1:]
 	}
+	return displayName, ""
+}
 
-	var rows []displayRow
+// prettyPrintModels renders models as a table with the given columns, or
+// every column if columns is empty. With groupBy set to groupByFamily, rows
+// sharing a base name are grouped under a row for their family.
+func prettyPrintModels(models []dmrm.Model, sortBy string, columns []string, groupBy string) string {
+	var rows []modelRow
 
 	for _, m := range models {
 		if len(m.Tags) == 0 {
-			rows = append(rows, displayRow{
+			rows = append(rows, modelRow{
 				displayName: "<none>",
 				tag:         "<none>",
 				model:       m,
@@ -197,7 +305,7 @@ func prettyPrintModels(models []dmrm.Model) string {
 
 		for _, tag := range m.Tags {
 			displayName := stripDefaultsFromModelName(tag)
-			rows = append(rows, displayRow{
+			rows = append(rows, modelRow{
 				displayName: displayName,
 				tag:         tag,
 				model:       m,
@@ -205,14 +313,6 @@ func prettyPrintModels(models []dmrm.Model) string {
 		}
 	}
 
-	// Helper function to split display name into base and variant
-	splitDisplayName := func(displayName string) (base, variant string) {
-		if idx := strings.LastIndex(displayName, ":"); idx != -1 {
-			return displayName[:idx], displayName[idx+1:]
-		}
-		return displayName, ""
-	}
-
 	// Sort all rows by display name
 	sort.Slice(rows, func(i, j int) bool {
 		displayI := rows[i].displayName
@@ -238,23 +338,111 @@ func prettyPrintModels(models []dmrm.Model) string {
 		}
 		return strings.ToLower(variantI) < strings.ToLower(variantJ)
 	})
+	if sortBy == sortByLastUsed {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].model.LastUsed > rows[j].model.LastUsed
+		})
+	}
+
+	if len(columns) == 0 {
+		columns = listColumnNames()
+	}
+	header := make([]string, 0, len(columns))
+	for _, name := range columns {
+		for _, column := range listColumns {
+			if column.name == name {
+				header = append(header, column.header)
+			}
+		}
+	}
 
 	var buf bytes.Buffer
 	table := newTable(&buf)
-	table.Header([]string{"MODEL NAME", "PARAMETERS", "QUANTIZATION", "ARCHITECTURE", "MODEL ID", "CREATED", "CONTEXT", "SIZE"})
+	table.Header(header)
 
-	for _, row := range rows {
-		appendRow(table, row.tag, row.model)
+	if groupBy == groupByFamily {
+		// Families are listed in the order of their first row, so by name,
+		// or by their most recently used variant.
+		var families []string
+		members := make(map[string][]modelRow)
+		for _, row := range rows {
+			base, _ := splitDisplayName(row.displayName)
+			family := strings.ToLower(base)
+			if _, ok := members[family]; !ok {
+				families = append(families, family)
+			}
+			members[family] = append(members[family], row)
+		}
+		for _, family := range families {
+			appendFamily(table, members[family], columns)
+		}
+	} else {
+		for _, row := range rows {
+			appendRow(table, row.tag, row.model, columns)
+		}
 	}
 
 	table.Render()
 	return buf.String()
 }
 
-func appendRow(table *tablewriter.Table, tag string, model dmrm.Model) {
+// appendFamily appends the rows of a model family. A family of one row is
+// appended as is. Otherwise a row naming the family holds the values its
+// variants share, and the indented row of each variant only the others.
+func appendFamily(table *tablewriter.Table, rows []modelRow, columns []string) {
+	var variants []string
+	var values []map[string]string
+	for _, row := range rows {
+		v, ok := rowValues(row.tag, row.model)
+		if !ok {
+			continue
+		}
+		base, variant := splitDisplayName(row.displayName)
+		if variant == "" && base != "<none>" {
+			variant = "latest"
+		}
+		variants = append(variants, variant)
+		values = append(values, v)
+	}
+	switch len(values) {
+	case 0:
+		return
+	case 1:
+		table.Append(selectColumns(values[0], columns))
+		return
+	}
+
+	family, _ := splitDisplayName(rows[0].displayName)
+	shared := map[string]string{"name": family}
+	for _, name := range columns {
+		if name == "name" || slices.ContainsFunc(values[1:], func(v map[string]string) bool { return v[name] != values[0][name] }) {
+			continue
+		}
+		shared[name] = values[0][name]
+		for _, v := range values {
+			delete(v, name)
+		}
+	}
+	table.Append(selectColumns(shared, columns))
+	for i, v := range values {
+		v["name"] = "  " + variants[i]
+		table.Append(selectColumns(v, columns))
+	}
+}
+
+func appendRow(table *tablewriter.Table, tag string, model dmrm.Model, columns []string) {
+	if values, ok := rowValues(tag, model); ok {
+		table.Append(selectColumns(values, columns))
+	}
+}
+
+// rowValues returns the values of the table row for model listed under tag,
+// by column name. It reports false, after printing why, if model cannot be
+// listed.
+func rowValues(tag string, model dmrm.Model) (map[string]string, bool) {
 	if len(model.ID) < 19 {
 		fmt.Fprintf(os.Stderr, "invalid model ID for model: %v\n", model)
-		return
+		return nil, false
 	}
 	// Strip default "ai/" prefix and ":latest" tag for display
 	displayTag := stripDefaultsFromModelName(tag)
@@ -271,16 +459,31 @@ func appendRow(table *tablewriter.Table, tag string, model dmrm.Model) {
 		}
 	}
 
-	table.Append([]string{
-		displayTag,
-		model.Config.GetParameters(),
-		model.Config.GetQuantization(),
-		model.Config.GetArchitecture(),
-		model.ID[7:19],
-		units.HumanDuration(time.Since(time.Unix(model.Created, 0))) + " ago",
-		contextSize,
-		model.Config.GetSize(),
-	})
+	lastUsed := "-"
+	if model.LastUsed != 0 {
+		lastUsed = units.HumanDuration(time.Since(time.Unix(model.LastUsed, 0))) + " ago"
+	}
+
+	return map[string]string{
+		"name":         displayTag,
+		"parameters":   model.Config.GetParameters(),
+		"quantization": model.Config.GetQuantization(),
+		"architecture": model.Config.GetArchitecture(),
+		"id":           model.ID[7:19],
+		"created":      units.HumanDuration(time.Since(time.Unix(model.Created, 0))) + " ago",
+		"last-used":    lastUsed,
+		"context":      contextSize,
+		"size":         model.Config.GetSize(),
+	}, true
+}
+
+// selectColumns returns values in the order of columns.
+func selectColumns(values map[string]string, columns []string) []string {
+	row := make([]string, 0, len(columns))
+	for _, name := range columns {
+		row = append(row, values[name])
+	}
+	return row
 }
 
 // prettyPrintOpenAIModels formats OpenAI model list in table format with only MODEL NAME populated
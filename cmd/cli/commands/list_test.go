@@ -2,8 +2,10 @@ package commands
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/types"
 	dmrm "github.com/docker/model-runner/pkg/inference/models"
@@ -129,7 +131,7 @@ func TestListModelsSorting(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			// Call the actual prettyPrintModels function to test the real sorting logic
-			output := prettyPrintModels(tt.inputModels)
+			output := prettyPrintModels(tt.inputModels, sortByName, nil, "")
 
 			// Parse the output to extract model names in order
 			actualOrder := extractModelNamesFromOutput(output)
@@ -164,7 +166,7 @@ func TestListModelsSorting(t *testing.T) {
 
 func TestListModelsEmptyList(t *testing.T) {
 	models := []dmrm.Model{}
-	output := prettyPrintModels(models)
+	output := prettyPrintModels(models, sortByName, nil, "")
 	actualOrder := extractModelNamesFromOutput(output)
 	if len(actualOrder) != 0 {
 		t.Errorf("Expected empty list to remain empty, got %d models", len(actualOrder))
@@ -185,7 +187,7 @@ func TestListModelsSingleModel(t *testing.T) {
 			},
 		},
 	}
-	output := prettyPrintModels(models)
+	output := prettyPrintModels(models, sortByName, nil, "")
 	actualOrder := extractModelNamesFromOutput(output)
 	if len(actualOrder) != 1 || actualOrder[0] != "single" {
 		t.Errorf("Single model should remain unchanged, got %v", actualOrder)
@@ -254,7 +256,7 @@ func TestPrettyPrintModelsWithSortedInput(t *testing.T) {
 		},
 	}
 
-	output := prettyPrintModels(models)
+	output := prettyPrintModels(models, sortByName, nil, "")
 
 	// Verify output contains both models
 	if !strings.Contains(output, "apple") {
@@ -291,7 +293,7 @@ func TestPrettyPrintModelsWithMultipleTags(t *testing.T) {
 		},
 	}
 
-	output := prettyPrintModels(models)
+	output := prettyPrintModels(models, sortByName, nil, "")
 
 	// Find positions of each tag display
 	qwen3Pos := strings.Index(output, "qwen3  ") // Just "qwen3" (from :latest with stripped suffix)
@@ -316,3 +318,143 @@ func TestPrettyPrintModelsWithMultipleTags(t *testing.T) {
 		t.Error("'qwen3:0.6B-F16' should appear before 'qwen3:8B-Q4_K_M'")
 	}
 }
+
+func TestPrettyPrintModelsSortByLastUsed(t *testing.T) {
+	now := time.Now()
+	models := []dmrm.Model{
+		{
+			ID:       "sha256:111111111111111111111111111111111111111111111111111111111111aaaa",
+			Tags:     []string{"alpha:latest"},
+			Created:  1000,
+			LastUsed: now.Add(-2 * time.Hour).Unix(),
+			Config:   &types.Config{},
+		},
+		{
+			ID:      "sha256:222222222222222222222222222222222222222222222222222222222222bbbb",
+			Tags:    []string{"beta:latest"},
+			Created: 1000,
+			Config:  &types.Config{},
+		},
+		{
+			ID:       "sha256:333333333333333333333333333333333333333333333333333333333333cccc",
+			Tags:     []string{"gamma:latest"},
+			Created:  1000,
+			LastUsed: now.Add(-time.Minute).Unix(),
+			Config:   &types.Config{},
+		},
+	}
+
+	output := prettyPrintModels(models, sortByLastUsed, nil, "")
+	expected := []string{"gamma", "alpha", "beta"}
+	if actual := extractModelNamesFromOutput(output); !slices.Equal(actual, expected) {
+		t.Errorf("Expected order %v, got %v", expected, actual)
+	}
+	if !strings.Contains(output, "LAST USED") {
+		t.Error("Expected output to contain a LAST USED column")
+	}
+	if !strings.Contains(output, "2 hours ago") {
+		t.Errorf("Expected output to show when alpha was last used, got:\n%s", output)
+	}
+}
+
+func TestPrettyPrintModelsColumns(t *testing.T) {
+	models := []dmrm.Model{
+		testModel("sha256:123456789012345678901234567890123456789012345678901234567890abcd", []string{"alpha:latest"}, 1000),
+	}
+
+	tests := []struct {
+		name     string
+		columns  string
+		compact  bool
+		expected []string
+	}{
+		{
+			name:     "selected columns in order",
+			columns:  "size, name,id",
+			expected: []string{"SIZE", "MODEL NAME", "MODEL ID"},
+		},
+		{
+			name:     "compact",
+			compact:  true,
+			expected: []string{"MODEL NAME", "PARAMETERS", "MODEL ID", "CREATED", "LAST USED", "CONTEXT", "SIZE"},
+		},
+		{
+			name:     "default",
+			expected: []string{"MODEL NAME", "PARAMETERS", "QUANTIZATION", "ARCHITECTURE", "MODEL ID", "CREATED", "LAST USED", "CONTEXT", "SIZE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := parseListColumns(tt.columns, tt.compact)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			output := prettyPrintModels(models, sortByName, columns, "")
+			lines := strings.Split(output, "\n")
+			header := regexp.MustCompile(`\s{2,}`).Split(strings.TrimSpace(lines[0]), -1)
+			if !slices.Equal(header, tt.expected) {
+				t.Errorf("Expected header %v, got %v", tt.expected, header)
+			}
+			if showsArchitecture := strings.Contains(lines[1], "llama"); showsArchitecture != slices.Contains(tt.expected, "ARCHITECTURE") {
+				t.Errorf("Expected the row to match the header %v, got %q", tt.expected, lines[1])
+			}
+		})
+	}
+}
+
+func TestParseListColumnsInvalid(t *testing.T) {
+	for _, columns := range []string{"name,bogus", "name,name", "name,"} {
+		if _, err := parseListColumns(columns, false); err == nil {
+			t.Errorf("Expected an error for --columns %q", columns)
+		}
+	}
+}
+
+func TestPrettyPrintModelsGroupByFamily(t *testing.T) {
+	qwen3 := func(id, parameters, quantization, size string, tags ...string) dmrm.Model {
+		return dmrm.Model{
+			ID:      id,
+			Tags:    tags,
+			Created: time.Now().Add(-48 * time.Hour).Unix(),
+			Config: &types.Config{
+				Parameters:   parameters,
+				Quantization: quantization,
+				Architecture: "qwen3",
+				Size:         size,
+			},
+		}
+	}
+	models := []dmrm.Model{
+		qwen3("sha256:111111111111111111111111111111111111111111111111111111111111aaaa", "8B", "Q4_K_M", "4.68GB", "qwen3:latest", "qwen3:8B-Q4_K_M"),
+		qwen3("sha256:222222222222222222222222222222222222222222222222222222222222bbbb", "0.6B", "F16", "1.19GB", "qwen3:0.6B-F16"),
+		qwen3("sha256:333333333333333333333333333333333333333333333333333333333333cccc", "30B", "Q4_K_M", "17.28GB", "qwen3-coder:30B-A3B-Q4_K_M"),
+		qwen3("sha256:444444444444444444444444444444444444444444444444444444444444dddd", "30B", "Q8_0", "32.48GB", "qwen3-coder:30B-A3B-Q8_0"),
+		testModel("sha256:555555555555555555555555555555555555555555555555555555555555eeee", []string{"smollm2:latest"}, 1000),
+	}
+
+	output := prettyPrintModels(models, sortByName, []string{"name", "parameters", "quantization", "architecture", "id"}, groupByFamily)
+	var rows [][]string
+	for _, line := range strings.Split(strings.TrimRight(output, "\n"), "\n")[1:] {
+		// Keep the indentation of variant rows, which marks them as members
+		// of the family above.
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		fields := regexp.MustCompile(`\s{2,}`).Split(strings.TrimSpace(line), -1)
+		fields[0] = indent + fields[0]
+		rows = append(rows, fields)
+	}
+
+	expected := [][]string{
+		{"qwen3", "qwen3"},
+		{"  latest", "8B", "Q4_K_M", "111111111111"},
+		{"  0.6B-F16", "0.6B", "F16", "222222222222"},
+		{"  8B-Q4_K_M", "8B", "Q4_K_M", "111111111111"},
+		{"qwen3-coder", "30B", "qwen3"},
+		{"  30B-A3B-Q4_K_M", "Q4_K_M", "333333333333"},
+		{"  30B-A3B-Q8_0", "Q8_0", "444444444444"},
+		{"smollm2", "7B", "Q4_0", "llama", "555555555555"},
+	}
+	if !slices.EqualFunc(rows, expected, slices.Equal) {
+		t.Errorf("Expected rows %q, got %q\n%s", expected, rows, output)
+	}
+}
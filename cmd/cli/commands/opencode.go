@@ -65,23 +65,11 @@ func setupOpenCode(cmd *cobra.Command, runner *standaloneRunner, model string) e
 
 // ensureModelExists checks if the model exists locally, and pulls it if not.
 func ensureModelExists(cmd *cobra.Command, model string) error {
-	models, err := desktopClient.List()
+	// The daemon resolves partial names such as "smollm2" the same way
+	// inference does.
+	modelExists, err := desktopClient.IsModelInStore(model)
 	if err != nil {
-		return fmt.Errorf("failed to list models: %w", err)
-	}
-
-	// Check if model exists
-	modelExists := false
-	for _, m := range models {
-		for _, tag := range m.Tags {
-			if tag == model || strings.TrimPrefix(tag, "ai/") == model {
-				modelExists = true
-				break
-			}
-		}
-		if modelExists {
-			break
-		}
+		return fmt.Errorf("failed to check for model: %w", err)
 	}
 
 	if !modelExists {
@@ -0,0 +1,108 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	"github.com/docker/model-runner/pkg/distribution/distribution"
+	"github.com/spf13/cobra"
+)
+
+// outdatedModel is a local model tag that points to a different model in its
+// registry.
+type outdatedModel struct {
+	Model        string `json:"model"`
+	LocalDigest  string `json:"local_digest"`
+	RemoteDigest string `json:"remote_digest"`
+}
+
+func newOutdatedCmd() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "outdated [OPTIONS]",
+		Short: "List local models with a newer version in their registry",
+		Long:  "Compare each local model tag with the model the tag points to in its registry, and list the tags with an update available. Hugging Face models are not checked.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			models, err := desktopClient.List()
+			if err != nil {
+				return handleClientError(err, "Failed to list models")
+			}
+			var tags []string
+			for _, model := range models {
+				for _, tag := range model.Tags {
+					if !distribution.IsHuggingFaceReference(tag) {
+						tags = append(tags, tag)
+					}
+				}
+			}
+			slices.Sort(tags)
+
+			var outdated []outdatedModel
+			for _, tag := range slices.Compact(tags) {
+				check, err := desktopClient.CheckUpdate(tag)
+				if err != nil {
+					cmd.PrintErrf("Warning: failed to check %s for updates: %v\n", stripDefaultsFromModelName(tag), err)
+					continue
+				}
+				if check.UpdateAvailable {
+					outdated = append(outdated, outdatedModel{
+						Model:        stripDefaultsFromModelName(tag),
+						LocalDigest:  check.LocalDigest,
+						RemoteDigest: check.RemoteDigest,
+					})
+				}
+			}
+
+			output, err := formatOutdated(outdated, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatOutdated renders the outdated models as a JSON array or as a table of
+// short digests.
+func formatOutdated(outdated []outdatedModel, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		if outdated == nil {
+			outdated = []outdatedModel{}
+		}
+		return formatter.ToStandardJSON(outdated)
+	}
+	if len(outdated) == 0 {
+		return "All models are up to date\n", nil
+	}
+
+	var buf bytes.Buffer
+	table := newTable(&buf)
+	table.Header([]string{"MODEL", "LOCAL ID", "REMOTE ID"})
+	for _, m := range outdated {
+		table.Append([]string{m.Model, shortDigest(m.LocalDigest), shortDigest(m.RemoteDigest)})
+	}
+	table.Render()
+	return buf.String(), nil
+}
+
+// shortDigest abbreviates a digest to the 12 hex characters model IDs are
+// listed with.
+func shortDigest(digest string) string {
+	_, hex, ok := strings.Cut(digest, ":")
+	if !ok {
+		hex = digest
+	}
+	return hex[:min(len(hex), 12)]
+}
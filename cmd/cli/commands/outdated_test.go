@@ -0,0 +1,57 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFormatOutdated(t *testing.T) {
+	outdated := []outdatedModel{{
+		Model:        "smollm2",
+		LocalDigest:  "sha256:aaaaaaaaaaaa1111111111111111111111111111111111111111111111111111",
+		RemoteDigest: "sha256:bbbbbbbbbbbb2222222222222222222222222222222222222222222222222222",
+	}}
+
+	t.Run("table", func(t *testing.T) {
+		output, err := formatOutdated(outdated, false)
+		if err != nil {
+			t.Fatalf("Failed to format outdated models: %v", err)
+		}
+		for _, want := range []string{"MODEL", "LOCAL ID", "REMOTE ID", "smollm2", "aaaaaaaaaaaa", "bbbbbbbbbbbb"} {
+			if !strings.Contains(output, want) {
+				t.Errorf("Expected output to contain %q, got:\n%s", want, output)
+			}
+		}
+		if strings.Contains(output, "1111") {
+			t.Errorf("Expected digests to be abbreviated, got:\n%s", output)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		output, err := formatOutdated(outdated, true)
+		if err != nil {
+			t.Fatalf("Failed to format outdated models: %v", err)
+		}
+		var decoded []outdatedModel
+		if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+			t.Fatalf("Failed to decode output: %v", err)
+		}
+		if len(decoded) != 1 || decoded[0] != outdated[0] {
+			t.Errorf("Expected %v, got %v", outdated, decoded)
+		}
+	})
+
+	t.Run("up to date", func(t *testing.T) {
+		output, err := formatOutdated(nil, false)
+		if err != nil {
+			t.Fatalf("Failed to format outdated models: %v", err)
+		}
+		if output != "All models are up to date\n" {
+			t.Errorf("Unexpected output: %q", output)
+		}
+		if output, err := formatOutdated(nil, true); err != nil || strings.TrimSpace(output) != "[]" {
+			t.Errorf("Expected an empty JSON array, got %q, %v", output, err)
+		}
+	})
+}
@@ -4,11 +4,15 @@ import (
 	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/docker/model-runner/cmd/cli/commands/completion"
 	"github.com/docker/model-runner/cmd/cli/desktop"
@@ -34,11 +38,40 @@ func validateAbsolutePath(path, name string) (string, error) {
 	return filepath.Clean(path), nil
 }
 
+// validateDirectory validates that a path is an absolute path to an existing
+// directory and returns the cleaned path. name describes the directory in
+// error messages, as in "Safetensors directory does not exist".
+func validateDirectory(path, name string) (string, error) {
+	path, err := validateAbsolutePath(path, name+" directory")
+	if err != nil {
+		return "", err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf(
+				"%s directory does not exist: %s\n\n"+
+					"See 'docker model package --help' for more information",
+				name, path,
+			)
+		}
+		return "", fmt.Errorf("could not access %s directory %q: %w", strings.ToLower(name), path, err)
+	}
+	if !info.IsDir() {
+		return "", fmt.Errorf(
+			"%s path must be a directory: %s\n\n"+
+				"See 'docker model package --help' for more information",
+			name, path,
+		)
+	}
+	return path, nil
+}
+
 func newPackagedCmd() *cobra.Command {
 	var opts packageOptions
 
 	c := &cobra.Command{
-		Use:   "package (--gguf <path> | --safetensors-dir <path> | --dduf <path> | --from <model>) [--license <path>...] [--mmproj <path>] [--context-size <tokens>] [--push] MODEL",
+		Use:   "package (--gguf <path> | --safetensors-dir <path> | --dduf <path> | --from-dir <path> | --from <model>) [--license <path>...] [--mmproj <path>] [--context-size <tokens>] [--push] MODEL",
 		Short: "Package a model into a Docker Model OCI artifact",
 		Long: `Package a model into a Docker Model OCI artifact.
 
@@ -46,6 +79,7 @@ The model source must be one of:
   --gguf               A GGUF file (single file or first shard of a sharded model)
   --safetensors-dir    A directory containing .safetensors and configuration files
   --dduf               A .dduf (Diffusers Unified Format) archive
+  --from-dir           A directory of model files in any supported format
   --from               An existing packaged model reference
 
 By default, the packaged artifact is loaded into the local Model Runner content store.
@@ -72,23 +106,35 @@ Packaging behavior:
   DDUF
     --dduf must point to a .dduf archive file.
 
+  Directories
+    --from-dir must point to a directory containing the weight files of a
+    model (safetensors, GGUF or DDUF) and any configuration files. The
+    format is detected from the weight files. --safetensors-dir is the same
+    source, and every file under the directory is packaged as a separate OCI
+    layer.
+
   Repackaging
     --from repackages an existing model. You may override selected properties
     such as --context-size to create a variant of the original model.
 
   Multimodal models
-    Use --mmproj to include a multimodal projector file.`,
+    Use --mmproj to include a multimodal projector file.
+
+  Provenance
+    Use --annotation to record provenance such as the model's source URL
+    (org.opencontainers.image.source) or license (org.opencontainers.image.licenses)
+    in the artifact manifest. The packaging tool is always recorded.`,
 		Args: func(cmd *cobra.Command, args []string) error {
 			if err := requireExactArgs(1, "package", "MODEL")(cmd, args); err != nil {
 				return err
 			}
 
-			// Validate that exactly one of --gguf, --safetensors-dir, --dduf, or --from is provided (mutually exclusive)
+			// Validate that exactly one of --gguf, --safetensors-dir, --dduf, --from-dir, or --from is provided (mutually exclusive)
 			sourcesProvided := 0
 			if opts.ggufPath != "" {
 				sourcesProvided++
 			}
-			if opts.safetensorsDir != "" {
+			if opts.modelDir != "" {
 				sourcesProvided++
 			}
 			if opts.ddufPath != "" {
@@ -100,13 +146,13 @@ Packaging behavior:
 
 			if sourcesProvided == 0 {
 				return fmt.Errorf(
-					"One of --gguf, --safetensors-dir, --dduf, or --from is required.\n\n" +
+					"One of --gguf, --safetensors-dir, --dduf, --from-dir, or --from is required.\n\n" +
 						"See 'docker model package --help' for more information",
 				)
 			}
 			if sourcesProvided > 1 {
 				return fmt.Errorf(
-					"Cannot specify more than one of --gguf, --safetensors-dir, --dduf, or --from. Please use only one source.\n\n" +
+					"Cannot specify more than one of --gguf, --safetensors-dir, --dduf, --from-dir, or --from. Please use only one source.\n\n" +
 						"See 'docker model package --help' for more information",
 				)
 			}
@@ -120,34 +166,12 @@ Packaging behavior:
 				}
 			}
 
-			// Validate safetensors directory if provided
-			if opts.safetensorsDir != "" {
-				if !filepath.IsAbs(opts.safetensorsDir) {
-					return fmt.Errorf(
-						"Safetensors directory path must be absolute.\n\n" +
-							"See 'docker model package --help' for more information",
-					)
-				}
-				opts.safetensorsDir = filepath.Clean(opts.safetensorsDir)
-
-				// Check if it's a directory
-				info, err := os.Stat(opts.safetensorsDir)
+			// Validate model directory if provided
+			if opts.modelDir != "" {
+				var err error
+				opts.modelDir, err = validateDirectory(opts.modelDir, "Model")
 				if err != nil {
-					if os.IsNotExist(err) {
-						return fmt.Errorf(
-							"Safetensors directory does not exist: %s\n\n"+
-								"See 'docker model package --help' for more information",
-							opts.safetensorsDir,
-						)
-					}
-					return fmt.Errorf("could not access safetensors directory %q: %w", opts.safetensorsDir, err)
-				}
-				if !info.IsDir() {
-					return fmt.Errorf(
-						"Safetensors path must be a directory: %s\n\n"+
-							"See 'docker model package --help' for more information",
-						opts.safetensorsDir,
-					)
+					return err
 				}
 			}
 
@@ -190,6 +214,11 @@ Packaging behavior:
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			opts.tag = args[0]
+			annotations, err := parseAnnotations(opts.annotationArgs)
+			if err != nil {
+				return err
+			}
+			opts.annotations = annotations
 			if err := packageModel(cmd.Context(), cmd, desktopClient, opts); err != nil {
 				cmd.PrintErrln("Failed to package model")
 				return fmt.Errorf("package model: %w", err)
@@ -200,23 +229,46 @@ Packaging behavior:
 	}
 
 	c.Flags().StringVar(&opts.ggufPath, "gguf", "", "absolute path to gguf file")
-	c.Flags().StringVar(&opts.safetensorsDir, "safetensors-dir", "", "absolute path to directory containing safetensors files and config")
+	c.Flags().StringVar(&opts.modelDir, "safetensors-dir", "", "absolute path to directory containing safetensors files and config")
 	c.Flags().StringVar(&opts.ddufPath, "dduf", "", "absolute path to DDUF archive file (Diffusers Unified Format)")
+	c.Flags().StringVar(&opts.modelDir, "from-dir", "", "absolute path to directory containing model files, whose format is detected")
+	c.MarkFlagsMutuallyExclusive("safetensors-dir", "from-dir")
 	c.Flags().StringVar(&opts.fromModel, "from", "", "reference to an existing model to repackage")
 	c.Flags().StringVar(&opts.chatTemplatePath, "chat-template", "", "absolute path to chat template file (must be Jinja format)")
 	c.Flags().StringArrayVarP(&opts.licensePaths, "license", "l", nil, "absolute path to a license file")
 	c.Flags().StringVar(&opts.mmprojPath, "mmproj", "", "absolute path to multimodal projector file")
 	c.Flags().BoolVar(&opts.push, "push", false, "push to registry (if not set, the model is loaded into the Model Runner content store)")
+	c.Flags().BoolVar(&opts.force, "force", false, "overwrite the target tag if it already points to a different model")
 	c.Flags().Uint64Var(&opts.contextSize, "context-size", 0, "context size in tokens")
+	c.Flags().StringArrayVar(&opts.annotationArgs, "annotation", nil, "manifest annotation to add, as KEY=VALUE")
 	return c
 }
 
+// parseAnnotations parses KEY=VALUE annotation arguments.
+func parseAnnotations(args []string) (map[string]string, error) {
+	if len(args) == 0 {
+		return nil, nil
+	}
+	annotations := make(map[string]string, len(args))
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid annotation %q: expected KEY=VALUE", arg)
+		}
+		annotations[key] = value
+	}
+	return annotations, nil
+}
+
 type packageOptions struct {
+	annotationArgs   []string
+	annotations      map[string]string
 	chatTemplatePath string
 	contextSize      uint64
 	ggufPath         string
-	safetensorsDir   string
 	ddufPath         string
+	force            bool
+	modelDir         string
 	fromModel        string
 	licensePaths     []string
 	mmprojPath       string
@@ -231,7 +283,7 @@ type builderInitResult struct {
 	cleanupFunc func()               // Optional cleanup function for temporary files
 }
 
-// initializeBuilder creates a package builder from GGUF, Safetensors, DDUF, or existing model
+// initializeBuilder creates a package builder from GGUF, Safetensors, DDUF, a model directory, or existing model
 func initializeBuilder(ctx context.Context, cmd *cobra.Command, client *desktop.Client, opts packageOptions) (*builderInitResult, error) {
 	result := &builderInitResult{}
 
@@ -291,13 +343,18 @@ func initializeBuilder(ctx context.Context, cmd *cobra.Command, client *desktop.
 			return nil, fmt.Errorf("add dduf file: %w", err)
 		}
 		result.builder = pkg
-	} else if opts.safetensorsDir != "" {
-		// Safetensors model from directory — uses V0.2 layer-per-file packaging
-		cmd.PrintErrf("Scanning directory %q for safetensors model...\n", opts.safetensorsDir)
-		pkg, err := builder.FromDirectory(opts.safetensorsDir)
+	} else if opts.modelDir != "" {
+		// Model from directory (--safetensors-dir or --from-dir) — uses V0.2 layer-per-file packaging
+		cmd.PrintErrf("Scanning directory %q for model files...\n", opts.modelDir)
+		pkg, err := builder.FromDirectory(opts.modelDir)
 		if err != nil {
-			return nil, fmt.Errorf("create safetensors model from directory: %w", err)
+			return nil, fmt.Errorf("create model from directory: %w", err)
 		}
+		cfg, err := pkg.Model().Config()
+		if err != nil {
+			return nil, fmt.Errorf("get model config: %w", err)
+		}
+		cmd.PrintErrf("Detected %s model\n", cfg.GetFormat())
 		result.builder = pkg
 	} else {
 		return nil, fmt.Errorf("no model source specified")
@@ -328,7 +385,7 @@ func fetchModelFromDaemon(ctx context.Context, cmd *cobra.Command, client *deskt
 	}
 
 	cmd.PrintErrf("Loading model from daemon...\n")
-	modelID, err := tempClient.LoadModel(exportReader, nil)
+	modelID, err := tempClient.LoadModel(ctx, exportReader, nil)
 	if err != nil {
 		cleanup()
 		return nil, nil, nil, fmt.Errorf("load model into temp store: %w", err)
@@ -350,6 +407,7 @@ func packageModel(ctx context.Context, cmd *cobra.Command, client *desktop.Clien
 		len(opts.licensePaths) == 0 &&
 		opts.chatTemplatePath == "" &&
 		opts.mmprojPath == "" &&
+		len(opts.annotations) == 0 &&
 		cmd.Flags().Changed("context-size")
 
 	if canUseDaemonRepackage {
@@ -364,6 +422,7 @@ func packageModel(ctx context.Context, cmd *cobra.Command, client *desktop.Clien
 
 		repackageOpts := desktop.RepackageOptions{
 			ContextSize: &opts.contextSize,
+			Force:       opts.force,
 		}
 		if err := client.RepackageModel(ctx, opts.fromModel, opts.tag, repackageOpts); err != nil {
 			return fmt.Errorf("failed to create lightweight model: %w", err)
@@ -435,6 +494,22 @@ func packageModel(ctx context.Context, cmd *cobra.Command, client *desktop.Clien
 		}
 	}
 
+	// Record provenance annotations, always including the packaging tool
+	annotations := map[string]string{types.AnnotationBuildTool: "docker-model-cli/" + desktop.Version}
+	maps.Copy(annotations, opts.annotations)
+	for _, key := range slices.Sorted(maps.Keys(opts.annotations)) {
+		cmd.PrintErrf("Adding annotation %s=%s\n", key, opts.annotations[key])
+	}
+	pkg = pkg.WithAnnotations(annotations)
+
+	// Refuse to move an existing local tag unless forced, as the daemon does
+	// when repackaging
+	if !opts.push {
+		if err := checkTagAvailable(client, opts.tag, pkg.Model(), opts.force); err != nil {
+			return err
+		}
+	}
+
 	// Check if we can use lightweight repackaging (config-only changes from existing model)
 	useLightweight := opts.fromModel != "" && pkg.HasOnlyConfigChanges()
 
@@ -500,6 +575,28 @@ func packageModel(ctx context.Context, cmd *cobra.Command, client *desktop.Clien
 	return nil
 }
 
+// checkTagAvailable returns an error if tag already points to a local model
+// other than mdl, unless force is set.
+func checkTagAvailable(client *desktop.Client, tag string, mdl types.ModelArtifact, force bool) error {
+	if force || tag == "" {
+		return nil
+	}
+	existing, err := client.Inspect(tag, false)
+	if errors.Is(err, desktop.ErrNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("inspect existing model %q: %w", tag, err)
+	}
+	id, err := mdl.ID()
+	if err != nil {
+		return fmt.Errorf("get model ID: %w", err)
+	}
+	if existing.ID != id {
+		return fmt.Errorf("tag %q already points to a different model, use --force to overwrite it", tag)
+	}
+	return nil
+}
+
 // modelRunnerTarget loads model to Docker Model Runner via models/load endpoint
 type modelRunnerTarget struct {
 	client *desktop.Client
@@ -547,7 +644,7 @@ func (t *modelRunnerTarget) Write(ctx context.Context, mdl types.ModelArtifact,
 		return fmt.Errorf("get model ID: %w", err)
 	}
 	if t.tag != nil {
-		if err := t.client.Tag(id, parseRepo(t.tag), t.tag.TagStr()); err != nil {
+		if _, err := t.client.Tag(id, parseRepo(t.tag), t.tag.TagStr(), false); err != nil {
 			return fmt.Errorf("tag model: %w", err)
 		}
 	}
@@ -0,0 +1,78 @@
+package commands
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/cmd/cli/desktop"
+	mockdesktop "github.com/docker/model-runner/cmd/cli/mocks"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestPackageGGUFOntoExistingTag verifies that packaging a GGUF file onto a
+// tag that points to a different model fails unless --force is set.
+func TestPackageGGUFOntoExistingTag(t *testing.T) {
+	ggufPath, err := filepath.Abs(filepath.Join("..", "..", "..", "assets", "dummy.gguf"))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		force  bool
+		loaded bool
+	}{
+		{name: "without force"},
+		{name: "with force", force: true, loaded: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mockdesktop.NewMockDockerHttpClient(ctrl)
+			originalRunner, originalClient := modelRunner, desktopClient
+			modelRunner = desktop.NewContextForMock(client)
+			desktopClient = desktop.New(modelRunner)
+			t.Cleanup(func() { modelRunner, desktopClient = originalRunner, originalClient })
+
+			var loaded bool
+			client.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				path := strings.TrimPrefix(req.URL.Path, inference.ExperimentalEndpointsPrefix)
+				switch {
+				case req.Method == http.MethodGet && path == inference.ModelsPrefix+"/ai/existing:v1":
+					body := `{"id":"sha256:0000000000000000000000000000000000000000000000000000000000000000","tags":["ai/existing:v1"]}`
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(body))}, nil
+				case req.Method == http.MethodPost && path == inference.ModelsPrefix+"/load":
+					loaded = true
+					_, _ = io.Copy(io.Discard, req.Body)
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+				case req.Method == http.MethodPost && strings.HasSuffix(path, "/tag"):
+					body := `{"message":"Model tagged","target":"ai/existing:v1"}`
+					return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(body))}, nil
+				}
+				t.Errorf("unexpected request: %s %s", req.Method, path)
+				return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader(""))}, nil
+			}).AnyTimes()
+
+			cmd := newPackagedCmd()
+			cmd.SetOut(new(bytes.Buffer))
+			cmd.SetErr(new(bytes.Buffer))
+			args := []string{"--gguf", ggufPath, "ai/existing:v1"}
+			if tt.force {
+				args = append(args, "--force")
+			}
+			cmd.SetArgs(args)
+			err := cmd.Execute()
+			if tt.force {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, "already points to a different model")
+			}
+			assert.Equal(t, tt.loaded, loaded)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/docker/go-units"
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/spf13/cobra"
+)
+
+func newPruneCmd() *cobra.Command {
+	var force bool
+
+	c := &cobra.Command{
+		Use:   "prune [OPTIONS]",
+		Short: "Remove all dangling models",
+		Long:  "Remove all dangling models, i.e. models without any tags",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if !force {
+				cmd.Println("WARNING! This will remove all dangling models.")
+				cmd.Print("Are you sure you want to continue? [y/N] ")
+
+				var input string
+				_, err := fmt.Scanln(&input)
+				if err != nil && err.Error() != "unexpected newline" {
+					return err
+				}
+
+				if input != "y" && input != "Y" {
+					cmd.Println("Operation cancelled.")
+					return nil
+				}
+			}
+			resp, err := desktopClient.Prune()
+			if err != nil {
+				return handleClientError(err, "Failed to prune models")
+			}
+			for _, id := range resp.Deleted {
+				cmd.Printf("Deleted: %s\n", id)
+			}
+			cmd.Printf("Total reclaimed space: %s\n", units.CustomSize("%.2f%s", float64(resp.SpaceReclaimed), 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}))
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+
+	c.Flags().BoolVarP(&force, "force", "f", false, "Do not prompt for confirmation")
+	return c
+}
@@ -2,51 +2,89 @@ package commands
 
 import (
 	"bytes"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/docker/go-units"
 	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
 	"github.com/docker/model-runner/cmd/cli/desktop"
 	"github.com/docker/model-runner/pkg/inference"
 	"github.com/spf13/cobra"
 )
 
 func newPSCmd() *cobra.Command {
+	var quiet bool
+	var format string
 	c := &cobra.Command{
-		Use:   "ps",
+		Use:   "ps [OPTIONS]",
 		Short: "List running models",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if quiet && format != "" {
+				return fmt.Errorf("--quiet flag cannot be used with --format flag")
+			}
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
 			ps, err := desktopClient.PS()
 			if err != nil {
 				return handleClientError(err, "Failed to list running models")
 			}
-			cmd.Print(psTable(ps))
+			output, err := formatPS(ps, quiet, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
 			return nil
 		},
 		ValidArgsFunction: completion.NoComplete,
 	}
+	c.Flags().BoolVarP(&quiet, "quiet", "q", false, "Only show model names")
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
 	return c
 }
 
+// formatPS renders the running models as JSON, as a list of model names (one
+// per line) when quiet is set, or as a table otherwise.
+func formatPS(ps []desktop.BackendStatus, quiet bool, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		if ps == nil {
+			ps = []desktop.BackendStatus{}
+		}
+		return formatter.ToStandardJSON(ps)
+	}
+	if quiet {
+		var names strings.Builder
+		for _, status := range ps {
+			names.WriteString(psModelName(status) + "\n")
+		}
+		return names.String(), nil
+	}
+	return psTable(ps), nil
+}
+
+// psModelName returns the display name of a running model: the short ID for
+// models referenced by digest, otherwise the name without default prefixes.
+func psModelName(status desktop.BackendStatus) string {
+	if strings.HasPrefix(status.ModelName, "sha256:") && len(status.ModelName) >= 19 {
+		return status.ModelName[7:19]
+	}
+	return stripDefaultsFromModelName(strings.ToLower(status.ModelName))
+}
+
 func psTable(ps []desktop.BackendStatus) string {
 	var buf bytes.Buffer
 	table := newTable(&buf)
-	table.Header([]string{"MODEL NAME", "BACKEND", "MODE", "UNTIL"})
+	table.Header([]string{"MODEL NAME", "BACKEND", "MODE", "UNTIL", "HEALTH"})
 
 	for _, status := range ps {
-		modelName := status.ModelName
-		if strings.HasPrefix(modelName, "sha256:") {
-			modelName = modelName[7:19]
-		} else {
-			modelName = stripDefaultsFromModelName(strings.ToLower(modelName))
-		}
-
 		table.Append([]string{
-			modelName,
+			psModelName(status),
 			status.BackendName,
 			status.Mode,
 			formatUntil(status),
+			formatHealth(status),
 		})
 	}
 
@@ -78,3 +116,13 @@ func formatUntil(status desktop.BackendStatus) string {
 	}
 	return units.HumanDuration(remaining) + " from now"
 }
+
+func formatHealth(status desktop.BackendStatus) string {
+	if status.Loading {
+		return "-"
+	}
+	if status.Healthy {
+		return "healthy"
+	}
+	return "unhealthy"
+}
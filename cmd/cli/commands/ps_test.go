@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/model-runner/cmd/cli/desktop"
+)
+
+func testBackendStatuses() []desktop.BackendStatus {
+	return []desktop.BackendStatus{
+		{
+			BackendName: "llama.cpp",
+			ModelName:   "ai/smollm2:latest",
+			Mode:        "completion",
+			LastUsed:    time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+			Healthy:     true,
+		},
+		{
+			BackendName: "vllm",
+			ModelName:   "sha256:123456789012345678901234567890123456789012345678901234567890abcd",
+			Mode:        "embedding",
+			Loading:     true,
+		},
+	}
+}
+
+func TestFormatPSQuiet(t *testing.T) {
+	output, err := formatPS(testBackendStatuses(), true, false)
+	if err != nil {
+		t.Fatalf("formatPS() error = %v", err)
+	}
+	want := "smollm2\n123456789012\n"
+	if output != want {
+		t.Errorf("formatPS() quiet output = %q, want %q", output, want)
+	}
+}
+
+func TestFormatPSJSON(t *testing.T) {
+	statuses := testBackendStatuses()
+	output, err := formatPS(statuses, false, true)
+	if err != nil {
+		t.Fatalf("formatPS() error = %v", err)
+	}
+
+	var got []desktop.BackendStatus
+	if err := json.Unmarshal([]byte(output), &got); err != nil {
+		t.Fatalf("formatPS() output is not valid JSON: %v\n%s", err, output)
+	}
+	if len(got) != len(statuses) {
+		t.Fatalf("got %d entries, want %d", len(got), len(statuses))
+	}
+	for i := range statuses {
+		if got[i].BackendName != statuses[i].BackendName ||
+			got[i].ModelName != statuses[i].ModelName ||
+			got[i].Mode != statuses[i].Mode ||
+			got[i].Loading != statuses[i].Loading ||
+			got[i].Healthy != statuses[i].Healthy ||
+			!got[i].LastUsed.Equal(statuses[i].LastUsed) {
+			t.Errorf("entry %d = %+v, want %+v", i, got[i], statuses[i])
+		}
+	}
+}
+
+func TestFormatPSJSONEmpty(t *testing.T) {
+	output, err := formatPS(nil, false, true)
+	if err != nil {
+		t.Fatalf("formatPS() error = %v", err)
+	}
+	if strings.TrimSpace(output) != "[]" {
+		t.Errorf("formatPS() empty JSON output = %q, want %q", output, "[]")
+	}
+}
+
+func TestFormatPSTable(t *testing.T) {
+	output, err := formatPS(testBackendStatuses(), false, false)
+	if err != nil {
+		t.Fatalf("formatPS() error = %v", err)
+	}
+	for _, want := range []string{"MODEL NAME", "smollm2", "123456789012", "llama.cpp", "Loading...", "healthy"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("formatPS() table output does not contain %q:\n%s", want, output)
+		}
+	}
+}
@@ -7,22 +7,34 @@ import (
 )
 
 func newPullCmd() *cobra.Command {
+	var noNormalize bool
 	c := &cobra.Command{
 		Use:   "pull MODEL",
 		Short: "Pull a model from Docker Hub or HuggingFace to your local environment",
 		Args:  requireExactArgs(1, "pull", "MODEL"),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return pullModel(cmd, desktopClient, args[0])
+			model, err := resolveModelAlias(args[0])
+			if err != nil {
+				return err
+			}
+			return pullModelWithOptions(cmd, desktopClient, model, desktop.PullOptions{
+				RawReference: noNormalize,
+			})
 		},
-		ValidArgsFunction: completion.NoComplete,
+		ValidArgsFunction: completion.RemoteModelNames(1),
 	}
+	c.Flags().BoolVar(&noNormalize, "no-normalize", false, "Use the model reference verbatim, without adding the default organization or tag")
 
 	return c
 }
 
 func pullModel(cmd *cobra.Command, desktopClient *desktop.Client, model string) error {
+	return pullModelWithOptions(cmd, desktopClient, model, desktop.PullOptions{})
+}
+
+func pullModelWithOptions(cmd *cobra.Command, desktopClient *desktop.Client, model string, opts desktop.PullOptions) error {
 	printer := asPrinter(cmd)
-	response, _, err := desktopClient.Pull(model, printer)
+	response, _, err := desktopClient.PullWithOptions(model, printer, opts)
 
 	if err != nil {
 		return handleClientError(err, "Failed to pull model")
@@ -41,6 +41,11 @@ func initModelRunner(cmd *cobra.Command, cli *command.DockerCli) error {
 	if err != nil {
 		return fmt.Errorf("unable to detect model runner context: %w", err)
 	}
+	timeouts, err := desktop.ClientTimeoutsFromEnv()
+	if err != nil {
+		return err
+	}
+	modelRunner.SetTimeouts(timeouts)
 	desktopClient = desktop.New(modelRunner)
 	return nil
 }
@@ -97,6 +102,7 @@ func NewRootCmd(cli *command.DockerCli) *cobra.Command {
 	rootCmd.AddCommand(
 		newVersionCmd(),
 		newContextCmd(cli),
+		newAliasCmd(cli),
 		newInstallRunner(),
 		newUninstallRunner(),
 		newStartRunner(),
@@ -121,11 +127,19 @@ func NewRootCmd(cli *command.DockerCli) *cobra.Command {
 		newComposeCmd(),
 		newLaunchCmd(),
 		newTagCmd(),
+		newTagsCmd(),
 		newConfigureCmd(),
 		newPSCmd(),
 		newDFCmd(),
+		newDiffCmd(),
+		newInfoCmd(),
+		newOutdatedCmd(),
+		newDebugCmd(),
 		newUnloadCmd(),
+		newWarmCmd(),
 		newRequestsCmd(),
+		newStatsCmd(),
+		newPruneCmd(),
 		newPurgeCmd(),
 		newBenchCmd(),
 	} {
@@ -8,11 +8,13 @@ import (
 	"io"
 	"os"
 	"os/signal"
+	"reflect"
 	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/charmbracelet/glamour"
+	"github.com/docker/cli/cli"
 	"github.com/docker/model-runner/cmd/cli/commands/completion"
 	"github.com/docker/model-runner/cmd/cli/desktop"
 	"github.com/docker/model-runner/cmd/cli/readline"
@@ -181,7 +183,10 @@ func generateInteractiveWithReadline(cmd *cobra.Command, desktopClient *desktop.
 	var sb strings.Builder
 	var multiline bool
 	var conversationHistory []desktop.OpenAIChatMessage
-	var systemPrompt string
+	// The system prompt starts out as --system and can be changed with /set system.
+	chatOpts := chatOptionsFromFlags(cmd)
+	systemPrompt := chatOpts.SystemPrompt
+	chatOpts.SystemPrompt = ""
 
 	// Add a helper function to handle file inclusion when @ is pressed
 	// We'll implement a basic version here that shows a message when @ is pressed
@@ -363,7 +368,7 @@ func generateInteractiveWithReadline(cmd *cobra.Command, desktopClient *desktop.
 				messagesWithSystem = append(messagesWithSystem, conversationHistory...)
 			}
 
-			assistantResponse, processedUserMessage, err := chatWithMarkdownContext(chatCtx, cmd, desktopClient, model, userInput, messagesWithSystem)
+			assistantResponse, processedUserMessage, err := chatWithMarkdownContext(chatCtx, cmd, desktopClient, model, userInput, messagesWithSystem, chatOpts)
 
 			// Clean up signal handler
 			signal.Stop(sigChan)
@@ -371,9 +376,12 @@ func generateInteractiveWithReadline(cmd *cobra.Command, desktopClient *desktop.
 			cancelChat()
 
 			if err != nil {
-				// Check if the error is due to context cancellation (Ctrl+C during response)
+				// Check if the error is due to context cancellation (Ctrl+C during response).
+				// An interrupted response has already been ended on a new line.
 				if errors.Is(err, context.Canceled) {
-					cmd.Println()
+					if !errors.Is(err, desktop.ErrChatInterrupted) {
+						cmd.Println()
+					}
 				} else {
 					cmd.PrintErrln(handleClientError(err, "Failed to generate a response"))
 				}
@@ -615,15 +623,60 @@ func buildUserMessage(prompt string, imageURLs []string) desktop.OpenAIChatMessa
 }
 
 // chatWithMarkdown performs chat and streams the response with selective markdown rendering.
+// The chat is cancelled on Ctrl+C.
 func chatWithMarkdown(cmd *cobra.Command, client *desktop.Client, model, prompt string) error {
-	_, _, err := chatWithMarkdownContext(cmd.Context(), cmd, client, model, prompt, nil)
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+	_, _, err := chatWithMarkdownContext(ctx, cmd, client, model, prompt, nil, chatOptionsFromFlags(cmd))
 	return err
 }
 
+// chatInterruptedExitCode is the exit code of a chat cut short by Ctrl+C,
+// following the shell convention of 128 + SIGINT.
+const chatInterruptedExitCode = 130
+
+// chatInterrupted returns the error for a chat interrupted by the user: a
+// silent one exiting with chatInterruptedExitCode, since the partial response
+// has already been printed.
+func chatInterrupted(cmd *cobra.Command) error {
+	cmd.SilenceErrors = true
+	cmd.SilenceUsage = true
+	return cli.StatusError{StatusCode: chatInterruptedExitCode}
+}
+
+// chatOptionsFromFlags returns the system prompt and sampling parameters set
+// on cmd. Sampling parameters that were not set are left nil so that the
+// model's defaults apply.
+func chatOptionsFromFlags(cmd *cobra.Command) desktop.ChatOptions {
+	flags := cmd.Flags()
+	var opts desktop.ChatOptions
+	opts.SystemPrompt, _ = flags.GetString("system")
+	if flags.Changed("temperature") {
+		temperature, _ := flags.GetFloat64("temperature")
+		opts.Temperature = &temperature
+	}
+	if flags.Changed("top-p") {
+		topP, _ := flags.GetFloat64("top-p")
+		opts.TopP = &topP
+	}
+	if flags.Changed("max-tokens") {
+		maxTokens, _ := flags.GetInt("max-tokens")
+		opts.MaxTokens = &maxTokens
+	}
+	opts.Stop, _ = flags.GetStringArray("stop")
+	// PreRunE has already checked that the template file is readable.
+	if path, _ := flags.GetString("chat-template-file"); path != "" {
+		if template, err := os.ReadFile(path); err == nil {
+			opts.ChatTemplate = string(template)
+		}
+	}
+	return opts
+}
+
 // chatWithMarkdownContext performs chat with context support and streams the response with selective markdown rendering.
-// It accepts an optional conversation history and returns both the assistant's response and the processed user message
+// It accepts an optional conversation history and chat options, and returns both the assistant's response and the processed user message
 // (after file inclusions and image processing) for accurate history tracking.
-func chatWithMarkdownContext(ctx context.Context, cmd *cobra.Command, client *desktop.Client, model, prompt string, conversationHistory []desktop.OpenAIChatMessage) (assistantResponse string, processedUserMessage desktop.OpenAIChatMessage, err error) {
+func chatWithMarkdownContext(ctx context.Context, cmd *cobra.Command, client *desktop.Client, model, prompt string, conversationHistory []desktop.OpenAIChatMessage, opts desktop.ChatOptions) (assistantResponse string, processedUserMessage desktop.OpenAIChatMessage, err error) {
 	colorMode, _ := cmd.Flags().GetString("color")
 	useMarkdown := shouldUseMarkdown(colorMode)
 	debug, _ := cmd.Flags().GetBool("debug")
@@ -653,7 +706,7 @@ func chatWithMarkdownContext(ctx context.Context, cmd *cobra.Command, client *de
 
 	if !useMarkdown {
 		// Simple case: just stream as plain text
-		assistantResponse, err = client.ChatWithMessagesContext(ctx, model, conversationHistory, prompt, imageURLs, func(content string) {
+		assistantResponse, err = client.ChatWithOptions(ctx, model, conversationHistory, prompt, imageURLs, opts, func(content string) {
 			cmd.Print(content)
 		}, false, activeTools...)
 		return assistantResponse, processedUserMessage, err
@@ -662,7 +715,7 @@ func chatWithMarkdownContext(ctx context.Context, cmd *cobra.Command, client *de
 	// For markdown: use streaming buffer to render code blocks as they complete
 	markdownBuffer := NewStreamingMarkdownBuffer()
 
-	assistantResponse, err = client.ChatWithMessagesContext(ctx, model, conversationHistory, prompt, imageURLs, func(content string) {
+	assistantResponse, err = client.ChatWithOptions(ctx, model, conversationHistory, prompt, imageURLs, opts, func(content string) {
 		// Use the streaming markdown buffer to intelligently render content
 		rendered, renderErr := markdownBuffer.AddContent(content, true)
 		if renderErr != nil {
@@ -687,11 +740,40 @@ func chatWithMarkdownContext(ctx context.Context, cmd *cobra.Command, client *de
 	return assistantResponse, processedUserMessage, nil
 }
 
+// Pull policies accepted by run --pull.
+const (
+	pullAlways  = "always"
+	pullMissing = "missing"
+	pullNever   = "never"
+)
+
+// ensureModelPulled makes model available locally before it is run, pulling
+// it according to policy. Pulls go through the regular pull path, so the
+// runner's checks and progress display apply as for docker model pull.
+func ensureModelPulled(cmd *cobra.Command, client *desktop.Client, model, policy string) error {
+	if policy == pullAlways {
+		return pullModel(cmd, client, model)
+	}
+	inStore, err := client.IsModelInStore(model)
+	if err != nil {
+		return handleClientError(err, "Failed to inspect model")
+	}
+	if inStore {
+		return nil
+	}
+	if policy == pullNever {
+		return fmt.Errorf("model %s not found locally and --pull=%s is set", model, pullNever)
+	}
+	cmd.Println("Unable to find model '" + model + "' locally. Pulling from the server.")
+	return pullModel(cmd, client, model)
+}
+
 func newRunCmd() *cobra.Command {
 	var debug bool
 	var colorMode string
 	var detach bool
 	var openaiURL string
+	var pullPolicy string
 
 	const cmdArgs = "MODEL [PROMPT]"
 	c := &cobra.Command{
@@ -700,13 +782,51 @@ func newRunCmd() *cobra.Command {
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			switch colorMode {
 			case "auto", "yes", "no":
-				return nil
 			default:
 				return fmt.Errorf("--color must be one of: auto, yes, no (got %q)", colorMode)
 			}
+			switch pullPolicy {
+			case pullAlways, pullMissing, pullNever:
+			default:
+				return fmt.Errorf("--pull must be one of: %s, %s, %s (got %q)", pullAlways, pullMissing, pullNever, pullPolicy)
+			}
+			opts := chatOptionsFromFlags(cmd)
+			if opts.Temperature != nil && *opts.Temperature < 0 {
+				return fmt.Errorf("--temperature must not be negative (got %g)", *opts.Temperature)
+			}
+			if opts.TopP != nil && (*opts.TopP <= 0 || *opts.TopP > 1) {
+				return fmt.Errorf("--top-p must be in (0, 1] (got %g)", *opts.TopP)
+			}
+			if opts.MaxTokens != nil && *opts.MaxTokens <= 0 {
+				return fmt.Errorf("--max-tokens must be positive (got %d)", *opts.MaxTokens)
+			}
+			if path, _ := cmd.Flags().GetString("chat-template-file"); path != "" {
+				template, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("reading --chat-template-file: %w", err)
+				}
+				if err := desktop.ValidateChatTemplate(string(template)); err != nil {
+					return fmt.Errorf("--chat-template-file %s: %w", path, err)
+				}
+			}
+			if cmd.Flags().Changed("gpu-layers") {
+				if openaiURL != "" {
+					return fmt.Errorf("--gpu-layers flag cannot be used with --openaiurl flag")
+				}
+				if gpuLayers, _ := cmd.Flags().GetInt32("gpu-layers"); gpuLayers < 0 {
+					return fmt.Errorf("--gpu-layers must not be negative (got %d)", gpuLayers)
+				}
+			}
+			if cmd.Flags().Changed("backend-arg") && openaiURL != "" {
+				return fmt.Errorf("--backend-arg flag cannot be used with --openaiurl flag")
+			}
+			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			model := args[0]
+			model, err := resolveModelAlias(args[0])
+			if err != nil {
+				return err
+			}
 			prompt := ""
 			argsLen := len(args)
 			if argsLen > 1 {
@@ -752,9 +872,14 @@ func newRunCmd() *cobra.Command {
 				if prompt != "" {
 					// Single prompt mode
 					useMarkdown := shouldUseMarkdown(colorMode)
-					if err := openaiClient.ChatWithContext(cmd.Context(), model, prompt, nil, func(content string) {
+					ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+					defer stop()
+					if _, err := openaiClient.ChatWithOptions(ctx, model, nil, prompt, nil, chatOptionsFromFlags(cmd), func(content string) {
 						cmd.Print(content)
 					}, useMarkdown); err != nil {
+						if errors.Is(err, desktop.ErrChatInterrupted) {
+							return chatInterrupted(cmd)
+						}
 						return handleClientError(err, "Failed to generate a response")
 					}
 					cmd.Println()
@@ -828,13 +953,12 @@ func newRunCmd() *cobra.Command {
 				return nil
 			}
 
-			_, err := desktopClient.Inspect(model, false)
-			if err != nil {
-				if !errors.Is(err, desktop.ErrNotFound) {
-					return handleClientError(err, "Failed to inspect model")
-				}
-				cmd.Println("Unable to find model '" + model + "' locally. Pulling from the server.")
-				if err := pullModel(cmd, desktopClient, model); err != nil {
+			if err := ensureModelPulled(cmd, desktopClient, model, pullPolicy); err != nil {
+				return err
+			}
+
+			if cmd.Flags().Changed("gpu-layers") || cmd.Flags().Changed("backend-arg") {
+				if err := configureRunBackend(cmd, model); err != nil {
 					return err
 				}
 			}
@@ -852,6 +976,9 @@ func newRunCmd() *cobra.Command {
 
 			if prompt != "" {
 				if err := chatWithMarkdown(cmd, desktopClient, model, prompt); err != nil {
+					if errors.Is(err, desktop.ErrChatInterrupted) {
+						return chatInterrupted(cmd)
+					}
 					return handleClientError(err, "Failed to generate a response")
 				}
 				cmd.Println()
@@ -884,7 +1011,53 @@ func newRunCmd() *cobra.Command {
 	c.Flags().StringVar(&colorMode, "color", "no", "Use colored output (auto|yes|no)")
 	c.Flags().BoolVarP(&detach, "detach", "d", false, "Load the model in the background without interaction")
 	c.Flags().StringVar(&openaiURL, "openaiurl", "", "OpenAI-compatible API endpoint URL to chat with")
+	c.Flags().StringVar(&pullPolicy, "pull", pullMissing, "Pull the model before running it (always|missing|never)")
 	c.Flags().Bool("websearch", false, "Enable web search tool during chat")
+	c.Flags().String("system", "", "System prompt to send ahead of the conversation")
+	c.Flags().Float64("temperature", 0, "Sampling temperature (model default if unset)")
+	c.Flags().Float64("top-p", 0, "Nucleus sampling probability (model default if unset)")
+	c.Flags().Int("max-tokens", 0, "Maximum number of tokens to generate (model default if unset)")
+	c.Flags().StringArray("stop", nil, "Sequence at which to stop generating (can be repeated)")
+	c.Flags().String("chat-template-file", "", "Jinja chat template to use instead of the model's for this session")
+	c.Flags().Int32("gpu-layers", 0, "Number of model layers to offload to the GPU (all if unset)")
+	c.Flags().StringArray("backend-arg", nil, "Extra argument to append to the inference engine command line (can be repeated)")
 
 	return c
 }
+
+// configureRunBackend applies --gpu-layers and --backend-arg on top of the
+// model's current runner configuration, since a configure request replaces the
+// whole configuration and would otherwise drop settings such as the context
+// size. Nothing is sent if the flags leave the configuration unchanged, so
+// that run can still use a model that is already loaded with it.
+func configureRunBackend(cmd *cobra.Command, model string) error {
+	configs, err := desktopClient.ShowConfigs(model)
+	if err != nil {
+		return handleClientError(err, "Failed to get backend configuration")
+	}
+	request := scheduling.ConfigureRequest{Model: model}
+	var current inference.BackendConfiguration
+	for _, entry := range configs {
+		if entry.Mode == inference.BackendModeCompletion || request.Mode == nil {
+			mode := entry.Mode
+			request.Mode = &mode
+			current = entry.Config
+		}
+	}
+
+	request.BackendConfiguration = current
+	if cmd.Flags().Changed("gpu-layers") {
+		gpuLayers, _ := cmd.Flags().GetInt32("gpu-layers")
+		request.GPULayers = &gpuLayers
+	}
+	if cmd.Flags().Changed("backend-arg") {
+		request.ExtraArgs, _ = cmd.Flags().GetStringArray("backend-arg")
+	}
+	if request.Mode != nil && reflect.DeepEqual(request.BackendConfiguration, current) {
+		return nil
+	}
+	if err := desktopClient.ConfigureBackend(request); err != nil {
+		return handleClientError(err, "Failed to configure backend")
+	}
+	return nil
+}
@@ -2,10 +2,24 @@ package commands
 
 import (
 	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 
+	"github.com/docker/cli/cli"
+	"github.com/docker/model-runner/cmd/cli/desktop"
+	mockdesktop "github.com/docker/model-runner/cmd/cli/mocks"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/scheduling"
 	"github.com/spf13/cobra"
+	"go.uber.org/mock/gomock"
 )
 
 func TestReadMultilineInput(t *testing.T) {
@@ -160,3 +174,295 @@ func TestRunCmdDetachFlag(t *testing.T) {
 		t.Errorf("Expected detach flag value to be true, got false")
 	}
 }
+
+func TestChatOptionsFromFlags(t *testing.T) {
+	cmd := newRunCmd()
+	opts := chatOptionsFromFlags(cmd)
+	if opts.SystemPrompt != "" || opts.Temperature != nil || opts.TopP != nil || opts.MaxTokens != nil || len(opts.Stop) != 0 {
+		t.Fatalf("Expected no chat options by default, got %+v", opts)
+	}
+
+	for flag, value := range map[string]string{
+		"system":      "Be terse.",
+		"temperature": "0",
+		"top-p":       "0.5",
+		"max-tokens":  "128",
+	} {
+		if err := cmd.Flags().Set(flag, value); err != nil {
+			t.Fatalf("Failed to set --%s: %v", flag, err)
+		}
+	}
+	for _, stop := range []string{"END", "\n\n"} {
+		if err := cmd.Flags().Set("stop", stop); err != nil {
+			t.Fatalf("Failed to set --stop: %v", err)
+		}
+	}
+
+	opts = chatOptionsFromFlags(cmd)
+	if opts.SystemPrompt != "Be terse." {
+		t.Errorf("Expected system prompt %q, got %q", "Be terse.", opts.SystemPrompt)
+	}
+	// An explicit zero temperature must be sent rather than left to the model default.
+	if opts.Temperature == nil || *opts.Temperature != 0 {
+		t.Errorf("Expected temperature 0, got %v", opts.Temperature)
+	}
+	if opts.TopP == nil || *opts.TopP != 0.5 {
+		t.Errorf("Expected top-p 0.5, got %v", opts.TopP)
+	}
+	if opts.MaxTokens == nil || *opts.MaxTokens != 128 {
+		t.Errorf("Expected max-tokens 128, got %v", opts.MaxTokens)
+	}
+	if len(opts.Stop) != 2 || opts.Stop[0] != "END" || opts.Stop[1] != "\n\n" {
+		t.Errorf("Expected stop sequences [END \\n\\n], got %q", opts.Stop)
+	}
+}
+
+func TestRunCmdBackendFlagsValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]string
+		wantErr string
+	}{
+		{name: "valid", flags: map[string]string{"gpu-layers": "12"}},
+		{name: "zero", flags: map[string]string{"gpu-layers": "0"}},
+		{name: "negative", flags: map[string]string{"gpu-layers": "-1"}, wantErr: "--gpu-layers must not be negative"},
+		{name: "with openaiurl", flags: map[string]string{"gpu-layers": "12", "openaiurl": "http://localhost:8080/v1"}, wantErr: "cannot be used with --openaiurl"},
+		{name: "backend arg", flags: map[string]string{"backend-arg": "--override-kv"}},
+		{name: "backend arg with openaiurl", flags: map[string]string{"backend-arg": "--override-kv", "openaiurl": "http://localhost:8080/v1"}, wantErr: "--backend-arg flag cannot be used with --openaiurl"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRunCmd()
+			for flag, value := range tt.flags {
+				if err := cmd.Flags().Set(flag, value); err != nil {
+					t.Fatalf("Failed to set --%s: %v", flag, err)
+				}
+			}
+			err := cmd.PreRunE(cmd, []string{"ai/smollm2"})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRunCmdChatTemplateFile(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.jinja")
+	if err := os.WriteFile(valid, []byte("{% for m in messages %}{{ m['content'] }}{% endfor %}"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+	invalid := filepath.Join(dir, "invalid.jinja")
+	if err := os.WriteFile(invalid, []byte("{% for m in messages %}{{ m['content'] }}"), 0o644); err != nil {
+		t.Fatalf("Failed to write template: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr string
+	}{
+		{name: "valid", path: valid},
+		{name: "invalid", path: invalid, wantErr: `unclosed "for" block`},
+		{name: "missing", path: filepath.Join(dir, "missing.jinja"), wantErr: "reading --chat-template-file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newRunCmd()
+			if err := cmd.Flags().Set("chat-template-file", tt.path); err != nil {
+				t.Fatalf("Failed to set --chat-template-file: %v", err)
+			}
+			err := cmd.PreRunE(cmd, []string{"ai/smollm2"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if opts := chatOptionsFromFlags(cmd); !strings.Contains(opts.ChatTemplate, "for m in messages") {
+				t.Errorf("Expected chat template from file, got %q", opts.ChatTemplate)
+			}
+		})
+	}
+}
+
+func TestChatInterrupted(t *testing.T) {
+	cmd := newRunCmd()
+	err := chatInterrupted(cmd)
+
+	var statusErr cli.StatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("Expected a cli.StatusError, got %T: %v", err, err)
+	}
+	if statusErr.StatusCode != 130 {
+		t.Errorf("Expected exit code 130, got %d", statusErr.StatusCode)
+	}
+	if statusErr.Error() != "" {
+		t.Errorf("Expected a silent error, got %q", statusErr.Error())
+	}
+	if !cmd.SilenceErrors || !cmd.SilenceUsage {
+		t.Error("Expected errors and usage to be silenced")
+	}
+}
+
+func TestRunCmdPullPolicy(t *testing.T) {
+	const model = "ai/smollm2"
+	inspectPath := inference.ModelsPrefix + "/" + model
+	createPath := inference.ModelsPrefix + "/create"
+	preloadPath := inference.InferencePrefix + "/v1/chat/completions"
+
+	tests := []struct {
+		name      string
+		policy    string
+		inStore   bool
+		wantCalls []string
+		wantErr   string
+	}{
+		{name: "missing pulls an absent model", policy: pullMissing, wantCalls: []string{inspectPath, createPath, preloadPath}},
+		{name: "missing skips a present model", policy: pullMissing, inStore: true, wantCalls: []string{inspectPath, preloadPath}},
+		{name: "always pulls a present model", policy: pullAlways, inStore: true, wantCalls: []string{createPath, preloadPath}},
+		{name: "never skips a present model", policy: pullNever, inStore: true, wantCalls: []string{inspectPath, preloadPath}},
+		{name: "never fails for an absent model", policy: pullNever, wantCalls: []string{inspectPath}, wantErr: "--pull=never"},
+		{name: "invalid policy", policy: "sometimes", wantErr: "--pull must be one of"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mockdesktop.NewMockDockerHttpClient(ctrl)
+			originalRunner, originalClient := modelRunner, desktopClient
+			modelRunner = desktop.NewContextForMock(client)
+			desktopClient = desktop.New(modelRunner)
+			t.Cleanup(func() { modelRunner, desktopClient = originalRunner, originalClient })
+
+			var calls []string
+			client.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				path := strings.TrimPrefix(req.URL.Path, inference.ExperimentalEndpointsPrefix)
+				calls = append(calls, path)
+				switch path {
+				case inspectPath:
+					if !tt.inStore {
+						return &http.Response{StatusCode: http.StatusNotFound, Body: io.NopCloser(strings.NewReader("not found"))}, nil
+					}
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":"sha256:1","tags":["` + model + `:latest"]}`))}, nil
+				case createPath:
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"type":"success","message":"Model pulled successfully"}`))}, nil
+				default:
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+				}
+			}).AnyTimes()
+
+			cmd := newRunCmd()
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs([]string{"--detach", "--pull", tt.policy, model})
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Expected error containing %q, got %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Unexpected error: %v\n%s", err, buf.String())
+			}
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("Expected requests %v, got %v", tt.wantCalls, calls)
+			}
+		})
+	}
+}
+
+func TestRunCmdBackendFlagsKeepConfiguration(t *testing.T) {
+	const model = "ai/smollm2"
+	inspectPath := inference.ModelsPrefix + "/" + model
+	configurePath := inference.InferencePrefix + "/_configure"
+	contextSize, gpuLayers := int32(8192), int32(12)
+
+	tests := []struct {
+		name          string
+		args          []string
+		wantConfigure bool
+	}{
+		{name: "changed gpu layers", args: []string{"--gpu-layers", "20"}, wantConfigure: true},
+		{name: "unchanged gpu layers", args: []string{"--gpu-layers", "12"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			client := mockdesktop.NewMockDockerHttpClient(ctrl)
+			originalRunner, originalClient := modelRunner, desktopClient
+			modelRunner = desktop.NewContextForMock(client)
+			desktopClient = desktop.New(modelRunner)
+			t.Cleanup(func() { modelRunner, desktopClient = originalRunner, originalClient })
+
+			existing, err := json.Marshal([]scheduling.ModelConfigEntry{{
+				Backend: "llama.cpp",
+				Model:   model + ":latest",
+				ModelID: "sha256:1",
+				Mode:    inference.BackendModeCompletion,
+				Config:  inference.BackendConfiguration{ContextSize: &contextSize, GPULayers: &gpuLayers},
+			}})
+			if err != nil {
+				t.Fatalf("Failed to encode configs: %v", err)
+			}
+			var sent *scheduling.ConfigureRequest
+			client.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				switch path := strings.TrimPrefix(req.URL.Path, inference.ExperimentalEndpointsPrefix); {
+				case path == inspectPath:
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(`{"id":"sha256:1","tags":["` + model + `:latest"]}`))}, nil
+				case path == configurePath && req.Method == http.MethodGet:
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(bytes.NewReader(existing))}, nil
+				case path == configurePath:
+					sent = &scheduling.ConfigureRequest{}
+					if err := json.NewDecoder(req.Body).Decode(sent); err != nil {
+						t.Errorf("Failed to decode configure request: %v", err)
+					}
+					return &http.Response{StatusCode: http.StatusAccepted, Body: io.NopCloser(strings.NewReader(""))}, nil
+				default:
+					return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("{}"))}, nil
+				}
+			}).AnyTimes()
+
+			cmd := newRunCmd()
+			buf := new(bytes.Buffer)
+			cmd.SetOut(buf)
+			cmd.SetErr(buf)
+			cmd.SetArgs(append(append([]string{"--detach"}, tt.args...), model))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Unexpected error: %v\n%s", err, buf.String())
+			}
+
+			if !tt.wantConfigure {
+				if sent != nil {
+					t.Errorf("Expected no configure request for an unchanged configuration, got %+v", sent)
+				}
+				return
+			}
+			if sent == nil {
+				t.Fatal("Expected a configure request")
+			}
+			if sent.ContextSize == nil || *sent.ContextSize != contextSize {
+				t.Errorf("Expected the context size %d to be kept, got %v", contextSize, sent.ContextSize)
+			}
+			if sent.GPULayers == nil || *sent.GPULayers != 20 {
+				t.Errorf("Expected 20 GPU layers, got %v", sent.GPULayers)
+			}
+			if sent.Mode == nil || *sent.Mode != inference.BackendModeCompletion {
+				t.Errorf("Expected completion mode, got %v", sent.Mode)
+			}
+		})
+	}
+}
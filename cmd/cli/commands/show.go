@@ -34,7 +34,7 @@ func newShowCmd() *cobra.Command {
 }
 
 func showModel(modelName string, remote bool, desktopClient *desktop.Client) (string, error) {
-	model, err := desktopClient.Inspect(modelName, remote)
+	model, err := desktopClient.InspectVerbose(modelName, remote)
 	if err != nil {
 		return "", handleClientError(err, "Failed to get model "+modelName)
 	}
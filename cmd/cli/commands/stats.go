@@ -0,0 +1,83 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	"github.com/docker/model-runner/pkg/metrics"
+	"github.com/spf13/cobra"
+)
+
+func newStatsCmd() *cobra.Command {
+	var reset bool
+	var format string
+	c := &cobra.Command{
+		Use:   "stats [OPTIONS]",
+		Short: "Show token usage per model",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if reset && format != "" {
+				return fmt.Errorf("--reset flag cannot be used with --format flag")
+			}
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			if reset {
+				if err := desktopClient.ResetStats(); err != nil {
+					return handleClientError(err, "Failed to reset stats")
+				}
+				cmd.Println("Token usage stats reset")
+				return nil
+			}
+			stats, err := desktopClient.Stats()
+			if err != nil {
+				return handleClientError(err, "Failed to get stats")
+			}
+			output, err := formatStats(stats, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	c.Flags().BoolVar(&reset, "reset", false, "Reset the accumulated stats")
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatStats renders the token usage as JSON or as a table with one row per
+// model followed by the totals.
+func formatStats(stats metrics.UsageStatsResponse, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		if stats.Models == nil {
+			stats.Models = []metrics.ModelUsageStats{}
+		}
+		return formatter.ToStandardJSON(stats)
+	}
+
+	var buf bytes.Buffer
+	table := newTable(&buf)
+	table.Header([]string{"MODEL", "REQUESTS", "PROMPT TOKENS", "COMPLETION TOKENS", "TOTAL TOKENS"})
+	for _, model := range stats.Models {
+		table.Append(statsRow(stripDefaultsFromModelName(model.Model), model))
+	}
+	if len(stats.Models) > 1 {
+		table.Append(statsRow("TOTAL", stats.Total))
+	}
+	table.Render()
+	return buf.String(), nil
+}
+
+func statsRow(name string, stats metrics.ModelUsageStats) []string {
+	return []string{
+		name,
+		strconv.FormatInt(stats.Requests, 10),
+		strconv.FormatInt(stats.PromptTokens, 10),
+		strconv.FormatInt(stats.CompletionTokens, 10),
+		strconv.FormatInt(stats.TotalTokens, 10),
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
 	"github.com/docker/model-runner/cmd/cli/desktop"
 	"github.com/docker/model-runner/pkg/distribution/oci/reference"
 	"github.com/docker/model-runner/pkg/distribution/registry"
@@ -12,28 +13,50 @@ import (
 )
 
 func newTagCmd() *cobra.Command {
+	var pin, jsonFormat bool
 	c := &cobra.Command{
 		Use:   "tag SOURCE TARGET",
 		Short: "Tag a model",
 		Args:  requireExactArgs(2, "tag", "SOURCE TARGET"),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return tagModel(cmd, desktopClient, args[0], args[1])
+			return tagModel(cmd, desktopClient, args[0], args[1], pin, jsonFormat)
 		},
 		ValidArgsFunction: completion.ModelNames(getDesktopClient, 1),
 	}
+	c.Flags().BoolVar(&pin, "pin", false, "Pin the tag to the source's current digest and warn when pulls of the source resolve to another one")
+	c.Flags().BoolVar(&jsonFormat, "json", false, "Output the tagged model ID and target in JSON format")
 	return c
 }
 
-func tagModel(cmd *cobra.Command, desktopClient *desktop.Client, source, target string) error {
+// tagOutput is the JSON output of the tag command.
+type tagOutput struct {
+	SourceID string `json:"source_id"`
+	Target   string `json:"target"`
+}
+
+func tagModel(cmd *cobra.Command, desktopClient *desktop.Client, source, target string, pin, jsonFormat bool) error {
 	// Ensure tag is valid
 	tag, err := reference.NewTag(target, registry.GetDefaultRegistryOptions()...)
 	if err != nil {
 		return fmt.Errorf("invalid tag: %w", err)
 	}
 	// Make tag request with model runner client
-	if err := desktopClient.Tag(source, parseRepo(tag), tag.TagStr()); err != nil {
+	response, err := desktopClient.Tag(source, parseRepo(tag), tag.TagStr(), pin)
+	if err != nil {
 		return fmt.Errorf("failed to tag model: %w", err)
 	}
+	if jsonFormat {
+		output, err := formatter.ToStandardJSON(tagOutput{SourceID: response.SourceID, Target: response.Target})
+		if err != nil {
+			return err
+		}
+		cmd.Print(output)
+		return nil
+	}
+	if pin {
+		cmd.Printf("Model %q tagged successfully with %q, pinned to its current digest\n", source, target)
+		return nil
+	}
 	cmd.Printf("Model %q tagged successfully with %q\n", source, target)
 	return nil
 }
@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/cmd/cli/desktop"
+	mockdesktop "github.com/docker/model-runner/cmd/cli/mocks"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// TestTagJSON verifies that "tag --json" prints the ID the source resolved
+// to and the created target.
+func TestTagJSON(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	client := mockdesktop.NewMockDockerHttpClient(ctrl)
+	originalRunner, originalClient := modelRunner, desktopClient
+	modelRunner = desktop.NewContextForMock(client)
+	desktopClient = desktop.New(modelRunner)
+	t.Cleanup(func() { modelRunner, desktopClient = originalRunner, originalClient })
+
+	client.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, inference.ModelsPrefix+"/smollm2/tag", strings.TrimPrefix(req.URL.Path, inference.ExperimentalEndpointsPrefix))
+		assert.Equal(t, "v1", req.URL.Query().Get("tag"))
+		body := `{"message":"Model tagged successfully with \"ai/tagged:v1\"","source_id":"sha256:123","target":"ai/tagged:v1"}`
+		return &http.Response{StatusCode: http.StatusCreated, Body: io.NopCloser(strings.NewReader(body))}, nil
+	})
+
+	cmd := newTagCmd()
+	out := new(bytes.Buffer)
+	cmd.SetOut(out)
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--json", "smollm2", "ai/tagged:v1"})
+	require.NoError(t, cmd.Execute())
+
+	var got map[string]string
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got), out.String())
+	assert.Equal(t, map[string]string{"source_id": "sha256:123", "target": "ai/tagged:v1"}, got)
+}
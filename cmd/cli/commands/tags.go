@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/docker/model-runner/cmd/cli/commands/formatter"
+	"github.com/spf13/cobra"
+)
+
+func newTagsCmd() *cobra.Command {
+	var format string
+	c := &cobra.Command{
+		Use:   "tags [OPTIONS] REPOSITORY",
+		Short: "List the tags available for a model repository in a registry",
+		Args:  requireExactArgs(1, "tags", "REPOSITORY"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "" && format != "json" {
+				return fmt.Errorf("unsupported format %q: only \"json\" is supported", format)
+			}
+			tags, err := desktopClient.ListRemoteTags(args[0])
+			if err != nil {
+				return handleClientError(err, "Failed to list tags for "+args[0])
+			}
+			output, err := formatTags(tags, format == "json")
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(cmd.OutOrStdout(), output)
+			return nil
+		},
+		ValidArgsFunction: completion.NoComplete,
+	}
+	c.Flags().StringVar(&format, "format", "", "Format the output (json)")
+	return c
+}
+
+// formatTags renders the tags as a JSON array or as a single-column table.
+func formatTags(tags []string, jsonFormat bool) (string, error) {
+	if jsonFormat {
+		if tags == nil {
+			tags = []string{}
+		}
+		return formatter.ToStandardJSON(tags)
+	}
+
+	var buf bytes.Buffer
+	table := newTable(&buf)
+	table.Header([]string{"TAG"})
+	for _, tag := range tags {
+		table.Append([]string{tag})
+	}
+	table.Render()
+	return buf.String(), nil
+}
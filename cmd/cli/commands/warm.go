@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"time"
+
+	"github.com/docker/model-runner/cmd/cli/commands/completion"
+	"github.com/spf13/cobra"
+)
+
+func newWarmCmd() *cobra.Command {
+	var backend string
+	var timeout time.Duration
+
+	c := &cobra.Command{
+		Use:   "warm MODEL",
+		Short: "Load a model into memory ahead of use",
+		Long:  "Load a model into memory ahead of use, so that the first request to it does not wait for the model to load",
+		Args:  requireExactArgs(1, "warm", "MODEL"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := desktopClient.Warm(args[0], backend, timeout); err != nil {
+				return handleClientError(err, "Failed to warm model")
+			}
+			cmd.Printf("Model %q is loaded and ready.\n", args[0])
+			return nil
+		},
+		ValidArgsFunction: completion.ModelNames(getDesktopClient, 1),
+	}
+	c.Flags().StringVar(&backend, "backend", "", "Optional backend to load the model into")
+	c.Flags().DurationVar(&timeout, "timeout", 0, "How long to wait for the model to be ready (default: 5m)")
+	return c
+}
@@ -47,10 +47,37 @@ type ImageURL struct {
 }
 
 type OpenAIChatRequest struct {
-	Model    string              `json:"model"`
-	Messages []OpenAIChatMessage `json:"messages"`
-	Stream   bool                `json:"stream"`
-	Tools    []Tool              `json:"tools,omitempty"`
+	Model       string              `json:"model"`
+	Messages    []OpenAIChatMessage `json:"messages"`
+	Stream      bool                `json:"stream"`
+	Tools       []Tool              `json:"tools,omitempty"`
+	Temperature *float64            `json:"temperature,omitempty"`
+	TopP        *float64            `json:"top_p,omitempty"`
+	MaxTokens   *int                `json:"max_tokens,omitempty"`
+	Stop        []string            `json:"stop,omitempty"`
+	// ChatTemplate is an extension field that overrides the model's chat
+	// template for this request on backends that support it, such as vLLM.
+	ChatTemplate string `json:"chat_template,omitempty"`
+}
+
+// ChatOptions holds the optional settings of a chat request. Unset sampling
+// parameters are left to the model's defaults.
+type ChatOptions struct {
+	// SystemPrompt, if set, is sent as a system message ahead of the
+	// conversation unless the conversation already starts with one.
+	SystemPrompt string
+	Temperature  *float64
+	TopP         *float64
+	MaxTokens    *int
+	Stop         []string
+	// ChatTemplate, if set, is a Jinja chat template the backend applies in
+	// place of the model's own for this request. It is validated before the
+	// request is sent.
+	ChatTemplate string
+	// OnToolCall, if set, receives each tool call the model requests when no
+	// ClientTool is registered to execute it. When nil, such calls are
+	// printed through the output function instead.
+	OnToolCall func(ToolCall)
 }
 
 type OpenAIChatResponse struct {
@@ -64,11 +91,14 @@ type OpenAIChatResponse struct {
 			Role             string     `json:"role,omitempty"`
 			ReasoningContent string     `json:"reasoning_content,omitempty"`
 			ToolCalls        []ToolCall `json:"tool_calls,omitempty"`
+			// FunctionCall is the legacy single-call form of ToolCalls.
+			FunctionCall *ToolCallFunction `json:"function_call,omitempty"`
 		} `json:"delta"`
 		Message struct {
-			Content   string     `json:"content"`
-			Role      string     `json:"role,omitempty"`
-			ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+			Content      string            `json:"content"`
+			Role         string            `json:"role,omitempty"`
+			ToolCalls    []ToolCall        `json:"tool_calls,omitempty"`
+			FunctionCall *ToolCallFunction `json:"function_call,omitempty"`
 		} `json:"message"`
 		Index        int    `json:"index"`
 		FinishReason string `json:"finish_reason"`
@@ -0,0 +1,186 @@
+package desktop
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// ErrInvalidChatTemplate is returned for chat templates that are not
+// well-formed Jinja.
+var ErrInvalidChatTemplate = errors.New("invalid chat template")
+
+// chatTemplateBlocks maps each Jinja block statement to the statement that
+// closes it.
+var chatTemplateBlocks = map[string]string{
+	"if":         "endif",
+	"for":        "endfor",
+	"macro":      "endmacro",
+	"call":       "endcall",
+	"filter":     "endfilter",
+	"block":      "endblock",
+	"with":       "endwith",
+	"set":        "endset",
+	"generation": "endgeneration",
+}
+
+// chatTemplateBranches lists the statements that may only appear directly
+// inside the given blocks.
+var chatTemplateBranches = map[string][]string{
+	"elif": {"if"},
+	"else": {"if", "for"},
+}
+
+// ValidateChatTemplate checks that template is well-formed Jinja: every
+// expression, statement and comment is closed, and block statements are
+// properly nested. The template is not evaluated, so errors that depend on
+// the conversation, such as undefined variables, are left to the backend.
+func ValidateChatTemplate(template string) error {
+	type openBlock struct {
+		name string
+		line int
+	}
+	var blocks []openBlock
+
+	rest := template
+	line := 1
+	for {
+		start := indexTemplateTag(rest)
+		if start < 0 {
+			break
+		}
+		line += strings.Count(rest[:start], "\n")
+		kind := rest[start+1]
+		rest = rest[start+2:]
+
+		var body string
+		var ok bool
+		switch kind {
+		case '#':
+			body, rest, ok = strings.Cut(rest, "#}")
+		case '{':
+			body, rest, ok = cutTemplateTag(rest, "}}")
+		default:
+			body, rest, ok = cutTemplateTag(rest, "%}")
+		}
+		if !ok {
+			return templateError(line, "unclosed %s", templateTagKind(kind))
+		}
+		tagLine := line
+		line += strings.Count(body, "\n")
+
+		if kind == '#' {
+			continue
+		}
+		body = strings.TrimSpace(strings.Trim(body, "-+"))
+		if body == "" {
+			return templateError(tagLine, "empty %s", templateTagKind(kind))
+		}
+		if kind == '{' {
+			continue
+		}
+
+		name := strings.Fields(body)[0]
+		switch {
+		case name == "raw":
+			// Everything up to endraw is literal text.
+			idx := indexEndRaw(rest)
+			if idx < 0 {
+				return templateError(tagLine, "unclosed raw block")
+			}
+			line += strings.Count(rest[:idx], "\n")
+			rest = rest[idx:]
+			_, rest, _ = cutTemplateTag(rest, "%}")
+		case name == "set" && strings.Contains(body, "="):
+			// An assignment, not a block.
+		case chatTemplateBlocks[name] != "":
+			blocks = append(blocks, openBlock{name: name, line: tagLine})
+		case chatTemplateBranches[name] != nil:
+			if len(blocks) == 0 || !slices.Contains(chatTemplateBranches[name], blocks[len(blocks)-1].name) {
+				return templateError(tagLine, "unexpected %q outside of %s", name, strings.Join(chatTemplateBranches[name], " or "))
+			}
+		case strings.HasPrefix(name, "end"):
+			if len(blocks) == 0 {
+				return templateError(tagLine, "unexpected %q", name)
+			}
+			open := blocks[len(blocks)-1]
+			if want := chatTemplateBlocks[open.name]; name != want {
+				return templateError(tagLine, "unexpected %q, expected %q to close %q from line %d", name, want, open.name, open.line)
+			}
+			blocks = blocks[:len(blocks)-1]
+		}
+	}
+
+	if len(blocks) > 0 {
+		open := blocks[len(blocks)-1]
+		return templateError(open.line, "unclosed %q block", open.name)
+	}
+	return nil
+}
+
+// indexTemplateTag returns the index of the first expression, statement or
+// comment delimiter in s, or -1 if there is none.
+func indexTemplateTag(s string) int {
+	for i := 0; i+1 < len(s); i++ {
+		if s[i] == '{' && strings.IndexByte("{%#", s[i+1]) >= 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// cutTemplateTag splits s at the first end delimiter that is not inside a
+// string literal.
+func cutTemplateTag(s, end string) (before, after string, found bool) {
+	var quote byte
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case strings.HasPrefix(s[i:], end):
+			return s[:i], s[i+len(end):], true
+		}
+	}
+	return s, "", false
+}
+
+// indexEndRaw returns the index of the statement closing a raw block in s, or
+// -1 if there is none.
+func indexEndRaw(s string) int {
+	offset := 0
+	for {
+		idx := strings.Index(s[offset:], "{%")
+		if idx < 0 {
+			return -1
+		}
+		idx += offset
+		body, _, ok := cutTemplateTag(s[idx+2:], "%}")
+		if ok && strings.TrimSpace(strings.Trim(body, "-+")) == "endraw" {
+			return idx
+		}
+		offset = idx + 2
+	}
+}
+
+func templateTagKind(kind byte) string {
+	switch kind {
+	case '#':
+		return "comment"
+	case '{':
+		return "expression"
+	default:
+		return "statement"
+	}
+}
+
+func templateError(line int, format string, args ...any) error {
+	return fmt.Errorf("%w: line %d: %s", ErrInvalidChatTemplate, line, fmt.Sprintf(format, args...))
+}
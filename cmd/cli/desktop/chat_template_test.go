@@ -0,0 +1,58 @@
+package desktop
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const chatMLTemplate = `{%- for message in messages %}
+{{- '<|im_start|>' + message['role'] + '\n' }}
+{%- if message['content'] is string %}{{ message['content'] }}{% else %}{# multimodal #}{% endif %}
+{{- '<|im_end|>\n' }}
+{%- endfor %}
+{%- if add_generation_prompt %}{{ '<|im_start|>assistant\n' }}{% endif %}`
+
+func TestValidateChatTemplate(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantErr  string
+	}{
+		{name: "chatml", template: chatMLTemplate},
+		{name: "plain text", template: "no tags at all"},
+		{name: "single braces", template: "{ not a tag }"},
+		{name: "delimiters in strings", template: `{{ '}}' }}{% set x = "%}" %}`},
+		{name: "block set", template: `{% set greeting %}hello{% endset %}{{ greeting }}`},
+		{name: "raw block", template: `{% raw %}{% if %}{{{% endraw %}`},
+		{name: "elif and else", template: `{% if a %}1{% elif b %}2{% else %}3{% endif %}`},
+		{name: "for else", template: `{% for m in messages %}{{ m }}{% else %}none{% endfor %}`},
+		{name: "unclosed expression", template: "hi\n{{ message['content']", wantErr: "line 2: unclosed expression"},
+		{name: "unclosed statement", template: "{% if add_generation_prompt ", wantErr: "line 1: unclosed statement"},
+		{name: "unclosed comment", template: "{# note", wantErr: "line 1: unclosed comment"},
+		{name: "empty expression", template: "{{ }}", wantErr: "line 1: empty expression"},
+		{name: "unclosed block", template: "{% for m in messages %}\n{{ m }}", wantErr: `line 1: unclosed "for" block`},
+		{name: "mismatched end", template: "{% if a %}\n{% endfor %}", wantErr: `line 2: unexpected "endfor", expected "endif" to close "if" from line 1`},
+		{name: "stray end", template: "{% endif %}", wantErr: `line 1: unexpected "endif"`},
+		{name: "stray elif", template: "{% for m in messages %}{% elif a %}{% endfor %}", wantErr: `unexpected "elif" outside of if`},
+		{name: "unclosed raw", template: "{% raw %}{{", wantErr: "line 1: unclosed raw block"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChatTemplate(tt.template)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrInvalidChatTemplate) {
+				t.Fatalf("Expected ErrInvalidChatTemplate, got %v", err)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
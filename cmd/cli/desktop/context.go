@@ -125,6 +125,11 @@ type ModelRunnerContext struct {
 	tlsURLPrefix *url.URL
 	// tlsClient is the TLS-enabled HTTP client (if TLS is enabled).
 	tlsClient DockerHttpClient
+	// timeouts holds the client timeout configuration.
+	timeouts ClientTimeouts
+	// transports are the transports owned by this context, which SetTimeouts
+	// updates in place.
+	transports []*http.Transport
 }
 
 // NewContextForMock is a ModelRunnerContext constructor exposed only for the
@@ -139,6 +144,7 @@ func NewContextForMock(client DockerHttpClient) *ModelRunnerContext {
 		urlPrefix:        urlPrefix,
 		client:           client,
 		openaiPathPrefix: inference.InferencePrefix + "/v1",
+		timeouts:         DefaultClientTimeouts(),
 	}
 }
 
@@ -160,6 +166,7 @@ func NewContextForTest(endpoint string, client DockerHttpClient, kind types.Mode
 		urlPrefix:        urlPrefix,
 		client:           client,
 		openaiPathPrefix: inference.InferencePrefix + "/v1",
+		timeouts:         DefaultClientTimeouts(),
 	}, nil
 }
 
@@ -176,6 +183,7 @@ func NewContextForOpenAI(endpoint string) (*ModelRunnerContext, error) {
 		urlPrefix:        urlPrefix,
 		client:           http.DefaultClient,
 		openaiPathPrefix: "", // Empty prefix for external OpenAI-compatible endpoints
+		timeouts:         DefaultClientTimeouts(),
 	}, nil
 }
 
@@ -385,6 +393,7 @@ func DetectContext(ctx context.Context, cli *command.DockerCli, printer standalo
 	}
 
 	// Construct the HTTP client.
+	mrc := &ModelRunnerContext{timeouts: DefaultClientTimeouts()}
 	var httpClient DockerHttpClient
 	if kind == types.ModelRunnerEngineKindDesktop {
 		if useTLS {
@@ -399,15 +408,17 @@ func DetectContext(ctx context.Context, cli *command.DockerCli, printer standalo
 			return nil, fmt.Errorf("unable to create model runner client: %w", err)
 		}
 		_ = dockerClient.Close()
-		httpClient = &http.Client{
-			Transport: &http.Transport{
-				DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-					return dockerClient.Dialer()(ctx)
-				},
-			},
-		}
+		transport := mrc.newTransport(func(ctx context.Context) (net.Conn, error) {
+			return dockerClient.Dialer()(ctx)
+		})
+		// The Docker dialer does not go through a proxy.
+		transport.Proxy = nil
+		mrc.transports = append(mrc.transports, transport)
+		httpClient = &http.Client{Transport: transport}
 	} else {
-		httpClient = http.DefaultClient
+		transport := mrc.newTransport(nil)
+		mrc.transports = append(mrc.transports, transport)
+		httpClient = &http.Client{Transport: transport}
 	}
 
 	if userAgent := os.Getenv("USER_AGENT"); userAgent != "" {
@@ -422,10 +433,9 @@ func DetectContext(ctx context.Context, cli *command.DockerCli, printer standalo
 			return nil, fmt.Errorf("unable to load TLS configuration: %w", err)
 		}
 
-		tlsTransport := &http.Transport{
-			TLSClientConfig: tlsConfig,
-			Proxy:           http.ProxyFromEnvironment,
-		}
+		tlsTransport := mrc.newTransport(nil)
+		tlsTransport.TLSClientConfig = tlsConfig
+		mrc.transports = append(mrc.transports, tlsTransport)
 		tlsClient = &http.Client{
 			Transport: tlsTransport,
 		}
@@ -436,15 +446,14 @@ func DetectContext(ctx context.Context, cli *command.DockerCli, printer standalo
 	}
 
 	// Success.
-	return &ModelRunnerContext{
-		kind:             kind,
-		urlPrefix:        urlPrefix,
-		client:           httpClient,
-		openaiPathPrefix: inference.InferencePrefix + "/v1",
-		useTLS:           useTLS,
-		tlsURLPrefix:     tlsURLPrefix,
-		tlsClient:        tlsClient,
-	}, nil
+	mrc.kind = kind
+	mrc.urlPrefix = urlPrefix
+	mrc.client = httpClient
+	mrc.openaiPathPrefix = inference.InferencePrefix + "/v1"
+	mrc.useTLS = useTLS
+	mrc.tlsURLPrefix = tlsURLPrefix
+	mrc.tlsClient = tlsClient
+	return mrc, nil
 }
 
 // EngineKind returns the Docker engine kind associated with the model runner.
@@ -463,12 +472,13 @@ func (c *ModelRunnerContext) URL(path string) string {
 }
 
 // Client returns an HTTP client appropriate for accessing the model runner.
-// If TLS is enabled, returns the TLS client.
+// If TLS is enabled, returns the TLS client. The client enforces the
+// configured ClientTimeouts.
 func (c *ModelRunnerContext) Client() DockerHttpClient {
 	if c.useTLS && c.tlsClient != nil {
-		return c.tlsClient
+		return c.withTimeouts(c.tlsClient)
 	}
-	return c.client
+	return c.withTimeouts(c.client)
 }
 
 // UseTLS returns whether TLS is enabled for this context.
@@ -501,7 +511,7 @@ func (c *ModelRunnerContext) buildURL(prefix *url.URL, path string) string {
 
 // TLSClient returns the TLS HTTP client, or nil if TLS is not enabled.
 func (c *ModelRunnerContext) TLSClient() DockerHttpClient {
-	return c.tlsClient
+	return c.withTimeouts(c.tlsClient)
 }
 
 // OpenAIPathPrefix returns the path prefix for OpenAI-compatible endpoints.
@@ -7,9 +7,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -18,10 +20,14 @@ import (
 	"github.com/docker/model-runner/pkg/distribution/distribution"
 	"github.com/docker/model-runner/pkg/inference"
 	dmrm "github.com/docker/model-runner/pkg/inference/models"
+	"github.com/docker/model-runner/pkg/inference/platform"
 	"github.com/docker/model-runner/pkg/inference/scheduling"
+	"github.com/docker/model-runner/pkg/metrics"
+	"github.com/docker/model-runner/pkg/tracing"
 	"github.com/fatih/color"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -33,6 +39,12 @@ const (
 var (
 	ErrNotFound           = errors.New("model not found")
 	ErrServiceUnavailable = errors.New("service unavailable")
+	// ErrNoPushInProgress is returned when cancelling the push of a model
+	// that is not being pushed.
+	ErrNoPushInProgress = errors.New("no push in progress")
+	// ErrChatInterrupted is returned when a chat is cancelled while the
+	// response is streaming. It wraps the context's error.
+	ErrChatInterrupted = errors.New("chat interrupted")
 )
 
 // ClientTool is a tool that can be registered with the chat client.
@@ -111,16 +123,29 @@ func (c *Client) Status() Status {
 }
 
 func (c *Client) Pull(model string, printer standalone.StatusPrinter) (string, bool, error) {
+	return c.PullWithOptions(model, printer, PullOptions{})
+}
+
+// PullOptions configures PullWithOptions.
+type PullOptions struct {
+	// RawReference sends the model reference to the registry verbatim,
+	// without adding the default organization or tag.
+	RawReference bool
+}
+
+func (c *Client) PullWithOptions(model string, printer standalone.StatusPrinter, opts PullOptions) (string, bool, error) {
 	// Check if this is a Hugging Face model and if HF_TOKEN is set
 	var hfToken string
 	if distribution.IsHuggingFaceReference(strings.ToLower(model)) {
 		hfToken = os.Getenv("HF_TOKEN")
 	}
 
-	return c.withRetries("download", 3, printer, func(attempt int) (string, bool, error, bool) {
+	ctx, span := tracing.Start(context.Background(), tracing.SpanPull, model, trace.SpanKindClient)
+	message, shown, err := c.withRetries("download", 3, printer, func(attempt int) (string, bool, error, bool) {
 		jsonData, err := json.Marshal(dmrm.ModelCreateRequest{
-			From:        model,
-			BearerToken: hfToken,
+			From:         model,
+			BearerToken:  hfToken,
+			RawReference: opts.RawReference,
 		})
 		if err != nil {
 			// Marshaling errors are not retryable
@@ -128,7 +153,8 @@ func (c *Client) Pull(model string, printer standalone.StatusPrinter) (string, b
 		}
 
 		createPath := inference.ModelsPrefix + "/create"
-		resp, err := c.doRequest(
+		resp, err := c.doStreamingRequest(
+			ctx,
 			http.MethodPost,
 			createPath,
 			bytes.NewReader(jsonData),
@@ -180,6 +206,8 @@ func (c *Client) Pull(model string, printer standalone.StatusPrinter) (string, b
 
 		return message, shown, nil, false
 	})
+	span.End(err)
+	return message, shown, err
 }
 
 // isRetryableError determines if an error is retryable (network-related)
@@ -263,7 +291,8 @@ func (c *Client) Push(model string, printer standalone.StatusPrinter) (string, b
 		hfToken = os.Getenv("HF_TOKEN")
 	}
 
-	return c.withRetries("push", 3, printer, func(attempt int) (string, bool, error, bool) {
+	ctx, span := tracing.Start(context.Background(), tracing.SpanPush, model, trace.SpanKindClient)
+	message, shown, err := c.withRetries("push", 3, printer, func(attempt int) (string, bool, error, bool) {
 		pushPath := inference.ModelsPrefix + "/" + model + "/push"
 		var body io.Reader
 		if hfToken != "" {
@@ -275,7 +304,8 @@ func (c *Client) Push(model string, printer standalone.StatusPrinter) (string, b
 			}
 			body = bytes.NewReader(jsonData)
 		}
-		resp, err := c.doRequest(
+		resp, err := c.doStreamingRequest(
+			ctx,
 			http.MethodPost,
 			pushPath,
 			body,
@@ -317,11 +347,38 @@ func (c *Client) Push(model string, printer standalone.StatusPrinter) (string, b
 
 		return message, shown, nil, false
 	})
+	span.End(err)
+	return message, shown, err
 }
 
 func (c *Client) List() ([]dmrm.Model, error) {
-	modelsRoute := inference.ModelsPrefix
-	body, err := c.listRaw(modelsRoute, "")
+	return c.ListByName("")
+}
+
+// ListByName lists the local models with a tag starting with prefix. The
+// filtering happens server-side; an empty prefix lists every model.
+func (c *Client) ListByName(prefix string) ([]dmrm.Model, error) {
+	return c.ListWithOptions(ListOptions{NamePrefix: prefix})
+}
+
+// ListOptions selects the models returned by ListWithOptions. The filtering
+// happens server-side.
+type ListOptions struct {
+	// NamePrefix, if set, keeps the models with a tag starting with it.
+	NamePrefix string
+	// Runnable keeps the models whose format an installed backend can run.
+	Runnable bool
+}
+
+func (c *Client) ListWithOptions(opts ListOptions) ([]dmrm.Model, error) {
+	query := url.Values{}
+	if opts.NamePrefix != "" {
+		query.Set("name", opts.NamePrefix)
+	}
+	if opts.Runnable {
+		query.Set("runnable", "true")
+	}
+	body, err := c.listRawWithQuery(inference.ModelsPrefix, "", query)
 	if err != nil {
 		return []dmrm.Model{}, err
 	}
@@ -350,7 +407,35 @@ func (c *Client) ListOpenAI() (dmrm.OpenAIModelList, error) {
 }
 
 func (c *Client) Inspect(model string, remote bool) (dmrm.Model, error) {
-	rawResponse, err := c.listRawWithQuery(fmt.Sprintf("%s/%s", inference.ModelsPrefix, model), model, remote)
+	return c.inspect(model, remote, false)
+}
+
+// IsModelInStore reports whether model is in the local store.
+func (c *Client) IsModelInStore(model string) (bool, error) {
+	if _, err := c.Inspect(model, false); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// InspectVerbose is like Inspect but also returns the full format metadata
+// (e.g. the GGUF key/value map), which is omitted by default.
+func (c *Client) InspectVerbose(model string, remote bool) (dmrm.Model, error) {
+	return c.inspect(model, remote, true)
+}
+
+func (c *Client) inspect(model string, remote, verbose bool) (dmrm.Model, error) {
+	query := url.Values{}
+	if remote {
+		query.Set("remote", "true")
+	}
+	if verbose {
+		query.Set("verbose", "true")
+	}
+	rawResponse, err := c.listRawWithQuery(fmt.Sprintf("%s/%s", inference.ModelsPrefix, model), model, query)
 	if err != nil {
 		return dmrm.Model{}, err
 	}
@@ -362,6 +447,93 @@ func (c *Client) Inspect(model string, remote bool) (dmrm.Model, error) {
 	return modelInspect, nil
 }
 
+// Manifest returns the raw manifest of a local model as stored.
+func (c *Client) Manifest(model string) ([]byte, error) {
+	return c.listRaw(fmt.Sprintf("%s/%s/manifest", inference.ModelsPrefix, model), model)
+}
+
+// ListRemoteTags returns the tags available in the remote repository of repo.
+func (c *Client) ListRemoteTags(repo string) ([]string, error) {
+	tagsPath := fmt.Sprintf("%s/%s/tags?remote=true", inference.ModelsPrefix, repo)
+	resp, err := c.doRequest(http.MethodGet, tagsPath, nil)
+	if err != nil {
+		return nil, c.handleQueryError(err, tagsPath)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, errors.Wrap(ErrNotFound, repo)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to list tags: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var tags []string
+	if err := json.Unmarshal(body, &tags); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return tags, nil
+}
+
+// Diff compares two local models.
+func (c *Client) Diff(a, b string) (dmrm.ModelDiff, error) {
+	diffPath := inference.ModelsPrefix + "/diff?" + url.Values{"a": {a}, "b": {b}}.Encode()
+	resp, err := c.doRequest(http.MethodGet, diffPath, nil)
+	if err != nil {
+		return dmrm.ModelDiff{}, c.handleQueryError(err, diffPath)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return dmrm.ModelDiff{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return dmrm.ModelDiff{}, errors.Wrap(ErrNotFound, strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return dmrm.ModelDiff{}, fmt.Errorf("failed to diff models: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var diff dmrm.ModelDiff
+	if err := json.Unmarshal(body, &diff); err != nil {
+		return dmrm.ModelDiff{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return diff, nil
+}
+
+// CheckUpdate reports whether the registry has a newer model for a local
+// model tag.
+func (c *Client) CheckUpdate(model string) (dmrm.ModelUpdateCheck, error) {
+	checkPath := fmt.Sprintf("%s/%s/update-check", inference.ModelsPrefix, model)
+	resp, err := c.doRequest(http.MethodGet, checkPath, nil)
+	if err != nil {
+		return dmrm.ModelUpdateCheck{}, c.handleQueryError(err, checkPath)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return dmrm.ModelUpdateCheck{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return dmrm.ModelUpdateCheck{}, errors.Wrap(ErrNotFound, strings.TrimSpace(string(body)))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return dmrm.ModelUpdateCheck{}, fmt.Errorf("failed to check for updates: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var check dmrm.ModelUpdateCheck
+	if err := json.Unmarshal(body, &check); err != nil {
+		return dmrm.ModelUpdateCheck{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return check, nil
+}
+
 func (c *Client) InspectOpenAI(model string) (dmrm.OpenAIModel, error) {
 	modelsRoute := c.modelRunner.OpenAIPathPrefix() + "/models"
 	rawResponse, err := c.listRaw(fmt.Sprintf("%s/%s", modelsRoute, model), model)
@@ -376,12 +548,12 @@ func (c *Client) InspectOpenAI(model string) (dmrm.OpenAIModel, error) {
 }
 
 func (c *Client) listRaw(route string, model string) ([]byte, error) {
-	return c.listRawWithQuery(route, model, false)
+	return c.listRawWithQuery(route, model, nil)
 }
 
-func (c *Client) listRawWithQuery(route string, model string, remote bool) ([]byte, error) {
-	if remote {
-		route += "?remote=true"
+func (c *Client) listRawWithQuery(route string, model string, query url.Values) ([]byte, error) {
+	if len(query) > 0 {
+		route += "?" + query.Encode()
 	}
 
 	resp, err := c.doRequest(http.MethodGet, route, nil)
@@ -416,6 +588,52 @@ type accumulatedToolCall struct {
 	arguments strings.Builder
 }
 
+// toolCallAccumulator assembles tool calls from streamed fragments, keyed by
+// the index each fragment carries. The ID and name usually arrive in the first
+// fragment of a call while its arguments are split across several.
+type toolCallAccumulator map[int]*accumulatedToolCall
+
+// add merges a tool call fragment into the call at its index.
+func (a toolCallAccumulator) add(tc ToolCall) {
+	atc, ok := a[tc.Index]
+	if !ok {
+		atc = &accumulatedToolCall{}
+		a[tc.Index] = atc
+	}
+	if tc.ID != "" {
+		atc.id = tc.ID
+	}
+	if tc.Function.Name != "" {
+		atc.name = tc.Function.Name
+	}
+	atc.arguments.WriteString(tc.Function.Arguments)
+}
+
+// addFunctionCall merges a fragment of a legacy function_call, which always
+// describes a single call at index 0.
+func (a toolCallAccumulator) addFunctionCall(fc ToolCallFunction) {
+	a.add(ToolCall{Function: fc})
+}
+
+// calls returns the assembled tool calls ordered by index.
+func (a toolCallAccumulator) calls() []ToolCall {
+	indexes := slices.Sorted(maps.Keys(a))
+	calls := make([]ToolCall, 0, len(indexes))
+	for _, idx := range indexes {
+		atc := a[idx]
+		calls = append(calls, ToolCall{
+			ID:    atc.id,
+			Type:  "function",
+			Index: idx,
+			Function: ToolCallFunction{
+				Name:      atc.name,
+				Arguments: atc.arguments.String(),
+			},
+		})
+	}
+	return calls
+}
+
 // Preload loads a model into memory without running inference.
 // The model stays loaded for the idle timeout period.
 func (c *Client) Preload(ctx context.Context, model string) error {
@@ -430,7 +648,9 @@ func (c *Client) Preload(ctx context.Context, model string) error {
 	}
 
 	completionsPath := c.modelRunner.OpenAIPathPrefix() + "/chat/completions"
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.modelRunner.URL(completionsPath), bytes.NewReader(jsonData))
+	// Preloading waits for the model to load, so it is exempt from response
+	// timeouts like a streaming request.
+	req, err := http.NewRequestWithContext(WithStreaming(ctx), http.MethodPost, c.modelRunner.URL(completionsPath), bytes.NewReader(jsonData))
 	if err != nil {
 		return fmt.Errorf("error creating request: %w", err)
 	}
@@ -460,18 +680,41 @@ func (c *Client) Preload(ctx context.Context, model string) error {
 // When tools are provided, the function implements an agentic loop: if the model requests a tool call,
 // the tool is executed and the result is sent back until the model produces a final response.
 func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conversationHistory []OpenAIChatMessage, prompt string, imageURLs []string, outputFunc func(string), shouldUseMarkdown bool, tools ...ClientTool) (string, error) {
+	return c.ChatWithOptions(ctx, model, conversationHistory, prompt, imageURLs, ChatOptions{}, outputFunc, shouldUseMarkdown, tools...)
+}
+
+// ChatWithOptions is like ChatWithMessagesContext, but also applies the given
+// system prompt and sampling parameters to the request.
+func (c *Client) ChatWithOptions(ctx context.Context, model string, conversationHistory []OpenAIChatMessage, prompt string, imageURLs []string, opts ChatOptions, outputFunc func(string), shouldUseMarkdown bool, tools ...ClientTool) (_ string, err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanChat, model, trace.SpanKindClient)
+	var sent int64
+	defer func() {
+		span.SetBytes(sent)
+		span.End(err)
+	}()
+
+	if opts.ChatTemplate != "" {
+		if err := ValidateChatTemplate(opts.ChatTemplate); err != nil {
+			return "", err
+		}
+	}
+
 	// Build the current user message content - either simple string or multimodal array
 	var messageContent interface{}
 	if len(imageURLs) > 0 {
 		// Multimodal message with images
 		contentParts := make([]ContentPart, 0, len(imageURLs)+1)
 
-		// Add all images first
+		// Add all images first, inlining local files as data URLs
 		for _, imageURL := range imageURLs {
+			resolvedURL, err := resolveImageURL(imageURL)
+			if err != nil {
+				return "", err
+			}
 			contentParts = append(contentParts, ContentPart{
 				Type: "image_url",
 				ImageURL: &ImageURL{
-					URL: imageURL,
+					URL: resolvedURL,
 				},
 			})
 		}
@@ -490,8 +733,14 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 		messageContent = prompt
 	}
 
-	// Build messages array with conversation history plus current message
-	messages := make([]OpenAIChatMessage, 0, len(conversationHistory)+1)
+	// Build messages array with the system prompt, conversation history and current message
+	messages := make([]OpenAIChatMessage, 0, len(conversationHistory)+2)
+	if opts.SystemPrompt != "" && (len(conversationHistory) == 0 || conversationHistory[0].Role != "system") {
+		messages = append(messages, OpenAIChatMessage{
+			Role:    "system",
+			Content: opts.SystemPrompt,
+		})
+	}
 	messages = append(messages, conversationHistory...)
 	messages = append(messages, OpenAIChatMessage{
 		Role:    "user",
@@ -536,25 +785,58 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 
 	var assistantResponse strings.Builder
 
+	printUsage := func(label string) {
+		if finalUsage == nil {
+			return
+		}
+		usageInfo := fmt.Sprintf("\n\n%s: %d prompt + %d completion = %d total",
+			label,
+			finalUsage.PromptTokens,
+			finalUsage.CompletionTokens,
+			finalUsage.TotalTokens)
+
+		usageFmt := color.New(color.FgHiBlack)
+		if !shouldUseMarkdown {
+			usageFmt.DisableColor()
+		}
+		outputFunc(usageFmt.Sprint(usageInfo))
+	}
+
+	// interrupted ends output that was cut off by cancellation on a new line,
+	// noting the usage reported so far, and returns ErrChatInterrupted.
+	interrupted := func(printed bool) (string, error) {
+		if printed {
+			printUsage("Token usage (partial)")
+		}
+		outputFunc("\n")
+		return assistantResponse.String(), fmt.Errorf("%w: %w", ErrChatInterrupted, ctx.Err())
+	}
+
 	// Agentic loop: iterate until the model produces a stop response (no more tool calls).
 	// toolCallIterations counts rounds where the model requested tool calls; it is capped
 	// at maxToolCallIterations to prevent infinite loops with poorly-behaved models.
 	toolCallIterations := 0
 	for {
 		reqBody := OpenAIChatRequest{
-			Model:    model,
-			Messages: messages,
-			Stream:   true,
-			Tools:    toolSchemas,
+			Model:        model,
+			Messages:     messages,
+			Stream:       true,
+			Tools:        toolSchemas,
+			Temperature:  opts.Temperature,
+			TopP:         opts.TopP,
+			MaxTokens:    opts.MaxTokens,
+			Stop:         opts.Stop,
+			ChatTemplate: opts.ChatTemplate,
 		}
 
 		jsonData, err := json.Marshal(reqBody)
 		if err != nil {
 			return assistantResponse.String(), fmt.Errorf("error marshaling request: %w", err)
 		}
+		sent += int64(len(jsonData))
 
 		resp, err := c.doRequestWithAuthContext(
-			ctx,
+			WithStreaming(ctx),
 			http.MethodPost,
 			completionsPath,
 			bytes.NewReader(jsonData),
@@ -599,7 +881,7 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 		printerState := chatPrinterNone
 
 		// Accumulated tool calls for this iteration, keyed by index.
-		pendingToolCalls := make(map[int]*accumulatedToolCall)
+		pendingToolCalls := make(toolCallAccumulator)
 		var finishReason string
 
 		// Use a buffered reader so we can consume server-sent progress
@@ -673,9 +955,10 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 				}
 				finishReason = nonStreamResp.Choices[0].FinishReason
 				for _, tc := range nonStreamResp.Choices[0].Message.ToolCalls {
-					atc := &accumulatedToolCall{id: tc.ID, name: tc.Function.Name}
-					atc.arguments.WriteString(tc.Function.Arguments)
-					pendingToolCalls[tc.Index] = atc
+					pendingToolCalls.add(tc)
+				}
+				if fc := nonStreamResp.Choices[0].Message.FunctionCall; fc != nil {
+					pendingToolCalls.addFunctionCall(*fc)
 				}
 			}
 
@@ -691,7 +974,7 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 				select {
 				case <-ctx.Done():
 					resp.Body.Close()
-					return assistantResponse.String(), ctx.Err()
+					return interrupted(printerState != chatPrinterNone)
 				default:
 				}
 
@@ -729,18 +1012,10 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 
 					// Accumulate tool call fragments.
 					for _, tc := range choice.Delta.ToolCalls {
-						atc, ok := pendingToolCalls[tc.Index]
-						if !ok {
-							atc = &accumulatedToolCall{}
-							pendingToolCalls[tc.Index] = atc
-						}
-						if tc.ID != "" {
-							atc.id = tc.ID
-						}
-						if tc.Function.Name != "" {
-							atc.name = tc.Function.Name
-						}
-						atc.arguments.WriteString(tc.Function.Arguments)
+						pendingToolCalls.add(tc)
+					}
+					if choice.Delta.FunctionCall != nil {
+						pendingToolCalls.addFunctionCall(*choice.Delta.FunctionCall)
 					}
 
 					if choice.Delta.ReasoningContent != "" {
@@ -777,32 +1052,38 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 
 			resp.Body.Close()
 			if err := scanner.Err(); err != nil {
+				if ctx.Err() != nil {
+					return interrupted(printerState != chatPrinterNone)
+				}
 				return assistantResponse.String(), fmt.Errorf("error reading response stream: %w", err)
 			}
 		}
 
+		// Without client tools nothing can execute the requested calls, so
+		// hand them to the caller and finish.
+		if len(toolMap) == 0 && len(pendingToolCalls) > 0 {
+			for _, tc := range pendingToolCalls.calls() {
+				if opts.OnToolCall != nil {
+					opts.OnToolCall(tc)
+					continue
+				}
+				if printerState != chatPrinterNone {
+					outputFunc("\n\n")
+				}
+				printerState = chatPrinterContent
+				outputFunc(fmt.Sprintf("Tool call: %s(%s)", tc.Function.Name, tc.Function.Arguments))
+			}
+			break
+		}
+
 		// If the model requested tool calls, execute them and loop.
-		if finishReason == "tool_calls" && len(pendingToolCalls) > 0 {
+		if (finishReason == "tool_calls" || finishReason == "function_call") && len(pendingToolCalls) > 0 {
 			toolCallIterations++
 			if toolCallIterations >= maxToolCallIterations {
 				return assistantResponse.String(), fmt.Errorf("tool call loop exceeded %d iterations", maxToolCallIterations)
 			}
 			// Build assistant message with the tool calls.
-			toolCallSlice := make([]ToolCall, 0, len(pendingToolCalls))
-			for idx := 0; idx < len(pendingToolCalls); idx++ {
-				atc, ok := pendingToolCalls[idx]
-				if !ok {
-					continue
-				}
-				toolCallSlice = append(toolCallSlice, ToolCall{
-					ID:   atc.id,
-					Type: "function",
-					Function: ToolCallFunction{
-						Name:      atc.name,
-						Arguments: atc.arguments.String(),
-					},
-				})
-			}
+			toolCallSlice := pendingToolCalls.calls()
 			messages = append(messages, OpenAIChatMessage{
 				Role:      "assistant",
 				ToolCalls: toolCallSlice,
@@ -841,18 +1122,7 @@ func (c *Client) ChatWithMessagesContext(ctx context.Context, model string, conv
 		break
 	}
 
-	if finalUsage != nil {
-		usageInfo := fmt.Sprintf("\n\nToken usage: %d prompt + %d completion = %d total",
-			finalUsage.PromptTokens,
-			finalUsage.CompletionTokens,
-			finalUsage.TotalTokens)
-
-		usageFmt := color.New(color.FgHiBlack)
-		if !shouldUseMarkdown {
-			usageFmt.DisableColor()
-		}
-		outputFunc(usageFmt.Sprint(usageInfo))
-	}
+	printUsage("Token usage")
 
 	return assistantResponse.String(), nil
 }
@@ -948,6 +1218,8 @@ type BackendStatus struct {
 	InUse       bool                 `json:"in_use,omitempty"`
 	Loading     bool                 `json:"loading,omitempty"`
 	KeepAlive   *inference.KeepAlive `json:"keep_alive,omitempty"`
+	Healthy     bool                 `json:"healthy"`
+	LastError   string               `json:"last_error,omitempty"`
 }
 
 func (c *Client) PS() ([]BackendStatus, error) {
@@ -998,6 +1270,90 @@ func (c *Client) DF() (DiskUsage, error) {
 	return df, nil
 }
 
+// PlatformInfo returns the GPUs detected by the model runner and which
+// inference backends are supported on its host.
+func (c *Client) PlatformInfo() (platform.Info, error) {
+	platformPath := inference.InferencePrefix + "/platform"
+	resp, err := c.doRequest(http.MethodGet, platformPath, nil)
+	if err != nil {
+		return platform.Info{}, c.handleQueryError(err, platformPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return platform.Info{}, fmt.Errorf("failed to get platform info: %s", resp.Status)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var info platform.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return platform.Info{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return info, nil
+}
+
+// DebugConfig returns the effective configuration of the model runner, keyed
+// by environment variable, with credentials redacted.
+func (c *Client) DebugConfig() (map[string]string, error) {
+	configPath := inference.InferencePrefix + "/debug/config"
+	resp, err := c.doRequest(http.MethodGet, configPath, nil)
+	if err != nil {
+		return nil, c.handleQueryError(err, configPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to get configuration: %s", resp.Status)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var config map[string]string
+	if err := json.Unmarshal(body, &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return config, nil
+}
+
+// Stats returns the token usage accumulated by the model runner.
+func (c *Client) Stats() (metrics.UsageStatsResponse, error) {
+	statsPath := inference.InferencePrefix + "/stats"
+	resp, err := c.doRequest(http.MethodGet, statsPath, nil)
+	if err != nil {
+		return metrics.UsageStatsResponse{}, c.handleQueryError(err, statsPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return metrics.UsageStatsResponse{}, fmt.Errorf("failed to get stats: %s", resp.Status)
+	}
+
+	body, _ := io.ReadAll(resp.Body)
+	var stats metrics.UsageStatsResponse
+	if err := json.Unmarshal(body, &stats); err != nil {
+		return metrics.UsageStatsResponse{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+
+	return stats, nil
+}
+
+// ResetStats discards the token usage accumulated by the model runner.
+func (c *Client) ResetStats() error {
+	statsPath := inference.InferencePrefix + "/stats"
+	resp, err := c.doRequest(http.MethodDelete, statsPath, nil)
+	if err != nil {
+		return c.handleQueryError(err, statsPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to reset stats: %s", resp.Status)
+	}
+
+	return nil
+}
+
 // UnloadRequest to be imported from docker/model-runner when https://github.com/docker/model-runner/pull/46 is merged.
 type UnloadRequest struct {
 	All     bool     `json:"all"`
@@ -1041,6 +1397,32 @@ func (c *Client) Unload(req UnloadRequest) (UnloadResponse, error) {
 	return unloadResp, nil
 }
 
+// Warm loads model into a backend ahead of any inference request, returning
+// once the model is ready to serve requests. An empty backend selects one
+// automatically, and a zero timeout uses the model runner's default.
+func (c *Client) Warm(model, backend string, timeout time.Duration) error {
+	query := url.Values{"model": []string{model}}
+	if backend != "" {
+		query.Set("backend", backend)
+	}
+	if timeout > 0 {
+		query.Set("timeout", timeout.String())
+	}
+	warmPath := inference.InferencePrefix + "/warm?" + query.Encode()
+
+	resp, err := c.doRequest(http.MethodPost, warmPath, nil)
+	if err != nil {
+		return c.handleQueryError(err, warmPath)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("warming model failed with status %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
 func (c *Client) ShowConfigs(modelFilter string) ([]scheduling.ModelConfigEntry, error) {
 	configureBackendPath := inference.InferencePrefix + "/_configure"
 	if modelFilter != "" {
@@ -1162,6 +1544,7 @@ func (c *Client) Requests(modelFilter string, streaming bool, includeExisting bo
 	}
 
 	if streaming {
+		req = req.WithContext(WithStreaming(req.Context()))
 		req.Header.Set("Accept", "text/event-stream")
 		req.Header.Set("Cache-Control", "no-cache")
 	} else {
@@ -1199,6 +1582,33 @@ func (c *Client) Requests(modelFilter string, streaming bool, includeExisting bo
 	return resp.Body, cancel, nil
 }
 
+// Events streams backend lifecycle events (see scheduling.BackendEvent) as
+// server-sent events. It returns the stream and a function that closes it.
+func (c *Client) Events() (io.ReadCloser, func(), error) {
+	path := c.modelRunner.URL(inference.InferencePrefix + "/events")
+	req, err := http.NewRequestWithContext(WithStreaming(context.Background()), http.MethodGet, path, http.NoBody)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("User-Agent", "docker-model-cli/"+Version)
+
+	resp, err := c.modelRunner.Client().Do(req)
+	if err != nil {
+		return nil, nil, c.handleQueryError(fmt.Errorf("failed to connect to stream: %w", err), path)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, nil, fmt.Errorf("stream request failed with status: %d", resp.StatusCode)
+	}
+
+	cancel := func() {
+		resp.Body.Close()
+	}
+	return resp.Body, cancel, nil
+}
+
 func (c *Client) Purge() error {
 	purgePath := inference.ModelsPrefix + "/purge"
 	resp, err := c.doRequest(http.MethodDelete, purgePath, nil)
@@ -1215,6 +1625,54 @@ func (c *Client) Purge() error {
 	return nil
 }
 
+// Prune removes every model without tags and reports what was removed.
+func (c *Client) Prune() (distribution.PruneModelsResponse, error) {
+	prunePath := inference.ModelsPrefix + "/prune"
+	resp, err := c.doRequest(http.MethodPost, prunePath, nil)
+	if err != nil {
+		return distribution.PruneModelsResponse{}, c.handleQueryError(err, prunePath)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return distribution.PruneModelsResponse{}, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return distribution.PruneModelsResponse{}, fmt.Errorf("pruning failed with status %s: %s", resp.Status, string(body))
+	}
+
+	var pruneResponse distribution.PruneModelsResponse
+	if err := json.Unmarshal(body, &pruneResponse); err != nil {
+		return distribution.PruneModelsResponse{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return pruneResponse, nil
+}
+
+// CancelPush cancels any push of model in progress. The cancelled push fails
+// with an error once its current upload has been aborted.
+func (c *Client) CancelPush(model string) error {
+	cancelPath := inference.ModelsPrefix + "/" + model + "/cancel-push"
+	resp, err := c.doRequest(http.MethodPost, cancelPath, nil)
+	if err != nil {
+		return c.handleQueryError(err, cancelPath)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return nil
+	case http.StatusNotFound:
+		return errors.Wrap(ErrNoPushInProgress, model)
+	default:
+		return fmt.Errorf("canceling push failed with status %s: %s", resp.Status, string(body))
+	}
+}
+
 // Logs streams the DMR log files from the server's /logs endpoint
 // into out. follow enables real-time tailing; noEngines excludes the
 // engine log.
@@ -1229,6 +1687,9 @@ func (c *Client) Logs(
 ) error {
 	path := "/logs?follow=" + strconv.FormatBool(follow) +
 		"&no-engines=" + strconv.FormatBool(noEngines)
+	if follow {
+		ctx = WithStreaming(ctx)
+	}
 	resp, err := c.doRequestWithAuthContext(ctx, http.MethodGet, path, nil)
 	if err != nil {
 		return fmt.Errorf("logs request failed: %w", err)
@@ -1252,6 +1713,12 @@ func (c *Client) doRequest(method, path string, body io.Reader) (*http.Response,
 	return c.doRequestWithAuth(method, path, body)
 }
 
+// doStreamingRequest is like doRequest but marks the request as streaming, so
+// that it is only subject to the stream idle timeout.
+func (c *Client) doStreamingRequest(ctx context.Context, method, path string, body io.Reader) (*http.Response, error) {
+	return c.doRequestWithAuthContext(WithStreaming(ctx), method, path, body)
+}
+
 // doRequestWithAuth is a helper function that performs HTTP requests with optional authentication
 func (c *Client) doRequestWithAuth(method, path string, body io.Reader) (*http.Response, error) {
 	return c.doRequestWithAuthContext(context.Background(), method, path, body)
@@ -1267,6 +1734,7 @@ func (c *Client) doRequestWithAuthContext(ctx context.Context, method, path stri
 	}
 
 	req.Header.Set("User-Agent", "docker-model-cli/"+Version)
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := c.modelRunner.Client().Do(req)
 	if err != nil {
@@ -1288,7 +1756,7 @@ func (c *Client) handleQueryError(err error, path string) error {
 	return fmt.Errorf("error querying %s: %w", path, err)
 }
 
-func (c *Client) Tag(source, targetRepo, targetTag string) error {
+func (c *Client) Tag(source, targetRepo, targetTag string, pin bool) (dmrm.ModelTagResponse, error) {
 	// Construct the URL with query parameters using the normalized source
 	tagPath := fmt.Sprintf("%s/%s/tag?repo=%s&tag=%s",
 		inference.ModelsPrefix,
@@ -1296,32 +1764,47 @@ func (c *Client) Tag(source, targetRepo, targetTag string) error {
 		targetRepo,
 		targetTag,
 	)
+	if pin {
+		tagPath += "&pin=true"
+	}
 
 	resp, err := c.doRequest(http.MethodPost, tagPath, nil)
 	if err != nil {
-		return c.handleQueryError(err, tagPath)
+		return dmrm.ModelTagResponse{}, c.handleQueryError(err, tagPath)
 	}
 	defer resp.Body.Close()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		return dmrm.ModelTagResponse{}, fmt.Errorf("failed to read response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return fmt.Errorf("tagging failed with status %s: %s", resp.Status, string(body))
+		return dmrm.ModelTagResponse{}, fmt.Errorf("tagging failed with status %s: %s", resp.Status, string(body))
 	}
 
-	return nil
+	var tagResponse dmrm.ModelTagResponse
+	if err := json.Unmarshal(body, &tagResponse); err != nil {
+		return dmrm.ModelTagResponse{}, fmt.Errorf("failed to unmarshal response body: %w", err)
+	}
+	return tagResponse, nil
 }
 
-func (c *Client) LoadModel(ctx context.Context, r io.Reader) error {
+func (c *Client) LoadModel(ctx context.Context, r io.Reader) (err error) {
+	ctx, span := tracing.Start(ctx, tracing.SpanLoad, "", trace.SpanKindClient)
+	counter := tracing.NewCountingReader(r)
+	defer func() {
+		span.SetBytes(counter.N())
+		span.End(err)
+	}()
+
 	loadPath := fmt.Sprintf("%s/load", inference.ModelsPrefix)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.modelRunner.URL(loadPath), r)
+	req, err := http.NewRequestWithContext(WithStreaming(ctx), http.MethodPost, c.modelRunner.URL(loadPath), counter)
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/x-tar")
 	req.Header.Set("User-Agent", "docker-model-cli/"+Version)
+	tracing.Inject(ctx, req.Header)
 
 	resp, err := c.modelRunner.Client().Do(req)
 	if err != nil {
@@ -1338,7 +1821,7 @@ func (c *Client) LoadModel(ctx context.Context, r io.Reader) error {
 
 func (c *Client) ExportModel(ctx context.Context, model string) (io.ReadCloser, error) {
 	exportPath := fmt.Sprintf("%s/%s/export", inference.ModelsPrefix, model)
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.modelRunner.URL(exportPath), http.NoBody)
+	req, err := http.NewRequestWithContext(WithStreaming(ctx), http.MethodGet, c.modelRunner.URL(exportPath), http.NoBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -1364,6 +1847,7 @@ func (c *Client) ExportModel(ctx context.Context, model string) (io.ReadCloser,
 
 type RepackageOptions struct {
 	ContextSize *uint64 `json:"context_size,omitempty"`
+	Force       bool    `json:"force,omitempty"`
 }
 
 func (c *Client) RepackageModel(ctx context.Context, source, target string, opts RepackageOptions) error {
@@ -1372,9 +1856,11 @@ func (c *Client) RepackageModel(ctx context.Context, source, target string, opts
 	reqBody := struct {
 		Target      string  `json:"target"`
 		ContextSize *uint64 `json:"context_size,omitempty"`
+		Force       bool    `json:"force,omitempty"`
 	}{
 		Target:      target,
 		ContextSize: opts.ContextSize,
+		Force:       opts.Force,
 	}
 
 	jsonData, err := json.Marshal(reqBody)
@@ -1391,6 +1877,10 @@ func (c *Client) RepackageModel(ctx context.Context, source, target string, opts
 	if resp.StatusCode == http.StatusNotFound {
 		return errors.Wrap(ErrNotFound, source)
 	}
+	if resp.StatusCode == http.StatusConflict {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s", strings.TrimSpace(string(body)))
+	}
 	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
 		return fmt.Errorf("repackage failed with status %s: %s", resp.Status, string(body))
@@ -3,16 +3,31 @@ package desktop
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	mockdesktop "github.com/docker/model-runner/cmd/cli/mocks"
 	"github.com/docker/model-runner/pkg/distribution/distribution"
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/tracing"
+	"github.com/moby/moby/api/types/jsonstream"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/mock/gomock"
 )
 
@@ -334,6 +349,112 @@ func TestChatWithMessagesContext_JinjaFallbackAfterToolCall(t *testing.T) {
 	assert.Equal(t, "Here is the news.", output)
 }
 
+// toolCallDeltaSSEResponse is an SSE stream in which two tool calls arrive as
+// interleaved fragments, with the arguments split across chunks.
+const toolCallDeltaSSEResponse = `data: {"choices":[{"delta":{"content":"Checking."},"finish_reason":null,"index":0}]}` + "\n\n" +
+	`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"id":"call_a","type":"function","function":{"name":"get_weather","arguments":""}}]},"finish_reason":null,"index":0}]}` + "\n\n" +
+	`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"{\"city\":"}}]},"finish_reason":null,"index":0}]}` + "\n\n" +
+	`data: {"choices":[{"delta":{"tool_calls":[{"index":1,"id":"call_b","type":"function","function":{"name":"get_time","arguments":"{}"}}]},"finish_reason":null,"index":0}]}` + "\n\n" +
+	`data: {"choices":[{"delta":{"tool_calls":[{"index":0,"function":{"arguments":"\"Paris\"}"}}]},"finish_reason":null,"index":0}]}` + "\n\n" +
+	`data: {"choices":[{"delta":{},"finish_reason":"tool_calls","index":0}]}` + "\n\n" +
+	"data: [DONE]\n\n"
+
+// TestChatWithOptions_ToolCallDeltas verifies that streamed tool call
+// fragments are assembled and handed to OnToolCall when no client tool is
+// registered, alongside the regular content.
+func TestChatWithOptions_ToolCallDeltas(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	// Only one request: the calls are reported, not executed and sent back.
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       io.NopCloser(bytes.NewBufferString(toolCallDeltaSSEResponse)),
+	}, nil).Times(1)
+
+	var calls []ToolCall
+	var output string
+	resp, err := client.ChatWithOptions(
+		t.Context(), "qwen3", nil, "weather?", nil,
+		ChatOptions{OnToolCall: func(tc ToolCall) { calls = append(calls, tc) }},
+		func(s string) { output += s },
+		false,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Checking.", resp)
+	assert.Equal(t, "Checking.", output)
+	assert.Equal(t, []ToolCall{
+		{ID: "call_a", Type: "function", Index: 0, Function: ToolCallFunction{Name: "get_weather", Arguments: `{"city":"Paris"}`}},
+		{ID: "call_b", Type: "function", Index: 1, Function: ToolCallFunction{Name: "get_time", Arguments: "{}"}},
+	}, calls)
+}
+
+// TestChatWithOptions_FunctionCallDeltasPrinted verifies that legacy
+// function_call fragments are assembled and printed when no OnToolCall
+// callback is set.
+func TestChatWithOptions_FunctionCallDeltasPrinted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	stream := `data: {"choices":[{"delta":{"function_call":{"name":"get_weather","arguments":"{\"city\""}},"finish_reason":null,"index":0}]}` + "\n\n" +
+		`data: {"choices":[{"delta":{"function_call":{"arguments":":\"Oslo\"}"}},"finish_reason":null,"index":0}]}` + "\n\n" +
+		`data: {"choices":[{"delta":{},"finish_reason":"function_call","index":0}]}` + "\n\n" +
+		"data: [DONE]\n\n"
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+		Body:       io.NopCloser(bytes.NewBufferString(stream)),
+	}, nil).Times(1)
+
+	var output string
+	resp, err := client.ChatWithOptions(
+		t.Context(), "qwen3", nil, "weather?", nil, ChatOptions{},
+		func(s string) { output += s },
+		false,
+	)
+	require.NoError(t, err)
+	assert.Empty(t, resp)
+	assert.Equal(t, `Tool call: get_weather({"city":"Oslo"})`, output)
+}
+
+// TestChatWithOptions_ChatTemplate verifies that a chat template is sent as
+// the chat_template extension field, and that an invalid one is rejected
+// before any request is made.
+func TestChatWithOptions_ChatTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	const template = "{% for m in messages %}{{ m['content'] }}{% endfor %}"
+	var sent OpenAIChatRequest
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		if err := json.NewDecoder(req.Body).Decode(&sent); err != nil {
+			t.Errorf("Failed to decode request: %v", err)
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(bytes.NewBufferString(sseResponse("Hello!"))),
+		}, nil
+	}).Times(1)
+
+	_, err := client.ChatWithOptions(t.Context(), "gemma3", nil, "hi", nil, ChatOptions{ChatTemplate: template}, func(string) {}, false)
+	require.NoError(t, err)
+	assert.Equal(t, template, sent.ChatTemplate)
+
+	_, err = client.ChatWithOptions(t.Context(), "gemma3", nil, "hi", nil, ChatOptions{ChatTemplate: "{% if true %}"}, func(string) {}, false)
+	assert.ErrorIs(t, err, ErrInvalidChatTemplate)
+}
+
 // TestChatWithMessagesContext_Non500ErrorNotRetried verifies that non-Jinja 500 errors
 // are not silently retried without tools.
 func TestChatWithMessagesContext_Non500ErrorNotRetried(t *testing.T) {
@@ -453,3 +574,476 @@ func TestDisplayProgressMixedContent(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, "Model pulled successfully", msg)
 }
+
+func TestDisplayProgressThroughput(t *testing.T) {
+	body := `{"type":"progress","total":30000000,"layer":{"id":"sha256:a","size":20000000,"current":5000000,"bytes_per_sec":1000000},"mode":"pull"}` + "\n" +
+		`{"type":"progress","total":30000000,"layer":{"id":"sha256:b","size":10000000,"current":5000000,"bytes_per_sec":1500000},"mode":"pull"}` + "\n" +
+		`{"type":"success","message":"Model pulled successfully"}` + "\n"
+	var lines []string
+	printer := NewSimplePrinter(func(s string) { lines = append(lines, strings.TrimSpace(s)) })
+	_, shown, err := DisplayProgress(strings.NewReader(body), printer)
+	require.NoError(t, err)
+	assert.True(t, shown)
+	require.Len(t, lines, 2)
+	assert.Equal(t, "Downloaded 5.00MB of 30.00MB (1.00MB/s, 25s left)", lines[0])
+	// Throughput is summed across layers still downloading.
+	assert.Equal(t, "Downloaded 10.00MB of 30.00MB (2.50MB/s, 8s left)", lines[1])
+}
+
+func TestWriteDockerProgressThroughput(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeDockerProgress(&buf, &oci.ProgressMessage{
+		Type:  oci.TypeProgress,
+		Layer: oci.ProgressLayer{ID: "sha256:0123456789abcdef", Size: 100, Current: 10, BytesPerSec: 1},
+		Mode:  oci.ModePull,
+	})
+	require.NoError(t, err)
+
+	var msg jsonstream.Message
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &msg))
+	require.NotNil(t, msg.Progress)
+	// Start is back-dated by current/rate so the rendered ETA matches.
+	assert.InDelta(t, time.Now().Add(-10*time.Second).Unix(), msg.Progress.Start, 1)
+}
+
+func TestWarm(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.True(t, strings.HasSuffix(req.URL.Path, inference.InferencePrefix+"/warm"), req.URL.Path)
+		assert.Equal(t, "ai/smollm2", req.URL.Query().Get("model"))
+		assert.Equal(t, "llama.cpp", req.URL.Query().Get("backend"))
+		assert.Equal(t, "30s", req.URL.Query().Get("timeout"))
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	require.NoError(t, client.Warm("ai/smollm2", "llama.cpp", 30*time.Second))
+
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusGatewayTimeout,
+		Status:     "504 Gateway Timeout",
+		Body:       io.NopCloser(strings.NewReader("model was not ready within 5m0s\n")),
+	}, nil)
+	err := client.Warm("ai/smollm2", "", 0)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "model was not ready within 5m0s")
+}
+
+func TestPrune(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.True(t, strings.HasSuffix(req.URL.Path, inference.ModelsPrefix+"/prune"), req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"Deleted":["sha256:abc"],"SpaceReclaimed":1024}`)),
+		}, nil
+	})
+	resp, err := client.Prune()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"sha256:abc"}, resp.Deleted)
+	assert.Equal(t, int64(1024), resp.SpaceReclaimed)
+
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusInternalServerError,
+		Status:     "500 Internal Server Error",
+		Body:       io.NopCloser(strings.NewReader("error while pruning models\n")),
+	}, nil)
+	_, err = client.Prune()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "error while pruning models")
+}
+
+func TestCancelPush(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.True(t, strings.HasSuffix(req.URL.Path, inference.ModelsPrefix+"/myorg/model:v1/cancel-push"), req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`{"message":"Push of \"myorg/model:v1\" canceled"}`)),
+		}, nil
+	})
+	require.NoError(t, client.CancelPush("myorg/model:v1"))
+
+	mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Body:       io.NopCloser(strings.NewReader("no push in progress\n")),
+	}, nil)
+	err := client.CancelPush("myorg/model:v1")
+	require.ErrorIs(t, err, ErrNoPushInProgress)
+}
+
+func TestStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodGet, req.Method)
+		assert.True(t, strings.HasSuffix(req.URL.Path, inference.InferencePrefix+"/stats"), req.URL.Path)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(strings.NewReader(`{"models":[{"model":"ai/smollm2","requests":2,"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}],` +
+				`"total":{"model":"","requests":2,"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`)),
+		}, nil
+	})
+	stats, err := client.Stats()
+	require.NoError(t, err)
+	require.Len(t, stats.Models, 1)
+	assert.Equal(t, "ai/smollm2", stats.Models[0].Model)
+	assert.Equal(t, int64(2), stats.Models[0].Requests)
+	assert.Equal(t, int64(15), stats.Total.TotalTokens)
+
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		assert.Equal(t, http.MethodDelete, req.Method)
+		assert.True(t, strings.HasSuffix(req.URL.Path, inference.InferencePrefix+"/stats"), req.URL.Path)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(""))}, nil
+	})
+	require.NoError(t, client.ResetStats())
+}
+
+// TestChatWithMessagesContext_ImageURLs verifies that local image paths are
+// inlined as data URLs while data URLs and remote URLs are sent unchanged.
+func TestChatWithMessagesContext_ImageURLs(t *testing.T) {
+	pngData := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 16)...)
+	pngPath := filepath.Join(t.TempDir(), "image.png")
+	require.NoError(t, os.WriteFile(pngPath, pngData, 0o644))
+	pngDataURL := "data:image/png;base64," + base64.StdEncoding.EncodeToString(pngData)
+	const httpURL = "https://example.com/image.jpg"
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+	client := New(NewContextForMock(mockClient))
+
+	var sentURLs []string
+	mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+		var body struct {
+			Messages []struct {
+				Content []ContentPart `json:"content"`
+			} `json:"messages"`
+		}
+		require.NoError(t, json.NewDecoder(req.Body).Decode(&body))
+		require.Len(t, body.Messages, 1)
+		for _, part := range body.Messages[0].Content {
+			if part.ImageURL != nil {
+				sentURLs = append(sentURLs, part.ImageURL.URL)
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+			Body:       io.NopCloser(bytes.NewBufferString(sseResponse("Two images."))),
+		}, nil
+	})
+
+	_, err := client.ChatWithMessagesContext(
+		t.Context(), "gemma3", nil, "describe these", []string{pngPath, pngDataURL, httpURL},
+		func(string) {}, false,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{pngDataURL, pngDataURL, httpURL}, sentURLs)
+}
+
+// TestChatWithMessagesContext_UnsupportedImage verifies that images of
+// unsupported types are rejected before any request is sent.
+func TestChatWithMessagesContext_UnsupportedImage(t *testing.T) {
+	textPath := filepath.Join(t.TempDir(), "notes.txt")
+	require.NoError(t, os.WriteFile(textPath, []byte("not an image"), 0o644))
+
+	tests := []struct {
+		name        string
+		imageURL    string
+		unsupported bool
+	}{
+		{name: "local text file", imageURL: textPath, unsupported: true},
+		{name: "gif data URL", imageURL: "data:image/gif;base64,R0lGODlhAQABAAAAACw=", unsupported: true},
+		{name: "non-base64 data URL", imageURL: "data:image/png,raw"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			client := New(NewContextForMock(mockdesktop.NewMockDockerHttpClient(ctrl)))
+			_, err := client.ChatWithMessagesContext(
+				t.Context(), "gemma3", nil, "describe this", []string{tt.imageURL},
+				func(string) {}, false,
+			)
+			require.Error(t, err)
+			assert.Equal(t, tt.unsupported, errors.Is(err, ErrUnsupportedImageType), err)
+		})
+	}
+}
+
+// TestChatWithOptions verifies that the system prompt and sampling parameters
+// are carried in the request body, and that unset options are omitted.
+func TestChatWithOptions(t *testing.T) {
+	temperature, topP, maxTokens := 0.2, 0.9, 64
+	history := []OpenAIChatMessage{
+		{Role: "user", Content: "earlier"},
+		{Role: "assistant", Content: "reply"},
+	}
+
+	tests := []struct {
+		name           string
+		history        []OpenAIChatMessage
+		opts           ChatOptions
+		expectedRoles  []string
+		expectedSystem string
+		expectedJSON   map[string]any
+	}{
+		{
+			name:          "no options",
+			expectedRoles: []string{"user"},
+		},
+		{
+			name: "system prompt and sampling params",
+			opts: ChatOptions{
+				SystemPrompt: "Be terse.",
+				Temperature:  &temperature,
+				TopP:         &topP,
+				MaxTokens:    &maxTokens,
+				Stop:         []string{"END"},
+			},
+			expectedRoles:  []string{"system", "user"},
+			expectedSystem: "Be terse.",
+			expectedJSON: map[string]any{
+				"temperature": 0.2,
+				"top_p":       0.9,
+				"max_tokens":  float64(64),
+				"stop":        []any{"END"},
+			},
+		},
+		{
+			name:           "system prompt ahead of history",
+			history:        history,
+			opts:           ChatOptions{SystemPrompt: "Be terse."},
+			expectedRoles:  []string{"system", "user", "assistant", "user"},
+			expectedSystem: "Be terse.",
+		},
+		{
+			name:           "history already has a system message",
+			history:        append([]OpenAIChatMessage{{Role: "system", Content: "Be verbose."}}, history...),
+			opts:           ChatOptions{SystemPrompt: "Be terse."},
+			expectedRoles:  []string{"system", "user", "assistant", "user"},
+			expectedSystem: "Be verbose.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+			client := New(NewContextForMock(mockClient))
+
+			var body map[string]any
+			var roles []string
+			var system string
+			mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				data, err := io.ReadAll(req.Body)
+				require.NoError(t, err)
+				require.NoError(t, json.Unmarshal(data, &body))
+				var chatReq OpenAIChatRequest
+				require.NoError(t, json.Unmarshal(data, &chatReq))
+				for _, msg := range chatReq.Messages {
+					roles = append(roles, msg.Role)
+					if msg.Role == "system" {
+						system, _ = msg.Content.(string)
+					}
+				}
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+					Body:       io.NopCloser(bytes.NewBufferString(sseResponse("ok"))),
+				}, nil
+			})
+
+			_, err := client.ChatWithOptions(t.Context(), "gemma3", tt.history, "hi", nil, tt.opts, func(string) {}, false)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.expectedRoles, roles)
+			assert.Equal(t, tt.expectedSystem, system)
+			for _, key := range []string{"temperature", "top_p", "max_tokens", "stop"} {
+				expected, ok := tt.expectedJSON[key]
+				if !ok {
+					assert.NotContains(t, body, key)
+					continue
+				}
+				assert.Equal(t, expected, body[key], key)
+			}
+		})
+	}
+}
+
+// TestChatWithMessagesContext_Interrupted verifies that cancelling the context
+// mid-stream ends the output on a new line, notes the usage reported so far,
+// and returns ErrChatInterrupted.
+func TestChatWithMessagesContext_Interrupted(t *testing.T) {
+	chunk := func(content string) string {
+		return `data: {"choices":[{"delta":{"content":"` + content + `"},"index":0}],"usage":{"prompt_tokens":4,"completion_tokens":2,"total_tokens":6}}` + "\n\n"
+	}
+
+	tests := []struct {
+		name          string
+		cancelAfter   int
+		expectedText  string
+		expectedUsage bool
+	}{
+		{name: "after two chunks", cancelAfter: 2, expectedText: "Hello, wor", expectedUsage: true},
+		{name: "before any output", cancelAfter: 0, expectedText: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+			client := New(NewContextForMock(mockClient))
+
+			mockClient.EXPECT().Do(gomock.Any()).Return(&http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+				Body:       io.NopCloser(strings.NewReader(chunk("Hello, ") + chunk("wor") + chunk("ld") + chunk("!") + "data: [DONE]\n\n")),
+			}, nil)
+
+			ctx, cancel := context.WithCancel(t.Context())
+			defer cancel()
+			if tt.cancelAfter == 0 {
+				cancel()
+			}
+
+			var output strings.Builder
+			chunks := 0
+			resp, err := client.ChatWithMessagesContext(ctx, "gemma3", nil, "hi", nil, func(s string) {
+				output.WriteString(s)
+				if chunks++; chunks == tt.cancelAfter {
+					cancel()
+				}
+			}, false)
+
+			require.ErrorIs(t, err, ErrChatInterrupted)
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, tt.expectedText, resp)
+			assert.True(t, strings.HasPrefix(output.String(), tt.expectedText), output.String())
+			assert.True(t, strings.HasSuffix(output.String(), "\n"), "output should end with a newline: %q", output.String())
+			assert.Equal(t, tt.expectedUsage, strings.Contains(output.String(), "Token usage (partial): 4 prompt + 2 completion = 6 total"), output.String())
+		})
+	}
+}
+
+func TestClientSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	tests := []struct {
+		name      string
+		span      string
+		model     string
+		bytes     bool
+		operation func(client *Client) error
+		response  func() *http.Response
+	}{
+		{
+			name:  "pull",
+			span:  tracing.SpanPull,
+			model: "ai/smollm2",
+			operation: func(client *Client) error {
+				_, _, err := client.Pull("ai/smollm2", NewSimplePrinter(func(string) {}))
+				return err
+			},
+			response: func() *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(bytes.NewBufferString(`{"type":"success","message":"Model pulled successfully"}`)),
+				}
+			},
+		},
+		{
+			name:  "chat",
+			span:  tracing.SpanChat,
+			model: "gemma3",
+			bytes: true,
+			operation: func(client *Client) error {
+				_, err := client.ChatWithOptions(t.Context(), "gemma3", nil, "hi", nil, ChatOptions{}, func(string) {}, false)
+				return err
+			},
+			response: func() *http.Response {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": []string{"text/event-stream"}},
+					Body:       io.NopCloser(bytes.NewBufferString(sseResponse("Hello!"))),
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exporter.Reset()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockClient := mockdesktop.NewMockDockerHttpClient(ctrl)
+			client := New(NewContextForMock(mockClient))
+
+			var propagated trace.SpanContext
+			var sent int64
+			mockClient.EXPECT().Do(gomock.Any()).DoAndReturn(func(req *http.Request) (*http.Response, error) {
+				ctx := propagation.TraceContext{}.Extract(context.Background(), propagation.HeaderCarrier(req.Header))
+				propagated = trace.SpanContextFromContext(ctx)
+				n, _ := io.Copy(io.Discard, req.Body)
+				sent = n
+				return tt.response(), nil
+			}).Times(1)
+
+			require.NoError(t, tt.operation(client))
+
+			spans := exporter.GetSpans()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tt.span, span.Name)
+			assert.Equal(t, trace.SpanKindClient, span.SpanKind)
+			assert.True(t, propagated.IsValid(), "traceparent header not propagated")
+			assert.Equal(t, span.SpanContext.SpanID(), propagated.SpanID())
+
+			attrs := make(map[attribute.Key]attribute.Value)
+			for _, kv := range span.Attributes {
+				attrs[kv.Key] = kv.Value
+			}
+			assert.Equal(t, tt.model, attrs[tracing.AttrModel].AsString())
+			assert.Contains(t, attrs, tracing.AttrDuration)
+			if tt.bytes {
+				assert.Equal(t, sent, attrs[tracing.AttrBytes].AsInt64())
+			}
+		})
+	}
+}
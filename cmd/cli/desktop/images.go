@@ -0,0 +1,85 @@
+package desktop
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+)
+
+// maxImageFileBytes is the largest local image that is inlined into a chat
+// request.
+const maxImageFileBytes int64 = 100 * 1024 * 1024
+
+// supportedImageTypes are the MIME types accepted for chat images.
+var supportedImageTypes = []string{"image/jpeg", "image/png", "image/webp"}
+
+// ErrUnsupportedImageType is returned for chat images that are not JPEG, PNG
+// or WebP.
+var ErrUnsupportedImageType = errors.New("unsupported image type")
+
+// resolveImageURL returns the URL to send for a chat image. Remote URLs are
+// returned unchanged, data URLs are validated, and anything else is treated
+// as a local file path and inlined as a base64 data URL.
+func resolveImageURL(imageURL string) (string, error) {
+	lower := strings.ToLower(imageURL)
+	switch {
+	case strings.HasPrefix(lower, "http://"), strings.HasPrefix(lower, "https://"):
+		return imageURL, nil
+	case strings.HasPrefix(lower, "data:"):
+		if err := validateImageDataURL(imageURL); err != nil {
+			return "", err
+		}
+		return imageURL, nil
+	default:
+		return EncodeImageFile(imageURL)
+	}
+}
+
+// validateImageDataURL checks that dataURL is a base64 data URL holding an
+// image of a supported type.
+func validateImageDataURL(dataURL string) error {
+	header, payload, ok := strings.Cut(dataURL[len("data:"):], ",")
+	if !ok {
+		return errors.New("invalid image data URL: missing data")
+	}
+	mimeType, isBase64 := strings.CutSuffix(header, ";base64")
+	if !isBase64 {
+		return errors.New("invalid image data URL: data must be base64 encoded")
+	}
+	if !slices.Contains(supportedImageTypes, strings.ToLower(mimeType)) {
+		return fmt.Errorf("%w %q in data URL (supported: %s)", ErrUnsupportedImageType, mimeType, strings.Join(supportedImageTypes, ", "))
+	}
+	if _, err := base64.StdEncoding.DecodeString(payload); err != nil {
+		return fmt.Errorf("invalid image data URL: %w", err)
+	}
+	return nil
+}
+
+// EncodeImageFile reads the image at path and encodes it as a base64 data URL
+// with its detected MIME type.
+func EncodeImageFile(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("reading image: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("reading image: %s is a directory", path)
+	}
+	if info.Size() > maxImageFileBytes {
+		return "", fmt.Errorf("image %s exceeds maximum size (%d MB)", path, maxImageFileBytes/(1024*1024))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading image: %w", err)
+	}
+	mimeType := http.DetectContentType(data)
+	if !slices.Contains(supportedImageTypes, mimeType) {
+		return "", fmt.Errorf("%w %q for %s (supported: %s)", ErrUnsupportedImageType, mimeType, path, strings.Join(supportedImageTypes, ", "))
+	}
+	return "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(data), nil
+}
@@ -8,6 +8,7 @@ import (
 	"html"
 	"io"
 	"strings"
+	"time"
 
 	"github.com/docker/go-units"
 	"github.com/docker/model-runner/cmd/cli/pkg/standalone"
@@ -115,6 +116,7 @@ func displayProgressSimple(body io.Reader, printer standalone.StatusPrinter) (st
 	scanner := bufio.NewScanner(body)
 	var current uint64
 	layerProgress := make(map[string]uint64)
+	layerRates := make(map[string]float64)
 	var finalMessage string
 	progressShown := false // Track if we actually showed any progress
 	// nonJSONBytes collects raw unparseable lines for error reporting.
@@ -139,16 +141,33 @@ func displayProgressSimple(body io.Reader, printer standalone.StatusPrinter) (st
 			progressShown = true // We're showing actual progress
 			layerID := progressMsg.Layer.ID
 			layerProgress[layerID] = progressMsg.Layer.Current
+			if progressMsg.Layer.Current < progressMsg.Layer.Size {
+				layerRates[layerID] = progressMsg.Layer.BytesPerSec
+			} else {
+				delete(layerRates, layerID)
+			}
 
-			// Sum all layer progress
+			// Sum all layer progress and throughput
 			current = uint64(0)
 			for _, layerCurrent := range layerProgress {
 				current += layerCurrent
 			}
+			var rate float64
+			for _, layerRate := range layerRates {
+				rate += layerRate
+			}
 
-			printer.Println(fmt.Sprintf("Downloaded %s of %s",
-				units.CustomSize("%.2f%s", float64(current), 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"}),
-				units.CustomSize("%.2f%s", float64(progressMsg.Total), 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"})))
+			line := fmt.Sprintf("Downloaded %s of %s",
+				formatProgressSize(float64(current)),
+				formatProgressSize(float64(progressMsg.Total)))
+			if rate > 0 {
+				line += fmt.Sprintf(" (%s/s", formatProgressSize(rate))
+				if progressMsg.Total > current {
+					line += ", " + formatETA(float64(progressMsg.Total-current)/rate) + " left"
+				}
+				line += ")"
+			}
+			printer.Println(line)
 
 		case oci.TypeSuccess:
 			finalMessage = progressMsg.Message
@@ -176,6 +195,16 @@ func displayProgressSimple(body io.Reader, printer standalone.StatusPrinter) (st
 	return finalMessage, progressShown, nil
 }
 
+// formatProgressSize formats a byte count using decimal units.
+func formatProgressSize(size float64) string {
+	return units.CustomSize("%.2f%s", size, 1000.0, []string{"B", "kB", "MB", "GB", "TB", "PB", "EB", "ZB", "YB"})
+}
+
+// formatETA formats a remaining duration given in seconds.
+func formatETA(seconds float64) string {
+	return time.Duration(seconds * float64(time.Second)).Round(time.Second).String()
+}
+
 // Status strings used in progress display. All are padded to
 // progressStatusWidth so that progress bars line up at the same column.
 const (
@@ -222,6 +251,12 @@ func writeDockerProgress(w io.Writer, msg *oci.ProgressMessage) error {
 			Current: int64(msg.Layer.Current),
 			Total:   int64(msg.Layer.Size),
 		}
+		if msg.Layer.BytesPerSec > 0 {
+			// jsonmessage estimates the time left from the average rate since
+			// Start, so back-date Start to match the reported throughput.
+			elapsed := time.Duration(float64(msg.Layer.Current) / msg.Layer.BytesPerSec * float64(time.Second))
+			progressDetail.Start = time.Now().Add(-elapsed).Unix()
+		}
 	} else if msg.Layer.Current >= msg.Layer.Size && msg.Layer.Size > 0 {
 		if isPush {
 			status = progressStatusPushComplete
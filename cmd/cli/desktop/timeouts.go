@@ -0,0 +1,262 @@
+package desktop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// ClientTimeouts configures the timeouts and keepalive behaviour of the HTTP
+// client used to reach the model runner. A zero duration disables the
+// corresponding timeout.
+type ClientTimeouts struct {
+	// DialTimeout bounds the time taken to establish a connection.
+	DialTimeout time.Duration
+	// KeepAlive is the TCP keepalive period for new connections.
+	KeepAlive time.Duration
+	// IdleConnTimeout is how long idle pooled connections are kept open.
+	IdleConnTimeout time.Duration
+	// ResponseHeaderTimeout bounds the time spent waiting for response headers
+	// of non-streaming requests.
+	ResponseHeaderTimeout time.Duration
+	// RequestTimeout bounds the total duration of non-streaming requests,
+	// including reading the response body.
+	RequestTimeout time.Duration
+	// StreamIdleTimeout bounds the time between successive reads of a
+	// streaming response body. Streaming requests are never subject to
+	// ResponseHeaderTimeout or RequestTimeout, since chats, pulls and pushes
+	// can legitimately take a long time.
+	StreamIdleTimeout time.Duration
+}
+
+// DefaultClientTimeouts returns the timeouts used unless SetTimeouts is
+// called, as the CLI does with ClientTimeoutsFromEnv. They match net/http's
+// default transport and impose no per-request timeouts.
+func DefaultClientTimeouts() ClientTimeouts {
+	return ClientTimeouts{
+		DialTimeout:     30 * time.Second,
+		KeepAlive:       30 * time.Second,
+		IdleConnTimeout: 90 * time.Second,
+	}
+}
+
+// ClientTimeoutsFromEnv returns DefaultClientTimeouts, with each timeout
+// overridden by its environment variable if set: MODEL_RUNNER_DIAL_TIMEOUT,
+// MODEL_RUNNER_KEEPALIVE, MODEL_RUNNER_IDLE_CONN_TIMEOUT,
+// MODEL_RUNNER_RESPONSE_HEADER_TIMEOUT, MODEL_RUNNER_REQUEST_TIMEOUT and
+// MODEL_RUNNER_STREAM_IDLE_TIMEOUT. Values are durations such as "30s"; "0"
+// disables the timeout.
+func ClientTimeoutsFromEnv() (ClientTimeouts, error) {
+	timeouts := DefaultClientTimeouts()
+	for _, v := range []struct {
+		env   string
+		value *time.Duration
+	}{
+		{"MODEL_RUNNER_DIAL_TIMEOUT", &timeouts.DialTimeout},
+		{"MODEL_RUNNER_KEEPALIVE", &timeouts.KeepAlive},
+		{"MODEL_RUNNER_IDLE_CONN_TIMEOUT", &timeouts.IdleConnTimeout},
+		{"MODEL_RUNNER_RESPONSE_HEADER_TIMEOUT", &timeouts.ResponseHeaderTimeout},
+		{"MODEL_RUNNER_REQUEST_TIMEOUT", &timeouts.RequestTimeout},
+		{"MODEL_RUNNER_STREAM_IDLE_TIMEOUT", &timeouts.StreamIdleTimeout},
+	} {
+		s, ok := os.LookupEnv(v.env)
+		if !ok || s == "" {
+			continue
+		}
+		d, err := time.ParseDuration(s)
+		if err != nil || d < 0 {
+			return ClientTimeouts{}, fmt.Errorf("invalid %s %q: must be a non-negative duration such as \"30s\"", v.env, s)
+		}
+		*v.value = d
+	}
+	return timeouts, nil
+}
+
+// hasRequestTimeouts reports whether any per-request timeout is enabled.
+func (t ClientTimeouts) hasRequestTimeouts() bool {
+	return t.ResponseHeaderTimeout > 0 || t.RequestTimeout > 0 || t.StreamIdleTimeout > 0
+}
+
+// errStreamIdle is the cause attached to streaming requests cancelled because
+// no data arrived within the idle timeout.
+var errStreamIdle = errors.New("stream idle timeout exceeded")
+
+type streamingKey struct{}
+
+// WithStreaming marks requests made with the returned context as streaming,
+// exempting them from response timeouts in favour of the stream idle timeout.
+func WithStreaming(ctx context.Context) context.Context {
+	return context.WithValue(ctx, streamingKey{}, true)
+}
+
+func isStreaming(ctx context.Context) bool {
+	streaming, _ := ctx.Value(streamingKey{}).(bool)
+	return streaming
+}
+
+// newTransport returns a transport that dials with dial, or with a net.Dialer
+// honouring the context's timeouts when dial is nil. Timeouts are read at dial
+// time so that SetTimeouts also applies to contexts created earlier.
+func (c *ModelRunnerContext) newTransport(dial func(ctx context.Context) (net.Conn, error)) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.IdleConnTimeout = c.timeouts.IdleConnTimeout
+	transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if dial == nil {
+			dialer := &net.Dialer{
+				Timeout:   c.timeouts.DialTimeout,
+				KeepAlive: c.timeouts.KeepAlive,
+			}
+			return dialer.DialContext(ctx, network, addr)
+		}
+		if c.timeouts.DialTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeouts.DialTimeout)
+			defer cancel()
+		}
+		return dial(ctx)
+	}
+	return transport
+}
+
+// SetTimeouts configures the client timeouts. It must be called before the
+// context is used to issue requests.
+func (c *ModelRunnerContext) SetTimeouts(timeouts ClientTimeouts) {
+	c.timeouts = timeouts
+	for _, transport := range c.transports {
+		transport.IdleConnTimeout = timeouts.IdleConnTimeout
+	}
+}
+
+// Timeouts returns the configured client timeouts.
+func (c *ModelRunnerContext) Timeouts() ClientTimeouts {
+	return c.timeouts
+}
+
+// withTimeouts wraps client so that it enforces the per-request timeouts, if
+// any are configured.
+func (c *ModelRunnerContext) withTimeouts(client DockerHttpClient) DockerHttpClient {
+	if client == nil || !c.timeouts.hasRequestTimeouts() {
+		return client
+	}
+	return &timeoutClient{client: client, timeouts: c.timeouts}
+}
+
+// timeoutClient enforces ClientTimeouts on top of another DockerHttpClient
+// using request contexts, so it works regardless of the underlying transport.
+type timeoutClient struct {
+	client   DockerHttpClient
+	timeouts ClientTimeouts
+}
+
+func (t *timeoutClient) Do(req *http.Request) (*http.Response, error) {
+	if isStreaming(req.Context()) {
+		return t.doStreaming(req)
+	}
+	return t.doBounded(req)
+}
+
+// doBounded performs a non-streaming request subject to the response header
+// and overall request timeouts.
+func (t *timeoutClient) doBounded(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancelCause(req.Context())
+	if t.timeouts.RequestTimeout > 0 {
+		var cancelTimeout context.CancelFunc
+		ctx, cancelTimeout = context.WithTimeoutCause(ctx, t.timeouts.RequestTimeout,
+			fmt.Errorf("request timeout of %s exceeded", t.timeouts.RequestTimeout))
+		parentCancel := cancel
+		cancel = func(cause error) {
+			cancelTimeout()
+			parentCancel(cause)
+		}
+	}
+
+	var headerTimer *time.Timer
+	if t.timeouts.ResponseHeaderTimeout > 0 {
+		headerTimer = time.AfterFunc(t.timeouts.ResponseHeaderTimeout, func() {
+			cancel(fmt.Errorf("response header timeout of %s exceeded", t.timeouts.ResponseHeaderTimeout))
+		})
+	}
+
+	resp, err := t.client.Do(req.WithContext(ctx))
+	if headerTimer != nil {
+		headerTimer.Stop()
+	}
+	if err != nil {
+		cancel(nil)
+		return nil, withCause(ctx, err)
+	}
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, ctx: ctx, cancel: func() { cancel(nil) }}
+	return resp, nil
+}
+
+// doStreaming performs a streaming request. Once headers arrive, the body is
+// cancelled if no data is read from it within the stream idle timeout.
+func (t *timeoutClient) doStreaming(req *http.Request) (*http.Response, error) {
+	if t.timeouts.StreamIdleTimeout <= 0 {
+		return t.client.Do(req)
+	}
+
+	ctx, cancel := context.WithCancelCause(req.Context())
+	resp, err := t.client.Do(req.WithContext(ctx))
+	if err != nil {
+		cancel(nil)
+		return nil, err
+	}
+	idle := t.timeouts.StreamIdleTimeout
+	body := &cancelOnCloseBody{ReadCloser: resp.Body, ctx: ctx, cancel: func() { cancel(nil) }}
+	body.idleTimer = time.AfterFunc(idle, func() {
+		cancel(fmt.Errorf("%w (%s)", errStreamIdle, idle))
+	})
+	body.idle = idle
+	resp.Body = body
+	return resp, nil
+}
+
+// cancelOnCloseBody releases the request context once the body is closed and,
+// for streaming responses, resets the idle timer on every read.
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	ctx       context.Context
+	cancel    func()
+	idle      time.Duration
+	idleTimer *time.Timer
+	closeOnce sync.Once
+}
+
+func (b *cancelOnCloseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if b.idleTimer != nil && n > 0 {
+		b.idleTimer.Reset(b.idle)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = withCause(b.ctx, err)
+	}
+	return n, err
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.closeOnce.Do(func() {
+		if b.idleTimer != nil {
+			b.idleTimer.Stop()
+		}
+		b.cancel()
+	})
+	return err
+}
+
+// withCause annotates err with the reason ctx was cancelled, if any, so that
+// timeouts are reported as such rather than as a bare "context canceled".
+func withCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil && !errors.Is(err, cause) &&
+		!errors.Is(cause, context.Canceled) && !errors.Is(cause, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", cause, err)
+	}
+	return err
+}
@@ -0,0 +1,153 @@
+package desktop
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/docker/model-runner/cmd/cli/pkg/types"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTimeoutTestContext starts a server running handler and returns a context
+// pointing at it with the given timeouts.
+func newTimeoutTestContext(t *testing.T, handler http.HandlerFunc, timeouts ClientTimeouts) *ModelRunnerContext {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	transport := &http.Transport{}
+	t.Cleanup(func() {
+		transport.CloseIdleConnections()
+		server.Close()
+	})
+
+	ctx, err := NewContextForTest(server.URL, &http.Client{Transport: transport}, types.ModelRunnerEngineKindMoby)
+	require.NoError(t, err)
+	ctx.SetTimeouts(timeouts)
+	return ctx
+}
+
+// streamChunks writes n chunks to w, pausing interval between them.
+func streamChunks(w http.ResponseWriter, r *http.Request, n int, interval time.Duration) {
+	flusher := w.(http.Flusher)
+	w.WriteHeader(http.StatusOK)
+	for i := 0; i < n; i++ {
+		_, _ = w.Write([]byte("chunk\n"))
+		flusher.Flush()
+		select {
+		case <-time.After(interval):
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func TestTimeoutClientNonStreamingRequestTimeout(t *testing.T) {
+	ctx := newTimeoutTestContext(t, func(w http.ResponseWriter, r *http.Request) {
+		streamChunks(w, r, 6, 50*time.Millisecond)
+	}, ClientTimeouts{RequestTimeout: 100 * time.Millisecond, StreamIdleTimeout: time.Second})
+
+	req, err := http.NewRequest(http.MethodGet, ctx.URL("/slow"), http.NoBody)
+	require.NoError(t, err)
+	resp, err := ctx.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "request timeout")
+}
+
+func TestTimeoutClientNonStreamingResponseHeaderTimeout(t *testing.T) {
+	ctx := newTimeoutTestContext(t, func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-time.After(time.Second):
+		case <-r.Context().Done():
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}, ClientTimeouts{ResponseHeaderTimeout: 50 * time.Millisecond})
+
+	req, err := http.NewRequest(http.MethodGet, ctx.URL("/slow-headers"), http.NoBody)
+	require.NoError(t, err)
+	resp, err := ctx.Client().Do(req)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "response header timeout")
+}
+
+func TestTimeoutClientStreamingIgnoresRequestTimeout(t *testing.T) {
+	ctx := newTimeoutTestContext(t, func(w http.ResponseWriter, r *http.Request) {
+		// Sleep before responding as a model load would, then stream for
+		// longer than the request timeout.
+		select {
+		case <-time.After(150 * time.Millisecond):
+		case <-r.Context().Done():
+			return
+		}
+		streamChunks(w, r, 6, 50*time.Millisecond)
+	}, ClientTimeouts{
+		ResponseHeaderTimeout: 50 * time.Millisecond,
+		RequestTimeout:        100 * time.Millisecond,
+		StreamIdleTimeout:     time.Second,
+	})
+
+	req, err := http.NewRequestWithContext(WithStreaming(context.Background()), http.MethodPost, ctx.URL(inference.InferencePrefix+"/v1/chat/completions"), http.NoBody)
+	require.NoError(t, err)
+	resp, err := ctx.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, 6, len(body)/len("chunk\n"))
+}
+
+func TestTimeoutClientStreamIdleTimeout(t *testing.T) {
+	ctx := newTimeoutTestContext(t, func(w http.ResponseWriter, r *http.Request) {
+		// Send one chunk, then stall for longer than the idle timeout.
+		streamChunks(w, r, 1, time.Second)
+	}, ClientTimeouts{StreamIdleTimeout: 100 * time.Millisecond})
+
+	req, err := http.NewRequestWithContext(WithStreaming(context.Background()), http.MethodGet, ctx.URL("/stall"), http.NoBody)
+	require.NoError(t, err)
+	resp, err := ctx.Client().Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	_, err = io.ReadAll(resp.Body)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, errStreamIdle), "expected stream idle error, got %v", err)
+}
+
+func TestClientWithoutRequestTimeoutsIsUnwrapped(t *testing.T) {
+	client := &http.Client{}
+	ctx, err := NewContextForTest("http://localhost", client, types.ModelRunnerEngineKindMoby)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultClientTimeouts(), ctx.Timeouts())
+	assert.Same(t, client, ctx.Client())
+}
+
+func TestClientTimeoutsFromEnv(t *testing.T) {
+	t.Setenv("MODEL_RUNNER_REQUEST_TIMEOUT", "2m")
+	t.Setenv("MODEL_RUNNER_STREAM_IDLE_TIMEOUT", "30s")
+	t.Setenv("MODEL_RUNNER_KEEPALIVE", "0")
+	timeouts, err := ClientTimeoutsFromEnv()
+	require.NoError(t, err)
+	want := DefaultClientTimeouts()
+	want.RequestTimeout = 2 * time.Minute
+	want.StreamIdleTimeout = 30 * time.Second
+	want.KeepAlive = 0
+	assert.Equal(t, want, timeouts)
+
+	t.Setenv("MODEL_RUNNER_DIAL_TIMEOUT", "soon")
+	_, err = ClientTimeoutsFromEnv()
+	assert.ErrorContains(t, err, "MODEL_RUNNER_DIAL_TIMEOUT")
+}
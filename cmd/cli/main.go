@@ -1,24 +1,41 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 
+	"github.com/docker/cli/cli"
 	"github.com/docker/cli/cli-plugins/metadata"
 	"github.com/docker/cli/cli-plugins/plugin"
 	"github.com/docker/cli/cli/command"
 	"github.com/docker/model-runner/cmd/cli/commands"
 	"github.com/docker/model-runner/cmd/cli/desktop"
+	"github.com/docker/model-runner/pkg/tracing"
 )
 
 func main() {
 	if err := run(); err != nil {
+		var statusErr cli.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode != 0 {
+			if msg := statusErr.Error(); msg != "" {
+				_, _ = fmt.Fprintln(os.Stderr, msg)
+			}
+			os.Exit(statusErr.StatusCode)
+		}
 		_, _ = fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
 
 func run() error {
+	shutdownTracing, err := tracing.Setup(context.Background(), "docker-model-cli")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = shutdownTracing(context.Background()) }()
+
 	cli, err := command.NewDockerCli()
 	if err != nil {
 		return fmt.Errorf("unable to initialize CLI: %w", err)
@@ -0,0 +1,188 @@
+// Package modelalias provides persistent storage for user-defined model
+// aliases, short local names that stand for full model references, so that
+// long references need not be typed repeatedly.
+package modelalias
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// aliasFileVersion is the version of the on-disk alias file format.
+const aliasFileVersion = 1
+
+// validAlias matches alias names. Aliases cannot contain slashes, colons or
+// "@", so they never look like a reference with an organization, tag or
+// digest.
+var validAlias = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]*$`)
+
+// aliasFile is the versioned on-disk representation of the alias store.
+type aliasFile struct {
+	// Version is the schema version; currently always 1.
+	Version int `json:"version"`
+	// Aliases is a map from alias to the model reference it stands for.
+	Aliases map[string]string `json:"aliases"`
+}
+
+// Store manages model aliases stored in a single JSON file.
+type Store struct {
+	// path is the absolute path to the model-aliases file.
+	path string
+}
+
+// New returns a Store that persists aliases in
+// <dockerConfigDir>/model-aliases. The file is only created once an alias is
+// added.
+func New(dockerConfigDir string) *Store {
+	return &Store{path: filepath.Join(dockerConfigDir, "model-aliases")}
+}
+
+// ValidateAlias returns an error if alias does not match the allowed pattern
+// or could be mistaken for a model ID.
+func ValidateAlias(alias string) error {
+	if !validAlias.MatchString(alias) {
+		return fmt.Errorf("invalid alias %q: must match %s", alias, validAlias)
+	}
+	if looksLikeID(alias) {
+		return fmt.Errorf("invalid alias %q: aliases cannot look like model IDs", alias)
+	}
+	return nil
+}
+
+// List returns all aliases, keyed by alias.
+func (s *Store) List() (map[string]string, error) {
+	af, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return af.Aliases, nil
+}
+
+// Add maps alias to reference. It returns an error if the alias is invalid or
+// already exists.
+func (s *Store) Add(alias, reference string) error {
+	if err := ValidateAlias(alias); err != nil {
+		return err
+	}
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return errors.New("reference cannot be empty")
+	}
+	return s.update(func(af *aliasFile) error {
+		if existing, exists := af.Aliases[alias]; exists {
+			return fmt.Errorf("alias %q already exists for %s", alias, existing)
+		}
+		af.Aliases[alias] = reference
+		return nil
+	})
+}
+
+// Remove deletes alias. It returns an error if the alias does not exist.
+func (s *Store) Remove(alias string) error {
+	return s.update(func(af *aliasFile) error {
+		if _, exists := af.Aliases[alias]; !exists {
+			return fmt.Errorf("alias %q not found", alias)
+		}
+		delete(af.Aliases, alias)
+		return nil
+	})
+}
+
+// Resolve returns the reference model stands for if it is an alias, and
+// model unchanged otherwise. Model IDs and digests are never resolved, so a
+// hand-edited alias file cannot shadow a real model.
+func (s *Store) Resolve(model string) (string, error) {
+	model = strings.TrimSpace(model)
+	if looksLikeID(model) || strings.HasPrefix(model, "sha256:") {
+		return model, nil
+	}
+	af, err := s.read()
+	if err != nil {
+		return "", err
+	}
+	if reference, ok := af.Aliases[model]; ok {
+		return reference, nil
+	}
+	return model, nil
+}
+
+// looksLikeID reports whether s is a short or full hex model ID (12 or 64
+// characters).
+func looksLikeID(s string) bool {
+	if len(s) != 12 && len(s) != 64 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// read loads the alias file from disk. A missing file is treated as an empty
+// store rather than an error.
+func (s *Store) read() (aliasFile, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return aliasFile{
+				Version: aliasFileVersion,
+				Aliases: make(map[string]string),
+			}, nil
+		}
+		return aliasFile{}, fmt.Errorf("unable to read alias file: %w", err)
+	}
+	var af aliasFile
+	if err := json.Unmarshal(data, &af); err != nil {
+		return aliasFile{}, fmt.Errorf("unable to parse alias file: %w", err)
+	}
+	if af.Aliases == nil {
+		af.Aliases = make(map[string]string)
+	}
+	return af, nil
+}
+
+// update applies a mutation function and writes the result atomically, so
+// that readers always see a complete file.
+func (s *Store) update(mutate func(*aliasFile) error) error {
+	af, err := s.read()
+	if err != nil {
+		return err
+	}
+	if err := mutate(&af); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(af, "", "    ")
+	if err != nil {
+		return fmt.Errorf("unable to serialise alias file: %w", err)
+	}
+	data = append(data, '\n')
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("unable to create alias directory: %w", err)
+	}
+
+	// Write to a uniquely named temp file then rename atomically.
+	var rndBuf [8]byte
+	if _, err := rand.Read(rndBuf[:]); err != nil {
+		return fmt.Errorf("unable to generate random bytes for temp file: %w", err)
+	}
+	tmpPath := fmt.Sprintf("%s.tmp.%d.%x", s.path, os.Getpid(), rndBuf)
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("unable to write alias file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("unable to commit alias file: %w", err)
+	}
+	return nil
+}
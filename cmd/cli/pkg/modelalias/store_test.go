@@ -0,0 +1,96 @@
+package modelalias
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResolve verifies that an alias resolves to its target and that other
+// names are returned unchanged.
+func TestResolve(t *testing.T) {
+	store := New(t.TempDir())
+	require.NoError(t, store.Add("qwen", "hf.co/bartowski/Qwen2.5-7B-Instruct-GGUF:Q4_K_M"))
+
+	got, err := store.Resolve("qwen")
+	require.NoError(t, err)
+	assert.Equal(t, "hf.co/bartowski/Qwen2.5-7B-Instruct-GGUF:Q4_K_M", got)
+
+	got, err = store.Resolve("ai/smollm2")
+	require.NoError(t, err)
+	assert.Equal(t, "ai/smollm2", got)
+}
+
+// TestResolve_missingFile verifies that a missing alias file resolves names
+// unchanged and is not created by reads.
+func TestResolve_missingFile(t *testing.T) {
+	dir := t.TempDir()
+	store := New(dir)
+
+	got, err := store.Resolve("qwen")
+	require.NoError(t, err)
+	assert.Equal(t, "qwen", got)
+
+	_, err = os.Stat(filepath.Join(dir, "model-aliases"))
+	assert.True(t, os.IsNotExist(err))
+}
+
+// TestResolve_idsTakePrecedence verifies that model IDs and digests are never
+// resolved as aliases, even if the alias file was edited to contain them.
+func TestResolve_idsTakePrecedence(t *testing.T) {
+	dir := t.TempDir()
+	const shortID = "0123456789ab"
+	const digest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+	data := `{"version":1,"aliases":{"` + shortID + `":"ai/other","` + digest + `":"ai/other"}}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "model-aliases"), []byte(data), 0o600))
+	store := New(dir)
+
+	for _, id := range []string{shortID, digest} {
+		got, err := store.Resolve(id)
+		require.NoError(t, err)
+		assert.Equal(t, id, got)
+	}
+}
+
+// TestAdd_invalid verifies that aliases that look like references or model
+// IDs are rejected.
+func TestAdd_invalid(t *testing.T) {
+	store := New(t.TempDir())
+	for _, alias := range []string{"", "ai/qwen", "qwen:latest", "qwen@sha256", "-qwen", "0123456789ab"} {
+		assert.Error(t, store.Add(alias, "ai/qwen"), "alias %q", alias)
+	}
+	assert.Error(t, store.Add("qwen", " "))
+}
+
+// TestAdd_duplicate verifies that an existing alias is not overwritten.
+func TestAdd_duplicate(t *testing.T) {
+	store := New(t.TempDir())
+	require.NoError(t, store.Add("qwen", "ai/qwen2.5"))
+
+	err := store.Add("qwen", "ai/qwen3")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "already exists")
+
+	got, err := store.Resolve("qwen")
+	require.NoError(t, err)
+	assert.Equal(t, "ai/qwen2.5", got)
+}
+
+// TestRemove verifies that a removed alias no longer resolves and that
+// removing an unknown alias fails.
+func TestRemove(t *testing.T) {
+	store := New(t.TempDir())
+	require.NoError(t, store.Add("qwen", "ai/qwen2.5"))
+	require.NoError(t, store.Remove("qwen"))
+
+	aliases, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, aliases)
+
+	err = store.Remove("qwen")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not found")
+}
@@ -21,11 +21,15 @@ import (
 	"github.com/docker/model-runner/pkg/inference/backends/sglang"
 	"github.com/docker/model-runner/pkg/inference/config"
 	"github.com/docker/model-runner/pkg/inference/models"
+	"github.com/docker/model-runner/pkg/inference/scheduling"
 	"github.com/docker/model-runner/pkg/logging"
 	dmrlogs "github.com/docker/model-runner/pkg/logs"
 	"github.com/docker/model-runner/pkg/metrics"
 	"github.com/docker/model-runner/pkg/routing"
+	"github.com/docker/model-runner/pkg/sandbox"
+	"github.com/docker/model-runner/pkg/servicemetrics"
 	modeltls "github.com/docker/model-runner/pkg/tls"
+	"github.com/docker/model-runner/pkg/tracing"
 )
 
 // initLogger creates the application logger based on LOG_LEVEL env var.
@@ -38,10 +42,27 @@ var log = initLogger()
 // exitFunc is used for Fatal-like exits; overridden in tests.
 var exitFunc = func(code int) { os.Exit(code) }
 
+// shutdownTimeout bounds how long the model manager waits for pulls, pushes
+// and store writes to stop on shutdown.
+const shutdownTimeout = 30 * time.Second
+
 func main() {
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
+	shutdownTracing, err := tracing.Setup(ctx, "docker-model-runner")
+	if err != nil {
+		log.Error("Failed to set up tracing", "error", err)
+		exitFunc(1)
+	}
+	defer func() {
+		flushCtx, cancelFlush := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancelFlush()
+		if err := shutdownTracing(flushCtx); err != nil {
+			log.Warn("Failed to flush traces", "error", err)
+		}
+	}()
+
 	sockName := envconfig.SocketPath()
 	modelPath, err := envconfig.ModelsPath()
 	if err != nil {
@@ -49,6 +70,42 @@ func main() {
 		exitFunc(1)
 	}
 
+	maxModelBytes, err := envconfig.MaxModelBytes()
+	if err != nil {
+		log.Error("Invalid maximum model size", "error", err)
+		exitFunc(1)
+	}
+
+	maxStoreBytes, err := envconfig.MaxStoreBytes()
+	if err != nil {
+		log.Error("Invalid maximum store size", "error", err)
+		exitFunc(1)
+	}
+
+	maxLoadBytes, err := envconfig.MaxLoadBytes()
+	if err != nil {
+		log.Error("Invalid maximum load size", "error", err)
+		exitFunc(1)
+	}
+
+	defaultContextSize, err := envconfig.DefaultContextSize()
+	if err != nil {
+		log.Error("Invalid default context size", "error", err)
+		exitFunc(1)
+	}
+
+	maxInflightRequests, err := envconfig.MaxInflightRequests()
+	if err != nil {
+		log.Error("Invalid maximum in-flight requests", "error", err)
+		exitFunc(1)
+	}
+
+	blobCopyBufferSize, err := envconfig.BlobCopyBufferSize()
+	if err != nil {
+		log.Error("Invalid blob copy buffer size", "error", err)
+		exitFunc(1)
+	}
+
 	if envconfig.DisableServerUpdate() {
 		llamacpp.ShouldUpdateServerLock.Lock()
 		llamacpp.ShouldUpdateServer = false
@@ -66,16 +123,6 @@ func main() {
 	diffusersServerPath := envconfig.DiffusersServerPath()
 	vllmMetalServerPath := envconfig.VLLMMetalServerPath()
 
-	// Create a proxy-aware HTTP transport
-	// Use a safe type assertion with fallback, and explicitly set Proxy to http.ProxyFromEnvironment
-	var baseTransport *http.Transport
-	if t, ok := http.DefaultTransport.(*http.Transport); ok {
-		baseTransport = t.Clone()
-	} else {
-		baseTransport = &http.Transport{}
-	}
-	baseTransport.Proxy = http.ProxyFromEnvironment
-
 	log.Info("LLAMA_SERVER_PATH", "path", llamaServerPath)
 	if vllmServerPath != "" {
 		log.Info("VLLM_SERVER_PATH", "path", vllmServerPath)
@@ -101,6 +148,13 @@ func main() {
 		return
 	}
 
+	vllmSandboxPolicy, err := sandbox.ParsePolicy(envconfig.VLLMSandboxPolicy())
+	if err != nil {
+		log.Error("invalid VLLM_SANDBOX_POLICY", "error", err)
+		exitFunc(1)
+		return
+	}
+
 	updatedServerPath := func() string {
 		wd, _ := os.Getwd()
 		d := filepath.Join(wd, "updated-inference", "bin")
@@ -108,12 +162,31 @@ func main() {
 		return d
 	}()
 
+	// The daemon's own metrics are only collected when they are served.
+	var serviceMetrics *servicemetrics.Metrics
+	if envconfig.ServiceMetrics() && !envconfig.DisableMetrics() {
+		serviceMetrics = servicemetrics.New()
+	}
+
+	// Take the effective configuration once it has been validated and applied.
+	effectiveConfig := envconfig.Effective()
+
 	svc, err := routing.NewService(routing.ServiceConfig{
 		Log: log,
 		ClientConfig: models.ClientConfig{
-			StoreRootPath: modelPath,
-			Logger:        log.With("component", "model-manager"),
-			Transport:     baseTransport,
+			StoreRootPath:              modelPath,
+			Logger:                     log.With("component", "model-manager"),
+			MaxModelBytes:              maxModelBytes,
+			AllowMaxModelBytesOverride: envconfig.AllowMaxModelBytesOverride(),
+			MaxStoreBytes:              maxStoreBytes,
+			MaxLoadBytes:               maxLoadBytes,
+			AllowPrivateLoadURLs:       envconfig.AllowPrivateLoadURLs(),
+			DefaultContextSize:         defaultContextSize,
+			UseDockerKeychain:          envconfig.UseDockerKeychain(true),
+			RepairStore:                envconfig.RepairStore(),
+			PreflightPulls:             envconfig.PreflightPulls(),
+			BlobCopyBufferSize:         blobCopyBufferSize,
+			Metrics:                    serviceMetrics,
 		},
 		Backends: append(
 			routing.DefaultBackendDefs(routing.BackendsConfig{
@@ -126,6 +199,7 @@ func main() {
 				IncludeVLLM:          includeVLLM,
 				VLLMPath:             vllmServerPath,
 				VLLMMetalPath:        vllmMetalServerPath,
+				VLLMSandboxPolicy:    vllmSandboxPolicy,
 				IncludeDiffusers:     true,
 				DiffusersPath:        diffusersServerPath,
 			}),
@@ -145,6 +219,10 @@ func main() {
 			"",
 			false,
 		),
+		InflightLimit: scheduling.InflightLimit{
+			Max:   maxInflightRequests,
+			Queue: envconfig.QueueInflightRequests(),
+		},
 		AllowedOrigins:      envconfig.AllowedOrigins(),
 		IncludeResponsesAPI: true,
 		ExtraRoutes: func(r *routing.NormalizedServeMux, s *routing.Service) {
@@ -166,6 +244,14 @@ func main() {
 				}
 			})
 
+			// Effective configuration endpoint, with credentials redacted.
+			r.HandleFunc("GET "+inference.InferencePrefix+"/debug/config", func(w http.ResponseWriter, req *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				if err := json.NewEncoder(w).Encode(effectiveConfig); err != nil {
+					log.Warn("failed to write debug config response", "error", err)
+				}
+			})
+
 			// Logs endpoint (Docker Desktop mode only).
 			if logDir := envconfig.LogDir(); logDir != "" {
 				r.HandleFunc(
@@ -181,6 +267,7 @@ func main() {
 					log.With("component", "metrics"),
 					s.SchedulerHTTP,
 				)
+				metricsHandler.SetServiceMetrics(serviceMetrics)
 				r.Handle("/metrics", metricsHandler)
 				log.Info("Metrics endpoint enabled at /metrics")
 			} else {
@@ -317,6 +404,12 @@ func main() {
 				log.Error("TLS server shutdown error", "error", err)
 			}
 		}
+		log.Info("Stopping model operations")
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), shutdownTimeout)
+		if err := svc.ModelManager.Shutdown(shutdownCtx); err != nil {
+			log.Error("Model manager shutdown error", "error", err)
+		}
+		cancelShutdown()
 		log.Info("Waiting for the scheduler to stop")
 		if err := <-schedulerErrors; err != nil {
 			log.Error("Scheduler error", "error", err)
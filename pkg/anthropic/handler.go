@@ -6,6 +6,7 @@ import (
 	"errors"
 	"io"
 	"net/http"
+	"sync"
 
 	"github.com/docker/model-runner/pkg/inference"
 	"github.com/docker/model-runner/pkg/inference/models"
@@ -30,6 +31,7 @@ const (
 type Handler struct {
 	log           logging.Logger
 	router        *http.ServeMux
+	lock          sync.RWMutex
 	httpHandler   http.Handler
 	modelManager  *models.Manager
 	schedulerHTTP *scheduling.HTTPHandler
@@ -49,8 +51,7 @@ func NewHandler(log logging.Logger, schedulerHTTP *scheduling.HTTPHandler, allow
 		h.router.HandleFunc(route, handler)
 	}
 
-	// Apply CORS middleware
-	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
+	h.RebuildRoutes(allowedOrigins)
 
 	return h
 }
@@ -60,7 +61,17 @@ func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	safeMethod := utils.SanitizeForLog(r.Method, -1)
 	safePath := utils.SanitizeForLog(r.URL.Path, -1)
 	h.log.Info("Anthropic API request", "method", safeMethod, "path", safePath)
-	h.httpHandler.ServeHTTP(w, r)
+	h.lock.RLock()
+	handler := h.httpHandler
+	h.lock.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// RebuildRoutes replaces the allowed origins of the CORS middleware.
+func (h *Handler) RebuildRoutes(allowedOrigins []string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
 }
 
 // routeHandlers returns the mapping of routes to their handlers.
@@ -124,7 +135,7 @@ func (h *Handler) proxyToBackend(w http.ResponseWriter, r *http.Request, targetP
 	modelName := req.Model
 
 	// Verify the model exists locally
-	_, err = h.modelManager.GetLocal(modelName)
+	_, err = h.modelManager.ResolveLocal(modelName)
 	if err != nil {
 		h.writeAnthropicError(w, http.StatusNotFound, "not_found_error", "Model not found: "+modelName)
 		return
@@ -133,7 +133,9 @@ func fromFormat(f format.Format, paths []string, opts ...BuildOption) (*Builder,
 	}, nil
 }
 
-// FromModel returns a *Builder that builds model artifacts from an existing model artifact
+// FromModel returns a *Builder that builds model artifacts from an existing model artifact.
+// All layers of the existing model, including its license, chat template and multimodal
+// projector layers, are kept in the built artifact.
 func FromModel(mdl types.ModelArtifact) (*Builder, error) {
 	// Capture original layers for comparison
 	layers, err := mdl.Layers()
@@ -165,6 +167,16 @@ func (b *Builder) WithContextSize(size int32) *Builder {
 	}
 }
 
+// WithAnnotations adds manifest-level annotations to the artifact, such as its
+// source or license (see the types.Annotation* keys). Annotations with keys
+// that are already set replace the existing values.
+func (b *Builder) WithAnnotations(annotations map[string]string) *Builder {
+	return &Builder{
+		model:          mutate.Annotations(b.model, annotations),
+		originalLayers: b.originalLayers,
+	}
+}
+
 // WithMultimodalProjector adds a Multimodal projector file to the artifact
 func (b *Builder) WithMultimodalProjector(path string) (*Builder, error) {
 	mmprojLayer, err := partial.NewLayer(path, types.MediaTypeMultimodalProjector)
@@ -1,9 +1,11 @@
 package builder_test
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -325,6 +327,94 @@ func TestFromModel(t *testing.T) {
 	}
 }
 
+func TestFromModelPreservesAuxiliaryLayers(t *testing.T) {
+	assets := map[types.MediaType]string{
+		types.MediaTypeLicense:             filepath.Join("..", "assets", "license.txt"),
+		types.MediaTypeChatTemplate:        filepath.Join("..", "assets", "template.jinja"),
+		types.MediaTypeMultimodalProjector: filepath.Join("..", "assets", "dummy.mmproj"),
+	}
+
+	// Create an initial model with a license, chat template and multimodal projector
+	initialBuilder, err := builder.FromPath(filepath.Join("..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create initial builder from GGUF: %v", err)
+	}
+	if initialBuilder, err = initialBuilder.WithLicense(assets[types.MediaTypeLicense]); err != nil {
+		t.Fatalf("Failed to add license: %v", err)
+	}
+	if initialBuilder, err = initialBuilder.WithChatTemplateFile(assets[types.MediaTypeChatTemplate]); err != nil {
+		t.Fatalf("Failed to add chat template: %v", err)
+	}
+	if initialBuilder, err = initialBuilder.WithMultimodalProjector(assets[types.MediaTypeMultimodalProjector]); err != nil {
+		t.Fatalf("Failed to add multimodal projector: %v", err)
+	}
+	initialTarget := &fakeTarget{}
+	if err := initialBuilder.Build(t.Context(), initialTarget, nil); err != nil {
+		t.Fatalf("Failed to build initial model: %v", err)
+	}
+
+	// Repackage with a config-only change
+	repackagedBuilder, err := builder.FromModel(initialTarget.artifact)
+	if err != nil {
+		t.Fatalf("Failed to create builder from model: %v", err)
+	}
+	repackagedBuilder = repackagedBuilder.WithContextSize(4096)
+	if !repackagedBuilder.HasOnlyConfigChanges() {
+		t.Error("Expected context size change to be config-only")
+	}
+	repackagedTarget := &fakeTarget{}
+	if err := repackagedBuilder.Build(t.Context(), repackagedTarget, nil); err != nil {
+		t.Fatalf("Failed to build repackaged model: %v", err)
+	}
+
+	artifacts := map[string]types.ModelArtifact{
+		"built":       repackagedTarget.artifact,
+		"lightweight": repackagedBuilder.Model(),
+	}
+	for name, artifact := range artifacts {
+		t.Run(name, func(t *testing.T) {
+			layers, err := artifact.Layers()
+			if err != nil {
+				t.Fatalf("Failed to get layers: %v", err)
+			}
+			found := make(map[types.MediaType]bool)
+			for _, layer := range layers {
+				mediaType, err := layer.MediaType()
+				if err != nil {
+					t.Fatalf("Failed to get layer media type: %v", err)
+				}
+				path, ok := assets[mediaType]
+				if !ok {
+					continue
+				}
+				found[mediaType] = true
+
+				rc, err := layer.Uncompressed()
+				if err != nil {
+					t.Fatalf("Failed to open %s layer: %v", mediaType, err)
+				}
+				got, err := io.ReadAll(rc)
+				rc.Close()
+				if err != nil {
+					t.Fatalf("Failed to read %s layer: %v", mediaType, err)
+				}
+				want, err := os.ReadFile(path)
+				if err != nil {
+					t.Fatalf("Failed to read %s: %v", path, err)
+				}
+				if !bytes.Equal(got, want) {
+					t.Errorf("Expected %s layer to contain %s, content differs", mediaType, path)
+				}
+			}
+			for mediaType := range assets {
+				if !found[mediaType] {
+					t.Errorf("Expected repackaged model to have %s layer", mediaType)
+				}
+			}
+		})
+	}
+}
+
 func TestFromModelWithAdditionalLayers(t *testing.T) {
 	// Create an initial model from GGUF
 	initialBuilder, err := builder.FromPath(filepath.Join("..", "assets", "dummy.gguf"))
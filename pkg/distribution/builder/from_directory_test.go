@@ -1,10 +1,14 @@
 package builder
 
 import (
+	"maps"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
 func TestFromDirectory(t *testing.T) {
@@ -298,3 +302,92 @@ func TestFromDirectoryWithCreatedTime(t *testing.T) {
 }
 
 // Need to import time for mockFileInfo
+
+func TestFromDirectoryDetectsFormat(t *testing.T) {
+	ggufContent, err := os.ReadFile(filepath.Join("..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to read GGUF file: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		files      map[string]string // path -> content
+		format     types.Format
+		mediaTypes map[string]oci.MediaType // path -> expected layer media type
+	}{
+		{
+			name: "safetensors",
+			files: map[string]string{
+				"model.safetensors":             "fake safetensors content",
+				"config.json":                   "{}",
+				"tokenizer/tokenizer.json":      "{}",
+				"tokenizer/chat_template.jinja": "{{ messages }}",
+			},
+			format: types.FormatSafetensors,
+			mediaTypes: map[string]oci.MediaType{
+				"model.safetensors":             types.MediaTypeSafetensors,
+				"config.json":                   types.MediaTypeModelFile,
+				"tokenizer/tokenizer.json":      types.MediaTypeModelFile,
+				"tokenizer/chat_template.jinja": types.MediaTypeChatTemplate,
+			},
+		},
+		{
+			name: "gguf",
+			files: map[string]string{
+				"model.gguf": string(ggufContent),
+				"LICENSE":    "license text",
+			},
+			format: types.FormatGGUF,
+			mediaTypes: map[string]oci.MediaType{
+				"model.gguf": types.MediaTypeGGUF,
+				"LICENSE":    types.MediaTypeLicense,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for path, content := range tt.files {
+				createTestFile(t, tmpDir, path, content)
+			}
+
+			b, err := FromDirectory(tmpDir)
+			if err != nil {
+				t.Fatalf("FromDirectory failed: %v", err)
+			}
+
+			config, err := b.Model().Config()
+			if err != nil {
+				t.Fatalf("Failed to get config: %v", err)
+			}
+			if config.GetFormat() != tt.format {
+				t.Errorf("Expected format %q, got %q", tt.format, config.GetFormat())
+			}
+
+			manifest, err := b.Model().Manifest()
+			if err != nil {
+				t.Fatalf("Failed to get manifest: %v", err)
+			}
+			if manifest.Config.MediaType != types.MediaTypeModelConfigV02 {
+				t.Errorf("Expected config media type %q, got %q", types.MediaTypeModelConfigV02, manifest.Config.MediaType)
+			}
+			mediaTypes := make(map[string]oci.MediaType, len(manifest.Layers))
+			for _, layer := range manifest.Layers {
+				mediaTypes[layer.Annotations[types.AnnotationFilePath]] = layer.MediaType
+			}
+			if !maps.Equal(mediaTypes, tt.mediaTypes) {
+				t.Errorf("Expected layers %v, got %v", tt.mediaTypes, mediaTypes)
+			}
+		})
+	}
+}
+
+func TestFromDirectoryWithoutWeights(t *testing.T) {
+	tmpDir := t.TempDir()
+	createTestFile(t, tmpDir, "config.json", "{}")
+
+	if _, err := FromDirectory(tmpDir); err == nil {
+		t.Fatal("Expected an error for a directory without weight files")
+	}
+}
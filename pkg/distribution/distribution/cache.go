@@ -0,0 +1,85 @@
+package distribution
+
+import (
+	"sync"
+
+	"github.com/docker/model-runner/pkg/distribution/internal/store"
+)
+
+// manifestCache is a concurrency-safe in-memory cache of models read from the
+// store, keyed by the normalized reference they were looked up with. Cached
+// models carry their tags, so the whole cache is dropped whenever the client
+// mutates the store rather than tracking which references are affected.
+type manifestCache struct {
+	mu     sync.RWMutex
+	models map[string]*store.Model
+	// gen counts invalidations, so that a model read before one isn't cached
+	// after it.
+	gen uint64
+}
+
+func newManifestCache() *manifestCache {
+	return &manifestCache{models: make(map[string]*store.Model)}
+}
+
+// get returns the cached model for reference. A nil cache never hits.
+func (c *manifestCache) get(reference string) (*store.Model, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	mdl, ok := c.models[reference]
+	return mdl, ok
+}
+
+// generation returns the number of invalidations so far, to be passed to put
+// along with a model read afterwards. A nil cache is always at generation 0.
+func (c *manifestCache) generation() uint64 {
+	if c == nil {
+		return 0
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.gen
+}
+
+// put caches mdl under reference, unless the cache was invalidated since
+// generation gen, when mdl may be stale. It is a no-op on a nil cache.
+func (c *manifestCache) put(reference string, mdl *store.Model, gen uint64) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if gen != c.gen {
+		return
+	}
+	c.models[reference] = mdl
+}
+
+// invalidate drops every cached model. It is a no-op on a nil cache.
+func (c *manifestCache) invalidate() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.models)
+	c.gen++
+}
+
+// readModel reads a model from the store by normalized reference, consulting
+// the manifest cache first.
+func (c *Client) readModel(reference string) (*store.Model, error) {
+	if mdl, ok := c.cache.get(reference); ok {
+		return mdl, nil
+	}
+	gen := c.cache.generation()
+	mdl, err := c.store.Read(reference)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.put(reference, mdl, gen)
+	return mdl, nil
+}
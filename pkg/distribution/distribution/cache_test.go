@@ -0,0 +1,201 @@
+package distribution
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/internal/store"
+	"github.com/docker/model-runner/pkg/distribution/internal/testutil"
+	mdregistry "github.com/docker/model-runner/pkg/distribution/registry"
+)
+
+// countingReadFile wraps os.ReadFile and counts reads of manifest files.
+type countingReadFile struct {
+	manifestReads atomic.Int64
+}
+
+func (c *countingReadFile) ReadFile(name string) ([]byte, error) {
+	if strings.Contains(filepath.ToSlash(name), "/manifests/") {
+		c.manifestReads.Add(1)
+	}
+	return os.ReadFile(name)
+}
+
+// newCountingClient returns a client whose store reports manifest reads to
+// the returned counter.
+func newCountingClient(t *testing.T, cacheEnabled bool) (*Client, *countingReadFile) {
+	t.Helper()
+	counter := &countingReadFile{}
+	s, err := store.New(store.Options{
+		RootPath: t.TempDir(),
+		ReadFile: counter.ReadFile,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	c := &Client{
+		store:    s,
+		log:      slog.Default(),
+		registry: mdregistry.NewClient(mdregistry.WithPlainHTTP(true)),
+	}
+	if cacheEnabled {
+		c.cache = newManifestCache()
+	}
+	return c, counter
+}
+
+func TestManifestCacheGetModel(t *testing.T) {
+	client, counter := newCountingClient(t, true)
+
+	const tag = "ai/cached-model:latest"
+	if err := client.store.Write(testutil.NewGGUFArtifact(t, testGGUFFile), []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	reads := counter.manifestReads.Load()
+	if reads == 0 {
+		t.Fatal("Expected the first GetModel to read the manifest from disk")
+	}
+
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	if got := counter.manifestReads.Load(); got != reads {
+		t.Errorf("Second GetModel read manifests from disk: reads = %d, want %d", got, reads)
+	}
+}
+
+func TestManifestCacheDeleteInvalidates(t *testing.T) {
+	client, counter := newCountingClient(t, true)
+
+	const tag = "ai/cached-model:latest"
+	if err := client.store.Write(testutil.NewGGUFArtifact(t, testGGUFFile), []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	if _, ok := client.cache.get(tag); !ok {
+		t.Fatal("Expected model to be cached after GetModel")
+	}
+
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+	if _, ok := client.cache.get(tag); ok {
+		t.Error("Expected DeleteModel to invalidate the cached model")
+	}
+
+	reads := counter.manifestReads.Load()
+	if _, err := client.GetModel(tag); !errors.Is(err, ErrModelNotFound) {
+		t.Fatalf("Expected ErrModelNotFound after delete, got %v", err)
+	}
+	if exists, err := client.IsModelInStore(tag); err != nil || exists {
+		t.Errorf("IsModelInStore() = %v, %v; want false, nil", exists, err)
+	}
+	if got := counter.manifestReads.Load(); got != reads {
+		t.Errorf("Reading a deleted model should not read manifests: reads = %d, want %d", got, reads)
+	}
+}
+
+func TestManifestCacheTagInvalidates(t *testing.T) {
+	client, _ := newCountingClient(t, true)
+
+	const tag = "ai/cached-model:latest"
+	const newTag = "docker.io/ai/cached-model:v2"
+	if err := client.store.Write(testutil.NewGGUFArtifact(t, testGGUFFile), []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+
+	if err := client.Tag(tag, newTag); err != nil {
+		t.Fatalf("Failed to tag model: %v", err)
+	}
+
+	mdl, err := client.GetModel(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	if !slices.Contains(mdl.Tags(), newTag) {
+		t.Errorf("Tags() = %v, want to contain %q", mdl.Tags(), newTag)
+	}
+}
+
+func TestManifestCacheInvalidatedDuringRead(t *testing.T) {
+	var client *Client
+	s, err := store.New(store.Options{
+		RootPath: t.TempDir(),
+		ReadFile: func(name string) ([]byte, error) {
+			// Invalidate the cache while the manifest is being read, as a
+			// concurrent delete would.
+			if client != nil && strings.Contains(filepath.ToSlash(name), "/manifests/") {
+				client.cache.invalidate()
+			}
+			return os.ReadFile(name)
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	const tag = "ai/cached-model:latest"
+	if err := s.Write(testutil.NewGGUFArtifact(t, testGGUFFile), []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	client = &Client{store: s, log: slog.Default(), cache: newManifestCache()}
+
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	if _, ok := client.cache.get(tag); ok {
+		t.Error("Expected a model read across an invalidation not to be cached")
+	}
+}
+
+func TestManifestCacheDisabled(t *testing.T) {
+	client, counter := newCountingClient(t, false)
+
+	const tag = "ai/uncached-model:latest"
+	if err := client.store.Write(testutil.NewGGUFArtifact(t, testGGUFFile), []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	reads := counter.manifestReads.Load()
+	if _, err := client.GetModel(tag); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	if got := counter.manifestReads.Load(); got <= reads {
+		t.Errorf("Expected GetModel to read from disk when caching is disabled: reads = %d, previously %d", got, reads)
+	}
+}
+
+func TestWithManifestCache(t *testing.T) {
+	enabled, err := NewClient(WithStoreRootPath(t.TempDir()))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if enabled.cache == nil {
+		t.Error("Expected manifest cache to be enabled by default")
+	}
+
+	disabled, err := NewClient(WithStoreRootPath(t.TempDir()), WithManifestCache(false))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if disabled.cache != nil {
+		t.Error("Expected WithManifestCache(false) to disable the manifest cache")
+	}
+}
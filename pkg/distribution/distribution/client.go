@@ -2,27 +2,35 @@ package distribution
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"log/slog"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/huggingface"
 	"github.com/docker/model-runner/pkg/distribution/internal/bundle"
 	"github.com/docker/model-runner/pkg/distribution/internal/mutate"
+	"github.com/docker/model-runner/pkg/distribution/internal/partial"
 	"github.com/docker/model-runner/pkg/distribution/internal/progress"
 	"github.com/docker/model-runner/pkg/distribution/internal/store"
-	"github.com/docker/model-runner/pkg/distribution/modelpack"
 	"github.com/docker/model-runner/pkg/distribution/oci"
 	"github.com/docker/model-runner/pkg/distribution/oci/authn"
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
 	"github.com/docker/model-runner/pkg/distribution/oci/remote"
 	"github.com/docker/model-runner/pkg/distribution/registry"
 	"github.com/docker/model-runner/pkg/distribution/tarball"
 	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/docker/model-runner/pkg/internal/utils"
+	"github.com/docker/model-runner/pkg/logging"
 )
 
 // Client provides model distribution functionality
@@ -30,6 +38,11 @@ type Client struct {
 	store    *store.LocalStore
 	log      *slog.Logger
 	registry *registry.Client
+	// hfTransport is the transport of the Hugging Face clients, or nil for
+	// the default transport.
+	hfTransport http.RoundTripper
+	// cache holds models read from the store. It is nil when caching is disabled.
+	cache *manifestCache
 }
 
 // GetStorePath returns the root path where models are stored
@@ -42,9 +55,14 @@ type Option func(*options)
 
 // options holds the configuration for a new Client
 type options struct {
-	storeRootPath  string
-	logger         *slog.Logger
-	registryClient *registry.Client
+	storeRootPath        string
+	logger               *slog.Logger
+	registryClient       *registry.Client
+	disableManifestCache bool
+	copyBufferSize       int
+	fileMode             os.FileMode
+	dirMode              os.FileMode
+	hfTransport          http.RoundTripper
 }
 
 // WithStoreRootPath sets the store root path
@@ -74,6 +92,39 @@ func WithRegistryClient(client *registry.Client) Option {
 	}
 }
 
+// WithManifestCache enables or disables the in-memory manifest cache used by
+// ListModels, GetModel and IsModelInStore. The cache is enabled by default.
+func WithManifestCache(enabled bool) Option {
+	return func(o *options) {
+		o.disableManifestCache = !enabled
+	}
+}
+
+// WithCopyBufferSize sets the size of the buffer blobs are written to the
+// store through. Zero or less keeps the store's default of 1 MiB.
+func WithCopyBufferSize(size int) Option {
+	return func(o *options) {
+		o.copyBufferSize = size
+	}
+}
+
+// WithFileModes sets the modes of the files and directories the store
+// creates. Zero keeps the store's defaults of 0600 and 0700 respectively.
+func WithFileModes(fileMode, dirMode os.FileMode) Option {
+	return func(o *options) {
+		o.fileMode = fileMode
+		o.dirMode = dirMode
+	}
+}
+
+// WithHuggingFaceTransport sets the HTTP transport native Hugging Face pulls
+// and pushes use. Nil keeps the default transport.
+func WithHuggingFaceTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.hfTransport = transport
+	}
+}
+
 func defaultOptions() *options {
 	return &options{
 		logger: slog.Default(),
@@ -92,7 +143,10 @@ func NewClient(opts ...Option) (*Client, error) {
 	}
 
 	s, err := store.New(store.Options{
-		RootPath: options.storeRootPath,
+		RootPath:       options.storeRootPath,
+		CopyBufferSize: options.copyBufferSize,
+		FileMode:       options.fileMode,
+		DirMode:        options.dirMode,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("initializing store: %w", err)
@@ -100,14 +154,18 @@ func NewClient(opts ...Option) (*Client, error) {
 
 	registryClient := options.registryClient
 	if registryClient == nil {
-		registryClient = registry.NewClient()
+		registryClient = registry.NewClient(registry.WithTransportOptions(registry.TransportOptions{}))
 	}
 
 	options.logger.Info("Successfully initialized store")
 	c := &Client{
-		store:    s,
-		log:      options.logger,
-		registry: registryClient,
+		store:       s,
+		log:         options.logger,
+		registry:    registryClient,
+		hfTransport: options.hfTransport,
+	}
+	if !options.disableManifestCache {
+		c.cache = newManifestCache()
 	}
 
 	// Migrate any legacy hf.co tags to huggingface.co
@@ -137,25 +195,17 @@ func (c *Client) migrateHFTags() error {
 	return nil
 }
 
+// NormalizeModelName returns the canonical form of a model reference, as used
+// to look models up in the store.
+func (c *Client) NormalizeModelName(model string) string {
+	return c.normalizeModelName(model)
+}
+
 // normalizeModelName adds the default organization prefix (ai/) and tag (:latest) if missing.
 // It also resolves IDs to full IDs.
 // This is a private method used internally by the Client.
 func (c *Client) normalizeModelName(model string) string {
-	const (
-		defaultOrg = "ai"
-		defaultTag = "latest"
-	)
-
 	model = strings.TrimSpace(model)
-	if model == "" {
-		return model
-	}
-
-	// Normalize HuggingFace short URL (hf.co) to canonical form (huggingface.co)
-	// This ensures that hf.co/org/model and huggingface.co/org/model are treated as the same model
-	if rest, found := strings.CutPrefix(model, "hf.co/"); found {
-		model = "huggingface.co/" + rest
-	}
 
 	// If it looks like an ID or digest, try to resolve it to full ID
 	if c.looksLikeID(model) || c.looksLikeDigest(model) {
@@ -165,34 +215,7 @@ func (c *Client) normalizeModelName(model string) string {
 		return model
 	}
 
-	// Split name vs tag, where ':' is a tag separator only if it's after the last '/'
-	lastSlash := strings.LastIndex(model, "/")
-	lastColon := strings.LastIndex(model, ":")
-
-	name := model
-	tag := defaultTag
-	hasTag := lastColon > lastSlash
-
-	if hasTag {
-		name = model[:lastColon]
-		// Preserve tag as-is; if empty, fall back to defaultTag
-		if t := model[lastColon+1:]; t != "" {
-			tag = t
-		}
-	}
-
-	// If name has no registry (domain with dot before first slash), apply default org if missing slash
-	firstSlash := strings.Index(name, "/")
-	hasRegistry := firstSlash > 0 && strings.Contains(name[:firstSlash], ".")
-
-	if !hasRegistry && !strings.Contains(name, "/") {
-		name = defaultOrg + "/" + name
-	}
-
-	// Lowercase ONLY the name part (registry/org/repo). Tag stays unchanged.
-	name = strings.ToLower(name)
-
-	return name + ":" + tag
+	return reference.Parse(model).Normalize()
 }
 
 // looksLikeID returns true for short & long hex IDs (12 or 64 chars)
@@ -262,41 +285,79 @@ func (c *Client) resolveID(id string) string {
 
 // PullModel pulls a model from a registry and returns the local file path
 func (c *Client) PullModel(ctx context.Context, reference string, progressWriter io.Writer, bearerToken ...string) error {
+	var opts PullOptions
+	if len(bearerToken) > 0 {
+		opts.BearerToken = bearerToken[0]
+	}
+	_, err := c.PullModelWithOptions(ctx, reference, progressWriter, opts)
+	return err
+}
+
+// PullOptions configures PullModelWithOptions.
+type PullOptions struct {
+	// BearerToken is an optional bearer token for registry authentication.
+	BearerToken string
+	// Force re-downloads the model even if the local copy is up to date.
+	Force bool
+	// MaxBytes, if positive, rejects models whose total size exceeds it with
+	// ErrModelTooLarge before anything is downloaded.
+	MaxBytes int64
+	// RawReference skips model name normalization, so the reference is used
+	// exactly as given, without the default organization or tag.
+	RawReference bool
+}
+
+// pullReference returns the reference a pull with opts resolves. It is
+// normalized unless opts.RawReference is set, in which case it is only trimmed;
+// the registry client still rejects references that do not parse.
+func (c *Client) pullReference(reference string, opts PullOptions) string {
+	if opts.RawReference {
+		return strings.TrimSpace(reference)
+	}
+	return c.normalizeModelName(reference)
+}
+
+// PullModelWithOptions pulls a model from a registry using the given options
+// and returns the ID of the model the reference points to once the pull has
+// finished. Unless opts.Force is set, the pull is skipped when the reference
+// already points to the remote manifest digest.
+func (c *Client) PullModelWithOptions(ctx context.Context, reference string, progressWriter io.Writer, opts PullOptions) (string, error) {
 	// Store original reference before normalization (needed for case-sensitive HuggingFace API)
 	originalReference := reference
-	// Normalize the model reference
-	reference = c.normalizeModelName(reference)
-	c.log.Info("starting model pull", "reference", utils.SanitizeForLog(reference))
+	reference = c.pullReference(reference, opts)
+	start := time.Now()
+	c.log.Info("starting model pull", logging.Model(reference), "force", opts.Force)
+	defer c.cache.invalidate()
 
 	// Handle bearer token for registry authentication
-	var token string
-	if len(bearerToken) > 0 && bearerToken[0] != "" {
-		token = bearerToken[0]
-	}
+	token := opts.BearerToken
 
 	// HuggingFace references always use native pull (download raw files from HF Hub)
 	if IsHuggingFaceReference(originalReference) {
-		c.log.Info("using native HuggingFace pull", "reference", utils.SanitizeForLog(reference))
+		if err := checkHFTag(originalReference); err != nil {
+			return "", err
+		}
+		c.log.Info("using native HuggingFace pull", logging.Model(reference))
 
 		// Check if model already exists in local store (reference is already normalized)
 		localModel, err := c.store.Read(reference)
-		if err == nil {
-			c.log.Info("HuggingFace model found in local store", "reference", utils.SanitizeForLog(reference))
+		if err == nil && !opts.Force {
+			c.log.Info("HuggingFace model found in local store", logging.Model(reference))
 			cfg, err := localModel.Config()
 			if err != nil {
-				return fmt.Errorf("getting cached model config: %w", err)
+				return "", fmt.Errorf("getting cached model config: %w", err)
 			}
 			if err := progress.WriteSuccess(progressWriter, fmt.Sprintf("Using cached model: %s", cfg.GetSize()), oci.ModePull); err != nil {
 				c.log.Warn("Writing progress", "error", err)
 			}
-			return nil
+			return localModel.ID()
 		}
-		if !errors.Is(err, ErrModelNotFound) {
-			return fmt.Errorf("checking for cached HuggingFace model: %w", err)
+		if err != nil && !errors.Is(err, ErrModelNotFound) {
+			return "", fmt.Errorf("checking for cached HuggingFace model: %w", err)
 		}
 
 		// Pass original reference to preserve case-sensitivity for HuggingFace API
-		return c.pullNativeHuggingFace(ctx, originalReference, progressWriter, token)
+		return c.pullNativeHuggingFace(ctx, originalReference, progressWriter, token, opts.MaxBytes)
 	}
 
 	// For non-HF references, use OCI registry
@@ -313,9 +374,9 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 		// Check if the error should be converted to registry.ErrModelNotFound for API compatibility
 		// If the error already matches ErrModelNotFound, return it directly to preserve errors.Is compatibility
 		if errors.Is(err, registry.ErrModelNotFound) {
-			return err
+			return "", err
 		}
-		return fmt.Errorf("reading model from registry: %w", err)
+		return "", fmt.Errorf("reading model from registry: %w", err)
 	}
 
 	// Get the remote image digest immediately to ensure we work with a consistent manifest
@@ -323,14 +384,34 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 	remoteDigest, err := remoteModel.Digest()
 	if err != nil {
 		c.log.Error("failed to get remote image digest", "error", err)
-		return fmt.Errorf("getting remote image digest: %w", err)
+		return "", fmt.Errorf("getting remote image digest: %w", err)
+	}
+	c.log.Info("resolved remote model digest", logging.Model(reference), logging.Digest(remoteDigest.String()))
+
+	// Skip the pull entirely if the reference already points to the remote digest
+	if !opts.Force {
+		upToDate, err := c.IsUpToDate(reference, remoteDigest)
+		if err != nil {
+			return "", err
+		}
+		if upToDate {
+			c.log.Info("model is up to date", logging.Model(reference), logging.Digest(remoteDigest.String()))
+			if err := progress.WriteSuccess(progressWriter, "Model is up to date", oci.ModePull); err != nil {
+				c.log.Warn("Writing progress", "error", err)
+			}
+			return remoteDigest.String(), nil
+		}
 	}
-	c.log.Info("remote model digest", "digest", remoteDigest.String())
 
 	// Check for incomplete downloads and prepare resume offsets
 	layers, err := remoteModel.Layers()
 	if err != nil {
-		return fmt.Errorf("getting layers: %w", err)
+		return "", fmt.Errorf("getting layers: %w", err)
+	}
+	totalBytes := totalLayerSize(layers)
+	if opts.MaxBytes > 0 && totalBytes > opts.MaxBytes {
+		c.log.Warn("rejecting model pull above size limit", logging.Model(reference), logging.TotalBytes(totalBytes), "limit", opts.MaxBytes)
+		return "", fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrModelTooLarge, totalBytes, opts.MaxBytes)
 	}
 
 	// Build a map of digest -> resume offset for layers with incomplete downloads
@@ -342,6 +423,12 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 			continue
 		}
 
+		// Compressed layers are decompressed while being stored, so partial
+		// downloads cannot be resumed from an offset.
+		if mt, err := layer.MediaType(); err == nil && mt.IsZstd() {
+			continue
+		}
+
 		// Check if there's an incomplete download for this layer (use DiffID for uncompressed models)
 		diffID, err := layer.DiffID()
 		if err != nil {
@@ -355,14 +442,39 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 			continue
 		}
 
-		if incompleteSize > 0 {
-			c.log.Info("Found incomplete download for layer", "digest", digest, "bytes", incompleteSize)
-			resumeOffsets[digest.String()] = incompleteSize
+		if incompleteSize == 0 {
+			continue
 		}
+
+		// An incomplete file at least as large as the remote layer cannot be
+		// resumed with a satisfiable Range request. One that is larger is
+		// corrupt, so discard it and restart the download from scratch.
+		size, err := layer.Size()
+		if err != nil {
+			c.log.Warn("Failed to get layer size", "digest", digest, "error", err)
+			continue
+		}
+		if incompleteSize > size {
+			c.log.Warn("Discarding incomplete download larger than remote layer",
+				"digest", digest, "bytes", incompleteSize, "size", size)
+			if err := c.store.RemoveIncomplete(diffID); err != nil {
+				c.log.Warn("Failed to remove incomplete download", "digest", digest, "error", err)
+			}
+			continue
+		}
+		if incompleteSize == size {
+			// The download may be complete; the store verifies its digest
+			// before fetching anything.
+			continue
+		}
+
+		c.log.Info("Found incomplete download for layer", "digest", digest, "bytes", incompleteSize)
+		resumeOffsets[digest.String()] = incompleteSize
 	}
 
 	// If we have any incomplete downloads, create a new context with resume offsets
 	// and re-fetch using the original reference to ensure compatibility with all registries
+	freshCtx := ctx
 	var rangeSuccess *remote.RangeSuccess
 	if len(resumeOffsets) > 0 {
 		c.log.Info("Resuming interrupted layer download(s)", "count", len(resumeOffsets))
@@ -375,24 +487,25 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 		c.log.Info("Re-fetching model with original reference for resume", "model", utils.SanitizeForLog(reference))
 		remoteModel, err = registryClient.Model(ctx, reference)
 		if err != nil {
-			return fmt.Errorf("reading model from registry with resume context: %w", err)
+			return "", fmt.Errorf("reading model from registry with resume context: %w", err)
 		}
 	}
 
 	// Check for supported type
 	if err := checkCompat(remoteModel, c.log, reference, progressWriter); err != nil {
-		return err
+		return "", err
 	}
 
 	// Check if model exists in local store
 	localModel, err := c.store.Read(remoteDigest.String())
-	if err == nil {
-		c.log.Info("model found in local store", "reference", utils.SanitizeForLog(reference))
+	if err == nil && !opts.Force {
+		c.log.Info("model found in local store", logging.Model(reference), logging.Digest(remoteDigest.String()))
 		cfg, err := localModel.Config()
 		if err != nil {
-			return fmt.Errorf("getting cached model config: %w", err)
+			return "", fmt.Errorf("getting cached model config: %w", err)
 		}
 
+		c.warnMovedPins(reference, remoteDigest.String(), progressWriter)
 		err = progress.WriteSuccess(progressWriter, fmt.Sprintf("Using cached model: %s", cfg.GetSize()), oci.ModePull)
 		if err != nil {
 			c.log.Warn("Writing progress", "error", err)
@@ -400,58 +513,193 @@ func (c *Client) PullModel(ctx context.Context, reference string, progressWriter
 
 		// Ensure model has the correct tag
 		if err := c.store.AddTags(remoteDigest.String(), []string{reference}); err != nil {
-			return fmt.Errorf("tagging model: %w", err)
+			return "", fmt.Errorf("tagging model: %w", err)
 		}
-		return nil
+		return remoteDigest.String(), nil
+	} else if opts.Force {
+		c.log.Info("forcing pull from remote", logging.Model(reference), logging.Digest(remoteDigest.String()))
 	} else {
-		c.log.Info("model not found in local store, pulling from remote", "reference", utils.SanitizeForLog(reference))
+		c.log.Info("model not found in local store, pulling from remote", logging.Model(reference), logging.Digest(remoteDigest.String()))
 	}
 
 	// Model doesn't exist in local store or digests don't match, pull from remote
 
-	// Pass rangeSuccess to store.Write for resume detection
 	var writeOpts []store.WriteOption
+	if opts.Force {
+		writeOpts = append(writeOpts, store.WithOverwrite())
+	}
+	// Pass rangeSuccess to store.Write for resume detection
+	resumeOpts := writeOpts
 	if rangeSuccess != nil {
-		writeOpts = append(writeOpts, store.WithRangeSuccess(rangeSuccess))
+		resumeOpts = append(slices.Clip(writeOpts), store.WithRangeSuccess(rangeSuccess))
+	}
+	err = c.store.Write(remoteModel, []string{reference}, progressWriter, resumeOpts...)
+	var mismatch *oci.DigestMismatchError
+	if errors.As(err, &mismatch) {
+		// The layers that were verified are kept in the store, so writing the
+		// model again only downloads the corrupt layers, this time from scratch.
+		c.log.Warn("layer failed verification, downloading it again", logging.Model(reference), logging.Digest(mismatch.Expected.String()), "error", err)
+		if writeErr := progress.WriteWarning(progressWriter, fmt.Sprintf("Layer %s failed verification, downloading it again", mismatch.Expected), oci.ModePull); writeErr != nil {
+			c.log.Warn("Failed to write warning message", "error", writeErr)
+		}
+		remoteModel, err = registryClient.Model(freshCtx, reference)
+		if err != nil {
+			return "", fmt.Errorf("reading model from registry: %w", err)
+		}
+		err = c.store.Write(remoteModel, []string{reference}, progressWriter, writeOpts...)
 	}
-	if err = c.store.Write(remoteModel, []string{reference}, progressWriter, writeOpts...); err != nil {
+	if err != nil {
 		if writeErr := progress.WriteError(progressWriter, fmt.Sprintf("Error: %s", err.Error()), oci.ModePull); writeErr != nil {
 			c.log.Warn("Failed to write error message", "error", writeErr)
 		}
-		return fmt.Errorf("writing image to store: %w", err)
+		return "", fmt.Errorf("writing image to store: %w", err)
 	}
 
+	c.log.Info("successfully pulled model",
+		logging.Model(reference),
+		logging.Digest(remoteDigest.String()),
+		logging.Layers(len(layers)),
+		logging.TotalBytes(totalBytes),
+		logging.Duration(start),
+	)
+	c.warnMovedPins(reference, remoteDigest.String(), progressWriter)
 	if err := progress.WriteSuccess(progressWriter, "Model pulled successfully", oci.ModePull); err != nil {
 		c.log.Warn("Failed to write success message", "error", err)
 	}
 
-	return nil
+	return remoteDigest.String(), nil
+}
+
+// totalLayerSize returns the combined size of layers, skipping layers whose
+// size is unknown.
+func totalLayerSize(layers []oci.Layer) int64 {
+	var total int64
+	for _, layer := range layers {
+		if size, err := layer.Size(); err == nil {
+			total += size
+		}
+	}
+	return total
+}
+
+// IsUpToDate reports whether reference is stored locally as the model with the
+// given remote manifest digest. A model whose manifest was rewritten when
+// stored is compared by the digest it was pulled with.
+func (c *Client) IsUpToDate(reference string, remoteDigest oci.Hash) (bool, error) {
+	localModel, err := c.store.Read(reference)
+	if errors.Is(err, ErrModelNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking local model: %w", err)
+	}
+	localDigest, err := localModel.RemoteDigest()
+	if err != nil {
+		return false, fmt.Errorf("getting local model digest: %w", err)
+	}
+	return localDigest == remoteDigest, nil
+}
+
+// LoadModel loads the model from the reader to the store. Blobs already in the
+// store are skipped as the archive is read, so a load that failed part way,
+// e.g. because the stream was cut, resumes from the blobs it imported when
+// the archive is loaded again. The load stops once ctx is done, and the blobs
+// it wrote are then removed unless another model uses them, so that an aborted
+// load leaves nothing behind. They are removed as well if the archive is
+// rejected for its digest or size. The blobs of a failed load that is never
+// retried stay in the store until RepairStore, which MODEL_RUNNER_REPAIR_STORE
+// runs on startup, removes them along with other unreferenced blobs.
+func (c *Client) LoadModel(ctx context.Context, r io.Reader, progressWriter io.Writer) (string, error) {
+	return c.LoadModelWithOptions(ctx, r, progressWriter, LoadOptions{})
+}
+
+// LoadOptions configures LoadModelWithOptions.
+type LoadOptions struct {
+	// Digest, if set, is the digest the archive must match. The whole stream
+	// is read and verified before the model is committed, and a mismatching
+	// archive is discarded with an *oci.DigestMismatchError.
+	Digest oci.Hash
 }
 
-// LoadModel loads the model from the reader to the store
-func (c *Client) LoadModel(r io.Reader, progressWriter io.Writer) (string, error) {
+// LoadModelWithOptions loads the model from the reader to the store, as
+// LoadModel does, using the given options.
+func (c *Client) LoadModelWithOptions(ctx context.Context, r io.Reader, progressWriter io.Writer, opts LoadOptions) (_ string, err error) {
 	c.log.Info("Starting model load")
+	defer c.cache.invalidate()
+
+	var hasher hash.Hash
+	if opts.Digest != (oci.Hash{}) {
+		if hasher, err = oci.Hasher(opts.Digest.Algorithm); err != nil {
+			return "", fmt.Errorf("archive digest: %w", err)
+		}
+		r = io.TeeReader(r, hasher)
+	}
 
-	tr := tarball.NewReader(r)
+	// Blobs that were not already in the store, including the one being
+	// written when the load is interrupted.
+	var written []oci.Hash
+	defer func() {
+		if err == nil || len(written) == 0 || isResumableLoadError(ctx, err) {
+			return
+		}
+		if discardErr := c.store.DiscardBlobs(written); discardErr != nil {
+			c.log.Warn("Failed to remove blobs of interrupted model load", "error", discardErr)
+		}
+	}()
+
+	cr := &contextReader{ctx: ctx, r: r}
+	tr := tarball.NewReader(cr)
 	for {
 		diffID, err := tr.Next()
 		if errors.Is(err, io.EOF) {
 			break
 		}
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				c.log.Info("Model load cancelled", "error", ctxErr)
+				return "", fmt.Errorf("model load interrupted: %w", ctxErr)
+			}
 			if errors.Is(err, io.ErrUnexpectedEOF) {
 				c.log.Info("Model load interrupted (likely cancelled)", "error", utils.SanitizeForLog(err.Error()))
 				return "", fmt.Errorf("model load interrupted: %w", err)
 			}
 			return "", fmt.Errorf("reading blob from stream: %w", err)
 		}
+		if exists, err := c.store.HasBlob(diffID); err == nil && !exists {
+			written = append(written, diffID)
+		}
 		c.log.Info("loading blob", "diffID", diffID)
-		if err := c.store.WriteBlob(diffID, tr); err != nil {
+		imported, err := c.store.ImportBlob(diffID, tr)
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				c.log.Info("Model load cancelled", "error", ctxErr)
+				return "", fmt.Errorf("model load interrupted: %w", ctxErr)
+			}
 			return "", fmt.Errorf("writing blob: %w", err)
 		}
+		if !imported {
+			c.log.Info("skipped blob already in the store", "diffID", diffID)
+			continue
+		}
 		c.log.Info("loaded blob", "diffID", diffID)
 	}
 
+	if hasher != nil {
+		// The archive is only verified once the padding after its end has been
+		// read too.
+		if _, err := io.Copy(io.Discard, cr); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				c.log.Info("Model load cancelled", "error", ctxErr)
+				return "", fmt.Errorf("model load interrupted: %w", ctxErr)
+			}
+			return "", fmt.Errorf("reading end of stream: %w", err)
+		}
+		computed := oci.Hash{Algorithm: opts.Digest.Algorithm, Hex: hex.EncodeToString(hasher.Sum(nil))}
+		if computed != opts.Digest {
+			return "", fmt.Errorf("verifying archive: %w", &oci.DigestMismatchError{Expected: opts.Digest, Computed: computed})
+		}
+	}
+
 	manifest, digest, err := tr.Manifest()
 	if err != nil {
 		return "", fmt.Errorf("read manifest: %w", err)
@@ -469,6 +717,30 @@ func (c *Client) LoadModel(r io.Reader, progressWriter io.Writer) (string, error
 	return digest.String(), nil
 }
 
+// isResumableLoadError reports whether the blobs of a load that failed with
+// err are kept for the next attempt. They are not if the load was aborted, or
+// if the archive is not the expected one or too large, since loading it again
+// would fail the same way.
+func isResumableLoadError(ctx context.Context, err error) bool {
+	var mismatch *oci.DigestMismatchError
+	var tooLarge *http.MaxBytesError
+	return ctx.Err() == nil && !errors.As(err, &mismatch) && !errors.As(err, &tooLarge)
+}
+
+// contextReader fails reads once ctx is done, so that a long copy from r stops
+// at the next read after cancellation.
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
+
 // ListModels returns all available models
 func (c *Client) ListModels() ([]types.Model, error) {
 	c.log.Info("Listing available models")
@@ -481,7 +753,7 @@ func (c *Client) ListModels() ([]types.Model, error) {
 	result := make([]types.Model, 0, len(modelInfos))
 	for _, modelInfo := range modelInfos {
 		// Read the models
-		model, err := c.store.Read(modelInfo.ID)
+		model, err := c.readModel(modelInfo.ID)
 		if err != nil {
 			c.log.Warn("Failed to read model with ID", "model", modelInfo.ID, "error", err)
 			continue
@@ -497,7 +769,7 @@ func (c *Client) ListModels() ([]types.Model, error) {
 func (c *Client) GetModel(reference string) (types.Model, error) {
 	c.log.Info("getting model by reference", "reference", utils.SanitizeForLog(reference))
 	normalizedRef := c.normalizeModelName(reference)
-	model, err := c.store.Read(normalizedRef)
+	model, err := c.readModel(normalizedRef)
 	if err != nil {
 		c.log.Error("failed to get model", "error", err, "reference", utils.SanitizeForLog(reference))
 		return nil, fmt.Errorf("get model '%q': %w", utils.SanitizeForLog(reference), err)
@@ -510,7 +782,7 @@ func (c *Client) GetModel(reference string) (types.Model, error) {
 func (c *Client) IsModelInStore(reference string) (bool, error) {
 	c.log.Info("checking model by reference", "reference", utils.SanitizeForLog(reference))
 	normalizedRef := c.normalizeModelName(reference)
-	if _, err := c.store.Read(normalizedRef); errors.Is(err, ErrModelNotFound) {
+	if _, err := c.readModel(normalizedRef); errors.Is(err, ErrModelNotFound) {
 		return false, nil
 	} else if err != nil {
 		return false, err
@@ -525,8 +797,12 @@ type DeleteModelAction struct {
 
 type DeleteModelResponse []DeleteModelAction
 
-// DeleteModel deletes a model
+// DeleteModel deletes a model. A tag reference only removes that tag, even
+// when force is set, and the model is deleted once its last tag is removed. A
+// model with several tags is only deleted by ID or digest when force is set,
+// which removes all of its tags.
 func (c *Client) DeleteModel(reference string, force bool) (*DeleteModelResponse, error) {
+	defer c.cache.invalidate()
 	normalizedRef := c.normalizeModelName(reference)
 	mdl, err := c.store.Read(normalizedRef)
 	if err != nil {
@@ -581,22 +857,255 @@ func (c *Client) DeleteModel(reference string, force bool) (*DeleteModelResponse
 	return &resp, nil
 }
 
+// PruneModelsResponse describes the models removed by PruneModels.
+type PruneModelsResponse struct {
+	// Deleted lists the IDs of the deleted models.
+	Deleted []string `json:"Deleted"`
+	// SpaceReclaimed is the number of bytes freed on disk.
+	SpaceReclaimed int64 `json:"SpaceReclaimed"`
+}
+
+// PruneModels deletes every dangling model, i.e. every model without tags.
+func (c *Client) PruneModels() (*PruneModelsResponse, error) {
+	defer c.cache.invalidate()
+	c.log.Info("pruning dangling models")
+	deleted, reclaimed, err := c.store.Prune()
+	if err != nil {
+		c.log.Error("failed to prune models", "error", err)
+		return nil, fmt.Errorf("pruning models: %w", err)
+	}
+	c.log.Info("successfully pruned models", "count", len(deleted), "bytes", reclaimed)
+	return &PruneModelsResponse{Deleted: deleted, SpaceReclaimed: reclaimed}, nil
+}
+
+// RepairStoreResponse describes the inconsistencies fixed by RepairStore.
+type RepairStoreResponse struct {
+	// RemovedBlobs lists the digests of the blobs no model referenced.
+	RemovedBlobs []string `json:"RemovedBlobs"`
+	// RemovedIncomplete lists the digests of the stale incomplete downloads.
+	RemovedIncomplete []string `json:"RemovedIncomplete"`
+	// SpaceReclaimed is the number of bytes freed on disk.
+	SpaceReclaimed int64 `json:"SpaceReclaimed"`
+	// MissingBlobs maps the IDs of models whose blobs are missing to the
+	// missing digests. These models cannot be used until they are pulled
+	// again.
+	MissingBlobs map[string][]string `json:"MissingBlobs"`
+}
+
+// StaleDownloadAge is how long incomplete downloads are kept for resuming. It
+// is the age the store cleans them up at when it is opened, and the age to pass
+// to RepairStore.
+const StaleDownloadAge = store.StaleDownloadAge
+
+// RepairStore removes blobs no model references and incomplete downloads
+// older than staleAfter, as left behind by a crash during a write, and
+// reports models whose blobs are missing. It must not run concurrently with
+// pulls or other writes to the store.
+func (c *Client) RepairStore(staleAfter time.Duration) (*RepairStoreResponse, error) {
+	start := time.Now()
+	report, err := c.store.Repair(staleAfter)
+	for id, missing := range report.MissingBlobs {
+		c.log.Warn("model is missing blobs; pull it again to repair it", "id", id, "missing", missing)
+	}
+	c.log.Info("repaired model store",
+		"removedBlobs", len(report.RemovedBlobs),
+		"removedIncomplete", len(report.RemovedIncomplete),
+		"bytes", report.ReclaimedBytes,
+		"brokenModels", len(report.MissingBlobs),
+		"duration", time.Since(start))
+	resp := &RepairStoreResponse{
+		RemovedBlobs:      report.RemovedBlobs,
+		RemovedIncomplete: report.RemovedIncomplete,
+		SpaceReclaimed:    report.ReclaimedBytes,
+		MissingBlobs:      report.MissingBlobs,
+	}
+	if err != nil {
+		c.log.Error("failed to repair model store", "error", err)
+		return resp, fmt.Errorf("repairing store: %w", err)
+	}
+	return resp, nil
+}
+
+// VerifyModelResponse describes the blobs of a model checked by VerifyModel.
+type VerifyModelResponse struct {
+	// ID is the ID of the verified model.
+	ID string `json:"ID"`
+	// Verified lists the digests of the blobs whose content matches.
+	Verified []string `json:"Verified"`
+	// Corrupt lists the blobs whose content does not match their digest.
+	Corrupt []CorruptBlob `json:"Corrupt"`
+	// Missing lists the digests of the blobs missing from the store.
+	Missing []string `json:"Missing"`
+}
+
+// CorruptBlob is a blob whose content does not match its digest.
+type CorruptBlob struct {
+	// Digest is the digest the blob is stored under.
+	Digest string `json:"Digest"`
+	// Computed is the digest of the blob's content.
+	Computed string `json:"Computed"`
+}
+
+// VerifyModel hashes the blobs of the model reference refers to and checks
+// them against their digests. Corrupt and missing blobs are reported, not
+// removed; pulling the model again repairs them.
+func (c *Client) VerifyModel(reference string) (*VerifyModelResponse, error) {
+	c.log.Info("verifying model", "reference", utils.SanitizeForLog(reference))
+	report, err := c.store.VerifyModel(c.normalizeModelName(reference))
+	if err != nil {
+		return nil, fmt.Errorf("verify model '%q': %w", utils.SanitizeForLog(reference), err)
+	}
+	resp := &VerifyModelResponse{
+		ID:       report.ID,
+		Verified: report.Verified,
+		Missing:  report.Missing,
+	}
+	for _, mismatch := range report.Corrupt {
+		resp.Corrupt = append(resp.Corrupt, CorruptBlob{
+			Digest:   mismatch.Expected.String(),
+			Computed: mismatch.Computed.String(),
+		})
+	}
+	if !report.OK() {
+		c.log.Warn("model failed verification; pull it again to repair it",
+			"id", report.ID, "corrupt", len(report.Corrupt), "missing", len(report.Missing))
+	}
+	return resp, nil
+}
+
+// EvictModels deletes the least recently used models until the store takes up
+// at most maxBytes. Models whose ID keep reports true for are never evicted.
+// It returns the IDs of the evicted models.
+func (c *Client) EvictModels(maxBytes int64, keep func(id string) bool) ([]string, error) {
+	defer c.cache.invalidate()
+	evicted, err := c.store.EvictLRU(maxBytes, func(entry store.IndexEntry) bool {
+		return keep != nil && keep(entry.ID)
+	})
+	ids := make([]string, 0, len(evicted))
+	for _, m := range evicted {
+		c.log.Info("evicted least recently used model", "id", m.ID, "tags", m.Tags, "maxStoreBytes", maxBytes)
+		ids = append(ids, m.ID)
+	}
+	if err != nil {
+		c.log.Error("failed to evict models", "error", err)
+		return ids, fmt.Errorf("evicting models: %w", err)
+	}
+	return ids, nil
+}
+
+// MarkModelUsed records that the model with the given reference was used now.
+func (c *Client) MarkModelUsed(reference string) error {
+	model, err := c.readModel(c.normalizeModelName(reference))
+	if err != nil {
+		return fmt.Errorf("get model '%q': %w", utils.SanitizeForLog(reference), err)
+	}
+	id, err := model.ID()
+	if err != nil {
+		return fmt.Errorf("get model ID: %w", err)
+	}
+	return c.store.Touch(id)
+}
+
+// LastUsedTimes returns the time each model in the store was last pulled or
+// used, keyed by model ID.
+func (c *Client) LastUsedTimes() (map[string]time.Time, error) {
+	times, err := c.store.LastUsedTimes()
+	if err != nil {
+		return nil, fmt.Errorf("reading model access times: %w", err)
+	}
+	return times, nil
+}
+
 // Tag adds a tag to a model
 func (c *Client) Tag(source string, target string) error {
 	c.log.Info("tagging model", "source", source, "target", utils.SanitizeForLog(target))
 	normalizedSource := c.normalizeModelName(source)
 	normalizedTarget := c.normalizeModelName(target)
+	defer c.cache.invalidate()
 	return c.store.AddTags(normalizedSource, []string{normalizedTarget})
 }
 
+// TagOptions configures TagWithOptions.
+type TagOptions struct {
+	// PinnedFrom, if set, pins the target to the model source refers to and
+	// records PinnedFrom as the reference it was pinned from. Pulls of that
+	// reference that resolve to another model then warn that the tag did not
+	// move.
+	PinnedFrom string
+}
+
+// TagWithOptions tags the model source refers to with target using the given
+// options.
+func (c *Client) TagWithOptions(source string, target string, opts TagOptions) error {
+	if opts.PinnedFrom == "" {
+		return c.Tag(source, target)
+	}
+	c.log.Info("tagging model with pin", "source", source, "target", utils.SanitizeForLog(target), "pinnedFrom", utils.SanitizeForLog(opts.PinnedFrom))
+	normalizedSource := c.normalizeModelName(source)
+	normalizedTarget := c.normalizeModelName(target)
+	defer c.cache.invalidate()
+	return c.store.PinTag(normalizedSource, normalizedTarget, c.normalizeModelName(opts.PinnedFrom))
+}
+
+// warnMovedPins warns about the tags pinned from reference to models other
+// than id, which a pull of reference has just resolved to.
+func (c *Client) warnMovedPins(reference string, id string, progressWriter io.Writer) {
+	pinned, err := c.store.PinnedFrom(reference)
+	if err != nil {
+		c.log.Warn("Failed to read pinned tags", logging.Model(reference), "error", err)
+		return
+	}
+	for _, tag := range slices.Sorted(maps.Keys(pinned)) {
+		if pinned[tag] == id {
+			continue
+		}
+		c.log.Warn("source of pinned tag has moved", "tag", utils.SanitizeForLog(tag), logging.Model(reference), "pinned", pinned[tag], logging.Digest(id))
+		msg := fmt.Sprintf("%s now resolves to %s, but %s stays pinned to %s", reference, id, tag, pinned[tag])
+		if err := progress.WriteWarning(progressWriter, msg, oci.ModePull); err != nil {
+			c.log.Warn("Failed to write warning message", "error", err)
+		}
+	}
+}
+
 // PushModel pushes a tagged model from the content store to the registry.
 func (c *Client) PushModel(ctx context.Context, tag string, progressWriter io.Writer, bearerToken ...string) (err error) {
+	var opts PushOptions
+	if len(bearerToken) > 0 {
+		opts.BearerToken = bearerToken[0]
+	}
+	return c.PushModelWithOptions(ctx, tag, progressWriter, opts)
+}
+
+// Compression identifies how model layers are compressed on push.
+type Compression string
+
+const (
+	// CompressionNone pushes layers uncompressed.
+	CompressionNone Compression = ""
+	// CompressionZstd pushes GGUF layers zstd-compressed. Layers are
+	// decompressed transparently on pull.
+	CompressionZstd Compression = "zstd"
+)
+
+// PushOptions configures PushModelWithOptions.
+type PushOptions struct {
+	// BearerToken is an optional bearer token for registry authentication.
+	BearerToken string
+	// Compression selects the layer compression to use.
+	Compression Compression
+}
+
+// PushModelWithOptions pushes a tagged model from the content store to the
+// registry using the given options.
+func (c *Client) PushModelWithOptions(ctx context.Context, tag string, progressWriter io.Writer, opts PushOptions) (err error) {
 	originalReference := tag
 	normalizedRef := c.normalizeModelName(tag)
+	token := opts.BearerToken
 
-	var token string
-	if len(bearerToken) > 0 && bearerToken[0] != "" {
-		token = bearerToken[0]
+	switch opts.Compression {
+	case CompressionNone, CompressionZstd:
+	default:
+		return fmt.Errorf("%w: %q", ErrUnsupportedCompression, utils.SanitizeForLog(string(opts.Compression)))
 	}
 
 	if IsHuggingFaceReference(originalReference) {
@@ -613,21 +1122,45 @@ func (c *Client) PushModel(ctx context.Context, tag string, progressWriter io.Wr
 		return fmt.Errorf("new tag: %w", err)
 	}
 
-	mdl, err := c.store.Read(normalizedRef)
+	var mdl types.ModelArtifact
+	mdl, err = c.store.Read(normalizedRef)
 	if err != nil {
 		return fmt.Errorf("reading model: %w", err)
 	}
 
-	c.log.Info("pushing model", "tag", utils.SanitizeForLog(tag, -1))
+	if opts.Compression == CompressionZstd {
+		tmpDir, err := os.MkdirTemp("", "model-push-*")
+		if err != nil {
+			return fmt.Errorf("create compression directory: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		compressed, cleanup, err := mutate.CompressLayers(mdl, tmpDir, types.MediaTypeGGUF)
+		if err != nil {
+			return fmt.Errorf("compressing model layers: %w", err)
+		}
+		defer cleanup()
+		mdl = compressed
+	}
+
+	start := time.Now()
+	c.log.Info("pushing model", logging.Model(tag), "compression", string(opts.Compression))
 	if err := target.Write(ctx, mdl, progressWriter); err != nil {
-		c.log.Error("failed to push image", "error", err, "reference", tag)
+		c.log.Error("failed to push image", logging.Model(tag), logging.Duration(start), "error", err)
 		if writeErr := progress.WriteError(progressWriter, fmt.Sprintf("Error: %s", err.Error()), oci.ModePush); writeErr != nil {
 			c.log.Warn("Failed to write error message", "error", writeErr)
 		}
 		return fmt.Errorf("pushing image: %w", err)
 	}
 
-	c.log.Info("successfully pushed model", "tag", tag)
+	pushed := []any{logging.Model(tag), logging.Duration(start)}
+	if digest, err := mdl.Digest(); err == nil {
+		pushed = append(pushed, logging.Digest(digest.String()))
+	}
+	if layers, err := mdl.Layers(); err == nil {
+		pushed = append(pushed, logging.Layers(len(layers)), logging.TotalBytes(totalLayerSize(layers)))
+	}
+	c.log.Info("successfully pushed model", pushed...)
 	if err := progress.WriteSuccess(progressWriter, "Model pushed successfully", oci.ModePush); err != nil {
 		c.log.Warn("Failed to write success message", "error", err)
 	}
@@ -636,7 +1169,7 @@ func (c *Client) PushModel(ctx context.Context, tag string, progressWriter io.Wr
 }
 
 func (c *Client) pushNativeHuggingFace(ctx context.Context, reference, normalizedRef string, progressWriter io.Writer, token string) error {
-	repo, _, _ := parseHFReference(reference)
+	repo := parseHFReference(reference).Repo
 	c.log.Info("Pushing native HuggingFace model", "repo", utils.SanitizeForLog(repo))
 
 	if progressWriter != nil {
@@ -659,6 +1192,7 @@ func (c *Client) pushNativeHuggingFace(ctx context.Context, reference, normalize
 
 	hfOpts := []huggingface.ClientOption{
 		huggingface.WithUserAgent(registry.DefaultUserAgent),
+		huggingface.WithTransport(c.hfTransport),
 	}
 	if token != "" {
 		hfOpts = append(hfOpts, huggingface.WithToken(token))
@@ -698,6 +1232,7 @@ func (c *Client) pushNativeHuggingFace(ctx context.Context, reference, normalize
 // The layers must already exist in the store.
 func (c *Client) WriteLightweightModel(mdl types.ModelArtifact, tags []string) error {
 	c.log.Info("Writing lightweight model variant")
+	defer c.cache.invalidate()
 	normalizedTags := make([]string, len(tags))
 	for i, tag := range tags {
 		normalizedTags[i] = c.normalizeModelName(tag)
@@ -707,6 +1242,7 @@ func (c *Client) WriteLightweightModel(mdl types.ModelArtifact, tags []string) e
 
 func (c *Client) ResetStore() error {
 	c.log.Info("Resetting store")
+	defer c.cache.invalidate()
 	if err := c.store.Reset(); err != nil {
 		c.log.Error("failed to reset store", "error", err)
 		return fmt.Errorf("resetting store: %w", err)
@@ -739,6 +1275,9 @@ func (c *Client) ExportModel(reference string, w io.Writer) error {
 
 type RepackageOptions struct {
 	ContextSize *uint64
+	// Force overwrites the target tag even if it already points to a
+	// different model.
+	Force bool
 }
 
 func (c *Client) RepackageModel(sourceRef string, targetRef string, opts RepackageOptions) error {
@@ -746,6 +1285,7 @@ func (c *Client) RepackageModel(sourceRef string, targetRef string, opts Repacka
 
 	normalizedSource := c.normalizeModelName(sourceRef)
 	normalizedTarget := c.normalizeModelName(targetRef)
+	defer c.cache.invalidate()
 
 	mdl, err := c.store.Read(normalizedSource)
 	if err != nil {
@@ -758,6 +1298,12 @@ func (c *Client) RepackageModel(sourceRef string, targetRef string, opts Repacka
 		modifiedModel = mutate.ContextSize(modifiedModel, int32(*opts.ContextSize))
 	}
 
+	if !opts.Force {
+		if err := c.checkTagAvailable(normalizedTarget, modifiedModel); err != nil {
+			return err
+		}
+	}
+
 	if err := c.store.WriteLightweight(modifiedModel, []string{normalizedTarget}); err != nil {
 		c.log.Error("failed to write repackaged model", "error", err, "target", utils.SanitizeForLog(targetRef))
 		return fmt.Errorf("write repackaged model: %w", err)
@@ -767,6 +1313,88 @@ func (c *Client) RepackageModel(sourceRef string, targetRef string, opts Repacka
 	return nil
 }
 
+// primaryLayerMediaTypes are the media types of the layers holding model
+// weights, which DropLayer refuses to drop.
+var primaryLayerMediaTypes = []oci.MediaType{
+	types.MediaTypeGGUF,
+	types.MediaTypeSafetensors,
+	types.MediaTypeDDUF,
+}
+
+// DropLayer rewrites the model reference refers to without its layers of
+// media type mt, e.g. to drop the multimodal projector of a model that is only
+// used for text. The tags and pins of the model move to the rewritten model,
+// and the original model is deleted along with the blobs no other model
+// references. It returns the ID of the rewritten model.
+func (c *Client) DropLayer(reference string, mt oci.MediaType) (string, error) {
+	c.log.Info("dropping model layer", "reference", utils.SanitizeForLog(reference), "mediaType", utils.SanitizeForLog(string(mt)))
+	if slices.Contains(primaryLayerMediaTypes, mt) {
+		return "", fmt.Errorf("dropping %s layers: %w", utils.SanitizeForLog(string(mt)), ErrPrimaryLayer)
+	}
+
+	normalizedRef := c.normalizeModelName(reference)
+	defer c.cache.invalidate()
+
+	mdl, err := c.store.Read(normalizedRef)
+	if err != nil {
+		return "", fmt.Errorf("get model '%q': %w", utils.SanitizeForLog(reference), err)
+	}
+	id, err := mdl.ID()
+	if err != nil {
+		return "", fmt.Errorf("get model ID: %w", err)
+	}
+	manifest, err := mdl.Manifest()
+	if err != nil {
+		return "", fmt.Errorf("get manifest: %w", err)
+	}
+	if !slices.ContainsFunc(manifest.Layers, func(layer oci.Descriptor) bool { return layer.MediaType == mt }) {
+		return "", fmt.Errorf("model '%q' has no %s layer: %w", utils.SanitizeForLog(reference), utils.SanitizeForLog(string(mt)), ErrLayerNotFound)
+	}
+
+	dropped := mutate.DropLayers(mdl, mt)
+	droppedID, err := dropped.ID()
+	if err != nil {
+		return "", fmt.Errorf("get rewritten model ID: %w", err)
+	}
+	if err := c.store.WriteLightweight(dropped, mdl.Tags()); err != nil {
+		return "", fmt.Errorf("write rewritten model: %w", err)
+	}
+	for tag, source := range mdl.Pins() {
+		if err := c.store.PinTag(droppedID, tag, source); err != nil {
+			return "", fmt.Errorf("pin tag %q: %w", utils.SanitizeForLog(tag), err)
+		}
+	}
+	if _, _, err := c.store.Delete(id); err != nil {
+		return "", fmt.Errorf("delete original model: %w", err)
+	}
+
+	c.log.Info("successfully dropped model layer", "reference", utils.SanitizeForLog(reference), "id", droppedID)
+	return droppedID, nil
+}
+
+// checkTagAvailable returns ErrConflict if tag already points to a model
+// other than mdl.
+func (c *Client) checkTagAvailable(tag string, mdl types.ModelArtifact) error {
+	existing, err := c.store.Read(tag)
+	if errors.Is(err, ErrModelNotFound) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("reading existing model for tag %q: %w", utils.SanitizeForLog(tag), err)
+	}
+	existingID, err := existing.ID()
+	if err != nil {
+		return fmt.Errorf("getting existing model ID: %w", err)
+	}
+	id, err := mdl.ID()
+	if err != nil {
+		return fmt.Errorf("getting model ID: %w", err)
+	}
+	if existingID != id {
+		return fmt.Errorf("tag %q already points to a different model (must be forced): %w", utils.SanitizeForLog(tag), ErrConflict)
+	}
+	return nil
+}
+
 // GetBundle returns a types.Bundle containing the model, creating one as necessary
 func (c *Client) GetBundle(ref string) (types.ModelBundle, error) {
 	normalizedRef := c.normalizeModelName(ref)
@@ -778,22 +1406,13 @@ func checkCompat(image types.ModelArtifact, log *slog.Logger, reference string,
 	if err != nil {
 		return err
 	}
-	if manifest.Config.MediaType != types.MediaTypeModelConfigV01 &&
-		manifest.Config.MediaType != types.MediaTypeModelConfigV02 &&
-		manifest.Config.MediaType != modelpack.MediaTypeModelConfigV1 {
-		return fmt.Errorf(
-			"config type %q is not supported (supported: %q, %q, %q)"+
-				" - try upgrading: %w",
-			manifest.Config.MediaType,
-			types.MediaTypeModelConfigV01,
-			types.MediaTypeModelConfigV02,
-			modelpack.MediaTypeModelConfigV1,
-			ErrUnsupportedMediaType,
-		)
+	raw, err := image.RawConfigFile()
+	if err != nil {
+		return fmt.Errorf("reading model config: %w", err)
 	}
-
-	// Check if the model format is supported
-	config, err := image.Config()
+	// Configs from a newer minor version are read by ignoring unknown fields;
+	// only an incompatible major version is rejected.
+	config, err := partial.ParseConfig(raw, manifest.Config.MediaType)
 	if err != nil {
 		return fmt.Errorf("reading model config: %w", err)
 	}
@@ -811,42 +1430,67 @@ func IsHuggingFaceReference(reference string) bool {
 		strings.HasPrefix(reference, "hf.co/")
 }
 
-// parseHFReference extracts repo, revision, and tag from a HF reference
-// e.g., "huggingface.co/org/model:revision" -> ("org/model", "main", "revision")
-// e.g., "hf.co/org/model:latest" -> ("org/model", "main", "latest")
-// e.g., "hf.co/org/model:Q4_K_M" -> ("org/model", "main", "Q4_K_M")
-// The tag is used for GGUF quantization selection, while revision is always "main" for HuggingFace
-func parseHFReference(reference string) (repo, revision, tag string) {
-	// Remove registry prefix (handle both hf.co and huggingface.co)
-	ref := strings.TrimPrefix(reference, "huggingface.co/")
-	ref = strings.TrimPrefix(ref, "hf.co/")
-
-	// Split by colon to get tag
-	parts := strings.SplitN(ref, ":", 2)
-	repo = parts[0]
+// hfReference is a HuggingFace model reference split into the parts the Hub
+// API addresses separately.
+type hfReference struct {
+	// Repo is the repository, e.g. "org/model".
+	Repo string
+	// Path is the directory within the repository holding the model, for
+	// references with more than two path components.
+	Path string
+	// Revision is the git revision, "main" unless given after "@".
+	Revision string
+	// Tag is used for GGUF quantization selection.
+	Tag string
+}
 
-	// Default tag is "latest"
-	tag = "latest"
-	if len(parts) == 2 && parts[1] != "" {
-		tag = parts[1]
+// parseHFReference splits a HF reference of the form
+// "hf.co/org/model[/path...][:tag][@revision]", keeping the case of the
+// repository and path as the Hub API requires:
+// e.g., "huggingface.co/org/model:Q4_K_M" -> {Repo: "org/model", Revision: "main", Tag: "Q4_K_M"}
+// e.g., "hf.co/org/model@v1.0" -> {Repo: "org/model", Revision: "v1.0", Tag: "latest"}
+// e.g., "hf.co/org/model/gguf:Q8_0" -> {Repo: "org/model", Path: "gguf", Revision: "main", Tag: "Q8_0"}
+func parseHFReference(ref string) hfReference {
+	m := reference.Parse(ref)
+	hf := hfReference{Revision: "main", Tag: "latest"}
+	if m.Tag != "" {
+		hf.Tag = m.Tag
+	}
+	if m.Digest != "" {
+		hf.Revision = m.Digest
 	}
 
-	// Revision is always "main" for HuggingFace repos
-	// (the tag is used for quantization selection, not git revision)
-	revision = "main"
+	repoPath := m.Name
+	if m.Org != "" {
+		repoPath = m.Org + "/" + m.Name
+	}
+	parts := strings.SplitN(repoPath, "/", 3)
+	hf.Repo = strings.Join(parts[:min(len(parts), 2)], "/")
+	if len(parts) == 3 {
+		hf.Path = parts[2]
+	}
+	return hf
+}
 
-	return repo, revision, tag
+// checkHFTag rejects HF references whose tag has the form revisions are stored
+// under, which would name the same model as the revision.
+func checkHFTag(ref string) error {
+	if m := reference.Parse(ref); m.HasRevisionTag() {
+		return fmt.Errorf("%w: tag %q is reserved for Hugging Face revisions, use %s@<revision> instead", ErrInvalidReference, m.Tag, reference.Model{Registry: m.Registry, Org: m.Org, Name: m.Name})
+	}
+	return nil
 }
 
 // pullNativeHuggingFace pulls a native HuggingFace repository (non-OCI format)
 // This is used when the model is stored as raw files (safetensors) on HuggingFace Hub
-func (c *Client) pullNativeHuggingFace(ctx context.Context, reference string, progressWriter io.Writer, token string) error {
-	repo, revision, tag := parseHFReference(reference)
-	c.log.Info("Pulling native HuggingFace model", "repo", utils.SanitizeForLog(repo), "revision", utils.SanitizeForLog(revision), "tag", utils.SanitizeForLog(tag))
+func (c *Client) pullNativeHuggingFace(ctx context.Context, reference string, progressWriter io.Writer, token string, maxBytes int64) (string, error) {
+	hf := parseHFReference(reference)
+	c.log.Info("Pulling native HuggingFace model", "repo", utils.SanitizeForLog(hf.Repo), "path", utils.SanitizeForLog(hf.Path), "revision", utils.SanitizeForLog(hf.Revision), "tag", utils.SanitizeForLog(hf.Tag))
 
 	// Create HuggingFace client
 	hfOpts := []huggingface.ClientOption{
 		huggingface.WithUserAgent(registry.DefaultUserAgent),
+		huggingface.WithTransport(c.hfTransport),
 	}
 	if token != "" {
 		hfOpts = append(hfOpts, huggingface.WithToken(token))
@@ -856,27 +1500,31 @@ func (c *Client) pullNativeHuggingFace(ctx context.Context, reference string, pr
 	// Create temp directory for downloads
 	tempDir, err := os.MkdirTemp("", "hf-model-*")
 	if err != nil {
-		return fmt.Errorf("create temp dir: %w", err)
+		return "", fmt.Errorf("create temp dir: %w", err)
 	}
 	defer os.RemoveAll(tempDir)
 
 	// Build model from HuggingFace repository
 	// The tag is used for GGUF quantization selection (e.g., "Q4_K_M", "Q8_0")
-	model, err := huggingface.BuildModel(ctx, hfClient, repo, revision, tag, tempDir, progressWriter)
+	model, err := huggingface.BuildModel(ctx, hfClient, hf.Repo, hf.Revision, hf.Path, hf.Tag, tempDir, progressWriter, maxBytes)
 	if err != nil {
 		// Convert HuggingFace errors to registry errors for consistent handling
 		var authErr *huggingface.AuthError
 		var notFoundErr *huggingface.NotFoundError
 		if errors.As(err, &authErr) {
-			return registry.ErrUnauthorized
+			return "", registry.ErrUnauthorized
 		}
 		if errors.As(err, &notFoundErr) {
-			return registry.ErrModelNotFound
+			return "", registry.ErrModelNotFound
+		}
+		if errors.Is(err, ErrModelTooLarge) {
+			// Nothing was written yet, so the caller can report the error.
+			return "", fmt.Errorf("build model from HuggingFace: %w", err)
 		}
 		if writeErr := progress.WriteError(progressWriter, fmt.Sprintf("Error: %s", err.Error()), oci.ModePull); writeErr != nil {
 			c.log.Warn("Failed to write error message", "error", writeErr)
 		}
-		return fmt.Errorf("build model from HuggingFace: %w", err)
+		return "", fmt.Errorf("build model from HuggingFace: %w", err)
 	}
 
 	// Write model to store with normalized tag
@@ -886,12 +1534,12 @@ func (c *Client) pullNativeHuggingFace(ctx context.Context, reference string, pr
 		if writeErr := progress.WriteError(progressWriter, fmt.Sprintf("Error: %s", err.Error()), oci.ModePull); writeErr != nil {
 			c.log.Warn("Failed to write error message", "error", writeErr)
 		}
-		return fmt.Errorf("writing model to store: %w", err)
+		return "", fmt.Errorf("writing model to store: %w", err)
 	}
 
 	if err := progress.WriteSuccess(progressWriter, "Model pulled successfully", oci.ModePull); err != nil {
 		c.log.Warn("Failed to write success message", "error", err)
 	}
 
-	return nil
+	return model.ID()
 }
@@ -9,11 +9,15 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -25,6 +29,7 @@ import (
 	"github.com/docker/model-runner/pkg/distribution/oci/remote"
 	mdregistry "github.com/docker/model-runner/pkg/distribution/registry"
 	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
 var (
@@ -136,7 +141,7 @@ func TestClientPullModel(t *testing.T) {
 
 		// Verify progress output
 		progressOutput := progressBuffer.String()
-		if !strings.Contains(progressOutput, "Using cached model") && !strings.Contains(progressOutput, "Downloading") {
+		if !strings.Contains(progressOutput, "Model is up to date") && !strings.Contains(progressOutput, "Downloading") {
 			t.Errorf("Progress output doesn't contain expected text: got %q", progressOutput)
 		}
 
@@ -559,6 +564,36 @@ func TestClientPullModel(t *testing.T) {
 		}
 	})
 
+	t.Run("pull newer minor version", func(t *testing.T) {
+		newMdl := testutil.NewGGUFArtifactWithConfigMediaType(
+			t,
+			testGGUFFile,
+			"application/vnd.docker.ai.model.config.v0.99+json",
+		)
+		testTag := registryHost + "/newer-minor-test/model:v1.0.0"
+		ref, err := reference.ParseReference(testTag)
+		if err != nil {
+			t.Fatalf("Failed to parse reference: %v", err)
+		}
+		if err := remote.Write(ref, newMdl, nil, remote.WithPlainHTTP(true)); err != nil {
+			t.Fatalf("Failed to push model: %v", err)
+		}
+		if err := client.PullModel(t.Context(), testTag, nil); err != nil {
+			t.Fatalf("Expected newer minor version to be pulled, got %v", err)
+		}
+		mdl, err := client.GetModel(testTag)
+		if err != nil {
+			t.Fatalf("Failed to get pulled model: %v", err)
+		}
+		cfg, err := mdl.Config()
+		if err != nil {
+			t.Fatalf("Failed to read pulled model config: %v", err)
+		}
+		if cfg.GetFormat() != types.FormatGGUF {
+			t.Errorf("Expected format %q, got %q", types.FormatGGUF, cfg.GetFormat())
+		}
+	})
+
 	t.Run("pull safetensors model returns error on unsupported platforms", func(t *testing.T) {
 		safetensorsTempDir := t.TempDir()
 
@@ -706,6 +741,94 @@ func TestClientPullModel(t *testing.T) {
 	})
 }
 
+// newBlobCountingRegistry starts a test registry that counts blob downloads.
+func newBlobCountingRegistry(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var blobGets atomic.Int32
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			blobGets.Add(1)
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &blobGets
+}
+
+func TestPullModelUpToDate(t *testing.T) {
+	server, blobGets := newBlobCountingRegistry(t)
+	registryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := registryURL.Host + "/testmodel:uptodate"
+
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if err := client.PushModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	t.Run("up to date", func(t *testing.T) {
+		blobGets.Store(0)
+		var progressBuffer bytes.Buffer
+		if err := client.PullModel(t.Context(), tag, &progressBuffer); err != nil {
+			t.Fatalf("Failed to pull model: %v", err)
+		}
+		if !strings.Contains(progressBuffer.String(), "Model is up to date") {
+			t.Errorf("Expected up to date message, got %q", progressBuffer.String())
+		}
+		if n := blobGets.Load(); n != 0 {
+			t.Errorf("Expected no blob downloads, got %d", n)
+		}
+	})
+
+	t.Run("force", func(t *testing.T) {
+		blobGets.Store(0)
+		var progressBuffer bytes.Buffer
+		if _, err := client.PullModelWithOptions(t.Context(), tag, &progressBuffer, PullOptions{Force: true}); err != nil {
+			t.Fatalf("Failed to pull model: %v", err)
+		}
+		if strings.Contains(progressBuffer.String(), "Model is up to date") {
+			t.Errorf("Expected forced pull to skip the up to date check, got %q", progressBuffer.String())
+		}
+		if !strings.Contains(progressBuffer.String(), "Model pulled successfully") {
+			t.Errorf("Expected pull success message, got %q", progressBuffer.String())
+		}
+		if n := blobGets.Load(); n == 0 {
+			t.Error("Expected forced pull to download blobs")
+		}
+
+		// The re-downloaded model should still be intact.
+		model, err := client.GetModel(tag)
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		paths, err := model.GGUFPaths()
+		if err != nil {
+			t.Fatalf("Failed to get model paths: %v", err)
+		}
+		got, err := os.ReadFile(paths[0])
+		if err != nil {
+			t.Fatalf("Failed to read pulled model: %v", err)
+		}
+		want, err := os.ReadFile(testGGUFFile)
+		if err != nil {
+			t.Fatalf("Failed to read GGUF file: %v", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Error("Pulled model content doesn't match original")
+		}
+	})
+}
+
 func TestClientGetModel(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -1008,6 +1131,545 @@ func TestPush(t *testing.T) {
 	}
 }
 
+func TestPullDiscardsOversizedIncompleteFile(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Create client with plainHTTP for test registry
+	client, err := newTestClient(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Create a test registry that records Range requests
+	var mu sync.Mutex
+	var ranges []string
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rng := r.Header.Get("Range"); rng != "" {
+			mu.Lock()
+			ranges = append(ranges, rng)
+			mu.Unlock()
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/oversized-incomplete-test/model:v1.0.0"
+
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if err := client.PushModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	model, err := client.GetModel(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	ggufPaths, err := model.GGUFPaths()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF path: %v", err)
+	}
+	if len(ggufPaths) != 1 {
+		t.Fatalf("Unexpected number of model files: %d", len(ggufPaths))
+	}
+	ggufPath := ggufPaths[0]
+	originalContent, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read GGUF file: %v", err)
+	}
+
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+
+	// Leave behind an incomplete file larger than the remote layer
+	incompletePath := ggufPath + ".incomplete"
+	oversized := append(bytes.Clone(originalContent), []byte("trailing garbage")...)
+	if err := os.WriteFile(incompletePath, oversized, 0644); err != nil {
+		t.Fatalf("Failed to create incomplete file: %v", err)
+	}
+
+	if err := client.PullModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// The incomplete file should have been discarded rather than resumed
+	mu.Lock()
+	defer mu.Unlock()
+	if len(ranges) != 0 {
+		t.Errorf("Expected no Range requests, got %v", ranges)
+	}
+	if _, err := os.Stat(incompletePath); !os.IsNotExist(err) {
+		t.Errorf("Incomplete file still exists after successful pull: %s", incompletePath)
+	}
+
+	pulledContent, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read pulled GGUF file: %v", err)
+	}
+	if !bytes.Equal(pulledContent, originalContent) {
+		t.Errorf("Pulled content doesn't match original content")
+	}
+}
+
+func TestPullRestartsWhenRangeIgnored(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Create client with plainHTTP for test registry
+	client, err := newTestClient(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Create a test registry that records and then ignores Range requests, as
+	// servers without range support do
+	var mu sync.Mutex
+	var ranges []string
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if rng := r.Header.Get("Range"); rng != "" {
+			mu.Lock()
+			ranges = append(ranges, rng)
+			mu.Unlock()
+			r.Header.Del("Range")
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/range-ignored-test/model:v1.0.0"
+
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if err := client.PushModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	model, err := client.GetModel(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	ggufPaths, err := model.GGUFPaths()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF path: %v", err)
+	}
+	if len(ggufPaths) != 1 {
+		t.Fatalf("Unexpected number of model files: %d", len(ggufPaths))
+	}
+	ggufPath := ggufPaths[0]
+	originalContent, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read GGUF file: %v", err)
+	}
+
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+
+	// Leave behind the first half of the layer as an incomplete download
+	incompletePath := ggufPath + ".incomplete"
+	if err := os.WriteFile(incompletePath, originalContent[:len(originalContent)/2], 0644); err != nil {
+		t.Fatalf("Failed to create incomplete file: %v", err)
+	}
+
+	if err := client.PullModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// A resume was attempted, but the full response must replace the partial
+	// file rather than be appended to it
+	mu.Lock()
+	defer mu.Unlock()
+	if want := fmt.Sprintf("bytes=%d-", len(originalContent)/2); !slices.Contains(ranges, want) {
+		t.Errorf("Expected a %q Range request, got %v", want, ranges)
+	}
+	if _, err := os.Stat(incompletePath); !os.IsNotExist(err) {
+		t.Errorf("Incomplete file still exists after successful pull: %s", incompletePath)
+	}
+
+	pulledContent, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read pulled GGUF file: %v", err)
+	}
+	if !bytes.Equal(pulledContent, originalContent) {
+		t.Errorf("Pulled content doesn't match original content")
+	}
+}
+
+// pushForPullTest pushes the test GGUF model to the registry served by server
+// and removes it from the store again. It returns the tag, the path the GGUF
+// layer is stored at and its content.
+func pushForPullTest(t *testing.T, client *Client, server *httptest.Server, repo string) (string, string, []byte) {
+	t.Helper()
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/" + repo + ":v1.0.0"
+
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if err := client.PushModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	model, err := client.GetModel(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	ggufPaths, err := model.GGUFPaths()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF path: %v", err)
+	}
+	if len(ggufPaths) != 1 {
+		t.Fatalf("Unexpected number of model files: %d", len(ggufPaths))
+	}
+	content, err := os.ReadFile(ggufPaths[0])
+	if err != nil {
+		t.Fatalf("Failed to read GGUF file: %v", err)
+	}
+
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+	return tag, ggufPaths[0], content
+}
+
+func TestPullRetriesCorruptResumedLayer(t *testing.T) {
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Create a test registry that records Range requests and layer downloads
+	var mu sync.Mutex
+	var ranges []string
+	var layerGets atomic.Int32
+	var layerPath atomic.Value
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == layerPath.Load() {
+			layerGets.Add(1)
+		}
+		if rng := r.Header.Get("Range"); rng != "" {
+			mu.Lock()
+			ranges = append(ranges, rng)
+			mu.Unlock()
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	tag, ggufPath, originalContent := pushForPullTest(t, client, server, "corrupt-resume-test/model")
+	layerDigest, _, err := oci.SHA256(bytes.NewReader(originalContent))
+	if err != nil {
+		t.Fatalf("Failed to compute layer digest: %v", err)
+	}
+	layerPath.Store("/v2/corrupt-resume-test/model/blobs/" + layerDigest.String())
+
+	// Leave behind a corrupt first half of the layer as an incomplete download
+	corrupt := bytes.Clone(originalContent[:len(originalContent)/2])
+	for i := range corrupt {
+		corrupt[i] ^= 0xff
+	}
+	incompletePath := ggufPath + ".incomplete"
+	if err := os.WriteFile(incompletePath, corrupt, 0644); err != nil {
+		t.Fatalf("Failed to create incomplete file: %v", err)
+	}
+
+	var progressBuf bytes.Buffer
+	if err := client.PullModel(t.Context(), tag, &progressBuf); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// The resumed download fails verification and the layer is downloaded
+	// again from scratch
+	mu.Lock()
+	defer mu.Unlock()
+	if want := fmt.Sprintf("bytes=%d-", len(corrupt)); !slices.Equal(ranges, []string{want}) {
+		t.Errorf("Expected a single %q Range request, got %v", want, ranges)
+	}
+	if got := layerGets.Load(); got != 2 {
+		t.Errorf("Expected the layer to be downloaded twice, got %d", got)
+	}
+	if want := fmt.Sprintf("Layer %s failed verification, downloading it again", layerDigest); !strings.Contains(progressBuf.String(), want) {
+		t.Errorf("Expected progress output to contain %q, got:\n%s", want, progressBuf.String())
+	}
+	if _, err := os.Stat(incompletePath); !os.IsNotExist(err) {
+		t.Errorf("Incomplete file still exists after successful pull: %s", incompletePath)
+	}
+
+	pulledContent, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read pulled GGUF file: %v", err)
+	}
+	if !bytes.Equal(pulledContent, originalContent) {
+		t.Errorf("Pulled content doesn't match original content")
+	}
+}
+
+func TestPullFailsOnRepeatedDigestMismatch(t *testing.T) {
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Create a test registry that corrupts every download of the layer
+	var layerGets atomic.Int32
+	var layerPath atomic.Value
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != layerPath.Load() {
+			registry.ServeHTTP(w, r)
+			return
+		}
+		layerGets.Add(1)
+		rec := httptest.NewRecorder()
+		registry.ServeHTTP(rec, r)
+		body := rec.Body.Bytes()
+		if len(body) > 0 {
+			body[0] ^= 0xff
+		}
+		for key, values := range rec.Header() {
+			w.Header()[key] = values
+		}
+		w.WriteHeader(rec.Code)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	tag, ggufPath, originalContent := pushForPullTest(t, client, server, "corrupt-layer-test/model")
+	layerDigest, _, err := oci.SHA256(bytes.NewReader(originalContent))
+	if err != nil {
+		t.Fatalf("Failed to compute layer digest: %v", err)
+	}
+	layerPath.Store("/v2/corrupt-layer-test/model/blobs/" + layerDigest.String())
+
+	err = client.PullModel(t.Context(), tag, nil)
+	var mismatch *oci.DigestMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("Expected a DigestMismatchError, got %v", err)
+	}
+	if mismatch.Expected != layerDigest {
+		t.Errorf("Expected digest %s, got %s", layerDigest, mismatch.Expected)
+	}
+	if mismatch.Computed == layerDigest {
+		t.Errorf("Expected the computed digest to differ from %s", layerDigest)
+	}
+	if want := fmt.Sprintf("layer %s failed verification", layerDigest); !strings.Contains(err.Error(), want) {
+		t.Errorf("Expected error to contain %q, got %v", want, err)
+	}
+	if got := layerGets.Load(); got != 2 {
+		t.Errorf("Expected the layer to be downloaded twice, got %d", got)
+	}
+	for _, path := range []string{ggufPath, ggufPath + ".incomplete"} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("Expected %s not to exist after a failed pull", path)
+		}
+	}
+	if _, err := client.GetModel(tag); err == nil {
+		t.Error("Expected the model not to be stored after a failed pull")
+	}
+}
+
+func TestPushCompressed(t *testing.T) {
+	tempDir := t.TempDir()
+
+	// Create client with plainHTTP for test registry
+	client, err := newTestClient(tempDir)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	// Create a test registry
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/compressed-test/model:v1.0.0"
+
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	ggufContent, err := os.ReadFile(testGGUFFile)
+	if err != nil {
+		t.Fatalf("Failed to read GGUF file: %v", err)
+	}
+	wantDiffID, _, err := oci.SHA256(bytes.NewReader(ggufContent))
+	if err != nil {
+		t.Fatalf("Failed to hash GGUF file: %v", err)
+	}
+
+	if err := client.PushModelWithOptions(t.Context(), tag, nil, PushOptions{Compression: CompressionZstd}); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	// The remote manifest should describe a compressed GGUF layer.
+	remoteMdl, err := client.registry.Model(t.Context(), tag)
+	if err != nil {
+		t.Fatalf("Failed to get remote model: %v", err)
+	}
+	remoteManifest, err := remoteMdl.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to get remote manifest: %v", err)
+	}
+	if len(remoteManifest.Layers) != 1 {
+		t.Fatalf("Expected 1 remote layer, got %d", len(remoteManifest.Layers))
+	}
+	remoteLayer := remoteManifest.Layers[0]
+	if remoteLayer.MediaType != types.MediaTypeGGUF.WithZstd() {
+		t.Errorf("Remote layer media type = %q, want %q", remoteLayer.MediaType, types.MediaTypeGGUF.WithZstd())
+	}
+	if remoteLayer.Digest == wantDiffID {
+		t.Error("Remote layer digest should be the digest of the compressed blob")
+	}
+
+	// Delete local copy and pull the compressed model back.
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+	if err := client.PullModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// The layer must be stored decompressed under its uncompressed DiffID.
+	pulled, err := client.GetModel(tag)
+	if err != nil {
+		t.Fatalf("Failed to get pulled model: %v", err)
+	}
+	ggufPaths, err := pulled.GGUFPaths()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF paths: %v", err)
+	}
+	if len(ggufPaths) != 1 {
+		t.Fatalf("Unexpected number of model files: %d", len(ggufPaths))
+	}
+	if filepath.Base(ggufPaths[0]) != wantDiffID.Hex {
+		t.Errorf("GGUF path = %q, want blob named %q", ggufPaths[0], wantDiffID.Hex)
+	}
+	pulledContent, err := os.ReadFile(ggufPaths[0])
+	if err != nil {
+		t.Fatalf("Failed to read pulled GGUF file: %v", err)
+	}
+	if !bytes.Equal(pulledContent, ggufContent) {
+		t.Error("Pulled GGUF content does not match original content")
+	}
+
+	stored, err := client.store.Read(tag)
+	if err != nil {
+		t.Fatalf("Failed to read pulled model from store: %v", err)
+	}
+	localManifest, err := stored.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to get local manifest: %v", err)
+	}
+	if localManifest.Layers[0].MediaType != types.MediaTypeGGUF {
+		t.Errorf("Local layer media type = %q, want %q", localManifest.Layers[0].MediaType, types.MediaTypeGGUF)
+	}
+	if localManifest.Layers[0].Digest != wantDiffID {
+		t.Errorf("Local layer digest = %s, want %s", localManifest.Layers[0].Digest, wantDiffID)
+	}
+}
+
+func TestPullCompressedUpToDate(t *testing.T) {
+	server, blobGets := newBlobCountingRegistry(t)
+	registryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := registryURL.Host + "/compressed-test/model:uptodate"
+
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	if err := client.store.Write(mdl, []string{tag}, nil); err != nil {
+		t.Fatalf("Failed to write model to store: %v", err)
+	}
+	if err := client.PushModelWithOptions(t.Context(), tag, nil, PushOptions{Compression: CompressionZstd}); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+	if _, err := client.DeleteModel(tag, false); err != nil {
+		t.Fatalf("Failed to delete model: %v", err)
+	}
+	if err := client.PullModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	remoteMdl, err := client.registry.Model(t.Context(), tag)
+	if err != nil {
+		t.Fatalf("Failed to get remote model: %v", err)
+	}
+	remoteDigest, err := remoteMdl.Digest()
+	if err != nil {
+		t.Fatalf("Failed to get remote digest: %v", err)
+	}
+	upToDate, err := client.IsUpToDate(tag, remoteDigest)
+	if err != nil {
+		t.Fatalf("Failed to compare digests: %v", err)
+	}
+	if !upToDate {
+		t.Error("Expected the pulled compressed model to be up to date")
+	}
+
+	blobGets.Store(0)
+	var progressBuffer bytes.Buffer
+	if err := client.PullModel(t.Context(), tag, &progressBuffer); err != nil {
+		t.Fatalf("Failed to pull model again: %v", err)
+	}
+	if !strings.Contains(progressBuffer.String(), "Model is up to date") {
+		t.Errorf("Expected up to date message, got %q", progressBuffer.String())
+	}
+	if n := blobGets.Load(); n != 0 {
+		t.Errorf("Expected no blob downloads, got %d", n)
+	}
+}
+
+func TestPushUnsupportedCompression(t *testing.T) {
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	err = client.PushModelWithOptions(t.Context(), "some-repo/some-model:latest", nil, PushOptions{Compression: "gzip"})
+	if !errors.Is(err, ErrUnsupportedCompression) {
+		t.Fatalf("Expected ErrUnsupportedCompression, got %v", err)
+	}
+}
+
+func TestPullRejectsHuggingFaceRevisionTag(t *testing.T) {
+	client, err := newTestClient(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	for _, ref := range []string{"hf.co/org/model:rev-v1", "hf.co/org/model:Q4_K_M-rev-v1"} {
+		if err := client.PullModel(t.Context(), ref, nil); !errors.Is(err, ErrInvalidReference) {
+			t.Errorf("Expected ErrInvalidReference pulling %s, got %v", ref, err)
+		}
+	}
+}
+
 func TestPushProgress(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -1143,6 +1805,69 @@ func TestTag(t *testing.T) {
 	}
 }
 
+func TestTagByID(t *testing.T) {
+	model := testutil.NewGGUFArtifact(t, testGGUFFile)
+	id, err := model.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	sources := []struct {
+		name string
+		ref  string
+	}{
+		{name: "full ID", ref: id},
+		{name: "short ID", ref: id[7:19]},
+		{name: "ID without prefix", ref: strings.TrimPrefix(id, "sha256:")},
+	}
+	models := []struct {
+		name string
+		tags []string
+	}{
+		{name: "untagged", tags: nil},
+		{name: "single tag", tags: []string{"some-repo:some-tag"}},
+		{name: "multiple tags", tags: []string{"some-repo:some-tag", "other-repo:other-tag"}},
+	}
+
+	for _, m := range models {
+		for _, source := range sources {
+			t.Run(m.name+" by "+source.name, func(t *testing.T) {
+				client, err := newTestClient(t.TempDir())
+				if err != nil {
+					t.Fatalf("Failed to create client: %v", err)
+				}
+
+				var tags []string
+				for _, tag := range m.tags {
+					tags = append(tags, client.normalizeModelName(tag))
+				}
+				if err := client.store.Write(model, tags, nil); err != nil {
+					t.Fatalf("Failed to write model to store: %v", err)
+				}
+
+				if err := client.Tag(source.ref, "new-repo:new-tag"); err != nil {
+					t.Fatalf("Failed to tag model %q: %v", source.ref, err)
+				}
+
+				tagged, err := client.GetModel("new-repo:new-tag")
+				if err != nil {
+					t.Fatalf("Failed to get model by new tag: %v", err)
+				}
+				taggedID, err := tagged.ID()
+				if err != nil {
+					t.Fatalf("Failed to get tagged model ID: %v", err)
+				}
+				if taggedID != id {
+					t.Errorf("Expected new tag to point to %s, got %s", id, taggedID)
+				}
+				if len(tagged.Tags()) != len(m.tags)+1 {
+					t.Errorf("Expected %d tags, got %d: %v", len(m.tags)+1, len(tagged.Tags()), tagged.Tags())
+				}
+			})
+		}
+	}
+}
+
 func TestTagNotFound(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -1158,6 +1883,72 @@ func TestTagNotFound(t *testing.T) {
 	}
 }
 
+func TestRepackageModelExistingTarget(t *testing.T) {
+	contextSize := func(n uint64) *uint64 { return &n }
+
+	tests := []struct {
+		name        string
+		contextSize *uint64
+		force       bool
+		wantErr     error
+	}{
+		{name: "different model without force", contextSize: contextSize(4096), wantErr: ErrConflict},
+		{name: "different model with force", contextSize: contextSize(4096), force: true},
+		{name: "same model without force", contextSize: contextSize(2048)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newTestClient(t.TempDir())
+			if err != nil {
+				t.Fatalf("Failed to create client: %v", err)
+			}
+
+			model := testutil.NewGGUFArtifact(t, testGGUFFile)
+			if err := client.store.Write(model, []string{client.normalizeModelName("source:latest")}, nil); err != nil {
+				t.Fatalf("Failed to write model to store: %v", err)
+			}
+
+			// Create the target tag as a variant with a 2048 token context
+			if err := client.RepackageModel("source:latest", "target:latest", RepackageOptions{ContextSize: contextSize(2048)}); err != nil {
+				t.Fatalf("Failed to create existing target: %v", err)
+			}
+			before, err := client.GetModel("target:latest")
+			if err != nil {
+				t.Fatalf("Failed to get existing target: %v", err)
+			}
+			beforeID, err := before.ID()
+			if err != nil {
+				t.Fatalf("Failed to get existing target ID: %v", err)
+			}
+
+			err = client.RepackageModel("source:latest", "target:latest", RepackageOptions{
+				ContextSize: tt.contextSize,
+				Force:       tt.force,
+			})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Expected %v, got: %v", tt.wantErr, err)
+				}
+			} else if err != nil {
+				t.Fatalf("Failed to repackage model: %v", err)
+			}
+
+			after, err := client.GetModel("target:latest")
+			if err != nil {
+				t.Fatalf("Failed to get target after repackage: %v", err)
+			}
+			afterID, err := after.ID()
+			if err != nil {
+				t.Fatalf("Failed to get target ID after repackage: %v", err)
+			}
+			if overwritten := afterID != beforeID; overwritten != tt.force {
+				t.Errorf("Expected target overwritten to be %v, got %v", tt.force, overwritten)
+			}
+		})
+	}
+}
+
 func TestClientPushModelNotFound(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -1386,3 +2177,82 @@ func TestPullHuggingFaceModelFromCache(t *testing.T) {
 		})
 	}
 }
+
+func TestPullModelStructuredLogs(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+	registryURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := registryURL.Host + "/testmodel:logs"
+
+	mdl := testutil.NewGGUFArtifact(t, testGGUFFile)
+	ref, err := reference.ParseReference(tag)
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	if err := remote.Write(ref, mdl, nil, remote.WithPlainHTTP(true)); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+	wantDigest, err := mdl.Digest()
+	if err != nil {
+		t.Fatalf("Failed to get model digest: %v", err)
+	}
+	layers, err := mdl.Layers()
+	if err != nil {
+		t.Fatalf("Failed to get model layers: %v", err)
+	}
+	var wantBytes int64
+	for _, layer := range layers {
+		size, err := layer.Size()
+		if err != nil {
+			t.Fatalf("Failed to get layer size: %v", err)
+		}
+		wantBytes += size
+	}
+
+	var logs bytes.Buffer
+	client, err := NewClient(
+		WithStoreRootPath(t.TempDir()),
+		WithRegistryClient(mdregistry.NewClient(mdregistry.WithPlainHTTP(true))),
+		WithLogger(slog.New(slog.NewJSONHandler(&logs, nil))),
+	)
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+	if err := client.PullModel(t.Context(), tag, nil); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	var record map[string]any
+	scanner := bufio.NewScanner(&logs)
+	for scanner.Scan() {
+		var r map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("Failed to parse log line %q: %v", scanner.Text(), err)
+		}
+		if r["msg"] == "successfully pulled model" {
+			record = r
+		}
+	}
+	if record == nil {
+		t.Fatalf("Expected a pull completion log record, got:\n%s", logs.String())
+	}
+
+	if record["model"] != tag {
+		t.Errorf("Expected model %q, got %v", tag, record["model"])
+	}
+	if record["digest"] != wantDigest.String() {
+		t.Errorf("Expected digest %q, got %v", wantDigest.String(), record["digest"])
+	}
+	if record["layers"] != float64(len(layers)) {
+		t.Errorf("Expected %d layers, got %v", len(layers), record["layers"])
+	}
+	if record["total_bytes"] != float64(wantBytes) {
+		t.Errorf("Expected %d total bytes, got %v", wantBytes, record["total_bytes"])
+	}
+	if _, ok := record["duration"].(float64); !ok {
+		t.Errorf("Expected a duration, got %v", record["duration"])
+	}
+}
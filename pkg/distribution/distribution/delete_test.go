@@ -67,6 +67,16 @@ func TestDeleteModel(t *testing.T) {
 			force:       true,
 			description: "one tag, by tag, with force",
 		},
+		{
+			ref:         "docker.io/ai/foo:latest",
+			tags:        []string{"index.docker.io/ai/foo:latest"},
+			description: "one tag, by docker.io alias of index.docker.io tag",
+		},
+		{
+			ref:         "index.docker.io/ai/foo:latest",
+			tags:        []string{"docker.io/ai/foo:latest"},
+			description: "one tag, by index.docker.io alias of docker.io tag",
+		},
 		{
 			ref:         id,
 			tags:        []string{"some-repo:some-tag", "other-repo:other-tag"},
@@ -92,6 +102,12 @@ func TestDeleteModel(t *testing.T) {
 			untagOnly:   true,
 			description: "multiple tags, by tag, with force",
 		},
+		{
+			ref:         "docker.io/ai/foo:latest",
+			tags:        []string{"index.docker.io/ai/foo:latest", "other-repo:other-tag"},
+			untagOnly:   true,
+			description: "multiple tags, by docker.io alias of index.docker.io tag",
+		},
 		{
 			ref:         "not-existing:tag",
 			tags:        []string{},
@@ -3,16 +3,29 @@ package distribution
 import (
 	"errors"
 
+	"github.com/docker/model-runner/pkg/distribution/huggingface"
 	"github.com/docker/model-runner/pkg/distribution/internal/store"
 	"github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
 var (
 	ErrInvalidReference = registry.ErrInvalidReference
 	ErrModelNotFound    = store.ErrModelNotFound // model not found in store
 	// ErrUnsupportedMediaType is returned when a model's config media type is
-	// not supported by this client. The caller should wrap this with a dynamic
-	// message that includes the actual and supported media types.
-	ErrUnsupportedMediaType = errors.New("unsupported model config media type")
+	// not supported by this client, such as a newer major config version.
+	ErrUnsupportedMediaType = types.ErrUnsupportedMediaType
 	ErrConflict             = errors.New("resource conflict")
+	// ErrUnsupportedCompression is returned when a push requests a layer
+	// compression this client does not implement.
+	ErrUnsupportedCompression = errors.New("unsupported compression")
+	// ErrModelTooLarge is returned when a pull is rejected because the model
+	// exceeds PullOptions.MaxBytes.
+	ErrModelTooLarge = huggingface.ErrModelTooLarge
+	// ErrLayerNotFound is returned when a model has no layer of the media
+	// type to drop.
+	ErrLayerNotFound = errors.New("layer not found")
+	// ErrPrimaryLayer is returned when dropping the layers holding a model's
+	// weights, without which the model cannot run.
+	ErrPrimaryLayer = errors.New("primary model layer cannot be dropped")
 )
@@ -1,10 +1,22 @@
 package distribution
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/docker/model-runner/pkg/distribution/internal/testutil"
+	"github.com/docker/model-runner/pkg/distribution/oci"
 	"github.com/docker/model-runner/pkg/distribution/tarball"
 )
 
@@ -27,7 +39,7 @@ func TestLoadModel(t *testing.T) {
 	var id string
 	go func() {
 		var err error
-		id, err = client.LoadModel(pr, nil)
+		id, err = client.LoadModel(t.Context(), pr, nil)
 		done <- err
 	}()
 	if err := target.Write(t.Context(), testutil.NewGGUFArtifact(t, testGGUFFile), nil); err != nil {
@@ -42,3 +54,218 @@ func TestLoadModel(t *testing.T) {
 		t.Fatalf("Failed to get model: %v", err)
 	}
 }
+
+// cancelingReader cancels a context once it has read past a given offset.
+type cancelingReader struct {
+	r      io.Reader
+	read   int
+	after  int
+	cancel context.CancelFunc
+}
+
+func (r *cancelingReader) Read(p []byte) (int, error) {
+	if r.read >= r.after {
+		r.cancel()
+	}
+	// Read in small chunks so the cancellation lands inside a blob.
+	n, err := r.r.Read(p[:min(len(p), 512)])
+	r.read += n
+	return n, err
+}
+
+func TestLoadModelCancelled(t *testing.T) {
+	tempDir := t.TempDir()
+
+	client, err := NewClient(WithStoreRootPath(tempDir))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var buf bytes.Buffer
+	target, err := tarball.NewTarget(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+	if err := target.Write(t.Context(), testutil.NewGGUFArtifact(t, testGGUFFile), nil); err != nil {
+		t.Fatalf("Failed to write model tarball: %v", err)
+	}
+
+	// Cancel well into the tarball, after the config blob has been written
+	// but before the model weights are complete.
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	r := &cancelingReader{r: &buf, after: buf.Len() * 3 / 4, cancel: cancel}
+	if _, err := client.LoadModel(ctx, r, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected load to be cancelled, got %v", err)
+	}
+	if buf.Len() == 0 {
+		t.Error("Expected load to stop before consuming the whole tarball")
+	}
+
+	var leftovers []string
+	err = filepath.WalkDir(filepath.Join(tempDir, "blobs"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			leftovers = append(leftovers, path)
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("Failed to walk blobs directory: %v", err)
+	}
+	if len(leftovers) > 0 {
+		t.Errorf("Expected no blobs after a cancelled load, found %v", leftovers)
+	}
+
+	models, err := client.ListModels()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	if len(models) != 0 {
+		t.Errorf("Expected no models after a cancelled load, got %d", len(models))
+	}
+}
+
+func TestLoadModelResume(t *testing.T) {
+	tempDir := t.TempDir()
+
+	client, err := NewClient(WithStoreRootPath(tempDir))
+	if err != nil {
+		t.Fatalf("Failed to create client: %v", err)
+	}
+
+	var buf bytes.Buffer
+	target, err := tarball.NewTarget(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+	if err := target.Write(t.Context(), testutil.NewGGUFArtifact(t, testGGUFFile), nil); err != nil {
+		t.Fatalf("Failed to write model tarball: %v", err)
+	}
+	archive := buf.Bytes()
+
+	// Cut the stream half way through the last blob, after the others have
+	// been imported.
+	var cutAt int64
+	ar := bytes.NewReader(archive)
+	tr := tar.NewReader(ar)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Failed to read model tarball: %v", err)
+		}
+		if strings.HasPrefix(hdr.Name, "blobs/") {
+			cutAt = ar.Size() - int64(ar.Len()) + hdr.Size/2
+		}
+	}
+	errCut := errors.New("connection reset")
+	cut := io.MultiReader(bytes.NewReader(archive[:cutAt]), iotest.ErrReader(errCut))
+	if _, err := client.LoadModel(t.Context(), cut, nil); !errors.Is(err, errCut) {
+		t.Fatalf("Expected load to fail with %v, got %v", errCut, err)
+	}
+
+	// Remember the files of the first attempt to tell whether they are
+	// rewritten by the second.
+	first := make(map[string]fs.FileInfo)
+	var complete, incomplete int
+	err = filepath.WalkDir(filepath.Join(tempDir, "blobs"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if name, ok := strings.CutSuffix(path, ".incomplete"); ok {
+			first[name] = info
+			incomplete++
+		} else {
+			first[path] = info
+			complete++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk blobs directory: %v", err)
+	}
+	if complete == 0 || incomplete != 1 {
+		t.Fatalf("Expected imported blobs and one incomplete blob after a failed load, got %d and %d", complete, incomplete)
+	}
+
+	id, err := client.LoadModel(t.Context(), bytes.NewReader(archive), nil)
+	if err != nil {
+		t.Fatalf("LoadModel exited with error: %v", err)
+	}
+	if _, err := client.GetModel(id); err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	for path, before := range first {
+		after, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat blob: %v", err)
+		}
+		if !os.SameFile(before, after) {
+			t.Errorf("Expected %s to be kept from the first attempt rather than rewritten", path)
+		}
+	}
+}
+
+func TestLoadModelWithDigest(t *testing.T) {
+	var buf bytes.Buffer
+	target, err := tarball.NewTarget(&buf)
+	if err != nil {
+		t.Fatalf("Failed to create target: %v", err)
+	}
+	if err := target.Write(t.Context(), testutil.NewGGUFArtifact(t, testGGUFFile), nil); err != nil {
+		t.Fatalf("Failed to write model tarball: %v", err)
+	}
+	// Archives are commonly padded past their end, and the padding is part
+	// of the digest.
+	buf.Write(make([]byte, 4096))
+	archive := buf.Bytes()
+	hasher := sha256.New()
+	hasher.Write(archive)
+	matching := oci.Hash{Algorithm: "sha256", Hex: hex.EncodeToString(hasher.Sum(nil))}
+
+	t.Run("matching digest", func(t *testing.T) {
+		client, err := NewClient(WithStoreRootPath(t.TempDir()))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		id, err := client.LoadModelWithOptions(t.Context(), bytes.NewReader(archive), nil, LoadOptions{Digest: matching})
+		if err != nil {
+			t.Fatalf("LoadModelWithOptions exited with error: %v", err)
+		}
+		if _, err := client.GetModel(id); err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+	})
+
+	t.Run("mismatching digest", func(t *testing.T) {
+		client, err := NewClient(WithStoreRootPath(t.TempDir()))
+		if err != nil {
+			t.Fatalf("Failed to create client: %v", err)
+		}
+		expected := oci.Hash{Algorithm: "sha256", Hex: strings.Repeat("0", 64)}
+		_, err = client.LoadModelWithOptions(t.Context(), bytes.NewReader(archive), nil, LoadOptions{Digest: expected})
+		var mismatch *oci.DigestMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("Expected a DigestMismatchError, got %v", err)
+		}
+		if mismatch.Computed != matching {
+			t.Errorf("Expected computed digest %s, got %s", matching, mismatch.Computed)
+		}
+		models, err := client.ListModels()
+		if err != nil {
+			t.Fatalf("Failed to list models: %v", err)
+		}
+		if len(models) != 0 {
+			t.Errorf("Expected no models after a rejected load, got %d", len(models))
+		}
+	})
+}
@@ -145,6 +145,21 @@ func TestNormalizeModelName(t *testing.T) {
 			input:    "huggingface.co/org/model:Q4_K_M",
 			expected: "huggingface.co/org/model:Q4_K_M",
 		},
+		{
+			name:     "hf.co with revision",
+			input:    "hf.co/Org/Model@v1.0",
+			expected: "huggingface.co/org/model:rev-v1.0",
+		},
+		{
+			name:     "hf.co with tag and revision",
+			input:    "hf.co/org/model:Q4_K_M@abc1234",
+			expected: "huggingface.co/org/model:Q4_K_M-rev-abc1234",
+		},
+		{
+			name:     "hf.co nested path",
+			input:    "hf.co/Org/Model/GGUF:Q8_0",
+			expected: "huggingface.co/org/model/gguf:Q8_0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -157,6 +172,51 @@ func TestNormalizeModelName(t *testing.T) {
 	}
 }
 
+func TestPullReference(t *testing.T) {
+	client, cleanup := createTestClient(t)
+	defer cleanup()
+
+	tests := []struct {
+		name     string
+		input    string
+		opts     PullOptions
+		expected string
+	}{
+		{
+			name:     "no org and no tag is normalized",
+			input:    "gemma3",
+			expected: "ai/gemma3:latest",
+		},
+		{
+			name:     "no org and no tag is kept with raw reference",
+			input:    "gemma3",
+			opts:     PullOptions{RawReference: true},
+			expected: "gemma3",
+		},
+		{
+			name:     "registry layout is kept with raw reference",
+			input:    "registry.example.com/team/model",
+			opts:     PullOptions{RawReference: true},
+			expected: "registry.example.com/team/model",
+		},
+		{
+			name:     "raw reference is trimmed",
+			input:    "  gemma3  ",
+			opts:     PullOptions{RawReference: true},
+			expected: "gemma3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := client.pullReference(tt.input, tt.opts)
+			if result != tt.expected {
+				t.Errorf("pullReference(%q, %+v) = %q, want %q", tt.input, tt.opts, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestLooksLikeID(t *testing.T) {
 	// Create a client for testing
 	client, cleanup := createTestClient(t)
@@ -381,67 +441,66 @@ func TestIsHuggingFaceReference(t *testing.T) {
 
 func TestParseHFReference(t *testing.T) {
 	tests := []struct {
-		name         string
-		input        string
-		expectedRepo string
-		expectedRev  string
-		expectedTag  string
+		name     string
+		input    string
+		expected hfReference
 	}{
 		{
-			name:         "basic with latest tag",
-			input:        "huggingface.co/org/model:latest",
-			expectedRepo: "org/model",
-			expectedRev:  "main", // revision is always main
-			expectedTag:  "latest",
+			name:     "basic with latest tag",
+			input:    "huggingface.co/org/model:latest",
+			expected: hfReference{Repo: "org/model", Revision: "main", Tag: "latest"},
 		},
 		{
-			name:         "with quantization tag",
-			input:        "huggingface.co/org/model:Q4_K_M",
-			expectedRepo: "org/model",
-			expectedRev:  "main",
-			expectedTag:  "Q4_K_M",
+			name:     "with quantization tag",
+			input:    "huggingface.co/org/model:Q4_K_M",
+			expected: hfReference{Repo: "org/model", Revision: "main", Tag: "Q4_K_M"},
+		},
+		{
+			name:     "without tag",
+			input:    "huggingface.co/org/model",
+			expected: hfReference{Repo: "org/model", Revision: "main", Tag: "latest"},
+		},
+		{
+			name:     "with commit hash as tag",
+			input:    "huggingface.co/HuggingFaceTB/SmolLM2-135M-Instruct:abc123",
+			expected: hfReference{Repo: "HuggingFaceTB/SmolLM2-135M-Instruct", Revision: "main", Tag: "abc123"},
 		},
 		{
-			name:         "without tag",
-			input:        "huggingface.co/org/model",
-			expectedRepo: "org/model",
-			expectedRev:  "main",
-			expectedTag:  "latest",
+			name:     "single name (no org)",
+			input:    "huggingface.co/model:latest",
+			expected: hfReference{Repo: "model", Revision: "main", Tag: "latest"},
 		},
 		{
-			name:         "with commit hash as tag",
-			input:        "huggingface.co/HuggingFaceTB/SmolLM2-135M-Instruct:abc123",
-			expectedRepo: "HuggingFaceTB/SmolLM2-135M-Instruct",
-			expectedRev:  "main",
-			expectedTag:  "abc123",
+			name:     "hf.co prefix with quantization",
+			input:    "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q8_0",
+			expected: hfReference{Repo: "bartowski/Llama-3.2-1B-Instruct-GGUF", Revision: "main", Tag: "Q8_0"},
 		},
 		{
-			name:         "single name (no org)",
-			input:        "huggingface.co/model:latest",
-			expectedRepo: "model",
-			expectedRev:  "main",
-			expectedTag:  "latest",
+			name:     "with revision",
+			input:    "hf.co/HuggingFaceTB/SmolLM2-135M-Instruct@v1.0",
+			expected: hfReference{Repo: "HuggingFaceTB/SmolLM2-135M-Instruct", Revision: "v1.0", Tag: "latest"},
 		},
 		{
-			name:         "hf.co prefix with quantization",
-			input:        "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q8_0",
-			expectedRepo: "bartowski/Llama-3.2-1B-Instruct-GGUF",
-			expectedRev:  "main",
-			expectedTag:  "Q8_0",
+			name:     "with quantization and revision",
+			input:    "hf.co/bartowski/Llama-3.2-1B-Instruct-GGUF:Q8_0@refs/pr/1",
+			expected: hfReference{Repo: "bartowski/Llama-3.2-1B-Instruct-GGUF", Revision: "refs/pr/1", Tag: "Q8_0"},
+		},
+		{
+			name:     "nested path",
+			input:    "hf.co/Org/Model/GGUF/Q8:Q8_0",
+			expected: hfReference{Repo: "Org/Model", Path: "GGUF/Q8", Revision: "main", Tag: "Q8_0"},
+		},
+		{
+			name:     "nested path with revision",
+			input:    "huggingface.co/org/model/onnx@abc1234",
+			expected: hfReference{Repo: "org/model", Path: "onnx", Revision: "abc1234", Tag: "latest"},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			repo, rev, tag := parseHFReference(tt.input)
-			if repo != tt.expectedRepo {
-				t.Errorf("parseHFReference(%q) repo = %q, want %q", tt.input, repo, tt.expectedRepo)
-			}
-			if rev != tt.expectedRev {
-				t.Errorf("parseHFReference(%q) rev = %q, want %q", tt.input, rev, tt.expectedRev)
-			}
-			if tag != tt.expectedTag {
-				t.Errorf("parseHFReference(%q) tag = %q, want %q", tt.input, tag, tt.expectedTag)
+			if got := parseHFReference(tt.input); got != tt.expected {
+				t.Errorf("parseHFReference(%q) = %+v, want %+v", tt.input, got, tt.expected)
 			}
 		})
 	}
@@ -462,7 +521,7 @@ func loadTestModel(t *testing.T, client *Client, ggufPath string) string {
 	var id string
 	go func() {
 		var err error
-		id, err = client.LoadModel(pr, nil)
+		id, err = client.LoadModel(t.Context(), pr, nil)
 		done <- err
 	}()
 
@@ -1,6 +1,12 @@
 package format
 
 import (
+	"bytes"
+	"encoding/binary"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/docker/model-runner/pkg/distribution/types"
@@ -238,3 +244,206 @@ func TestFormatSize(t *testing.T) {
 		})
 	}
 }
+
+func TestGGUFFormat_ExtractConfigSidecar(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to read GGUF asset: %v", err)
+	}
+	f := &GGUFFormat{}
+
+	t.Run("sidecar absent", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "model.gguf")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("Failed to write GGUF file: %v", err)
+		}
+		config, err := f.ExtractConfig([]string{path})
+		if err != nil {
+			t.Fatalf("ExtractConfig failed: %v", err)
+		}
+		if _, ok := config.GGUF["tokenizer.chat_template"]; ok {
+			t.Errorf("Expected no chat template without a sidecar, got %q", config.GGUF["tokenizer.chat_template"])
+		}
+	})
+
+	t.Run("sidecar present", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, "model.gguf")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("Failed to write GGUF file: %v", err)
+		}
+
+		sidecar := `{
+			"some.parameter.string": "overridden",
+			"tokenizer": {"chat_template": "{{ messages }}"},
+			"general.quantization_version": 2,
+			"general.tags": ["chat", "small"]
+		}`
+		if err := os.WriteFile(filepath.Join(dir, "model.json"), []byte(sidecar), 0o644); err != nil {
+			t.Fatalf("Failed to write sidecar: %v", err)
+		}
+		config, err := f.ExtractConfig([]string{path})
+		if err != nil {
+			t.Fatalf("ExtractConfig failed: %v", err)
+		}
+
+		expected := map[string]string{
+			"some.parameter.string":        "hello world",
+			"tokenizer.chat_template":      "{{ messages }}",
+			"general.quantization_version": "2",
+			"general.tags":                 "chat, small",
+		}
+		for key, want := range expected {
+			if got := config.GGUF[key]; got != want {
+				t.Errorf("GGUF[%q] = %q, want %q", key, got, want)
+			}
+		}
+	})
+}
+
+func TestGGUFFormat_ExtractConfigChatTemplate(t *testing.T) {
+	const template = "{% for m in messages %}{{ m['content'] }}{% endfor %}"
+	f := &GGUFFormat{}
+
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	writeGGUF(t, path, map[string]string{
+		"general.architecture":    "llama",
+		"tokenizer.chat_template": template,
+	})
+	config, err := f.ExtractConfig([]string{path})
+	if err != nil {
+		t.Fatalf("ExtractConfig failed: %v", err)
+	}
+	if config.ChatTemplate != template {
+		t.Errorf("ChatTemplate = %q, want %q", config.ChatTemplate, template)
+	}
+	if got := config.GGUF[types.GGUFChatTemplateKey]; got != template {
+		t.Errorf("GGUF[%q] = %q, want %q", types.GGUFChatTemplateKey, got, template)
+	}
+
+	path = filepath.Join(t.TempDir(), "model.gguf")
+	writeGGUF(t, path, map[string]string{"general.architecture": "llama"})
+	config, err = f.ExtractConfig([]string{path})
+	if err != nil {
+		t.Fatalf("ExtractConfig failed: %v", err)
+	}
+	if config.ChatTemplate != "" {
+		t.Errorf("Expected no chat template, got %q", config.ChatTemplate)
+	}
+}
+
+func TestGGUFFormat_ExtractConfigInferredQuantization(t *testing.T) {
+	const (
+		ggmlTypeF32  = 0
+		ggmlTypeQ4_0 = 2
+		ggmlTypeQ8_0 = 8
+	)
+	// The tensor names are not ones the parser uses to guess the file type.
+	q4Tensors := []ggufTensor{
+		{name: "layers.0.attn_q", ggmlType: ggmlTypeQ4_0, size: 18},
+		{name: "layers.0.attn_k", ggmlType: ggmlTypeQ4_0, size: 18},
+		{name: "layers.0.attn_v", ggmlType: ggmlTypeQ4_0, size: 18},
+		{name: "layers.0.norm", ggmlType: ggmlTypeF32, size: 128},
+		{name: "layers.0.norm_bias", ggmlType: ggmlTypeF32, size: 128},
+		{name: "layers.0.norm_scale", ggmlType: ggmlTypeF32, size: 128},
+		{name: "layers.0.norm_extra", ggmlType: ggmlTypeF32, size: 128},
+		{name: "output", ggmlType: ggmlTypeQ8_0, size: 34},
+	}
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		tensors  []ggufTensor
+		want     string
+	}{
+		{
+			name:     "file type unset",
+			metadata: map[string]string{"general.architecture": "llama"},
+			tensors:  q4Tensors,
+			want:     "MOSTLY_Q4_0",
+		},
+		{
+			name:     "unquantized tensors",
+			metadata: map[string]string{"general.architecture": "llama"},
+			tensors:  q4Tensors[3:7],
+			want:     "Unknown",
+		},
+		{
+			name:     "no tensors",
+			metadata: map[string]string{"general.architecture": "llama"},
+			want:     "Unknown",
+		},
+	}
+	f := &GGUFFormat{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "model.gguf")
+			writeGGUFWithTensors(t, path, tt.metadata, tt.tensors)
+			config, err := f.ExtractConfig([]string{path})
+			if err != nil {
+				t.Fatalf("ExtractConfig failed: %v", err)
+			}
+			if config.Quantization != tt.want {
+				t.Errorf("Quantization = %q, want %q", config.Quantization, tt.want)
+			}
+		})
+	}
+}
+
+// ggufTensor describes a tensor written by writeGGUFWithTensors.
+type ggufTensor struct {
+	name string
+	// ggmlType is the GGML type of the tensor, e.g. 2 for Q4_0.
+	ggmlType uint32
+	// size is the size of the tensor data in bytes, holding a single block.
+	size int
+}
+
+// writeGGUF writes a GGUF version 3 file without tensors and with the given
+// string metadata to path.
+func writeGGUF(t *testing.T, path string, metadata map[string]string) {
+	t.Helper()
+	writeGGUFWithTensors(t, path, metadata, nil)
+}
+
+// writeGGUFWithTensors writes a GGUF version 3 file with the given string
+// metadata and one-dimensional, zeroed tensors to path.
+func writeGGUFWithTensors(t *testing.T, path string, metadata map[string]string, tensors []ggufTensor) {
+	t.Helper()
+	const (
+		stringType = 8
+		alignment  = 32
+		// blockElements is the number of elements in a block of the
+		// quantized GGML types.
+		blockElements = 32
+	)
+	var buf bytes.Buffer
+	writeString := func(s string) {
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(len(s)))
+		buf.WriteString(s)
+	}
+	buf.WriteString("GGUF")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(3))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(tensors)))
+	_ = binary.Write(&buf, binary.LittleEndian, uint64(len(metadata)))
+	for _, key := range slices.Sorted(maps.Keys(metadata)) {
+		writeString(key)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(stringType))
+		writeString(metadata[key])
+	}
+	var offset uint64
+	for _, tensor := range tensors {
+		writeString(tensor.name)
+		_ = binary.Write(&buf, binary.LittleEndian, uint32(1))
+		_ = binary.Write(&buf, binary.LittleEndian, uint64(blockElements))
+		_ = binary.Write(&buf, binary.LittleEndian, tensor.ggmlType)
+		_ = binary.Write(&buf, binary.LittleEndian, offset)
+		offset += uint64(tensor.size+alignment-1) / alignment * alignment
+	}
+	if len(tensors) > 0 {
+		buf.Write(make([]byte, (alignment-buf.Len()%alignment)%alignment))
+		buf.Write(make([]byte, offset))
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatalf("Failed to write GGUF file: %v", err)
+	}
+}
@@ -1,7 +1,11 @@
 package format
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"regexp"
 	"strings"
 
@@ -54,16 +58,126 @@ func (g *GGUFFormat) ExtractConfig(paths []string) (types.Config, error) {
 		return types.Config{Format: types.FormatGGUF}, nil
 	}
 
+	metadata := mergeSidecarMetadata(extractGGUFMetadata(&gguf.Header), paths[0])
 	return types.Config{
 		Format:       types.FormatGGUF,
 		Parameters:   normalizeUnitString(gguf.Metadata().Parameters.String()),
 		Architecture: strings.TrimSpace(gguf.Metadata().Architecture),
-		Quantization: strings.TrimSpace(gguf.Metadata().FileType.String()),
+		Quantization: ggufQuantization(gguf),
 		Size:         normalizeUnitString(gguf.Metadata().Size.String()),
-		GGUF:         extractGGUFMetadata(&gguf.Header),
+		GGUF:         metadata,
+		ChatTemplate: metadata[types.GGUFChatTemplateKey],
 	}, nil
 }
 
+// unknownQuantization is how the GGUF parser reports a file type it does not
+// know.
+const unknownQuantization = "Unknown"
+
+// ggufQuantization returns the quantization of a GGUF file. Files whose file
+// type is unset or unknown, and whose tensor names the parser does not
+// recognize, are reported by the parser as "Unknown"; if their tensors are
+// quantized, the file type is inferred from the dominant type of all tensors
+// instead.
+func ggufQuantization(gguf *parser.GGUFFile) string {
+	quantization := strings.TrimSpace(gguf.Metadata().FileType.String())
+	if knownQuantization(quantization) {
+		return quantization
+	}
+
+	counts := make(map[parser.GGMLType]int)
+	quantized := false
+	for _, tensor := range gguf.TensorInfos {
+		counts[tensor.Type]++
+		quantized = quantized || tensor.Type.IsQuantized()
+	}
+	if !quantized {
+		return unknownQuantization
+	}
+	if inferred := parser.GetFileType(counts).String(); knownQuantization(inferred) {
+		return inferred
+	}
+	return unknownQuantization
+}
+
+// knownQuantization reports whether quantization names a GGUF file type,
+// rather than the parser's "Unknown" or placeholder for an out-of-range value.
+func knownQuantization(quantization string) bool {
+	return quantization != unknownQuantization && !strings.HasPrefix(quantization, "GGUFFileType(")
+}
+
+// mergeSidecarMetadata adds the metadata of the JSON sidecar stored next to
+// the GGUF file at path (model.json for model.gguf), if there is one. Values
+// embedded in the GGUF file take precedence over the sidecar. Nested objects
+// are flattened into dotted keys, matching the GGUF key naming. A missing or
+// malformed sidecar is ignored.
+func mergeSidecarMetadata(metadata map[string]string, path string) map[string]string {
+	sidecarPath := strings.TrimSuffix(path, filepath.Ext(path)) + ".json"
+	data, err := os.ReadFile(sidecarPath)
+	if err != nil {
+		return metadata
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var sidecar map[string]any
+	if err := decoder.Decode(&sidecar); err != nil {
+		return metadata
+	}
+
+	flattened := make(map[string]string)
+	flattenSidecarMetadata("", sidecar, flattened)
+	for key, value := range flattened {
+		if _, ok := metadata[key]; !ok {
+			metadata[key] = value
+		}
+	}
+	return metadata
+}
+
+// flattenSidecarMetadata stores the values of obj into out, keyed by their
+// dotted path below prefix.
+func flattenSidecarMetadata(prefix string, obj map[string]any, out map[string]string) {
+	for key, value := range obj {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenSidecarMetadata(key, v, out)
+		case []any:
+			if len(v) > maxArraySize {
+				continue
+			}
+			values := make([]string, 0, len(v))
+			for _, elem := range v {
+				if s, ok := sidecarScalar(elem); ok {
+					values = append(values, s)
+				}
+			}
+			out[key] = strings.Join(values, ", ")
+		default:
+			if s, ok := sidecarScalar(v); ok {
+				out[key] = s
+			}
+		}
+	}
+}
+
+// sidecarScalar formats a scalar JSON value. It reports false for nulls,
+// objects and arrays.
+func sidecarScalar(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return fmt.Sprintf("%t", v), true
+	default:
+		return "", false
+	}
+}
+
 var (
 	// spaceBeforeUnitRegex matches one or more spaces between a valid number and a letter (unit)
 	// Used to remove spaces between numbers and units (e.g., "16.78 M" -> "16.78M")
@@ -5,11 +5,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
 	"github.com/docker/model-runner/pkg/distribution/types"
@@ -93,7 +95,9 @@ func (s *SafetensorsFormat) ExtractConfig(paths []string) (types.Config, error)
 	header, err := parseSafetensorsHeader(paths[0])
 	if err != nil {
 		// Continue without metadata if parsing fails
-		return types.Config{Format: types.FormatSafetensors}, nil
+		config := types.Config{Format: types.FormatSafetensors}
+		applyModelConfigJSON(&config, filepath.Dir(paths[0]))
+		return config, nil
 	}
 
 	// Calculate total size across all files
@@ -115,14 +119,61 @@ func (s *SafetensorsFormat) ExtractConfig(paths []string) (types.Config, error)
 		architecture = fmt.Sprintf("%v", arch)
 	}
 
-	return types.Config{
+	config := types.Config{
 		Format:       types.FormatSafetensors,
 		Parameters:   formatParameters(params),
 		Quantization: header.getQuantization(),
 		Size:         formatSize(totalSize),
 		Architecture: architecture,
 		Safetensors:  header.extractMetadata(),
-	}, nil
+	}
+	applyModelConfigJSON(&config, filepath.Dir(paths[0]))
+	return config, nil
+}
+
+// modelConfigJSON holds the fields of a Hugging Face config.json that are
+// surfaced in the model config.
+type modelConfigJSON struct {
+	Architectures         []string `json:"architectures"`
+	ModelType             string   `json:"model_type"`
+	TorchDtype            string   `json:"torch_dtype"`
+	MaxPositionEmbeddings *int64   `json:"max_position_embeddings"`
+}
+
+// applyModelConfigJSON reads the config.json next to the safetensors files, if
+// any, and populates the architecture and safetensors metadata from it. A
+// missing or malformed config.json is ignored.
+func applyModelConfigJSON(config *types.Config, dir string) {
+	raw, err := os.ReadFile(filepath.Join(dir, "config.json"))
+	if err != nil {
+		return
+	}
+	var hfConfig modelConfigJSON
+	if err := json.Unmarshal(raw, &hfConfig); err != nil {
+		return
+	}
+
+	if config.Safetensors == nil {
+		config.Safetensors = make(map[string]string)
+	}
+	if len(hfConfig.Architectures) > 0 {
+		config.Safetensors["architectures"] = strings.Join(hfConfig.Architectures, ",")
+		if config.Architecture == "" {
+			config.Architecture = hfConfig.Architectures[0]
+		}
+	}
+	if hfConfig.ModelType != "" {
+		config.Safetensors["model_type"] = hfConfig.ModelType
+	}
+	if hfConfig.TorchDtype != "" {
+		config.Safetensors["torch_dtype"] = hfConfig.TorchDtype
+	}
+	if n := hfConfig.MaxPositionEmbeddings; n != nil && *n > 0 && *n <= math.MaxInt32 {
+		// This is the longest context the model was trained with, not the
+		// one to run it with, so it is only recorded as metadata: as a
+		// context size it would override the backend defaults.
+		config.Safetensors["max_position_embeddings"] = strconv.FormatInt(*n, 10)
+	}
 }
 
 const (
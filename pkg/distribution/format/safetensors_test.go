@@ -34,3 +34,80 @@ func TestParseSafetensorsHeader_TruncatedFile(t *testing.T) {
 		t.Fatal("expected error for truncated safetensors header, got nil")
 	}
 }
+
+// writeSafetensorsFile writes a minimal safetensors file with a single tensor.
+func writeSafetensorsFile(t *testing.T, path string) {
+	t.Helper()
+	header := []byte(`{"weight":{"dtype":"F16","shape":[2,2],"data_offsets":[0,8]}}`)
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create test file: %v", err)
+	}
+	defer file.Close()
+	if err := binary.Write(file, binary.LittleEndian, uint64(len(header))); err != nil {
+		t.Fatalf("failed to write header length: %v", err)
+	}
+	if _, err := file.Write(header); err != nil {
+		t.Fatalf("failed to write header: %v", err)
+	}
+	if _, err := file.Write(make([]byte, 8)); err != nil {
+		t.Fatalf("failed to write tensor data: %v", err)
+	}
+}
+
+func TestSafetensorsExtractConfig_ModelConfigJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	weightsPath := filepath.Join(tmpDir, "model.safetensors")
+	writeSafetensorsFile(t, weightsPath)
+
+	configJSON := `{
+  "architectures": ["LlamaForCausalLM"],
+  "model_type": "llama",
+  "torch_dtype": "bfloat16",
+  "max_position_embeddings": 131072,
+  "hidden_size": 4096
+}`
+	if err := os.WriteFile(filepath.Join(tmpDir, "config.json"), []byte(configJSON), 0o644); err != nil {
+		t.Fatalf("failed to write config.json: %v", err)
+	}
+
+	config, err := (&SafetensorsFormat{}).ExtractConfig([]string{weightsPath})
+	if err != nil {
+		t.Fatalf("ExtractConfig() error = %v", err)
+	}
+
+	if config.ContextSize != nil {
+		t.Errorf("expected max_position_embeddings not to set the context size, got %d", *config.ContextSize)
+	}
+	if config.Architecture != "LlamaForCausalLM" {
+		t.Errorf("Architecture = %q, want %q", config.Architecture, "LlamaForCausalLM")
+	}
+	for key, want := range map[string]string{
+		"architectures":           "LlamaForCausalLM",
+		"model_type":              "llama",
+		"torch_dtype":             "bfloat16",
+		"max_position_embeddings": "131072",
+		"tensor_count":            "1",
+	} {
+		if got := config.Safetensors[key]; got != want {
+			t.Errorf("Safetensors[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestSafetensorsExtractConfig_WithoutModelConfigJSON(t *testing.T) {
+	tmpDir := t.TempDir()
+	weightsPath := filepath.Join(tmpDir, "model.safetensors")
+	writeSafetensorsFile(t, weightsPath)
+
+	config, err := (&SafetensorsFormat{}).ExtractConfig([]string{weightsPath})
+	if err != nil {
+		t.Fatalf("ExtractConfig() error = %v", err)
+	}
+	if config.ContextSize != nil {
+		t.Errorf("expected no context size, got %d", *config.ContextSize)
+	}
+	if _, ok := config.Safetensors["max_position_embeddings"]; ok {
+		t.Error("expected no max_position_embeddings metadata")
+	}
+}
@@ -2,9 +2,11 @@ package huggingface
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -15,16 +17,21 @@ import (
 	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
+// ErrModelTooLarge is returned by BuildModel when the files to download exceed
+// the requested size limit.
+var ErrModelTooLarge = errors.New("model exceeds the maximum allowed size")
+
 // BuildModel downloads files from a HuggingFace repository and constructs an OCI model artifact
 // This is the main entry point for pulling native HuggingFace models
 // The tag parameter is used for GGUF repos to select the requested quantization (e.g., "Q4_K_M")
-func BuildModel(ctx context.Context, client *Client, repo, revision, tag string, tempDir string, progressWriter io.Writer) (types.ModelArtifact, error) {
-	// List files in the repository
-	if progressWriter != nil {
-		_ = progress.WriteProgress(progressWriter, "Fetching file list...", 0, 0, 0, "", "pull")
-	}
-
-	files, err := client.ListFiles(ctx, repo, revision)
+// If dir is not empty, only the files in that directory of the repository are used
+// If maxBytes is positive, nothing is downloaded when the selected files exceed it
+func BuildModel(ctx context.Context, client *Client, repo, revision, dir, tag string, tempDir string, progressWriter io.Writer, maxBytes int64) (types.ModelArtifact, error) {
+	// List files in the repository. Nothing is written to progressWriter
+	// until the size limit is checked, so that callers can still report a
+	// model that is too large as a plain error.
+	dir = strings.Trim(dir, "/")
+	files, err := client.listFilesRecursive(ctx, repo, revision, dir)
 	if err != nil {
 		return nil, fmt.Errorf("list files: %w", err)
 	}
@@ -33,11 +40,12 @@ func BuildModel(ctx context.Context, client *Client, repo, revision, tag string,
 	weightFiles, configFiles := FilterModelFiles(files)
 
 	if len(weightFiles) == 0 {
-		return nil, fmt.Errorf("no model weight files (GGUF or SafeTensors) found in repository %s", repo)
+		return nil, fmt.Errorf("no model weight files (GGUF or SafeTensors) found in repository %s", path.Join(repo, dir))
 	}
 
 	// For GGUF repos with multiple quantizations, select the appropriate files
 	var mmprojFile *RepoFile
+	var quantizationMsg string
 	if isGGUFModel(weightFiles) && len(weightFiles) > 1 {
 		// Use the tag as quantization hint (e.g., "Q4_K_M", "Q8_0", or "latest")
 		weightFiles, mmprojFile = SelectGGUFFiles(weightFiles, tag)
@@ -45,12 +53,10 @@ func BuildModel(ctx context.Context, client *Client, repo, revision, tag string,
 			return nil, fmt.Errorf("no GGUF files found matching quantization %q in repository %s", tag, repo)
 		}
 
-		if progressWriter != nil {
-			if tag == "" || tag == "latest" || tag == "main" {
-				_ = progress.WriteProgress(progressWriter, fmt.Sprintf("Selected %s quantization (default)", DefaultGGUFQuantization), 0, 0, 0, "", "pull")
-			} else {
-				_ = progress.WriteProgress(progressWriter, fmt.Sprintf("Selected %s quantization", tag), 0, 0, 0, "", "pull")
-			}
+		if tag == "" || tag == "latest" || tag == "main" {
+			quantizationMsg = fmt.Sprintf("Selected %s quantization (default)", DefaultGGUFQuantization)
+		} else {
+			quantizationMsg = fmt.Sprintf("Selected %s quantization", tag)
 		}
 	}
 
@@ -60,8 +66,15 @@ func BuildModel(ctx context.Context, client *Client, repo, revision, tag string,
 		allFiles = append(allFiles, *mmprojFile)
 	}
 
+	totalSize := TotalSize(allFiles)
+	if maxBytes > 0 && totalSize > maxBytes {
+		return nil, fmt.Errorf("%w: %d bytes exceeds the limit of %d bytes", ErrModelTooLarge, totalSize, maxBytes)
+	}
+
 	if progressWriter != nil {
-		totalSize := TotalSize(allFiles)
+		if quantizationMsg != "" {
+			_ = progress.WriteProgress(progressWriter, quantizationMsg, 0, 0, 0, "", "pull")
+		}
 		msg := fmt.Sprintf("Found %d files (%.2f MB total)",
 			len(allFiles), float64(totalSize)/1024/1024)
 		_ = progress.WriteProgress(progressWriter, msg, uint64(totalSize), 0, 0, "", "pull")
@@ -90,8 +103,9 @@ func BuildModel(ctx context.Context, client *Client, repo, revision, tag string,
 		_ = progress.WriteProgress(progressWriter, "Building model artifact...", 0, 0, 0, "", "pull")
 	}
 
+	// Files keep their repository paths, so a model in dir is rooted there.
 	model, err := buildModelFromFiles(
-		result.LocalPaths, weightFiles, configFiles, mmprojFile, tempDir, createdTime,
+		result.LocalPaths, weightFiles, configFiles, mmprojFile, filepath.Join(tempDir, filepath.FromSlash(dir)), createdTime,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("build model: %w", err)
@@ -1,6 +1,10 @@
 package huggingface
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
 	"path/filepath"
 	"testing"
 	"time"
@@ -81,3 +85,45 @@ func TestBuildGGUFModelV01WithoutMMProj(t *testing.T) {
 		}
 	}
 }
+
+// TestBuildModelDirAtRevision verifies that BuildModel only lists and
+// downloads the files in the requested directory, at the requested revision.
+func TestBuildModelDirAtRevision(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("read model file: %v", err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/models/org/model/tree/v1.0/gguf":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode([]RepoFile{
+				{Type: "file", Path: "gguf/model.gguf", Size: int64(len(data))},
+			})
+		case "/org/model/resolve/v1.0/gguf/model.gguf":
+			w.Write(data)
+		case "/api/models/org/model/revision/v1.0":
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(RepoInfo{LastModified: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)})
+		default:
+			t.Errorf("unexpected request: %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL))
+	artifact, err := BuildModel(t.Context(), client, "org/model", "v1.0", "gguf", "latest", t.TempDir(), nil, 0)
+	if err != nil {
+		t.Fatalf("BuildModel failed: %v", err)
+	}
+
+	manifest, err := artifact.Manifest()
+	if err != nil {
+		t.Fatalf("get manifest: %v", err)
+	}
+	if len(manifest.Layers) != 1 || manifest.Layers[0].MediaType != types.MediaTypeGGUF {
+		t.Errorf("expected a single %s layer, got %+v", types.MediaTypeGGUF, manifest.Layers)
+	}
+}
@@ -30,13 +30,15 @@ func Unpack(dir string, model types.Model) (*Bundle, error) {
 }
 
 // isV02Model checks if the model was packaged using V0.2 format (layer-per-file with annotations).
-// It does this by checking the config media type in the manifest.
+// It does this by checking the config media type in the manifest. Newer minor
+// versions build on V0.2 and are unpacked the same way.
 func isV02Model(model types.ModelArtifact) bool {
 	manifest, err := model.Manifest()
 	if err != nil {
 		return false
 	}
-	return manifest.Config.MediaType == types.MediaTypeModelConfigV02
+	major, minor, ok := types.ParseModelConfigVersion(manifest.Config.MediaType)
+	return ok && major == types.ModelConfigMajorVersion && minor >= 2
 }
 
 // isCNCFModel checks if the model was packaged using the CNCF ModelPack format.
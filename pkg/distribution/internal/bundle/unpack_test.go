@@ -268,6 +268,51 @@ func TestIsCNCFModel(t *testing.T) {
 	}
 }
 
+func TestIsV02Model(t *testing.T) {
+	tests := []struct {
+		name            string
+		configMediaType oci.MediaType
+		expected        bool
+	}{
+		{
+			name:            "Docker V0.1 config",
+			configMediaType: types.MediaTypeModelConfigV01,
+			expected:        false,
+		},
+		{
+			name:            "Docker V0.2 config",
+			configMediaType: types.MediaTypeModelConfigV02,
+			expected:        true,
+		},
+		{
+			name:            "newer Docker V0 minor version",
+			configMediaType: "application/vnd.docker.ai.model.config.v0.3+json",
+			expected:        true,
+		},
+		{
+			name:            "newer Docker major version",
+			configMediaType: "application/vnd.docker.ai.model.config.v1.0+json",
+			expected:        false,
+		},
+		{
+			name:            "CNCF ModelPack config V1",
+			configMediaType: modelpack.MediaTypeModelConfigV1,
+			expected:        false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact := &testArtifactWithConfigMediaType{
+				configMediaType: tt.configMediaType,
+			}
+			if result := isV02Model(artifact); result != tt.expected {
+				t.Errorf("isV02Model() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
 // testArtifactWithConfigMediaType is a minimal ModelArtifact for testing isCNCFModel/isV02Model.
 type testArtifactWithConfigMediaType struct {
 	configMediaType oci.MediaType
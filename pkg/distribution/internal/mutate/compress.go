@@ -0,0 +1,125 @@
+package mutate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/docker/model-runner/pkg/distribution/internal/partial"
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/types"
+)
+
+// compressedModel is a model whose layers have been replaced by compressed
+// copies. The config (and therefore the DiffIDs) is left untouched.
+type compressedModel struct {
+	types.ModelArtifact
+	layers     []oci.Layer
+	manifest   *oci.Manifest
+	compressed []string
+}
+
+// CompressLayers returns a variant of mdl in which every local layer with one
+// of the given media types is replaced by a zstd-compressed copy written to
+// dir. Layers that are not available locally are left as-is. The returned
+// cleanup function removes the compressed copies and must be called once the
+// model is no longer needed.
+func CompressLayers(mdl types.ModelArtifact, dir string, mediaTypes ...oci.MediaType) (types.ModelArtifact, func(), error) {
+	baseManifest, err := mdl.Manifest()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get manifest: %w", err)
+	}
+	manifest := *baseManifest
+	manifest.Layers = slices.Clone(baseManifest.Layers)
+
+	ls, err := mdl.Layers()
+	if err != nil {
+		return nil, nil, fmt.Errorf("get layers: %w", err)
+	}
+	if len(ls) != len(manifest.Layers) {
+		return nil, nil, fmt.Errorf("manifest has %d layers, model has %d", len(manifest.Layers), len(ls))
+	}
+
+	m := &compressedModel{
+		ModelArtifact: mdl,
+		layers:        slices.Clone(ls),
+		manifest:      &manifest,
+	}
+	cleanup := func() {
+		for _, path := range m.compressed {
+			_ = os.Remove(path)
+		}
+	}
+
+	for i, l := range ls {
+		layer, ok := l.(*partial.Layer)
+		if !ok || !slices.Contains(mediaTypes, layer.Descriptor.MediaType) {
+			continue
+		}
+		zl, err := partial.NewZstdLayer(layer, dir)
+		if err != nil {
+			cleanup()
+			return nil, nil, fmt.Errorf("compress layer %s: %w", layer.Descriptor.Digest, err)
+		}
+		m.compressed = append(m.compressed, zl.Path)
+		m.layers[i] = zl
+		manifest.Layers[i] = zl.Descriptor
+	}
+
+	return m, cleanup, nil
+}
+
+func (m *compressedModel) ID() (string, error) {
+	return partial.ID(m)
+}
+
+func (m *compressedModel) Layers() ([]oci.Layer, error) {
+	return m.layers, nil
+}
+
+func (m *compressedModel) MediaType() (oci.MediaType, error) {
+	return m.manifest.MediaType, nil
+}
+
+func (m *compressedModel) Size() (int64, error) {
+	return oci.Size(m)
+}
+
+func (m *compressedModel) Digest() (oci.Hash, error) {
+	return oci.Digest(m)
+}
+
+func (m *compressedModel) Manifest() (*oci.Manifest, error) {
+	return m.manifest, nil
+}
+
+func (m *compressedModel) RawManifest() ([]byte, error) {
+	return json.Marshal(m.manifest)
+}
+
+func (m *compressedModel) LayerByDigest(hash oci.Hash) (oci.Layer, error) {
+	for _, l := range m.layers {
+		d, err := l.Digest()
+		if err != nil {
+			return nil, fmt.Errorf("get layer digest: %w", err)
+		}
+		if d == hash {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("layer not found")
+}
+
+func (m *compressedModel) LayerByDiffID(hash oci.Hash) (oci.Layer, error) {
+	for _, l := range m.layers {
+		d, err := l.DiffID()
+		if err != nil {
+			return nil, fmt.Errorf("get layer diff id: %w", err)
+		}
+		if d == hash {
+			return l, nil
+		}
+	}
+	return nil, fmt.Errorf("layer not found")
+}
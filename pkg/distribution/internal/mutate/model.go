@@ -3,6 +3,8 @@ package mutate
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 
 	"github.com/docker/model-runner/pkg/distribution/internal/partial"
 	"github.com/docker/model-runner/pkg/distribution/oci"
@@ -14,6 +16,8 @@ type model struct {
 	appended        []oci.Layer
 	configMediaType oci.MediaType
 	contextSize     *int32
+	annotations     map[string]string
+	dropped         oci.MediaType
 }
 
 func (m *model) Descriptor() (types.Descriptor, error) {
@@ -95,6 +99,19 @@ func (m *model) Layers() ([]oci.Layer, error) {
 	if err != nil {
 		return nil, err
 	}
+	if m.dropped != "" {
+		kept := make([]oci.Layer, 0, len(ls))
+		for _, l := range ls {
+			mt, err := l.MediaType()
+			if err != nil {
+				return nil, fmt.Errorf("get layer media type: %w", err)
+			}
+			if mt != m.dropped {
+				kept = append(kept, l)
+			}
+		}
+		ls = kept
+	}
 	return append(ls, m.appended...), nil
 }
 
@@ -106,6 +123,18 @@ func (m *model) Manifest() (*oci.Manifest, error) {
 	if m.configMediaType != "" {
 		manifest.Config.MediaType = m.configMediaType
 	}
+	// Preserve the base model's manifest annotations, overlaid with our own.
+	base, err := m.base.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("get base manifest: %w", err)
+	}
+	if len(base.Annotations) > 0 || len(m.annotations) > 0 {
+		manifest.Annotations = maps.Clone(base.Annotations)
+		if manifest.Annotations == nil {
+			manifest.Annotations = make(map[string]string, len(m.annotations))
+		}
+		maps.Copy(manifest.Annotations, m.annotations)
+	}
 	return manifest, nil
 }
 
@@ -114,6 +143,30 @@ func (m *model) RawConfigFile() ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if m.dropped != "" {
+		ls, err := m.base.Layers()
+		if err != nil {
+			return nil, err
+		}
+		dropped := make(map[oci.Hash]bool)
+		for _, l := range ls {
+			mt, err := l.MediaType()
+			if err != nil {
+				return nil, fmt.Errorf("get layer media type: %w", err)
+			}
+			if mt != m.dropped {
+				continue
+			}
+			diffID, err := l.DiffID()
+			if err != nil {
+				return nil, err
+			}
+			dropped[diffID] = true
+		}
+		cf.RootFS.DiffIDs = slices.DeleteFunc(cf.RootFS.DiffIDs, func(diffID oci.Hash) bool {
+			return dropped[diffID]
+		})
+	}
 	for _, l := range m.appended {
 		diffID, err := l.DiffID()
 		if err != nil {
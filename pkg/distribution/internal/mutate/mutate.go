@@ -25,3 +25,20 @@ func ContextSize(mdl types.ModelArtifact, cs int32) types.ModelArtifact {
 		contextSize: &cs,
 	}
 }
+
+// Annotations returns mdl with the given annotations added to its manifest,
+// replacing any existing annotations with the same keys.
+func Annotations(mdl types.ModelArtifact, annotations map[string]string) types.ModelArtifact {
+	return &model{
+		base:        mdl,
+		annotations: annotations,
+	}
+}
+
+// DropLayers returns mdl without its layers of media type mt.
+func DropLayers(mdl types.ModelArtifact, mt oci.MediaType) types.ModelArtifact {
+	return &model{
+		base:    mdl,
+		dropped: mt,
+	}
+}
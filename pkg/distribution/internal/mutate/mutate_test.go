@@ -2,6 +2,7 @@ package mutate_test
 
 import (
 	"encoding/json"
+	"maps"
 	"path/filepath"
 	"testing"
 
@@ -52,6 +53,41 @@ func TestAppendLayer(t *testing.T) {
 	}
 }
 
+func TestDropLayers(t *testing.T) {
+	mdl1 := mutate.AppendLayers(
+		testutil.NewGGUFArtifact(t, filepath.Join("..", "..", "assets", "dummy.gguf")),
+		testutil.NewStaticLayer([]byte("some projector content"), types.MediaTypeMultimodalProjector),
+	)
+
+	// Drop the layer
+	mdl2 := mutate.DropLayers(mdl1, types.MediaTypeMultimodalProjector)
+
+	// Check the manifest
+	manifest2, err := mdl2.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to create model: %v", err)
+	}
+	if len(manifest2.Layers) != 1 {
+		t.Fatalf("Expected 1 layer, got %d", len(manifest2.Layers))
+	}
+	if manifest2.Layers[0].MediaType != types.MediaTypeGGUF {
+		t.Fatalf("Expected remaining layer to be %s, got %s", types.MediaTypeGGUF, manifest2.Layers[0].MediaType)
+	}
+
+	// Check the config file
+	rawCfg, err := mdl2.RawConfigFile()
+	if err != nil {
+		t.Fatalf("Failed to get raw config file: %v", err)
+	}
+	var cfg types.ConfigFile
+	if err := json.Unmarshal(rawCfg, &cfg); err != nil {
+		t.Fatalf("Failed to unmarshal config file: %v", err)
+	}
+	if len(cfg.RootFS.DiffIDs) != 1 || cfg.RootFS.DiffIDs[0] != manifest2.Layers[0].Digest {
+		t.Fatalf("Expected only the remaining layer's diff id in rootfs, got %v", cfg.RootFS.DiffIDs)
+	}
+}
+
 func TestConfigMediaTypes(t *testing.T) {
 	mdl1 := testutil.NewGGUFArtifact(t, filepath.Join("..", "..", "assets", "dummy.gguf"))
 	manifest1, err := mdl1.Manifest()
@@ -98,3 +134,48 @@ func TestContextSize(t *testing.T) {
 		t.Fatalf("Expected context size of 2096 got %d", *cfg2.GetContextSize())
 	}
 }
+
+func TestAnnotations(t *testing.T) {
+	mdl1 := testutil.NewGGUFArtifact(t, filepath.Join("..", "..", "assets", "dummy.gguf"))
+	manifest, err := mdl1.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to get manifest: %v", err)
+	}
+	if len(manifest.Annotations) != 0 {
+		t.Fatalf("Expected no annotations, got %v", manifest.Annotations)
+	}
+
+	mdl2 := mutate.Annotations(mdl1, map[string]string{
+		types.AnnotationSource:   "https://example.com/model",
+		types.AnnotationLicenses: "MIT",
+	})
+	// Annotations survive further mutation and can be overridden.
+	mdl3 := mutate.Annotations(mutate.ContextSize(mdl2, 2096), map[string]string{
+		types.AnnotationLicenses: "Apache-2.0",
+	})
+
+	manifest3, err := mdl3.Manifest()
+	if err != nil {
+		t.Fatalf("Failed to get manifest: %v", err)
+	}
+	want := map[string]string{
+		types.AnnotationSource:   "https://example.com/model",
+		types.AnnotationLicenses: "Apache-2.0",
+	}
+	if !maps.Equal(manifest3.Annotations, want) {
+		t.Errorf("Expected annotations %v, got %v", want, manifest3.Annotations)
+	}
+
+	// The annotations are part of the serialized manifest.
+	raw, err := mdl3.RawManifest()
+	if err != nil {
+		t.Fatalf("Failed to get raw manifest: %v", err)
+	}
+	var decoded oci.Manifest
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		t.Fatalf("Failed to decode raw manifest: %v", err)
+	}
+	if !maps.Equal(decoded.Annotations, want) {
+		t.Errorf("Expected serialized annotations %v, got %v", want, decoded.Annotations)
+	}
+}
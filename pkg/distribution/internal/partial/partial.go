@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/docker/model-runner/pkg/distribution/files"
 	"github.com/docker/model-runner/pkg/distribution/modelpack"
@@ -23,7 +26,49 @@ func Config(i WithRawConfigFile) (types.ModelConfig, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get raw config file: %w", err)
 	}
+	return parseConfig(raw)
+}
+
+// supportedConfigMediaTypes lists the config media types this client knows.
+// Docker configs with a newer minor version of ModelConfigMajorVersion are
+// also accepted and read as the newest version listed here.
+var supportedConfigMediaTypes = []oci.MediaType{
+	types.MediaTypeModelConfigV01,
+	types.MediaTypeModelConfigV02,
+	modelpack.MediaTypeModelConfigV1,
+}
+
+// CheckConfigMediaType returns an error wrapping types.ErrUnsupportedMediaType
+// if configs of media type mt cannot be read by this client.
+func CheckConfigMediaType(mt oci.MediaType) error {
+	if slices.Contains(supportedConfigMediaTypes, mt) {
+		return nil
+	}
+	if major, _, ok := types.ParseModelConfigVersion(mt); ok && major == types.ModelConfigMajorVersion {
+		return nil
+	}
+	supported := make([]string, len(supportedConfigMediaTypes))
+	for i, s := range supportedConfigMediaTypes {
+		supported[i] = strconv.Quote(string(s))
+	}
+	return fmt.Errorf(
+		"config type %q is not supported (supported: %s, or a newer v%d.x Docker config) - try upgrading: %w",
+		mt, strings.Join(supported, ", "), types.ModelConfigMajorVersion, types.ErrUnsupportedMediaType,
+	)
+}
+
+// ParseConfig decodes a raw config of media type mt. Fields unknown to this
+// client, such as those added by a newer minor config version, are ignored.
+// It returns an error wrapping types.ErrUnsupportedMediaType for media types
+// that CheckConfigMediaType rejects.
+func ParseConfig(raw []byte, mt oci.MediaType) (types.ModelConfig, error) {
+	if err := CheckConfigMediaType(mt); err != nil {
+		return nil, err
+	}
+	return parseConfig(raw)
+}
 
+func parseConfig(raw []byte) (types.ModelConfig, error) {
 	// ModelPack format: parse directly into modelpack.Model without conversion
 	if modelpack.IsModelPackConfig(raw) {
 		var mp modelpack.Model
@@ -1,6 +1,7 @@
 package partial_test
 
 import (
+	"errors"
 	"path/filepath"
 	"testing"
 
@@ -78,6 +79,69 @@ func TestConfig_NativeFormatSupport(t *testing.T) {
 	})
 }
 
+// TestParseConfig_VersionNegotiation tests that configs from newer minor
+// versions are read by their known fields while newer major versions are rejected.
+func TestParseConfig_VersionNegotiation(t *testing.T) {
+	// A synthetic future config that adds fields this client does not know.
+	futureJSON := `{
+		"config": {"format": "gguf", "parameters": "8B", "context_size": 4096, "tokenizer": {"type": "bpe"}},
+		"descriptor": {"created": "2025-01-15T10:30:00Z", "signature": "abc"},
+		"rootfs": {"type": "layers", "diff_ids": []},
+		"provenance": {"builder": "future-tool"}
+	}`
+
+	tests := []struct {
+		name      string
+		mediaType oci.MediaType
+	}{
+		{name: "V0.1", mediaType: types.MediaTypeModelConfigV01},
+		{name: "V0.2", mediaType: types.MediaTypeModelConfigV02},
+		{name: "newer V0 minor version", mediaType: "application/vnd.docker.ai.model.config.v0.3+json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := partial.ParseConfig([]byte(futureJSON), tt.mediaType)
+			if err != nil {
+				t.Fatalf("ParseConfig() error = %v", err)
+			}
+			if cfg.GetFormat() != types.FormatGGUF {
+				t.Errorf("GetFormat() = %v, want %v", cfg.GetFormat(), types.FormatGGUF)
+			}
+			if cfg.GetParameters() != "8B" {
+				t.Errorf("GetParameters() = %q, want %q", cfg.GetParameters(), "8B")
+			}
+			if cs := cfg.GetContextSize(); cs == nil || *cs != 4096 {
+				t.Errorf("GetContextSize() = %v, want 4096", cs)
+			}
+		})
+	}
+
+	t.Run("ModelPack V1", func(t *testing.T) {
+		modelPackJSON := `{"config": {"format": "gguf", "paramSize": "8B"}, "modelfs": {"type": "layers"}}`
+		cfg, err := partial.ParseConfig([]byte(modelPackJSON), modelpack.MediaTypeModelConfigV1)
+		if err != nil {
+			t.Fatalf("ParseConfig() error = %v", err)
+		}
+		if cfg.GetParameters() != "8B" {
+			t.Errorf("GetParameters() = %q, want %q", cfg.GetParameters(), "8B")
+		}
+	})
+
+	for _, mt := range []oci.MediaType{
+		"application/vnd.docker.ai.model.config.v1.0+json",
+		"application/vnd.docker.ai.model.config.v99.0+json",
+		"application/vnd.docker.ai.model.config+json",
+		oci.OCIConfigJSON,
+	} {
+		t.Run("rejects "+string(mt), func(t *testing.T) {
+			_, err := partial.ParseConfig([]byte(futureJSON), mt)
+			if !errors.Is(err, types.ErrUnsupportedMediaType) {
+				t.Fatalf("ParseConfig() error = %v, want %v", err, types.ErrUnsupportedMediaType)
+			}
+		})
+	}
+}
+
 // TestConfigFile tests ConfigFile() which is for Docker format only
 func TestConfigFile(t *testing.T) {
 	t.Run("Docker format parses correctly", func(t *testing.T) {
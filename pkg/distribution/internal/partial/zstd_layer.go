@@ -0,0 +1,106 @@
+package partial
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/klauspost/compress/zstd"
+)
+
+var _ oci.Layer = &ZstdLayer{}
+
+// ZstdLayer is a layer whose contents are transferred zstd-compressed. The
+// embedded descriptor describes the compressed blob, while DiffID reports the
+// digest of the original uncompressed contents.
+type ZstdLayer struct {
+	// Path is the local file path of the compressed blob.
+	Path string
+	// UncompressedPath is the local file path of the uncompressed contents.
+	UncompressedPath string
+	oci.Descriptor
+	diffID oci.Hash
+}
+
+// NewZstdLayer compresses the contents of l into a new file in dir and returns
+// a layer describing the compressed blob. Annotations are preserved and the
+// media type gains a "+zstd" suffix. The caller is responsible for removing
+// the compressed file once the layer is no longer needed.
+func NewZstdLayer(l *Layer, dir string) (*ZstdLayer, error) {
+	src, err := os.Open(l.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(dir, filepath.Base(l.Path)+"-*.zst")
+	if err != nil {
+		return nil, fmt.Errorf("create compressed file: %w", err)
+	}
+	defer dst.Close()
+
+	enc, err := zstd.NewWriter(dst)
+	if err != nil {
+		return nil, fmt.Errorf("create zstd encoder: %w", err)
+	}
+	if _, err := io.Copy(enc, src); err != nil {
+		enc.Close()
+		return nil, fmt.Errorf("compress layer: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("finish compressed layer: %w", err)
+	}
+
+	if _, err := dst.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("rewind compressed file: %w", err)
+	}
+	hash, size, err := oci.SHA256(dst)
+	if err != nil {
+		return nil, fmt.Errorf("hash compressed layer: %w", err)
+	}
+
+	return &ZstdLayer{
+		Path:             dst.Name(),
+		UncompressedPath: l.Path,
+		Descriptor: oci.Descriptor{
+			Size:        size,
+			Digest:      hash,
+			MediaType:   l.Descriptor.MediaType.WithZstd(),
+			Annotations: maps.Clone(l.Descriptor.Annotations),
+		},
+		diffID: l.Descriptor.Digest,
+	}, nil
+}
+
+func (l ZstdLayer) Digest() (oci.Hash, error) {
+	return l.Descriptor.Digest, nil
+}
+
+func (l ZstdLayer) DiffID() (oci.Hash, error) {
+	return l.diffID, nil
+}
+
+func (l ZstdLayer) Compressed() (io.ReadCloser, error) {
+	return os.Open(l.Path)
+}
+
+func (l ZstdLayer) Uncompressed() (io.ReadCloser, error) {
+	return os.Open(l.UncompressedPath)
+}
+
+func (l ZstdLayer) Size() (int64, error) {
+	return l.Descriptor.Size, nil
+}
+
+func (l ZstdLayer) MediaType() (oci.MediaType, error) {
+	return l.Descriptor.MediaType, nil
+}
+
+// GetDescriptor returns the full descriptor of the compressed blob, including
+// annotations.
+func (l ZstdLayer) GetDescriptor() oci.Descriptor {
+	return l.Descriptor
+}
@@ -0,0 +1,102 @@
+package partial_test
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/internal/partial"
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/types"
+)
+
+func TestZstdLayerRoundTrip(t *testing.T) {
+	// Highly compressible content so the compressed blob is clearly distinct.
+	content := bytes.Repeat([]byte("GGUF test content "), 4096)
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	if err := os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("Failed to write layer file: %v", err)
+	}
+
+	layer, err := partial.NewLayer(path, types.MediaTypeGGUF)
+	if err != nil {
+		t.Fatalf("NewLayer() error = %v", err)
+	}
+
+	zl, err := partial.NewZstdLayer(layer, t.TempDir())
+	if err != nil {
+		t.Fatalf("NewZstdLayer() error = %v", err)
+	}
+
+	mt, err := zl.MediaType()
+	if err != nil {
+		t.Fatalf("MediaType() error = %v", err)
+	}
+	if !mt.IsZstd() {
+		t.Errorf("MediaType() = %q, want zstd media type", mt)
+	}
+	if mt.WithoutZstd() != types.MediaTypeGGUF {
+		t.Errorf("MediaType().WithoutZstd() = %q, want %q", mt.WithoutZstd(), types.MediaTypeGGUF)
+	}
+
+	// DiffID must match the uncompressed content.
+	wantDiffID, _, err := oci.SHA256(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("SHA256() error = %v", err)
+	}
+	diffID, err := zl.DiffID()
+	if err != nil {
+		t.Fatalf("DiffID() error = %v", err)
+	}
+	if diffID != wantDiffID {
+		t.Errorf("DiffID() = %s, want %s", diffID, wantDiffID)
+	}
+
+	// Digest and Size must describe the compressed blob.
+	rc, err := zl.Compressed()
+	if err != nil {
+		t.Fatalf("Compressed() error = %v", err)
+	}
+	compressed, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("Failed to read compressed layer: %v", err)
+	}
+	wantDigest, _, err := oci.SHA256(bytes.NewReader(compressed))
+	if err != nil {
+		t.Fatalf("SHA256() error = %v", err)
+	}
+	digest, err := zl.Digest()
+	if err != nil {
+		t.Fatalf("Digest() error = %v", err)
+	}
+	if digest != wantDigest {
+		t.Errorf("Digest() = %s, want %s", digest, wantDigest)
+	}
+	if digest == diffID {
+		t.Error("Digest() should differ from DiffID() for a compressed layer")
+	}
+	size, err := zl.Size()
+	if err != nil {
+		t.Fatalf("Size() error = %v", err)
+	}
+	if size != int64(len(compressed)) {
+		t.Errorf("Size() = %d, want %d", size, len(compressed))
+	}
+
+	// Decompressing the compressed blob must yield the original content.
+	dr, err := oci.NewZstdReadCloser(io.NopCloser(bytes.NewReader(compressed)))
+	if err != nil {
+		t.Fatalf("NewZstdReadCloser() error = %v", err)
+	}
+	defer dr.Close()
+	decompressed, err := io.ReadAll(dr)
+	if err != nil {
+		t.Fatalf("Failed to decompress layer: %v", err)
+	}
+	if !bytes.Equal(decompressed, content) {
+		t.Error("Decompressed content does not match original content")
+	}
+}
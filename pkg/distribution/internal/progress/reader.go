@@ -2,15 +2,31 @@ package progress
 
 import (
 	"io"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
 )
 
+const (
+	// rateSampleInterval is the minimum time between throughput samples.
+	rateSampleInterval = 250 * time.Millisecond
+	// rateSmoothing is the weight given to the latest sample in the moving
+	// average throughput.
+	rateSmoothing = 0.3
+)
+
 // Reader wraps an io.Reader to track reading progress
 type Reader struct {
 	Reader       io.Reader
 	ProgressChan chan<- oci.Update
 	Total        int64
+
+	// now returns the current time; it defaults to time.Now.
+	now         func() time.Time
+	sampleAt    time.Time
+	sampleBytes int64
+	rate        float64
+	hasRate     bool
 }
 
 // NewReader returns a reader that reports progress to the given channel while reading.
@@ -38,15 +54,49 @@ func NewReaderWithOffset(r io.Reader, updates chan<- oci.Update, initialOffset i
 }
 
 func (pr *Reader) Read(p []byte) (int, error) {
+	if pr.sampleAt.IsZero() {
+		// Start measuring from the first read so that the initial offset of a
+		// resumed download does not count towards the throughput.
+		pr.sampleAt = pr.clock()
+		pr.sampleBytes = pr.Total
+	}
 	n, err := pr.Reader.Read(p)
 	pr.Total += int64(n)
+	pr.sample()
+	update := oci.Update{Complete: pr.Total, BytesPerSec: pr.rate}
 	if err == io.EOF {
-		pr.ProgressChan <- oci.Update{Complete: pr.Total}
+		pr.ProgressChan <- update
 	} else if n > 0 {
 		select {
-		case pr.ProgressChan <- oci.Update{Complete: pr.Total}:
+		case pr.ProgressChan <- update:
 		default: // if the progress channel is full, it skips sending rather than blocking the Read() call.
 		}
 	}
 	return n, err
 }
+
+// sample updates the moving average throughput once at least
+// rateSampleInterval has passed since the previous sample.
+func (pr *Reader) sample() {
+	now := pr.clock()
+	elapsed := now.Sub(pr.sampleAt)
+	if elapsed < rateSampleInterval {
+		return
+	}
+	current := float64(pr.Total-pr.sampleBytes) / elapsed.Seconds()
+	if pr.hasRate {
+		pr.rate = rateSmoothing*current + (1-rateSmoothing)*pr.rate
+	} else {
+		pr.rate = current
+		pr.hasRate = true
+	}
+	pr.sampleAt = now
+	pr.sampleBytes = pr.Total
+}
+
+func (pr *Reader) clock() time.Time {
+	if pr.now == nil {
+		return time.Now()
+	}
+	return pr.now()
+}
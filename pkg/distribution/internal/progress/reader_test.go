@@ -0,0 +1,158 @@
+package progress
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+)
+
+// fakeClock is a manually advanced clock.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+// pacedReader returns chunkSize bytes per Read, advancing clock by interval
+// each time, so that it appears to stream at chunkSize/interval bytes/s.
+type pacedReader struct {
+	clock     *fakeClock
+	chunkSize int
+	interval  time.Duration
+	remaining int
+}
+
+func (r *pacedReader) Read(p []byte) (int, error) {
+	if r.remaining <= 0 {
+		return 0, io.EOF
+	}
+	n := min(r.chunkSize, len(p), r.remaining)
+	r.clock.t = r.clock.t.Add(r.interval)
+	r.remaining -= n
+	return n, nil
+}
+
+// readAll reads r to EOF in chunks of up to size bytes.
+func readAll(t *testing.T, r io.Reader, size int) {
+	t.Helper()
+	buf := make([]byte, size)
+	for {
+		_, err := r.Read(buf)
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			t.Fatalf("Failed to read: %v", err)
+		}
+	}
+}
+
+// lastUpdate drains updates until the channel is closed and then delivers
+// the last one.
+func lastUpdate(updates <-chan oci.Update) <-chan oci.Update {
+	result := make(chan oci.Update, 1)
+	go func() {
+		var last oci.Update
+		for u := range updates {
+			last = u
+		}
+		result <- last
+	}()
+	return result
+}
+
+// readPaced reads a stream of total bytes at bytesPerSec through a progress
+// reader starting at offset, and returns the last update sent.
+func readPaced(t *testing.T, offset int64, total int, bytesPerSec int) oci.Update {
+	t.Helper()
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	const interval = 100 * time.Millisecond
+	src := &pacedReader{
+		clock:     clock,
+		chunkSize: bytesPerSec / int(time.Second/interval),
+		interval:  interval,
+		remaining: total,
+	}
+	updates := make(chan oci.Update, 1)
+	last := lastUpdate(updates)
+	r := NewReaderWithOffset(src, updates, offset).(*Reader)
+	r.now = clock.now
+
+	readAll(t, r, src.chunkSize)
+	close(updates)
+	return <-last
+}
+
+func assertRate(t *testing.T, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > want*0.05 {
+		t.Errorf("Expected throughput %.0f B/s (±5%%), got %.0f B/s", want, got)
+	}
+}
+
+func TestReaderThroughput(t *testing.T) {
+	const rate = 10 * 1024 * 1024
+	last := readPaced(t, 0, 5*rate, rate)
+	if last.Complete != 5*rate {
+		t.Errorf("Expected complete %d, got %d", 5*rate, last.Complete)
+	}
+	assertRate(t, last.BytesPerSec, rate)
+}
+
+func TestReaderThroughputWithOffset(t *testing.T) {
+	const rate = 10 * 1024 * 1024
+	const offset = 1 << 30
+	last := readPaced(t, offset, 5*rate, rate)
+	if last.Complete != offset+5*rate {
+		t.Errorf("Expected complete %d, got %d", offset+5*rate, last.Complete)
+	}
+	// The resume offset must not be counted as transferred in this session.
+	assertRate(t, last.BytesPerSec, rate)
+}
+
+func TestReaderThroughputMovingAverage(t *testing.T) {
+	clock := &fakeClock{t: time.Unix(0, 0)}
+	updates := make(chan oci.Update, 1)
+	last := lastUpdate(updates)
+	r := NewReader(nil, updates).(*Reader)
+	r.now = clock.now
+
+	// Stream at 1 MB/s, then at 4 MB/s; the average should converge to the
+	// new rate rather than the overall mean.
+	for _, rate := range []int{1 << 20, 4 << 20} {
+		r.Reader = &pacedReader{clock: clock, chunkSize: rate / 10, interval: 100 * time.Millisecond, remaining: 10 * rate}
+		readAll(t, r, rate/10)
+	}
+	close(updates)
+	assertRate(t, (<-last).BytesPerSec, 4<<20)
+}
+
+func TestReporterETA(t *testing.T) {
+	var buf bytes.Buffer
+	layer := newMockLayer(100 * 1024 * 1024)
+	reporter := NewProgressReporter(&buf, PullMsg, layer.size, layer, oci.ModePull)
+	updates := reporter.Updates()
+	updates <- oci.Update{Complete: 20 * 1024 * 1024, BytesPerSec: 10 * 1024 * 1024}
+	close(updates)
+	if err := reporter.Wait(); err != nil {
+		t.Fatalf("Reporter failed: %v", err)
+	}
+
+	var msg oci.ProgressMessage
+	if err := json.Unmarshal(buf.Bytes(), &msg); err != nil {
+		t.Fatalf("Failed to parse JSON: %v", err)
+	}
+	if msg.Layer.BytesPerSec != 10*1024*1024 {
+		t.Errorf("Expected layer throughput %d, got %.0f", 10*1024*1024, msg.Layer.BytesPerSec)
+	}
+	if msg.Layer.ETASeconds != 8 {
+		t.Errorf("Expected ETA of 8s, got %.2fs", msg.Layer.ETASeconds)
+	}
+}
@@ -25,6 +25,14 @@ type Reporter struct {
 	layer     oci.Layer
 	imageSize uint64
 	mode      oci.Mode
+
+	// interval and minBytes bound how often updates are written. They
+	// default to UpdateInterval and MinBytesForUpdate; minBytes <= 0 disables
+	// byte-based updates.
+	interval time.Duration
+	minBytes int64
+	// now returns the current time; it defaults to time.Now.
+	now func() time.Time
 }
 
 type progressF func(update oci.Update) string
@@ -46,6 +54,8 @@ func NewProgressReporter(w io.Writer, msgF progressF, imageSize int64, layer oci
 		layer:     layer,
 		imageSize: safeUint64(imageSize),
 		mode:      mode,
+		interval:  UpdateInterval,
+		minBytes:  MinBytesForUpdate,
 	}
 }
 
@@ -59,43 +69,47 @@ func safeUint64(n int64) uint64 {
 
 // Updates returns a channel for receiving progress Updates. It is the responsibility of the caller to close
 // the channel when they are done sending Updates. Should only be called once per Reporter instance.
+//
+// Updates are coalesced: one is written once the update interval has passed
+// or enough bytes were transferred since the previous one, or when the layer
+// is complete. The latest update withheld this way is written when the
+// channel is closed, so the final progress is always reported.
 func (r *Reporter) Updates() chan<- oci.Update {
 	go func() {
 		var lastComplete int64
 		var lastUpdate time.Time
+		var withheld *oci.Update
 
 		for p := range r.progress {
 			if r.out == nil || r.err != nil {
 				continue // If we fail to write progress, don't try again
 			}
-			now := time.Now()
-			var layerSize uint64
-			var layerID string
-			if r.layer != nil {
-				id, err := r.layer.DiffID()
-				if err != nil {
-					r.err = err
-					continue
-				}
-				layerID = id.String()
-				size, err := r.layer.Size()
-				if err != nil {
-					r.err = err
-					continue
-				}
-				layerSize = safeUint64(size)
+			now := r.clock()
+			layerID, layerSize, err := r.layerInfo()
+			if err != nil {
+				r.err = err
+				continue
 			}
 			incrementalBytes := p.Complete - lastComplete
 
 			// Only update if enough time has passed or enough bytes downloaded or finished
-			if now.Sub(lastUpdate) >= UpdateInterval ||
-				incrementalBytes >= MinBytesForUpdate ||
+			if now.Sub(lastUpdate) >= r.interval ||
+				(r.minBytes > 0 && incrementalBytes >= r.minBytes) ||
 				safeUint64(p.Complete) == layerSize {
-				if err := WriteProgress(r.out, r.format(p), r.imageSize, layerSize, safeUint64(p.Complete), layerID, r.mode); err != nil {
-					r.err = err
-				}
+				r.write(p, layerID, layerSize)
 				lastUpdate = now
 				lastComplete = p.Complete
+				withheld = nil
+			} else if p.Complete != lastComplete {
+				withheld = &p
+			}
+		}
+
+		if withheld != nil && r.err == nil {
+			if layerID, layerSize, err := r.layerInfo(); err != nil {
+				r.err = err
+			} else {
+				r.write(*withheld, layerID, layerSize)
 			}
 		}
 		close(r.done) // Close the done channel when progress is complete
@@ -103,6 +117,46 @@ func (r *Reporter) Updates() chan<- oci.Update {
 	return r.progress
 }
 
+// layerInfo returns the ID and size of the reported layer, if any.
+func (r *Reporter) layerInfo() (string, uint64, error) {
+	if r.layer == nil {
+		return "", 0, nil
+	}
+	id, err := r.layer.DiffID()
+	if err != nil {
+		return "", 0, err
+	}
+	size, err := r.layer.Size()
+	if err != nil {
+		return "", 0, err
+	}
+	return id.String(), safeUint64(size), nil
+}
+
+// write writes a progress update for the reported layer, recording any
+// error so that no further updates are attempted.
+func (r *Reporter) write(p oci.Update, layerID string, layerSize uint64) {
+	layer := oci.ProgressLayer{
+		ID:          layerID,
+		Size:        layerSize,
+		Current:     safeUint64(p.Complete),
+		BytesPerSec: p.BytesPerSec,
+	}
+	if p.BytesPerSec > 0 && layer.Size > layer.Current {
+		layer.ETASeconds = float64(layer.Size-layer.Current) / p.BytesPerSec
+	}
+	if err := writeLayerProgress(r.out, r.format(p), r.imageSize, layer, r.mode); err != nil {
+		r.err = err
+	}
+}
+
+func (r *Reporter) clock() time.Time {
+	if r.now == nil {
+		return time.Now()
+	}
+	return r.now()
+}
+
 // Wait waits for the progress Reporter to finish and returns any error encountered.
 func (r *Reporter) Wait() error {
 	<-r.done
@@ -111,16 +165,21 @@ func (r *Reporter) Wait() error {
 
 // WriteProgress writes a progress update message
 func WriteProgress(w io.Writer, msg string, imageSize, layerSize, current uint64, layerID string, mode oci.Mode) error {
+	return writeLayerProgress(w, msg, imageSize, oci.ProgressLayer{
+		ID:      layerID,
+		Size:    layerSize,
+		Current: current,
+	}, mode)
+}
+
+// writeLayerProgress writes a progress update message for the given layer
+func writeLayerProgress(w io.Writer, msg string, imageSize uint64, layer oci.ProgressLayer, mode oci.Mode) error {
 	return write(w, oci.ProgressMessage{
 		Type:    oci.TypeProgress,
 		Message: msg,
 		Total:   imageSize,
-		Layer: oci.ProgressLayer{
-			ID:      layerID,
-			Size:    layerSize,
-			Current: current,
-		},
-		Mode: mode,
+		Layer:   layer,
+		Mode:    mode,
 	})
 }
 
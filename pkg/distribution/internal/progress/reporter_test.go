@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"io"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -283,3 +284,89 @@ func TestProgressEmissionScenarios(t *testing.T) {
 		})
 	}
 }
+
+// parseMessages parses the newline-delimited progress messages in data.
+func parseMessages(t *testing.T, data []byte) []oci.ProgressMessage {
+	t.Helper()
+	var messages []oci.ProgressMessage
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var msg oci.ProgressMessage
+		if err := json.Unmarshal(line, &msg); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		messages = append(messages, msg)
+	}
+	return messages
+}
+
+func TestUpdatesCoalesced(t *testing.T) {
+	const (
+		reads    = 1000
+		readSize = 1024
+		readTime = time.Millisecond
+		total    = reads * readSize
+	)
+
+	tests := []struct {
+		name        string
+		layer       oci.Layer
+		interval    time.Duration
+		minBytes    int64
+		maxMessages int
+	}{
+		{
+			name:     "bounded by interval",
+			layer:    newMockLayer(total),
+			interval: 100 * time.Millisecond,
+			// One update per interval over the second of reads, plus the
+			// first and final updates.
+			maxMessages: int(reads*readTime/(100*time.Millisecond)) + 2,
+		},
+		{
+			name:        "bounded by bytes",
+			layer:       newMockLayer(total),
+			interval:    time.Hour,
+			minBytes:    100 * readSize,
+			maxMessages: reads/100 + 2,
+		},
+		{
+			name:        "final update of unknown size",
+			interval:    100 * time.Millisecond,
+			maxMessages: int(reads*readTime/(100*time.Millisecond)) + 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			var elapsed atomic.Int64
+			start := time.Unix(0, 0)
+			reporter := NewProgressReporter(&buf, PullMsg, total, tt.layer, oci.ModePull)
+			reporter.interval, reporter.minBytes = tt.interval, tt.minBytes
+			reporter.now = func() time.Time { return start.Add(time.Duration(elapsed.Load())) }
+			updates := reporter.Updates()
+
+			for i := 1; i <= reads; i++ {
+				elapsed.Add(int64(readTime))
+				updates <- oci.Update{Complete: int64(i * readSize)}
+			}
+			close(updates)
+			if err := reporter.Wait(); err != nil {
+				t.Fatalf("Reporter.Wait() failed: %v", err)
+			}
+
+			messages := parseMessages(t, buf.Bytes())
+			if len(messages) < 2 || len(messages) > tt.maxMessages {
+				t.Errorf("Expected between 2 and %d messages, got %d", tt.maxMessages, len(messages))
+			}
+			if len(messages) > 0 {
+				if last := messages[len(messages)-1]; last.Layer.Current != total {
+					t.Errorf("Expected final update at %d bytes, got %d", total, last.Layer.Current)
+				}
+			}
+		})
+	}
+}
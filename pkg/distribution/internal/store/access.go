@@ -0,0 +1,76 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// accessTimes maps model IDs to the time each model was last written or used.
+type accessTimes map[string]time.Time
+
+// accessPath returns the path to the access times file
+func (s *LocalStore) accessPath() string {
+	return filepath.Join(s.rootPath, "access.json")
+}
+
+// readAccessTimes reads the access times file. The caller must hold accessMu.
+func (s *LocalStore) readAccessTimes() (accessTimes, error) {
+	data, err := os.ReadFile(s.accessPath())
+	if errors.Is(err, os.ErrNotExist) {
+		return accessTimes{}, nil
+	} else if err != nil {
+		return nil, fmt.Errorf("reading access times file: %w", err)
+	}
+	times := accessTimes{}
+	if err := json.Unmarshal(data, &times); err != nil {
+		return nil, fmt.Errorf("unmarshaling access times: %w", err)
+	}
+	return times, nil
+}
+
+// updateAccessTimes applies update to the access times file.
+func (s *LocalStore) updateAccessTimes(update func(accessTimes)) error {
+	s.accessMu.Lock()
+	defer s.accessMu.Unlock()
+
+	times, err := s.readAccessTimes()
+	if err != nil {
+		return err
+	}
+	update(times)
+	data, err := json.MarshalIndent(times, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling access times: %w", err)
+	}
+	if err := writeFile(s.accessPath(), data, s.perm); err != nil {
+		return fmt.Errorf("writing access times file: %w", err)
+	}
+	return nil
+}
+
+// Touch records that the model with the given ID was used now.
+func (s *LocalStore) Touch(id string) error {
+	return s.updateAccessTimes(func(times accessTimes) {
+		times[id] = time.Now()
+	})
+}
+
+// forget drops the access time of the model with the given ID.
+func (s *LocalStore) forget(id string) error {
+	return s.updateAccessTimes(func(times accessTimes) {
+		delete(times, id)
+	})
+}
+
+// LastUsedTimes returns the time each model was last written or used, keyed
+// by model ID. Models without a recorded time are absent from the map.
+func (s *LocalStore) LastUsedTimes() (map[string]time.Time, error) {
+	s.accessMu.Lock()
+	defer s.accessMu.Unlock()
+
+	return s.readAccessTimes()
+}
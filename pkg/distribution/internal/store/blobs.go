@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
@@ -84,7 +85,7 @@ type blob interface {
 
 // writeLayer writes the layer blob to the store.
 // It returns true when a new blob was created and the blob's DiffID.
-func (s *LocalStore) writeLayer(layer blob, updates chan<- oci.Update, rangeSuccess *remote.RangeSuccess) (bool, oci.Hash, error) {
+func (s *LocalStore) writeLayer(layer blob, updates chan<- oci.Update, rangeSuccess *remote.RangeSuccess, overwrite bool) (bool, oci.Hash, error) {
 	hash, err := layer.DiffID()
 	if err != nil {
 		return false, oci.Hash{}, fmt.Errorf("get file hash: %w", err)
@@ -99,15 +100,26 @@ func (s *LocalStore) writeLayer(layer blob, updates chan<- oci.Update, rangeSucc
 		}
 	}
 
-	hasBlob, err := s.hasBlob(hash)
+	hasBlob, err := s.HasBlob(hash)
 	if err != nil {
 		return false, oci.Hash{}, fmt.Errorf("check blob existence: %w", err)
 	}
-	if hasBlob {
+	if hasBlob && !overwrite {
 		// TODO: write something to the progress channel (we probably need to redo progress reporting a little bit)
 		return false, hash, nil
 	}
 
+	// Compressed layers are decompressed while being written, so they are
+	// handled separately from the resumable uncompressed path.
+	if mt, ok := layer.(interface{ MediaType() (oci.MediaType, error) }); ok {
+		if m, mtErr := mt.MediaType(); mtErr == nil && m.IsZstd() {
+			created, diffID, err := s.writeZstdLayer(layer, hash, updates, overwrite)
+			// A blob that existed before is shared with other models and must
+			// not be removed if the write is rolled back.
+			return created && !hasBlob, diffID, err
+		}
+	}
+
 	// Check if we're resuming an incomplete download
 	incompleteSize, err := s.GetIncompleteSize(hash)
 	if err != nil {
@@ -139,10 +151,125 @@ func (s *LocalStore) writeLayer(layer blob, updates chan<- oci.Update, rangeSucc
 
 	// WriteBlob will handle appending to incomplete files
 	// The HTTP layer will handle resuming via Range headers
-	if err := s.WriteBlobWithResume(hash, r, layerDigestStr, rangeSuccess); err != nil {
+	if err := s.writeBlob(hash, r, layerDigestStr, rangeSuccess, overwrite); err != nil {
+		var mismatch *oci.DigestMismatchError
+		if errors.As(err, &mismatch) {
+			// A corrupt download must not be resumed by the next pull.
+			if removeErr := s.RemoveIncomplete(hash); removeErr != nil {
+				return false, hash, errors.Join(layerVerificationError(hash, mismatch), removeErr)
+			}
+			return false, hash, layerVerificationError(hash, mismatch)
+		}
 		return false, hash, err
 	}
-	return true, hash, nil
+	// The registry only verifies downloads fetched in full, so a resumed
+	// download is verified once it has been assembled.
+	if incompleteSize > 0 {
+		if err := s.verifyBlob(hash); err != nil {
+			return false, hash, err
+		}
+	}
+	return !hasBlob, hash, nil
+}
+
+// verifyBlob checks that the content of the blob with the given hash matches
+// the hash, removing the blob if it does not.
+func (s *LocalStore) verifyBlob(hash oci.Hash) error {
+	computed, err := s.hashBlob(hash)
+	if err != nil {
+		return err
+	}
+	if computed != hash {
+		if err := s.removeBlob(hash); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove corrupt blob %s: %w", hash, err)
+		}
+		return layerVerificationError(hash, &oci.DigestMismatchError{Expected: hash, Computed: computed})
+	}
+	return nil
+}
+
+// hashBlob computes the digest of the content of the blob stored under hash,
+// using the same algorithm.
+func (s *LocalStore) hashBlob(hash oci.Hash) (oci.Hash, error) {
+	path, err := s.blobPath(hash)
+	if err != nil {
+		return oci.Hash{}, fmt.Errorf("get blob path: %w", err)
+	}
+	hasher, err := oci.Hasher(hash.Algorithm)
+	if err != nil {
+		return oci.Hash{}, fmt.Errorf("create hasher: %w", err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return oci.Hash{}, fmt.Errorf("open blob %s: %w", hash, err)
+	}
+	defer f.Close()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return oci.Hash{}, fmt.Errorf("read blob %s: %w", hash, err)
+	}
+	return oci.Hash{
+		Algorithm: hash.Algorithm,
+		Hex:       hex.EncodeToString(hasher.Sum(nil)),
+	}, nil
+}
+
+// layerVerificationError reports that the content of the layer with the given
+// digest did not match.
+func layerVerificationError(layer oci.Hash, mismatch *oci.DigestMismatchError) error {
+	return fmt.Errorf("layer %s failed verification: %w", layer, mismatch)
+}
+
+// writeZstdLayer decompresses a zstd-compressed layer into the store and
+// verifies the result against the layer's uncompressed DiffID. Progress is
+// reported against the compressed stream, matching the layer size. Partial
+// downloads are discarded rather than resumed because offsets into the
+// compressed stream do not correspond to offsets into the stored blob.
+func (s *LocalStore) writeZstdLayer(layer blob, diffID oci.Hash, updates chan<- oci.Update, overwrite bool) (bool, oci.Hash, error) {
+	compressor, ok := layer.(interface{ Compressed() (io.ReadCloser, error) })
+	if !ok {
+		return false, oci.Hash{}, fmt.Errorf("layer %s does not provide compressed contents", diffID)
+	}
+
+	path, err := s.blobPath(diffID)
+	if err != nil {
+		return false, oci.Hash{}, fmt.Errorf("get blob path: %w", err)
+	}
+	if err := os.Remove(incompletePath(path)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, oci.Hash{}, fmt.Errorf("remove incomplete file: %w", err)
+	}
+
+	hasher, err := oci.Hasher(diffID.Algorithm)
+	if err != nil {
+		return false, oci.Hash{}, fmt.Errorf("create hasher: %w", err)
+	}
+
+	cr, err := compressor.Compressed()
+	if err != nil {
+		return false, oci.Hash{}, fmt.Errorf("get blob contents: %w", err)
+	}
+	defer cr.Close()
+
+	dr, err := oci.NewZstdReadCloser(io.NopCloser(progress.NewReader(cr, updates)))
+	if err != nil {
+		return false, oci.Hash{}, fmt.Errorf("create zstd decoder: %w", err)
+	}
+	defer dr.Close()
+
+	if err := s.writeBlob(diffID, io.TeeReader(dr, hasher), "", nil, overwrite); err != nil {
+		return false, diffID, err
+	}
+
+	computed := oci.Hash{
+		Algorithm: diffID.Algorithm,
+		Hex:       hex.EncodeToString(hasher.Sum(nil)),
+	}
+	if computed != diffID {
+		if err := s.removeBlob(diffID); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return false, diffID, fmt.Errorf("remove corrupt blob %s: %w", diffID, err)
+		}
+		return false, diffID, layerVerificationError(diffID, &oci.DigestMismatchError{Expected: diffID, Computed: computed})
+	}
+	return true, diffID, nil
 }
 
 // WriteBlob writes the blob to the store. For backwards compatibility, this version
@@ -156,11 +283,18 @@ func (s *LocalStore) WriteBlob(diffID oci.Hash, r io.Reader) error {
 // Range request for this digest, WriteBlob will append to the incomplete file instead
 // of starting fresh.
 func (s *LocalStore) WriteBlobWithResume(diffID oci.Hash, r io.Reader, digestStr string, rangeSuccess *remote.RangeSuccess) error {
-	hasBlob, err := s.hasBlob(diffID)
+	return s.writeBlob(diffID, r, digestStr, rangeSuccess, false)
+}
+
+// writeBlob implements WriteBlobWithResume. Unless overwrite is set, an
+// existing blob is kept and r is not read. Otherwise the blob is rewritten
+// from r and atomically replaces the existing one.
+func (s *LocalStore) writeBlob(diffID oci.Hash, r io.Reader, digestStr string, rangeSuccess *remote.RangeSuccess, overwrite bool) error {
+	hasBlob, err := s.HasBlob(diffID)
 	if err != nil {
 		return fmt.Errorf("check blob existence: %w", err)
 	}
-	if hasBlob {
+	if hasBlob && !overwrite {
 		return nil
 	}
 
@@ -216,7 +350,7 @@ func (s *LocalStore) WriteBlobWithResume(diffID oci.Hash, r io.Reader, digestStr
 		if shouldResume {
 			// Range request succeeded and offset matches - append to incomplete file
 			var openFileErr error
-			f, openFileErr = os.OpenFile(incompletePath, os.O_APPEND|os.O_WRONLY, 0644)
+			f, openFileErr = os.OpenFile(incompletePath, os.O_APPEND|os.O_WRONLY, s.perm.file)
 			if openFileErr != nil {
 				return fmt.Errorf("open incomplete file for resume: %w", openFileErr)
 			}
@@ -226,7 +360,7 @@ func (s *LocalStore) WriteBlobWithResume(diffID oci.Hash, r io.Reader, digestStr
 				return fmt.Errorf("remove incomplete file: %w", removeErr)
 			}
 			var createErr error
-			f, createErr = createFile(incompletePath)
+			f, createErr = createFile(incompletePath, s.perm)
 			if createErr != nil {
 				return fmt.Errorf("create blob file: %w", createErr)
 			}
@@ -250,19 +384,22 @@ func (s *LocalStore) WriteBlobWithResume(diffID oci.Hash, r io.Reader, digestStr
 		}
 	} else {
 		// No incomplete file exists - create new file
-		f, err = createFile(incompletePath)
+		f, err = createFile(incompletePath, s.perm)
 		if err != nil {
 			return fmt.Errorf("create blob file: %w", err)
 		}
 	}
 	defer f.Close()
 
-	if _, err := io.Copy(f, r); err != nil {
+	// Hide the file's ReadFrom and any WriteTo of r, so that the copy goes
+	// through the configured buffer rather than io.Copy's default one.
+	buf := make([]byte, s.copyBufferSize)
+	if _, err := io.CopyBuffer(struct{ io.Writer }{f}, struct{ io.Reader }{r}, buf); err != nil {
 		// Preserve incomplete file for all errors to allow resume attempts.
 		// Transient network errors (HTTP/2 stream errors, connection resets, etc.)
 		// should not cause the downloaded data to be discarded.
 		// Stale incomplete files are cleaned up during store initialization
-		// (CleanupStaleIncompleteFiles removes files older than 7 days).
+		// (CleanupStaleIncompleteFiles removes files older than StaleDownloadAge).
 		return fmt.Errorf("copy blob %q to store: %w", diffID.String(), err)
 	}
 
@@ -277,6 +414,53 @@ func (s *LocalStore) WriteBlobWithResume(diffID oci.Hash, r io.Reader, digestStr
 	return nil
 }
 
+// ImportBlob writes the blob read from r, which streams the whole content of
+// the blob, to the store and reports whether it was added. An existing blob is
+// kept and r is not read. If an earlier import of the blob was interrupted,
+// the bytes it wrote are skipped in r and the rest is appended to them, and the
+// assembled blob is verified against diffID.
+func (s *LocalStore) ImportBlob(diffID oci.Hash, r io.Reader) (bool, error) {
+	hasBlob, err := s.HasBlob(diffID)
+	if err != nil {
+		return false, fmt.Errorf("check blob existence: %w", err)
+	}
+	if hasBlob {
+		return false, nil
+	}
+
+	incompleteSize, err := s.GetIncompleteSize(diffID)
+	if err != nil {
+		return false, fmt.Errorf("check incomplete size: %w", err)
+	}
+	if incompleteSize == 0 {
+		if err := s.WriteBlob(diffID, r); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+
+	if _, err := io.CopyN(io.Discard, r, incompleteSize); err != nil {
+		if errors.Is(err, io.EOF) {
+			// The incomplete file is longer than the blob, so it cannot be
+			// resumed.
+			if removeErr := s.RemoveIncomplete(diffID); removeErr != nil {
+				return false, errors.Join(fmt.Errorf("incomplete blob %s is larger than the blob", diffID), removeErr)
+			}
+			return false, fmt.Errorf("incomplete blob %s is larger than the blob", diffID)
+		}
+		return false, fmt.Errorf("skip imported part of blob %s: %w", diffID, err)
+	}
+	var rangeSuccess remote.RangeSuccess
+	rangeSuccess.Add(diffID.String(), incompleteSize)
+	if err := s.WriteBlobWithResume(diffID, r, diffID.String(), &rangeSuccess); err != nil {
+		return false, err
+	}
+	if err := s.verifyBlob(diffID); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
 // removeBlob removes the blob with the given hash from the store.
 func (s *LocalStore) removeBlob(hash oci.Hash) error {
 	path, err := s.blobPath(hash)
@@ -286,7 +470,8 @@ func (s *LocalStore) removeBlob(hash oci.Hash) error {
 	return os.Remove(path)
 }
 
-func (s *LocalStore) hasBlob(hash oci.Hash) (bool, error) {
+// HasBlob reports whether the blob with the given hash is in the store.
+func (s *LocalStore) HasBlob(hash oci.Hash) (bool, error) {
 	path, err := s.blobPath(hash)
 	if err != nil {
 		return false, fmt.Errorf("get blob path: %w", err)
@@ -316,12 +501,35 @@ func (s *LocalStore) GetIncompleteSize(hash oci.Hash) (int64, error) {
 	return stat.Size(), nil
 }
 
-// createFile is a wrapper around os.Create that creates any parent directories as needed.
-func createFile(path string) (*os.File, error) {
-	if err := os.MkdirAll(filepath.Dir(path), 0777); err != nil {
+// RemoveIncomplete removes the incomplete download for the given hash, if any.
+func (s *LocalStore) RemoveIncomplete(hash oci.Hash) error {
+	path, err := s.blobPath(hash)
+	if err != nil {
+		return fmt.Errorf("get blob path: %w", err)
+	}
+
+	if err := os.Remove(incompletePath(path)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("remove incomplete file: %w", err)
+	}
+	return nil
+}
+
+// createFile is a wrapper around os.Create that creates any parent directories
+// as needed, with the modes in perm.
+func createFile(path string, perm permissions) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), perm.dir); err != nil {
 		return nil, fmt.Errorf("create parent directory %q: %w", filepath.Dir(path), err)
 	}
-	return os.Create(path)
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm.file)
+	if err != nil {
+		return nil, err
+	}
+	// Apply the mode the umask may have restricted.
+	if err := f.Chmod(perm.file); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("chmod %q: %w", path, err)
+	}
+	return f, nil
 }
 
 // incompletePath returns the path to the incomplete file for the given path.
@@ -335,7 +543,7 @@ func (s *LocalStore) writeConfigFile(mdl oci.Image) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("get digest: %w", err)
 	}
-	hasBlob, err := s.hasBlob(hash)
+	hasBlob, err := s.HasBlob(hash)
 	if err != nil {
 		return false, fmt.Errorf("check config existence: %w", err)
 	}
@@ -352,7 +560,7 @@ func (s *LocalStore) writeConfigFile(mdl oci.Image) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("get raw manifest: %w", err)
 	}
-	if err := writeFile(path, rcf); err != nil {
+	if err := writeFile(path, rcf, s.perm); err != nil {
 		return false, err
 	}
 	return true, nil
@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"testing"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
@@ -155,6 +156,182 @@ func TestBlobs(t *testing.T) {
 	})
 }
 
+func TestImportBlob(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1<<10)
+	hash, _, err := oci.SHA256(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("error calculating hash: %v", err)
+	}
+
+	// writeIncomplete leaves prefix behind as an interrupted import of hash.
+	writeIncomplete := func(t *testing.T, store *LocalStore, prefix []byte) string {
+		t.Helper()
+		blobPath, err := store.blobPath(hash)
+		if err != nil {
+			t.Fatalf("error getting blob path: %v", err)
+		}
+		if err := os.MkdirAll(filepath.Dir(blobPath), 0o755); err != nil {
+			t.Fatalf("error creating blob directory: %v", err)
+		}
+		if err := os.WriteFile(incompletePath(blobPath), prefix, 0o600); err != nil {
+			t.Fatalf("error writing incomplete blob: %v", err)
+		}
+		return blobPath
+	}
+
+	t.Run("resumes interrupted import", func(t *testing.T) {
+		store, err := New(Options{RootPath: t.TempDir()})
+		if err != nil {
+			t.Fatalf("error creating store: %v", err)
+		}
+		blobPath := writeIncomplete(t, store, content[:1000])
+		before, err := os.Stat(incompletePath(blobPath))
+		if err != nil {
+			t.Fatalf("error getting incomplete blob info: %v", err)
+		}
+
+		imported, err := store.ImportBlob(hash, bytes.NewReader(content))
+		if err != nil {
+			t.Fatalf("error importing blob: %v", err)
+		}
+		if !imported {
+			t.Error("expected blob to be reported as imported")
+		}
+		after, err := os.Stat(blobPath)
+		if err != nil {
+			t.Fatalf("error getting blob info: %v", err)
+		}
+		if !os.SameFile(before, after) {
+			t.Error("expected the incomplete blob to be appended to rather than rewritten")
+		}
+		got, err := os.ReadFile(blobPath)
+		if err != nil {
+			t.Fatalf("error reading blob file: %v", err)
+		}
+		if !bytes.Equal(got, content) {
+			t.Error("unexpected blob content")
+		}
+	})
+
+	t.Run("skips existing blob", func(t *testing.T) {
+		store, err := New(Options{RootPath: t.TempDir()})
+		if err != nil {
+			t.Fatalf("error creating store: %v", err)
+		}
+		if err := store.WriteBlob(hash, bytes.NewReader(content)); err != nil {
+			t.Fatalf("error writing blob: %v", err)
+		}
+		imported, err := store.ImportBlob(hash, &errorReader{})
+		if err != nil {
+			t.Fatalf("error importing blob: %v", err)
+		}
+		if imported {
+			t.Error("expected existing blob not to be reported as imported")
+		}
+	})
+
+	t.Run("rejects corrupt incomplete blob", func(t *testing.T) {
+		store, err := New(Options{RootPath: t.TempDir()})
+		if err != nil {
+			t.Fatalf("error creating store: %v", err)
+		}
+		blobPath := writeIncomplete(t, store, bytes.Repeat([]byte("x"), 1000))
+
+		_, err = store.ImportBlob(hash, bytes.NewReader(content))
+		var mismatch *oci.DigestMismatchError
+		if !errors.As(err, &mismatch) {
+			t.Fatalf("expected a DigestMismatchError, got %v", err)
+		}
+		if _, err := os.Stat(blobPath); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected corrupt blob to be removed, got %v", err)
+		}
+	})
+
+	t.Run("discards incomplete blob larger than the blob", func(t *testing.T) {
+		store, err := New(Options{RootPath: t.TempDir()})
+		if err != nil {
+			t.Fatalf("error creating store: %v", err)
+		}
+		blobPath := writeIncomplete(t, store, append(bytes.Clone(content), 'x'))
+
+		if _, err := store.ImportBlob(hash, bytes.NewReader(content)); err == nil {
+			t.Fatal("expected error importing blob")
+		}
+		if _, err := os.Stat(incompletePath(blobPath)); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expected incomplete blob to be removed, got %v", err)
+		}
+	})
+}
+
+func TestWriteBlobCopyBufferSize(t *testing.T) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 4<<16) // 4 MiB
+	hash, _, err := oci.SHA256(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("error calculating hash: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		configured int
+		expected   int
+	}{
+		{name: "default", configured: 0, expected: DefaultCopyBufferSize},
+		{name: "configured", configured: 64 << 10, expected: 64 << 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, err := New(Options{RootPath: t.TempDir(), CopyBufferSize: tt.configured})
+			if err != nil {
+				t.Fatalf("error creating store: %v", err)
+			}
+			r := &maxReadRecorder{r: bytes.NewReader(content)}
+			if err := store.WriteBlob(hash, r); err != nil {
+				t.Fatalf("error writing blob: %v", err)
+			}
+			if r.max != tt.expected {
+				t.Errorf("expected reads of up to %d bytes, got %d", tt.expected, r.max)
+			}
+		})
+	}
+}
+
+func BenchmarkWriteBlob(b *testing.B) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 16<<16) // 16 MiB
+	hash, _, err := oci.SHA256(bytes.NewReader(content))
+	if err != nil {
+		b.Fatalf("error calculating hash: %v", err)
+	}
+
+	for _, size := range []int{32 << 10, DefaultCopyBufferSize} {
+		b.Run(strconv.Itoa(size>>10)+"KiB", func(b *testing.B) {
+			store, err := New(Options{RootPath: b.TempDir(), CopyBufferSize: size})
+			if err != nil {
+				b.Fatalf("error creating store: %v", err)
+			}
+			b.SetBytes(int64(len(content)))
+			for b.Loop() {
+				if err := store.WriteBlob(hash, bytes.NewReader(content)); err != nil {
+					b.Fatalf("error writing blob: %v", err)
+				}
+				if err := store.removeBlob(hash); err != nil {
+					b.Fatalf("error removing blob: %v", err)
+				}
+			}
+		})
+	}
+}
+
+// maxReadRecorder records the largest read made from r.
+type maxReadRecorder struct {
+	r   io.Reader
+	max int
+}
+
+func (m *maxReadRecorder) Read(p []byte) (int, error) {
+	m.max = max(m.max, len(p))
+	return m.r.Read(p)
+}
+
 var _ io.Reader = &errorReader{}
 
 type errorReader struct {
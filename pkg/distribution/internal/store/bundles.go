@@ -29,6 +29,9 @@ func (s *LocalStore) BundleForModel(ref string) (types.ModelBundle, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get model ID: %w", err)
 	}
+	if err := s.Touch(dgst.String()); err != nil {
+		fmt.Printf("Warning: failed to record access time of %q: %v\n", dgst, err)
+	}
 	path := s.bundlePath(dgst)
 	bdl, err := bundle.Parse(path)
 	if err != nil {
@@ -44,7 +47,7 @@ func (s *LocalStore) createBundle(path string, mdl *Model) (types.ModelBundle, e
 	if err := os.RemoveAll(path); err != nil {
 		return nil, fmt.Errorf("remove %s: %w", path, err)
 	}
-	if err := os.MkdirAll(path, 0755); err != nil {
+	if err := os.MkdirAll(path, s.perm.dir); err != nil {
 		return nil, fmt.Errorf("create bundle directory: %w", err)
 	}
 	bdl, err := bundle.Unpack(path, mdl)
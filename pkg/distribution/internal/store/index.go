@@ -50,6 +50,51 @@ func (i Index) Tag(ref string, tag string) (Index, error) {
 	return result, nil
 }
 
+// Pin tags the model ref refers to with tag, as Tag does, and records that the
+// tag was pinned to it from source.
+func (i Index) Pin(ref string, tag string, source string) (Index, error) {
+	result, err := i.Tag(ref, tag)
+	if err != nil {
+		return Index{}, err
+	}
+	tagRef, err := reference.NewTag(tag, registry.GetDefaultRegistryOptions()...)
+	if err != nil {
+		return Index{}, fmt.Errorf("invalid tag: %w", err)
+	}
+	for n, entry := range result.Models {
+		if entry.MatchesReference(ref) {
+			result.Models[n] = entry.Pin(tagRef, source)
+		}
+	}
+	return result, nil
+}
+
+// PinnedFrom returns the tags pinned from source, mapped to the IDs of the
+// models they are pinned to.
+func (i Index) PinnedFrom(source string) map[string]string {
+	pinned := make(map[string]string)
+	for _, entry := range i.Models {
+		for tag, s := range entry.Pins {
+			if s == source {
+				pinned[tag] = entry.ID
+			}
+		}
+	}
+	return pinned
+}
+
+// referencedBlobs returns the set of the digests of the files the models in
+// the index reference.
+func (i Index) referencedBlobs() map[string]bool {
+	referenced := make(map[string]bool)
+	for _, m := range i.Models {
+		for _, file := range m.Files {
+			referenced[file] = true
+		}
+	}
+	return referenced
+}
+
 func (i Index) UnTag(tag string) (*reference.Tag, Index, error) {
 	tagRef, err := reference.NewTag(tag, registry.GetDefaultRegistryOptions()...)
 	if err != nil {
@@ -112,7 +157,7 @@ func (s *LocalStore) writeIndex(index Index) error {
 	}
 
 	// Write the models index
-	if err := writeFile(s.indexPath(), modelsData); err != nil {
+	if err := writeFile(s.indexPath(), modelsData, s.perm); err != nil {
 		return fmt.Errorf("writing models file: %w", err)
 	}
 
@@ -150,6 +195,14 @@ type IndexEntry struct {
 	Tags []string `json:"tags"`
 	// Files are the files associated with the model.
 	Files []string `json:"files"`
+	// Pins maps tags that were pinned to this model when they were created to
+	// the reference they were created from. The tag keeps pointing at ID even
+	// if that reference later resolves to another model.
+	Pins map[string]string `json:"pins,omitempty"`
+	// RemoteDigest is the digest of the manifest the model was pulled with,
+	// if it differs from ID because the manifest was rewritten to describe
+	// the stored blobs, as for models pushed with compressed layers.
+	RemoteDigest string `json:"remoteDigest,omitempty"`
 }
 
 func (e IndexEntry) HasTag(tag string) bool {
@@ -162,7 +215,7 @@ func (e IndexEntry) HasTag(tag string) bool {
 		if err != nil {
 			continue
 		}
-		if tr.String() == ref.String() {
+		if canonicalTag(tr) == canonicalTag(ref) {
 			return true
 		}
 	}
@@ -175,13 +228,17 @@ func (e IndexEntry) hasTag(tag *reference.Tag) bool {
 		if err != nil {
 			continue
 		}
-		if tr.String() == tag.String() {
+		if canonicalTag(tr) == canonicalTag(tag) {
 			return true
 		}
 	}
 	return false
 }
 
+// MatchesReference reports whether ref refers to the model: by ID, by one of
+// its tags, or by a digest reference whose digest is the model's manifest
+// digest, which is its ID. The repository of a digest reference is not
+// checked, so, as with IDs, it matches however the model is tagged.
 func (e IndexEntry) MatchesReference(ref string) bool {
 	if e.ID == ref {
 		return true
@@ -203,9 +260,11 @@ func (e IndexEntry) Tag(tag *reference.Tag) IndexEntry {
 		return e
 	}
 	return IndexEntry{
-		ID:    e.ID,
-		Tags:  append(e.Tags, tag.String()),
-		Files: e.Files,
+		ID:           e.ID,
+		Tags:         append(e.Tags, tag.String()),
+		Files:        e.Files,
+		Pins:         e.Pins,
+		RemoteDigest: e.RemoteDigest,
 	}
 }
 
@@ -216,14 +275,56 @@ func (e IndexEntry) UnTag(tag *reference.Tag) IndexEntry {
 		if err != nil {
 			continue
 		}
-		if tr.String() == tag.String() {
+		if canonicalTag(tr) == canonicalTag(tag) {
 			continue
 		}
 		tags = append(tags, e.Tags[i])
 	}
+	var pins map[string]string
+	for t, source := range e.Pins {
+		tr, err := reference.ParseReference(t, registry.GetDefaultRegistryOptions()...)
+		if err == nil && canonicalTag(tr) == canonicalTag(tag) {
+			continue
+		}
+		if pins == nil {
+			pins = make(map[string]string, len(e.Pins))
+		}
+		pins[t] = source
+	}
 	return IndexEntry{
-		ID:    e.ID,
-		Tags:  tags,
-		Files: e.Files,
+		ID:           e.ID,
+		Tags:         tags,
+		Files:        e.Files,
+		Pins:         pins,
+		RemoteDigest: e.RemoteDigest,
+	}
+}
+
+// Pin records that tag, which must already be a tag of the entry, was pinned
+// to the entry when created from source.
+func (e IndexEntry) Pin(tag *reference.Tag, source string) IndexEntry {
+	pins := make(map[string]string, len(e.Pins)+1)
+	for t, s := range e.UnTag(tag).Pins {
+		pins[t] = s
+	}
+	pins[tag.String()] = source
+	return IndexEntry{
+		ID:           e.ID,
+		Tags:         e.Tags,
+		Files:        e.Files,
+		Pins:         pins,
+		RemoteDigest: e.RemoteDigest,
+	}
+}
+
+// canonicalTag returns the string form of ref with Docker Hub's registry
+// aliases collapsed, so that tags under docker.io and index.docker.io match.
+func canonicalTag(ref reference.Reference) string {
+	s := ref.String()
+	if registry := ref.Context().Registry; registry.IsDockerHub() {
+		if rest, ok := strings.CutPrefix(s, registry.RegistryStr()+"/"); ok {
+			return "docker.io/" + rest
+		}
 	}
+	return s
 }
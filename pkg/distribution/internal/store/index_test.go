@@ -68,6 +68,15 @@ func TestMatchReference(t *testing.T) {
 			shouldMatch: true,
 			description: "digest reference match",
 		},
+		{
+			entry: store.IndexEntry{
+				ID:   "sha256:232a0650cd323d3b760854c4030f63ef11023d6eb3ef78327883f3f739f99def",
+				Tags: []string{"some-repo:latest", "some-repo:some-tag"},
+			},
+			reference:   "some-repo@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+			shouldMatch: false,
+			description: "digest reference mismatch",
+		},
 	}
 	for _, tc := range tcs {
 		t.Run(tc.description, func(t *testing.T) {
@@ -175,3 +184,41 @@ func TestUntag(t *testing.T) {
 		})
 	})
 }
+
+func TestPin(t *testing.T) {
+	idx := store.Index{
+		Models: []store.IndexEntry{
+			{
+				ID:   "some-id",
+				Tags: []string{"docker.io/ai/some-tag:latest"},
+			},
+			{
+				ID:   "other-id",
+				Tags: []string{"docker.io/ai/other-tag:latest"},
+			},
+		},
+	}
+	idx, err := idx.Pin("some-id", "pinned", "docker.io/ai/some-tag:latest")
+	if err != nil {
+		t.Fatalf("Error pinning tag: %v", err)
+	}
+	if got := idx.Models[0].Pins["docker.io/ai/pinned:latest"]; got != "docker.io/ai/some-tag:latest" {
+		t.Fatalf("Expected pinned tag to record its source, got %v", idx.Models[0].Pins)
+	}
+	pinned := idx.PinnedFrom("docker.io/ai/some-tag:latest")
+	if len(pinned) != 1 || pinned["docker.io/ai/pinned:latest"] != "some-id" {
+		t.Fatalf("Expected pinned tag to be pinned to 'some-id', got %v", pinned)
+	}
+
+	// Moving the tag to another model drops the pin.
+	idx, err = idx.Tag("other-id", "pinned")
+	if err != nil {
+		t.Fatalf("Error tagging entry: %v", err)
+	}
+	if len(idx.Models[0].Pins) != 0 {
+		t.Fatalf("Expected the pin to be dropped, got %v", idx.Models[0].Pins)
+	}
+	if pinned := idx.PinnedFrom("docker.io/ai/some-tag:latest"); len(pinned) != 0 {
+		t.Fatalf("Expected no pinned tags, got %v", pinned)
+	}
+}
@@ -4,8 +4,11 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
 )
@@ -21,12 +24,18 @@ func (s *LocalStore) manifestPath(hash oci.Hash) string {
 
 // WriteManifest writes the model's manifest to the store
 func (s *LocalStore) WriteManifest(hash oci.Hash, raw []byte) error {
+	return s.writeManifest(hash, raw, oci.Hash{})
+}
+
+// writeManifest writes the manifest to the store and records remoteDigest, if
+// set, as the digest of the manifest the model was pulled with.
+func (s *LocalStore) writeManifest(hash oci.Hash, raw []byte, remoteDigest oci.Hash) error {
 	manifest, err := oci.ParseManifest(bytes.NewReader(raw))
 	if err != nil {
 		return fmt.Errorf("parse manifest: %w", err)
 	}
 	for _, layer := range manifest.Layers {
-		hasBlob, err := s.hasBlob(layer.Digest)
+		hasBlob, err := s.HasBlob(layer.Digest)
 		if err != nil {
 			return fmt.Errorf("check blob existence: %w", err)
 		}
@@ -34,7 +43,7 @@ func (s *LocalStore) WriteManifest(hash oci.Hash, raw []byte) error {
 			return fmt.Errorf("missing blob %q for manifest - refusing to write unless all blobs exist", layer.Digest)
 		}
 	}
-	if err := writeFile(s.manifestPath(hash), raw); err != nil {
+	if err := writeFile(s.manifestPath(hash), raw, s.perm); err != nil {
 		return fmt.Errorf("write manifest: %w", err)
 	}
 
@@ -44,7 +53,13 @@ func (s *LocalStore) WriteManifest(hash oci.Hash, raw []byte) error {
 		return fmt.Errorf("reading models: %w", err)
 	}
 
-	if err := s.writeIndex(idx.Add(newEntryForManifest(hash, manifest))); err != nil {
+	idx = idx.Add(newEntryForManifest(hash, manifest))
+	if remoteDigest != (oci.Hash{}) {
+		if _, i, ok := idx.Find(hash.String()); ok {
+			idx.Models[i].RemoteDigest = remoteDigest.String()
+		}
+	}
+	if err := s.writeIndex(idx); err != nil {
 		// Best effort rollback to avoid leaving an orphaned manifest on disk.
 		if removeErr := s.removeManifest(hash); removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
 			return errors.Join(
@@ -54,9 +69,73 @@ func (s *LocalStore) WriteManifest(hash oci.Hash, raw []byte) error {
 		}
 		return fmt.Errorf("write models index: %w", err)
 	}
+	if err := s.Touch(hash.String()); err != nil {
+		fmt.Printf("Warning: failed to record access time of %q: %v\n", hash, err)
+	}
 	return nil
 }
 
+// localManifest returns the digest and raw manifest under which mdl is recorded
+// in the store. Layers are always stored uncompressed under their DiffID, so
+// descriptors of compressed layers are rewritten to describe the stored blobs.
+// As a result, the local ID of a model pulled with compressed layers differs
+// from its remote manifest digest, which Write records in the index entry.
+func (s *LocalStore) localManifest(mdl oci.Image) (oci.Hash, []byte, error) {
+	digest, err := mdl.Digest()
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("get digest: %w", err)
+	}
+	rm, err := mdl.RawManifest()
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("get raw manifest: %w", err)
+	}
+	manifest, err := oci.ParseManifest(bytes.NewReader(rm))
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if !slices.ContainsFunc(manifest.Layers, func(d oci.Descriptor) bool { return d.MediaType.IsZstd() }) {
+		return digest, rm, nil
+	}
+
+	layers, err := mdl.Layers()
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("get layers: %w", err)
+	}
+	if len(layers) != len(manifest.Layers) {
+		return oci.Hash{}, nil, fmt.Errorf("manifest has %d layers, model has %d", len(manifest.Layers), len(layers))
+	}
+	for i, desc := range manifest.Layers {
+		if !desc.MediaType.IsZstd() {
+			continue
+		}
+		diffID, err := layers[i].DiffID()
+		if err != nil {
+			return oci.Hash{}, nil, fmt.Errorf("get layer diff id: %w", err)
+		}
+		path, err := s.blobPath(diffID)
+		if err != nil {
+			return oci.Hash{}, nil, fmt.Errorf("get blob path: %w", err)
+		}
+		stat, err := os.Stat(path)
+		if err != nil {
+			return oci.Hash{}, nil, fmt.Errorf("stat blob %s: %w", diffID, err)
+		}
+		manifest.Layers[i].Digest = diffID
+		manifest.Layers[i].Size = stat.Size()
+		manifest.Layers[i].MediaType = desc.MediaType.WithoutZstd()
+	}
+
+	raw, err := manifest.RawManifest()
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("marshal manifest: %w", err)
+	}
+	digest, _, err = oci.SHA256(bytes.NewReader(raw))
+	if err != nil {
+		return oci.Hash{}, nil, fmt.Errorf("compute digest: %w", err)
+	}
+	return digest, raw, nil
+}
+
 func newEntryForManifest(digest oci.Hash, manifest *oci.Manifest) IndexEntry {
 	files := make([]string, len(manifest.Layers)+1)
 	for i := range manifest.Layers {
@@ -75,10 +154,23 @@ func (s *LocalStore) removeManifest(hash oci.Hash) error {
 	return os.Remove(s.manifestPath(hash))
 }
 
-// writeFile is a wrapper around os.WriteFile that creates any parent directories as needed.
-func writeFile(path string, data []byte) error {
+// writeTemp and renameTemp perform the write and rename steps of writeFile.
+// They are variables so that tests can simulate a crash part way through.
+var (
+	writeTemp = func(w io.Writer, data []byte) error {
+		_, err := w.Write(data)
+		return err
+	}
+	renameTemp = os.Rename
+)
+
+// writeFile atomically replaces the file at path with data, creating any
+// parent directories as needed, with the modes in perm. The data is written to a temporary file in
+// the same directory, synced, and renamed over path, so a crash leaves
+// either the previous contents or the new ones but never a partial file.
+func writeFile(path string, data []byte, perm permissions) error {
 	dir := filepath.Dir(path)
-	if err := os.MkdirAll(dir, 0o755); err != nil {
+	if err := os.MkdirAll(dir, perm.dir); err != nil {
 		return fmt.Errorf("create parent directory %q: %w", dir, err)
 	}
 
@@ -91,7 +183,7 @@ func writeFile(path string, data []byte) error {
 		_ = os.Remove(tmpName)
 	}
 
-	if _, err := tmp.Write(data); err != nil {
+	if err := writeTemp(tmp, data); err != nil {
 		tmp.Close()
 		cleanup()
 		return fmt.Errorf("write temporary file %q: %w", tmpName, err)
@@ -105,20 +197,46 @@ func writeFile(path string, data []byte) error {
 		cleanup()
 		return fmt.Errorf("close temporary file %q: %w", tmpName, err)
 	}
-	if err := os.Chmod(tmpName, 0o644); err != nil {
+	if err := os.Chmod(tmpName, perm.file); err != nil {
 		cleanup()
 		return fmt.Errorf("chmod temporary file %q: %w", tmpName, err)
 	}
-	if err := os.Rename(tmpName, path); err != nil {
+	if err := renameTemp(tmpName, path); err != nil {
+		// Renames replace existing files atomically on Unix, so a failure
+		// there is not something removing the file would fix. On Windows,
+		// replacing a file can fail while it is open, leaving no choice but
+		// to remove it first.
+		if runtime.GOOS != "windows" {
+			cleanup()
+			return fmt.Errorf("replace %q with temporary file: %w", path, err)
+		}
 		removeErr := os.Remove(path)
 		if removeErr != nil && !errors.Is(removeErr, os.ErrNotExist) {
 			cleanup()
 			return fmt.Errorf("replace %q with temporary file: %w (also failed to remove existing file: %w)", path, err, removeErr)
 		}
-		if err := os.Rename(tmpName, path); err != nil {
+		if err := renameTemp(tmpName, path); err != nil {
 			cleanup()
 			return fmt.Errorf("replace %q with temporary file: %w", path, err)
 		}
 	}
+	return syncDir(dir)
+}
+
+// syncDir flushes the directory entry of a renamed file to disk, so that the
+// rename itself survives a crash. Directories cannot be synced on Windows,
+// where renames are durable once they return.
+func syncDir(dir string) error {
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	d, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("open directory %q: %w", dir, err)
+	}
+	defer d.Close()
+	if err := d.Sync(); err != nil {
+		return fmt.Errorf("sync directory %q: %w", dir, err)
+	}
 	return nil
 }
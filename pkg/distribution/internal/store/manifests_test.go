@@ -0,0 +1,93 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWriteFileKeepsOriginalOnFailure(t *testing.T) {
+	original := []byte(`{"schemaVersion":2,"layers":["original"]}`)
+	replacement := []byte(`{"schemaVersion":2,"layers":["replacement"]}`)
+
+	tests := []struct {
+		name   string
+		inject func(t *testing.T)
+	}{
+		{
+			name: "write fails",
+			inject: func(t *testing.T) {
+				// Write half of the data and then fail, as a crash part way
+				// through the write would.
+				defaultWriteTemp := writeTemp
+				writeTemp = func(w io.Writer, data []byte) error {
+					if _, err := w.Write(data[:len(data)/2]); err != nil {
+						return err
+					}
+					return errors.New("simulated crash during write")
+				}
+				t.Cleanup(func() { writeTemp = defaultWriteTemp })
+			},
+		},
+		{
+			name: "crash between write and rename",
+			inject: func(t *testing.T) {
+				if runtime.GOOS == "windows" {
+					t.Skip("failed renames fall back to removing the original on Windows")
+				}
+				defaultRenameTemp := renameTemp
+				renameTemp = func(string, string) error { return errors.New("simulated crash before rename") }
+				t.Cleanup(func() { renameTemp = defaultRenameTemp })
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "manifests", "sha256", "abc")
+			if err := writeFile(path, original, defaultPermissions); err != nil {
+				t.Fatalf("Failed to write original file: %v", err)
+			}
+
+			tt.inject(t)
+			if err := writeFile(path, replacement, defaultPermissions); err == nil {
+				t.Fatal("Expected write to fail")
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("Failed to read file: %v", err)
+			}
+			if string(got) != string(original) {
+				t.Errorf("Expected original contents %q, got %q", original, got)
+			}
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatalf("Failed to read directory: %v", err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("Expected temporary files to be cleaned up, got %d entries", len(entries))
+			}
+		})
+	}
+}
+
+func TestWriteFileReplacesContents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "models.json")
+	for _, data := range []string{"first", "second"} {
+		if err := writeFile(path, []byte(data), defaultPermissions); err != nil {
+			t.Fatalf("Failed to write file: %v", err)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read file: %v", err)
+		}
+		if string(got) != data {
+			t.Errorf("Expected contents %q, got %q", data, got)
+		}
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"os"
 
 	mdpartial "github.com/docker/model-runner/pkg/distribution/internal/partial"
 	"github.com/docker/model-runner/pkg/distribution/oci"
@@ -19,10 +18,12 @@ type Model struct {
 	rawConfigFile []byte
 	layers        []oci.Layer
 	tags          []string
+	pins          map[string]string
+	remoteDigest  string
 }
 
 func (s *LocalStore) newModel(digest oci.Hash, tags []string) (*Model, error) {
-	rawManifest, err := os.ReadFile(s.manifestPath(digest))
+	rawManifest, err := s.readFile(s.manifestPath(digest))
 	if err != nil {
 		return nil, fmt.Errorf("read manifest: %w", err)
 	}
@@ -36,7 +37,7 @@ func (s *LocalStore) newModel(digest oci.Hash, tags []string) (*Model, error) {
 	if err != nil {
 		return nil, fmt.Errorf("get config blob path: %w", err)
 	}
-	rawConfigFile, err := os.ReadFile(configPath)
+	rawConfigFile, err := s.readFile(configPath)
 	if err != nil {
 		return nil, fmt.Errorf("read config file: %w", err)
 	}
@@ -169,6 +170,22 @@ func (m *Model) Tags() []string {
 	return m.tags
 }
 
+// Pins maps the tags of the model that were pinned to it to the references
+// they were created from.
+func (m *Model) Pins() map[string]string {
+	return m.pins
+}
+
+// RemoteDigest returns the digest of the manifest the model was pulled with.
+// It is the model's digest unless the manifest was rewritten when stored, as
+// for models pushed with compressed layers.
+func (m *Model) RemoteDigest() (oci.Hash, error) {
+	if m.remoteDigest != "" {
+		return oci.NewHash(m.remoteDigest)
+	}
+	return m.Digest()
+}
+
 func (m *Model) ID() (string, error) {
 	return mdpartial.ID(m)
 }
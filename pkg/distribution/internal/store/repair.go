@@ -0,0 +1,141 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+)
+
+// RepairReport describes the inconsistencies found and fixed by Repair.
+type RepairReport struct {
+	// RemovedBlobs lists the digests of the blobs removed because no model
+	// references them.
+	RemovedBlobs []string
+	// RemovedIncomplete lists the digests of the incomplete downloads removed.
+	RemovedIncomplete []string
+	// ReclaimedBytes is the combined size of the removed files.
+	ReclaimedBytes int64
+	// MissingBlobs maps the IDs of models whose blobs are missing from the
+	// store to the missing digests. These models are left in place.
+	MissingBlobs map[string][]string
+}
+
+// Repair brings the store back to a consistent state after a crash part way
+// through a write. It removes blobs that no model references and incomplete
+// downloads that are older than staleAfter or whose blob is already complete,
+// and reports models that reference missing blobs. It only lists and stats
+// files, so it is cheap enough to run on startup, but it must not run
+// concurrently with writes to the store.
+func (s *LocalStore) Repair(staleAfter time.Duration) (RepairReport, error) {
+	report := RepairReport{MissingBlobs: make(map[string][]string)}
+	idx, err := s.readIndex()
+	if err != nil {
+		return report, fmt.Errorf("reading models file: %w", err)
+	}
+	referenced := idx.referencedBlobs()
+
+	algorithms, err := os.ReadDir(s.blobsDir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return report, fmt.Errorf("reading blobs directory: %w", err)
+	}
+
+	present := make(map[string]bool)
+	var errs []error
+	for _, algorithm := range algorithms {
+		if !algorithm.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.blobsDir(), algorithm.Name())
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("reading %s: %w", dir, err))
+			continue
+		}
+
+		var incomplete []os.DirEntry
+		for _, entry := range entries {
+			if entry.IsDir() {
+				continue
+			}
+			if strings.HasSuffix(entry.Name(), ".incomplete") {
+				incomplete = append(incomplete, entry)
+				continue
+			}
+			digest, ok := blobDigest(algorithm.Name(), entry.Name())
+			if !ok {
+				continue
+			}
+			if referenced[digest] {
+				present[digest] = true
+				continue
+			}
+			size, err := removeEntry(dir, entry)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("remove unreferenced blob %s: %w", digest, err))
+				continue
+			}
+			report.RemovedBlobs = append(report.RemovedBlobs, digest)
+			report.ReclaimedBytes += size
+		}
+
+		// Incomplete downloads are kept for resuming unless they are stale or
+		// the blob they were downloading is already complete.
+		for _, entry := range incomplete {
+			digest, ok := blobDigest(algorithm.Name(), strings.TrimSuffix(entry.Name(), ".incomplete"))
+			if !ok {
+				continue
+			}
+			info, err := entry.Info()
+			if err != nil {
+				continue
+			}
+			if !present[digest] && time.Since(info.ModTime()) <= staleAfter {
+				continue
+			}
+			size, err := removeEntry(dir, entry)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("remove incomplete download %s: %w", digest, err))
+				continue
+			}
+			report.RemovedIncomplete = append(report.RemovedIncomplete, digest)
+			report.ReclaimedBytes += size
+		}
+	}
+
+	for _, m := range idx.Models {
+		for _, file := range m.Files {
+			if !present[file] && !slices.Contains(report.MissingBlobs[m.ID], file) {
+				report.MissingBlobs[m.ID] = append(report.MissingBlobs[m.ID], file)
+			}
+		}
+	}
+	return report, errors.Join(errs...)
+}
+
+// blobDigest returns the digest of the blob stored as name in the directory
+// of algorithm, if name is a valid blob file name.
+func blobDigest(algorithm, name string) (string, bool) {
+	hash := oci.Hash{Algorithm: algorithm, Hex: name}
+	if validateHash(hash) != nil {
+		return "", false
+	}
+	return hash.String(), true
+}
+
+// removeEntry removes entry from dir and returns its size.
+func removeEntry(dir string, entry os.DirEntry) (int64, error) {
+	var size int64
+	if info, err := entry.Info(); err == nil {
+		size = info.Size()
+	}
+	if err := os.Remove(filepath.Join(dir, entry.Name())); err != nil {
+		return 0, err
+	}
+	return size, nil
+}
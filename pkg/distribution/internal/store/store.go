@@ -6,6 +6,8 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"sync"
 	"time"
 
@@ -17,11 +19,24 @@ import (
 const (
 	// CurrentVersion is the current version of the store layout
 	CurrentVersion = "1.0.0"
+	// StaleDownloadAge is how long incomplete downloads are kept for resuming
+	// before they are cleaned up.
+	StaleDownloadAge = 7 * 24 * time.Hour
 )
 
 // LocalStore implements the Store interface for local storage
 type LocalStore struct {
 	rootPath string
+	readFile func(name string) ([]byte, error)
+	// copyBufferSize is the size of the buffer blobs are written through.
+	copyBufferSize int
+	// verifyConcurrency is the maximum number of blobs VerifyModel hashes at
+	// once.
+	verifyConcurrency int
+	// perm holds the modes of the files and directories the store creates.
+	perm permissions
+	// accessMu serializes updates of the access times file.
+	accessMu sync.Mutex
 }
 
 // RootPath returns the root path of the store
@@ -29,15 +44,73 @@ func (s *LocalStore) RootPath() string {
 	return s.rootPath
 }
 
+// DefaultCopyBufferSize is the size of the buffer blobs are written through
+// when Options.CopyBufferSize is not set. It is well above the 32 KiB io.Copy
+// uses, so multi-gigabyte blobs are written in fewer, larger writes.
+const DefaultCopyBufferSize = 1 << 20
+
+// DefaultFileMode and DefaultDirMode are the modes of the files and
+// directories the store creates when Options.FileMode and Options.DirMode are
+// not set. They keep models private to the user running the store.
+const (
+	DefaultFileMode os.FileMode = 0o600
+	DefaultDirMode  os.FileMode = 0o700
+)
+
+// permissions are the modes of the files and directories the store creates.
+type permissions struct {
+	file os.FileMode
+	dir  os.FileMode
+}
+
+// defaultPermissions are the permissions used when Options sets none.
+var defaultPermissions = permissions{file: DefaultFileMode, dir: DefaultDirMode}
+
 // Options represents options for creating a store
 type Options struct {
 	RootPath string
+	// ReadFile, if set, is used instead of os.ReadFile to read manifests and
+	// config blobs. It exists so tests can observe disk reads.
+	ReadFile func(name string) ([]byte, error)
+	// CopyBufferSize is the size of the buffer blobs are written through.
+	// Zero selects DefaultCopyBufferSize.
+	CopyBufferSize int
+	// VerifyConcurrency is the maximum number of blobs VerifyModel hashes at
+	// once. Zero selects runtime.GOMAXPROCS(0), as hashing is CPU-bound.
+	VerifyConcurrency int
+	// FileMode is the mode of the blobs, manifests and index files the store
+	// writes. It is applied regardless of the umask. Zero selects
+	// DefaultFileMode.
+	FileMode os.FileMode
+	// DirMode is the mode of the directories the store creates, which the
+	// umask may restrict further. Zero selects DefaultDirMode. Existing files
+	// and directories keep their modes.
+	DirMode os.FileMode
 }
 
 // New creates a new LocalStore
 func New(opts Options) (*LocalStore, error) {
 	store := &LocalStore{
-		rootPath: opts.RootPath,
+		rootPath:          opts.RootPath,
+		readFile:          opts.ReadFile,
+		copyBufferSize:    opts.CopyBufferSize,
+		verifyConcurrency: opts.VerifyConcurrency,
+		perm:              permissions{file: opts.FileMode.Perm(), dir: opts.DirMode.Perm()},
+	}
+	if store.readFile == nil {
+		store.readFile = os.ReadFile
+	}
+	if store.copyBufferSize <= 0 {
+		store.copyBufferSize = DefaultCopyBufferSize
+	}
+	if store.verifyConcurrency <= 0 {
+		store.verifyConcurrency = runtime.GOMAXPROCS(0)
+	}
+	if store.perm.file == 0 {
+		store.perm.file = DefaultFileMode
+	}
+	if store.perm.dir == 0 {
+		store.perm.dir = DefaultDirMode
 	}
 
 	// Initialize store if it doesn't exist
@@ -95,9 +168,9 @@ func (s *LocalStore) initialize() error {
 		}
 	}
 
-	// Clean up stale incomplete files (older than 7 days)
+	// Clean up stale incomplete files (older than StaleDownloadAge)
 	// This prevents disk space leaks from abandoned downloads
-	if err := s.CleanupStaleIncompleteFiles(7 * 24 * time.Hour); err != nil {
+	if err := s.CleanupStaleIncompleteFiles(StaleDownloadAge); err != nil {
 		// Log the error but don't fail initialization
 		fmt.Printf("Warning: failed to clean up stale incomplete files: %v\n", err)
 	}
@@ -167,11 +240,180 @@ func (s *LocalStore) Delete(ref string) (string, []string, error) {
 		}
 	}
 
+	if err := s.forget(model.ID); err != nil {
+		fmt.Printf("Warning: failed to remove access time of %q: %v\n", model.ID, err)
+	}
+
 	idx = idx.Remove(model.ID)
 
 	return model.ID, model.Tags, s.writeIndex(idx)
 }
 
+// DiscardBlobs removes the given blobs, along with any incomplete writes of
+// them, unless a model in the index references them. It cleans up after an
+// aborted import so that no unreferenced blobs are left behind.
+func (s *LocalStore) DiscardBlobs(hashes []oci.Hash) error {
+	idx, err := s.readIndex()
+	if err != nil {
+		return fmt.Errorf("reading models file: %w", err)
+	}
+	referenced := make(map[string]bool)
+	for _, m := range idx.Models {
+		for _, file := range m.Files {
+			referenced[file] = true
+		}
+	}
+
+	var errs []error
+	for _, hash := range hashes {
+		if err := s.RemoveIncomplete(hash); err != nil {
+			errs = append(errs, err)
+		}
+		if referenced[hash.String()] {
+			continue
+		}
+		if err := s.removeBlob(hash); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, fmt.Errorf("remove blob %q: %w", hash.String(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Prune deletes every model without tags. It returns the IDs of the deleted
+// models and the combined size of the blobs removed from disk, which excludes
+// blobs still referenced by the remaining models.
+func (s *LocalStore) Prune() ([]string, int64, error) {
+	idx, err := s.readIndex()
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading models file: %w", err)
+	}
+
+	kept := make(map[string]bool)
+	var dangling []IndexEntry
+	for _, m := range idx.Models {
+		if len(m.Tags) > 0 {
+			for _, file := range m.Files {
+				kept[file] = true
+			}
+			continue
+		}
+		dangling = append(dangling, m)
+	}
+
+	// Measure the blobs before deleting anything, counting each shared blob once.
+	var reclaimed int64
+	counted := make(map[string]bool)
+	for _, m := range dangling {
+		for _, file := range m.Files {
+			if kept[file] || counted[file] {
+				continue
+			}
+			counted[file] = true
+			hash, err := oci.NewHash(file)
+			if err != nil {
+				continue
+			}
+			path, err := s.blobPath(hash)
+			if err != nil {
+				continue
+			}
+			if info, err := os.Stat(path); err == nil {
+				reclaimed += info.Size()
+			}
+		}
+	}
+
+	deleted := make([]string, 0, len(dangling))
+	for _, m := range dangling {
+		id, _, err := s.Delete(m.ID)
+		if err != nil {
+			return deleted, 0, fmt.Errorf("deleting model %q: %w", m.ID, err)
+		}
+		deleted = append(deleted, id)
+	}
+	return deleted, reclaimed, nil
+}
+
+// EvictLRU deletes the least recently used models until the blobs of the
+// remaining models take up at most maxBytes. Models for which keep returns
+// true are never evicted, so the store may remain above maxBytes. It returns
+// the evicted models, least recently used first.
+func (s *LocalStore) EvictLRU(maxBytes int64, keep func(IndexEntry) bool) ([]IndexEntry, error) {
+	idx, err := s.readIndex()
+	if err != nil {
+		return nil, fmt.Errorf("reading models file: %w", err)
+	}
+
+	// Count the references to each blob and measure each blob once.
+	refs := make(map[string]int)
+	sizes := make(map[string]int64)
+	var total int64
+	for _, m := range idx.Models {
+		for _, file := range m.Files {
+			refs[file]++
+			if _, ok := sizes[file]; ok {
+				continue
+			}
+			sizes[file] = s.blobSize(file)
+			total += sizes[file]
+		}
+	}
+	if total <= maxBytes {
+		return nil, nil
+	}
+
+	s.accessMu.Lock()
+	times, err := s.readAccessTimes()
+	s.accessMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	var candidates []IndexEntry
+	for _, m := range idx.Models {
+		if keep == nil || !keep(m) {
+			candidates = append(candidates, m)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b IndexEntry) int {
+		return times[a.ID].Compare(times[b.ID])
+	})
+
+	var evicted []IndexEntry
+	for _, m := range candidates {
+		if total <= maxBytes {
+			break
+		}
+		if _, _, err := s.Delete(m.ID); err != nil {
+			return evicted, fmt.Errorf("deleting model %q: %w", m.ID, err)
+		}
+		evicted = append(evicted, m)
+		for _, file := range m.Files {
+			if refs[file]--; refs[file] == 0 {
+				total -= sizes[file]
+			}
+		}
+	}
+	return evicted, nil
+}
+
+// blobSize returns the size of the blob stored for the given digest, or zero
+// if it cannot be determined.
+func (s *LocalStore) blobSize(file string) int64 {
+	hash, err := oci.NewHash(file)
+	if err != nil {
+		return 0
+	}
+	path, err := s.blobPath(hash)
+	if err != nil {
+		return 0
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
+
 // AddTags adds tags to an existing model
 func (s *LocalStore) AddTags(ref string, newTags []string) error {
 	index, err := s.readIndex()
@@ -188,6 +430,30 @@ func (s *LocalStore) AddTags(ref string, newTags []string) error {
 	return s.writeIndex(index)
 }
 
+// PinTag adds tag to the model ref refers to and records that it was pinned to
+// that model from source.
+func (s *LocalStore) PinTag(ref string, tag string, source string) error {
+	index, err := s.readIndex()
+	if err != nil {
+		return fmt.Errorf("reading models file: %w", err)
+	}
+	index, err = index.Pin(ref, tag, source)
+	if err != nil {
+		return fmt.Errorf("pinning tag: %w", err)
+	}
+	return s.writeIndex(index)
+}
+
+// PinnedFrom returns the tags pinned from source, mapped to the IDs of the
+// models they are pinned to.
+func (s *LocalStore) PinnedFrom(source string) (map[string]string, error) {
+	index, err := s.readIndex()
+	if err != nil {
+		return nil, fmt.Errorf("reading models file: %w", err)
+	}
+	return index.PinnedFrom(source), nil
+}
+
 // RemoveTags removes tags from models
 func (s *LocalStore) RemoveTags(tags []string) ([]string, error) {
 	index, err := s.readIndex()
@@ -238,6 +504,7 @@ type WriteOption func(*writeOptions)
 
 type writeOptions struct {
 	rangeSuccess *remote.RangeSuccess
+	overwrite    bool
 }
 
 // WithRangeSuccess passes a RangeSuccess tracker for resume detection.
@@ -247,6 +514,14 @@ func WithRangeSuccess(rs *remote.RangeSuccess) WriteOption {
 	}
 }
 
+// WithOverwrite rewrites layer blobs already present in the store instead of
+// reusing them.
+func WithOverwrite() WriteOption {
+	return func(o *writeOptions) {
+		o.overwrite = true
+	}
+}
+
 // Write writes a model to the store
 func (s *LocalStore) Write(mdl oci.Image, tags []string, w io.Writer, opts ...WriteOption) (err error) {
 	var options writeOptions
@@ -341,7 +616,7 @@ func (s *LocalStore) Write(mdl oci.Image, tags []string, w io.Writer, opts ...Wr
 				progressChan = pr.Updates()
 			}
 
-			created, diffID, err := s.writeLayer(l, progressChan, options.rangeSuccess)
+			created, diffID, err := s.writeLayer(l, progressChan, options.rangeSuccess, options.overwrite)
 
 			if progressChan != nil {
 				close(progressChan)
@@ -399,13 +674,16 @@ func (s *LocalStore) Write(mdl oci.Image, tags []string, w io.Writer, opts ...Wr
 	}
 
 	// Write the manifest
-	digest, err := mdl.Digest()
+	digest, rm, err := s.localManifest(mdl)
 	if err != nil {
-		return fmt.Errorf("get digest: %w", err)
+		return err
 	}
-	rm, err := mdl.RawManifest()
+	remoteDigest, err := mdl.Digest()
 	if err != nil {
-		return fmt.Errorf("get raw manifest: %w", err)
+		return fmt.Errorf("get digest: %w", err)
+	}
+	if remoteDigest == digest {
+		remoteDigest = oci.Hash{}
 	}
 	manifestExists := false
 	if _, statErr := os.Stat(s.manifestPath(digest)); statErr == nil {
@@ -413,7 +691,7 @@ func (s *LocalStore) Write(mdl oci.Image, tags []string, w io.Writer, opts ...Wr
 	} else if !errors.Is(statErr, os.ErrNotExist) {
 		return fmt.Errorf("stat manifest: %w", statErr)
 	}
-	if err := s.WriteManifest(digest, rm); err != nil {
+	if err := s.writeManifest(digest, rm, remoteDigest); err != nil {
 		return fmt.Errorf("write manifest: %w", err)
 	}
 	if !manifestExists {
@@ -480,7 +758,7 @@ func (s *LocalStore) WriteLightweight(mdl oci.Image, tags []string) (err error)
 		if err != nil {
 			return fmt.Errorf("getting layer digest: %w", err)
 		}
-		hasBlob, err := s.hasBlob(digest)
+		hasBlob, err := s.HasBlob(digest)
 		if err != nil {
 			return fmt.Errorf("checking if layer %s exists: %w", digest, err)
 		}
@@ -568,6 +846,13 @@ func (s *LocalStore) MigrateTags(transform func(string) string) (int, error) {
 			newTags[j] = newTag
 		}
 		index.Models[i].Tags = newTags
+		if len(entry.Pins) > 0 {
+			pins := make(map[string]string, len(entry.Pins))
+			for tag, source := range entry.Pins {
+				pins[transform(tag)] = transform(source)
+			}
+			index.Models[i].Pins = pins
+		}
 	}
 
 	if changed {
@@ -593,7 +878,13 @@ func (s *LocalStore) Read(reference string) (*Model, error) {
 			if err != nil {
 				return nil, fmt.Errorf("parsing hash: %w", err)
 			}
-			return s.newModel(hash, model.Tags)
+			mdl, err := s.newModel(hash, model.Tags)
+			if err != nil {
+				return nil, err
+			}
+			mdl.pins = model.Pins
+			mdl.remoteDigest = model.RemoteDigest
+			return mdl, nil
 		}
 	}
 
@@ -8,8 +8,11 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/internal/mutate"
 	"github.com/docker/model-runner/pkg/distribution/internal/store"
@@ -1106,6 +1109,289 @@ func TestResetAllowsEmptyNonStoreDirectory(t *testing.T) {
 	}
 }
 
+func TestPrune(t *testing.T) {
+	tempDir := t.TempDir()
+
+	storePath := filepath.Join(tempDir, "prune-store")
+	s, err := store.New(store.Options{
+		RootPath: storePath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	writeModel := func(name string, content []byte, tags []string) (string, oci.Hash) {
+		t.Helper()
+		path := filepath.Join(tempDir, name+".gguf")
+		if err := os.WriteFile(path, content, 0644); err != nil {
+			t.Fatalf("Failed to write model file: %v", err)
+		}
+		mdl := testutil.BuildModelFromPath(t, path)
+		if err := s.Write(mdl, tags, nil); err != nil {
+			t.Fatalf("Write %s failed: %v", name, err)
+		}
+		id, err := mdl.ID()
+		if err != nil {
+			t.Fatalf("Failed to get model ID: %v", err)
+		}
+		configName, err := mdl.ConfigName()
+		if err != nil {
+			t.Fatalf("Failed to get config name: %v", err)
+		}
+		return id, configName
+	}
+	blobPath := func(hex string) string {
+		return filepath.Join(storePath, "blobs", "sha256", hex)
+	}
+	blobSize := func(path string) int64 {
+		t.Helper()
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatalf("Failed to stat blob: %v", err)
+		}
+		return info.Size()
+	}
+
+	sharedContent := []byte("content shared by a tagged and an untagged model")
+	danglingContent := []byte("content only used by an untagged model")
+	sharedHash := sha256.Sum256(sharedContent)
+	danglingHash := sha256.Sum256(danglingContent)
+
+	taggedID, _ := writeModel("tagged", sharedContent, []string{"tagged:latest"})
+	sharedID, sharedConfig := writeModel("dangling-shared", sharedContent, nil)
+	danglingID, danglingConfig := writeModel("dangling", danglingContent, nil)
+
+	// Only blobs not referenced by the tagged model count as reclaimed.
+	wantReclaimed := blobSize(blobPath(hex.EncodeToString(danglingHash[:]))) +
+		blobSize(blobPath(danglingConfig.Hex)) +
+		blobSize(blobPath(sharedConfig.Hex))
+
+	deleted, reclaimed, err := s.Prune()
+	if err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+
+	if len(deleted) != 2 {
+		t.Fatalf("Expected 2 deleted models, got %v", deleted)
+	}
+	for _, id := range []string{sharedID, danglingID} {
+		if !slices.Contains(deleted, id) {
+			t.Errorf("Expected %s to be deleted, got %v", id, deleted)
+		}
+		if _, err := s.Read(id); !errors.Is(err, store.ErrModelNotFound) {
+			t.Errorf("Expected ErrModelNotFound for pruned model %s, got: %v", id, err)
+		}
+	}
+	if reclaimed != wantReclaimed {
+		t.Errorf("Expected %d bytes reclaimed, got %d", wantReclaimed, reclaimed)
+	}
+
+	// The tagged model and its blobs are untouched.
+	if _, err := s.Read("tagged:latest"); err != nil {
+		t.Fatalf("Tagged model should be unaffected: %v", err)
+	}
+	if _, err := os.Stat(blobPath(hex.EncodeToString(sharedHash[:]))); err != nil {
+		t.Errorf("Shared blob should have been kept: %v", err)
+	}
+	if _, err := os.Stat(blobPath(hex.EncodeToString(danglingHash[:]))); !os.IsNotExist(err) {
+		t.Errorf("Dangling blob should have been removed")
+	}
+
+	models, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != taggedID {
+		t.Errorf("Expected only the tagged model to remain, got %v", models)
+	}
+
+	// Pruning again is a no-op.
+	deleted, reclaimed, err = s.Prune()
+	if err != nil {
+		t.Fatalf("Second Prune failed: %v", err)
+	}
+	if len(deleted) != 0 || reclaimed != 0 {
+		t.Errorf("Expected second prune to be a no-op, got %v and %d bytes", deleted, reclaimed)
+	}
+}
+
+func TestEvictLRU(t *testing.T) {
+	tempDir := t.TempDir()
+
+	storePath := filepath.Join(tempDir, "evict-store")
+	s, err := store.New(store.Options{
+		RootPath: storePath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	writeModel := func(name string) string {
+		t.Helper()
+		path := filepath.Join(tempDir, name+".gguf")
+		if err := os.WriteFile(path, []byte(strings.Repeat(name, 1000)), 0644); err != nil {
+			t.Fatalf("Failed to write model file: %v", err)
+		}
+		mdl := testutil.BuildModelFromPath(t, path)
+		if err := s.Write(mdl, []string{name + ":latest"}, nil); err != nil {
+			t.Fatalf("Write %s failed: %v", name, err)
+		}
+		id, err := mdl.ID()
+		if err != nil {
+			t.Fatalf("Failed to get model ID: %v", err)
+		}
+		return id
+	}
+	storeSize := func() int64 {
+		t.Helper()
+		var size int64
+		err := filepath.WalkDir(filepath.Join(storePath, "blobs"), func(path string, d os.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			size += info.Size()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to measure store: %v", err)
+		}
+		return size
+	}
+
+	oldest := writeModel("oldest")
+	older := writeModel("older")
+	locked := writeModel("locked")
+	recent := writeModel("recent")
+	used := writeModel("used")
+	// Using the first model makes it the most recently used one.
+	if _, err := s.BundleForModel(oldest); err != nil {
+		t.Fatalf("BundleForModel failed: %v", err)
+	}
+	keep := func(entry store.IndexEntry) bool {
+		return entry.ID == locked
+	}
+
+	// Under the cap, nothing is evicted.
+	evicted, err := s.EvictLRU(storeSize(), keep)
+	if err != nil {
+		t.Fatalf("EvictLRU failed: %v", err)
+	}
+	if len(evicted) != 0 {
+		t.Fatalf("Expected no evictions under the cap, got %v", evicted)
+	}
+
+	// Just over the cap, only the least recently used model is evicted.
+	evicted, err = s.EvictLRU(storeSize()-1, keep)
+	if err != nil {
+		t.Fatalf("EvictLRU failed: %v", err)
+	}
+	if len(evicted) != 1 || evicted[0].ID != older {
+		t.Fatalf("Expected only %s to be evicted, got %v", older, evicted)
+	}
+	if _, err := s.Read(older); !errors.Is(err, store.ErrModelNotFound) {
+		t.Errorf("Expected ErrModelNotFound for evicted model, got: %v", err)
+	}
+	for _, id := range []string{oldest, locked, recent, used} {
+		if _, err := s.Read(id); err != nil {
+			t.Errorf("Expected %s to be retained: %v", id, err)
+		}
+	}
+
+	// With no room at all, every model but the kept one is evicted, least
+	// recently used first.
+	evicted, err = s.EvictLRU(0, keep)
+	if err != nil {
+		t.Fatalf("EvictLRU failed: %v", err)
+	}
+	var evictedIDs []string
+	for _, entry := range evicted {
+		evictedIDs = append(evictedIDs, entry.ID)
+	}
+	if want := []string{recent, used, oldest}; !slices.Equal(evictedIDs, want) {
+		t.Errorf("Expected eviction order %v, got %v", want, evictedIDs)
+	}
+	models, err := s.List()
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != locked {
+		t.Errorf("Expected only the kept model to remain, got %v", models)
+	}
+}
+
+func TestLastUsedTimes(t *testing.T) {
+	tempDir := t.TempDir()
+
+	storePath := filepath.Join(tempDir, "last-used-store")
+	s, err := store.New(store.Options{
+		RootPath: storePath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	mdl := testutil.BuildModelFromPath(t, filepath.Join("testdata", "dummy.gguf"))
+	if err := s.Write(mdl, []string{"last-used:latest"}, nil); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	id, err := mdl.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	times, err := s.LastUsedTimes()
+	if err != nil {
+		t.Fatalf("LastUsedTimes failed: %v", err)
+	}
+	written, ok := times[id]
+	if !ok {
+		t.Fatal("Expected writing the model to record an access time")
+	}
+
+	if err := s.Touch(id); err != nil {
+		t.Fatalf("Touch failed: %v", err)
+	}
+	times, err = s.LastUsedTimes()
+	if err != nil {
+		t.Fatalf("LastUsedTimes failed: %v", err)
+	}
+	used := times[id]
+	if !used.After(written) {
+		t.Errorf("Expected access time to advance past %v, got %v", written, used)
+	}
+
+	// Access times are persisted and survive reopening the store.
+	reopened, err := store.New(store.Options{
+		RootPath: storePath,
+	})
+	if err != nil {
+		t.Fatalf("Failed to reopen store: %v", err)
+	}
+	times, err = reopened.LastUsedTimes()
+	if err != nil {
+		t.Fatalf("LastUsedTimes failed: %v", err)
+	}
+	if !times[id].Equal(used) {
+		t.Errorf("Expected access time %v after reopening, got %v", used, times[id])
+	}
+
+	// Deleting the model drops its access time.
+	if _, _, err := reopened.Delete(id); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	times, err = reopened.LastUsedTimes()
+	if err != nil {
+		t.Fatalf("LastUsedTimes failed: %v", err)
+	}
+	if _, ok := times[id]; ok {
+		t.Error("Expected deleting the model to drop its access time")
+	}
+}
+
 func TestMigrateTags(t *testing.T) {
 	tempDir := t.TempDir()
 
@@ -1527,3 +1813,175 @@ func TestWriteLightweight(t *testing.T) {
 		}
 	})
 }
+
+// TestRepair verifies that Repair removes unreferenced blobs and stale
+// incomplete downloads left behind by a crash, keeps downloads that can still
+// be resumed, and reports models whose blobs are missing.
+func TestRepair(t *testing.T) {
+	storePath := t.TempDir()
+	s, err := store.New(store.Options{RootPath: storePath})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+
+	model := newTestModel(t)
+	if err := s.Write(model, []string{"intact:latest"}, nil); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	broken := newTestModelWithMultimodalProjector(t)
+	if err := s.Write(broken, []string{"broken:latest"}, nil); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	brokenID, err := broken.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	blobPath := func(digest string) string {
+		return filepath.Join(storePath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+	}
+	digestOf := func(content string) string {
+		sum := sha256.Sum256([]byte(content))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	writeBlobFile := func(path string, age time.Duration) {
+		t.Helper()
+		if err := os.WriteFile(path, []byte("partial"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+		mtime := time.Now().Add(-age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatalf("Failed to set modification time of %s: %v", path, err)
+		}
+	}
+
+	// Remove the multimodal projector of the broken model.
+	layers, err := broken.Layers()
+	if err != nil {
+		t.Fatalf("Failed to get layers: %v", err)
+	}
+	var mmprojDigest, ggufDigest string
+	for _, layer := range layers {
+		mt, err := layer.MediaType()
+		if err != nil {
+			t.Fatalf("Failed to get media type: %v", err)
+		}
+		digest, err := layer.Digest()
+		if err != nil {
+			t.Fatalf("Failed to get digest: %v", err)
+		}
+		switch mt {
+		case types.MediaTypeMultimodalProjector:
+			mmprojDigest = digest.String()
+		case types.MediaTypeGGUF:
+			ggufDigest = digest.String()
+		}
+	}
+	if err := os.Remove(blobPath(mmprojDigest)); err != nil {
+		t.Fatalf("Failed to remove blob: %v", err)
+	}
+
+	orphan := digestOf("orphan")
+	writeBlobFile(blobPath(orphan), 0)
+	stale := digestOf("stale")
+	writeBlobFile(blobPath(stale)+".incomplete", 48*time.Hour)
+	resumable := digestOf("resumable")
+	writeBlobFile(blobPath(resumable)+".incomplete", 0)
+	writeBlobFile(blobPath(ggufDigest)+".incomplete", 0)
+
+	report, err := s.Repair(24 * time.Hour)
+	if err != nil {
+		t.Fatalf("Repair failed: %v", err)
+	}
+
+	if !slices.Equal(report.RemovedBlobs, []string{orphan}) {
+		t.Errorf("Expected removed blobs %v, got %v", []string{orphan}, report.RemovedBlobs)
+	}
+	slices.Sort(report.RemovedIncomplete)
+	wantIncomplete := []string{ggufDigest, stale}
+	slices.Sort(wantIncomplete)
+	if !slices.Equal(report.RemovedIncomplete, wantIncomplete) {
+		t.Errorf("Expected removed incomplete downloads %v, got %v", wantIncomplete, report.RemovedIncomplete)
+	}
+	if report.ReclaimedBytes != 3*int64(len("partial")) {
+		t.Errorf("Expected %d bytes reclaimed, got %d", 3*len("partial"), report.ReclaimedBytes)
+	}
+	wantMissing := map[string][]string{brokenID: {mmprojDigest}}
+	if len(report.MissingBlobs) != 1 || !slices.Equal(report.MissingBlobs[brokenID], wantMissing[brokenID]) {
+		t.Errorf("Expected missing blobs %v, got %v", wantMissing, report.MissingBlobs)
+	}
+
+	for _, path := range []string{blobPath(orphan), blobPath(stale) + ".incomplete", blobPath(ggufDigest) + ".incomplete"} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected %s to be removed, got %v", path, err)
+		}
+	}
+	for _, path := range []string{blobPath(resumable) + ".incomplete", blobPath(ggufDigest)} {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("Expected %s to be kept, got %v", path, err)
+		}
+	}
+	if _, err := s.Read("intact:latest"); err != nil {
+		t.Errorf("Expected intact model to be readable: %v", err)
+	}
+}
+
+func TestFileModes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not enforced on Windows")
+	}
+
+	tests := []struct {
+		name     string
+		opts     store.Options
+		fileMode os.FileMode
+		dirMode  os.FileMode
+	}{
+		{name: "defaults", fileMode: store.DefaultFileMode, dirMode: store.DefaultDirMode},
+		{name: "configured", opts: store.Options{FileMode: 0o640, DirMode: 0o750}, fileMode: 0o640, dirMode: 0o750},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.RootPath = filepath.Join(t.TempDir(), "store")
+			s, err := store.New(tt.opts)
+			if err != nil {
+				t.Fatalf("Failed to create store: %v", err)
+			}
+			if err := s.Write(newTestModel(t), []string{"mode-model:latest"}, nil); err != nil {
+				t.Fatalf("Write failed: %v", err)
+			}
+
+			var blobs int
+			err = filepath.WalkDir(s.RootPath(), func(path string, d os.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				info, err := d.Info()
+				if err != nil {
+					return err
+				}
+				mode := info.Mode().Perm()
+				if d.IsDir() {
+					// The umask may restrict directory modes further.
+					if mode&^tt.dirMode != 0 || mode&0o700 != 0o700 {
+						t.Errorf("Expected directory %s to have mode %v, got %v", path, tt.dirMode, mode)
+					}
+					return nil
+				}
+				if strings.Contains(filepath.ToSlash(path), "/blobs/") {
+					blobs++
+				}
+				if mode != tt.fileMode {
+					t.Errorf("Expected file %s to have mode %v, got %v", path, tt.fileMode, mode)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Failed to walk store: %v", err)
+			}
+			if blobs == 0 {
+				t.Error("Expected blobs to be written to the store")
+			}
+		})
+	}
+}
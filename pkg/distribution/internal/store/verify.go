@@ -0,0 +1,81 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"golang.org/x/sync/errgroup"
+)
+
+// VerificationReport describes the result of VerifyModel.
+type VerificationReport struct {
+	// ID is the ID of the verified model.
+	ID string
+	// Verified lists the digests of the blobs whose content matches.
+	Verified []string
+	// Corrupt lists the blobs whose content does not match their digest.
+	Corrupt []*oci.DigestMismatchError
+	// Missing lists the digests of the blobs missing from the store.
+	Missing []string
+}
+
+// OK reports whether every blob of the model was verified.
+func (r VerificationReport) OK() bool {
+	return len(r.Corrupt) == 0 && len(r.Missing) == 0
+}
+
+// VerifyModel hashes the blobs of the model ref refers to and checks them
+// against their digests. Blobs are hashed concurrently, at most
+// Options.VerifyConcurrency at once, and reported in the order the model lists
+// them. Corrupt blobs are reported but left in place. An error is only
+// returned if the model can't be found or a blob can't be read.
+func (s *LocalStore) VerifyModel(ref string) (VerificationReport, error) {
+	idx, err := s.readIndex()
+	if err != nil {
+		return VerificationReport{}, fmt.Errorf("reading models file: %w", err)
+	}
+	model, _, ok := idx.Find(ref)
+	if !ok {
+		return VerificationReport{}, ErrModelNotFound
+	}
+
+	// Each worker writes only to its blob's slot, so the results need no
+	// locking and keep the model's order.
+	computed := make([]oci.Hash, len(model.Files))
+	missing := make([]bool, len(model.Files))
+	var g errgroup.Group
+	g.SetLimit(s.verifyConcurrency)
+	for i, file := range model.Files {
+		g.Go(func() error {
+			hash, err := oci.NewHash(file)
+			if err != nil {
+				return fmt.Errorf("parse blob hash %q: %w", file, err)
+			}
+			computed[i], err = s.hashBlob(hash)
+			if errors.Is(err, os.ErrNotExist) {
+				missing[i] = true
+				return nil
+			}
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return VerificationReport{}, err
+	}
+
+	report := VerificationReport{ID: model.ID}
+	for i, file := range model.Files {
+		switch {
+		case missing[i]:
+			report.Missing = append(report.Missing, file)
+		case computed[i].String() == file:
+			report.Verified = append(report.Verified, file)
+		default:
+			expected, _ := oci.NewHash(file)
+			report.Corrupt = append(report.Corrupt, &oci.DigestMismatchError{Expected: expected, Computed: computed[i]})
+		}
+	}
+	return report, nil
+}
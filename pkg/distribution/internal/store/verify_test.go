@@ -0,0 +1,130 @@
+package store_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/internal/store"
+	"github.com/docker/model-runner/pkg/distribution/internal/testutil"
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/types"
+)
+
+// newMultiLayerModel returns a model with the given number of distinct
+// layers of size bytes each.
+func newMultiLayerModel(layers, size int) types.ModelArtifact {
+	built := make([]oci.Layer, layers)
+	for i := range built {
+		built[i] = testutil.NewStaticLayer(bytes.Repeat([]byte{byte('a' + i)}, size), types.MediaTypeGGUF)
+	}
+	return testutil.NewArtifact([]byte(`{"config":{"format":"gguf"}}`), types.MediaTypeModelConfigV01, built...)
+}
+
+func TestVerifyModel(t *testing.T) {
+	storePath := t.TempDir()
+	s, err := store.New(store.Options{RootPath: storePath, VerifyConcurrency: 2})
+	if err != nil {
+		t.Fatalf("Failed to create store: %v", err)
+	}
+	model := newMultiLayerModel(5, 1024)
+	if err := s.Write(model, []string{"model:latest"}, nil); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	id, err := model.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+	models, err := s.List()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	files := models[0].Files
+	if len(files) != 6 {
+		t.Fatalf("Expected 6 files (5 layers and config), got %d", len(files))
+	}
+	blobPath := func(digest string) string {
+		return filepath.Join(storePath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+	}
+
+	report, err := s.VerifyModel("model:latest")
+	if err != nil {
+		t.Fatalf("VerifyModel failed: %v", err)
+	}
+	if report.ID != id || !report.OK() || !slices.Equal(report.Verified, files) {
+		t.Errorf("Expected every blob of %s to be verified in order, got %+v", id, report)
+	}
+
+	// Corrupt one layer and remove another.
+	corrupt, missing := files[1], files[3]
+	if err := os.WriteFile(blobPath(corrupt), []byte("corrupt"), 0o644); err != nil {
+		t.Fatalf("Failed to corrupt blob: %v", err)
+	}
+	if err := os.Remove(blobPath(missing)); err != nil {
+		t.Fatalf("Failed to remove blob: %v", err)
+	}
+
+	sum := sha256.Sum256([]byte("corrupt"))
+	corruptDigest := "sha256:" + hex.EncodeToString(sum[:])
+
+	report, err = s.VerifyModel(id)
+	if err != nil {
+		t.Fatalf("VerifyModel failed: %v", err)
+	}
+	if report.OK() {
+		t.Error("Expected verification to fail")
+	}
+	wantVerified := []string{files[0], files[2], files[4], files[5]}
+	if !slices.Equal(report.Verified, wantVerified) {
+		t.Errorf("Expected verified blobs %v, got %v", wantVerified, report.Verified)
+	}
+	if len(report.Corrupt) != 1 || report.Corrupt[0].Expected.String() != corrupt || report.Corrupt[0].Computed.String() != corruptDigest {
+		t.Errorf("Expected %s to be reported corrupt, got %v", corrupt, report.Corrupt)
+	}
+	if !slices.Equal(report.Missing, []string{missing}) {
+		t.Errorf("Expected missing blobs %v, got %v", []string{missing}, report.Missing)
+	}
+	if _, err := os.Stat(blobPath(corrupt)); err != nil {
+		t.Errorf("Expected the corrupt blob to be left in place, got %v", err)
+	}
+
+	if _, err := s.VerifyModel("unknown:latest"); !errors.Is(err, store.ErrModelNotFound) {
+		t.Errorf("Expected ErrModelNotFound, got %v", err)
+	}
+}
+
+func BenchmarkVerifyModel(b *testing.B) {
+	s, err := store.New(store.Options{RootPath: b.TempDir()})
+	if err != nil {
+		b.Fatalf("Failed to create store: %v", err)
+	}
+	if err := s.Write(newMultiLayerModel(8, 8<<20), []string{"model:latest"}, nil); err != nil {
+		b.Fatalf("Failed to write model: %v", err)
+	}
+
+	for _, concurrency := range []int{1, 0} {
+		name := fmt.Sprintf("concurrency=%d", concurrency)
+		if concurrency == 0 {
+			name = "concurrency=default"
+		}
+		b.Run(name, func(b *testing.B) {
+			s, err := store.New(store.Options{RootPath: s.RootPath(), VerifyConcurrency: concurrency})
+			if err != nil {
+				b.Fatalf("Failed to open store: %v", err)
+			}
+			b.SetBytes(8 * 8 << 20)
+			for b.Loop() {
+				if _, err := s.VerifyModel("model:latest"); err != nil {
+					b.Fatalf("VerifyModel failed: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -84,26 +84,33 @@ type Keychain interface {
 // defaultKeychain implements Keychain using the Docker config file.
 type defaultKeychain struct{}
 
-// DefaultKeychain is the default keychain that reads from ~/.docker/config.json.
+// DefaultKeychain is the default keychain that reads credentials from the
+// environment and then from ~/.docker/config.json, including its credential
+// helpers.
 var DefaultKeychain Keychain = &defaultKeychain{}
 
+// envKeychain implements Keychain using environment variables only.
+type envKeychain struct{}
+
+// EnvKeychain is a keychain that only reads credentials from the environment.
+var EnvKeychain Keychain = &envKeychain{}
+
+// Resolve returns credentials from the environment, or anonymous access if
+// none are set.
+func (k *envKeychain) Resolve(Resource) (Authenticator, error) {
+	if auth := getAuthFromEnv(); auth != nil {
+		return auth, nil
+	}
+	return &Anonymous{}, nil
+}
+
 // Resolve returns credentials for the given resource from the Docker config file.
 func (k *defaultKeychain) Resolve(r Resource) (Authenticator, error) {
 	registry := r.RegistryStr()
 
 	// Try environment variables first
-	for _, envPair := range []struct{ user, pass string }{
-		{"DOCKER_USERNAME", "DOCKER_PASSWORD"},
-		{"DOCKER_HUB_USER", "DOCKER_HUB_PASSWORD"},
-	} {
-		if username := os.Getenv(envPair.user); username != "" {
-			if password := os.Getenv(envPair.pass); password != "" {
-				return &Basic{
-					Username: username,
-					Password: password,
-				}, nil
-			}
-		}
+	if auth := getAuthFromEnv(); auth != nil {
+		return auth, nil
 	}
 
 	// Read from Docker config file
@@ -121,6 +128,25 @@ func (k *defaultKeychain) Resolve(r Resource) (Authenticator, error) {
 	return &Anonymous{}, nil
 }
 
+// getAuthFromEnv returns the credentials set in the environment, or nil if
+// there are none.
+func getAuthFromEnv() Authenticator {
+	for _, envPair := range []struct{ user, pass string }{
+		{"DOCKER_USERNAME", "DOCKER_PASSWORD"},
+		{"DOCKER_HUB_USER", "DOCKER_HUB_PASSWORD"},
+	} {
+		if username := os.Getenv(envPair.user); username != "" {
+			if password := os.Getenv(envPair.pass); password != "" {
+				return &Basic{
+					Username: username,
+					Password: password,
+				}
+			}
+		}
+	}
+	return nil
+}
+
 // dockerConfig represents the structure of ~/.docker/config.json
 type dockerConfig struct {
 	Auths       map[string]AuthConfig `json:"auths"`
@@ -215,6 +241,10 @@ func getServerAddressForRegistry(registry string) string {
 	return normalized
 }
 
+// identityTokenUsername is the username credential helpers report for
+// identity tokens.
+const identityTokenUsername = "<token>"
+
 // getCredentialsFromHelper retrieves credentials using a Docker credential helper.
 // It uses the docker-credential-helpers library to interact with the credential helper.
 func getCredentialsFromHelper(helper, serverAddress string) (Authenticator, error) {
@@ -229,6 +259,11 @@ func getCredentialsFromHelper(helper, serverAddress string) (Authenticator, erro
 		return nil, nil
 	}
 
+	// Helpers store identity tokens, such as short-lived tokens issued by
+	// an OAuth login, under a placeholder username.
+	if creds.Username == identityTokenUsername && creds.Secret != "" {
+		return &Bearer{Token: creds.Secret}, nil
+	}
 	if creds.Username != "" && creds.Secret != "" {
 		return &Basic{Username: creds.Username, Password: creds.Secret}, nil
 	}
@@ -38,6 +38,19 @@ func NewHash(s string) (Hash, error) {
 	return h, nil
 }
 
+// DigestMismatchError is returned for content whose digest differs from the
+// digest it was fetched or stored under.
+type DigestMismatchError struct {
+	// Expected is the digest the content should have.
+	Expected Hash
+	// Computed is the digest of the content that was received.
+	Computed Hash
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("digest mismatch: expected %s, computed %s", e.Expected, e.Computed)
+}
+
 // MarshalJSON implements json.Marshaler
 func (h Hash) MarshalJSON() ([]byte, error) { return json.Marshal(h.String()) }
 
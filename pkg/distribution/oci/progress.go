@@ -10,6 +10,8 @@ type Update struct {
 	Complete int64
 	Total    int64
 	Error    error
+	// BytesPerSec is the moving average throughput, or zero if unknown.
+	BytesPerSec float64
 }
 
 // MessageType represents the type of progress message
@@ -38,9 +40,11 @@ const (
 
 // ProgressLayer represents layer information in a progress message
 type ProgressLayer struct {
-	ID      string `json:"id,omitempty"` // Layer ID
-	Size    uint64 `json:"size"`         // Layer size
-	Current uint64 `json:"current"`      // Current bytes transferred
+	ID          string  `json:"id,omitempty"`            // Layer ID
+	Size        uint64  `json:"size"`                    // Layer size
+	Current     uint64  `json:"current"`                 // Current bytes transferred
+	BytesPerSec float64 `json:"bytes_per_sec,omitempty"` // Moving average throughput, if known
+	ETASeconds  float64 `json:"eta_seconds,omitempty"`   // Estimated seconds until the layer completes, if known
 }
 
 // ProgressMessage represents a structured message for progress reporting
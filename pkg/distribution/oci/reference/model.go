@@ -0,0 +1,209 @@
+package reference
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+const (
+	// huggingFaceRegistry is the canonical Hugging Face registry and
+	// huggingFaceShortRegistry its short alias.
+	huggingFaceRegistry      = "huggingface.co"
+	huggingFaceShortRegistry = "hf.co"
+
+	digestPrefix = "sha256:"
+
+	// huggingFaceDefaultRevision is the Hugging Face revision references
+	// without "@revision" resolve to.
+	huggingFaceDefaultRevision = "main"
+	// huggingFaceRevisionPrefix starts the tag segment a Hugging Face revision
+	// is stored under. Tags holding it are reserved; see HasRevisionTag.
+	huggingFaceRevisionPrefix = "rev-"
+)
+
+// invalidTagChars matches the characters that cannot appear in a tag.
+var invalidTagChars = regexp.MustCompile(`[^a-zA-Z0-9_.-]`)
+
+// dockerHubRegistries are the equivalent names of Docker Hub.
+var dockerHubRegistries = []string{DefaultRegistry, "docker.io"}
+
+// Model is a model reference split into its components. Unlike Reference, it
+// holds references exactly as users and the model store write them, such as
+// "gemma3", "ai/gemma3:v1" or "hf.co/org/model@sha256:...", without applying
+// any defaults. Components that are not present are empty.
+type Model struct {
+	// Registry is the registry host, including any port.
+	Registry string
+	// Org is the path between the registry and the name. It may contain
+	// slashes.
+	Org string
+	// Name is the last path component.
+	Name string
+	// Tag is the tag, without the leading colon.
+	Tag string
+	// Digest is the digest, without the leading "@".
+	Digest string
+}
+
+// Parse splits ref into its components. The first path component is a
+// registry if it contains a dot or a port or is localhost, a colon starts a
+// tag only after the last slash, and an "@" starts a digest. A bare
+// "sha256:" digest, as accepted in place of a model ID, only sets Digest.
+//
+// Parse does not validate ref: anything is split as written, so that the
+// result can be normalized or displayed like the reference it came from.
+func Parse(ref string) Model {
+	ref = strings.TrimSpace(ref)
+	var m Model
+	if isDigest(ref) {
+		m.Digest = ref
+		return m
+	}
+	if name, digest, ok := strings.Cut(ref, "@"); ok {
+		ref, m.Digest = name, digest
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref, m.Tag = ref[:i], ref[i+1:]
+	}
+	if first, rest, ok := strings.Cut(ref, "/"); ok && isRegistryHost(first) {
+		m.Registry, ref = first, rest
+	}
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		m.Org, ref = ref[:i], ref[i+1:]
+	}
+	m.Name = ref
+	return m
+}
+
+// String returns the reference as written.
+func (m Model) String() string {
+	if m.Name == "" && m.Registry == "" && m.Org == "" {
+		return m.Digest
+	}
+	var sb strings.Builder
+	for _, part := range []string{m.Registry, m.Org} {
+		if part != "" {
+			sb.WriteString(part)
+			sb.WriteByte('/')
+		}
+	}
+	sb.WriteString(m.Name)
+	if m.Tag != "" {
+		sb.WriteString(":" + m.Tag)
+	}
+	if m.Digest != "" {
+		sb.WriteString("@" + m.Digest)
+	}
+	return sb.String()
+}
+
+// Normalize returns the canonical form of the reference used to look models
+// up in the store. Names without a registry or organization get the default
+// organization, references without a tag or digest get the default tag, hf.co
+// becomes huggingface.co, and everything but the tag and digest is lowercased.
+// A Hugging Face revision, such as "main" in "hf.co/org/model@main", is not a
+// digest and is folded into the tag instead; see huggingFaceRevisionTag.
+func (m Model) Normalize() string {
+	if m.Name == "" && m.Registry == "" && m.Org == "" && m.Tag == "" {
+		// Empty, or a bare digest.
+		return m.Digest
+	}
+	m.Registry = strings.ToLower(m.Registry)
+	m.Org = strings.ToLower(m.Org)
+	m.Name = strings.ToLower(m.Name)
+	if m.Registry == huggingFaceShortRegistry {
+		m.Registry = huggingFaceRegistry
+	}
+	if m.Registry == huggingFaceRegistry && m.Digest != "" && !isDigest(m.Digest) {
+		m.Tag, m.Digest = huggingFaceRevisionTag(m.Tag, m.Digest), ""
+	}
+	if m.Registry == "" && m.Org == "" {
+		m.Org = DefaultOrg
+	}
+	if m.Tag == "" && m.Digest == "" {
+		m.Tag = DefaultTag
+	}
+	return m.String()
+}
+
+// Strip returns the shortest form of the reference for display, without the
+// default registry, organization or tag. Docker Hub is the default registry
+// unless the DEFAULT_REGISTRY environment variable names another one, in
+// which case only that registry is stripped.
+func (m Model) Strip() string {
+	if m.Registry != "" && isDefaultRegistry(m.Registry) {
+		m.Registry = ""
+	}
+	if m.Registry == "" && m.Org == DefaultOrg {
+		m.Org = ""
+	}
+	if m.Tag == DefaultTag {
+		m.Tag = ""
+	}
+	return m.String()
+}
+
+// Match reports whether partial is the bare name of the reference, without
+// registry, organization or tag, as "smollm2" is for "ai/smollm2:latest".
+func (m Model) Match(partial string) bool {
+	return partial != "" && m.Name == partial
+}
+
+// HasRevisionTag reports whether the reference is a Hugging Face reference
+// whose tag has the form revisions are stored under, such as "rev-v1" or
+// "Q4_K_M-rev-v1". Such tags cannot be pulled, so that a quantization tag
+// never names the same model as a revision.
+func (m Model) HasRevisionTag() bool {
+	registry := strings.ToLower(m.Registry)
+	if registry != huggingFaceRegistry && registry != huggingFaceShortRegistry {
+		return false
+	}
+	return strings.HasPrefix(m.Tag, huggingFaceRevisionPrefix) || strings.Contains(m.Tag, "-"+huggingFaceRevisionPrefix)
+}
+
+// huggingFaceRevisionTag returns the tag under which a Hugging Face model
+// pulled at revision with the given tag is stored: "rev-" and the revision for
+// the default tag, the tag, "-rev-" and the revision otherwise, and the tag
+// alone for the default revision. Characters not allowed in tags, such as the
+// slashes of "refs/pr/1", are replaced by "-".
+func huggingFaceRevisionTag(tag, revision string) string {
+	if revision == huggingFaceDefaultRevision {
+		return tag
+	}
+	revision = huggingFaceRevisionPrefix + invalidTagChars.ReplaceAllString(revision, "-")
+	if tag == "" || tag == DefaultTag {
+		return revision
+	}
+	return tag + "-" + revision
+}
+
+// isRegistryHost reports whether the first path component of a reference
+// names a registry rather than an organization.
+func isRegistryHost(s string) bool {
+	return strings.ContainsAny(s, ".:") || s == "localhost"
+}
+
+// isDefaultRegistry reports whether registry is the default registry.
+func isDefaultRegistry(registry string) bool {
+	if custom := strings.TrimSuffix(os.Getenv("DEFAULT_REGISTRY"), "/"); custom != "" && !slices.Contains(dockerHubRegistries, custom) {
+		return registry == custom
+	}
+	return slices.Contains(dockerHubRegistries, registry)
+}
+
+// isDigest reports whether s is a sha256 digest, such as
+// "sha256:<64 hex characters>".
+func isDigest(s string) bool {
+	hash, ok := strings.CutPrefix(s, digestPrefix)
+	if !ok || len(hash) != 64 {
+		return false
+	}
+	for i := 0; i < len(hash); i++ {
+		if c := hash[i]; (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
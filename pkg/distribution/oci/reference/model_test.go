@@ -0,0 +1,227 @@
+package reference
+
+import "testing"
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected Model
+	}{
+		{name: "empty", input: "", expected: Model{}},
+		{name: "whitespace only", input: "   ", expected: Model{}},
+		{name: "name only", input: "gemma3", expected: Model{Name: "gemma3"}},
+		{name: "name with whitespace", input: "  gemma3  ", expected: Model{Name: "gemma3"}},
+		{name: "name and tag", input: "gemma3:v1", expected: Model{Name: "gemma3", Tag: "v1"}},
+		{name: "trailing colon", input: "model:", expected: Model{Name: "model"}},
+		{name: "org and name", input: "ai/gemma3", expected: Model{Org: "ai", Name: "gemma3"}},
+		{name: "org, name and tag", input: "myorg/model:v2", expected: Model{Org: "myorg", Name: "model", Tag: "v2"}},
+		{name: "nested org", input: "team/sub/model:v1", expected: Model{Org: "team/sub", Name: "model", Tag: "v1"}},
+		{name: "registry and name", input: "registry.example.com/model", expected: Model{Registry: "registry.example.com", Name: "model"}},
+		{
+			name:     "registry, org, name and tag",
+			input:    "registry.example.com/myorg/model:v1",
+			expected: Model{Registry: "registry.example.com", Org: "myorg", Name: "model", Tag: "v1"},
+		},
+		{
+			name:     "registry with port",
+			input:    "localhost:5000/ai/model:v1",
+			expected: Model{Registry: "localhost:5000", Org: "ai", Name: "model", Tag: "v1"},
+		},
+		{name: "registry port without tag", input: "localhost:5000/model", expected: Model{Registry: "localhost:5000", Name: "model"}},
+		{name: "localhost", input: "localhost/model", expected: Model{Registry: "localhost", Name: "model"}},
+		{name: "docker hub", input: "docker.io/ai/gemma3:latest", expected: Model{Registry: "docker.io", Org: "ai", Name: "gemma3", Tag: "latest"}},
+		{
+			name:     "huggingface short registry",
+			input:    "hf.co/bartowski/model:Q4_K_S",
+			expected: Model{Registry: "hf.co", Org: "bartowski", Name: "model", Tag: "Q4_K_S"},
+		},
+		{name: "digest", input: "ai/gemma3@" + testDigest, expected: Model{Org: "ai", Name: "gemma3", Digest: testDigest}},
+		{name: "tag and digest", input: "ai/gemma3:v1@" + testDigest, expected: Model{Org: "ai", Name: "gemma3", Tag: "v1", Digest: testDigest}},
+		{name: "bare digest", input: testDigest, expected: Model{Digest: testDigest}},
+		{name: "invalid bare digest", input: "sha256:invalid", expected: Model{Name: "sha256", Tag: "invalid"}},
+		{name: "case is preserved", input: "MyOrg/MyModel:Q4_K_M", expected: Model{Org: "MyOrg", Name: "MyModel", Tag: "Q4_K_M"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input); got != tt.expected {
+				t.Errorf("Parse(%q) = %+v, want %+v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestModelString(t *testing.T) {
+	for _, input := range []string{
+		"",
+		"gemma3",
+		"ai/gemma3:latest",
+		"team/sub/model:v1",
+		"localhost:5000/ai/model:v1",
+		"hf.co/bartowski/model:Q4_K_S",
+		"ai/gemma3:v1@" + testDigest,
+		testDigest,
+	} {
+		if got := Parse(input).String(); got != input {
+			t.Errorf("Parse(%q).String() = %q", input, got)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "short name only", input: "gemma3", expected: "ai/gemma3:latest"},
+		{name: "short name with tag", input: "gemma3:v1", expected: "ai/gemma3:v1"},
+		{name: "org and name without tag", input: "myorg/model", expected: "myorg/model:latest"},
+		{name: "org and name with tag", input: "myorg/model:v2", expected: "myorg/model:v2"},
+		{name: "fully qualified reference", input: "ai/gemma3:latest", expected: "ai/gemma3:latest"},
+		{name: "registry without tag", input: "registry.example.com/model", expected: "registry.example.com/model:latest"},
+		{name: "registry with tag", input: "registry.example.com/model:v1", expected: "registry.example.com/model:v1"},
+		{name: "registry with org and tag", input: "registry.example.com/myorg/model:v1", expected: "registry.example.com/myorg/model:v1"},
+		{name: "registry with port", input: "localhost:5000/model", expected: "localhost:5000/model:latest"},
+		{name: "empty string", input: "", expected: ""},
+		{name: "whitespace only", input: "   ", expected: ""},
+		{name: "name with leading/trailing whitespace", input: "  gemma3  ", expected: "ai/gemma3:latest"},
+		{name: "name with trailing colon", input: "model:", expected: "ai/model:latest"},
+		{name: "org/name with trailing colon", input: "myorg/model:", expected: "myorg/model:latest"},
+		{name: "name that looks like hex but wrong length", input: "abc123", expected: "ai/abc123:latest"},
+		{name: "name with uppercase", input: "MyModel", expected: "ai/mymodel:latest"},
+		{name: "tag case is preserved", input: "MyOrg/Model:Q4_K_M", expected: "myorg/model:Q4_K_M"},
+		{name: "hf.co normalized to huggingface.co", input: "hf.co/org/model", expected: "huggingface.co/org/model:latest"},
+		{name: "hf.co with tag normalized to huggingface.co", input: "hf.co/org/model:Q4_K_M", expected: "huggingface.co/org/model:Q4_K_M"},
+		{name: "huggingface.co stays unchanged", input: "huggingface.co/org/model", expected: "huggingface.co/org/model:latest"},
+		{name: "huggingface revision becomes the tag", input: "hf.co/Org/Model@abc1234", expected: "huggingface.co/org/model:rev-abc1234"},
+		{name: "huggingface revision joins the tag", input: "hf.co/org/model:Q4_K_M@v1.0", expected: "huggingface.co/org/model:Q4_K_M-rev-v1.0"},
+		{name: "huggingface default revision", input: "hf.co/org/model@main", expected: "huggingface.co/org/model:latest"},
+		{name: "huggingface branch revision", input: "hf.co/org/model@refs/pr/1", expected: "huggingface.co/org/model:rev-refs-pr-1"},
+		{name: "huggingface nested path with revision", input: "hf.co/Org/Model/GGUF:Q8_0@abc1234", expected: "huggingface.co/org/model/gguf:Q8_0-rev-abc1234"},
+		{name: "huggingface revision differs from the tag", input: "hf.co/org/model@Q4_K_M", expected: "huggingface.co/org/model:rev-Q4_K_M"},
+		{name: "huggingface digest is kept", input: "hf.co/org/model@" + testDigest, expected: "huggingface.co/org/model@" + testDigest},
+		{name: "digest without tag", input: "gemma3@" + testDigest, expected: "ai/gemma3@" + testDigest},
+		{name: "bare digest", input: testDigest, expected: testDigest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.input).Normalize(); got != tt.expected {
+				t.Errorf("Parse(%q).Normalize() = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestHuggingFaceRevisionDistinctFromTag(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"hf.co/org/model@Q4_K_M", "hf.co/org/model:Q4_K_M"},
+		{"hf.co/org/model:Q4@v1", "hf.co/org/model:Q4-v1"},
+	} {
+		revision, tag := Parse(pair[0]).Normalize(), Parse(pair[1]).Normalize()
+		if revision == tag {
+			t.Errorf("Expected %q and %q to normalize to different references, both got %q", pair[0], pair[1], revision)
+		}
+		if Parse(pair[1]).HasRevisionTag() {
+			t.Errorf("Expected %q not to have a revision tag", pair[1])
+		}
+		if !Parse(revision).HasRevisionTag() {
+			t.Errorf("Expected %q to have a revision tag", revision)
+		}
+	}
+	if Parse("registry.example.com/org/model:rev-1").HasRevisionTag() {
+		t.Error("Expected tags of other registries not to be reserved")
+	}
+}
+
+func TestStrip(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		defaultRegistry string
+		expected        string
+	}{
+		{name: "ai prefix and latest tag", input: "ai/gemma3:latest", expected: "gemma3"},
+		{name: "ai prefix with custom tag", input: "ai/gemma3:v1", expected: "gemma3:v1"},
+		{name: "custom org with latest tag", input: "myorg/gemma3:latest", expected: "myorg/gemma3"},
+		{name: "simple model name with latest", input: "gemma3:latest", expected: "gemma3"},
+		{name: "simple model name without tag", input: "gemma3", expected: "gemma3"},
+		{name: "ai prefix without tag", input: "ai/gemma3", expected: "gemma3"},
+		{name: "nested org starting with ai", input: "ai/sub/model:latest", expected: "ai/sub/model"},
+		{name: "huggingface model with latest", input: "hf.co/bartowski/model:latest", expected: "hf.co/bartowski/model"},
+		{name: "huggingface model with custom tag", input: "hf.co/bartowski/model:Q4_K_S", expected: "hf.co/bartowski/model:Q4_K_S"},
+		{name: "ai org on another registry", input: "registry.example.com/ai/model:latest", expected: "registry.example.com/ai/model"},
+		{name: "empty string", input: "", expected: ""},
+		{name: "docker.io registry with ai prefix and latest tag", input: "docker.io/ai/gemma3:latest", expected: "gemma3"},
+		{name: "index.docker.io registry with ai prefix and latest tag", input: "index.docker.io/ai/gemma3:latest", expected: "gemma3"},
+		{name: "docker.io registry with ai prefix and custom tag", input: "docker.io/ai/gemma3:v1", expected: "gemma3:v1"},
+		{name: "docker.io registry with custom org and latest tag", input: "docker.io/myorg/gemma3:latest", expected: "myorg/gemma3"},
+		{name: "index.docker.io registry with custom org and latest tag", input: "index.docker.io/myorg/gemma3:latest", expected: "myorg/gemma3"},
+		{name: "digest", input: "ai/gemma3@" + testDigest, expected: "gemma3@" + testDigest},
+		{name: "bare digest", input: testDigest, expected: testDigest},
+		{
+			name:            "custom default registry",
+			input:           "registry.example.com/ai/gemma3:latest",
+			defaultRegistry: "registry.example.com",
+			expected:        "gemma3",
+		},
+		{
+			name:            "custom default registry with trailing slash",
+			input:           "registry.example.com/myorg/gemma3:v1",
+			defaultRegistry: "registry.example.com/",
+			expected:        "myorg/gemma3:v1",
+		},
+		{
+			name:            "docker hub is kept with a custom default registry",
+			input:           "docker.io/ai/gemma3:latest",
+			defaultRegistry: "registry.example.com",
+			expected:        "docker.io/ai/gemma3",
+		},
+		{
+			name:            "docker.io as default registry",
+			input:           "index.docker.io/ai/gemma3:latest",
+			defaultRegistry: "docker.io",
+			expected:        "gemma3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("DEFAULT_REGISTRY", tt.defaultRegistry)
+			if got := Parse(tt.input).Strip(); got != tt.expected {
+				t.Errorf("Parse(%q).Strip() = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		ref      string
+		partial  string
+		expected bool
+	}{
+		{name: "bare name", ref: "ai/smollm2:latest", partial: "smollm2", expected: true},
+		{name: "bare name with custom tag", ref: "ai/smollm2:360M", partial: "smollm2", expected: true},
+		{name: "bare name on another registry", ref: "hf.co/bartowski/model:Q4_K_S", partial: "model", expected: true},
+		{name: "bare name behind registry port", ref: "localhost:5000/model:v1", partial: "model", expected: true},
+		{name: "prefix of the name", ref: "ai/smollm2:latest", partial: "smol", expected: false},
+		{name: "org is not a name", ref: "ai/smollm2:latest", partial: "ai", expected: false},
+		{name: "name with tag", ref: "ai/smollm2:latest", partial: "smollm2:latest", expected: false},
+		{name: "empty partial", ref: "ai/smollm2:latest", partial: "", expected: false},
+		{name: "bare digest", ref: testDigest, partial: "sha256", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.ref).Match(tt.partial); got != tt.expected {
+				t.Errorf("Parse(%q).Match(%q) = %v, want %v", tt.ref, tt.partial, got, tt.expected)
+			}
+		})
+	}
+}
@@ -1,9 +1,13 @@
 // Package reference provides image reference parsing using the distribution/reference library.
 // This replaces go-containerregistry's name package.
+//
+// It also provides Parse, which splits model references as the CLI and the
+// model store write them, for normalizing, displaying and matching model names.
 package reference
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/distribution/reference"
@@ -53,6 +57,8 @@ func (r Repository) RepositoryStr() string {
 type Registry struct {
 	registry string
 	insecure bool
+	// mirror is the registry tried before this one when pulling, if any.
+	mirror *Registry
 }
 
 // Name returns the registry name.
@@ -65,6 +71,11 @@ func (r Registry) RegistryStr() string {
 	return r.registry
 }
 
+// IsDockerHub reports whether r is Docker Hub, under any of its names.
+func (r Registry) IsDockerHub() bool {
+	return slices.Contains(dockerHubRegistries, r.registry)
+}
+
 // Scheme returns the URL scheme (http or https).
 func (r Registry) Scheme() string {
 	if r.insecure || isInsecureHost(r.registry) {
@@ -73,14 +84,38 @@ func (r Registry) Scheme() string {
 	return "https"
 }
 
+// Mirror returns the registry tried before r when pulling, if one is configured.
+func (r Registry) Mirror() (Registry, bool) {
+	if r.mirror == nil {
+		return Registry{}, false
+	}
+	return *r.mirror, true
+}
+
+// stripPort returns host without its port, if any.
+func stripPort(host string) string {
+	if idx := strings.LastIndex(host, ":"); idx != -1 {
+		return host[:idx]
+	}
+	return host
+}
+
+// isPlainHTTPHost reports whether host is listed in hosts. A listed host
+// without a port matches the host on any port.
+func isPlainHTTPHost(host string, hosts []string) bool {
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) || strings.EqualFold(h, stripPort(host)) {
+			return true
+		}
+	}
+	return false
+}
+
 // isInsecureHost returns true if the host should use HTTP by default.
 // This includes localhost and .local hostnames.
 func isInsecureHost(host string) bool {
 	// Remove port if present
-	hostWithoutPort := host
-	if idx := strings.LastIndex(host, ":"); idx != -1 {
-		hostWithoutPort = host[:idx]
-	}
+	hostWithoutPort := stripPort(host)
 
 	// Check for localhost
 	if hostWithoutPort == "localhost" {
@@ -184,6 +219,8 @@ type options struct {
 	defaultRegistry string
 	defaultOrg      string
 	insecure        bool
+	plainHTTPHosts  []string
+	mirror          string
 }
 
 // WithDefaultRegistry sets a custom default registry.
@@ -206,6 +243,24 @@ var Insecure Option = func(o *options) {
 	o.insecure = true
 }
 
+// WithPlainHTTPHosts forces HTTP connections to the given registry hosts,
+// regardless of their name. A host without a port matches it on any port.
+// Multiple uses accumulate.
+func WithPlainHTTPHosts(hosts ...string) Option {
+	return func(o *options) {
+		o.plainHTTPHosts = append(o.plainHTTPHosts, hosts...)
+	}
+}
+
+// WithMirror sets a registry that is tried before the referenced registry when
+// pulling. The mirror may be given as a host or as an http(s) URL; a host is
+// reached over HTTP under the same rules as any other registry.
+func WithMirror(mirror string) Option {
+	return func(o *options) {
+		o.mirror = mirror
+	}
+}
+
 // ParseReference parses a string into a Reference.
 func ParseReference(s string, opts ...Option) (Reference, error) {
 	o := &options{
@@ -258,7 +313,22 @@ func ParseReference(s string, opts ...Option) (Reference, error) {
 
 	registry := Registry{
 		registry: domain,
-		insecure: o.insecure,
+		insecure: o.insecure || isPlainHTTPHost(domain, o.plainHTTPHosts),
+	}
+	if o.mirror != "" {
+		mirror := strings.TrimSuffix(o.mirror, "/")
+		insecure := o.insecure
+		if rest, ok := strings.CutPrefix(mirror, "http://"); ok {
+			mirror, insecure = rest, true
+		} else {
+			mirror = strings.TrimPrefix(mirror, "https://")
+		}
+		if mirror != "" && !strings.EqualFold(mirror, domain) {
+			registry.mirror = &Registry{
+				registry: mirror,
+				insecure: insecure || isPlainHTTPHost(mirror, o.plainHTTPHosts),
+			}
+		}
 	}
 
 	// Check if it's a tagged reference
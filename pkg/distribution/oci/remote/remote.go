@@ -10,6 +10,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -29,6 +30,11 @@ import (
 var (
 	// DefaultTransport is the default HTTP transport used for registry operations.
 	DefaultTransport = http.DefaultTransport
+
+	// ErrIndex is returned by Image when the reference resolves to an image
+	// index, such as a multi-platform manifest list, rather than an image.
+	// Use Index to fetch it.
+	ErrIndex = errors.New("reference is an image index")
 )
 
 const (
@@ -197,24 +203,55 @@ func (t *rangeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
 		return resp, err
 	}
 
-	// If we requested a Range, record success only if the server accepted the range request
-	// Servers should return 206 (Partial Content) for successful range requests,
-	// but some may return 200 with the partial content, so we record success for both
-	if requestedOffset > 0 {
-		if resp.StatusCode == http.StatusPartialContent || resp.StatusCode == http.StatusOK {
-			// Record in RangeSuccess tracker so WriteBlob can check it
-			if rs := GetRangeSuccess(req.Context()); rs != nil {
-				rs.Add(digest, requestedOffset)
-			}
+	// If we requested a Range, record success only if the server honored it.
+	// Otherwise (e.g., the server ignored the header and sent the whole blob, or
+	// answered 416 Range Not Satisfiable), don't record in RangeSuccess, which
+	// will cause WriteBlob to discard the incomplete file and start fresh.
+	if requestedOffset > 0 && rangeHonored(resp, requestedOffset) {
+		// Record in RangeSuccess tracker so WriteBlob can check it
+		if rs := GetRangeSuccess(req.Context()); rs != nil {
+			rs.Add(digest, requestedOffset)
 		}
-		// If range request was not successful (e.g., 416 Range Not Satisfiable),
-		// don't record in RangeSuccess, which will cause WriteBlob to start fresh
-		// (no explicit action needed in the else case)
 	}
 
 	return resp, nil
 }
 
+// rangeHonored reports whether resp carries the blob starting at offset, as
+// requested with a Range header. Servers without range support ignore the
+// header and answer 200 with the whole blob, which must not be appended to a
+// partial download. A 200 is therefore only trusted when the server advertises
+// Accept-Ranges: bytes and sends a Content-Range starting at offset.
+func rangeHonored(resp *http.Response, offset int64) bool {
+	start, hasRange := contentRangeStart(resp.Header.Get("Content-Range"))
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		return !hasRange || start == offset
+	case http.StatusOK:
+		return hasRange && start == offset && strings.EqualFold(resp.Header.Get("Accept-Ranges"), "bytes")
+	default:
+		return false
+	}
+}
+
+// contentRangeStart returns the first byte position of a Content-Range header
+// value such as "bytes 100-199/200".
+func contentRangeStart(value string) (int64, bool) {
+	unit, rng, ok := strings.Cut(strings.TrimSpace(value), " ")
+	if !ok || !strings.EqualFold(unit, "bytes") {
+		return 0, false
+	}
+	first, _, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return start, true
+}
+
 // extractDigestAndOffset extracts the blob digest from the request URL and returns
 // the corresponding resume offset if one exists.
 func (t *rangeTransport) extractDigestAndOffset(req *http.Request, offsets map[string]int64) (string, int64) {
@@ -264,8 +301,9 @@ func makeOptions(opts ...Option) *options {
 	return o
 }
 
-// credentialsFunc returns a docker credentials function.
-func credentialsFunc(o *options, ref reference.Reference) func(string) (string, string, error) {
+// credentialsFunc returns a docker credentials function resolving credentials
+// for resource.
+func credentialsFunc(o *options, resource authn.Resource) func(string) (string, string, error) {
 	return func(host string) (string, string, error) {
 		var auth authn.Authenticator
 
@@ -273,7 +311,7 @@ func credentialsFunc(o *options, ref reference.Reference) func(string) (string,
 			auth = o.auth
 		} else if o.keychain != nil {
 			var err error
-			auth, err = o.keychain.Resolve(authn.NewResource(ref))
+			auth, err = o.keychain.Resolve(resource)
 			if err != nil {
 				return "", "", err
 			}
@@ -318,8 +356,20 @@ type resolverComponents struct {
 
 // createResolver creates a docker resolver with the given options.
 func createResolver(o *options, ref reference.Reference) resolverComponents {
+	return newResolver(o, ref, false)
+}
+
+// createPullResolver creates a docker resolver that fetches content from the
+// registry's mirror, if one is configured, before the registry itself. Tags
+// are only resolved by the registry, so the resolved digest never depends on
+// the mirror.
+func createPullResolver(o *options, ref reference.Reference) resolverComponents {
+	return newResolver(o, ref, true)
+}
+
+func newResolver(o *options, ref reference.Reference, useMirror bool) resolverComponents {
 	authorizer := docker.NewDockerAuthorizer(
-		docker.WithAuthCreds(credentialsFunc(o, ref)))
+		docker.WithAuthCreds(credentialsFunc(o, authn.NewResource(ref))))
 
 	// Wrap transport with Range header support for resumable downloads
 	// and User-Agent header for registry compatibility (required by HuggingFace)
@@ -329,30 +379,30 @@ func createResolver(o *options, ref reference.Reference) resolverComponents {
 	// Check if we should use plain HTTP (either explicitly configured or for insecure hosts)
 	usePlainHTTP := o.plainHTTP || ref.Context().Registry.Scheme() == "http"
 
-	var resolver remotes.Resolver
+	var hosts docker.RegistryHosts
 	if usePlainHTTP {
 		// For plain HTTP, use a custom hosts function
-		resolver = docker.NewResolver(docker.ResolverOptions{
-			Hosts: func(host string) ([]docker.RegistryHost, error) {
-				return []docker.RegistryHost{
-					{
-						Host:         host,
-						Scheme:       "http",
-						Path:         "/v2",
-						Capabilities: docker.HostCapabilityPush | docker.HostCapabilityPull | docker.HostCapabilityResolve,
-						Authorizer:   authorizer,
-						Client:       client,
-					},
-				}, nil
-			},
-		})
+		hosts = func(host string) ([]docker.RegistryHost, error) {
+			return []docker.RegistryHost{
+				{
+					Host:         host,
+					Scheme:       "http",
+					Path:         "/v2",
+					Capabilities: docker.HostCapabilityPush | docker.HostCapabilityPull | docker.HostCapabilityResolve,
+					Authorizer:   authorizer,
+					Client:       client,
+				},
+			}, nil
+		}
 	} else {
-		resolver = docker.NewResolver(docker.ResolverOptions{
-			Hosts: docker.ConfigureDefaultRegistries(
-				docker.WithAuthorizer(authorizer),
-				docker.WithClient(client)),
-		})
+		hosts = docker.ConfigureDefaultRegistries(
+			docker.WithAuthorizer(authorizer),
+			docker.WithClient(client))
+	}
+	if mirror, ok := ref.Context().Registry.Mirror(); ok && useMirror {
+		hosts = withMirror(hosts, mirrorHost(o, mirror, transport))
 	}
+	resolver := docker.NewResolver(docker.ResolverOptions{Hosts: hosts})
 
 	return resolverComponents{
 		resolver:   resolver,
@@ -362,6 +412,53 @@ func createResolver(o *options, ref reference.Reference) resolverComponents {
 	}
 }
 
+// mirrorHost returns the registry host for a pull mirror. The mirror only has
+// the pull capability, so it serves content by digest but never resolves tags.
+// It is authorized with its own credentials rather than the registry's.
+func mirrorHost(o *options, mirror reference.Registry, transport http.RoundTripper) docker.RegistryHost {
+	authorizer := docker.NewDockerAuthorizer(
+		docker.WithAuthCreds(func(string) (string, string, error) {
+			if o.keychain == nil {
+				return "", "", nil
+			}
+			auth, err := o.keychain.Resolve(mirror)
+			if err != nil {
+				return "", "", err
+			}
+			cfg, err := auth.Authorization()
+			if err != nil {
+				return "", "", err
+			}
+			if cfg.RegistryToken != "" {
+				return "", cfg.RegistryToken, nil
+			}
+			return cfg.Username, cfg.Password, nil
+		}))
+	scheme := mirror.Scheme()
+	if o.plainHTTP {
+		scheme = "http"
+	}
+	return docker.RegistryHost{
+		Host:         mirror.RegistryStr(),
+		Scheme:       scheme,
+		Path:         "/v2",
+		Capabilities: docker.HostCapabilityPull,
+		Authorizer:   authorizer,
+		Client:       &http.Client{Transport: transport},
+	}
+}
+
+// withMirror returns hosts with mirror tried first.
+func withMirror(hosts docker.RegistryHosts, mirror docker.RegistryHost) docker.RegistryHosts {
+	return func(host string) ([]docker.RegistryHost, error) {
+		registryHosts, err := hosts(host)
+		if err != nil {
+			return nil, err
+		}
+		return append([]docker.RegistryHost{mirror}, registryHosts...), nil
+	}
+}
+
 // createResolverWithPushScope creates a docker resolver pre-authorized with push scope.
 func createResolverWithPushScope(o *options, ref reference.Reference) (resolverComponents, error) {
 	var auth authn.Authenticator
@@ -446,7 +543,7 @@ func Image(ref reference.Reference, opts ...Option) (oci.Image, error) {
 	o := makeOptions(opts...)
 
 	// Create resolver
-	components := createResolver(o, ref)
+	components := createPullResolver(o, ref)
 
 	// Resolve the reference
 	name, desc, err := components.resolver.Resolve(o.ctx, ref.String())
@@ -454,6 +551,9 @@ func Image(ref reference.Reference, opts ...Option) (oci.Image, error) {
 		return nil, fmt.Errorf("resolving %s: %w", ref.String(), err)
 	}
 	_ = name // we use the original ref
+	if oci.MediaType(desc.MediaType).IsIndex() {
+		return nil, fmt.Errorf("resolving %s: %w", ref.String(), ErrIndex)
+	}
 
 	// Create a temporary content store
 	tmpDir, err := os.MkdirTemp("", "model-runner-remote")
@@ -476,6 +576,48 @@ func Image(ref reference.Reference, opts ...Option) (oci.Image, error) {
 	}, nil
 }
 
+// Index fetches a remote image index, such as a multi-platform manifest list,
+// along with its digest.
+func Index(ref reference.Reference, opts ...Option) (*oci.IndexManifest, oci.Hash, error) {
+	o := makeOptions(opts...)
+	components := createPullResolver(o, ref)
+
+	_, desc, err := components.resolver.Resolve(o.ctx, ref.String())
+	if err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("resolving %s: %w", ref.String(), err)
+	}
+	if !oci.MediaType(desc.MediaType).IsIndex() {
+		return nil, oci.Hash{}, fmt.Errorf("%s is not an image index: %s", ref.String(), desc.MediaType)
+	}
+
+	fetcher, err := components.resolver.Fetcher(o.ctx, ref.String())
+	if err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("getting fetcher: %w", err)
+	}
+	rc, err := fetcher.Fetch(o.ctx, desc)
+	if err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("fetching index: %w", err)
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("reading index: %w", err)
+	}
+	if got := godigest.FromBytes(data); got != desc.Digest {
+		return nil, oci.Hash{}, fmt.Errorf("index digest mismatch: expected %s, got %s", desc.Digest, got)
+	}
+
+	var index oci.IndexManifest
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("parsing index: %w", err)
+	}
+	if index.MediaType == "" {
+		index.MediaType = oci.MediaType(desc.MediaType)
+	}
+	return &index, oci.FromDigest(desc.Digest), nil
+}
+
 // fetchManifest fetches and caches the manifest.
 func (i *remoteImage) fetchManifest() error {
 	i.mu.Lock()
@@ -501,6 +643,9 @@ func (i *remoteImage) fetchManifest() error {
 	if err != nil {
 		return fmt.Errorf("reading manifest: %w", err)
 	}
+	if got := godigest.FromBytes(data); got != i.desc.Digest {
+		return fmt.Errorf("manifest digest mismatch: expected %s, got %s", i.desc.Digest, got)
+	}
 
 	i.rawManifest = data
 
@@ -588,7 +733,14 @@ func (i *remoteImage) RawConfigFile() ([]byte, error) {
 	}
 	defer rc.Close()
 
-	return io.ReadAll(rc)
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return nil, fmt.Errorf("reading config: %w", err)
+	}
+	if got := godigest.FromBytes(data); got != configDesc.Digest {
+		return nil, fmt.Errorf("config digest mismatch: expected %s, got %s", configDesc.Digest, got)
+	}
+	return data, nil
 }
 
 // Digest returns the manifest digest.
@@ -680,14 +832,52 @@ func (l *remoteLayer) Compressed() (io.ReadCloser, error) {
 		Size:      l.desc.Size,
 	}
 
-	return fetcher.Fetch(l.image.ctx, desc)
+	rc, err := fetcher.Fetch(l.image.ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	// A resumed download starts part way through the blob, so only complete
+	// downloads of blobs with a valid digest can be verified here.
+	if desc.Digest.Validate() != nil || getResumeOffsets(l.image.ctx)[desc.Digest.String()] > 0 {
+		return rc, nil
+	}
+	return &verifyingReadCloser{ReadCloser: rc, digest: desc.Digest, digester: desc.Digest.Algorithm().Digester()}, nil
 }
 
-// Uncompressed returns the uncompressed layer contents.
+// verifyingReadCloser fails the final read of a blob whose content does not
+// match its digest, e.g. when served by a misbehaving mirror, with an
+// *oci.DigestMismatchError.
+type verifyingReadCloser struct {
+	io.ReadCloser
+	digest   godigest.Digest
+	digester godigest.Digester
+}
+
+func (r *verifyingReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	_, _ = r.digester.Hash().Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if computed := r.digester.Digest(); computed != r.digest {
+			return n, fmt.Errorf("blob %s: %w", r.digest, &oci.DigestMismatchError{
+				Expected: oci.Hash{Algorithm: r.digest.Algorithm().String(), Hex: r.digest.Encoded()},
+				Computed: oci.Hash{Algorithm: computed.Algorithm().String(), Hex: computed.Encoded()},
+			})
+		}
+	}
+	return n, err
+}
+
+// Uncompressed returns the uncompressed layer contents. Layers with a zstd
+// media type are decompressed; all other layers are stored uncompressed.
 func (l *remoteLayer) Uncompressed() (io.ReadCloser, error) {
-	// For simplicity, return compressed data
-	// Real implementations would decompress based on media type
-	return l.Compressed()
+	rc, err := l.Compressed()
+	if err != nil {
+		return nil, err
+	}
+	if !l.desc.MediaType.IsZstd() {
+		return rc, nil
+	}
+	return oci.NewZstdReadCloser(rc)
 }
 
 // Size returns the compressed layer size.
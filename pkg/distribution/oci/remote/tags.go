@@ -0,0 +1,125 @@
+package remote
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+)
+
+// maxTagPages bounds the number of tag list pages fetched for a repository so
+// that a misbehaving registry cannot keep ListTags paginating forever.
+const maxTagPages = 1000
+
+// tagList is the body of a registry tags list response.
+type tagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ListTags returns the tags of a remote repository. Paginated responses are
+// followed through their Link headers until every page has been fetched.
+func ListTags(repo reference.Repository, opts ...Option) ([]string, error) {
+	o := makeOptions(opts...)
+
+	client := &http.Client{Transport: &rangeTransport{base: o.transport, userAgent: o.userAgent}}
+	authorizer := docker.NewDockerAuthorizer(
+		docker.WithAuthClient(client),
+		docker.WithAuthCreds(credentialsFunc(o, repo.Registry)))
+
+	scheme := repo.Registry.Scheme()
+	if o.plainHTTP {
+		scheme = "http"
+	}
+	host, err := docker.DefaultHost(repo.Registry.RegistryStr())
+	if err != nil {
+		return nil, fmt.Errorf("resolving registry host: %w", err)
+	}
+	next := &url.URL{
+		Scheme: scheme,
+		Host:   host,
+		Path:   fmt.Sprintf("/v2/%s/tags/list", repo.RepositoryStr()),
+	}
+
+	var tags []string
+	for page := 0; next != nil; page++ {
+		if page == maxTagPages {
+			return nil, fmt.Errorf("listing tags of %s: more than %d pages", repo.Name(), maxTagPages)
+		}
+		resp, err := fetchTagsPage(o, client, authorizer, next)
+		if err != nil {
+			return nil, fmt.Errorf("listing tags of %s: %w", repo.Name(), err)
+		}
+		var list tagList
+		err = json.NewDecoder(resp.Body).Decode(&list)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding tags of %s: %w", repo.Name(), err)
+		}
+		tags = append(tags, list.Tags...)
+
+		next, err = nextPageURL(next, resp.Header.Get("Link"))
+		if err != nil {
+			return nil, fmt.Errorf("listing tags of %s: %w", repo.Name(), err)
+		}
+	}
+	return tags, nil
+}
+
+// fetchTagsPage fetches a single tags list page, authorizing and retrying the
+// request once if the registry challenges it.
+func fetchTagsPage(o *options, client *http.Client, authorizer docker.Authorizer, u *url.URL) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(o.ctx, http.MethodGet, u.String(), http.NoBody)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Accept", "application/json")
+		if err := authorizer.Authorize(o.ctx, req); err != nil {
+			return nil, fmt.Errorf("authorizing request: %w", err)
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusOK {
+			return resp, nil
+		}
+
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			err := authorizer.AddResponses(o.ctx, []*http.Response{resp})
+			resp.Body.Close()
+			if err != nil {
+				return nil, fmt.Errorf("authorizing request: %w", err)
+			}
+			continue
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
+
+// nextPageURL returns the URL of the next page advertised by a Link header,
+// resolved against the URL of the current page, or nil on the last page.
+func nextPageURL(current *url.URL, link string) (*url.URL, error) {
+	for _, part := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(part), ";")
+		if !ok || !strings.Contains(strings.ReplaceAll(params, " ", ""), `rel="next"`) {
+			continue
+		}
+		target = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(target), "<"), ">")
+		next, err := current.Parse(target)
+		if err != nil {
+			return nil, fmt.Errorf("parsing next page link %q: %w", target, err)
+		}
+		return next, nil
+	}
+	return nil, nil
+}
@@ -0,0 +1,94 @@
+package remote_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+	"github.com/docker/model-runner/pkg/distribution/oci/remote"
+)
+
+// newPaginatedTagsServer returns a registry serving tags for ai/model two per
+// page. Requests must carry the bearer token issued by its token endpoint.
+func newPaginatedTagsServer(t *testing.T, tags []string) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:ai/model:pull" {
+				t.Errorf("Unexpected token scope %q", r.URL.Query().Get("scope"))
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"token":"test-token"}`)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:ai/model:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/ai/model/tags/list" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"errors":[{"code":"NAME_UNKNOWN","message":"repository name not known to registry"}]}`)
+			return
+		}
+
+		start := 0
+		if last := r.URL.Query().Get("last"); last != "" {
+			start = slices.Index(tags, last) + 1
+		}
+		end := min(start+2, len(tags))
+		if end < len(tags) {
+			// Alternate between relative and absolute links, both of which
+			// registries use in practice.
+			link := "/v2/ai/model/tags/list?last=" + tags[end-1]
+			if start%4 != 0 {
+				link = server.URL + link
+			}
+			w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, link))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"name": "ai/model", "tags": tags[start:end]})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListTagsFollowsPagination(t *testing.T) {
+	var tags []string
+	for i := range 7 {
+		tags = append(tags, "v"+strconv.Itoa(i))
+	}
+	server := newPaginatedTagsServer(t, tags)
+
+	ref, err := reference.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/ai/model")
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	got, err := remote.ListTags(ref.Context(), remote.WithPlainHTTP(true))
+	if err != nil {
+		t.Fatalf("ListTags failed: %v", err)
+	}
+	if !slices.Equal(got, tags) {
+		t.Errorf("Expected tags %v, got %v", tags, got)
+	}
+}
+
+func TestListTagsUnknownRepository(t *testing.T) {
+	server := newPaginatedTagsServer(t, []string{"latest"})
+
+	ref, err := reference.ParseReference(strings.TrimPrefix(server.URL, "http://") + "/ai/other")
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	_, err = remote.ListTags(ref.Context(), remote.WithPlainHTTP(true))
+	if err == nil || !strings.Contains(err.Error(), "NAME_UNKNOWN") {
+		t.Errorf("Expected NAME_UNKNOWN error, got %v", err)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -19,8 +20,10 @@ import (
 // PullScope is the scope for pulling from a registry.
 const PullScope = "pull"
 
-// PushScope is the scope for pushing to a registry.
-const PushScope = "push,pull"
+// PushScope is the scope for pushing to a registry. Pushes need pull access
+// too, to check for blobs the repository already has, but nothing beyond the
+// repository being pushed to.
+const PushScope = "pull,push"
 
 // PingResponse contains information from a registry ping.
 type PingResponse struct {
@@ -41,12 +44,19 @@ type Token struct {
 	ExpiresIn   int    `json:"expires_in"`
 }
 
+// ErrDisallowedHost is returned when a URL refers to a host on a private,
+// loopback or link-local network, or to a well-known internal hostname.
+var ErrDisallowedHost = errors.New("host is not allowed")
+
 // privateOrLoopbackCIDRs lists IP ranges that must never be contacted as a
-// token-exchange realm. Allowing requests to these addresses would let a
+// token-exchange realm, or by any other request whose URL is controlled by a
+// third party. Allowing requests to these addresses would let a
 // malicious registry pivot Model Runner into an internal-service proxy
 // (SSRF), reaching endpoints that are not accessible from the public internet.
 var privateOrLoopbackCIDRs = func() []*net.IPNet {
 	cidrs := []string{
+		"0.0.0.0/8",      // "this network", which reaches the local host
+		"::/128",         // unspecified IPv6, which reaches the local host
 		"127.0.0.0/8",    // loopback IPv4
 		"::1/128",        // loopback IPv6
 		"169.254.0.0/16", // link-local IPv4 / AWS EC2 instance-metadata
@@ -91,8 +101,8 @@ func isDisallowedIP(ip net.IP) bool {
 	return false
 }
 
-// resolveAndValidateRealm parses the realm URL, validates the hostname against
-// a static blocklist, and resolves it to a dial address (ip:port) that is safe
+// resolveAndValidateHost parses rawURL, validates the hostname against a
+// static blocklist, and resolves it to a dial address (ip:port) that is safe
 // to connect to. By returning the resolved IP, callers can use a custom
 // DialContext to connect to that exact address — preventing DNS-rebinding
 // attacks where a malicious DNS server could return different IPs for
@@ -100,10 +110,10 @@ func isDisallowedIP(ip net.IP) bool {
 //
 // If hostname is a literal IP address it is validated directly without
 // triggering a DNS lookup.
-func resolveAndValidateRealm(rawURL string) (dialAddr, hostname string, err error) {
+func resolveAndValidateHost(rawURL string) (dialAddr, hostname string, err error) {
 	u, err := url.Parse(rawURL)
 	if err != nil {
-		return "", "", fmt.Errorf("invalid realm URL: %w", err)
+		return "", "", fmt.Errorf("invalid URL: %w", err)
 	}
 
 	hostname = u.Hostname()
@@ -120,7 +130,7 @@ func resolveAndValidateRealm(rawURL string) (dialAddr, hostname string, err erro
 	// Block well-known internal hostnames regardless of DNS resolution.
 	for _, internal := range internalHostnames {
 		if strings.EqualFold(hostname, internal) {
-			return "", "", fmt.Errorf("realm URL hostname %q is not allowed", hostname)
+			return "", "", fmt.Errorf("%w: hostname %q", ErrDisallowedHost, hostname)
 		}
 	}
 
@@ -128,7 +138,7 @@ func resolveAndValidateRealm(rawURL string) (dialAddr, hostname string, err erro
 	// DNS lookup — there is no DNS to rebind.
 	if ip := net.ParseIP(hostname); ip != nil {
 		if isDisallowedIP(ip) {
-			return "", "", fmt.Errorf("realm URL contains a disallowed IP address %s", hostname)
+			return "", "", fmt.Errorf("%w: IP address %s", ErrDisallowedHost, hostname)
 		}
 		return net.JoinHostPort(hostname, port), hostname, nil
 	}
@@ -138,10 +148,10 @@ func resolveAndValidateRealm(rawURL string) (dialAddr, hostname string, err erro
 	// passed validation is the one that will be used for the connection.
 	ips, err := net.LookupHost(hostname)
 	if err != nil {
-		return "", "", fmt.Errorf("resolving realm hostname %q: %w", hostname, err)
+		return "", "", fmt.Errorf("resolving hostname %q: %w", hostname, err)
 	}
 	if len(ips) == 0 {
-		return "", "", fmt.Errorf("realm hostname %q resolved to no addresses", hostname)
+		return "", "", fmt.Errorf("hostname %q resolved to no addresses", hostname)
 	}
 	for _, ipStr := range ips {
 		ip := net.ParseIP(ipStr)
@@ -149,7 +159,7 @@ func resolveAndValidateRealm(rawURL string) (dialAddr, hostname string, err erro
 			continue
 		}
 		if isDisallowedIP(ip) {
-			return "", "", fmt.Errorf("realm URL resolves to a disallowed address %s", ipStr)
+			return "", "", fmt.Errorf("%w: %q resolves to %s", ErrDisallowedHost, hostname, ipStr)
 		}
 	}
 
@@ -188,6 +198,42 @@ func buildSafeTransport(base http.RoundTripper, dialAddr, serverName string) (ht
 	return cloned, nil
 }
 
+// NewPublicTransport returns a transport that only connects to hosts on
+// public networks. Each request, including every redirect a client follows,
+// is validated as token-exchange realms are and dialed at the validated
+// address, so that a URL supplied by a third party can't reach internal
+// services. If base is nil, the default transport is used. Connections can
+// only be pinned when base is an *http.Transport; any other base is left to
+// dial the validated URL itself.
+func NewPublicTransport(base http.RoundTripper) http.RoundTripper {
+	return publicTransport{base: base}
+}
+
+// publicTransport is the transport NewPublicTransport returns.
+type publicTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t publicTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dialAddr, hostname, err := resolveAndValidateHost(req.URL.String())
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := t.base.(*http.Transport); !ok && t.base != nil {
+		return t.base.RoundTrip(req)
+	}
+	transport, err := buildSafeTransport(t.base, dialAddr, hostname)
+	if err != nil {
+		return nil, err
+	}
+	// The pinned transport is only used for this request, so don't leave its
+	// connection idle once the response has been read.
+	req = req.Clone(req.Context())
+	req.Close = true
+	return transport.RoundTrip(req)
+}
+
 // Ping pings a registry and returns authentication information.
 func Ping(ctx context.Context, reg reference.Registry, transport http.RoundTripper) (*PingResponse, error) {
 	if transport == nil {
@@ -253,22 +299,19 @@ func parseWWWAuthenticate(header string) WWWAuthenticate {
 	return result
 }
 
-// Exchange exchanges credentials for a bearer token.
+// Exchange exchanges credentials for a bearer token. The token is requested
+// from the realm pr advertises, which must be on a public network.
 func Exchange(ctx context.Context, _ reference.Registry, auth authn.Authenticator, transport http.RoundTripper, scopes []string, pr *PingResponse) (*Token, error) {
 	if transport == nil {
 		transport = http.DefaultTransport
 	}
 
-	dialAddr, hostname, err := resolveAndValidateRealm(pr.WWWAuthenticate.Realm)
-	if err != nil {
+	if _, _, err := resolveAndValidateHost(pr.WWWAuthenticate.Realm); err != nil {
 		return nil, fmt.Errorf("realm URL rejected: %w", err)
 	}
-
-	safeTransport, err := buildSafeTransport(transport, dialAddr, hostname)
-	if err != nil {
-		return nil, fmt.Errorf("failed to build safe transport: %w", err)
-	}
-	client := &http.Client{Transport: safeTransport}
+	// The public transport validates the realm again, along with any redirect
+	// from it, and dials the validated address.
+	client := &http.Client{Transport: NewPublicTransport(transport)}
 
 	// Build token request URL
 	tokenURL, err := url.Parse(pr.WWWAuthenticate.Realm)
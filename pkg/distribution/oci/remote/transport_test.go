@@ -2,13 +2,17 @@ package remote_test
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
 
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/oci/authn"
 	"github.com/docker/model-runner/pkg/distribution/oci/reference"
 	"github.com/docker/model-runner/pkg/distribution/oci/remote"
 )
@@ -106,3 +110,87 @@ func TestExchangeSSRF_SensitiveBodyNotReflectedInError(t *testing.T) {
 		}
 	}
 }
+
+// TestPushScope verifies that pushes request pull and push access to the
+// target repository only.
+func TestPushScope(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want string
+	}{
+		{ref: "model", want: "repository:ai/model:pull,push"},
+		{ref: "myorg/model:v1", want: "repository:myorg/model:pull,push"},
+		{ref: "registry.example.com:5000/org/team/model:v1", want: "repository:org/team/model:pull,push"},
+		{ref: "registry.example.com/org/model@sha256:" + strings.Repeat("a", 64), want: "repository:org/model:pull,push"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			ref, err := reference.ParseReference(tt.ref, reference.WithDefaultOrg("ai"))
+			if err != nil {
+				t.Fatalf("Failed to parse reference: %v", err)
+			}
+			if got := ref.Scope(remote.PushScope); got != tt.want {
+				t.Errorf("Expected scope %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+// tokenRegistryTransport fakes a registry at a public address whose token
+// endpoint records the scopes it is asked for.
+type tokenRegistryTransport struct {
+	scopes chan []string
+}
+
+func (t tokenRegistryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	switch req.URL.Path {
+	case "/v2/":
+		rec.Header().Set("WWW-Authenticate", `Bearer realm="https://203.0.113.7/token",service="registry"`)
+		rec.WriteHeader(http.StatusUnauthorized)
+	case "/token":
+		t.scopes <- req.URL.Query()["scope"]
+		rec.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rec).Encode(remote.Token{Token: "token"})
+	default:
+		rec.WriteHeader(http.StatusNotFound)
+	}
+	return rec.Result(), nil
+}
+
+// layerlessImage is an image whose layers can't be read, which stops a push
+// once it is authorized.
+type layerlessImage struct {
+	oci.Image
+}
+
+func (layerlessImage) Layers() ([]oci.Layer, error) {
+	return nil, errors.New("no layers")
+}
+
+// TestWriteRequestsPushScope verifies that a push exchanges its credentials
+// for a token scoped to pulling from and pushing to the target repository.
+func TestWriteRequestsPushScope(t *testing.T) {
+	ref, err := reference.ParseReference("203.0.113.7/myorg/model:v1")
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	transport := tokenRegistryTransport{scopes: make(chan []string, 1)}
+	err = remote.Write(ref, layerlessImage{}, nil,
+		remote.WithContext(t.Context()),
+		remote.WithTransport(transport),
+		remote.WithAuth(&authn.Basic{Username: "user", Password: "secret"}),
+	)
+	if err == nil {
+		t.Fatal("Expected the push to fail on reading layers")
+	}
+
+	select {
+	case scopes := <-transport.scopes:
+		if want := []string{"repository:myorg/model:pull,push"}; !slices.Equal(scopes, want) {
+			t.Errorf("Expected token request scopes %q, got %q", want, scopes)
+		}
+	default:
+		t.Fatal("Expected a token request")
+	}
+}
@@ -1,5 +1,7 @@
 package oci
 
+import "strings"
+
 // MediaType is an enumeration of the supported mime types that an element of an image might have.
 type MediaType string
 
@@ -26,6 +28,27 @@ const (
 	DockerUncompressedLayer MediaType = "application/vnd.docker.image.rootfs.diff.tar"
 )
 
+// zstdSuffix is the structured syntax suffix indicating zstd-compressed content.
+const zstdSuffix = "+zstd"
+
+// IsZstd returns true if the media type indicates zstd-compressed content.
+func (m MediaType) IsZstd() bool {
+	return strings.HasSuffix(string(m), zstdSuffix)
+}
+
+// WithZstd returns the zstd-compressed variant of the media type.
+func (m MediaType) WithZstd() MediaType {
+	if m.IsZstd() {
+		return m
+	}
+	return m + zstdSuffix
+}
+
+// WithoutZstd returns the uncompressed variant of the media type.
+func (m MediaType) WithoutZstd() MediaType {
+	return MediaType(strings.TrimSuffix(string(m), zstdSuffix))
+}
+
 // IsDistributable returns true if a layer is distributable (not foreign).
 func (m MediaType) IsDistributable() bool {
 	return m != DockerForeignLayer
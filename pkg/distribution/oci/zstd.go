@@ -0,0 +1,31 @@
+package oci
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// zstdReadCloser decompresses a zstd stream and closes both the decoder and
+// the underlying stream on Close.
+type zstdReadCloser struct {
+	*zstd.Decoder
+	rc io.ReadCloser
+}
+
+// NewZstdReadCloser returns an io.ReadCloser that decompresses the zstd
+// stream read from rc. Closing it also closes rc.
+func NewZstdReadCloser(rc io.ReadCloser) (io.ReadCloser, error) {
+	dec, err := zstd.NewReader(rc)
+	if err != nil {
+		rc.Close()
+		return nil, err
+	}
+	return &zstdReadCloser{Decoder: dec, rc: rc}, nil
+}
+
+// Close implements io.Closer.
+func (z *zstdReadCloser) Close() error {
+	z.Decoder.Close()
+	return z.rc.Close()
+}
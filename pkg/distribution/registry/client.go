@@ -2,6 +2,9 @@ package registry
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -24,6 +27,9 @@ var (
 	defaultRegistryOpts []reference.Option
 	once                sync.Once
 	DefaultTransport    = remote.DefaultTransport
+
+	plainHTTPHostsMu sync.RWMutex
+	plainHTTPHosts   []string
 )
 
 // GetDefaultRegistryOptions returns reference.Option slice with custom default registry
@@ -32,6 +38,13 @@ var (
 // Returns a copy of the options to prevent race conditions from slice modifications.
 // - DEFAULT_REGISTRY: Override the default registry (index.docker.io)
 // - INSECURE_REGISTRY: Set to "true" to allow HTTP connections
+// - PLAIN_HTTP_HOSTS: Comma-separated registry hosts always reached over HTTP
+// - REGISTRY_MIRROR: Registry tried first for pulls, falling back on a miss
+//
+// A registry is reached over HTTP if INSECURE_REGISTRY is "true", which applies
+// to every registry and takes precedence; otherwise if it is listed in
+// PLAIN_HTTP_HOSTS or registered with SetPlainHTTPHosts; otherwise if it is
+// localhost or has a .local suffix. All other registries use HTTPS.
 func GetDefaultRegistryOptions() []reference.Option {
 	once.Do(func() {
 		var opts []reference.Option
@@ -41,19 +54,55 @@ func GetDefaultRegistryOptions() []reference.Option {
 		if os.Getenv("INSECURE_REGISTRY") == "true" {
 			opts = append(opts, reference.Insecure)
 		}
+		if hosts := parsePlainHTTPHosts(os.Getenv("PLAIN_HTTP_HOSTS")); len(hosts) > 0 {
+			opts = append(opts, reference.WithPlainHTTPHosts(hosts...))
+		}
+		if mirror := strings.TrimSpace(os.Getenv("REGISTRY_MIRROR")); mirror != "" {
+			opts = append(opts, reference.WithMirror(mirror))
+		}
 		// Always use the default org for consistency with model-runner's normalization
 		opts = append(opts, reference.WithDefaultOrg(reference.DefaultOrg))
 		defaultRegistryOpts = opts
 	})
-	return append([]reference.Option(nil), defaultRegistryOpts...)
+	opts := append([]reference.Option(nil), defaultRegistryOpts...)
+	plainHTTPHostsMu.RLock()
+	defer plainHTTPHostsMu.RUnlock()
+	if len(plainHTTPHosts) > 0 {
+		opts = append(opts, reference.WithPlainHTTPHosts(plainHTTPHosts...))
+	}
+	return opts
+}
+
+// SetPlainHTTPHosts sets the registry hosts that are always reached over plain
+// HTTP, in addition to those listed in PLAIN_HTTP_HOSTS. See
+// GetDefaultRegistryOptions for how this interacts with INSECURE_REGISTRY.
+func SetPlainHTTPHosts(hosts []string) {
+	plainHTTPHostsMu.Lock()
+	defer plainHTTPHostsMu.Unlock()
+	plainHTTPHosts = append([]string(nil), hosts...)
+}
+
+// parsePlainHTTPHosts splits a comma-separated host list, dropping empty entries.
+func parsePlainHTTPHosts(value string) []string {
+	var hosts []string
+	for _, host := range strings.Split(value, ",") {
+		if host = strings.TrimSpace(host); host != "" {
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
 }
 
 type Client struct {
 	transport http.RoundTripper
-	userAgent string
-	keychain  authn.Keychain
-	auth      authn.Authenticator
-	plainHTTP bool
+	// transportOptions configures the transport built when none is set
+	// with WithTransport.
+	transportOptions *TransportOptions
+	userAgent        string
+	keychain         authn.Keychain
+	auth             authn.Authenticator
+	plainHTTP        bool
+	mirror           string
 }
 
 type ClientOption func(*Client)
@@ -66,6 +115,14 @@ func WithTransport(transport http.RoundTripper) ClientOption {
 	}
 }
 
+// WithTransportOptions builds the client's transport with the given connection
+// pool settings. A transport set with WithTransport takes precedence.
+func WithTransportOptions(opts TransportOptions) ClientOption {
+	return func(c *Client) {
+		c.transportOptions = &opts
+	}
+}
+
 func WithUserAgent(userAgent string) ClientOption {
 	return func(c *Client) {
 		if userAgent != "" {
@@ -94,6 +151,16 @@ func WithAuth(auth authn.Authenticator) ClientOption {
 	}
 }
 
+// WithKeychain sets the keychain used to resolve credentials when no
+// authenticator is set.
+func WithKeychain(keychain authn.Keychain) ClientOption {
+	return func(c *Client) {
+		if keychain != nil {
+			c.keychain = keychain
+		}
+	}
+}
+
 // WithPlainHTTP enables or disables plain HTTP connections to registries.
 func WithPlainHTTP(plain bool) ClientOption {
 	return func(c *Client) {
@@ -101,15 +168,26 @@ func WithPlainHTTP(plain bool) ClientOption {
 	}
 }
 
+// WithMirror sets a registry that is tried first when pulling, overriding
+// REGISTRY_MIRROR. Content served by the mirror must match the digests
+// resolved by the referenced registry.
+func WithMirror(mirror string) ClientOption {
+	return func(c *Client) {
+		if mirror != "" {
+			c.mirror = mirror
+		}
+	}
+}
+
 func NewClient(opts ...ClientOption) *Client {
 	client := &Client{
-		transport: remote.DefaultTransport,
 		userAgent: DefaultUserAgent,
 		keychain:  authn.DefaultKeychain,
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	client.resolveTransport(remote.DefaultTransport)
 	return client
 }
 
@@ -117,21 +195,49 @@ func NewClient(opts ...ClientOption) *Client {
 // and applying optional modifications via ClientOption functions.
 func FromClient(base *Client, opts ...ClientOption) *Client {
 	client := &Client{
-		transport: base.transport,
 		userAgent: base.userAgent,
 		keychain:  base.keychain,
 		auth:      base.auth,
 		plainHTTP: base.plainHTTP,
+		mirror:    base.mirror,
 	}
 	for _, opt := range opts {
 		opt(client)
 	}
+	client.resolveTransport(base.transport)
 	return client
 }
 
+// resolveTransport sets the transport unless one was set explicitly. Without
+// transport options it is base. With them, a transport is built from the
+// options if base is the default one; otherwise the options are applied to a
+// copy of base if it is an *http.Transport, keeping its TLS and proxy
+// settings, and any other base, e.g. one wrapping another transport, is kept
+// as it is.
+func (c *Client) resolveTransport(base http.RoundTripper) {
+	switch {
+	case c.transport != nil:
+	case c.transportOptions == nil:
+		c.transport = base
+	case base == remote.DefaultTransport:
+		c.transport = NewTransport(*c.transportOptions)
+	default:
+		if t, ok := base.(*http.Transport); ok {
+			t = t.Clone()
+			applyTransportOptions(t, *c.transportOptions)
+			base = t
+		}
+		c.transport = base
+	}
+}
+
 func (c *Client) Model(ctx context.Context, ref string) (types.ModelArtifact, error) {
 	// Parse the reference
-	parsedRef, err := reference.ParseReference(ref, GetDefaultRegistryOptions()...)
+	refOpts := GetDefaultRegistryOptions()
+	if c.mirror != "" {
+		refOpts = append(refOpts, reference.WithMirror(c.mirror))
+	}
+	parsedRef, err := reference.ParseReference(ref, refOpts...)
 	if err != nil {
 		return nil, NewReferenceError(ref, err)
 	}
@@ -153,6 +259,9 @@ func (c *Client) Model(ctx context.Context, ref string) (types.ModelArtifact, er
 
 	// Return the artifact at the given reference
 	remoteImg, err := remote.Image(parsedRef, authOpts...)
+	if errors.Is(err, remote.ErrIndex) {
+		return nil, fmt.Errorf("%w: %s", ErrIndex, ref)
+	}
 	if err != nil {
 		errStr := err.Error()
 		errStrLower := strings.ToLower(errStr)
@@ -182,9 +291,82 @@ func (c *Client) Model(ctx context.Context, ref string) (types.ModelArtifact, er
 	return &artifact{remoteImg}, nil
 }
 
+// Index returns the image index at ref, such as a multi-platform manifest
+// list, along with its digest. References for which Model returns ErrIndex
+// resolve to an index.
+func (c *Client) Index(ctx context.Context, ref string) (*oci.IndexManifest, oci.Hash, error) {
+	refOpts := GetDefaultRegistryOptions()
+	if c.mirror != "" {
+		refOpts = append(refOpts, reference.WithMirror(c.mirror))
+	}
+	parsedRef, err := reference.ParseReference(ref, refOpts...)
+	if err != nil {
+		return nil, oci.Hash{}, NewReferenceError(ref, err)
+	}
+
+	authOpts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithTransport(c.transport),
+		remote.WithUserAgent(c.userAgent),
+		remote.WithPlainHTTP(c.plainHTTP),
+	}
+	if c.auth != nil {
+		authOpts = append(authOpts, remote.WithAuth(c.auth))
+	} else {
+		authOpts = append(authOpts, remote.WithAuthFromKeychain(c.keychain))
+	}
+
+	index, digest, err := remote.Index(parsedRef, authOpts...)
+	if err != nil {
+		errStr := err.Error()
+		switch {
+		case strings.Contains(errStr, "UNAUTHORIZED") || strings.Contains(strings.ToLower(errStr), "unauthorized"):
+			return nil, oci.Hash{}, NewRegistryError(ref, "UNAUTHORIZED", "Authentication required for this model", err)
+		case strings.Contains(errStr, "404") || strings.Contains(strings.ToLower(errStr), "not found"):
+			return nil, oci.Hash{}, NewRegistryError(ref, "MANIFEST_UNKNOWN", "Model not found", err)
+		}
+		return nil, oci.Hash{}, NewRegistryError(ref, "UNKNOWN", err.Error(), err)
+	}
+	return index, digest, nil
+}
+
+// ListTags returns the tags of the repository referenced by repo. Any tag or
+// digest in repo is ignored.
+func (c *Client) ListTags(ctx context.Context, repo string) ([]string, error) {
+	parsedRef, err := reference.ParseReference(repo, GetDefaultRegistryOptions()...)
+	if err != nil {
+		return nil, NewReferenceError(repo, err)
+	}
+
+	authOpts := []remote.Option{
+		remote.WithContext(ctx),
+		remote.WithTransport(c.transport),
+		remote.WithUserAgent(c.userAgent),
+		remote.WithPlainHTTP(c.plainHTTP),
+	}
+	if c.auth != nil {
+		authOpts = append(authOpts, remote.WithAuth(c.auth))
+	} else {
+		authOpts = append(authOpts, remote.WithAuthFromKeychain(c.keychain))
+	}
+
+	tags, err := remote.ListTags(parsedRef.Context(), authOpts...)
+	if err != nil {
+		errStr := err.Error()
+		switch {
+		case strings.Contains(errStr, "UNAUTHORIZED") || strings.Contains(strings.ToLower(errStr), "unauthorized"):
+			return nil, NewRegistryError(repo, "UNAUTHORIZED", "Authentication required for this repository", err)
+		case strings.Contains(errStr, "NAME_UNKNOWN") || strings.Contains(errStr, "status 404"):
+			return nil, NewRegistryError(repo, "NAME_UNKNOWN", "Repository not found", err)
+		}
+		return nil, NewRegistryError(repo, "UNKNOWN", err.Error(), err)
+	}
+	return tags, nil
+}
+
 func (c *Client) BlobURL(ref string, digest oci.Hash) (string, error) {
 	// Parse the reference
-	parsedRef, err := reference.ParseReference(ref, GetDefaultRegistryOptions()...)
+	parsedRef, err := reference.ParseReference(ref, c.directReferenceOptions()...)
 	if err != nil {
 		return "", NewReferenceError(ref, err)
 	}
@@ -196,9 +378,22 @@ func (c *Client) BlobURL(ref string, digest oci.Hash) (string, error) {
 		digest.String()), nil
 }
 
+// directReferenceOptions returns the options for parsing references that are
+// accessed directly rather than through the remote package, so that a client
+// configured for plain HTTP builds plain HTTP URLs.
+func (c *Client) directReferenceOptions() []reference.Option {
+	opts := GetDefaultRegistryOptions()
+	if c.plainHTTP {
+		opts = append(opts, reference.Insecure)
+	}
+	return opts
+}
+
+// BearerToken returns a token for pulling ref, or an empty token if the
+// registry does not require one.
 func (c *Client) BearerToken(ctx context.Context, ref string) (string, error) {
 	// Parse the reference
-	parsedRef, err := reference.ParseReference(ref, GetDefaultRegistryOptions()...)
+	parsedRef, err := reference.ParseReference(ref, c.directReferenceOptions()...)
 	if err != nil {
 		return "", NewReferenceError(ref, err)
 	}
@@ -217,6 +412,10 @@ func (c *Client) BearerToken(ctx context.Context, ref string) (string, error) {
 	if err != nil {
 		return "", fmt.Errorf("pinging registry: %w", err)
 	}
+	if pr.WWWAuthenticate.Realm == "" {
+		// The registry does not require a token.
+		return "", nil
+	}
 
 	tok, err := remote.Exchange(ctx, parsedRef.Context().Registry, auth, c.transport, []string{parsedRef.Scope(remote.PullScope)}, pr)
 	if err != nil {
@@ -225,6 +424,57 @@ func (c *Client) BearerToken(ctx context.Context, ref string) (string, error) {
 	return tok.Token, nil
 }
 
+// DownloadBlob streams the blob with the given digest from the repository of
+// ref to w, bypassing the local store. The content is verified against digest
+// as it is written; on a mismatch an error is returned and the bytes already
+// written to w must be discarded by the caller.
+func (c *Client) DownloadBlob(ctx context.Context, ref string, digest oci.Hash, w io.Writer) error {
+	if digest.Algorithm != "sha256" {
+		return fmt.Errorf("unsupported digest algorithm %q", digest.Algorithm)
+	}
+	blobURL, err := c.BlobURL(ref, digest)
+	if err != nil {
+		return err
+	}
+	tok, err := c.BearerToken(ctx, ref)
+	if err != nil {
+		return fmt.Errorf("getting bearer token: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, blobURL, http.NoBody)
+	if err != nil {
+		return fmt.Errorf("creating blob request: %w", err)
+	}
+	req.Header.Set("User-Agent", c.userAgent)
+	if tok != "" {
+		req.Header.Set("Authorization", "Bearer "+tok)
+	}
+	resp, err := (&http.Client{Transport: c.transport}).Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching blob: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return NewRegistryError(ref, "UNAUTHORIZED", "Authentication required for this model", nil)
+	case http.StatusNotFound:
+		return NewRegistryError(ref, "BLOB_UNKNOWN", fmt.Sprintf("Blob %s not found", digest), nil)
+	default:
+		return NewRegistryError(ref, "UNKNOWN", fmt.Sprintf("unexpected status %d fetching blob", resp.StatusCode), nil)
+	}
+
+	hasher := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(w, hasher), resp.Body); err != nil {
+		return fmt.Errorf("downloading blob: %w", err)
+	}
+	if got := hex.EncodeToString(hasher.Sum(nil)); got != digest.Hex {
+		return fmt.Errorf("digest mismatch: expected %s, got sha256:%s", digest, got)
+	}
+	return nil
+}
+
 type Target struct {
 	reference reference.Reference
 	transport http.RoundTripper
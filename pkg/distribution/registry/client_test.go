@@ -1,11 +1,25 @@
 package registry
 
 import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/docker/model-runner/pkg/distribution/internal/testutil"
+	"github.com/docker/model-runner/pkg/distribution/oci"
 	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+	"github.com/docker/model-runner/pkg/distribution/oci/remote"
+	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
 func TestGetDefaultRegistryOptions_NoEnvVars(t *testing.T) {
@@ -129,6 +143,51 @@ func TestGetDefaultRegistryOptions_BothEnvVars(t *testing.T) {
 	}
 }
 
+func TestGetDefaultRegistryOptions_PlainHTTPHosts(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        string
+		configured []string
+	}{
+		{name: "env", env: "registry.internal:5000, mirror.internal"},
+		{name: "configured", configured: []string{"registry.internal:5000", "mirror.internal"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetOnceForTest()
+			os.Unsetenv("DEFAULT_REGISTRY")
+			os.Unsetenv("INSECURE_REGISTRY")
+			t.Setenv("PLAIN_HTTP_HOSTS", tt.env)
+			SetPlainHTTPHosts(tt.configured)
+			t.Cleanup(func() { SetPlainHTTPHosts(nil) })
+
+			opts := GetDefaultRegistryOptions()
+			for ref, want := range map[string]string{
+				"registry.internal:5000/myrepo/model:tag":   "http",
+				"mirror.internal:8443/myrepo/model:tag":     "http",
+				"mirror.internal/myrepo/model:tag":          "http",
+				"registry.internal:5001/myrepo/model:tag":   "https",
+				"other.internal/myrepo/model:tag":           "https",
+				"myrepo/model:tag":                          "https",
+				"registry.local/myrepo/model:tag":           "http",
+				"localhost:5000/myrepo/model:tag":           "http",
+				"REGISTRY.INTERNAL:5000/myrepo/model:tag":   "http",
+				"registry.internal.example/myrepo/m:tag":    "https",
+				"sub.mirror.internal/myrepo/model:tag":      "https",
+				"mirror.internal.evil.com/myrepo/model:tag": "https",
+			} {
+				parsed, err := reference.ParseReference(ref, opts...)
+				if err != nil {
+					t.Fatalf("Failed to parse reference %q: %v", ref, err)
+				}
+				if got := parsed.Context().Registry.Scheme(); got != want {
+					t.Errorf("Scheme for %q = %q, want %q", ref, got, want)
+				}
+			}
+		})
+	}
+}
+
 // Helper function to reset the sync.Once for testing
 // Note: This is a workaround for testing. In production code, sync.Once ensures
 // the initialization only happens once for the lifetime of the program.
@@ -149,6 +208,103 @@ func TestWithTransportNil(t *testing.T) {
 	}
 }
 
+func TestWithTransportOptions(t *testing.T) {
+	custom := &http.Transport{
+		Proxy:           http.ProxyFromEnvironment,
+		TLSClientConfig: &tls.Config{ServerName: "registry.example"},
+	}
+	tests := []struct {
+		name                    string
+		client                  *Client
+		wantMaxIdleConnsPerHost int
+		wantMaxConnsPerHost     int
+		wantIdleConnTimeout     time.Duration
+		wantServerName          string
+	}{
+		{
+			name:                    "defaults",
+			client:                  NewClient(WithTransportOptions(TransportOptions{})),
+			wantMaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+			wantIdleConnTimeout:     DefaultIdleConnTimeout,
+		},
+		{
+			name: "configured",
+			client: NewClient(WithTransportOptions(TransportOptions{
+				MaxIdleConnsPerHost: 64,
+				MaxConnsPerHost:     8,
+				IdleConnTimeout:     time.Minute,
+			})),
+			wantMaxIdleConnsPerHost: 64,
+			wantMaxConnsPerHost:     8,
+			wantIdleConnTimeout:     time.Minute,
+		},
+		{
+			name: "from client",
+			client: FromClient(NewClient(WithTransport(custom)), WithTransportOptions(TransportOptions{
+				MaxConnsPerHost: 4,
+			})),
+			wantMaxIdleConnsPerHost: DefaultMaxIdleConnsPerHost,
+			wantMaxConnsPerHost:     4,
+			wantIdleConnTimeout:     DefaultIdleConnTimeout,
+			wantServerName:          "registry.example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transport, ok := tt.client.transport.(*http.Transport)
+			if !ok {
+				t.Fatalf("Expected an *http.Transport, got %T", tt.client.transport)
+			}
+			if transport == custom || transport == DefaultTransport {
+				t.Fatal("Expected a newly built transport")
+			}
+			if transport.MaxIdleConnsPerHost != tt.wantMaxIdleConnsPerHost {
+				t.Errorf("MaxIdleConnsPerHost = %d, want %d", transport.MaxIdleConnsPerHost, tt.wantMaxIdleConnsPerHost)
+			}
+			if transport.MaxIdleConns < transport.MaxIdleConnsPerHost {
+				t.Errorf("MaxIdleConns = %d, want at least %d", transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+			}
+			if transport.MaxConnsPerHost != tt.wantMaxConnsPerHost {
+				t.Errorf("MaxConnsPerHost = %d, want %d", transport.MaxConnsPerHost, tt.wantMaxConnsPerHost)
+			}
+			if transport.IdleConnTimeout != tt.wantIdleConnTimeout {
+				t.Errorf("IdleConnTimeout = %v, want %v", transport.IdleConnTimeout, tt.wantIdleConnTimeout)
+			}
+			if transport.Proxy == nil {
+				t.Error("Expected the transport to honor proxy settings")
+			}
+			if tt.wantServerName != "" && (transport.TLSClientConfig == nil || transport.TLSClientConfig.ServerName != tt.wantServerName) {
+				t.Errorf("Expected the TLS settings of the base transport to be kept, got %+v", transport.TLSClientConfig)
+			}
+		})
+	}
+}
+
+func TestFromClientKeepsWrappingTransport(t *testing.T) {
+	var wrapped http.RoundTripper = roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return http.DefaultTransport.RoundTrip(req)
+	})
+	client := FromClient(NewClient(WithTransport(wrapped)), WithTransportOptions(TransportOptions{MaxConnsPerHost: 4}))
+	if _, ok := client.transport.(roundTripperFunc); !ok {
+		t.Errorf("Expected the base transport to be kept, got %T", client.transport)
+	}
+}
+
+func TestWithTransportOverridesTransportOptions(t *testing.T) {
+	custom := &http.Transport{}
+	options := WithTransportOptions(TransportOptions{MaxConnsPerHost: 8})
+
+	for _, client := range []*Client{
+		NewClient(WithTransport(custom), options),
+		NewClient(options, WithTransport(custom)),
+	} {
+		if client.transport != custom {
+			t.Errorf("Expected the custom transport to be used, got %v", client.transport)
+		}
+	}
+}
+
 func TestWithUserAgentEmpty(t *testing.T) {
 	client := NewClient(WithUserAgent(""))
 
@@ -161,3 +317,272 @@ func TestWithUserAgentEmpty(t *testing.T) {
 			client.userAgent, DefaultUserAgent)
 	}
 }
+
+// mirrorTestServer wraps a test registry, recording the paths it is asked for
+// and letting tests drop or corrupt individual blobs.
+type mirrorTestServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []string
+	// missing and corrupt are keyed by blob digest.
+	missing map[string]bool
+	corrupt map[string]bool
+}
+
+func newMirrorTestServer(t *testing.T) *mirrorTestServer {
+	t.Helper()
+	s := &mirrorTestServer{missing: map[string]bool{}, corrupt: map[string]bool{}}
+	registry := testregistry.New()
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		if _, dgst, ok := strings.Cut(r.URL.Path, "/blobs/"); ok && !strings.HasPrefix(dgst, "uploads") {
+			if s.missing[dgst] {
+				http.Error(w, "blob unknown", http.StatusNotFound)
+				return
+			}
+			if s.corrupt[dgst] && r.Method == http.MethodGet {
+				rec := httptest.NewRecorder()
+				registry.ServeHTTP(rec, r)
+				body := rec.Body.Bytes()
+				body[0] ^= 0xff
+				w.WriteHeader(rec.Code)
+				_, _ = w.Write(body)
+				return
+			}
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// host returns the host:port of the server.
+func (s *mirrorTestServer) host() string {
+	return strings.TrimPrefix(s.URL, "http://")
+}
+
+// served reports whether the server was asked for path with method.
+func (s *mirrorTestServer) served(method, path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Contains(s.requests, method+" "+path)
+}
+
+func TestModelFromMirror(t *testing.T) {
+	mdl := testutil.NewGGUFArtifact(t, filepath.Join("..", "assets", "dummy.gguf"),
+		testutil.Layer(filepath.Join("..", "assets", "license.txt"), types.MediaTypeLicense))
+	wantDigest, err := mdl.Digest()
+	if err != nil {
+		t.Fatalf("Failed to get model digest: %v", err)
+	}
+	layers, err := mdl.Layers()
+	if err != nil {
+		t.Fatalf("Failed to get layers: %v", err)
+	}
+	ggufDigest, err := layers[0].Digest()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF layer digest: %v", err)
+	}
+	licenseDigest, err := layers[1].Digest()
+	if err != nil {
+		t.Fatalf("Failed to get license layer digest: %v", err)
+	}
+
+	const repo = "ai/mirrored"
+	push := func(s *mirrorTestServer) {
+		t.Helper()
+		ref, err := reference.NewTag(s.host()+"/"+repo+":latest", reference.WithPlainHTTPHosts(s.host()))
+		if err != nil {
+			t.Fatalf("Failed to parse reference: %v", err)
+		}
+		if err := remote.Write(ref, mdl, nil, remote.WithPlainHTTP(true)); err != nil {
+			t.Fatalf("Failed to push model: %v", err)
+		}
+		s.mu.Lock()
+		s.requests = nil
+		s.mu.Unlock()
+	}
+	blobPath := func(dgst oci.Hash) string {
+		return "/v2/" + repo + "/blobs/" + dgst.String()
+	}
+	readLayers := func(t *testing.T, model types.ModelArtifact) error {
+		t.Helper()
+		remoteLayers, err := model.Layers()
+		if err != nil {
+			t.Fatalf("Failed to get remote layers: %v", err)
+		}
+		for _, layer := range remoteLayers {
+			rc, err := layer.Uncompressed()
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(io.Discard, rc)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	t.Run("mirror serves some blobs", func(t *testing.T) {
+		origin := newMirrorTestServer(t)
+		mirror := newMirrorTestServer(t)
+		push(origin)
+		push(mirror)
+		mirror.missing[ggufDigest.String()] = true
+
+		client := NewClient(WithPlainHTTP(true), WithMirror(mirror.host()))
+		model, err := client.Model(t.Context(), origin.host()+"/"+repo+":latest")
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		gotDigest, err := model.Digest()
+		if err != nil {
+			t.Fatalf("Failed to get remote digest: %v", err)
+		}
+		if gotDigest != wantDigest {
+			t.Errorf("Expected digest %s, got %s", wantDigest, gotDigest)
+		}
+		if err := readLayers(t, model); err != nil {
+			t.Fatalf("Failed to read layers: %v", err)
+		}
+
+		// The mirror serves the manifest by digest and the license, and the
+		// origin serves the GGUF layer the mirror does not have.
+		if !mirror.served(http.MethodGet, "/v2/"+repo+"/manifests/"+wantDigest.String()) {
+			t.Error("Expected the manifest to be fetched from the mirror")
+		}
+		if !mirror.served(http.MethodGet, blobPath(licenseDigest)) {
+			t.Error("Expected the license to be fetched from the mirror")
+		}
+		if origin.served(http.MethodGet, blobPath(licenseDigest)) {
+			t.Error("Expected the license not to be fetched from the origin")
+		}
+		if !origin.served(http.MethodGet, blobPath(ggufDigest)) {
+			t.Error("Expected the GGUF layer to fall back to the origin")
+		}
+		// Tags are only resolved by the origin.
+		for _, method := range []string{http.MethodHead, http.MethodGet} {
+			if mirror.served(method, "/v2/"+repo+"/manifests/latest") {
+				t.Errorf("Expected the tag not to be resolved by the mirror (%s)", method)
+			}
+		}
+	})
+
+	t.Run("mirror serves corrupt blob", func(t *testing.T) {
+		origin := newMirrorTestServer(t)
+		mirror := newMirrorTestServer(t)
+		push(origin)
+		push(mirror)
+		mirror.corrupt[licenseDigest.String()] = true
+
+		client := NewClient(WithPlainHTTP(true), WithMirror(mirror.host()))
+		model, err := client.Model(t.Context(), origin.host()+"/"+repo+":latest")
+		if err != nil {
+			t.Fatalf("Failed to get model: %v", err)
+		}
+		err = readLayers(t, model)
+		if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+			t.Fatalf("Expected a digest mismatch error, got %v", err)
+		}
+	})
+}
+
+func TestGetDefaultRegistryOptions_RegistryMirror(t *testing.T) {
+	resetOnceForTest()
+	os.Unsetenv("DEFAULT_REGISTRY")
+	os.Unsetenv("INSECURE_REGISTRY")
+	os.Unsetenv("PLAIN_HTTP_HOSTS")
+	t.Setenv("REGISTRY_MIRROR", "http://mirror.internal:5000/")
+	t.Cleanup(resetOnceForTest)
+
+	parsed, err := reference.ParseReference("registry.example.com/myrepo/model:tag", GetDefaultRegistryOptions()...)
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	mirror, ok := parsed.Context().Registry.Mirror()
+	if !ok {
+		t.Fatal("Expected a mirror to be configured")
+	}
+	if mirror.RegistryStr() != "mirror.internal:5000" {
+		t.Errorf("Mirror = %q, want %q", mirror.RegistryStr(), "mirror.internal:5000")
+	}
+	if mirror.Scheme() != "http" {
+		t.Errorf("Mirror scheme = %q, want %q", mirror.Scheme(), "http")
+	}
+}
+
+func TestDownloadBlob(t *testing.T) {
+	mdl := testutil.NewGGUFArtifact(t, filepath.Join("..", "assets", "dummy.gguf"))
+	layers, err := mdl.Layers()
+	if err != nil {
+		t.Fatalf("Failed to get layers: %v", err)
+	}
+	ggufDigest, err := layers[0].Digest()
+	if err != nil {
+		t.Fatalf("Failed to get GGUF layer digest: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join("..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+
+	server := newMirrorTestServer(t)
+	SetPlainHTTPHosts([]string{server.host()})
+	t.Cleanup(func() { SetPlainHTTPHosts(nil) })
+	ref := server.host() + "/ai/model:latest"
+	parsedRef, err := reference.NewTag(ref, GetDefaultRegistryOptions()...)
+	if err != nil {
+		t.Fatalf("Failed to parse reference: %v", err)
+	}
+	if err := remote.Write(parsedRef, mdl, nil, remote.WithPlainHTTP(true)); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+	client := NewClient(WithPlainHTTP(true))
+
+	t.Run("verified download", func(t *testing.T) {
+		var buf strings.Builder
+		if err := client.DownloadBlob(t.Context(), ref, ggufDigest, &buf); err != nil {
+			t.Fatalf("Failed to download blob: %v", err)
+		}
+		if buf.String() != string(want) {
+			t.Errorf("Expected %d bytes matching the model file, got %d bytes", len(want), buf.Len())
+		}
+		gotDigest, _, err := oci.SHA256(strings.NewReader(buf.String()))
+		if err != nil {
+			t.Fatalf("Failed to hash downloaded blob: %v", err)
+		}
+		if gotDigest != ggufDigest {
+			t.Errorf("Expected digest %s, got %s", ggufDigest, gotDigest)
+		}
+	})
+
+	t.Run("corrupt blob", func(t *testing.T) {
+		server.corrupt[ggufDigest.String()] = true
+		t.Cleanup(func() { delete(server.corrupt, ggufDigest.String()) })
+		err := client.DownloadBlob(t.Context(), ref, ggufDigest, io.Discard)
+		if err == nil || !strings.Contains(err.Error(), "digest mismatch") {
+			t.Fatalf("Expected a digest mismatch error, got %v", err)
+		}
+	})
+
+	t.Run("missing blob", func(t *testing.T) {
+		server.missing[ggufDigest.String()] = true
+		t.Cleanup(func() { delete(server.missing, ggufDigest.String()) })
+		err := client.DownloadBlob(t.Context(), ref, ggufDigest, io.Discard)
+		var regErr *Error
+		if !errors.As(err, &regErr) || regErr.Code != "BLOB_UNKNOWN" {
+			t.Fatalf("Expected a BLOB_UNKNOWN registry error, got %v", err)
+		}
+	})
+}
+
+// roundTripperFunc adapts a function to an http.RoundTripper.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -9,6 +9,9 @@ var (
 	ErrInvalidReference = errors.New("invalid model reference")
 	ErrModelNotFound    = errors.New("model not found")
 	ErrUnauthorized     = errors.New("unauthorized access to model")
+	// ErrIndex is returned for references that resolve to an image index,
+	// such as a multi-platform manifest list, rather than a single model.
+	ErrIndex = errors.New("model reference is an image index")
 )
 
 // ReferenceError represents an error related to an invalid model reference
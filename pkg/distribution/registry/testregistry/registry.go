@@ -6,6 +6,9 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -56,6 +59,8 @@ func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 		r.handleBlob(w, req, path)
 	case strings.Contains(path, "/manifests/"):
 		r.handleManifest(w, req, path)
+	case strings.HasSuffix(path, "/tags/list"):
+		r.handleTagsList(w, req, strings.TrimSuffix(path, "/tags/list"))
 	default:
 		http.Error(w, "not found", http.StatusNotFound)
 	}
@@ -144,6 +149,7 @@ func (r *Registry) handleBlob(w http.ResponseWriter, req *http.Request, path str
 
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 		w.Header().Set("Docker-Content-Digest", dgst)
+		w.Header().Set("Accept-Ranges", "bytes")
 		w.WriteHeader(http.StatusOK)
 
 	case http.MethodGet:
@@ -162,15 +168,17 @@ func (r *Registry) handleBlob(w http.ResponseWriter, req *http.Request, path str
 			// Parse Range header (format: "bytes=start-" or "bytes=start-end")
 			var start, end int64
 			end = int64(len(content) - 1)
-			n, err := fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
-			if err != nil || n != 1 {
-				// Try parsing with end
-				n, err = fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end)
-				if err != nil || n < 1 {
+			n, err := fmt.Sscanf(rangeHeader, "bytes=%d-%d", &start, &end)
+			if err != nil || n != 2 {
+				// Try parsing without end
+				end = int64(len(content) - 1)
+				n, err = fmt.Sscanf(rangeHeader, "bytes=%d-", &start)
+				if err != nil || n != 1 {
 					http.Error(w, "invalid range", http.StatusBadRequest)
 					return
 				}
 			}
+			end = min(end, int64(len(content)-1))
 
 			if start >= int64(len(content)) {
 				http.Error(w, "range not satisfiable", http.StatusRequestedRangeNotSatisfiable)
@@ -188,6 +196,7 @@ func (r *Registry) handleBlob(w http.ResponseWriter, req *http.Request, path str
 
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(content)))
 		w.Header().Set("Docker-Content-Digest", dgst)
+		w.Header().Set("Accept-Ranges", "bytes")
 		w.WriteHeader(http.StatusOK)
 		w.Write(content)
 
@@ -237,7 +246,7 @@ func (r *Registry) handleManifest(w http.ResponseWriter, req *http.Request, path
 		dgst := digest.FromBytes(manifest)
 		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(manifest)))
 		w.Header().Set("Docker-Content-Digest", dgst.String())
-		w.Header().Set("Content-Type", "application/vnd.oci.image.manifest.v1+json")
+		w.Header().Set("Content-Type", manifestMediaType(manifest))
 
 		if req.Method == http.MethodGet {
 			w.WriteHeader(http.StatusOK)
@@ -271,3 +280,69 @@ func (r *Registry) handleManifest(w http.ResponseWriter, req *http.Request, path
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
 	}
 }
+
+// manifestMediaType returns the media type declared by manifest, defaulting to
+// an OCI image manifest.
+func manifestMediaType(manifest []byte) string {
+	var m struct {
+		MediaType string `json:"mediaType"`
+	}
+	if err := json.Unmarshal(manifest, &m); err != nil || m.MediaType == "" {
+		return "application/vnd.oci.image.manifest.v1+json"
+	}
+	return m.MediaType
+}
+
+// handleTagsList serves the tags of repo in lexical order. Like a real
+// registry, it returns at most n tags following last and advertises the next
+// page through a Link header.
+func (r *Registry) handleTagsList(w http.ResponseWriter, req *http.Request, repo string) {
+	if req.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	r.mu.RLock()
+	repoManifests, ok := r.manifests[repo]
+	var tags []string
+	for ref := range repoManifests {
+		if !strings.HasPrefix(ref, "sha256:") {
+			tags = append(tags, ref)
+		}
+	}
+	r.mu.RUnlock()
+	if !ok {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		//nolint:errchkjson // test registry, ignore write errors
+		_ = json.NewEncoder(w).Encode(ociErrorResponse{
+			Errors: []ociError{{Code: "NAME_UNKNOWN", Message: "Repository not found"}},
+		})
+		return
+	}
+	slices.Sort(tags)
+
+	query := req.URL.Query()
+	if last := query.Get("last"); last != "" {
+		start, _ := slices.BinarySearch(tags, last)
+		for start < len(tags) && tags[start] == last {
+			start++
+		}
+		tags = tags[start:]
+	}
+	if n, err := strconv.Atoi(query.Get("n")); err == nil && n > 0 && n < len(tags) {
+		tags = tags[:n]
+		next := url.Values{"n": {strconv.Itoa(n)}, "last": {tags[n-1]}}
+		w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?%s>; rel="next"`, repo, next.Encode()))
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	//nolint:errchkjson // test registry, ignore write errors
+	_ = json.NewEncoder(w).Encode(struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}{Name: repo, Tags: tags})
+}
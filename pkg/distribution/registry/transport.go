@@ -0,0 +1,60 @@
+package registry
+
+import (
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultMaxIdleConnsPerHost is the number of idle connections kept per
+	// registry host. It is well above net/http's default of 2 so concurrent
+	// layer downloads and tag listings reuse connections.
+	DefaultMaxIdleConnsPerHost = 16
+	// DefaultIdleConnTimeout is how long an idle connection is kept open.
+	DefaultIdleConnTimeout = 90 * time.Second
+)
+
+// TransportOptions tunes the connection pool of the HTTP transport built for
+// registry operations. Zero values select the defaults.
+type TransportOptions struct {
+	// MaxIdleConnsPerHost is the number of idle connections kept per host.
+	// Defaults to DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the connections per host, including those in
+	// use. Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle connection is kept open. Defaults
+	// to DefaultIdleConnTimeout.
+	IdleConnTimeout time.Duration
+}
+
+// NewTransport returns a proxy-aware HTTP transport for registry operations
+// with its connection pool configured from opts.
+func NewTransport(opts TransportOptions) *http.Transport {
+	var transport *http.Transport
+	if t, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport = t.Clone()
+	} else {
+		transport = &http.Transport{}
+	}
+	transport.Proxy = http.ProxyFromEnvironment
+	applyTransportOptions(transport, opts)
+	return transport
+}
+
+// applyTransportOptions configures the connection pool of transport from opts.
+func applyTransportOptions(transport *http.Transport, opts TransportOptions) {
+	transport.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+	if opts.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = opts.MaxIdleConnsPerHost
+	}
+	// Keep the overall idle pool large enough to serve every host's share.
+	transport.MaxIdleConns = max(transport.MaxIdleConns, transport.MaxIdleConnsPerHost)
+	if opts.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = opts.MaxConnsPerHost
+	}
+	transport.IdleConnTimeout = DefaultIdleConnTimeout
+	if opts.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = opts.IdleConnTimeout
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
@@ -68,8 +71,58 @@ const (
 	// Valid values are "true" or "false". When set to "true", it signals that the model packager has not verified
 	// the media type classification and the type is inferred or assumed based on some heuristics.
 	AnnotationMediaTypeUntested = "org.cncf.model.file.mediatype.untested"
+
+	// OCI Annotation keys for model manifests, recording the model's provenance
+
+	// AnnotationSource specifies the URL of the source the model was built from (string)
+	AnnotationSource = "org.opencontainers.image.source"
+
+	// AnnotationLicenses specifies the SPDX license expression of the model (string)
+	AnnotationLicenses = "org.opencontainers.image.licenses"
+
+	// AnnotationBuildTool specifies the name and version of the tool that packaged the model (string)
+	AnnotationBuildTool = "org.cncf.model.build.tool"
 )
 
+// ModelConfigMajorVersion is the major version of the Docker model config
+// media type understood by this client. Newer minor versions only add fields,
+// so their configs are read by ignoring the fields this client does not know.
+const ModelConfigMajorVersion = 0
+
+// GGUFChatTemplateKey is the GGUF metadata key holding the chat template
+// embedded in a model.
+const GGUFChatTemplateKey = "tokenizer.chat_template"
+
+// ErrUnsupportedMediaType is returned when a model's config media type is not
+// supported by this client.
+var ErrUnsupportedMediaType = errors.New("unsupported model config media type")
+
+// ParseModelConfigVersion returns the major and minor version encoded in a
+// Docker model config media type such as MediaTypeModelConfigV01. ok is false
+// if mt is not a Docker model config media type.
+func ParseModelConfigVersion(mt MediaType) (major, minor int, ok bool) {
+	version, found := strings.CutPrefix(string(mt), "application/vnd.docker.ai.model.config.v")
+	if !found {
+		return 0, 0, false
+	}
+	if version, found = strings.CutSuffix(version, "+json"); !found {
+		return 0, 0, false
+	}
+	majorStr, minorStr, found := strings.Cut(version, ".")
+	if !found {
+		return 0, 0, false
+	}
+	major, err := strconv.Atoi(majorStr)
+	if err != nil || major < 0 {
+		return 0, 0, false
+	}
+	minor, err = strconv.Atoi(minorStr)
+	if err != nil || minor < 0 {
+		return 0, 0, false
+	}
+	return major, minor, true
+}
+
 type Format string
 
 // ModelConfig provides a unified interface for accessing model configuration.
@@ -102,6 +155,7 @@ type Config struct {
 	Safetensors  map[string]string `json:"safetensors,omitempty"`
 	Diffusers    map[string]string `json:"diffusers,omitempty"`
 	ContextSize  *int32            `json:"context_size,omitempty"`
+	ChatTemplate string            `json:"chat_template,omitempty"`
 }
 
 // Descriptor provides metadata about the provenance of the model.
@@ -99,6 +99,119 @@ func ModelsPath() (string, error) {
 	return filepath.Join(home, ".docker", "models"), nil
 }
 
+// MaxModelBytes returns the maximum total size in bytes of a pulled model.
+// Configured via MODEL_RUNNER_MAX_MODEL_BYTES; zero or unset means no limit.
+func MaxModelBytes() (int64, error) {
+	s := Var("MODEL_RUNNER_MAX_MODEL_BYTES")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid MODEL_RUNNER_MAX_MODEL_BYTES %q: must be a non-negative integer", s)
+	}
+	return n, nil
+}
+
+// MaxStoreBytes returns the size in bytes above which least recently used
+// models are evicted from the store after a pull. Configured via
+// MODEL_RUNNER_MAX_STORE_BYTES; zero or unset means no limit.
+func MaxStoreBytes() (int64, error) {
+	s := Var("MODEL_RUNNER_MAX_STORE_BYTES")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid MODEL_RUNNER_MAX_STORE_BYTES %q: must be a non-negative integer", s)
+	}
+	return n, nil
+}
+
+// MaxLoadBytes returns the maximum size in bytes of a model archive sent to
+// the load endpoint. Configured via MODEL_RUNNER_MAX_LOAD_BYTES; zero or unset
+// means no limit.
+func MaxLoadBytes() (int64, error) {
+	s := Var("MODEL_RUNNER_MAX_LOAD_BYTES")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid MODEL_RUNNER_MAX_LOAD_BYTES %q: must be a non-negative integer", s)
+	}
+	return n, nil
+}
+
+// DefaultContextSize returns the context size used for models that set none
+// when no context size is configured for the run. Configured via
+// DEFAULT_CONTEXT_SIZE; zero or unset leaves it to the backend.
+func DefaultContextSize() (int32, error) {
+	s := Var("DEFAULT_CONTEXT_SIZE")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid DEFAULT_CONTEXT_SIZE %q: must be a non-negative integer", s)
+	}
+	return int32(n), nil
+}
+
+// MaxInflightRequests returns the maximum number of chat completion requests
+// each backend serves at once. Configured via MODEL_RUNNER_MAX_INFLIGHT_REQUESTS;
+// zero or unset means no limit.
+func MaxInflightRequests() (int, error) {
+	s := Var("MODEL_RUNNER_MAX_INFLIGHT_REQUESTS")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid MODEL_RUNNER_MAX_INFLIGHT_REQUESTS %q: must be a non-negative integer", s)
+	}
+	return int(n), nil
+}
+
+// BlobCopyBufferSize returns the size in bytes of the buffer blobs are written
+// to the store through. Configured via MODEL_RUNNER_BLOB_COPY_BUFFER_SIZE; zero
+// or unset selects the store's default of 1 MiB.
+func BlobCopyBufferSize() (int, error) {
+	s := Var("MODEL_RUNNER_BLOB_COPY_BUFFER_SIZE")
+	if s == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 32)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid MODEL_RUNNER_BLOB_COPY_BUFFER_SIZE %q: must be a non-negative integer", s)
+	}
+	return int(n), nil
+}
+
+// QueueInflightRequests is true when MODEL_RUNNER_QUEUE_INFLIGHT_REQUESTS is
+// set to a truthy value, making chat completion requests beyond
+// MODEL_RUNNER_MAX_INFLIGHT_REQUESTS wait for a slot instead of being rejected.
+var QueueInflightRequests = Bool("MODEL_RUNNER_QUEUE_INFLIGHT_REQUESTS")
+
+// AllowMaxModelBytesOverride is true when MODEL_RUNNER_ALLOW_MAX_MODEL_BYTES_OVERRIDE
+// is set to a truthy value, letting pull requests set their own size limit.
+var AllowMaxModelBytesOverride = Bool("MODEL_RUNNER_ALLOW_MAX_MODEL_BYTES_OVERRIDE")
+
+// AllowPrivateLoadURLs is true when MODEL_RUNNER_ALLOW_PRIVATE_LOAD_URLS is set
+// to a truthy value, letting the load endpoint fetch archives from hosts on
+// private networks.
+var AllowPrivateLoadURLs = Bool("MODEL_RUNNER_ALLOW_PRIVATE_LOAD_URLS")
+
+// RepairStore is true when MODEL_RUNNER_REPAIR_STORE is set to a truthy value,
+// making the model store remove unreferenced blobs and stale incomplete
+// downloads on startup.
+var RepairStore = Bool("MODEL_RUNNER_REPAIR_STORE")
+
+// PreflightPulls is true when MODEL_RUNNER_PREFLIGHT_PULLS is set to a truthy
+// value, making pulls read the GGUF header of the remote model and reject
+// architectures the installed backend can't run before downloading it.
+var PreflightPulls = Bool("MODEL_RUNNER_PREFLIGHT_PULLS")
+
 // TCPPort returns the optional TCP port for the model runner HTTP server.
 // Configured via MODEL_RUNNER_PORT; empty string means use Unix socket.
 func TCPPort() string {
@@ -135,6 +248,13 @@ func VLLMServerPath() string {
 	return Var("VLLM_SERVER_PATH")
 }
 
+// VLLMSandboxPolicy returns the JSON-encoded sandbox policy replacing the
+// default one of vLLM processes. Configured via VLLM_SANDBOX_POLICY; the
+// daemon refuses to start with a policy on platforms that can't enforce it.
+func VLLMSandboxPolicy() string {
+	return Var("VLLM_SANDBOX_POLICY")
+}
+
 // SGLangServerPath returns the optional path to the SGLang server binary.
 // Configured via SGLANG_SERVER_PATH.
 func SGLangServerPath() string {
@@ -169,6 +289,11 @@ func LogDir() string {
 // DisableMetrics is true when DISABLE_METRICS is set to a truthy value (e.g. "1").
 var DisableMetrics = Bool("DISABLE_METRICS")
 
+// ServiceMetrics is true when MODEL_RUNNER_SERVICE_METRICS is set to a truthy
+// value, adding pull, download, store size, and inference request metrics of
+// the daemon itself to the /metrics endpoint.
+var ServiceMetrics = Bool("MODEL_RUNNER_SERVICE_METRICS")
+
 // TLSEnabled is true when MODEL_RUNNER_TLS_ENABLED is set to a truthy value.
 var TLSEnabled = Bool("MODEL_RUNNER_TLS_ENABLED")
 
@@ -193,6 +318,156 @@ func TLSKey() string {
 	return Var("MODEL_RUNNER_TLS_KEY")
 }
 
+// UseDockerKeychain is true (default) unless MODEL_RUNNER_DOCKER_KEYCHAIN is set
+// to a falsy value. When false, registry credentials are only read from the
+// environment, not from the Docker config file or its credential helpers.
+// Call as UseDockerKeychain(true) to get the default-true behaviour.
+var UseDockerKeychain = BoolWithDefault("MODEL_RUNNER_DOCKER_KEYCHAIN")
+
 // TLSAutoCert is true (default) unless MODEL_RUNNER_TLS_AUTO_CERT is set to a falsy value.
 // Call as TLSAutoCert(true) to get the default-true behaviour.
 var TLSAutoCert = BoolWithDefault("MODEL_RUNNER_TLS_AUTO_CERT")
+
+// redacted replaces the values of credentials in Effective.
+const redacted = "<redacted>"
+
+// Effective returns the configuration the model runner resolves from the
+// environment, keyed by variable name, with defaults applied to the variables
+// that have one. Credentials are redacted, as are any in URLs and the values
+// of secret-bearing server arguments. The daemon takes it once its
+// configuration is applied, so that it reports what is in effect even if the
+// environment changes afterwards.
+func Effective() map[string]string {
+	config := map[string]string{
+		"LOG_LEVEL":                                   LogLevel().String(),
+		"DMR_ORIGINS":                                 Var("DMR_ORIGINS"),
+		"MODEL_RUNNER_SOCK":                           SocketPath(),
+		"MODEL_RUNNER_PORT":                           TCPPort(),
+		"DEFAULT_CONTEXT_SIZE":                        envOr("DEFAULT_CONTEXT_SIZE", "0"),
+		"LLAMA_SERVER_PATH":                           LlamaServerPath(),
+		"LLAMA_ARGS":                                  redactArgs(LlamaArgs()),
+		"LLAMA_SERVER_VERSION":                        LlamaServerVersion(),
+		"DISABLE_SERVER_UPDATE":                       strconv.FormatBool(DisableServerUpdate()),
+		"VLLM_SERVER_PATH":                            VLLMServerPath(),
+		"VLLM_SANDBOX_POLICY":                         VLLMSandboxPolicy(),
+		"SGLANG_SERVER_PATH":                          SGLangServerPath(),
+		"MLX_SERVER_PATH":                             MLXServerPath(),
+		"DIFFUSERS_SERVER_PATH":                       DiffusersServerPath(),
+		"VLLM_METAL_SERVER_PATH":                      VLLMMetalServerPath(),
+		"MODEL_RUNNER_LOG_DIR":                        LogDir(),
+		"DISABLE_METRICS":                             strconv.FormatBool(DisableMetrics()),
+		"MODEL_RUNNER_SERVICE_METRICS":                strconv.FormatBool(ServiceMetrics()),
+		"MODEL_RUNNER_TLS_ENABLED":                    strconv.FormatBool(TLSEnabled()),
+		"MODEL_RUNNER_TLS_PORT":                       TLSPort(),
+		"MODEL_RUNNER_TLS_CERT":                       TLSCert(),
+		"MODEL_RUNNER_TLS_KEY":                        TLSKey(),
+		"MODEL_RUNNER_TLS_AUTO_CERT":                  strconv.FormatBool(TLSAutoCert(true)),
+		"DEFAULT_REGISTRY":                            redactURL(Var("DEFAULT_REGISTRY")),
+		"INSECURE_REGISTRY":                           strconv.FormatBool(Var("INSECURE_REGISTRY") == "true"),
+		"PLAIN_HTTP_HOSTS":                            Var("PLAIN_HTTP_HOSTS"),
+		"REGISTRY_MIRROR":                             redactURL(Var("REGISTRY_MIRROR")),
+		"DOCKER_USERNAME":                             Var("DOCKER_USERNAME"),
+		"DOCKER_PASSWORD":                             redact(Var("DOCKER_PASSWORD")),
+		"DOCKER_HUB_USER":                             Var("DOCKER_HUB_USER"),
+		"DOCKER_HUB_PASSWORD":                         redact(Var("DOCKER_HUB_PASSWORD")),
+		"MODEL_RUNNER_MAX_MODEL_BYTES":                envOr("MODEL_RUNNER_MAX_MODEL_BYTES", "0"),
+		"MODEL_RUNNER_MAX_STORE_BYTES":                envOr("MODEL_RUNNER_MAX_STORE_BYTES", "0"),
+		"MODEL_RUNNER_MAX_LOAD_BYTES":                 envOr("MODEL_RUNNER_MAX_LOAD_BYTES", "0"),
+		"MODEL_RUNNER_MAX_INFLIGHT_REQUESTS":          envOr("MODEL_RUNNER_MAX_INFLIGHT_REQUESTS", "0"),
+		"MODEL_RUNNER_QUEUE_INFLIGHT_REQUESTS":        strconv.FormatBool(QueueInflightRequests()),
+		"MODEL_RUNNER_BLOB_COPY_BUFFER_SIZE":          envOr("MODEL_RUNNER_BLOB_COPY_BUFFER_SIZE", "0"),
+		"MODEL_RUNNER_ALLOW_MAX_MODEL_BYTES_OVERRIDE": strconv.FormatBool(AllowMaxModelBytesOverride()),
+		"MODEL_RUNNER_ALLOW_PRIVATE_LOAD_URLS":        strconv.FormatBool(AllowPrivateLoadURLs()),
+		"MODEL_RUNNER_REPAIR_STORE":                   strconv.FormatBool(RepairStore()),
+		"MODEL_RUNNER_PREFLIGHT_PULLS":                strconv.FormatBool(PreflightPulls()),
+		"MODEL_RUNNER_DOCKER_KEYCHAIN":                strconv.FormatBool(UseDockerKeychain(true)),
+	}
+	if path, err := ModelsPath(); err == nil {
+		config["MODELS_PATH"] = path
+	}
+	return config
+}
+
+// envOr returns the environment variable key, or fallback if it is unset.
+func envOr(key, fallback string) string {
+	if s := Var(key); s != "" {
+		return s
+	}
+	return fallback
+}
+
+// redact hides a credential, leaving unset ones empty so that it is still
+// visible whether they are set.
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+// secretArgWords are the words that mark a server argument as taking a
+// secret, such as llama-server's --api-key and --hf-token.
+var secretArgWords = []string{"key", "token", "password", "secret"}
+
+// isSecretArg reports whether arg is a flag taking a secret. Flags naming a
+// file that holds a secret, such as --ssl-key-file, are not.
+func isSecretArg(arg string) bool {
+	name, ok := strings.CutPrefix(arg, "-")
+	if !ok {
+		return false
+	}
+	name = strings.ToLower(strings.TrimLeft(name, "-"))
+	if name == "hft" {
+		return true
+	}
+	if strings.HasSuffix(name, "-file") || strings.HasSuffix(name, "-path") {
+		return false
+	}
+	for _, word := range secretArgWords {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactArgs hides the values of secret-bearing flags in a space-separated
+// argument list, given either as "--flag value" or "--flag=value". Every
+// argument up to the next flag is hidden, so that quoted values with spaces
+// are too.
+func redactArgs(s string) string {
+	args := strings.Fields(s)
+	inSecret := false
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			flag, _, hasValue := strings.Cut(arg, "=")
+			inSecret = isSecretArg(flag)
+			if inSecret && hasValue {
+				args[i] = flag + "=" + redacted
+			}
+			continue
+		}
+		if inSecret {
+			args[i] = redacted
+		}
+	}
+	return strings.Join(args, " ")
+}
+
+// redactURL hides the user info of a URL, or of a host name without a scheme
+// as registries are commonly given.
+func redactURL(s string) string {
+	scheme, rest := "", s
+	if i := strings.Index(s, "://"); i >= 0 {
+		scheme, rest = s[:i+len("://")], s[i+len("://"):]
+	}
+	authority := rest
+	if i := strings.IndexAny(rest, "/?#"); i >= 0 {
+		authority = rest[:i]
+	}
+	at := strings.LastIndex(authority, "@")
+	if at < 0 {
+		return s
+	}
+	return scheme + redacted + rest[at:]
+}
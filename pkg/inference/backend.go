@@ -261,6 +261,13 @@ type BackendConfiguration struct {
 	RuntimeFlags []string                   `json:"runtime-flags,omitempty"`
 	Speculative  *SpeculativeDecodingConfig `json:"speculative,omitempty"`
 	KeepAlive    *KeepAlive                 `json:"keep_alive,omitempty"`
+	// GPULayers is the number of model layers to offload to the GPU. A nil
+	// value leaves the backend's default (offload everything) in place.
+	GPULayers *int32 `json:"gpu-layers,omitempty"`
+	// ExtraArgs are appended verbatim to the end of the backend command line.
+	// Unlike RuntimeFlags they are not limited to an allowlist, only checked
+	// against the flags denied by ValidateExtraArgs.
+	ExtraArgs []string `json:"extra-args,omitempty"`
 
 	// Backend-specific configuration
 	VLLM     *VLLMConfig     `json:"vllm,omitempty"`
@@ -323,3 +330,12 @@ type Backend interface {
 	// GetDiskUsage returns the disk usage of the backend.
 	GetDiskUsage() (int64, error)
 }
+
+// ArchitectureReporter is implemented by backends that know which model
+// architectures they can run. Backends that don't implement it are assumed
+// to run any architecture of the formats they support.
+type ArchitectureReporter interface {
+	// SupportedArchitectures returns the model architectures, as recorded
+	// in model configs, that the backend can run.
+	SupportedArchitectures() []string
+}
@@ -274,6 +274,12 @@ func (d *diffusers) Uninstall() error {
 	return nil
 }
 
+// SupportedArchitectures implements inference.ArchitectureReporter. DDUF
+// models all record the same architecture.
+func (d *diffusers) SupportedArchitectures() []string {
+	return []string{"diffusers"}
+}
+
 func (d *diffusers) Status() string {
 	return d.status
 }
@@ -46,6 +46,14 @@ func (c *Config) GetArgs(bundle types.ModelBundle, socket string, mode inference
 	// Start with the arguments from LlamaCppConfig
 	args := append([]string{}, c.Args...)
 
+	// Override the number of layers offloaded to the GPU if requested
+	if config != nil && config.GPULayers != nil {
+		if err := validateGPULayers(*config.GPULayers, bundle.RuntimeConfig()); err != nil {
+			return nil, err
+		}
+		args = setArg(args, "-ngl", strconv.FormatInt(int64(*config.GPULayers), 10))
+	}
+
 	modelPath := bundle.GGUFPath()
 	if modelPath == "" {
 		return nil, fmt.Errorf("GGUF file required by llama.cpp backend")
@@ -91,6 +99,11 @@ func (c *Config) GetArgs(bundle types.ModelBundle, socket string, mode inference
 		args = append(args, "--jinja")
 	}
 
+	// Add extra arguments last
+	if config != nil {
+		args = append(args, config.ExtraArgs...)
+	}
+
 	return args, nil
 }
 
@@ -115,6 +128,48 @@ func GetReasoningBudget(backendCfg *inference.BackendConfiguration) *int32 {
 	return nil
 }
 
+// GetLayerCount returns the number of layers llama.cpp can offload for the
+// model, i.e. its repeating blocks plus the output layer, and false if the
+// model metadata does not report a block count.
+func GetLayerCount(modelCfg types.ModelConfig) (int32, bool) {
+	cfg, ok := modelCfg.(*types.Config)
+	if !ok || cfg == nil {
+		return 0, false
+	}
+	v, ok := cfg.GGUF[cfg.GGUF["general.architecture"]+".block_count"]
+	if !ok {
+		return 0, false
+	}
+	blocks, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || blocks <= 0 {
+		return 0, false
+	}
+	return int32(blocks) + 1, true
+}
+
+// validateGPULayers checks that gpuLayers is within the model's layer count.
+func validateGPULayers(gpuLayers int32, modelCfg types.ModelConfig) error {
+	if gpuLayers < 0 {
+		return fmt.Errorf("gpu-layers must not be negative, got %d", gpuLayers)
+	}
+	if layers, ok := GetLayerCount(modelCfg); ok && gpuLayers > layers {
+		return fmt.Errorf("gpu-layers must be between 0 and %d for this model, got %d", layers, gpuLayers)
+	}
+	return nil
+}
+
+// setArg sets the value of flag in args, appending the flag if it is not
+// already present.
+func setArg(args []string, flag, value string) []string {
+	for i, a := range args {
+		if a == flag && i+1 < len(args) {
+			args[i+1] = value
+			return args
+		}
+	}
+	return append(args, flag, value)
+}
+
 // containsArg checks if the given argument is already in the args slice.
 func containsArg(args []string, arg string) bool {
 	for _, a := range args {
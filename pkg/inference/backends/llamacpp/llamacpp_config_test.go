@@ -158,6 +158,22 @@ func TestGetArgs(t *testing.T) {
 				"--jinja",
 			),
 		},
+		{
+			name: "GPU layers from backend config",
+			mode: inference.BackendModeCompletion,
+			bundle: &fakeBundle{
+				ggufPath: modelPath,
+				config:   layeredModelConfig("32"),
+			},
+			config: &inference.BackendConfiguration{
+				GPULayers: int32ptr(20),
+			},
+			expected: append(append([]string{"-ngl", "20"}, baseArgs[2:]...),
+				"--model", modelPath,
+				"--host", socket,
+				"--jinja",
+			),
+		},
 		{
 			name: "0 context size from backend config ignored",
 			mode: inference.BackendModeEmbedding,
@@ -241,6 +257,24 @@ func TestGetArgs(t *testing.T) {
 				"--jinja",
 			),
 		},
+		{
+			name: "extra args appended last",
+			mode: inference.BackendModeCompletion,
+			bundle: &fakeBundle{
+				ggufPath: modelPath,
+			},
+			config: &inference.BackendConfiguration{
+				RuntimeFlags: []string{"--threads", "4"},
+				ExtraArgs:    []string{"--override-kv", "tokenizer.ggml.add_bos_token=bool:false"},
+			},
+			expected: append(slices.Clone(baseArgs),
+				"--model", modelPath,
+				"--host", socket,
+				"--threads", "4",
+				"--jinja",
+				"--override-kv", "tokenizer.ggml.add_bos_token=bool:false",
+			),
+		},
 		{
 			name: "multimodal projector removes jinja",
 			mode: inference.BackendModeCompletion,
@@ -382,6 +416,52 @@ func TestGetArgs(t *testing.T) {
 	}
 }
 
+func TestGetArgsGPULayers(t *testing.T) {
+	config := NewDefaultLlamaCppConfig()
+
+	tests := []struct {
+		name        string
+		modelConfig *types.Config
+		gpuLayers   int32
+		expectedNGL string
+		expectError bool
+	}{
+		{name: "no layers offloaded", modelConfig: layeredModelConfig("32"), gpuLayers: 0, expectedNGL: "0"},
+		{name: "all layers including output", modelConfig: layeredModelConfig("32"), gpuLayers: 33, expectedNGL: "33"},
+		{name: "more layers than the model has", modelConfig: layeredModelConfig("32"), gpuLayers: 34, expectError: true},
+		{name: "negative", modelConfig: layeredModelConfig("32"), gpuLayers: -1, expectError: true},
+		{name: "unknown layer count", gpuLayers: 500, expectedNGL: "500"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle := &fakeBundle{ggufPath: "/path/to/model", config: tt.modelConfig}
+			args, err := config.GetArgs(bundle, "unix:///tmp/socket", inference.BackendModeCompletion, &inference.BackendConfiguration{
+				GPULayers: int32ptr(tt.gpuLayers),
+			})
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %d GPU layers, got args %v", tt.gpuLayers, args)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetArgs() error = %v", err)
+			}
+			idx := slices.Index(args, "-ngl")
+			if idx == -1 || idx+1 >= len(args) {
+				t.Fatalf("Expected -ngl argument with a value, got %v", args)
+			}
+			if args[idx+1] != tt.expectedNGL {
+				t.Errorf("Expected -ngl value %s, got %s", tt.expectedNGL, args[idx+1])
+			}
+			if slices.Index(args[idx+1:], "-ngl") != -1 {
+				t.Errorf("Expected a single -ngl argument, got %v", args)
+			}
+		})
+	}
+}
+
 func TestContainsArg(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -459,6 +539,17 @@ func (f *fakeBundle) RuntimeConfig() types.ModelConfig {
 	return f.config
 }
 
+// layeredModelConfig returns a GGUF model config reporting the given block count.
+func layeredModelConfig(blockCount string) *types.Config {
+	return &types.Config{
+		Format: types.FormatGGUF,
+		GGUF: map[string]string{
+			"general.architecture": "llama",
+			"llama.block_count":    blockCount,
+		},
+	}
+}
+
 func int32ptr(n int32) *int32 {
 	return &n
 }
@@ -0,0 +1,40 @@
+package mlx
+
+import (
+	"slices"
+
+	"github.com/docker/model-runner/pkg/inference"
+)
+
+var _ inference.ArchitectureReporter = (*mlx)(nil)
+
+// supportedArchitectures lists the Hugging Face architectures, as recorded in
+// the architectures field of a model's config.json, that mlx-lm can load.
+var supportedArchitectures = []string{
+	"ApertusForCausalLM",
+	"BaichuanForCausalLM", "BailingMoeForCausalLM", "BitNetForCausalLM",
+	"Cohere2ForCausalLM", "CohereForCausalLM",
+	"DbrxForCausalLM", "DeepseekForCausalLM", "DeepseekV2ForCausalLM", "DeepseekV3ForCausalLM", "Dots1ForCausalLM",
+	"Ernie4_5ForCausalLM", "Ernie4_5_MoeForCausalLM", "Exaone4ForCausalLM", "ExaoneForCausalLM",
+	"FalconH1ForCausalLM",
+	"Gemma2ForCausalLM", "Gemma3ForCausalLM", "Gemma3ForConditionalGeneration", "Gemma3nForConditionalGeneration", "GemmaForCausalLM",
+	"Glm4ForCausalLM", "Glm4MoeForCausalLM",
+	"GPT2LMHeadModel", "GPTBigCodeForCausalLM", "GPTNeoXForCausalLM", "GptOssForCausalLM",
+	"GraniteForCausalLM", "GraniteMoeForCausalLM", "GraniteMoeHybridForCausalLM",
+	"HunYuanMoEV1ForCausalLM",
+	"InternLM2ForCausalLM", "InternLM3ForCausalLM",
+	"JambaForCausalLM",
+	"Lfm2ForCausalLM", "Llama4ForConditionalGeneration", "LlamaForCausalLM",
+	"Mamba2ForCausalLM", "MambaForCausalLM", "MiniCPM3ForCausalLM", "MiniCPMForCausalLM", "MiniMaxM2ForCausalLM",
+	"Mistral3ForConditionalGeneration", "MistralForCausalLM", "MixtralForCausalLM",
+	"NemotronForCausalLM",
+	"Olmo2ForCausalLM", "OlmoeForCausalLM", "OlmoForCausalLM", "OpenELMForCausalLM",
+	"Phi3ForCausalLM", "PhiForCausalLM", "PhiMoEForCausalLM", "Plamo2ForCausalLM",
+	"Qwen2ForCausalLM", "Qwen2MoeForCausalLM", "Qwen3ForCausalLM", "Qwen3MoeForCausalLM", "Qwen3NextForCausalLM",
+	"SeedOssForCausalLM", "SmolLM3ForCausalLM", "StableLmForCausalLM", "Starcoder2ForCausalLM",
+}
+
+// SupportedArchitectures implements inference.ArchitectureReporter.
+func (m *mlx) SupportedArchitectures() []string {
+	return slices.Clone(supportedArchitectures)
+}
@@ -9,6 +9,7 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/docker/model-runner/pkg/internal/utils"
@@ -33,6 +34,9 @@ type RunnerConfig struct {
 	SandboxPath string
 	// SandboxConfig is the sandbox configuration string
 	SandboxConfig string
+	// SandboxPolicy, if set, replaces SandboxConfig with the policy rendered
+	// for the platform, allowing the process to listen on Socket.
+	SandboxPolicy *sandbox.Policy
 	// Args are the command line arguments
 	Args []string
 	// Logger provides logging functionality
@@ -75,10 +79,17 @@ func RunBackend(ctx context.Context, config RunnerConfig) error {
 	tailBuf := tailbuffer.NewTailBuffer(1024)
 	out := io.MultiWriter(config.ServerLogWriter, tailBuf)
 
+	sandboxConfig := config.SandboxConfig
+	if config.SandboxPolicy != nil {
+		policy := *config.SandboxPolicy
+		policy.Sockets = append(slices.Clip(policy.Sockets), config.Socket)
+		sandboxConfig = policy.Configuration()
+	}
+
 	// Create sandbox with process cancellation
 	backendSandbox, err := sandbox.Create(
 		ctx,
-		config.SandboxConfig,
+		sandboxConfig,
 		func(command *exec.Cmd) {
 			command.Cancel = func() error {
 				if runtime.GOOS == "windows" {
@@ -0,0 +1,61 @@
+package vllm
+
+import (
+	"slices"
+
+	"github.com/docker/model-runner/pkg/inference"
+)
+
+var (
+	_ inference.ArchitectureReporter = (*vLLM)(nil)
+	_ inference.ArchitectureReporter = (*vllmMetal)(nil)
+)
+
+// supportedArchitectures lists the Hugging Face architectures, as recorded in
+// the architectures field of a model's config.json, that vLLM can serve.
+// vllm-metal follows the same model registry.
+var supportedArchitectures = []string{
+	"AquilaForCausalLM", "ArceeForCausalLM", "ArcticForCausalLM",
+	"BaiChuanForCausalLM", "BaichuanForCausalLM", "BailingMoeForCausalLM", "BambaForCausalLM",
+	"BertModel", "BloomForCausalLM",
+	"ChatGLMForConditionalGeneration", "ChatGLMModel", "Cohere2ForCausalLM", "CohereForCausalLM",
+	"DbrxForCausalLM", "DeepseekForCausalLM", "DeepseekV2ForCausalLM", "DeepseekV3ForCausalLM", "Dots1ForCausalLM",
+	"Ernie4_5ForCausalLM", "Ernie4_5_MoeForCausalLM", "Exaone4ForCausalLM", "ExaoneForCausalLM",
+	"FalconForCausalLM", "FalconH1ForCausalLM",
+	"Gemma2ForCausalLM", "Gemma3ForCausalLM", "Gemma3ForConditionalGeneration", "Gemma3nForConditionalGeneration", "GemmaForCausalLM",
+	"Glm4ForCausalLM", "Glm4MoeForCausalLM", "GlmForCausalLM",
+	"GPT2LMHeadModel", "GPTBigCodeForCausalLM", "GPTJForCausalLM", "GPTNeoXForCausalLM", "GptOssForCausalLM",
+	"GraniteForCausalLM", "GraniteMoeForCausalLM", "GraniteMoeHybridForCausalLM",
+	"HunYuanDenseV1ForCausalLM", "HunYuanMoEV1ForCausalLM",
+	"InternLM2ForCausalLM", "InternLM3ForCausalLM", "InternLMForCausalLM",
+	"JambaForCausalLM",
+	"KimiVLForConditionalGeneration",
+	"Lfm2ForCausalLM", "Llama4ForConditionalGeneration", "LlamaForCausalLM",
+	"LlavaForConditionalGeneration", "LlavaNextForConditionalGeneration",
+	"Mamba2ForCausalLM", "MambaForCausalLM", "MiniCPM3ForCausalLM", "MiniCPMForCausalLM", "MiniCPMV",
+	"MiniMaxM1ForCausalLM", "MiniMaxText01ForCausalLM",
+	"Mistral3ForConditionalGeneration", "MistralForCausalLM", "MixtralForCausalLM", "MllamaForConditionalGeneration", "MPTForCausalLM",
+	"NemotronForCausalLM", "NemotronHForCausalLM",
+	"Olmo2ForCausalLM", "OlmoeForCausalLM", "OlmoForCausalLM", "OPTForCausalLM", "OrionForCausalLM",
+	"PersimmonForCausalLM", "Phi3ForCausalLM", "Phi3SmallForCausalLM", "Phi4MMForCausalLM", "PhiForCausalLM", "PhiMoEForCausalLM",
+	"PixtralForConditionalGeneration", "Plamo2ForCausalLM",
+	"Qwen2_5_VLForConditionalGeneration", "Qwen2ForCausalLM", "Qwen2MoeForCausalLM", "Qwen2VLForConditionalGeneration",
+	"Qwen3ForCausalLM", "Qwen3MoeForCausalLM", "Qwen3NextForCausalLM", "Qwen3VLForConditionalGeneration", "Qwen3VLMoeForConditionalGeneration",
+	"QWenLMHeadModel",
+	"RobertaModel",
+	"SeedOssForCausalLM", "SmolLM3ForCausalLM", "SolarForCausalLM", "StableLmForCausalLM", "Starcoder2ForCausalLM",
+	"TeleChat2ForCausalLM",
+	"WhisperForConditionalGeneration",
+	"XLMRobertaModel", "XverseForCausalLM",
+	"Zamba2ForCausalLM",
+}
+
+// SupportedArchitectures implements inference.ArchitectureReporter.
+func (v *vLLM) SupportedArchitectures() []string {
+	return slices.Clone(supportedArchitectures)
+}
+
+// SupportedArchitectures implements inference.ArchitectureReporter.
+func (v *vllmMetal) SupportedArchitectures() []string {
+	return slices.Clone(supportedArchitectures)
+}
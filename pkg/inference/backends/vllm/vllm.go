@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/docker/model-runner/pkg/diskusage"
@@ -18,6 +19,7 @@ import (
 	"github.com/docker/model-runner/pkg/inference/models"
 	"github.com/docker/model-runner/pkg/inference/platform"
 	"github.com/docker/model-runner/pkg/logging"
+	"github.com/docker/model-runner/pkg/sandbox"
 )
 
 const (
@@ -42,6 +44,8 @@ type vLLM struct {
 	status string
 	// customBinaryPath is an optional custom path to the vllm binary.
 	customBinaryPath string
+	// sandboxPolicy, if set, replaces the default sandbox policy.
+	sandboxPolicy *sandbox.Policy
 }
 
 // Options holds the configuration for the unified vLLM backend constructor.
@@ -49,6 +53,10 @@ type Options struct {
 	Config          *Config // Linux-only: extra vllm args (nil = defaults)
 	LinuxBinaryPath string  // Linux: custom vllm binary path
 	MetalPythonPath string  // macOS ARM64: custom python path
+	// SandboxPolicy replaces the default sandbox policy of the Linux vLLM
+	// processes (nil = defaults). The models served and the runner socket
+	// are always allowed. Policies are enforced with Landlock.
+	SandboxPolicy *sandbox.Policy
 }
 
 // New creates the appropriate vLLM backend for the current platform.
@@ -59,7 +67,7 @@ func New(log logging.Logger, modelManager *models.Manager, serverLog logging.Log
 	if platform.SupportsVLLMMetal() {
 		return newMetal(log, modelManager, serverLog, opts.MetalPythonPath)
 	}
-	return newLinux(log, modelManager, serverLog, opts.Config, opts.LinuxBinaryPath)
+	return newLinux(log, modelManager, serverLog, opts)
 }
 
 // NeedsDeferredInstall reports whether vllm on the current platform
@@ -69,9 +77,10 @@ func NeedsDeferredInstall() bool {
 }
 
 // newLinux creates a new Linux vLLM-based backend.
-// customBinaryPath is an optional path to a custom vllm binary; if empty, the default path is used.
-func newLinux(log logging.Logger, modelManager *models.Manager, serverLog logging.Logger, conf *Config, customBinaryPath string) (inference.Backend, error) {
+// opts.LinuxBinaryPath is an optional path to a custom vllm binary; if empty, the default path is used.
+func newLinux(log logging.Logger, modelManager *models.Manager, serverLog logging.Logger, opts Options) (inference.Backend, error) {
 	// If no config is provided, use the default configuration
+	conf := opts.Config
 	if conf == nil {
 		conf = NewDefaultVLLMConfig()
 	}
@@ -82,7 +91,8 @@ func newLinux(log logging.Logger, modelManager *models.Manager, serverLog loggin
 		serverLog:        serverLog,
 		config:           conf,
 		status:           inference.FormatNotInstalled(""),
-		customBinaryPath: customBinaryPath,
+		customBinaryPath: opts.LinuxBinaryPath,
+		sandboxPolicy:    opts.SandboxPolicy,
 	}, nil
 }
 
@@ -180,7 +190,7 @@ func (v *vLLM) Run(ctx context.Context, socket, model string, modelRef string, m
 		Socket:          socket,
 		BinaryPath:      v.binaryPath(),
 		SandboxPath:     vllmDir,
-		SandboxConfig:   "",
+		SandboxPolicy:   v.runSandboxPolicy(bundle, draftBundle),
 		Args:            args,
 		Logger:          v.log,
 		ServerLogWriter: logging.NewWriter(v.serverLog),
@@ -210,3 +220,38 @@ func (v *vLLM) binaryPath() string {
 	}
 	return filepath.Join(vllmDir, "vllm")
 }
+
+// runSandboxPolicy returns the sandbox policy of a vLLM process serving the
+// given bundles, which may be nil: the configured policy, or the default one,
+// with read access to the bundles added.
+func (v *vLLM) runSandboxPolicy(bundles ...types.ModelBundle) *sandbox.Policy {
+	policy := v.defaultSandboxPolicy()
+	if v.sandboxPolicy != nil {
+		policy = *v.sandboxPolicy
+	}
+	policy.ReadPaths = slices.Clip(policy.ReadPaths)
+	for _, bundle := range bundles {
+		if bundle != nil {
+			policy.ReadPaths = append(policy.ReadPaths, bundle.RootDir())
+		}
+	}
+	return &policy
+}
+
+// defaultSandboxPolicy returns the default sandbox policy of vLLM processes,
+// which may read the vLLM environment and write their caches and temporary
+// files.
+func (v *vLLM) defaultSandboxPolicy() sandbox.Policy {
+	policy := sandbox.Policy{
+		// The binary lives in the bin directory of its environment.
+		ReadPaths:  []string{filepath.Dir(filepath.Dir(v.binaryPath()))},
+		WritePaths: []string{os.TempDir()},
+		// vLLM's engine and workers coordinate over loopback TCP, which
+		// Landlock can't tell apart from other TCP connections.
+		Network: true,
+	}
+	if cacheDir, err := os.UserCacheDir(); err == nil {
+		policy.WritePaths = append(policy.WritePaths, cacheDir)
+	}
+	return policy
+}
@@ -53,6 +53,11 @@ func (c *Config) GetArgs(bundle types.ModelBundle, socket string, mode inference
 		return nil, fmt.Errorf("unsupported backend mode %q", mode)
 	}
 
+	// vLLM places the whole model on the GPU and has no per-layer offloading
+	if config != nil && config.GPULayers != nil {
+		return nil, fmt.Errorf("gpu-layers is not supported by the vLLM backend, use gpu-memory-utilization instead")
+	}
+
 	// Add max-model-len if specified in model config or backend config
 	if maxLen := GetMaxModelLen(bundle.RuntimeConfig(), config); maxLen != nil {
 		args = append(args, "--max-model-len", strconv.FormatInt(int64(*maxLen), 10))
@@ -84,6 +89,11 @@ func (c *Config) GetArgs(bundle types.ModelBundle, socket string, mode inference
 		}
 	}
 
+	// Add extra arguments last
+	if config != nil {
+		args = append(args, config.ExtraArgs...)
+	}
+
 	return args, nil
 }
 
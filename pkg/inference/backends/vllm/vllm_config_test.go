@@ -108,6 +108,28 @@ func TestGetArgs(t *testing.T) {
 				"0.9",
 			},
 		},
+		{
+			name: "with extra args",
+			bundle: &mockModelBundle{
+				safetensorsPath: "/path/to/model",
+			},
+			config: &inference.BackendConfiguration{
+				ExtraArgs: []string{"--max-num-partial-prefills", "2"},
+				VLLM: &inference.VLLMConfig{
+					GPUMemoryUtilization: float64ptr(0.5),
+				},
+			},
+			expected: []string{
+				"serve",
+				"/path/to",
+				"--uds",
+				"/tmp/socket",
+				"--gpu-memory-utilization",
+				"0.5",
+				"--max-num-partial-prefills",
+				"2",
+			},
+		},
 		{
 			name: "with model context size (takes precedence)",
 			bundle: &mockModelBundle{
@@ -309,6 +331,16 @@ func TestGetArgs(t *testing.T) {
 			},
 			expectError: true,
 		},
+		{
+			name: "with GPU layers (unsupported)",
+			bundle: &mockModelBundle{
+				safetensorsPath: "/path/to/model",
+			},
+			config: &inference.BackendConfiguration{
+				GPULayers: int32ptr(10),
+			},
+			expectError: true,
+		},
 		{
 			name: "with GPU memory utilization and other parameters",
 			bundle: &mockModelBundle{
@@ -275,6 +275,11 @@ func (v *vllmMetal) buildArgs(bundle interface{ SafetensorsPath() string }, sock
 		args = append(args, config.RuntimeFlags...)
 	}
 
+	// Add extra arguments last
+	if config != nil {
+		args = append(args, config.ExtraArgs...)
+	}
+
 	return args, nil
 }
 
@@ -0,0 +1,63 @@
+package vllm
+
+import (
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/sandbox"
+)
+
+func TestRunSandboxPolicy(t *testing.T) {
+	bundle := &mockModelBundle{safetensorsPath: "/path/to/bundle/model.safetensors"}
+
+	t.Run("default", func(t *testing.T) {
+		v := &vLLM{}
+		policy := v.runSandboxPolicy(bundle, nil)
+
+		if want := []string{"/opt/vllm-env", "/path/to/bundle"}; !slices.Equal(policy.ReadPaths, want) {
+			t.Errorf("Expected read paths %v, got %v", want, policy.ReadPaths)
+		}
+		if !slices.Contains(policy.WritePaths, os.TempDir()) {
+			t.Errorf("Expected write paths to include %s, got %v", os.TempDir(), policy.WritePaths)
+		}
+		for _, path := range policy.WritePaths {
+			if path == "/" || path == "/path/to/bundle" || path == "/opt/vllm-env" {
+				t.Errorf("Expected %s not to be writable", path)
+			}
+		}
+		if !policy.Network {
+			t.Error("Expected network access to be allowed")
+		}
+	})
+
+	t.Run("custom binary", func(t *testing.T) {
+		v := &vLLM{customBinaryPath: "/srv/vllm/bin/vllm"}
+		policy := v.runSandboxPolicy(bundle)
+		if want := []string{"/srv/vllm", "/path/to/bundle"}; !slices.Equal(policy.ReadPaths, want) {
+			t.Errorf("Expected read paths %v, got %v", want, policy.ReadPaths)
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		override := &sandbox.Policy{
+			ReadPaths:  []string{"/srv/models"},
+			WritePaths: []string{"/srv/cache"},
+		}
+		v := &vLLM{sandboxPolicy: override}
+		policy := v.runSandboxPolicy(bundle)
+
+		if want := []string{"/srv/models", "/path/to/bundle"}; !slices.Equal(policy.ReadPaths, want) {
+			t.Errorf("Expected read paths %v, got %v", want, policy.ReadPaths)
+		}
+		if want := []string{"/srv/cache"}; !slices.Equal(policy.WritePaths, want) {
+			t.Errorf("Expected write paths %v, got %v", want, policy.WritePaths)
+		}
+		if policy.Network {
+			t.Error("Expected the override to deny network access")
+		}
+		if len(override.ReadPaths) != 1 {
+			t.Errorf("Expected the override to be left unchanged, got %v", override.ReadPaths)
+		}
+	})
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"fmt"
 
+	"github.com/docker/model-runner/pkg/distribution/oci"
 	"github.com/docker/model-runner/pkg/distribution/types"
 )
 
@@ -27,11 +28,19 @@ func ToModel(m types.Model) (*Model, error) {
 		created = desc.Created.Unix()
 	}
 
+	manifest, err := manifestOf(m)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Model{
-		ID:      id,
-		Tags:    m.Tags(),
-		Created: created,
-		Config:  cfg,
+		ID:          id,
+		Tags:        m.Tags(),
+		Pins:        pinsOf(m),
+		Created:     created,
+		Config:      cfg,
+		Annotations: manifestAnnotations(manifest),
+		Layers:      layerInfos(manifest),
 	}, nil
 }
 
@@ -58,10 +67,99 @@ func ToModelFromArtifact(artifact types.ModelArtifact) (*Model, error) {
 		created = desc.Created.Unix()
 	}
 
+	manifest, err := manifestOf(artifact)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Model{
-		ID:      id,
-		Tags:    nil, // Remote models don't have local tags
-		Created: created,
-		Config:  cfg,
+		ID:          id,
+		Tags:        nil, // Remote models don't have local tags
+		Created:     created,
+		Config:      cfg,
+		Annotations: manifestAnnotations(manifest),
+		Layers:      layerInfos(manifest),
 	}, nil
 }
+
+// ToModelFromIndex converts a remote image index, identified by its digest, to
+// the API Model representation, summarizing the variants it lists.
+func ToModelFromIndex(index *oci.IndexManifest, digest oci.Hash) *Model {
+	summary := &ModelIndex{
+		MediaType: string(index.MediaType),
+		Variants:  make([]ModelVariant, 0, len(index.Manifests)),
+	}
+	for _, desc := range index.Manifests {
+		variant := ModelVariant{
+			Digest:      desc.Digest.String(),
+			MediaType:   string(desc.MediaType),
+			Size:        desc.Size,
+			Annotations: desc.Annotations,
+		}
+		if p := desc.Platform; p != nil {
+			variant.Platform = p.OS + "/" + p.Architecture
+			if p.Variant != "" {
+				variant.Platform += "/" + p.Variant
+			}
+		}
+		summary.Variants = append(summary.Variants, variant)
+	}
+	return &Model{
+		ID:          digest.String(),
+		Annotations: index.Annotations,
+		Index:       summary,
+	}
+}
+
+// manifestOf returns the manifest of m, or nil if m does not expose its
+// manifest.
+func manifestOf(m any) (*oci.Manifest, error) {
+	withManifest, ok := m.(interface {
+		Manifest() (*oci.Manifest, error)
+	})
+	if !ok {
+		return nil, nil
+	}
+	manifest, err := withManifest.Manifest()
+	if err != nil {
+		return nil, fmt.Errorf("get manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// pinsOf returns the pinned tags of m, or nil if m does not record them.
+func pinsOf(m any) map[string]string {
+	withPins, ok := m.(interface {
+		Pins() map[string]string
+	})
+	if !ok {
+		return nil
+	}
+	return withPins.Pins()
+}
+
+// manifestAnnotations returns the manifest-level annotations of manifest,
+// which may be nil.
+func manifestAnnotations(manifest *oci.Manifest) map[string]string {
+	if manifest == nil {
+		return nil
+	}
+	return manifest.Annotations
+}
+
+// layerInfos describes the layers of manifest, which may be nil.
+func layerInfos(manifest *oci.Manifest) []LayerInfo {
+	if manifest == nil {
+		return nil
+	}
+	layers := make([]LayerInfo, 0, len(manifest.Layers))
+	for _, layer := range manifest.Layers {
+		layers = append(layers, LayerInfo{
+			Digest:    layer.Digest.String(),
+			MediaType: string(layer.MediaType),
+			Size:      layer.Size,
+			Filepath:  layer.Annotations[types.AnnotationFilePath],
+		})
+	}
+	return layers
+}
@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/docker/model-runner/pkg/distribution/types"
 )
@@ -18,6 +21,18 @@ type ModelCreateRequest struct {
 	From string `json:"from"`
 	// BearerToken is an optional bearer token for authentication.
 	BearerToken string `json:"bearer-token,omitempty"`
+	// Force re-downloads the model even if the local copy is up to date.
+	Force bool `json:"force,omitempty"`
+	// MaxModelBytes overrides the configured maximum model size for this
+	// pull, where zero means no limit. It is only honored when the manager
+	// allows overrides.
+	MaxModelBytes *int64 `json:"max-model-bytes,omitempty"`
+	// As optionally tags the pulled model with this reference once the pull
+	// succeeds, e.g. to give a model pulled by digest a short alias.
+	As string `json:"as,omitempty"`
+	// RawReference passes From to the registry verbatim instead of
+	// normalizing it, for registries with unusual repository layouts.
+	RawReference bool `json:"raw-reference,omitempty"`
 }
 
 // ModelPushRequest represents a model push request. It mirrors ModelCreateRequest
@@ -25,6 +40,42 @@ type ModelCreateRequest struct {
 type ModelPushRequest struct {
 	// BearerToken is an optional bearer token for authentication.
 	BearerToken string `json:"bearer-token,omitempty"`
+	// Compression optionally selects the layer compression used on push.
+	// Supported values are "" (uncompressed) and "zstd".
+	Compression string `json:"compression,omitempty"`
+}
+
+// ModelTagResponse is the response to a tag request.
+type ModelTagResponse struct {
+	// Message describes the result for display.
+	Message string `json:"message"`
+	// SourceID is the ID of the model the source reference resolved to.
+	SourceID string `json:"source_id"`
+	// Target is the reference the model was tagged with.
+	Target string `json:"target"`
+}
+
+// ModelDropLayerResponse is the response to a drop layer request.
+type ModelDropLayerResponse struct {
+	// Message describes the result for display.
+	Message string `json:"message"`
+	// ID is the ID of the rewritten model.
+	ID string `json:"id"`
+	// MediaType is the media type of the dropped layers.
+	MediaType string `json:"media_type"`
+}
+
+// ModelUpdateCheck is the response to an update check, comparing a local
+// model with the model its tag currently points to in the registry.
+type ModelUpdateCheck struct {
+	// UpdateAvailable reports whether the tag points to another model than the
+	// local one. Models whose manifest was rewritten when stored, as for
+	// compressed layers, are compared by the digest they were pulled with.
+	UpdateAvailable bool `json:"update_available"`
+	// LocalDigest is the manifest digest of the local model.
+	LocalDigest string `json:"local_digest"`
+	// RemoteDigest is the manifest digest the tag points to in the registry.
+	RemoteDigest string `json:"remote_digest"`
 }
 
 // SimpleModel is a wrapper that allows creating a model with modified configuration
@@ -42,7 +93,8 @@ func (s *SimpleModel) Descriptor() (types.Descriptor, error) {
 	return s.DescriptorValue, nil
 }
 
-// ToOpenAIList converts the model list to its OpenAI API representation. This function never
+// ToOpenAIList converts the model list to its OpenAI API representation, sorted
+// by model ID so that the order is stable across calls. This function never
 // returns a nil slice (though it may return an empty slice).
 func ToOpenAIList(l []types.Model) (*OpenAIModelList, error) {
 	// Convert the constituent models.
@@ -54,6 +106,9 @@ func ToOpenAIList(l []types.Model) (*OpenAIModelList, error) {
 		}
 		models[i] = openAI
 	}
+	slices.SortStableFunc(models, func(a, b *OpenAIModel) int {
+		return strings.Compare(a.ID, b.ID)
+	})
 
 	// Create the OpenAI model list.
 	return &OpenAIModelList{
@@ -86,7 +141,7 @@ func ToOpenAI(m types.Model) (*OpenAIModel, error) {
 		ID:      id,
 		Object:  "model",
 		Created: created,
-		OwnedBy: "docker",
+		OwnedBy: modelOwner(id),
 	}
 
 	config, err := m.Config()
@@ -116,6 +171,25 @@ type DMRMetadata struct {
 	Size          string `json:"size,omitempty"`
 }
 
+// modelOwner returns the organization of a model reference: the path
+// component before the repository name, ignoring any registry host. It falls
+// back to "docker" for references without one, such as bare IDs.
+func modelOwner(ref string) string {
+	if i := strings.Index(ref, "@"); i != -1 {
+		ref = ref[:i]
+	}
+	parts := strings.Split(ref, "/")
+	if len(parts) < 2 {
+		return "docker"
+	}
+	owner := parts[len(parts)-2]
+	if len(parts) == 2 && (strings.ContainsAny(owner, ".:") || owner == "localhost") {
+		// A registry host followed directly by the repository name.
+		return "docker"
+	}
+	return owner
+}
+
 // OpenAIModel represents a locally stored model using OpenAI conventions.
 type OpenAIModel struct {
 	// ID is the model tag.
@@ -124,7 +198,8 @@ type OpenAIModel struct {
 	Object string `json:"object"`
 	// Created is the Unix epoch timestamp corresponding to the model creation.
 	Created int64 `json:"created"`
-	// OwnedBy is the model owner. At the moment, it is always "docker".
+	// OwnedBy is the organization in the model's reference, such as "ai" for
+	// ai/smollm2, or "docker" if the reference has no organization.
 	OwnedBy string `json:"owned_by"`
 	// DMR contains Docker Model Runner-specific metadata.
 	DMR *DMRMetadata `json:"dmr,omitempty"`
@@ -143,11 +218,58 @@ type Model struct {
 	ID string `json:"id"`
 	// Tags are the list of tags associated with the model.
 	Tags []string `json:"tags,omitempty"`
-	// Created is the Unix epoch timestamp corresponding to the model creation.
-	Created int64 `json:"created"`
+	// Pins maps the tags that were pinned to this model when they were
+	// created to the references they were pinned from.
+	Pins map[string]string `json:"pins,omitempty"`
+	// Created is the Unix epoch timestamp corresponding to the model creation,
+	// taken from the model's descriptor.
+	Created int64 `json:"created,omitempty"`
+	// LastUsed is the Unix epoch timestamp corresponding to the last time the
+	// model was pulled or targeted by an inference request, if known.
+	LastUsed int64 `json:"last_used,omitempty"`
 	// Config describes the model. Can be either Docker format (*types.Config)
 	// or ModelPack format (*modelpack.Model).
 	Config types.ModelConfig `json:"config"`
+	// Annotations are the manifest-level annotations of the model, such as
+	// its source or license.
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Layers describes the files the model bundles, such as weights,
+	// multimodal projectors, chat templates and licenses, in manifest order.
+	Layers []LayerInfo `json:"layers,omitempty"`
+	// Index is set for remote models published as an image index, such as a
+	// multi-platform manifest list, rather than a single model. Config is
+	// nil for them.
+	Index *ModelIndex `json:"index,omitempty"`
+}
+
+// LayerInfo describes a layer of a model.
+type LayerInfo struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	// Filepath is the path of the file within the model, if the layer
+	// records one.
+	Filepath string `json:"filepath,omitempty"`
+}
+
+// ModelIndex summarizes the variants of a model published as an image index.
+type ModelIndex struct {
+	// MediaType is the media type of the index.
+	MediaType string `json:"media_type"`
+	// Variants are the manifests the index lists.
+	Variants []ModelVariant `json:"variants"`
+}
+
+// ModelVariant is a manifest listed by an image index.
+type ModelVariant struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+	// Platform is the platform of the variant, such as linux/arm64, if the
+	// index records one.
+	Platform string `json:"platform,omitempty"`
+	// Annotations are the annotations of the variant in the index.
+	Annotations map[string]string `json:"annotations,omitempty"`
 }
 
 // UnmarshalJSON implements custom JSON unmarshaling for Model.
@@ -181,3 +303,49 @@ func (m *Model) UnmarshalJSON(data []byte) error {
 
 	return nil
 }
+
+// Concise returns a copy of m without the full per-format metadata maps, which
+// can be large, or the descriptor's creation time. Summary fields are kept, and
+// the context size and chat template are filled in from the GGUF metadata when
+// the config does not set them.
+func (m *Model) Concise() *Model {
+	cfg, ok := m.Config.(*types.Config)
+	if !ok {
+		return m
+	}
+	concise := *cfg
+	if concise.ContextSize == nil {
+		if contextSize, ok := trainedContextSize(cfg); ok {
+			concise.ContextSize = &contextSize
+		}
+	}
+	if concise.ChatTemplate == "" {
+		concise.ChatTemplate = cfg.GGUF[types.GGUFChatTemplateKey]
+	}
+	concise.GGUF = nil
+	concise.Safetensors = nil
+	concise.Diffusers = nil
+
+	model := *m
+	model.Config = &concise
+	model.Created = 0
+	return &model
+}
+
+// trainedContextSize returns the context length the model was trained with,
+// as reported by its GGUF metadata.
+func trainedContextSize(config types.ModelConfig) (int32, bool) {
+	cfg, ok := config.(*types.Config)
+	if !ok || cfg == nil {
+		return 0, false
+	}
+	v, ok := cfg.GGUF[cfg.GGUF["general.architecture"]+".context_length"]
+	if !ok {
+		return 0, false
+	}
+	parsed, err := strconv.ParseInt(v, 10, 32)
+	if err != nil || parsed <= 0 {
+		return 0, false
+	}
+	return int32(parsed), true
+}
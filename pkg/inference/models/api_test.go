@@ -2,7 +2,10 @@ package models
 
 import (
 	"encoding/json"
+	"log/slog"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/stretchr/testify/assert"
@@ -351,7 +354,7 @@ func TestToOpenAIWithFullConfig(t *testing.T) {
 
 	assert.Equal(t, "ai/smollm2:latest", result.ID)
 	assert.Equal(t, "model", result.Object)
-	assert.Equal(t, "docker", result.OwnedBy)
+	assert.Equal(t, "ai", result.OwnedBy)
 
 	require.NotNil(t, result.DMR)
 	require.NotNil(t, result.DMR.ContextWindow)
@@ -374,7 +377,7 @@ func TestToOpenAIWithNilConfig(t *testing.T) {
 
 	assert.Equal(t, "ai/model:latest", result.ID)
 	assert.Equal(t, "model", result.Object)
-	assert.Equal(t, "docker", result.OwnedBy)
+	assert.Equal(t, "ai", result.OwnedBy)
 	assert.Nil(t, result.DMR)
 }
 
@@ -478,7 +481,132 @@ func TestToOpenAIList(t *testing.T) {
 	assert.Nil(t, result.Data[1].DMR)
 }
 
+func TestToOpenAIListOrderAndMetadata(t *testing.T) {
+	created1 := time.Date(2025, 1, 15, 10, 30, 0, 0, time.UTC)
+	created2 := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	models := []types.Model{
+		&mockModel{id: "sha256:ccc", tags: []string{"hf.co/bartowski/model3:Q4_K_M"}, desc: types.Descriptor{Created: &created2}},
+		&mockModel{id: "sha256:aaa", tags: []string{"ai/model1:latest"}, desc: types.Descriptor{Created: &created1}},
+		&mockModel{id: "sha256:ddd"},
+		&mockModel{id: "sha256:bbb", tags: []string{"docker.io/myorg/model2:v1"}},
+	}
+
+	expected := []struct {
+		id      string
+		created int64
+		ownedBy string
+	}{
+		{id: "ai/model1:latest", created: created1.Unix(), ownedBy: "ai"},
+		{id: "docker.io/myorg/model2:v1", created: 0, ownedBy: "myorg"},
+		{id: "hf.co/bartowski/model3:Q4_K_M", created: created2.Unix(), ownedBy: "bartowski"},
+		{id: "sha256:ddd", created: 0, ownedBy: "docker"},
+	}
+
+	first, err := ToOpenAIList(models)
+	require.NoError(t, err)
+	require.Len(t, first.Data, len(expected))
+	for i, e := range expected {
+		assert.Equal(t, e.id, first.Data[i].ID)
+		assert.Equal(t, e.created, first.Data[i].Created, "created of %s", e.id)
+		assert.Equal(t, e.ownedBy, first.Data[i].OwnedBy, "owned_by of %s", e.id)
+	}
+
+	// The order does not depend on the order of the input.
+	slices.Reverse(models)
+	second, err := ToOpenAIList(models)
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+}
+
+func TestModelOwner(t *testing.T) {
+	tests := map[string]string{
+		"ai/smollm2:latest":               "ai",
+		"docker.io/ai/smollm2":            "ai",
+		"hf.co/bartowski/model:Q4_K_M":    "bartowski",
+		"registry.local:5000/org/model":   "org",
+		"registry.local:5000/model:v1":    "docker",
+		"localhost/model":                 "docker",
+		"ai/model@sha256:abc":             "ai",
+		"model:latest":                    "docker",
+		"sha256:abc123":                   "docker",
+		"registry.local/team/group/model": "group",
+	}
+	for ref, want := range tests {
+		assert.Equal(t, want, modelOwner(ref), "owner of %s", ref)
+	}
+}
+
 // Helper function to create int32 pointers
 func int32Ptr(i int32) *int32 {
 	return &i
 }
+
+func TestModelConcise(t *testing.T) {
+	cfg := &types.Config{
+		Format:       types.FormatGGUF,
+		Parameters:   "8B",
+		Quantization: "Q4_K_M",
+		Architecture: "llama",
+		Size:         "4.58GB",
+		GGUF: map[string]string{
+			"general.architecture":    "llama",
+			"llama.context_length":    "131072",
+			"tokenizer.chat_template": "{{ messages }}",
+		},
+	}
+	m := &Model{ID: "sha256:abc123", Tags: []string{"ai/model:latest"}, Created: 1700000000, Config: cfg}
+
+	concise := m.Concise()
+	conciseCfg, ok := concise.Config.(*types.Config)
+	require.True(t, ok)
+	assert.Nil(t, conciseCfg.GGUF)
+	assert.Equal(t, "8B", conciseCfg.Parameters)
+	assert.Equal(t, "Q4_K_M", conciseCfg.Quantization)
+	assert.Equal(t, "llama", conciseCfg.Architecture)
+	assert.Equal(t, "4.58GB", conciseCfg.Size)
+	assert.Equal(t, []string{"ai/model:latest"}, concise.Tags)
+	assert.Zero(t, concise.Created)
+	require.NotNil(t, conciseCfg.ContextSize)
+	assert.Equal(t, int32(131072), *conciseCfg.ContextSize)
+	assert.Equal(t, "{{ messages }}", conciseCfg.ChatTemplate)
+
+	// The original model is left untouched.
+	assert.Equal(t, int64(1700000000), m.Created)
+	assert.Len(t, cfg.GGUF, 3)
+	assert.Nil(t, cfg.ContextSize)
+	assert.Empty(t, cfg.ChatTemplate)
+}
+
+func TestManagerDefaultContextSize(t *testing.T) {
+	gguf := func(contextLength string) *types.Config {
+		return &types.Config{GGUF: map[string]string{
+			"general.architecture": "llama",
+			"llama.context_length": contextLength,
+		}}
+	}
+	tests := []struct {
+		name    string
+		def     int32
+		config  types.ModelConfig
+		want    int32
+		wantNil bool
+	}{
+		{name: "no default", def: 0, config: gguf("4096"), wantNil: true},
+		{name: "no trained length", def: 8192, config: &types.Config{}, want: 8192},
+		{name: "below trained length", def: 8192, config: gguf("131072"), want: 8192},
+		{name: "capped at trained length", def: 8192, config: gguf("4096"), want: 4096},
+		{name: "invalid trained length", def: 8192, config: gguf("unknown"), want: 8192},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manager := NewManager(slog.Default(), ClientConfig{StoreRootPath: t.TempDir(), DefaultContextSize: tt.def})
+			got := manager.DefaultContextSize(tt.config)
+			if tt.wantNil {
+				assert.Nil(t, got)
+				return
+			}
+			require.NotNil(t, got)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+}
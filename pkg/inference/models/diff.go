@@ -0,0 +1,144 @@
+package models
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/types"
+)
+
+// ModelDiff describes how model B differs from model A.
+type ModelDiff struct {
+	// A is the ID of the first model.
+	A string `json:"a"`
+	// B is the ID of the second model.
+	B string `json:"b"`
+	// Config lists the config fields whose values differ.
+	Config []FieldChange `json:"config,omitempty"`
+	// Labels lists the manifest annotations that were added, removed or
+	// changed.
+	Labels []FieldChange `json:"labels,omitempty"`
+	// AddedLayers are the layers of B that are not in A.
+	AddedLayers []LayerSummary `json:"added_layers,omitempty"`
+	// RemovedLayers are the layers of A that are not in B.
+	RemovedLayers []LayerSummary `json:"removed_layers,omitempty"`
+	// SizeA is the total size of the layers of A, in bytes.
+	SizeA int64 `json:"size_a"`
+	// SizeB is the total size of the layers of B, in bytes.
+	SizeB int64 `json:"size_b"`
+}
+
+// Identical reports whether the diff found no differences.
+func (d ModelDiff) Identical() bool {
+	return len(d.Config) == 0 && len(d.Labels) == 0 &&
+		len(d.AddedLayers) == 0 && len(d.RemovedLayers) == 0 && d.SizeA == d.SizeB
+}
+
+// FieldChange is a named value that differs between two models. A or B is
+// empty if the value is unset in that model.
+type FieldChange struct {
+	Field string `json:"field"`
+	A     string `json:"a,omitempty"`
+	B     string `json:"b,omitempty"`
+}
+
+// LayerSummary identifies a model layer.
+type LayerSummary struct {
+	Digest    string `json:"digest"`
+	MediaType string `json:"media_type"`
+	Size      int64  `json:"size"`
+}
+
+// diffModels compares two local model artifacts.
+func diffModels(a, b types.ModelArtifact) (*ModelDiff, error) {
+	var diff ModelDiff
+	var manifests [2]*oci.Manifest
+	var configs [2]types.ModelConfig
+	for i, model := range []types.ModelArtifact{a, b} {
+		id, err := model.ID()
+		if err != nil {
+			return nil, fmt.Errorf("error while getting model ID: %w", err)
+		}
+		if i == 0 {
+			diff.A = id
+		} else {
+			diff.B = id
+		}
+		if manifests[i], err = model.Manifest(); err != nil {
+			return nil, fmt.Errorf("error while reading manifest of %s: %w", id, err)
+		}
+		if configs[i], err = model.Config(); err != nil {
+			return nil, fmt.Errorf("error while reading config of %s: %w", id, err)
+		}
+	}
+
+	diff.Config = diffFields(configFields(configs[0]), configFields(configs[1]))
+	diff.Labels = diffFields(manifests[0].Annotations, manifests[1].Annotations)
+	diff.RemovedLayers, diff.SizeA = layersNotIn(manifests[0].Layers, manifests[1].Layers)
+	diff.AddedLayers, diff.SizeB = layersNotIn(manifests[1].Layers, manifests[0].Layers)
+	return &diff, nil
+}
+
+// configFields returns the comparable fields of a model config, keyed by
+// their JSON names. Unset fields are omitted.
+func configFields(config types.ModelConfig) map[string]string {
+	fields := make(map[string]string)
+	if config == nil {
+		return fields
+	}
+	set := func(name, value string) {
+		if value != "" {
+			fields[name] = value
+		}
+	}
+	set("format", string(config.GetFormat()))
+	set("architecture", config.GetArchitecture())
+	set("parameters", config.GetParameters())
+	set("size", config.GetSize())
+	set("quantization", config.GetQuantization())
+	if ctx := config.GetContextSize(); ctx != nil {
+		set("context_size", strconv.FormatInt(int64(*ctx), 10))
+	}
+	return fields
+}
+
+// diffFields returns the keys whose values differ between a and b, sorted by
+// key.
+func diffFields(a, b map[string]string) []FieldChange {
+	fields := slices.Collect(maps.Keys(a))
+	for field := range b {
+		if _, ok := a[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	slices.Sort(fields)
+
+	var changes []FieldChange
+	for _, field := range fields {
+		if a[field] != b[field] {
+			changes = append(changes, FieldChange{Field: field, A: a[field], B: b[field]})
+		}
+	}
+	return changes
+}
+
+// layersNotIn returns the layers of from whose digests are not in other,
+// along with the total size of from.
+func layersNotIn(from, other []oci.Descriptor) ([]LayerSummary, int64) {
+	var missing []LayerSummary
+	var size int64
+	for _, layer := range from {
+		size += layer.Size
+		if !slices.ContainsFunc(other, func(o oci.Descriptor) bool { return o.Digest == layer.Digest }) {
+			missing = append(missing, LayerSummary{
+				Digest:    layer.Digest.String(),
+				MediaType: string(layer.MediaType),
+				Size:      layer.Size,
+			})
+		}
+	}
+	return missing, size
+}
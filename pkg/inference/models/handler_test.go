@@ -1,20 +1,46 @@
 package models
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"log/slog"
+	"maps"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"os"
 	"path/filepath"
+	"reflect"
+	"runtime"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/builder"
+	"github.com/docker/model-runner/pkg/distribution/distribution"
+	"github.com/docker/model-runner/pkg/distribution/oci"
 	reg "github.com/docker/model-runner/pkg/distribution/registry"
 	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/servicemetrics"
+	"github.com/docker/model-runner/pkg/tracing"
+	"github.com/opencontainers/go-digest"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // getProjectRoot returns the absolute path to the project root directory
@@ -113,7 +139,7 @@ func TestPullModel(t *testing.T) {
 			}
 
 			w := httptest.NewRecorder()
-			err = handler.manager.Pull(tag, "", r, w)
+			err = handler.manager.Pull(ModelCreateRequest{From: tag}, r, w)
 			if err != nil {
 				t.Fatalf("Failed to pull model: %v", err)
 			}
@@ -219,7 +245,7 @@ func TestHandleGetModel(t *testing.T) {
 			if !tt.remote && !strings.Contains(tt.modelName, "nonexistent") {
 				r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tt.modelName+`"}`))
 				w := httptest.NewRecorder()
-				err = handler.manager.Pull(tt.modelName, "", r, w)
+				err = handler.manager.Pull(ModelCreateRequest{From: tt.modelName}, r, w)
 				if err != nil {
 					t.Fatalf("Failed to pull model: %v", err)
 				}
@@ -265,42 +291,3621 @@ func TestHandleGetModel(t *testing.T) {
 	}
 }
 
+func TestHandleTagModelByID(t *testing.T) {
+	// Create a test registry
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	// Prepare the OCI model artifact
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+
+	// Build the OCI model artifact + push it (use plainHTTP for test registry)
+	tag := uri.Host + "/ai/model:v1.0.0"
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, os.Stdout); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	pulled, err := manager.GetLocal(tag)
+	if err != nil {
+		t.Fatalf("Failed to get pulled model: %v", err)
+	}
+	id, err := pulled.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		source string
+		repo   string
+	}{
+		{name: "full ID, single tag", source: id, repo: "ai/full-id"},
+		{name: "short ID, multiple tags", source: id[7:19], repo: "ai/short-id"},
+		{name: "ID without prefix, multiple tags", source: strings.TrimPrefix(id, "sha256:"), repo: "ai/no-prefix"},
+		{name: "short ID with prefix, multiple tags", source: id[:19], repo: "ai/prefixed-short-id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := inference.ModelsPrefix + "/" + tt.source + "/tag?repo=" + tt.repo + "&tag=v1"
+			w := httptest.NewRecorder()
+			handler.handleTagModel(w, httptest.NewRequest(http.MethodPost, path, http.NoBody), tt.source)
+			if w.Code != http.StatusCreated {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+			}
+			var response ModelTagResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to decode tag response: %v", err)
+			}
+			if response.SourceID != id || response.Target != tt.repo+":v1" {
+				t.Errorf("Expected response for %s tagged as %s, got %+v", id, tt.repo+":v1", response)
+			}
+
+			tagged, err := manager.GetLocal(tt.repo + ":v1")
+			if err != nil {
+				t.Fatalf("Failed to get model by new tag: %v", err)
+			}
+			taggedID, err := tagged.ID()
+			if err != nil {
+				t.Fatalf("Failed to get tagged model ID: %v", err)
+			}
+			if taggedID != id {
+				t.Errorf("Expected new tag to point to %s, got %s", id, taggedID)
+			}
+		})
+	}
+}
+
+func TestHandleTagModelPinned(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	ggufPath := filepath.Join(getProjectRoot(t), "assets", "dummy.gguf")
+	tag := uri.Host + "/ai/model:latest"
+	push := func(path string) {
+		t.Helper()
+		model, err := builder.FromPath(path)
+		if err != nil {
+			t.Fatalf("Failed to create model builder: %v", err)
+		}
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to build model: %v", err)
+		}
+	}
+	push(ggufPath)
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	pull := func() string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+		w := httptest.NewRecorder()
+		if err := manager.Pull(ModelCreateRequest{From: tag}, r, w); err != nil {
+			t.Fatalf("Failed to pull model: %v", err)
+		}
+		return w.Body.String()
+	}
+	idOf := func(ref string) string {
+		t.Helper()
+		model, err := manager.GetLocal(ref)
+		if err != nil {
+			t.Fatalf("Failed to get model %s: %v", ref, err)
+		}
+		id, err := model.ID()
+		if err != nil {
+			t.Fatalf("Failed to get model ID: %v", err)
+		}
+		return id
+	}
+
+	pull()
+	pinnedID := idOf(tag)
+
+	path := inference.ModelsPrefix + "/" + tag + "/tag?repo=ai/pinned&tag=v1&pin=true"
+	w := httptest.NewRecorder()
+	handler.handleTagModel(w, httptest.NewRequest(http.MethodPost, path, http.NoBody), tag)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusCreated, w.Code, w.Body.String())
+	}
+
+	// The pin is surfaced when inspecting the model.
+	r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/ai/pinned:v1", http.NoBody)
+	r.SetPathValue("name", "ai/pinned:v1")
+	w = httptest.NewRecorder()
+	handler.handleGetModel(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response Model
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if response.ID != pinnedID {
+		t.Errorf("Expected pinned tag to point to %s, got %s", pinnedID, response.ID)
+	}
+	if len(response.Pins) != 1 {
+		t.Fatalf("Expected a single pin, got %v", response.Pins)
+	}
+	for pinnedTag, source := range response.Pins {
+		if !strings.HasSuffix(pinnedTag, "ai/pinned:v1") || source != tag {
+			t.Errorf("Expected ai/pinned:v1 to be pinned from %s, got %s pinned from %s", tag, pinnedTag, source)
+		}
+	}
+
+	// Pulling the source after it moved warns and leaves the pinned tag alone.
+	data, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	movedPath := filepath.Join(t.TempDir(), "moved.gguf")
+	if err := os.WriteFile(movedPath, append(data, 0), 0o644); err != nil {
+		t.Fatalf("Failed to write moved model: %v", err)
+	}
+	push(movedPath)
+	if body := pull(); !strings.Contains(body, "stays pinned to "+pinnedID) {
+		t.Errorf("Expected pull to warn about the pinned tag, got %s", body)
+	}
+	if id := idOf(tag); id == pinnedID {
+		t.Fatalf("Expected %s to move away from %s", tag, pinnedID)
+	}
+	if id := idOf("ai/pinned:v1"); id != pinnedID {
+		t.Errorf("Expected pinned tag to stay on %s, got %s", pinnedID, id)
+	}
+}
+
+func TestPullAs(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:v1.0.0"
+	mdl, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := mdl.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+	remoteMdl, err := client.Model(t.Context(), tag)
+	if err != nil {
+		t.Fatalf("Failed to get remote model: %v", err)
+	}
+	digest, err := remoteMdl.Digest()
+	if err != nil {
+		t.Fatalf("Failed to get remote model digest: %v", err)
+	}
+	digestRef := uri.Host + "/ai/model@" + digest.String()
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	body := `{"from": "` + digestRef + `", "as": "myalias:latest"}`
+	w := httptest.NewRecorder()
+	handler.handleCreateModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	pulled, err := manager.GetLocal(digestRef)
+	if err != nil {
+		t.Fatalf("Failed to get model by digest: %v", err)
+	}
+	pulledID, err := pulled.ID()
+	if err != nil {
+		t.Fatalf("Failed to get pulled model ID: %v", err)
+	}
+	alias, err := manager.GetLocal("myalias:latest")
+	if err != nil {
+		t.Fatalf("Failed to get model by alias: %v", err)
+	}
+	aliasID, err := alias.ID()
+	if err != nil {
+		t.Fatalf("Failed to get alias model ID: %v", err)
+	}
+	if aliasID != pulledID {
+		t.Errorf("Expected alias to point to %s, got %s", pulledID, aliasID)
+	}
+}
+
+func TestPullWithDockerKeychain(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake credential helper is a shell script")
+	}
+	const helperToken = "short-lived-token"
+	const accessToken = "registry-access-token"
+
+	// Create a test registry that requires a bearer token once the model has
+	// been pushed, issuing access tokens in exchange for the helper's token.
+	registry := testregistry.New()
+	var requireAuth atomic.Bool
+	var tokenRequests, helperTokenRequests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			tokenRequests.Add(1)
+			_ = r.ParseForm()
+			if r.PostForm.Get("refresh_token") != helperToken {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			helperTokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": accessToken, "expires_in": 60})
+			return
+		}
+		if requireAuth.Load() && r.URL.Path != "/v2/" && r.Header.Get("Authorization") != "Bearer "+accessToken {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="test-registry"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/ai/private:latest"
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+	requireAuth.Store(true)
+
+	// Install a fake credential helper that returns an identity token for
+	// the registry, and point the Docker config at it.
+	binDir := t.TempDir()
+	helper := `#!/bin/sh
+read -r server
+printf '{"ServerURL":"%s","Username":"<token>","Secret":"` + helperToken + `"}' "$server"
+`
+	if err := os.WriteFile(filepath.Join(binDir, "docker-credential-dmrtest"), []byte(helper), 0o755); err != nil {
+		t.Fatalf("Failed to write credential helper: %v", err)
+	}
+	home := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(home, ".docker"), 0o755); err != nil {
+		t.Fatalf("Failed to create Docker config directory: %v", err)
+	}
+	dockerConfig := `{"credHelpers": {"` + uri.Host + `": "dmrtest"}}`
+	if err := os.WriteFile(filepath.Join(home, ".docker", "config.json"), []byte(dockerConfig), 0o644); err != nil {
+		t.Fatalf("Failed to write Docker config: %v", err)
+	}
+	t.Setenv("PATH", binDir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", home)
+	for _, env := range []string{"DOCKER_USERNAME", "DOCKER_PASSWORD", "DOCKER_HUB_USER", "DOCKER_HUB_PASSWORD"} {
+		t.Setenv(env, "")
+	}
+
+	tests := []struct {
+		name              string
+		useDockerKeychain bool
+		expectHelperToken bool
+	}{
+		{name: "docker keychain", useDockerKeychain: true, expectHelperToken: true},
+		{name: "environment only", useDockerKeychain: false, expectHelperToken: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenRequests.Store(0)
+			helperTokenRequests.Store(0)
+
+			log := slog.Default()
+			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+				StoreRootPath:     t.TempDir(),
+				Logger:            log.With("component", "model-manager"),
+				PlainHTTP:         true,
+				UseDockerKeychain: tt.useDockerKeychain,
+			})
+
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+			err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder())
+			if tokenRequests.Load() == 0 {
+				t.Fatal("Expected the registry to request authentication")
+			}
+			if tt.expectHelperToken {
+				if err != nil {
+					t.Fatalf("Failed to pull model: %v", err)
+				}
+				if helperTokenRequests.Load() == 0 {
+					t.Error("Expected the credential helper's token to be used for the token request")
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Expected pull without credentials to fail")
+			}
+			if helperTokenRequests.Load() != 0 {
+				t.Error("Expected the credential helper not to be used")
+			}
+		})
+	}
+}
+
+func TestHandleGetModelsNameFilter(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:v1.0.0"
+	mdl, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := mdl.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "no filter", query: "", want: 1},
+		{name: "matching prefix", query: "?name=" + url.QueryEscape(uri.Host+"/ai/mo"), want: 1},
+		{name: "non-matching prefix", query: "?name=" + url.QueryEscape(uri.Host+"/ai/other"), want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.handleGetModels(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+tt.query, http.NoBody))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var models []Model
+			if err := json.Unmarshal(w.Body.Bytes(), &models); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(models) != tt.want {
+				t.Errorf("Expected %d models, got %d: %v", tt.want, len(models), models)
+			}
+		})
+	}
+}
+
+// writeSafetensors writes a minimal single-tensor safetensors file to dir.
+func writeSafetensors(t *testing.T, dir string) string {
+	t.Helper()
+	header := []byte(`{"weight":{"dtype":"F32","shape":[1],"data_offsets":[0,4]}}`)
+	data := make([]byte, 8, 8+len(header)+4)
+	binary.LittleEndian.PutUint64(data, uint64(len(header)))
+	data = append(data, header...)
+	data = append(data, 0, 0, 0, 0)
+	path := filepath.Join(dir, "model.safetensors")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("Failed to write safetensors file: %v", err)
+	}
+	return path
+}
+
+func TestHandleGetModelsRunnable(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	ggufTag := uri.Host + "/ai/gguf-model:latest"
+	safetensorsTag := uri.Host + "/ai/safetensors-model:latest"
+	for tag, path := range map[string]string{
+		ggufTag:        filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"),
+		safetensorsTag: writeSafetensors(t, t.TempDir()),
+	} {
+		mdl, err := builder.FromPath(path)
+		if err != nil {
+			t.Fatalf("Failed to create model builder for %s: %v", path, err)
+		}
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := mdl.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to push model: %v", err)
+		}
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+		if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+			t.Fatalf("Failed to pull model %s: %v", tag, err)
+		}
+	}
+
+	ggufOnly := func(format types.Format) bool { return format == types.FormatGGUF }
+	vllmCapable := func(format types.Format) bool {
+		return format == types.FormatGGUF || format == types.FormatSafetensors
+	}
+
+	tests := []struct {
+		name      string
+		supported func(types.Format) bool
+		query     string
+		want      []string
+	}{
+		{name: "GGUF-only, unfiltered", supported: ggufOnly, query: "", want: []string{ggufTag, safetensorsTag}},
+		{name: "GGUF-only, runnable", supported: ggufOnly, query: "?runnable=true", want: []string{ggufTag}},
+		{name: "vLLM-capable, runnable", supported: vllmCapable, query: "?runnable=true", want: []string{ggufTag, safetensorsTag}},
+		{name: "no format support check, runnable", supported: nil, query: "?runnable=true", want: []string{ggufTag, safetensorsTag}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler.SetFormatSupport(tt.supported)
+			w := httptest.NewRecorder()
+			handler.handleGetModels(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+tt.query, http.NoBody))
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+			var models []Model
+			if err := json.Unmarshal(w.Body.Bytes(), &models); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			var got []string
+			for _, m := range models {
+				got = append(got, m.Tags...)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Expected models %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestPullInvalidAlias(t *testing.T) {
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+	})
+
+	// The alias is rejected before the pull starts streaming, so nothing has
+	// been written and the error can still be sent with a status code.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", http.NoBody)
+	err := manager.Pull(ModelCreateRequest{From: "ai/model", As: "Invalid Alias"}, r, w)
+	if !errors.Is(err, reg.ErrInvalidReference) {
+		t.Fatalf("Expected ErrInvalidReference, got %v", err)
+	}
+	if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+		t.Errorf("Expected nothing to be written, got headers %v and body %q", w.Header(), w.Body.String())
+	}
+}
+
+func TestTagAndDeleteConcurrently(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:v1.0.0"
+	mdl, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := mdl.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	for i := range 10 {
+		r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+		if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+			t.Fatalf("Failed to pull model: %v", err)
+		}
+
+		var wg sync.WaitGroup
+		tagW := httptest.NewRecorder()
+		deleteW := httptest.NewRecorder()
+		wg.Go(func() {
+			path := inference.ModelsPrefix + "/" + tag + "/tag?repo=ai/concurrent&tag=v1"
+			handler.handleTagModel(tagW, httptest.NewRequest(http.MethodPost, path, http.NoBody), tag)
+		})
+		wg.Go(func() {
+			r := httptest.NewRequest(http.MethodDelete, inference.ModelsPrefix+"/"+tag+"?force=true", http.NoBody)
+			r.SetPathValue("name", tag)
+			handler.handleDeleteModel(deleteW, r)
+		})
+		wg.Wait()
+
+		if tagW.Code != http.StatusCreated && tagW.Code != http.StatusNotFound {
+			t.Fatalf("Iteration %d: expected tag status %d or %d, got %d: %s",
+				i, http.StatusCreated, http.StatusNotFound, tagW.Code, tagW.Body.String())
+		}
+		if deleteW.Code != http.StatusOK {
+			t.Fatalf("Iteration %d: expected delete status %d, got %d: %s",
+				i, http.StatusOK, deleteW.Code, deleteW.Body.String())
+		}
+
+		// Deleting a tag only removes that tag, so the model survives under
+		// the new tag if it was added first, and is gone otherwise.
+		models, err := manager.List()
+		if err != nil {
+			t.Fatalf("Iteration %d: failed to list models: %v", i, err)
+		}
+		if tagW.Code == http.StatusNotFound {
+			if len(models) != 0 {
+				t.Fatalf("Iteration %d: expected no models after delete, got %d (tags %v)", i, len(models), models[0].Tags)
+			}
+			continue
+		}
+		if len(models) != 1 || len(models[0].Tags) != 1 {
+			t.Fatalf("Iteration %d: expected the model to survive with a single tag, got %d models", i, len(models))
+		}
+		if _, err := manager.GetLocal("ai/concurrent:v1"); err != nil {
+			t.Fatalf("Iteration %d: expected the model to survive as ai/concurrent:v1, got %v", i, err)
+		}
+		deleteConcurrentTag := httptest.NewRequest(http.MethodDelete, inference.ModelsPrefix+"/ai/concurrent:v1", http.NoBody)
+		deleteConcurrentTag.SetPathValue("name", "ai/concurrent:v1")
+		handler.handleDeleteModel(httptest.NewRecorder(), deleteConcurrentTag)
+	}
+}
+
+func TestHandleDeleteModelTag(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:v1.0.0"
+	mdl, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := mdl.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	const otherTag = "ai/other:v1"
+	if _, err := manager.Tag(tag, otherTag); err != nil {
+		t.Fatalf("Failed to tag model: %v", err)
+	}
+	model, err := manager.GetLocal(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	id, err := model.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	deleteModel := func(ref string, force bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodDelete, inference.ModelsPrefix+"/"+ref+"?force="+strconv.FormatBool(force), http.NoBody)
+		r.SetPathValue("name", ref)
+		w := httptest.NewRecorder()
+		handler.handleDeleteModel(w, r)
+		return w
+	}
+
+	// A model with several tags is not deleted by ID unless forced.
+	if w := deleteModel(id, false); w.Code != http.StatusConflict {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	// Deleting one of the tags, even when forced, only removes that tag.
+	w := deleteModel(tag, true)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp distribution.DeleteModelResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp) != 1 || resp[0].Untagged == nil || resp[0].Deleted != nil {
+		t.Fatalf("Expected a single untag action, got %+v", resp)
+	}
+
+	models, err := manager.List()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != id || len(models[0].Tags) != 1 {
+		t.Fatalf("Expected model %s to survive with a single tag, got %d models", id, len(models))
+	}
+	if _, err := manager.GetLocal(tag); !errors.Is(err, distribution.ErrModelNotFound) {
+		t.Errorf("Expected %s to be untagged, got %v", tag, err)
+	}
+	if _, err := manager.GetBundle(otherTag); err != nil {
+		t.Errorf("Expected the blobs of %s to survive, got %v", otherTag, err)
+	}
+
+	// Deleting the last tag deletes the model.
+	w = deleteModel(otherTag, false)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	resp = nil
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp) == 0 || resp[len(resp)-1].Deleted == nil || *resp[len(resp)-1].Deleted != id {
+		t.Fatalf("Expected model %s to be deleted, got %+v", id, resp)
+	}
+	if models, err := manager.List(); err != nil || len(models) != 0 {
+		t.Fatalf("Expected no models, got %v (error %v)", models, err)
+	}
+}
+
+func TestHandleRepackageModelExistingTarget(t *testing.T) {
+	// Create a test registry
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	// Prepare the OCI model artifact
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+
+	// Build the OCI model artifact + push it (use plainHTTP for test registry)
+	tag := uri.Host + "/ai/model:v1.0.0"
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, os.Stdout); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// Each step repackages onto the same target, which exists after the first
+	tests := []struct {
+		name         string
+		body         string
+		expectedCode int
+		expectedCtx  int32
+	}{
+		{name: "new target", body: `{"target":"ai/variant","context_size":2048}`, expectedCode: http.StatusCreated, expectedCtx: 2048},
+		{name: "existing target without force", body: `{"target":"ai/variant","context_size":4096}`, expectedCode: http.StatusConflict, expectedCtx: 2048},
+		{name: "existing target with force", body: `{"target":"ai/variant","context_size":4096,"force":true}`, expectedCode: http.StatusCreated, expectedCtx: 4096},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := inference.ModelsPrefix + "/" + tag + "/repackage"
+			w := httptest.NewRecorder()
+			handler.handleRepackageModel(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(tt.body)), tag)
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+
+			variant, err := manager.GetLocal("ai/variant")
+			if err != nil {
+				t.Fatalf("Failed to get repackaged model: %v", err)
+			}
+			config, err := variant.Config()
+			if err != nil {
+				t.Fatalf("Failed to get repackaged model config: %v", err)
+			}
+			if ctx := config.GetContextSize(); ctx == nil || *ctx != tt.expectedCtx {
+				t.Errorf("Expected context size %d, got %v", tt.expectedCtx, ctx)
+			}
+		})
+	}
+}
+
+func TestHandleDiffModels(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	w := httptest.NewRecorder()
+	repackage := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+tag+"/repackage", strings.NewReader(`{"target":"ai/variant","context_size":4096}`))
+	handler.handleRepackageModel(w, repackage, tag)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Failed to repackage model: status %d: %s", w.Code, w.Body.String())
+	}
+
+	t.Run("context size variant", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		handler.handleDiffModels(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/diff?a="+url.QueryEscape(tag)+"&b=ai/variant", http.NoBody))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var diff ModelDiff
+		if err := json.Unmarshal(w.Body.Bytes(), &diff); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+
+		if diff.A == "" || diff.B == "" || diff.A == diff.B {
+			t.Errorf("Expected two distinct model IDs, got %q and %q", diff.A, diff.B)
+		}
+		want := []FieldChange{{Field: "context_size", B: "4096"}}
+		if !slices.Equal(diff.Config, want) {
+			t.Errorf("Expected config changes %v, got %v", want, diff.Config)
+		}
+		if len(diff.Labels) != 0 {
+			t.Errorf("Expected no label changes, got %v", diff.Labels)
+		}
+		if len(diff.AddedLayers) != 0 || len(diff.RemovedLayers) != 0 {
+			t.Errorf("Expected shared layers, got added %v and removed %v", diff.AddedLayers, diff.RemovedLayers)
+		}
+		if diff.SizeA == 0 || diff.SizeA != diff.SizeB {
+			t.Errorf("Expected equal non-zero sizes, got %d and %d", diff.SizeA, diff.SizeB)
+		}
+	})
+
+	t.Run("same model", func(t *testing.T) {
+		diff, err := manager.Diff(tag, tag)
+		if err != nil {
+			t.Fatalf("Failed to diff model: %v", err)
+		}
+		if !diff.Identical() {
+			t.Errorf("Expected a model to be identical to itself, got %+v", diff)
+		}
+	})
+
+	errorTests := []struct {
+		name         string
+		query        string
+		expectedCode int
+	}{
+		{name: "missing reference", query: "?a=" + url.QueryEscape(tag), expectedCode: http.StatusBadRequest},
+		{name: "unknown model", query: "?a=" + url.QueryEscape(tag) + "&b=ai/nonexistent", expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.handleDiffModels(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/diff"+tt.query, http.NoBody))
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleCheckUpdate(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	tag := uri.Host + "/ai/model:latest"
+	// publish builds a model with the given license text and pushes it as tag.
+	publish := func(license string) {
+		t.Helper()
+		model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+		if err != nil {
+			t.Fatalf("Failed to create model builder: %v", err)
+		}
+		licensePath := filepath.Join(t.TempDir(), "license.txt")
+		if err := os.WriteFile(licensePath, []byte(license), 0644); err != nil {
+			t.Fatalf("Failed to write license: %v", err)
+		}
+		if model, err = model.WithLicense(licensePath); err != nil {
+			t.Fatalf("Failed to add license to model: %v", err)
+		}
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to push model: %v", err)
+		}
+	}
+	publish("v1")
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	local, err := manager.GetLocal(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	localID, err := local.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+
+	checkUpdate := func(t *testing.T, model string) ModelUpdateCheck {
+		t.Helper()
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+model+"/update-check", http.NoBody))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var check ModelUpdateCheck
+		if err := json.Unmarshal(w.Body.Bytes(), &check); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return check
+	}
+
+	t.Run("matching digests", func(t *testing.T) {
+		check := checkUpdate(t, tag)
+		if check.UpdateAvailable {
+			t.Error("Expected no update to be available")
+		}
+		if check.LocalDigest != localID || check.RemoteDigest != localID {
+			t.Errorf("Expected local and remote digests %s, got %s and %s", localID, check.LocalDigest, check.RemoteDigest)
+		}
+	})
+
+	publish("v2")
+
+	t.Run("differing digests", func(t *testing.T) {
+		check := checkUpdate(t, tag)
+		if !check.UpdateAvailable {
+			t.Error("Expected an update to be available")
+		}
+		if check.LocalDigest != localID {
+			t.Errorf("Expected local digest %s, got %s", localID, check.LocalDigest)
+		}
+		if check.RemoteDigest == "" || check.RemoteDigest == localID {
+			t.Errorf("Expected a remote digest other than %s, got %q", localID, check.RemoteDigest)
+		}
+	})
+
+	errorTests := []struct {
+		name         string
+		model        string
+		expectedCode int
+	}{
+		{name: "ID", model: localID, expectedCode: http.StatusBadRequest},
+		{name: "unknown model", model: uri.Host + "/ai/nonexistent:latest", expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tt.model+"/update-check", http.NoBody))
+			if w.Code != tt.expectedCode {
+				t.Errorf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandlePushModelReference(t *testing.T) {
+	// Create a test registry
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	// Prepare the OCI model artifact
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+
+	// Build the OCI model artifact + push it (use plainHTTP for test registry)
+	tag := uri.Host + "/ai/model:v1.0.0"
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, os.Stdout); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	pushed := uri.Host + "/ai/pushed:v2"
+	if _, err := manager.Tag(tag, pushed); err != nil {
+		t.Fatalf("Failed to tag model as %s: %v", pushed, err)
+	}
+
+	tests := []struct {
+		name          string
+		model         string
+		expectedCode  int
+		expectedError string
+	}{
+		{name: "unknown bare name", model: "bare", expectedCode: http.StatusBadRequest, expectedError: "docker model tag bare <org>/bare"},
+		{name: "unknown bare name with tag", model: "bare:v1", expectedCode: http.StatusBadRequest, expectedError: "docker model tag bare:v1 <org>/bare"},
+		{name: "digest", model: "ai/model@sha256:" + strings.Repeat("a", 64), expectedCode: http.StatusBadRequest, expectedError: "by digest"},
+		{name: "fully-qualified reference", model: pushed, expectedCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+tt.model+"/push", http.NoBody)
+			w := httptest.NewRecorder()
+			handler.handlePushModel(w, r, tt.model)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedError != "" && !strings.Contains(w.Body.String(), tt.expectedError) {
+				t.Errorf("Expected error containing %q, got %q", tt.expectedError, w.Body.String())
+			}
+		})
+	}
+
+	if _, err := client.Model(t.Context(), pushed); err != nil {
+		t.Errorf("Expected %s to be pushed to the registry: %v", pushed, err)
+	}
+}
+
+func TestHandlePushModelShortName(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	// Build two distinct models, so that pushing the wrong one is detected.
+	projectRoot := getProjectRoot(t)
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	var sources []string
+	for _, name := range []string{"smollm2", "other"} {
+		model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+		if err != nil {
+			t.Fatalf("Failed to create model builder: %v", err)
+		}
+		licensePath := filepath.Join(t.TempDir(), name+".txt")
+		if err := os.WriteFile(licensePath, []byte(name), 0644); err != nil {
+			t.Fatalf("Failed to write license: %v", err)
+		}
+		if model, err = model.WithLicense(licensePath); err != nil {
+			t.Fatalf("Failed to add license to model: %v", err)
+		}
+		source := uri.Host + "/source/" + name + ":v1"
+		target, err := client.NewTarget(source)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to build model: %v", err)
+		}
+		sources = append(sources, source)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	for _, source := range sources {
+		r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+		if err := manager.Pull(ModelCreateRequest{From: source}, r, httptest.NewRecorder()); err != nil {
+			t.Fatalf("Failed to pull model: %v", err)
+		}
+	}
+	stored := uri.Host + "/ai/smollm2:latest"
+	if _, err := manager.Tag(sources[0], stored); err != nil {
+		t.Fatalf("Failed to tag model as %s: %v", stored, err)
+	}
+	if _, err := manager.Tag(sources[1], uri.Host+"/ai/other:latest"); err != nil {
+		t.Fatalf("Failed to tag model: %v", err)
+	}
+
+	if got := manager.ResolvePushReference("smollm2"); got != stored {
+		t.Fatalf("Expected smollm2 to resolve to %s, got %s", stored, got)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/smollm2/push", http.NoBody)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	remote, err := client.Model(t.Context(), stored)
+	if err != nil {
+		t.Fatalf("Expected %s to be pushed to the registry: %v", stored, err)
+	}
+	remoteID, err := remote.ID()
+	if err != nil {
+		t.Fatalf("Failed to get pushed model ID: %v", err)
+	}
+	if want := manager.ResolveID(sources[0]); remoteID != want {
+		t.Errorf("Expected pushed model %s, got %s", want, remoteID)
+	}
+}
+
+func TestHandleCancelPush(t *testing.T) {
+	// Create a test registry whose uploads to ai/slow never complete, and
+	// which records when they are aborted and whether a manifest is pushed.
+	// Blobs are reported missing from ai/slow so that they are uploaded.
+	registry := testregistry.New()
+	uploadStarted := make(chan struct{})
+	uploadStopped := make(chan struct{})
+	var startOnce, stopOnce sync.Once
+	var manifestPushed atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/v2/ai/slow/") {
+			switch {
+			case r.Method == http.MethodHead && strings.Contains(r.URL.Path, "/blobs/"):
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case strings.Contains(r.URL.Path, "/blobs/uploads/") && (r.Method == http.MethodPatch || r.Method == http.MethodPut):
+				startOnce.Do(func() { close(uploadStarted) })
+				_, _ = io.Copy(io.Discard, r.Body)
+				<-r.Context().Done()
+				stopOnce.Do(func() { close(uploadStopped) })
+				return
+			case strings.Contains(r.URL.Path, "/manifests/") && r.Method == http.MethodPut:
+				manifestPushed.Store(true)
+			}
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, io.Discard); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	slow := uri.Host + "/ai/slow:v1"
+	if _, err := manager.Tag(tag, slow); err != nil {
+		t.Fatalf("Failed to tag model: %v", err)
+	}
+
+	cancelPush := func() *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+slow+"/cancel-push", http.NoBody)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := cancelPush(); w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status code %d without a push in progress, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+
+	pushRecorder := httptest.NewRecorder()
+	pushDone := make(chan struct{})
+	go func() {
+		defer close(pushDone)
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+slow+"/push", http.NoBody)
+		handler.handlePushModel(pushRecorder, r, slow)
+	}()
+
+	select {
+	case <-uploadStarted:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the upload to start")
+	}
+
+	if w := cancelPush(); w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	for name, done := range map[string]chan struct{}{"upload to stop": uploadStopped, "push to return": pushDone} {
+		select {
+		case <-done:
+		case <-time.After(10 * time.Second):
+			t.Fatalf("Timed out waiting for the %s", name)
+		}
+	}
+	if !strings.Contains(pushRecorder.Body.String(), "context canceled") {
+		t.Errorf("Expected the push to report that it was canceled, got %q", pushRecorder.Body.String())
+	}
+	if manifestPushed.Load() {
+		t.Error("Expected no manifest to be pushed after canceling")
+	}
+
+	if w := cancelPush(); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d once the push has finished, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestHandleListRemoteTags(t *testing.T) {
+	// Create a test registry that returns two tags per page unless asked
+	// otherwise, so listing all tags requires following the pagination links.
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/tags/list") && !r.URL.Query().Has("n") {
+			query := r.URL.Query()
+			query.Set("n", "2")
+			r.URL.RawQuery = query.Encode()
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	expectedTags := []string{"latest", "v1", "v2", "v3", "v4"}
+	for _, tag := range expectedTags {
+		target, err := client.NewTarget(uri.Host + "/ai/model:" + tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, os.Stdout); err != nil {
+			t.Fatalf("Failed to build model: %v", err)
+		}
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	tests := []struct {
+		name         string
+		repo         string
+		expectedCode int
+		expectedTags []string
+	}{
+		{name: "paginated tags", repo: uri.Host + "/ai/model", expectedCode: http.StatusOK, expectedTags: expectedTags},
+		{name: "tag in reference is ignored", repo: uri.Host + "/ai/model:v1", expectedCode: http.StatusOK, expectedTags: expectedTags},
+		{name: "unknown repository", repo: uri.Host + "/ai/nonexistent", expectedCode: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nameAndAction := tt.repo + "/tags"
+			r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+nameAndAction+"?remote=true", http.NoBody)
+			r.SetPathValue("nameAndAction", nameAndAction)
+			w := httptest.NewRecorder()
+			handler.handleModelGetAction(w, r)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			var tags []string
+			if err := json.NewDecoder(w.Body).Decode(&tags); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			if !slices.Equal(tags, tt.expectedTags) {
+				t.Errorf("Expected tags %v, got %v", tt.expectedTags, tags)
+			}
+		})
+	}
+}
+
+func TestHandleRemoteBlob(t *testing.T) {
+	// Create a test registry that can be told to corrupt the blobs it serves.
+	registry := testregistry.New()
+	var corrupt atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, dgst, ok := strings.Cut(r.URL.Path, "/blobs/"); ok && !strings.HasPrefix(dgst, "uploads") &&
+			r.Method == http.MethodGet && corrupt.Load() {
+			rec := httptest.NewRecorder()
+			registry.ServeHTTP(rec, r)
+			body := rec.Body.Bytes()
+			body[len(body)-1] ^= 0xff
+			w.WriteHeader(rec.Code)
+			_, _ = w.Write(body)
+			return
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	ggufPath := filepath.Join(getProjectRoot(t), "assets", "dummy.gguf")
+	content, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	digest, _, err := oci.SHA256(bytes.NewReader(content))
+	if err != nil {
+		t.Fatalf("Failed to compute model file digest: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:latest"
+	model, err := builder.FromPath(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	daemon := httptest.NewServer(NewHTTPHandler(log, manager, nil))
+	defer daemon.Close()
+	blobURL := daemon.URL + inference.ModelsPrefix + "/" + tag + "/blobs/" + digest.String()
+
+	t.Run("streams verified blob", func(t *testing.T) {
+		corrupt.Store(false)
+		resp, err := http.Get(blobURL)
+		if err != nil {
+			t.Fatalf("Failed to get blob: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("Failed to read blob: %v", err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+		}
+		if got := resp.Header.Get("Docker-Content-Digest"); got != digest.String() {
+			t.Errorf("Expected Docker-Content-Digest %q, got %q", digest.String(), got)
+		}
+		if resp.ContentLength != int64(len(content)) {
+			t.Errorf("Expected Content-Length %d, got %d", len(content), resp.ContentLength)
+		}
+		if got, _, _ := oci.SHA256(bytes.NewReader(body)); got != digest {
+			t.Errorf("Expected body with digest %s, got %s", digest, got)
+		}
+	})
+
+	t.Run("corrupt upstream", func(t *testing.T) {
+		corrupt.Store(true)
+		resp, err := http.Get(blobURL)
+		if err != nil {
+			// The blob fits in a single chunk, so the connection may be
+			// aborted before the response headers are flushed.
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err == nil {
+			t.Fatalf("Expected corrupt blob to fail, read %d of %d bytes", len(body), resp.ContentLength)
+		}
+		if len(body) >= len(content) {
+			t.Errorf("Expected truncated body, got %d bytes", len(body))
+		}
+	})
+
+	t.Run("unknown blob", func(t *testing.T) {
+		corrupt.Store(false)
+		unknown, _, _ := oci.SHA256(strings.NewReader("unknown"))
+		resp, err := http.Get(daemon.URL + inference.ModelsPrefix + "/" + tag + "/blobs/" + unknown.String())
+		if err != nil {
+			t.Fatalf("Failed to get blob: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusNotFound {
+			t.Errorf("Expected status code %d, got %d", http.StatusNotFound, resp.StatusCode)
+		}
+	})
+}
+
+// chunkReader returns one chunk per read and then err.
+type chunkReader struct {
+	chunks []string
+	err    error
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func TestCopyWithholdingLast(t *testing.T) {
+	var buf bytes.Buffer
+	if err := copyWithholdingLast(&buf, &chunkReader{chunks: []string{"a", "b", "c"}, err: io.EOF}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if buf.String() != "abc" {
+		t.Errorf("Expected %q, got %q", "abc", buf.String())
+	}
+
+	buf.Reset()
+	mismatch := errors.New("digest mismatch")
+	if err := copyWithholdingLast(&buf, &chunkReader{chunks: []string{"a", "b", "c"}, err: mismatch}); !errors.Is(err, mismatch) {
+		t.Fatalf("Expected %v, got %v", mismatch, err)
+	}
+	if buf.String() != "ab" {
+		t.Errorf("Expected the last chunk to be withheld, got %q", buf.String())
+	}
+}
+
+func TestProgressResponseWriterEscaping(t *testing.T) {
+	const line = "Downloading ai/r&d<test>\n"
+	tests := []struct {
+		name   string
+		accept string
+		want   string
+	}{
+		{name: "plain text", want: line},
+		{name: "plain text with other parameters", accept: "text/plain; charset=utf-8", want: line},
+		{name: "plain text escaped", accept: "text/plain; escape=html", want: "Downloading ai/r&amp;d&lt;test&gt;\n"},
+		{name: "escaped among other media types", accept: "text/event-stream, text/plain;escape=html", want: "Downloading ai/r&amp;d&lt;test&gt;\n"},
+		{name: "json", accept: "application/json", want: line},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+			w := httptest.NewRecorder()
+			progressWriter := &progressResponseWriter{
+				writer:     w,
+				isJSON:     r.Header.Get("Accept") == "application/json",
+				escapeHTML: escapeProgressHTML(r),
+			}
+			if _, err := progressWriter.Write([]byte(line)); err != nil {
+				t.Fatalf("Failed to write progress: %v", err)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("Expected %q, got %q", tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleGetModelVerbose(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	client := reg.NewClient(reg.WithPlainHTTP(true))
+	target, err := client.NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		Transport:     http.DefaultTransport,
+		UserAgent:     "test-agent",
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	getConfig := func(query string) map[string]json.RawMessage {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tag+query, http.NoBody)
+		r.SetPathValue("name", tag)
+		w := httptest.NewRecorder()
+		handler.handleGetModel(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response struct {
+			Config map[string]json.RawMessage `json:"config"`
+		}
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		return response.Config
+	}
+
+	concise := getConfig("")
+	if _, ok := concise["gguf"]; ok {
+		t.Errorf("Expected concise view to omit the GGUF map, got %s", concise["gguf"])
+	}
+	for _, field := range []string{"format", "parameters", "quantization", "architecture", "size"} {
+		if _, ok := concise[field]; !ok {
+			t.Errorf("Expected concise view to include %q", field)
+		}
+	}
+
+	verbose := getConfig("?verbose=true")
+	if len(verbose["gguf"]) == 0 {
+		t.Error("Expected verbose view to include the GGUF map")
+	}
+}
+
+func TestHandleGetModelByManifestDigest(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// The digest the registry serves the manifest under, and the digest of
+	// the manifest as stored, identify the same model.
+	remote, err := manager.GetRemote(t.Context(), tag)
+	if err != nil {
+		t.Fatalf("Failed to get remote model: %v", err)
+	}
+	remoteDigest, err := remote.Digest()
+	if err != nil {
+		t.Fatalf("Failed to get remote manifest digest: %v", err)
+	}
+	raw, _, err := manager.GetRawManifest(tag)
+	if err != nil {
+		t.Fatalf("Failed to get stored manifest: %v", err)
+	}
+	manifestDigest := digest.FromBytes(raw).String()
+	if manifestDigest != remoteDigest.String() {
+		t.Fatalf("Expected stored manifest digest %s to match remote digest %s", manifestDigest, remoteDigest)
+	}
+	otherDigest := digest.FromString("other").String()
+
+	tests := []struct {
+		name         string
+		ref          string
+		expectedCode int
+	}{
+		{name: "with registry and org", ref: uri.Host + "/ai/model@" + manifestDigest, expectedCode: http.StatusOK},
+		{name: "with org", ref: "ai/model@" + manifestDigest, expectedCode: http.StatusOK},
+		{name: "name only", ref: "model@" + manifestDigest, expectedCode: http.StatusOK},
+		{name: "tag and digest", ref: tag + "@" + manifestDigest, expectedCode: http.StatusOK},
+		{name: "other digest", ref: uri.Host + "/ai/model@" + otherDigest, expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tt.ref, http.NoBody)
+			r.SetPathValue("name", tt.ref)
+			w := httptest.NewRecorder()
+			handler.handleGetModel(w, r)
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+			if tt.expectedCode != http.StatusOK {
+				return
+			}
+			var response Model
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+			if response.ID != manifestDigest {
+				t.Errorf("Expected model %s, got %s", manifestDigest, response.ID)
+			}
+		})
+	}
+}
+
 func TestCors(t *testing.T) {
 	t.Parallel()
 
-	tempDir := t.TempDir()
+	tempDir := t.TempDir()
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{
+			name: "root",
+			path: "/",
+		},
+		{
+			name: "list models",
+			path: "/models/list",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			t.Parallel()
+			log := slog.Default()
+			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+				StoreRootPath: tempDir,
+				Logger:        log.With("component", "model-manager"),
+			})
+			m := NewHTTPHandler(log, manager, []string{"*"})
+			req := httptest.NewRequest(http.MethodOptions, "http://model-runner.docker.internal"+tt.path, http.NoBody)
+			req.Header.Set("Origin", "docker.com")
+			w := httptest.NewRecorder()
+			m.ServeHTTP(w, req)
+
+			if w.Code != http.StatusNoContent {
+				t.Errorf("Expected status code 204 for OPTIONS request, got %d", w.Code)
+			}
+		})
+	}
+}
+
+func TestHandleGetModelAnnotations(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	annotations := map[string]string{
+		types.AnnotationSource:    "https://example.com/dummy",
+		types.AnnotationLicenses:  "MIT",
+		types.AnnotationBuildTool: "test-builder/1.0",
+	}
+	model = model.WithAnnotations(annotations)
+	tag := uri.Host + "/ai/model:annotated"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		Transport:     http.DefaultTransport,
+		UserAgent:     "test-agent",
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	getAnnotations := func(query string) map[string]string {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tag+query, http.NoBody)
+		r.SetPathValue("name", tag)
+		w := httptest.NewRecorder()
+		handler.handleGetModel(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response Model
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		return response.Annotations
+	}
+
+	if got := getAnnotations("?remote=true"); !maps.Equal(got, annotations) {
+		t.Errorf("Expected remote annotations %v, got %v", annotations, got)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	if got := getAnnotations(""); !maps.Equal(got, annotations) {
+		t.Errorf("Expected local annotations %v, got %v", annotations, got)
+	}
+}
+
+func TestHandleGetModelLayers(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	ggufPath := filepath.Join(getProjectRoot(t), "assets", "dummy.gguf")
+	data, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	// Give the projector distinct contents so that it gets its own blob.
+	mmproj := append(slices.Clone(data), 0)
+	mmprojPath := filepath.Join(t.TempDir(), "mmproj.gguf")
+	if err := os.WriteFile(mmprojPath, mmproj, 0o644); err != nil {
+		t.Fatalf("Failed to write multimodal projector: %v", err)
+	}
+
+	model, err := builder.FromPath(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	model, err = model.WithMultimodalProjector(mmprojPath)
+	if err != nil {
+		t.Fatalf("Failed to add multimodal projector: %v", err)
+	}
+	tag := uri.Host + "/ai/model:multimodal"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	want := []LayerInfo{
+		{MediaType: string(types.MediaTypeGGUF), Size: int64(len(data)), Filepath: "dummy.gguf"},
+		{MediaType: string(types.MediaTypeMultimodalProjector), Size: int64(len(mmproj)), Filepath: "mmproj.gguf"},
+	}
+	checkLayers := func(query string) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tag+query, http.NoBody)
+		r.SetPathValue("name", tag)
+		w := httptest.NewRecorder()
+		handler.handleGetModel(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var response Model
+		if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode response body: %v", err)
+		}
+		if len(response.Layers) != len(want) {
+			t.Fatalf("Expected %d layers, got %+v", len(want), response.Layers)
+		}
+		for i, layer := range response.Layers {
+			if !strings.HasPrefix(layer.Digest, "sha256:") {
+				t.Errorf("Expected layer %d to have a digest, got %q", i, layer.Digest)
+			}
+			layer.Digest = ""
+			if layer != want[i] {
+				t.Errorf("Expected layer %d to be %+v, got %+v", i, want[i], layer)
+			}
+		}
+	}
+
+	checkLayers("?remote=true")
+
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	checkLayers("")
+}
+
+func TestHandleDropLayer(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	ggufPath := filepath.Join(getProjectRoot(t), "assets", "dummy.gguf")
+	data, err := os.ReadFile(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to read model file: %v", err)
+	}
+	// Give the projector distinct contents so that it gets its own blob.
+	mmprojPath := filepath.Join(t.TempDir(), "mmproj.gguf")
+	if err := os.WriteFile(mmprojPath, append(slices.Clone(data), 0), 0o644); err != nil {
+		t.Fatalf("Failed to write multimodal projector: %v", err)
+	}
+
+	model, err := builder.FromPath(ggufPath)
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	model, err = model.WithMultimodalProjector(mmprojPath)
+	if err != nil {
+		t.Fatalf("Failed to add multimodal projector: %v", err)
+	}
+	tag := uri.Host + "/ai/model:multimodal"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	originalID := manager.ResolveID(tag)
+
+	original, err := manager.GetLocal(tag)
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	mmprojBlob, err := original.MMPROJPath()
+	if err != nil || mmprojBlob == "" {
+		t.Fatalf("Expected the model to have a multimodal projector, got %q (%v)", mmprojBlob, err)
+	}
+
+	dropLayer := func(ref, mediaType string) *httptest.ResponseRecorder {
+		t.Helper()
+		query := url.Values{}
+		if mediaType != "" {
+			query.Set("media_type", mediaType)
+		}
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+ref+"/drop-layer?"+query.Encode(), http.NoBody)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := dropLayer(tag, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected a missing media type to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := dropLayer(tag, string(types.MediaTypeGGUF)); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected dropping the GGUF layer to be rejected, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := dropLayer(tag, string(types.MediaTypeChatTemplate)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected dropping an absent layer to fail with not found, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := dropLayer(tag, string(types.MediaTypeMultimodalProjector))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var response ModelDropLayerResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if response.ID == "" || response.ID == originalID {
+		t.Errorf("Expected the rewritten model to get a new ID, got %q", response.ID)
+	}
+
+	dropped, err := manager.GetLocal(tag)
+	if err != nil {
+		t.Fatalf("Expected the tag to move to the rewritten model, got %v", err)
+	}
+	if id, _ := dropped.ID(); id != response.ID {
+		t.Errorf("Expected %s to point to %s, got %s", tag, response.ID, id)
+	}
+	bundle, err := manager.GetBundle(tag)
+	if err != nil {
+		t.Fatalf("Failed to get bundle: %v", err)
+	}
+	if path := bundle.MMPROJPath(); path != "" {
+		t.Errorf("Expected no multimodal projector, got %q", path)
+	}
+	if bundle.GGUFPath() == "" {
+		t.Error("Expected the bundle to still have a GGUF file")
+	}
+	paths, err := dropped.GGUFPaths()
+	if err != nil || len(paths) != 1 {
+		t.Fatalf("Expected the GGUF file to still resolve, got %v (%v)", paths, err)
+	}
+	if _, err := os.Stat(paths[0]); err != nil {
+		t.Errorf("Expected the GGUF blob to survive, got %v", err)
+	}
+	if _, err := os.Stat(mmprojBlob); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the orphaned projector blob to be removed, got %v", err)
+	}
+	if _, err := manager.GetLocal(originalID); !errors.Is(err, distribution.ErrModelNotFound) {
+		t.Errorf("Expected the original model to be deleted, got %v", err)
+	}
+
+	if w := dropLayer(tag, string(types.MediaTypeMultimodalProjector)); w.Code != http.StatusNotFound {
+		t.Errorf("Expected dropping the projector again to fail with not found, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestNewManagerRepairsStore(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/ai/model:latest"
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	config := ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	}
+	manager := NewManager(log.With("component", "model-manager"), config)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	// Leave behind what a crash during a pull would: a blob that no model
+	// references and a stale incomplete download.
+	blobsDir := filepath.Join(config.StoreRootPath, "blobs", "sha256")
+	orphan := filepath.Join(blobsDir, strings.Repeat("a", 64))
+	incomplete := filepath.Join(blobsDir, strings.Repeat("b", 64)+".incomplete")
+	for _, path := range []string{orphan, incomplete} {
+		if err := os.WriteFile(path, []byte("partial"), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+	}
+	stale := time.Now().Add(-2 * distribution.StaleDownloadAge)
+	if err := os.Chtimes(incomplete, stale, stale); err != nil {
+		t.Fatalf("Failed to age incomplete download: %v", err)
+	}
+
+	config.RepairStore = true
+	manager = NewManager(log.With("component", "model-manager"), config)
+
+	for _, path := range []string{orphan, incomplete} {
+		if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Expected %s to be removed, got %v", path, err)
+		}
+	}
+	if _, err := manager.GetLocal(tag); err != nil {
+		t.Errorf("Expected the pulled model to survive the repair: %v", err)
+	}
+}
+
+func TestHandleVerifyModel(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/ai/model:latest"
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	config := ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	}
+	manager := NewManager(log.With("component", "model-manager"), config)
+	handler := NewHTTPHandler(log, manager, nil)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	verify := func(ref string) (int, distribution.VerifyModelResponse) {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/"+ref+"/verify", http.NoBody))
+		var report distribution.VerifyModelResponse
+		if w.Code == http.StatusOK {
+			if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+				t.Fatalf("Failed to decode response body: %v", err)
+			}
+		}
+		return w.Code, report
+	}
+
+	code, report := verify(tag)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+	}
+	if len(report.Verified) == 0 || len(report.Corrupt) != 0 || len(report.Missing) != 0 {
+		t.Fatalf("Expected every blob to verify, got %+v", report)
+	}
+
+	// Corrupt one blob and remove another.
+	blobPath := func(digest string) string {
+		return filepath.Join(config.StoreRootPath, "blobs", "sha256", strings.TrimPrefix(digest, "sha256:"))
+	}
+	corrupt := report.Verified[0]
+	if err := os.WriteFile(blobPath(corrupt), []byte("corrupt"), 0o644); err != nil {
+		t.Fatalf("Failed to corrupt blob: %v", err)
+	}
+	missing := report.Verified[len(report.Verified)-1]
+	if err := os.Remove(blobPath(missing)); err != nil {
+		t.Fatalf("Failed to remove blob: %v", err)
+	}
+
+	code, report = verify(tag)
+	if code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, code)
+	}
+	if len(report.Corrupt) != 1 || report.Corrupt[0].Digest != corrupt {
+		t.Errorf("Expected %s to be reported corrupt, got %+v", corrupt, report.Corrupt)
+	}
+	if len(report.Missing) != 1 || report.Missing[0] != missing {
+		t.Errorf("Expected %s to be reported missing, got %v", missing, report.Missing)
+	}
+
+	if code, _ := verify("unknown"); code != http.StatusNotFound {
+		t.Errorf("Expected status code %d for an unknown model, got %d", http.StatusNotFound, code)
+	}
+}
+
+func TestHandleCreateModelMaxModelBytes(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	modelPath := filepath.Join(projectRoot, "assets", "dummy.gguf")
+	info, err := os.Stat(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to stat model file: %v", err)
+	}
+	modelSize := info.Size()
+
+	model, err := builder.FromPath(modelPath)
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:sized"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	override := func(n int64) *int64 { return &n }
+
+	tests := []struct {
+		name          string
+		maxModelBytes int64
+		allowOverride bool
+		requestLimit  *int64
+		expectedCode  int
+	}{
+		{
+			name:         "no limit",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:          "below limit",
+			maxModelBytes: modelSize,
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "above limit",
+			maxModelBytes: modelSize - 1,
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:          "override not allowed",
+			maxModelBytes: modelSize - 1,
+			requestLimit:  override(0),
+			expectedCode:  http.StatusForbidden,
+		},
+		{
+			name:          "override lifts limit",
+			maxModelBytes: modelSize - 1,
+			allowOverride: true,
+			requestLimit:  override(0),
+			expectedCode:  http.StatusOK,
+		},
+		{
+			name:          "override lowers limit",
+			allowOverride: true,
+			requestLimit:  override(modelSize - 1),
+			expectedCode:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := slog.Default()
+			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+				StoreRootPath:              t.TempDir(),
+				Logger:                     log.With("component", "model-manager"),
+				Transport:                  http.DefaultTransport,
+				UserAgent:                  "test-agent",
+				PlainHTTP:                  true,
+				MaxModelBytes:              tt.maxModelBytes,
+				AllowMaxModelBytesOverride: tt.allowOverride,
+			})
+			handler := NewHTTPHandler(log, manager, nil)
+
+			body, err := json.Marshal(ModelCreateRequest{From: tag, MaxModelBytes: tt.requestLimit})
+			if err != nil {
+				t.Fatalf("Failed to marshal request: %v", err)
+			}
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(string(body)))
+			w := httptest.NewRecorder()
+			handler.handleCreateModel(w, r)
+
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+
+			inStore, err := manager.InStore(tag)
+			if err != nil {
+				t.Fatalf("Failed to check store: %v", err)
+			}
+			if inStore != (tt.expectedCode == http.StatusOK) {
+				t.Errorf("Expected model in store to be %t, got %t", tt.expectedCode == http.StatusOK, inStore)
+			}
+		})
+	}
+}
+
+// rewriteTransport sends every request to the server at host.
+type rewriteTransport struct {
+	host string
+}
+
+func (t rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = "http"
+	req.URL.Host = t.host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestHandleCreateModelMaxModelBytesHuggingFace(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/models/org/model/tree/main" {
+			t.Errorf("Expected nothing to be downloaded, got a request for %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode([]map[string]any{
+			{"type": "file", "path": "model.gguf", "size": 1 << 30},
+		})
+	}))
+	defer server.Close()
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse server URL: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		Transport:     rewriteTransport{host: uri.Host},
+		MaxModelBytes: 1 << 20,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "hf.co/org/model"}`))
+	w := httptest.NewRecorder()
+	handler.handleCreateModel(w, r)
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+}
+
+func TestHandleCreateModelUnsupportedArchitecture(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:arch"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+	config, err := model.Model().Config()
+	if err != nil {
+		t.Fatalf("Failed to read model config: %v", err)
+	}
+	architecture := config.GetArchitecture()
+	if architecture == "" {
+		t.Fatal("Expected the test model to record an architecture")
+	}
+
+	tests := []struct {
+		name          string
+		architectures []string
+		wantWarning   bool
+	}{
+		{name: "supported", architectures: []string{"qwen3", architecture}},
+		{name: "unsupported", architectures: []string{"qwen3", "gemma3"}, wantWarning: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := slog.Default()
+			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+				StoreRootPath: t.TempDir(),
+				Logger:        log.With("component", "model-manager"),
+				PlainHTTP:     true,
+			})
+			handler := NewHTTPHandler(log, manager, nil)
+			handler.SetArchitectureSupport(func(format types.Format, arch string) bool {
+				return format != types.FormatGGUF || slices.Contains(tt.architectures, arch)
+			})
+
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+			r.Header.Set("Accept", "application/json")
+			w := httptest.NewRecorder()
+			handler.handleCreateModel(w, r)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+			}
+
+			var warnings []string
+			for _, line := range strings.Split(strings.TrimSpace(w.Body.String()), "\n") {
+				var msg oci.ProgressMessage
+				if err := json.Unmarshal([]byte(line), &msg); err != nil {
+					t.Fatalf("Failed to decode progress line %q: %v", line, err)
+				}
+				if msg.Type == oci.TypeWarning {
+					warnings = append(warnings, msg.Message)
+				}
+			}
+			if !tt.wantWarning {
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !strings.Contains(warnings[0], fmt.Sprintf("%q is not supported", architecture)) {
+				t.Errorf("Expected an unsupported architecture warning, got %v", warnings)
+			}
+		})
+	}
+}
+
+func TestHandleCreateModelPreflight(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:preflight"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+	config, err := model.Model().Config()
+	if err != nil {
+		t.Fatalf("Failed to read model config: %v", err)
+	}
+	architecture := config.GetArchitecture()
+
+	tests := []struct {
+		name          string
+		architectures []string
+		wantCode      int
+	}{
+		{name: "supported", architectures: []string{architecture}, wantCode: http.StatusOK},
+		{name: "unsupported", architectures: []string{"gemma3"}, wantCode: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storeRoot := t.TempDir()
+			log := slog.Default()
+			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+				StoreRootPath:  storeRoot,
+				Logger:         log.With("component", "model-manager"),
+				PlainHTTP:      true,
+				PreflightPulls: true,
+			})
+			handler := NewHTTPHandler(log, manager, nil)
+			handler.SetArchitectureSupport(func(format types.Format, arch string) bool {
+				return format != types.FormatGGUF || slices.Contains(tt.architectures, arch)
+			})
+
+			got, err := manager.RemoteGGUFArchitecture(t.Context(), tag, "", false)
+			if err != nil {
+				t.Fatalf("Failed to read remote GGUF header: %v", err)
+			}
+			if got != architecture {
+				t.Fatalf("Expected remote architecture %q, got %q", architecture, got)
+			}
+
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+			w := httptest.NewRecorder()
+			handler.handleCreateModel(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode == http.StatusOK {
+				return
+			}
+			if !strings.Contains(w.Body.String(), architecture) {
+				t.Errorf("Expected the rejection to name architecture %q, got %s", architecture, w.Body.String())
+			}
+
+			// Nothing was downloaded.
+			blobs, err := os.ReadDir(filepath.Join(storeRoot, "blobs", "sha256"))
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				t.Fatalf("Failed to read blobs directory: %v", err)
+			}
+			if len(blobs) != 0 {
+				t.Errorf("Expected no blobs to be written, got %d", len(blobs))
+			}
+			if models, err := manager.List(); err != nil || len(models) != 0 {
+				t.Errorf("Expected no models, got %d (%v)", len(models), err)
+			}
+		})
+	}
+}
+
+func TestPullEvictsLeastRecentlyUsedModels(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	// The models share their weights and differ only by license, so each one
+	// adds a distinct license blob to the store.
+	projectRoot := getProjectRoot(t)
+	var tags []string
+	for _, name := range []string{"first", "second", "third", "fourth"} {
+		model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+		if err != nil {
+			t.Fatalf("Failed to create model builder: %v", err)
+		}
+		licensePath := filepath.Join(t.TempDir(), name+".txt")
+		if err := os.WriteFile(licensePath, []byte(strings.Repeat(name, 4096)), 0644); err != nil {
+			t.Fatalf("Failed to write license: %v", err)
+		}
+		model, err = model.WithLicense(licensePath)
+		if err != nil {
+			t.Fatalf("Failed to add license to model: %v", err)
+		}
+		tag := uri.Host + "/ai/" + name + ":latest"
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to build model: %v", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	// Measure the store with two models in it and use that as the cap.
+	log := slog.Default()
+	measureRoot := t.TempDir()
+	measure := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: measureRoot,
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	pull := func(manager *Manager, tag string) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+		w := httptest.NewRecorder()
+		if err := manager.Pull(ModelCreateRequest{From: tag}, r, w); err != nil {
+			t.Fatalf("Failed to pull %s: %v", tag, err)
+		}
+	}
+	pull(measure, tags[0])
+	pull(measure, tags[1])
+	var maxStoreBytes int64
+	err = filepath.WalkDir(filepath.Join(measureRoot, "blobs"), func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		maxStoreBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Failed to measure store: %v", err)
+	}
+
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+		MaxStoreBytes: maxStoreBytes,
+	})
+	assertInStore := func(want ...bool) {
+		t.Helper()
+		for i, tag := range tags {
+			inStore, err := manager.InStore(tag)
+			if err != nil {
+				t.Fatalf("Failed to check store: %v", err)
+			}
+			if inStore != want[i] {
+				t.Errorf("Expected %s in store to be %t, got %t", tag, want[i], inStore)
+			}
+		}
+	}
+
+	pull(manager, tags[0])
+	pull(manager, tags[1])
+	assertInStore(true, true, false, false)
+
+	// Using the first model makes the second one the least recently used.
+	if _, err := manager.GetBundle(tags[0]); err != nil {
+		t.Fatalf("Failed to get bundle: %v", err)
+	}
+	pull(manager, tags[2])
+	assertInStore(true, false, true, false)
+
+	// A locked model is retained even when it is the least recently used.
+	first, err := manager.GetLocal(tags[0])
+	if err != nil {
+		t.Fatalf("Failed to get model: %v", err)
+	}
+	firstID, err := first.ID()
+	if err != nil {
+		t.Fatalf("Failed to get model ID: %v", err)
+	}
+	manager.LockModel(firstID)
+	defer manager.UnlockModel(firstID)
+	pull(manager, tags[3])
+	assertInStore(true, false, false, true)
+}
+
+func TestModelLastUsed(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:used"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	storeRoot := t.TempDir()
+	newHandler := func() *HTTPHandler {
+		manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+			StoreRootPath: storeRoot,
+			Logger:        log.With("component", "model-manager"),
+			PlainHTTP:     true,
+		})
+		return NewHTTPHandler(log, manager, nil)
+	}
+	getModel := func(handler *HTTPHandler) Model {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+tag, http.NoBody)
+		r.SetPathValue("name", tag)
+		w := httptest.NewRecorder()
+		handler.handleGetModel(w, r)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+		}
+		var apiModel Model
+		if err := json.NewDecoder(w.Body).Decode(&apiModel); err != nil {
+			t.Fatalf("Failed to decode response: %v", err)
+		}
+		return apiModel
+	}
+
+	handler := newHandler()
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	w := httptest.NewRecorder()
+	if err := handler.manager.Pull(ModelCreateRequest{From: tag}, r, w); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+
+	before := time.Now().Unix()
+	if err := handler.manager.MarkUsed(tag); err != nil {
+		t.Fatalf("Failed to mark model used: %v", err)
+	}
+	after := time.Now().Unix()
+
+	lastUsed := getModel(handler).LastUsed
+	if lastUsed < before || lastUsed > after {
+		t.Errorf("Expected last used between %d and %d, got %d", before, after, lastUsed)
+	}
+	models, err := handler.manager.List()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	if len(models) != 1 || models[0].LastUsed != lastUsed {
+		t.Errorf("Expected listed model to be last used at %d, got %+v", lastUsed, models)
+	}
+
+	// The timestamp is persisted in the store and survives a restart.
+	if got := getModel(newHandler()).LastUsed; got != lastUsed {
+		t.Errorf("Expected last used %d after restart, got %d", lastUsed, got)
+	}
+
+	if err := handler.manager.MarkUsed("nonexistent:v1"); err == nil {
+		t.Error("Expected an error marking an unknown model used")
+	}
+}
+
+func TestHandleRequestValidation(t *testing.T) {
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	tests := []struct {
+		name          string
+		handle        func(w http.ResponseWriter, r *http.Request)
+		body          string
+		expectedError string
+	}{
+		{
+			name:          "create - empty body",
+			handle:        handler.handleCreateModel,
+			body:          "",
+			expectedError: "invalid request body: request body is empty",
+		},
+		{
+			name:          "create - malformed JSON",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model"`,
+			expectedError: "invalid request body: malformed JSON: unexpected end of input",
+		},
+		{
+			name:          "create - not an object",
+			handle:        handler.handleCreateModel,
+			body:          `["ai/model"]`,
+			expectedError: "invalid request body: request body must be a JSON object",
+		},
+		{
+			name:          "create - unknown field",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model", "frm": "ai/other"}`,
+			expectedError: `invalid request body: unknown field "frm"`,
+		},
+		{
+			name:          "create - wrong type",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": 42}`,
+			expectedError: `invalid request body: field "from" must be a string, got number`,
+		},
+		{
+			name:          "create - wrong boolean type",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model", "force": "yes"}`,
+			expectedError: `invalid request body: field "force" must be a boolean, got string`,
+		},
+		{
+			name:          "create - missing from",
+			handle:        handler.handleCreateModel,
+			body:          `{"force": true}`,
+			expectedError: `invalid request body: field "from" is required`,
+		},
+		{
+			name:          "create - negative max model bytes",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model", "max-model-bytes": -1}`,
+			expectedError: `invalid request body: field "max-model-bytes" must not be negative`,
+		},
+		{
+			name:          "create - digest alias",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model", "as": "ai/alias@sha256:` + strings.Repeat("a", 64) + `"}`,
+			expectedError: `invalid request body: field "as" must be a tag reference: reference "ai/alias@sha256:` + strings.Repeat("a", 64) + `" is not a tag`,
+		},
+		{
+			name:          "create - invalid raw reference",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/Model", "raw-reference": true}`,
+			expectedError: `invalid request body: field "from" must be a valid reference: invalid reference "ai/Model": invalid reference format: repository name (ai/Model) must be lowercase`,
+		},
+		{
+			name:          "create - trailing data",
+			handle:        handler.handleCreateModel,
+			body:          `{"from": "ai/model"} {"from": "ai/other"}`,
+			expectedError: "invalid request body: unexpected data after the JSON object",
+		},
+		{
+			name: "repackage - missing target",
+			handle: func(w http.ResponseWriter, r *http.Request) {
+				handler.handleRepackageModel(w, r, "ai/model")
+			},
+			body:          `{"context_size": 4096}`,
+			expectedError: `invalid request body: field "target" is required`,
+		},
+		{
+			name: "repackage - negative context size",
+			handle: func(w http.ResponseWriter, r *http.Request) {
+				handler.handleRepackageModel(w, r, "ai/model")
+			},
+			body:          `{"target": "ai/model:4k", "context_size": -1}`,
+			expectedError: `invalid request body: field "context_size" must be a non-negative integer, got number -1`,
+		},
+		{
+			name: "repackage - zero context size",
+			handle: func(w http.ResponseWriter, r *http.Request) {
+				handler.handleRepackageModel(w, r, "ai/model")
+			},
+			body:          `{"target": "ai/model:4k", "context_size": 0}`,
+			expectedError: `invalid request body: field "context_size" must be between 1 and 2147483647`,
+		},
+		{
+			name: "repackage - unknown field",
+			handle: func(w http.ResponseWriter, r *http.Request) {
+				handler.handleRepackageModel(w, r, "ai/model")
+			},
+			body:          `{"target": "ai/model:4k", "ctx_size": 4096}`,
+			expectedError: `invalid request body: unknown field "ctx_size"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(tt.body))
+			w := httptest.NewRecorder()
+			tt.handle(w, r)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status code %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+			}
+			if got := strings.TrimSpace(w.Body.String()); got != tt.expectedError {
+				t.Errorf("Expected error %q, got %q", tt.expectedError, got)
+			}
+		})
+	}
+}
+
+func TestHandleGetRawManifestAndConfig(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	license, err := model.WithLicense(filepath.Join(projectRoot, "assets", "license.txt"))
+	if err != nil {
+		t.Fatalf("Failed to add license to model: %v", err)
+	}
+	// The second tag names a model whose name ends in an action.
+	tag := uri.Host + "/ai/model:raw"
+	actionNamedTag := uri.Host + "/ai/config:latest"
+	for _, ref := range []string{tag, actionNamedTag} {
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(ref)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := license.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to build model: %v", err)
+		}
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	for _, ref := range []string{tag, actionNamedTag} {
+		r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+ref+`"}`))
+		w := httptest.NewRecorder()
+		if err := manager.Pull(ModelCreateRequest{From: ref}, r, w); err != nil {
+			t.Fatalf("Failed to pull %s: %v", ref, err)
+		}
+	}
+	get := func(nameAndAction string) *httptest.ResponseRecorder {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+nameAndAction, http.NoBody)
+		r.SetPathValue("nameAndAction", nameAndAction)
+		w := httptest.NewRecorder()
+		handler.handleModelGetAction(w, r)
+		return w
+	}
+
+	w := get(tag + "/manifest")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var manifest oci.Manifest
+	if err := json.Unmarshal(w.Body.Bytes(), &manifest); err != nil {
+		t.Fatalf("Failed to parse manifest: %v", err)
+	}
+	if got := w.Header().Get("Content-Type"); got != string(oci.OCIManifestSchema1) {
+		t.Errorf("Expected content type %q, got %q", oci.OCIManifestSchema1, got)
+	}
+	if len(manifest.Layers) != 2 {
+		t.Errorf("Expected 2 layers (gguf, license), got %d", len(manifest.Layers))
+	}
+	rawManifest, _, err := manager.GetRawManifest(tag)
+	if err != nil {
+		t.Fatalf("Failed to get raw manifest: %v", err)
+	}
+	if !slices.Equal(w.Body.Bytes(), rawManifest) {
+		t.Error("Expected the manifest to be returned unmodified")
+	}
+
+	w = get(tag + "/config")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != string(manifest.Config.MediaType) {
+		t.Errorf("Expected content type %q, got %q", manifest.Config.MediaType, got)
+	}
+	var config types.ConfigFile
+	if err := json.Unmarshal(w.Body.Bytes(), &config); err != nil {
+		t.Fatalf("Failed to parse config: %v", err)
+	}
+	if config.Config.Format != types.FormatGGUF {
+		t.Errorf("Expected format %q, got %q", types.FormatGGUF, config.Config.Format)
+	}
+	if int64(w.Body.Len()) != manifest.Config.Size {
+		t.Errorf("Expected %d config bytes, got %d", manifest.Config.Size, w.Body.Len())
+	}
+
+	// A model named like an action is still inspected.
+	w = get(uri.Host + "/ai/config")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var apiModel Model
+	if err := json.Unmarshal(w.Body.Bytes(), &apiModel); err != nil {
+		t.Fatalf("Failed to parse model: %v", err)
+	}
+	if !slices.Contains(apiModel.Tags, actionNamedTag) {
+		t.Errorf("Expected model tagged %s, got tags %v", actionNamedTag, apiModel.Tags)
+	}
+
+	if w := get("nonexistent:v1/manifest"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d: %s", http.StatusNotFound, w.Code, w.Body.String())
+	}
+}
+
+func TestHandlerSpans(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(t.Context())
+	})
+
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:traced"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	// The pull continues the trace propagated by the client.
+	ctx, client := tracing.Start(t.Context(), tracing.SpanPull, tag, trace.SpanKindClient)
+	r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`))
+	tracing.Inject(ctx, r.Header)
+	w := httptest.NewRecorder()
+	handler.handleCreateModel(w, r)
+	client.End(nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var archive bytes.Buffer
+	if err := manager.Export(tag, &archive); err != nil {
+		t.Fatalf("Failed to export model: %v", err)
+	}
+	archiveSize := int64(archive.Len())
+	w = httptest.NewRecorder()
+	handler.handleLoadModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/load", &archive))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	// Registry clients may record spans of their own; keep only ours.
+	var spans tracetest.SpanStubs
+	for _, span := range exporter.GetSpans() {
+		if strings.HasPrefix(span.Name, "model.") {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) != 3 {
+		t.Fatalf("Expected 3 spans, got %d", len(spans))
+	}
+	attrs := func(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+		m := make(map[attribute.Key]attribute.Value)
+		for _, kv := range span.Attributes {
+			m[kv.Key] = kv.Value
+		}
+		return m
+	}
+
+	pull, clientSpan, load := spans[0], spans[1], spans[2]
+	if pull.Name != tracing.SpanPull || pull.SpanKind != trace.SpanKindServer {
+		t.Errorf("Expected a %s server span, got %s (%v)", tracing.SpanPull, pull.Name, pull.SpanKind)
+	}
+	if pull.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Errorf("Expected the pull span to be a child of the client span")
+	}
+	pullAttrs := attrs(pull)
+	if got := pullAttrs[tracing.AttrModel].AsString(); got != tag {
+		t.Errorf("Expected %s %q, got %q", tracing.AttrModel, tag, got)
+	}
+	if got := pullAttrs[tracing.AttrBytes].AsInt64(); got <= 0 {
+		t.Errorf("Expected a positive %s, got %d", tracing.AttrBytes, got)
+	}
+	if _, ok := pullAttrs[tracing.AttrDuration]; !ok {
+		t.Errorf("Expected %s to be recorded", tracing.AttrDuration)
+	}
+
+	if load.Name != tracing.SpanLoad {
+		t.Errorf("Expected a %s span, got %s", tracing.SpanLoad, load.Name)
+	}
+	if got := attrs(load)[tracing.AttrBytes].AsInt64(); got != archiveSize {
+		t.Errorf("Expected %s %d, got %d", tracing.AttrBytes, archiveSize, got)
+	}
+}
+
+func TestHandleLoadModelMaxLoadBytes(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	projectRoot := getProjectRoot(t)
+	model, err := builder.FromPath(filepath.Join(projectRoot, "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:load"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	source := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	w := httptest.NewRecorder()
+	NewHTTPHandler(log, source, nil).handleCreateModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var archive bytes.Buffer
+	if err := source.Export(tag, &archive); err != nil {
+		t.Fatalf("Failed to export model: %v", err)
+	}
+
+	storeRoot := t.TempDir()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: storeRoot,
+		Logger:        log.With("component", "model-manager"),
+		MaxLoadBytes:  int64(archive.Len()) / 2,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	w = httptest.NewRecorder()
+	handler.handleLoadModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/load", &archive))
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusRequestEntityTooLarge, w.Code, w.Body.String())
+	}
+
+	models, err := manager.List()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	if len(models) != 0 {
+		t.Errorf("Expected no models after the rejected load, got %d", len(models))
+	}
+	err = filepath.WalkDir(storeRoot, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && strings.Contains(path, "blobs") {
+			t.Errorf("Expected no blobs after the rejected load, found %s", path)
+		}
+		return err
+	})
+	if err != nil {
+		t.Fatalf("Failed to walk store: %v", err)
+	}
+}
+
+func TestHandleLoadModelFromURL(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:load"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	source := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	w := httptest.NewRecorder()
+	NewHTTPHandler(log, source, nil).handleCreateModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tag+`"}`)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var archive bytes.Buffer
+	if err := source.Export(tag, &archive); err != nil {
+		t.Fatalf("Failed to export model: %v", err)
+	}
+	archiveDigest := digest.FromBytes(archive.Bytes())
+
+	archives := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/model.tar" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(archive.Bytes())
+	}))
+	defer archives.Close()
 
 	tests := []struct {
-		name string
-		path string
+		name       string
+		query      url.Values
+		wantStatus int
 	}{
 		{
-			name: "root",
-			path: "/",
+			name:       "no digest",
+			query:      url.Values{"url": {archives.URL + "/model.tar"}},
+			wantStatus: http.StatusOK,
 		},
 		{
-			name: "list models",
-			path: "/models/list",
+			name:       "matching digest",
+			query:      url.Values{"url": {archives.URL + "/model.tar"}, "digest": {archiveDigest.String()}},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "mismatching digest",
+			query:      url.Values{"url": {archives.URL + "/model.tar"}, "digest": {digest.FromString("other").String()}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "invalid digest",
+			query:      url.Values{"url": {archives.URL + "/model.tar"}, "digest": {"sha256:invalid"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "unsupported scheme",
+			query:      url.Values{"url": {"file:///model.tar"}},
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing archive",
+			query:      url.Values{"url": {archives.URL + "/missing.tar"}},
+			wantStatus: http.StatusBadGateway,
 		},
 	}
 
 	for _, tt := range tests {
-		t.Run(tt.path, func(t *testing.T) {
-			t.Parallel()
-			log := slog.Default()
+		t.Run(tt.name, func(t *testing.T) {
+			storeRoot := t.TempDir()
 			manager := NewManager(log.With("component", "model-manager"), ClientConfig{
-				StoreRootPath: tempDir,
-				Logger:        log.With("component", "model-manager"),
+				StoreRootPath:        storeRoot,
+				Logger:               log.With("component", "model-manager"),
+				AllowPrivateLoadURLs: true,
 			})
-			m := NewHTTPHandler(log, manager, []string{"*"})
-			req := httptest.NewRequest(http.MethodOptions, "http://model-runner.docker.internal"+tt.path, http.NoBody)
-			req.Header.Set("Origin", "docker.com")
+			handler := NewHTTPHandler(log, manager, nil)
+
 			w := httptest.NewRecorder()
-			m.ServeHTTP(w, req)
+			handler.handleLoadModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/load?"+tt.query.Encode(), http.NoBody))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
 
-			if w.Code != http.StatusNoContent {
-				t.Errorf("Expected status code 204 for OPTIONS request, got %d", w.Code)
+			models, err := manager.List()
+			if err != nil {
+				t.Fatalf("Failed to list models: %v", err)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if len(models) != 1 {
+					t.Fatalf("Expected the loaded model, got %d models", len(models))
+				}
+				if _, err := manager.GetBundle(models[0].ID); err != nil {
+					t.Errorf("Expected loaded model to be usable, got %v", err)
+				}
+				return
+			}
+			if len(models) != 0 {
+				t.Errorf("Expected no models after the rejected load, got %d", len(models))
+			}
+			err = filepath.WalkDir(storeRoot, func(path string, d fs.DirEntry, err error) error {
+				if err == nil && !d.IsDir() && strings.Contains(path, "blobs") {
+					t.Errorf("Expected no blobs after the rejected load, found %s", path)
+				}
+				return err
+			})
+			if err != nil {
+				t.Fatalf("Failed to walk store: %v", err)
 			}
 		})
 	}
 }
+
+// redirectTransport answers every request with a redirect to location,
+// recording the URLs it was asked for.
+type redirectTransport struct {
+	location  string
+	requested []string
+}
+
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requested = append(t.requested, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusFound,
+		Header:     http.Header{"Location": {t.location}},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func TestHandleLoadModelPrivateURL(t *testing.T) {
+	var hits atomic.Int32
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer internal.Close()
+
+	log := slog.Default()
+	newHandler := func(transport http.RoundTripper) *HTTPHandler {
+		manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+			StoreRootPath: t.TempDir(),
+			Logger:        log.With("component", "model-manager"),
+			Transport:     transport,
+		})
+		return NewHTTPHandler(log, manager, nil)
+	}
+	load := func(handler *HTTPHandler, source string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		query := url.Values{"url": {source}}
+		handler.handleLoadModel(w, httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/load?"+query.Encode(), http.NoBody))
+		return w
+	}
+
+	t.Run("loopback URL", func(t *testing.T) {
+		w := load(newHandler(nil), internal.URL+"/model.tar")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("redirect to loopback", func(t *testing.T) {
+		// The public address is never dialed: the fake transport redirects
+		// every request to the internal server.
+		transport := &redirectTransport{location: internal.URL + "/model.tar"}
+		w := load(newHandler(transport), "http://203.0.113.7/model.tar")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		if len(transport.requested) != 1 {
+			t.Errorf("Expected only the public URL to be requested, got %v", transport.requested)
+		}
+	})
+
+	t.Run("redirect loop", func(t *testing.T) {
+		transport := &redirectTransport{location: "http://203.0.113.7/model.tar"}
+		w := load(newHandler(transport), "http://203.0.113.7/model.tar")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status code %d, got %d: %s", http.StatusBadRequest, w.Code, w.Body.String())
+		}
+		if len(transport.requested) != maxLoadRedirects {
+			t.Errorf("Expected %d requests, got %d", maxLoadRedirects, len(transport.requested))
+		}
+	})
+
+	if n := hits.Load(); n != 0 {
+		t.Errorf("Expected the internal server not to be contacted, got %d requests", n)
+	}
+}
+
+func TestHandleGetRemoteModelIndex(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	tag := uri.Host + "/ai/model:single"
+	mdl, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := mdl.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	// Publish an index listing the model for one platform under another tag.
+	resp, err := http.Get(server.URL + "/v2/ai/model/manifests/single")
+	if err != nil {
+		t.Fatalf("Failed to fetch manifest: %v", err)
+	}
+	manifest, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	manifestDigest := oci.FromDigest(digest.FromBytes(manifest))
+	index, err := json.Marshal(oci.IndexManifest{
+		SchemaVersion: 2,
+		MediaType:     oci.OCIImageIndex,
+		Manifests: []oci.Descriptor{{
+			MediaType:   oci.OCIManifestSchema1,
+			Size:        int64(len(manifest)),
+			Digest:      manifestDigest,
+			Platform:    &oci.Platform{OS: "linux", Architecture: "arm64"},
+			Annotations: map[string]string{"variant": "Q4_K_M"},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode index: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/v2/ai/model/manifests/multi", bytes.NewReader(index))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", string(oci.OCIImageIndex))
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to push index: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected status code %d pushing the index, got %d", http.StatusCreated, resp.StatusCode)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+
+	ref := uri.Host + "/ai/model:multi"
+	r := httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/"+ref+"?remote=true", http.NoBody)
+	r.SetPathValue("name", ref)
+	w := httptest.NewRecorder()
+	handler.handleGetModel(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+
+	var model Model
+	if err := json.NewDecoder(w.Body).Decode(&model); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if want := oci.FromDigest(digest.FromBytes(index)).String(); model.ID != want {
+		t.Errorf("Expected ID %s, got %s", want, model.ID)
+	}
+	if model.Config != nil {
+		t.Errorf("Expected no config for an index, got %+v", model.Config)
+	}
+	if model.Index == nil {
+		t.Fatal("Expected the response to describe an index")
+	}
+	if model.Index.MediaType != string(oci.OCIImageIndex) {
+		t.Errorf("Expected media type %s, got %s", oci.OCIImageIndex, model.Index.MediaType)
+	}
+	want := []ModelVariant{{
+		Digest:      manifestDigest.String(),
+		MediaType:   string(oci.OCIManifestSchema1),
+		Size:        int64(len(manifest)),
+		Platform:    "linux/arm64",
+		Annotations: map[string]string{"variant": "Q4_K_M"},
+	}}
+	if !reflect.DeepEqual(model.Index.Variants, want) {
+		t.Errorf("Expected variants %+v, got %+v", want, model.Index.Variants)
+	}
+}
+
+func TestPullMetrics(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	metrics := servicemetrics.New()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+		Metrics:       metrics,
+	})
+	pull := func(from string) error {
+		r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+		return manager.Pull(ModelCreateRequest{From: from}, r, httptest.NewRecorder())
+	}
+	if err := pull(tag); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	if err := pull(uri.Host + "/ai/missing:latest"); err == nil {
+		t.Fatal("Expected pull of a missing model to fail")
+	}
+
+	values := map[string]float64{}
+	for _, family := range metrics.Families() {
+		for _, metric := range family.Metric {
+			name := family.GetName()
+			for _, label := range metric.Label {
+				name += "/" + label.GetValue()
+			}
+			values[name] = metric.GetCounter().GetValue() + metric.GetGauge().GetValue()
+		}
+	}
+	if got := values[servicemetrics.PullsTotal+"/"+servicemetrics.PullSucceeded]; got != 1 {
+		t.Errorf("Expected 1 successful pull, got %v", got)
+	}
+	if got := values[servicemetrics.PullsTotal+"/"+servicemetrics.PullFailed]; got != 1 {
+		t.Errorf("Expected 1 failed pull, got %v", got)
+	}
+	if got := values[servicemetrics.ActivePulls]; got != 0 {
+		t.Errorf("Expected no active pulls, got %v", got)
+	}
+	if got := values[servicemetrics.DownloadedBytesTotal]; got <= 0 {
+		t.Errorf("Expected downloaded bytes to be counted, got %v", got)
+	}
+	if got := values[servicemetrics.StoreSizeBytes]; got <= 0 {
+		t.Errorf("Expected store size to be reported, got %v", got)
+	}
+}
+
+func TestManagerShutdown(t *testing.T) {
+	// Create a test registry whose blob downloads never complete once
+	// blocked, and which reports when a download has started.
+	registry := testregistry.New()
+	downloading := make(chan struct{}, 1)
+	var blockDownloads atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if blockDownloads.Load() && r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") {
+			select {
+			case downloading <- struct{}{}:
+			default:
+			}
+			<-r.Context().Done()
+			return
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+	blockDownloads.Store(true)
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	var unloads atomic.Int32
+	manager.SetBackendUnloader(func(context.Context) int {
+		unloads.Add(1)
+		return 1
+	})
+
+	pulled := make(chan error, 1)
+	go func() {
+		r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+		pulled <- manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder())
+	}()
+	select {
+	case <-downloading:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the download to start")
+	}
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+	if err := manager.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown failed: %v", err)
+	}
+
+	select {
+	case err := <-pulled:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected the pull to be cancelled, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Timed out waiting for the pull to stop")
+	}
+	if got := unloads.Load(); got != 1 {
+		t.Errorf("Expected backends to be unloaded once, got %d", got)
+	}
+	if inStore, err := manager.InStore(tag); err != nil || inStore {
+		t.Errorf("Expected the cancelled pull not to be in the store, got %v, %v", inStore, err)
+	}
+}
@@ -9,18 +9,27 @@ import (
 	"html"
 	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"os"
 	"path"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/distribution"
+	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
 	"github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/docker/model-runner/pkg/inference"
 	"github.com/docker/model-runner/pkg/internal/utils"
 	"github.com/docker/model-runner/pkg/logging"
 	"github.com/docker/model-runner/pkg/middleware"
+	"github.com/docker/model-runner/pkg/servicemetrics"
+	"github.com/docker/model-runner/pkg/tracing"
 )
 
 // parseBoolQueryParam parses a boolean query parameter from the request.
@@ -58,6 +67,14 @@ type HTTPHandler struct {
 	lock sync.RWMutex
 	// manager handles business logic for model operations.
 	manager *Manager
+	// formatSupported reports whether an installed backend can run models
+	// of a format. It is used for ?runnable=true listings; when nil, every
+	// model is considered runnable.
+	formatSupported func(types.Format) bool
+	// architectureSupported reports whether the backend that runs models of
+	// a format can run an architecture. It is used to warn about pulled
+	// models that can't be run; when nil, no warnings are given.
+	architectureSupported func(types.Format, string) bool
 }
 
 type ClientConfig struct {
@@ -65,12 +82,74 @@ type ClientConfig struct {
 	StoreRootPath string
 	// Logger is the logger to use.
 	Logger *slog.Logger
-	// Transport is the HTTP transport to use.
+	// Transport is the HTTP transport to use for registries and Hugging Face.
+	// When nil, a transport pooled according to the connection settings
+	// below is built.
 	Transport http.RoundTripper
+	// MaxIdleConnsPerHost is the number of idle registry connections kept per
+	// host. Zero selects registry.DefaultMaxIdleConnsPerHost.
+	MaxIdleConnsPerHost int
+	// MaxConnsPerHost limits the registry connections per host, including
+	// those in use. Zero means no limit.
+	MaxConnsPerHost int
+	// IdleConnTimeout is how long an idle registry connection is kept open.
+	// Zero selects registry.DefaultIdleConnTimeout.
+	IdleConnTimeout time.Duration
 	// UserAgent is the user agent to use.
 	UserAgent string
 	// PlainHTTP enables plain HTTP connections to registries (for testing).
 	PlainHTTP bool
+	// UseDockerKeychain resolves registry credentials from the Docker config
+	// file and its credential helpers when none are set in the environment.
+	// Otherwise only credentials from the environment are used.
+	UseDockerKeychain bool
+	// PlainHTTPHosts lists registry hosts that are always reached over plain
+	// HTTP, in addition to those in the PLAIN_HTTP_HOSTS environment variable.
+	// INSECURE_REGISTRY=true still forces HTTP for every registry.
+	PlainHTTPHosts []string
+	// MaxModelBytes rejects pulls of models whose total size exceeds it.
+	// Zero means no limit.
+	MaxModelBytes int64
+	// AllowMaxModelBytesOverride lets pull requests set their own size limit
+	// in place of MaxModelBytes.
+	AllowMaxModelBytesOverride bool
+	// MaxStoreBytes caps the size of the model store. When a pull leaves the
+	// store larger, least recently used models that are not in use are
+	// evicted until it fits. Zero means no limit.
+	MaxStoreBytes int64
+	// MaxLoadBytes rejects load requests whose archive exceeds it with
+	// status 413. Zero means no limit.
+	MaxLoadBytes int64
+	// AllowPrivateLoadURLs lets load requests fetch archives from hosts on
+	// private, loopback and link-local networks. Otherwise such URLs, and
+	// redirects to them, are rejected.
+	AllowPrivateLoadURLs bool
+	// DefaultContextSize is the context size used for models whose config
+	// sets none when no context size is configured for the run. It is capped
+	// at the context length the model was trained with. Zero leaves the
+	// context size to the backend.
+	DefaultContextSize int32
+	// RepairStore removes unreferenced blobs and stale incomplete downloads
+	// from the store on startup, and logs models whose blobs are missing.
+	RepairStore bool
+	// PreflightPulls reads the GGUF header of remote models before pulling
+	// them, and rejects architectures the installed backend can't run.
+	PreflightPulls bool
+	// BlobCopyBufferSize is the size of the buffer blobs are written to the
+	// store through. Zero selects 1 MiB.
+	BlobCopyBufferSize int
+	// StoreFileMode and StoreDirMode are the modes of the files and
+	// directories created in the store. Zero selects 0600 and 0700, so
+	// models are private to the user running the model runner.
+	StoreFileMode os.FileMode
+	StoreDirMode  os.FileMode
+	// Resolver, if set, is called with the default model resolver and
+	// returns the resolver the model endpoints use, which typically falls
+	// back on the default one.
+	Resolver func(ModelResolver) ModelResolver
+	// Metrics, if set, records pulls, the bytes downloaded from registries,
+	// and the size of the store.
+	Metrics *servicemetrics.Metrics
 }
 
 // NewHTTPHandler creates a new model's handler.
@@ -100,7 +179,7 @@ func (h *HTTPHandler) RebuildRoutes(allowedOrigins []string) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 	// Update handlers that depend on the allowed origins.
-	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
+	h.httpHandler = middleware.LoggingMiddleware(h.log, middleware.CorsMiddleware(allowedOrigins, h.router))
 }
 
 func (h *HTTPHandler) routeHandlers() map[string]http.HandlerFunc {
@@ -108,10 +187,12 @@ func (h *HTTPHandler) routeHandlers() map[string]http.HandlerFunc {
 		"POST " + inference.ModelsPrefix + "/create":                          h.handleCreateModel,
 		"POST " + inference.ModelsPrefix + "/load":                            h.handleLoadModel,
 		"GET " + inference.ModelsPrefix:                                       h.handleGetModels,
+		"GET " + inference.ModelsPrefix + "/diff":                             h.handleDiffModels,
 		"GET " + inference.ModelsPrefix + "/{nameAndAction...}":               h.handleModelGetAction,
 		"DELETE " + inference.ModelsPrefix + "/{name...}":                     h.handleDeleteModel,
 		"POST " + inference.ModelsPrefix + "/{nameAndAction...}":              h.handleModelAction,
 		"DELETE " + inference.ModelsPrefix + "/purge":                         h.handlePurge,
+		"POST " + inference.ModelsPrefix + "/prune":                           h.handlePrune,
 		"GET " + inference.InferencePrefix + "/{backend}/v1/models":           h.handleOpenAIGetModels,
 		"GET " + inference.InferencePrefix + "/{backend}/v1/models/{name...}": h.handleOpenAIGetModel,
 		"GET " + inference.InferencePrefix + "/v1/models":                     h.handleOpenAIGetModels,
@@ -123,18 +204,29 @@ func (h *HTTPHandler) routeHandlers() map[string]http.HandlerFunc {
 func (h *HTTPHandler) handleCreateModel(w http.ResponseWriter, r *http.Request) {
 	// Decode the request.
 	var request ModelCreateRequest
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		http.Error(w, "invalid request body", http.StatusBadRequest)
+	if err := decodeRequest(r.Body, &request); err != nil {
+		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	// Pull the model
-	if err := h.manager.Pull(request.From, request.BearerToken, r, w); err != nil {
+	r, span := tracing.StartServer(r, tracing.SpanPull, request.From)
+
+	// Pull the model, unless the preflight shows it can't run
+	err := h.preflightPull(r.Context(), request)
+	if err == nil {
+		err = h.manager.Pull(request, r, w)
+	}
+	h.endModelSpan(span, request.From, err)
+	if err != nil {
 		sanitizedFrom := utils.SanitizeForLog(request.From, -1)
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			h.log.Info("Request canceled/timed out while pulling model", "model", sanitizedFrom)
 			return
 		}
+		if errors.Is(err, ErrPulledModelNotTagged) {
+			// Already reported in the progress stream.
+			return
+		}
 		if errors.Is(err, registry.ErrInvalidReference) {
 			h.log.Warn("Invalid model reference", "model", sanitizedFrom, "error", err)
 			http.Error(w, "Invalid model reference", http.StatusBadRequest)
@@ -155,17 +247,76 @@ func (h *HTTPHandler) handleCreateModel(w http.ResponseWriter, r *http.Request)
 			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
 			return
 		}
+		if errors.Is(err, distribution.ErrModelTooLarge) {
+			h.log.Warn("Model exceeds the maximum size", "model", sanitizedFrom, "error", err)
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+		if errors.Is(err, ErrMaxModelBytesOverrideNotAllowed) {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if errors.Is(err, ErrUnsupportedArchitecture) {
+			h.log.Warn("Rejected pull of model with an unsupported architecture", "model", sanitizedFrom, "error", err)
+			http.Error(w, err.Error(), http.StatusUnsupportedMediaType)
+			return
+		}
 		// Note: ErrUnsupportedFormat is no longer treated as an error - it's a warning
 		// that's sent to the client via the progress stream
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	h.warnUnsupportedArchitecture(w, r, request.From)
+}
+
+// endModelSpan ends a pull or push span, recording the size of the model if
+// the operation succeeded.
+func (h *HTTPHandler) endModelSpan(span *tracing.Span, ref string, err error) {
+	if err == nil {
+		if size, sizeErr := h.manager.modelSize(ref); sizeErr == nil {
+			span.SetBytes(size)
+		}
+	}
+	span.End(err)
 }
 
-// handleLoadModel handles POST <inference-prefix>/models/load requests.
+// handleLoadModel handles POST <inference-prefix>/models/load requests. The
+// model archive is read from the request body, or fetched from the url query
+// parameter, in which case it is verified against the optional digest query
+// parameter.
 func (h *HTTPHandler) handleLoadModel(w http.ResponseWriter, r *http.Request) {
-	err := h.manager.Load(r.Body, w)
+	r, span := tracing.StartServer(r, tracing.SpanLoad, "")
+	var err error
+	if source := r.URL.Query().Get("url"); source != "" {
+		err = h.manager.LoadFromURL(r.Context(), source, r.URL.Query().Get("digest"), w)
+	} else {
+		if h.manager.maxLoadBytes > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, h.manager.maxLoadBytes)
+		}
+		body := tracing.NewCountingReader(r.Body)
+		err = h.manager.Load(r.Context(), body, w)
+		span.SetBytes(body.N())
+	}
+	span.End(err)
 	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.log.Info("Request canceled while loading model")
+			return
+		}
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			http.Error(w, fmt.Sprintf("model archive exceeds the maximum size of %d bytes", h.manager.maxLoadBytes), http.StatusRequestEntityTooLarge)
+			return
+		}
+		var mismatch *oci.DigestMismatchError
+		if errors.Is(err, ErrInvalidLoadSource) || errors.As(err, &mismatch) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if errors.Is(err, ErrLoadSourceUnavailable) {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -180,10 +331,166 @@ func (h *HTTPHandler) handleModelGetAction(w http.ResponseWriter, r *http.Reques
 		h.handleExportModel(w, r, model)
 		return
 	}
+	if action == "update-check" && model != "" {
+		h.handleCheckUpdate(w, r, model)
+		return
+	}
+	if action == "tags" && model != "" && parseBoolQueryParam(r, h.log, "remote") {
+		h.handleListRemoteTags(w, r, model)
+		return
+	}
+	if idx := strings.LastIndex(nameAndAction, "/blobs/"); idx > 0 {
+		// A model whose name contains blobs/ followed by something other
+		// than a digest is inspected as usual.
+		if digest, err := oci.NewHash(nameAndAction[idx+len("/blobs/"):]); err == nil {
+			h.handleRemoteBlob(w, r, nameAndAction[:idx], digest)
+			return
+		}
+	}
+	if (action == "manifest" || action == "config") && model != "" {
+		// A model whose name ends in manifest or config is inspected as usual.
+		if h.handleRawModelJSON(w, model, action) {
+			return
+		}
+	}
 
 	h.handleGetModelByRef(w, r, nameAndAction)
 }
 
+// handleRawModelJSON handles GET <inference-prefix>/models/{name}/manifest and
+// GET <inference-prefix>/models/{name}/config requests, returning the stored
+// manifest or config blob unmodified. It reports false without writing a
+// response if the model is not found.
+func (h *HTTPHandler) handleRawModelJSON(w http.ResponseWriter, model, action string) bool {
+	getRaw := h.manager.GetRawManifest
+	if action == "config" {
+		getRaw = h.manager.GetRawConfig
+	}
+	ref, err := h.manager.resolver.ResolveLocal(model)
+	if err != nil {
+		if errors.Is(err, distribution.ErrModelNotFound) {
+			return false
+		}
+		h.writeModelError(w, err)
+		return true
+	}
+	raw, mediaType, err := getRaw(ref)
+	if errors.Is(err, distribution.ErrModelNotFound) {
+		return false
+	}
+	if err != nil {
+		h.log.Warn("error while reading raw model "+action, "model", utils.SanitizeForLog(model, -1), "error", err)
+		h.writeModelError(w, err)
+		return true
+	}
+
+	if mediaType == "" {
+		mediaType = "application/json"
+	}
+	w.Header().Set("Content-Type", mediaType)
+	if _, err := w.Write(raw); err != nil {
+		h.log.Warn("error while writing raw model "+action, "error", err)
+	}
+	return true
+}
+
+// handleRemoteBlob handles GET <inference-prefix>/models/{name}/blobs/{digest}
+// requests, streaming a blob of the remote model for clients that cannot reach
+// the registry directly. The final chunk is withheld until the blob's digest
+// has been verified, so a corrupt blob ends in a truncated response rather than
+// a complete one.
+func (h *HTTPHandler) handleRemoteBlob(w http.ResponseWriter, r *http.Request, model string, digest oci.Hash) {
+	ref, err := h.manager.resolver.ResolveRemote(r.Context(), model)
+	if err != nil {
+		h.writeModelError(w, err)
+		return
+	}
+	rc, size, err := h.manager.OpenRemoteBlob(r.Context(), ref, digest)
+	if err != nil {
+		h.log.Warn("error while opening remote blob", "model", utils.SanitizeForLog(model, -1), "digest", digest.String(), "error", err)
+		h.writeModelError(w, err)
+		return
+	}
+	defer rc.Close()
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+	w.Header().Set("Docker-Content-Digest", digest.String())
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if err := copyWithholdingLast(w, rc); err != nil {
+		h.log.Warn("error while streaming remote blob", "model", utils.SanitizeForLog(model, -1), "digest", digest.String(), "error", err)
+		// The headers are already sent, so abort the connection to make
+		// sure the client does not mistake the partial body for the blob.
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// copyWithholdingLast copies src to dst, holding each chunk back until the
+// next read succeeds. An error reported with the end of src is therefore
+// returned before the last chunk is written.
+func copyWithholdingLast(dst io.Writer, src io.Reader) error {
+	buf := make([]byte, 32*1024)
+	held := make([]byte, 0, len(buf))
+	for {
+		n, err := src.Read(buf)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return err
+		}
+		if n > 0 {
+			if _, werr := dst.Write(held); werr != nil {
+				return werr
+			}
+			held = append(held[:0], buf[:n]...)
+		}
+		if err != nil {
+			_, werr := dst.Write(held)
+			return werr
+		}
+	}
+}
+
+// handleListRemoteTags handles GET <inference-prefix>/models/{name}/tags?remote=true
+// requests, returning the names of the tags available in the remote repository.
+func (h *HTTPHandler) handleListRemoteTags(w http.ResponseWriter, r *http.Request, repo string) {
+	tags, err := h.manager.ListRemoteTags(r.Context(), repo)
+	if err != nil {
+		h.log.Warn("error while listing remote tags", "repository", utils.SanitizeForLog(repo), "error", err)
+		h.writeModelError(w, err)
+		return
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(tags); err != nil {
+		h.log.Warn("error while encoding remote tags response", "error", err)
+	}
+}
+
+// handleCheckUpdate handles GET <inference-prefix>/models/{name}/update-check
+// requests, reporting whether the tag points to a newer model in the registry.
+func (h *HTTPHandler) handleCheckUpdate(w http.ResponseWriter, r *http.Request, model string) {
+	check, err := h.manager.CheckUpdate(r.Context(), model)
+	if err != nil {
+		if errors.Is(err, ErrUpdateCheckUnsupported) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Warn("error while checking for model update", "model", utils.SanitizeForLog(model, -1), "error", err)
+		h.writeModelError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(check); err != nil {
+		h.log.Warn("error while encoding update check response", "error", err)
+	}
+}
+
 func (h *HTTPHandler) handleExportModel(w http.ResponseWriter, r *http.Request, modelRef string) {
 	w.Header().Set("Content-Type", "application/x-tar")
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", modelRef+".tar"))
@@ -200,13 +507,94 @@ func (h *HTTPHandler) handleExportModel(w http.ResponseWriter, r *http.Request,
 	}
 }
 
-// handleGetModels handles GET <inference-prefix>/models requests.
+// SetFormatSupport sets the check that ?runnable=true listings use to decide
+// whether an installed backend can run a model's format. It must be called
+// before the handler serves requests.
+func (h *HTTPHandler) SetFormatSupport(supported func(types.Format) bool) {
+	h.formatSupported = supported
+}
+
+// SetArchitectureSupport sets the check that pulls use to warn about models
+// whose architecture the backend for their format can't run. It must be
+// called before the handler serves requests.
+func (h *HTTPHandler) SetArchitectureSupport(supported func(types.Format, string) bool) {
+	h.architectureSupported = supported
+}
+
+// preflightPull returns ErrUnsupportedArchitecture if pull preflights are
+// enabled and the GGUF header of the remote model names an architecture the
+// installed backend can't run. Only the header is read. If it can't be read,
+// the pull is let through and checked once downloaded instead.
+func (h *HTTPHandler) preflightPull(ctx context.Context, request ModelCreateRequest) error {
+	if !h.manager.preflightPulls || h.architectureSupported == nil || distribution.IsHuggingFaceReference(request.From) {
+		return nil
+	}
+	architecture, err := h.manager.RemoteGGUFArchitecture(ctx, request.From, request.BearerToken, request.RawReference)
+	if err != nil {
+		h.log.Warn("Failed to read remote GGUF header, skipping pull preflight", "model", utils.SanitizeForLog(request.From, -1), "error", err)
+		return nil
+	}
+	if architecture == "" || h.architectureSupported(types.FormatGGUF, architecture) {
+		return nil
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedArchitecture, architecture)
+}
+
+// warnUnsupportedArchitecture appends a warning to the progress stream of a
+// pull if the pulled model's architecture is known not to be supported.
+func (h *HTTPHandler) warnUnsupportedArchitecture(w http.ResponseWriter, r *http.Request, ref string) {
+	if h.architectureSupported == nil {
+		return
+	}
+	model, err := h.manager.GetLocal(ref)
+	if err != nil {
+		return
+	}
+	config, err := model.Config()
+	if err != nil || config == nil {
+		return
+	}
+	architecture := config.GetArchitecture()
+	if h.architectureSupported(config.GetFormat(), architecture) {
+		return
+	}
+	h.log.Warn("Pulled model has an unsupported architecture", "model", utils.SanitizeForLog(ref, -1), "architecture", architecture)
+	data, err := json.Marshal(oci.ProgressMessage{
+		Type:    oci.TypeWarning,
+		Message: fmt.Sprintf("Model architecture %q is not supported by the installed backend; the model may fail to run", architecture),
+		Mode:    oci.ModePull,
+	})
+	if err != nil {
+		return
+	}
+	progressWriter := &progressResponseWriter{
+		writer:     w,
+		isJSON:     r.Header.Get("Accept") == "application/json",
+		escapeHTML: escapeProgressHTML(r),
+	}
+	if flusher, ok := w.(http.Flusher); ok {
+		progressWriter.flusher = flusher
+	}
+	_, _ = progressWriter.Write(append(data, '\n'))
+}
+
+// handleGetModels handles GET <inference-prefix>/models requests. With
+// ?name=<prefix>, only models with a tag starting with prefix are listed, and
+// with ?runnable=true, only models an installed backend can run.
 func (h *HTTPHandler) handleGetModels(w http.ResponseWriter, r *http.Request) {
 	apiModels, err := h.manager.List()
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	if prefix := r.URL.Query().Get("name"); prefix != "" {
+		apiModels = filterModelsByName(apiModels, prefix)
+	}
+	if parseBoolQueryParam(r, h.log, "runnable") && h.formatSupported != nil {
+		apiModels = slices.DeleteFunc(apiModels, func(m *Model) bool {
+			return !h.formatSupported(modelFormat(m))
+		})
+	}
 
 	// Write the response.
 	w.Header().Set("Content-Type", "application/json")
@@ -215,7 +603,52 @@ func (h *HTTPHandler) handleGetModels(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// handleGetModel handles GET <inference-prefix>/models/{name} requests.
+// filterModelsByName returns the models that have a tag starting with prefix.
+func filterModelsByName(models []*Model, prefix string) []*Model {
+	filtered := make([]*Model, 0, len(models))
+	for _, m := range models {
+		if slices.ContainsFunc(m.Tags, func(tag string) bool {
+			return strings.HasPrefix(tag, prefix)
+		}) {
+			filtered = append(filtered, m)
+		}
+	}
+	return filtered
+}
+
+// modelFormat returns the format declared in a model's config, or "" if it
+// has none.
+func modelFormat(m *Model) types.Format {
+	if m.Config == nil {
+		return ""
+	}
+	return m.Config.GetFormat()
+}
+
+// handleDiffModels handles GET <inference-prefix>/models/diff?a={ref}&b={ref}
+// requests, comparing the configs, labels and layers of two local models.
+func (h *HTTPHandler) handleDiffModels(w http.ResponseWriter, r *http.Request) {
+	a, b := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if a == "" || b == "" {
+		http.Error(w, "both a and b query parameters are required", http.StatusBadRequest)
+		return
+	}
+
+	diff, err := h.manager.Diff(a, b)
+	if err != nil {
+		h.log.Warn("error while diffing models", "a", utils.SanitizeForLog(a, -1), "b", utils.SanitizeForLog(b, -1), "error", err)
+		h.writeModelError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(diff); err != nil {
+		h.log.Warn("error while encoding model diff response", "error", err)
+	}
+}
+
+// handleGetModel handles GET <inference-prefix>/models/{name} requests. The
+// full per-format metadata is only included with ?verbose=true.
 func (h *HTTPHandler) handleGetModel(w http.ResponseWriter, r *http.Request) {
 	modelRef := r.PathValue("name")
 	h.handleGetModelByRef(w, r, modelRef)
@@ -233,12 +666,18 @@ func (h *HTTPHandler) handleGetModelByRef(w http.ResponseWriter, r *http.Request
 		apiModel, err = h.getRemoteAPIModel(r.Context(), modelRef)
 	} else {
 		apiModel, err = h.getLocalAPIModel(modelRef)
+		if err == nil {
+			h.manager.setLastUsed(apiModel)
+		}
 	}
 
 	if err != nil {
 		h.writeModelError(w, err)
 		return
 	}
+	if !parseBoolQueryParam(r, h.log, "verbose") {
+		apiModel = apiModel.Concise()
+	}
 
 	// Write the response.
 	w.Header().Set("Content-Type", "application/json")
@@ -248,7 +687,18 @@ func (h *HTTPHandler) handleGetModelByRef(w http.ResponseWriter, r *http.Request
 }
 
 func (h *HTTPHandler) getRemoteAPIModel(ctx context.Context, modelRef string) (*Model, error) {
+	modelRef, err := h.manager.resolver.ResolveRemote(ctx, modelRef)
+	if err != nil {
+		return nil, err
+	}
 	model, err := h.manager.GetRemote(ctx, modelRef)
+	if errors.Is(err, registry.ErrIndex) {
+		index, digest, err := h.manager.GetRemoteIndex(ctx, modelRef)
+		if err != nil {
+			return nil, err
+		}
+		return ToModelFromIndex(index, digest), nil
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -256,21 +706,15 @@ func (h *HTTPHandler) getRemoteAPIModel(ctx context.Context, modelRef string) (*
 }
 
 func (h *HTTPHandler) getLocalAPIModel(modelRef string) (*Model, error) {
-	model, err := h.manager.GetLocal(modelRef)
+	model, err := h.manager.ResolveLocal(modelRef)
 	if err != nil {
-		// If not found locally, try partial name matching
-		if errors.Is(err, distribution.ErrModelNotFound) {
-			// e.g., "smollm2" for "ai/smollm2:latest"
-			return findModelByPartialName(h, modelRef)
-		}
 		return nil, err
 	}
-
 	return ToModel(model)
 }
 
 func (h *HTTPHandler) writeModelError(w http.ResponseWriter, err error) {
-	if errors.Is(err, distribution.ErrModelNotFound) || errors.Is(err, registry.ErrModelNotFound) {
+	if errors.Is(err, distribution.ErrModelNotFound) || errors.Is(err, registry.ErrModelNotFound) || errors.Is(err, ErrBlobNotFound) {
 		http.Error(w, err.Error(), http.StatusNotFound)
 		return
 	}
@@ -278,40 +722,6 @@ func (h *HTTPHandler) writeModelError(w http.ResponseWriter, err error) {
 	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
-// findModelByPartialName looks for a model by matching the provided reference
-// against model tags using partial name matching (e.g., "smollm2" matches "ai/smollm2:latest")
-func findModelByPartialName(h *HTTPHandler, modelRef string) (*Model, error) {
-	// Get all models to search through their tags
-	models, err := h.manager.RawList()
-	if err != nil {
-		return nil, err
-	}
-
-	// Look for a model whose tags match the reference
-	for _, model := range models {
-		for _, tag := range model.Tags() {
-			// Extract the model name without tag part (e.g., from "ai/smollm2:latest" get "ai/smollm2")
-			tagWithoutVersion := tag
-			if idx := strings.LastIndex(tag, ":"); idx != -1 {
-				tagWithoutVersion = tag[:idx]
-			}
-
-			// Get just the name part without organization (e.g., from "ai/smollm2" get "smollm2")
-			namePart := tagWithoutVersion
-			if idx := strings.LastIndex(tagWithoutVersion, "/"); idx != -1 {
-				namePart = tagWithoutVersion[idx+1:]
-			}
-
-			// Check if the reference matches the name part
-			if namePart == modelRef {
-				return ToModel(model)
-			}
-		}
-	}
-
-	return nil, distribution.ErrModelNotFound
-}
-
 // handleDeleteModel handles DELETE <inference-prefix>/models/{name} requests.
 // query params:
 // - force: if true, delete the model even if it has multiple tags
@@ -377,8 +787,7 @@ func (h *HTTPHandler) handleOpenAIGetModels(w http.ResponseWriter, r *http.Reque
 // handleOpenAIGetModel handles GET <inference-prefix>/<backend>/v1/models/{name}
 // and GET <inference-prefix>/v1/models/{name} requests.
 func (h *HTTPHandler) handleOpenAIGetModel(w http.ResponseWriter, r *http.Request) {
-	modelRef := r.PathValue("name")
-	model, err := h.manager.GetLocal(modelRef)
+	model, err := h.manager.ResolveLocal(r.PathValue("name"))
 	if err != nil {
 		if errors.Is(err, distribution.ErrModelNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -411,8 +820,14 @@ func (h *HTTPHandler) handleModelAction(w http.ResponseWriter, r *http.Request)
 		h.handleTagModel(w, r, model)
 	case "push":
 		h.handlePushModel(w, r, model)
+	case "cancel-push":
+		h.handleCancelPush(w, model)
 	case "repackage":
 		h.handleRepackageModel(w, r, model)
+	case "drop-layer":
+		h.handleDropLayer(w, r, model)
+	case "verify":
+		h.handleVerifyModel(w, model)
 	default:
 		http.Error(w, fmt.Sprintf("unknown action %q", action), http.StatusNotFound)
 	}
@@ -422,10 +837,12 @@ func (h *HTTPHandler) handleModelAction(w http.ResponseWriter, r *http.Request)
 // The query parameters are:
 // - repo: the repository to tag the model with (required)
 // - tag: the tag to apply to the model (required)
+// - pin: if true, the tag is pinned to the model's current digest (optional)
 func (h *HTTPHandler) handleTagModel(w http.ResponseWriter, r *http.Request, model string) {
 	// Extract query parameters.
 	repo := r.URL.Query().Get("repo")
 	tag := r.URL.Query().Get("tag")
+	pin := parseBoolQueryParam(r, h.log, "pin")
 
 	// Validate query parameters.
 	if repo == "" || tag == "" {
@@ -437,7 +854,11 @@ func (h *HTTPHandler) handleTagModel(w http.ResponseWriter, r *http.Request, mod
 	target := fmt.Sprintf("%s:%s", repo, tag)
 
 	// First try to tag using the provided model reference as-is
-	err := h.manager.Tag(model, target)
+	tagModel := h.manager.Tag
+	if pin {
+		tagModel = h.manager.TagPinned
+	}
+	id, err := tagModel(model, target)
 	if err != nil {
 		if errors.Is(err, distribution.ErrModelNotFound) {
 			http.Error(w, err.Error(), http.StatusNotFound)
@@ -451,9 +872,10 @@ func (h *HTTPHandler) handleTagModel(w http.ResponseWriter, r *http.Request, mod
 	// Respond with success.
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusCreated)
-	response := map[string]string{
-		"message": fmt.Sprintf("Model tagged successfully with %q", target),
-		"target":  target,
+	response := ModelTagResponse{
+		Message:  fmt.Sprintf("Model tagged successfully with %q", target),
+		SourceID: id,
+		Target:   target,
 	}
 	if err := json.NewEncoder(w).Encode(response); err != nil {
 		h.log.Warn("error while encoding tag response", "error", err)
@@ -462,6 +884,18 @@ func (h *HTTPHandler) handleTagModel(w http.ResponseWriter, r *http.Request, mod
 
 // handlePushModel handles POST <inference-prefix>/models/{name}/push requests.
 func (h *HTTPHandler) handlePushModel(w http.ResponseWriter, r *http.Request, model string) {
+	if resolved := h.manager.ResolvePushReference(model); resolved != model {
+		h.log.Info("Resolved push reference", "model", utils.SanitizeForLog(model, -1), "tag", utils.SanitizeForLog(resolved, -1))
+		model = resolved
+	}
+	target, err := pushTarget(model)
+	if err != nil {
+		h.log.Warn("Invalid push reference", "model", utils.SanitizeForLog(model, -1), "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.log.Info("Pushing model", "model", utils.SanitizeForLog(model, -1), "target", utils.SanitizeForLog(target, -1))
+
 	var req ModelPushRequest
 	if r.Body != nil && r.Body != http.NoBody {
 		body, err := io.ReadAll(r.Body)
@@ -477,7 +911,18 @@ func (h *HTTPHandler) handlePushModel(w http.ResponseWriter, r *http.Request, mo
 		}
 	}
 
-	if err := h.manager.Push(model, req.BearerToken, r, w); err != nil {
+	r, span := tracing.StartServer(r, tracing.SpanPush, model)
+	err = h.manager.Push(model, req, r, w)
+	h.endModelSpan(span, model, err)
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			h.log.Info("Push canceled", "model", utils.SanitizeForLog(model, -1))
+			return
+		}
+		if errors.Is(err, distribution.ErrUnsupportedCompression) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
 		if errors.Is(err, distribution.ErrInvalidReference) {
 			h.log.Warn("Invalid model reference", "model", utils.SanitizeForLog(model, -1), "error", err)
 			http.Error(w, "Invalid model reference", http.StatusBadRequest)
@@ -498,25 +943,66 @@ func (h *HTTPHandler) handlePushModel(w http.ResponseWriter, r *http.Request, mo
 	}
 }
 
+// handleCancelPush handles POST <inference-prefix>/models/{name}/cancel-push
+// requests, cancelling any push of the model in progress.
+func (h *HTTPHandler) handleCancelPush(w http.ResponseWriter, model string) {
+	if !h.manager.CancelPush(model) {
+		http.Error(w, fmt.Sprintf("no push in progress for %q", model), http.StatusNotFound)
+		return
+	}
+	h.log.Info("Canceling push", "model", utils.SanitizeForLog(model, -1))
+
+	w.Header().Set("Content-Type", "application/json")
+	response := map[string]string{
+		"message": fmt.Sprintf("Push of %q canceled", model),
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.Warn("error while encoding cancel push response", "error", err)
+	}
+}
+
+// pushTarget returns the fully-qualified reference a model is pushed to. The
+// reference must not be a digest, as the pushed model needs a tag. A bare name
+// that matches a local model has already been replaced with that model's full
+// tag by ResolvePushReference, so the push goes wherever the model was stored.
+// A bare name left unresolved must be tagged with an organization or registry
+// first, so that it is never pushed under the default organization by
+// accident.
+func pushTarget(model string) (string, error) {
+	ref, err := reference.ParseReference(model, registry.GetDefaultRegistryOptions()...)
+	if err != nil {
+		return "", fmt.Errorf("invalid model reference %q: %w", model, err)
+	}
+	if _, ok := ref.(*reference.Tag); !ok {
+		return "", fmt.Errorf("cannot push %q by digest: tag the model first, e.g. docker model tag %s <org>/<name>:<tag>", model, model)
+	}
+	name := model
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	if !strings.Contains(name, "/") {
+		return "", fmt.Errorf("model reference %q does not include an organization or registry: tag the model first, e.g. docker model tag %s <org>/%s", model, model, name)
+	}
+	return ref.String(), nil
+}
+
 type RepackageRequest struct {
 	Target      string  `json:"target"`
 	ContextSize *uint64 `json:"context_size,omitempty"`
+	// Force overwrites Target even if it already points to a different model.
+	Force bool `json:"force,omitempty"`
 }
 
 func (h *HTTPHandler) handleRepackageModel(w http.ResponseWriter, r *http.Request, model string) {
 	var req RepackageRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+	if err := decodeRequest(r.Body, &req); err != nil {
 		http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
 		return
 	}
 
-	if req.Target == "" {
-		http.Error(w, "target is required", http.StatusBadRequest)
-		return
-	}
-
 	opts := RepackageOptions{
 		ContextSize: req.ContextSize,
+		Force:       req.Force,
 	}
 
 	if err := h.manager.Repackage(model, req.Target, opts); err != nil {
@@ -524,6 +1010,10 @@ func (h *HTTPHandler) handleRepackageModel(w http.ResponseWriter, r *http.Reques
 			http.Error(w, err.Error(), http.StatusNotFound)
 			return
 		}
+		if errors.Is(err, distribution.ErrConflict) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		h.log.Warn("Failed to repackage model", "model", utils.SanitizeForLog(model, -1), "error", err)
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -541,16 +1031,93 @@ func (h *HTTPHandler) handleRepackageModel(w http.ResponseWriter, r *http.Reques
 	}
 }
 
+// handleDropLayer handles POST <inference-prefix>/models/{name}/drop-layer
+// requests. The query parameters are:
+// - media_type: the media type of the layers to drop (required)
+func (h *HTTPHandler) handleDropLayer(w http.ResponseWriter, r *http.Request, model string) {
+	mediaType := r.URL.Query().Get("media_type")
+	if mediaType == "" {
+		http.Error(w, "missing media_type query parameter", http.StatusBadRequest)
+		return
+	}
+
+	id, err := h.manager.DropLayer(model, mediaType)
+	if err != nil {
+		if errors.Is(err, distribution.ErrModelNotFound) || errors.Is(err, distribution.ErrLayerNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if errors.Is(err, distribution.ErrPrimaryLayer) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.log.Warn("Failed to drop model layer", "model", utils.SanitizeForLog(model, -1), "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	response := ModelDropLayerResponse{
+		Message:   fmt.Sprintf("Dropped %s layers", mediaType),
+		ID:        id,
+		MediaType: mediaType,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.log.Warn("error while encoding drop layer response", "error", err)
+	}
+}
+
+// handleVerifyModel handles POST <inference-prefix>/models/{name}/verify
+// requests, hashing the model's blobs and reporting those that are corrupt or
+// missing.
+func (h *HTTPHandler) handleVerifyModel(w http.ResponseWriter, model string) {
+	report, err := h.manager.Verify(model)
+	if err != nil {
+		if errors.Is(err, distribution.ErrModelNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		h.log.Warn("Failed to verify model", "model", utils.SanitizeForLog(model, -1), "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		h.log.Warn("error while encoding verify response", "error", err)
+	}
+}
+
 // handlePurge handles DELETE <inference-prefix>/models/purge requests.
 func (h *HTTPHandler) handlePurge(w http.ResponseWriter, _ *http.Request) {
 	err := h.manager.Purge()
 	if err != nil {
 		h.log.Warn("Failed to purge models", "error", err)
+		if errors.Is(err, ErrStoreBusy) {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 }
 
+// handlePrune handles POST <inference-prefix>/models/prune requests. It deletes
+// every model without tags.
+func (h *HTTPHandler) handlePrune(w http.ResponseWriter, _ *http.Request) {
+	resp, err := h.manager.Prune()
+	if err != nil {
+		h.log.Warn("Failed to prune models", "error", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("error writing response: %v", err), http.StatusInternalServerError)
+	}
+}
+
 // ServeHTTP implement net/http.HTTPHandler.ServeHTTP.
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.lock.RLock()
@@ -558,22 +1125,33 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.httpHandler.ServeHTTP(w, r)
 }
 
+// escapeProgressHTML reports whether plain-text progress written for r should
+// be HTML-escaped, for clients that display it in a page. Clients opt in with
+// an escape=html parameter on a text/plain media range in their Accept header,
+// as in "text/plain; escape=html"; others get the text as written.
+func escapeProgressHTML(r *http.Request) bool {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(accept)
+		if err == nil && mediaType == "text/plain" && params["escape"] == "html" {
+			return true
+		}
+	}
+	return false
+}
+
 // progressResponseWriter implements io.Writer to write progress updates to the HTTP response
 type progressResponseWriter struct {
 	writer  http.ResponseWriter
 	flusher http.Flusher
 	isJSON  bool
+	// escapeHTML HTML-escapes plain-text progress. JSON is never escaped.
+	escapeHTML bool
 }
 
 func (w *progressResponseWriter) Write(p []byte) (n int, err error) {
-	var data []byte
-	if w.isJSON {
-		// For JSON, write the raw bytes without escaping
-		data = p
-	} else {
-		// For plain text, escape HTML
-		escapedData := html.EscapeString(string(p))
-		data = []byte(escapedData)
+	data := p
+	if !w.isJSON && w.escapeHTML {
+		data = []byte(html.EscapeString(string(p)))
 	}
 
 	n, err = w.writer.Write(data)
@@ -1,26 +1,41 @@
 package models
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/docker/model-runner/pkg/diskusage"
 	"github.com/docker/model-runner/pkg/distribution/distribution"
 	"github.com/docker/model-runner/pkg/distribution/oci"
+	"github.com/docker/model-runner/pkg/distribution/oci/authn"
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+	"github.com/docker/model-runner/pkg/distribution/oci/remote"
 	"github.com/docker/model-runner/pkg/distribution/registry"
 	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/docker/model-runner/pkg/internal/utils"
 	"github.com/docker/model-runner/pkg/logging"
+	"github.com/docker/model-runner/pkg/servicemetrics"
+	parser "github.com/gpustack/gguf-parser-go"
 )
 
 const (
 	// maximumConcurrentModelPulls is the maximum number of concurrent model
 	// pulls that a model manager will allow.
 	maximumConcurrentModelPulls = 2
+	// purgeTimeout is how long a purge waits for the operations writing to
+	// the store to finish.
+	purgeTimeout = 30 * time.Second
 )
 
 // Manager handles the business logic for model management operations.
@@ -31,16 +46,112 @@ type Manager struct {
 	distributionClient *distribution.Client
 	// registryClient is the client for model registry.
 	registryClient *registry.Client
+	// httpClient fetches model archives loaded from a URL.
+	httpClient *http.Client
+	// resolver resolves the references models are requested by.
+	resolver ModelResolver
 	// pullTokens is a semaphore used to restrict the maximum number of
 	// concurrent pull requests.
 	pullTokens chan struct{}
+	// pulls coalesces concurrent pulls of the same model.
+	pulls *pullGroup
+	// pushes tracks the pushes in progress so that they can be cancelled.
+	pushes *pushTracker
+	// maxModelBytes is the maximum total size of a pulled model, or zero for
+	// no limit.
+	maxModelBytes int64
+	// allowMaxModelBytesOverride lets pull requests replace maxModelBytes.
+	allowMaxModelBytesOverride bool
+	// maxStoreBytes is the size above which least recently used models are
+	// evicted after a pull, or zero for no limit.
+	maxStoreBytes int64
+	// maxLoadBytes is the maximum size of a loaded model archive, or zero
+	// for no limit.
+	maxLoadBytes int64
+	// defaultContextSize is the context size used for models and runs that
+	// set none, or zero for the backend default.
+	defaultContextSize int32
+	// locksMu protects locks.
+	locksMu sync.Mutex
+	// locks holds the lock state of models that are locked, or being locked,
+	// keyed by model ID.
+	locks map[string]*modelLock
+	// storeGate is held shared by operations that write to the store and
+	// exclusively while the store is purged.
+	storeGate storeGate
+	// purgeTimeout is how long Purge waits for storeGate.
+	purgeTimeout time.Duration
+	// preflightPulls enables reading the GGUF header of remote models
+	// before pulling them.
+	preflightPulls bool
+	// metrics records pulls. It may be nil.
+	metrics *servicemetrics.Metrics
+	// unloadBackends unloads the models running on the backends when the
+	// manager shuts down. It may be nil.
+	unloadBackends func(ctx context.Context) int
 }
 
+// ErrMaxModelBytesOverrideNotAllowed is returned when a pull request sets its
+// own size limit but the manager does not allow overrides.
+var ErrMaxModelBytesOverrideNotAllowed = errors.New("overriding the maximum model size is not allowed")
+
+// ErrBlobNotFound is returned when a model does not reference the requested
+// blob.
+var ErrBlobNotFound = errors.New("blob not found")
+
+// ErrUnsupportedArchitecture is returned when a pull preflight finds that the
+// installed backend can't run the model's architecture.
+var ErrUnsupportedArchitecture = errors.New("model architecture is not supported by the installed backend")
+
+// ErrInvalidLoadSource is returned when the URL or digest of a model archive
+// to load is invalid.
+var ErrInvalidLoadSource = errors.New("invalid model archive source")
+
+// ErrLoadSourceUnavailable is returned when a model archive to load can't be
+// fetched from its URL.
+var ErrLoadSourceUnavailable = errors.New("model archive source unavailable")
+
+// ErrUpdateCheckUnsupported is returned when an update check is requested for
+// a model reference that does not name a registry tag, such as an ID or a
+// Hugging Face reference.
+var ErrUpdateCheckUnsupported = errors.New("update checks require a registry tag")
+
+// ErrPulledModelNotTagged is returned when a pulled model can't be tagged with
+// the requested alias. By then the response has been committed, so the error
+// is reported in the progress stream rather than with a status code.
+var ErrPulledModelNotTagged = errors.New("pulled model could not be tagged")
+
 // NewManager creates a new model models with the provided clients.
 func NewManager(log logging.Logger, c ClientConfig) *Manager {
+	if len(c.PlainHTTPHosts) > 0 {
+		registry.SetPlainHTTPHosts(c.PlainHTTPHosts)
+	}
+
+	keychain := authn.EnvKeychain
+	if c.UseDockerKeychain {
+		keychain = authn.DefaultKeychain
+	}
+
+	transportOptions := registry.TransportOptions{
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
+	}
+	transport := c.Transport
+	if c.Metrics != nil {
+		// Count downloaded bytes on the transport the registry client would
+		// otherwise build itself.
+		if transport == nil {
+			transport = registry.NewTransport(transportOptions)
+		}
+		transport = c.Metrics.Transport(transport)
+	}
+
 	// Create the registry client (shared between distribution and direct registry access).
 	registryClient := registry.NewClient(
-		registry.WithTransport(c.Transport),
+		registry.WithKeychain(keychain),
+		registry.WithTransport(transport),
+		registry.WithTransportOptions(transportOptions),
 		registry.WithUserAgent(c.UserAgent),
 		registry.WithPlainHTTP(c.PlainHTTP),
 	)
@@ -50,12 +161,21 @@ func NewManager(log logging.Logger, c ClientConfig) *Manager {
 		distribution.WithStoreRootPath(c.StoreRootPath),
 		distribution.WithLogger(c.Logger),
 		distribution.WithRegistryClient(registryClient),
+		distribution.WithHuggingFaceTransport(transport),
+		distribution.WithCopyBufferSize(c.BlobCopyBufferSize),
+		distribution.WithFileModes(c.StoreFileMode, c.StoreDirMode),
 	)
 	if err != nil {
 		log.Error("Failed to create distribution client", "error", err)
 		// Continue without distribution client. The model manager will still
 		// respond to requests, but may return errors if the client is required.
 	}
+	if distributionClient != nil && c.RepairStore {
+		// Nothing writes to the store yet, so it can be repaired safely.
+		if _, err := distributionClient.RepairStore(distribution.StaleDownloadAge); err != nil {
+			log.Warn("Failed to repair model store", "error", err)
+		}
+	}
 
 	tokens := make(chan struct{}, maximumConcurrentModelPulls)
 
@@ -64,11 +184,146 @@ func NewManager(log logging.Logger, c ClientConfig) *Manager {
 		tokens <- struct{}{}
 	}
 
-	return &Manager{
-		log:                log,
-		distributionClient: distributionClient,
-		registryClient:     registryClient,
-		pullTokens:         tokens,
+	m := &Manager{
+		log:                        log,
+		distributionClient:         distributionClient,
+		registryClient:             registryClient,
+		httpClient:                 newLoadClient(c.Transport, c.AllowPrivateLoadURLs),
+		pullTokens:                 tokens,
+		pulls:                      newPullGroup(),
+		pushes:                     newPushTracker(),
+		maxModelBytes:              c.MaxModelBytes,
+		allowMaxModelBytesOverride: c.AllowMaxModelBytesOverride,
+		maxStoreBytes:              c.MaxStoreBytes,
+		maxLoadBytes:               c.MaxLoadBytes,
+		defaultContextSize:         c.DefaultContextSize,
+		purgeTimeout:               purgeTimeout,
+		preflightPulls:             c.PreflightPulls,
+		metrics:                    c.Metrics,
+	}
+	c.Metrics.SetStoreSize(m.GetDiskUsage)
+	m.resolver = defaultResolver{manager: m}
+	if c.Resolver != nil {
+		m.resolver = c.Resolver(m.resolver)
+	}
+	return m
+}
+
+// DefaultContextSize returns the context size to use for a model whose config
+// sets none when no context size is configured for the run, or nil to leave it
+// to the backend. The configured default is capped at the context length the
+// model was trained with, if its metadata reports one.
+func (m *Manager) DefaultContextSize(config types.ModelConfig) *int32 {
+	if m.defaultContextSize <= 0 {
+		return nil
+	}
+	size := m.defaultContextSize
+	if trained, ok := trainedContextSize(config); ok {
+		size = min(size, trained)
+	}
+	return &size
+}
+
+// modelLock is the lock state of a model.
+type modelLock struct {
+	// pins counts the LockModel calls not yet matched by UnlockModel. Pinned
+	// models are never evicted.
+	pins int
+	// mu serializes operations that resolve the model and then modify it.
+	mu sync.Mutex
+	// waiters counts the goroutines holding or waiting for mu.
+	waiters int
+}
+
+// lockState returns the lock state of the model with the given ID, creating
+// it if needed. locksMu must be held.
+func (m *Manager) lockState(id string) *modelLock {
+	if m.locks == nil {
+		m.locks = make(map[string]*modelLock)
+	}
+	l, ok := m.locks[id]
+	if !ok {
+		l = &modelLock{}
+		m.locks[id] = l
+	}
+	return l
+}
+
+// releaseLockState drops the lock state of the model with the given ID once
+// nothing pins, holds or waits for it. locksMu must be held.
+func (m *Manager) releaseLockState(id string, l *modelLock) {
+	if l.pins == 0 && l.waiters == 0 {
+		delete(m.locks, id)
+	}
+}
+
+// LockModel prevents the model with the given ID from being evicted from the
+// store until a matching call to UnlockModel.
+func (m *Manager) LockModel(id string) {
+	m.locksMu.Lock()
+	defer m.locksMu.Unlock()
+	m.lockState(id).pins++
+}
+
+// UnlockModel releases a lock taken with LockModel.
+func (m *Manager) UnlockModel(id string) {
+	m.locksMu.Lock()
+	defer m.locksMu.Unlock()
+	l, ok := m.locks[id]
+	if !ok || l.pins == 0 {
+		return
+	}
+	l.pins--
+	m.releaseLockState(id, l)
+}
+
+// isLocked reports whether the model with the given ID is locked with
+// LockModel.
+func (m *Manager) isLocked(id string) bool {
+	m.locksMu.Lock()
+	defer m.locksMu.Unlock()
+	l, ok := m.locks[id]
+	return ok && l.pins > 0
+}
+
+// lockForUpdate acquires exclusive access to the model with the given ID,
+// blocking until it is available, and returns the function that releases it.
+// It doesn't pin the model against eviction.
+func (m *Manager) lockForUpdate(id string) func() {
+	m.locksMu.Lock()
+	l := m.lockState(id)
+	l.waiters++
+	m.locksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+
+		m.locksMu.Lock()
+		defer m.locksMu.Unlock()
+		l.waiters--
+		m.releaseLockState(id, l)
+	}
+}
+
+// enforceStoreLimit evicts least recently used models once the store exceeds
+// maxStoreBytes. The model just pulled as ref and locked models are retained.
+func (m *Manager) enforceStoreLimit(ref string) {
+	if m.maxStoreBytes <= 0 {
+		return
+	}
+	pulledID := ""
+	if model, err := m.distributionClient.GetModel(ref); err == nil {
+		pulledID, _ = model.ID()
+	}
+	evicted, err := m.distributionClient.EvictModels(m.maxStoreBytes, func(id string) bool {
+		return id == pulledID || m.isLocked(id)
+	})
+	if err != nil {
+		m.log.Warn("Failed to enforce store size limit", "maxStoreBytes", m.maxStoreBytes, "error", err)
+	}
+	if len(evicted) > 0 {
+		m.log.Info("Evicted models to enforce store size limit", "count", len(evicted), "maxStoreBytes", m.maxStoreBytes)
 	}
 }
 
@@ -86,11 +341,33 @@ func (m *Manager) GetLocal(ref string) (types.Model, error) {
 	return model, nil
 }
 
+// ResolveLocal returns the local model ref refers to, resolving it with the
+// model resolver first, so that partial names and ID prefixes are accepted.
+func (m *Manager) ResolveLocal(ref string) (types.Model, error) {
+	resolved, err := m.resolveLocalRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	return m.GetLocal(resolved)
+}
+
+// resolveLocalRef returns the reference the model resolver resolves ref to.
+func (m *Manager) resolveLocalRef(ref string) (string, error) {
+	if m.distributionClient == nil {
+		return "", fmt.Errorf("model distribution service unavailable")
+	}
+	resolved, err := m.resolver.ResolveLocal(ref)
+	if err != nil {
+		return "", fmt.Errorf("error while getting model: %w", err)
+	}
+	return resolved, nil
+}
+
 // ResolveID resolves a model reference to a model ID. If resolution fails, it returns the original ref.
 func (m *Manager) ResolveID(modelRef string) string {
 	// Sanitize modelRef to prevent log forgery
 	sanitizedModelRef := utils.SanitizeForLog(modelRef, -1)
-	model, err := m.GetLocal(sanitizedModelRef)
+	model, err := m.ResolveLocal(sanitizedModelRef)
 	if err != nil {
 		m.log.Warn("Failed to resolve model ref to ID", "model", sanitizedModelRef, "error", err)
 		return sanitizedModelRef
@@ -129,6 +406,84 @@ func (m *Manager) GetRemote(ctx context.Context, ref string) (types.ModelArtifac
 	return model, nil
 }
 
+// GetRemoteIndex returns the image index at ref, for remote models published
+// as a multi-platform manifest list rather than a single model.
+func (m *Manager) GetRemoteIndex(ctx context.Context, ref string) (*oci.IndexManifest, oci.Hash, error) {
+	if m.registryClient == nil {
+		return nil, oci.Hash{}, fmt.Errorf("model registry service unavailable")
+	}
+	index, digest, err := m.registryClient.Index(ctx, ref)
+	if err != nil {
+		return nil, oci.Hash{}, fmt.Errorf("error while getting remote index: %w", err)
+	}
+	return index, digest, nil
+}
+
+// ListRemoteTags returns the tags available in a remote repository.
+func (m *Manager) ListRemoteTags(ctx context.Context, repo string) ([]string, error) {
+	if m.registryClient == nil {
+		return nil, fmt.Errorf("model registry service unavailable")
+	}
+	tags, err := m.registryClient.ListTags(ctx, repo)
+	if err != nil {
+		return nil, fmt.Errorf("error while listing remote tags: %w", err)
+	}
+	return tags, nil
+}
+
+// CheckUpdate compares the manifest digest of the local model tagged ref with
+// the digest the tag currently points to in its registry. Short names resolve
+// to the local tag they refer to, as for pushes.
+func (m *Manager) CheckUpdate(ctx context.Context, ref string) (*ModelUpdateCheck, error) {
+	if m.distributionClient == nil {
+		return nil, fmt.Errorf("model distribution service unavailable")
+	}
+	tag := m.distributionClient.NormalizeModelName(m.ResolvePushReference(ref))
+	if parsed := reference.Parse(tag); parsed.Name == "" || parsed.Digest != "" || distribution.IsHuggingFaceReference(tag) {
+		return nil, fmt.Errorf("%w: %s", ErrUpdateCheckUnsupported, ref)
+	}
+
+	local, err := m.GetLocal(tag)
+	if err != nil {
+		return nil, err
+	}
+	localDigest, err := local.ID()
+	if err != nil {
+		return nil, fmt.Errorf("error while getting model ID: %w", err)
+	}
+	remote, err := m.GetRemote(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+	remoteDigest, err := remote.Digest()
+	if err != nil {
+		return nil, fmt.Errorf("error while getting remote model digest: %w", err)
+	}
+	upToDate, err := m.distributionClient.IsUpToDate(tag, remoteDigest)
+	if err != nil {
+		return nil, fmt.Errorf("error while comparing model digests: %w", err)
+	}
+	return &ModelUpdateCheck{
+		UpdateAvailable: !upToDate,
+		LocalDigest:     localDigest,
+		RemoteDigest:    remoteDigest.String(),
+	}, nil
+}
+
+// Verify hashes the blobs of the local model ref refers to and reports those
+// whose content does not match their digest or that are missing.
+func (m *Manager) Verify(ref string) (*distribution.VerifyModelResponse, error) {
+	resolved, err := m.resolveLocalRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	report, err := m.distributionClient.VerifyModel(resolved)
+	if err != nil {
+		return nil, fmt.Errorf("error while verifying model: %w", err)
+	}
+	return report, nil
+}
+
 // GetRemoteBlobURL returns the URL of a given model blob.
 func (m *Manager) GetRemoteBlobURL(ref string, digest oci.Hash) (string, error) {
 	blobURL, err := m.registryClient.BlobURL(ref, digest)
@@ -138,6 +493,89 @@ func (m *Manager) GetRemoteBlobURL(ref string, digest oci.Hash) (string, error)
 	return blobURL, nil
 }
 
+// RemoteGGUFArchitecture returns the architecture recorded in the GGUF header
+// of the remote model ref, fetching only the header with range requests, or
+// an empty string if the model has no GGUF layer. Sharded models are
+// identified by their first shard. bearerToken, if set, is used instead of
+// the registry's token, and rawReference skips normalizing ref, as for pulls.
+func (m *Manager) RemoteGGUFArchitecture(ctx context.Context, ref, bearerToken string, rawReference bool) (string, error) {
+	if !rawReference && m.distributionClient != nil {
+		ref = m.distributionClient.NormalizeModelName(ref)
+	}
+	model, err := m.GetRemote(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := model.Manifest()
+	if err != nil {
+		return "", fmt.Errorf("error while reading remote manifest: %w", err)
+	}
+	idx := slices.IndexFunc(manifest.Layers, func(layer oci.Descriptor) bool {
+		return layer.MediaType == types.MediaTypeGGUF
+	})
+	if idx == -1 {
+		return "", nil
+	}
+	blobURL, err := m.GetRemoteBlobURL(ref, manifest.Layers[idx].Digest)
+	if err != nil {
+		return "", err
+	}
+	if bearerToken == "" {
+		if bearerToken, err = m.BearerTokenForModel(ctx, ref); err != nil {
+			return "", err
+		}
+	}
+	var opts []parser.GGUFReadOption
+	if bearerToken != "" {
+		opts = append(opts, parser.UseBearerAuth(bearerToken))
+	}
+	// The parser buffers reads until its context is done, so stop it once the
+	// header has been read.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	gguf, err := parser.ParseGGUFFileRemote(ctx, blobURL, opts...)
+	if err != nil {
+		return "", fmt.Errorf("error while reading remote GGUF header: %w", err)
+	}
+	return strings.TrimSpace(gguf.Metadata().Architecture), nil
+}
+
+// OpenRemoteBlob opens a layer or config blob of a remote model, returning
+// its size as recorded in the model's manifest. The returned reader fails its
+// final read if the content does not match digest.
+func (m *Manager) OpenRemoteBlob(ctx context.Context, ref string, digest oci.Hash) (io.ReadCloser, int64, error) {
+	model, err := m.GetRemote(ctx, ref)
+	if err != nil {
+		return nil, 0, err
+	}
+	manifest, err := model.Manifest()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error while reading remote manifest: %w", err)
+	}
+
+	if manifest.Config.Digest == digest {
+		// The config blob is small and verified when it is fetched.
+		raw, err := model.RawConfigFile()
+		if err != nil {
+			return nil, 0, fmt.Errorf("error while fetching remote config: %w", err)
+		}
+		return io.NopCloser(bytes.NewReader(raw)), manifest.Config.Size, nil
+	}
+	layer, err := model.LayerByDigest(digest)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: %s", ErrBlobNotFound, digest)
+	}
+	size, err := layer.Size()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error while reading remote blob size: %w", err)
+	}
+	rc, err := layer.Compressed()
+	if err != nil {
+		return nil, 0, fmt.Errorf("error while fetching remote blob: %w", err)
+	}
+	return rc, size, nil
+}
+
 // BearerTokenForModel returns the bearer token needed to pull a given model.
 func (m *Manager) BearerTokenForModel(ctx context.Context, ref string) (string, error) {
 	tok, err := m.registryClient.BearerToken(ctx, ref)
@@ -147,9 +585,92 @@ func (m *Manager) BearerTokenForModel(ctx context.Context, ref string) (string,
 	return tok, nil
 }
 
+// GetRawManifest returns the stored manifest of a local model exactly as it
+// was written, along with its media type.
+func (m *Manager) GetRawManifest(ref string) ([]byte, string, error) {
+	artifact, err := m.getLocalArtifact(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	raw, err := artifact.RawManifest()
+	if err != nil {
+		return nil, "", fmt.Errorf("error while reading manifest: %w", err)
+	}
+	mediaType, err := artifact.MediaType()
+	if err != nil {
+		return nil, "", fmt.Errorf("error while reading manifest media type: %w", err)
+	}
+	return raw, string(mediaType), nil
+}
+
+// GetRawConfig returns the stored config blob of a local model exactly as it
+// was written, along with its media type.
+func (m *Manager) GetRawConfig(ref string) ([]byte, string, error) {
+	artifact, err := m.getLocalArtifact(ref)
+	if err != nil {
+		return nil, "", err
+	}
+	raw, err := artifact.RawConfigFile()
+	if err != nil {
+		return nil, "", fmt.Errorf("error while reading config: %w", err)
+	}
+	manifest, err := artifact.Manifest()
+	if err != nil {
+		return nil, "", fmt.Errorf("error while reading manifest: %w", err)
+	}
+	return raw, string(manifest.Config.MediaType), nil
+}
+
+// Diff compares two local models.
+func (m *Manager) Diff(a, b string) (*ModelDiff, error) {
+	artifactA, err := m.getLocalArtifact(a)
+	if err != nil {
+		return nil, err
+	}
+	artifactB, err := m.getLocalArtifact(b)
+	if err != nil {
+		return nil, err
+	}
+	return diffModels(artifactA, artifactB)
+}
+
+// modelSize returns the total size of the layers of a local model.
+func (m *Manager) modelSize(ref string) (int64, error) {
+	artifact, err := m.getLocalArtifact(ref)
+	if err != nil {
+		return 0, err
+	}
+	manifest, err := artifact.Manifest()
+	if err != nil {
+		return 0, fmt.Errorf("error while reading manifest: %w", err)
+	}
+	var size int64
+	for _, layer := range manifest.Layers {
+		size += layer.Size
+	}
+	return size, nil
+}
+
+// getLocalArtifact returns a local model as an OCI artifact.
+func (m *Manager) getLocalArtifact(ref string) (types.ModelArtifact, error) {
+	model, err := m.GetLocal(ref)
+	if err != nil {
+		return nil, err
+	}
+	artifact, ok := model.(types.ModelArtifact)
+	if !ok {
+		return nil, fmt.Errorf("model %q does not expose its manifest", utils.SanitizeForLog(ref, -1))
+	}
+	return artifact, nil
+}
+
 // GetBundle returns model bundle.
 func (m *Manager) GetBundle(ref string) (types.ModelBundle, error) {
-	bundle, err := m.distributionClient.GetBundle(ref)
+	resolved, err := m.resolveLocalRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	bundle, err := m.distributionClient.GetBundle(resolved)
 	if err != nil {
 		return nil, fmt.Errorf("error while getting model bundle: %w", err)
 	}
@@ -177,10 +698,38 @@ func (m *Manager) List() ([]*Model, error) {
 		}
 		apiModels = append(apiModels, apiModel)
 	}
+	m.setLastUsed(apiModels...)
 
 	return apiModels, nil
 }
 
+// MarkUsed records that the model with the given reference was targeted by an
+// inference request now.
+func (m *Manager) MarkUsed(ref string) error {
+	if m.distributionClient == nil {
+		return fmt.Errorf("model distribution service unavailable")
+	}
+	return m.distributionClient.MarkModelUsed(ref)
+}
+
+// setLastUsed fills in the LastUsed timestamp of the given models from the
+// store's access times.
+func (m *Manager) setLastUsed(apiModels ...*Model) {
+	if m.distributionClient == nil {
+		return
+	}
+	times, err := m.distributionClient.LastUsedTimes()
+	if err != nil {
+		m.log.Warn("error while reading model access times", "error", err)
+		return
+	}
+	for _, apiModel := range apiModels {
+		if t, ok := times[apiModel.ID]; ok {
+			apiModel.LastUsed = t.Unix()
+		}
+	}
+}
+
 func (m *Manager) RawList() ([]types.Model, error) {
 	if m.distributionClient == nil {
 		return nil, fmt.Errorf("model distribution models unavailable")
@@ -197,6 +746,23 @@ func (m *Manager) Delete(reference string, force bool) (*distribution.DeleteMode
 	if m.distributionClient == nil {
 		return nil, errors.New("model distribution service unavailable")
 	}
+	leave, err := m.storeGate.enter(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer leave()
+
+	// Lock the model so that it is not tagged while it is being deleted. A
+	// reference that does not resolve is passed through to report the error.
+	if resolved, err := m.resolver.ResolveLocal(reference); err == nil {
+		reference = resolved
+		if model, err := m.distributionClient.GetModel(reference); err == nil {
+			if id, err := model.ID(); err == nil {
+				unlock := m.lockForUpdate(id)
+				defer unlock()
+			}
+		}
+	}
 
 	resp, err := m.distributionClient.DeleteModel(reference, force)
 	if err != nil {
@@ -205,18 +771,50 @@ func (m *Manager) Delete(reference string, force bool) (*distribution.DeleteMode
 	return resp, nil
 }
 
-// Pull pulls a model to local storage. Any error it returns is suitable
-// for writing back to the client.
-func (m *Manager) Pull(model string, bearerToken string, r *http.Request, w http.ResponseWriter) error {
-	// Restrict model pull concurrency.
-	select {
-	case <-m.pullTokens:
-	case <-r.Context().Done():
-		return context.Canceled
+// DropLayer rewrites the model reference refers to without its layers of the
+// given media type, removing their blobs unless another model references
+// them. It returns the ID of the rewritten model.
+func (m *Manager) DropLayer(reference string, mediaType string) (string, error) {
+	if m.distributionClient == nil {
+		return "", errors.New("model distribution service unavailable")
+	}
+	leave, err := m.storeGate.enter(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer leave()
+
+	// Lock the model so that it is not tagged while it is being rewritten. A
+	// reference that does not resolve is passed through to report the error.
+	if resolved, err := m.resolver.ResolveLocal(reference); err == nil {
+		reference = resolved
+		if model, err := m.distributionClient.GetModel(reference); err == nil {
+			if id, err := model.ID(); err == nil {
+				unlock := m.lockForUpdate(id)
+				defer unlock()
+			}
+		}
+	}
+
+	id, err := m.distributionClient.DropLayer(reference, oci.MediaType(mediaType))
+	if err != nil {
+		return "", fmt.Errorf("error while dropping model layer: %w", err)
+	}
+	return id, nil
+}
+
+// Pull pulls a model to local storage, tagging it with req.As if set. Any
+// error it returns is suitable for writing back to the client, except
+// ErrPulledModelNotTagged, which has already been written to the progress
+// stream.
+func (m *Manager) Pull(req ModelCreateRequest, r *http.Request, w http.ResponseWriter) error {
+	// Validate the alias before anything is streamed, so that an invalid one
+	// can still be rejected with a status code.
+	if req.As != "" {
+		if _, err := reference.NewTag(req.As, registry.GetDefaultRegistryOptions()...); err != nil {
+			return fmt.Errorf("%w: alias %q: %w", registry.ErrInvalidReference, req.As, err)
+		}
 	}
-	defer func() {
-		m.pullTokens <- struct{}{}
-	}()
 
 	// Set up response headers for streaming
 	w.Header().Set("Cache-Control", "no-cache")
@@ -242,134 +840,262 @@ func (m *Manager) Pull(model string, bearerToken string, r *http.Request, w http
 
 	// Create a progress writer that writes to the response
 	progressWriter := &progressResponseWriter{
-		writer:  w,
-		flusher: flusher,
-		isJSON:  isJSON,
+		writer:     w,
+		flusher:    flusher,
+		isJSON:     isJSON,
+		escapeHTML: escapeProgressHTML(r),
 	}
 
-	// Pull the model using the Docker model distribution client
-	m.log.Info("pulling model", "model", utils.SanitizeForLog(model, -1))
-
-	// Use bearer token if provided
-	var err error
-	if bearerToken != "" {
-		m.log.Info("Using provided bearer token for authentication")
-		err = m.distributionClient.PullModel(r.Context(), model, progressWriter, bearerToken)
-	} else {
-		err = m.distributionClient.PullModel(r.Context(), model, progressWriter)
+	maxBytes := m.maxModelBytes
+	if req.MaxModelBytes != nil {
+		if !m.allowMaxModelBytesOverride {
+			return ErrMaxModelBytesOverrideNotAllowed
+		}
+		maxBytes = *req.MaxModelBytes
 	}
 
+	id, err := m.pulls.do(r.Context(), m.pullKey(req, maxBytes), progressWriter, func(ctx context.Context, w io.Writer) (string, error) {
+		// Restrict model pull concurrency.
+		select {
+		case <-m.pullTokens:
+		case <-ctx.Done():
+			return "", context.Canceled
+		}
+		defer func() {
+			m.pullTokens <- struct{}{}
+		}()
+
+		// Hold the store gate while downloading so that the store is not
+		// purged part way through. It is only taken once the pull may start,
+		// so that queued pulls don't hold up a purge.
+		leave, err := m.storeGate.enter(ctx)
+		if err != nil {
+			return "", err
+		}
+		defer leave()
+
+		finished := m.metrics.PullStarted()
+		// Pull the model using the Docker model distribution client
+		start := time.Now()
+		m.log.Info("pulling model", logging.Model(req.From), "force", req.Force)
+
+		// Use bearer token if provided
+		if req.BearerToken != "" {
+			m.log.Info("Using provided bearer token for authentication")
+		}
+		id, err := m.distributionClient.PullModelWithOptions(ctx, req.From, w, distribution.PullOptions{
+			BearerToken:  req.BearerToken,
+			Force:        req.Force,
+			MaxBytes:     maxBytes,
+			RawReference: req.RawReference,
+		})
+		finished(err)
+		if err != nil {
+			m.log.Warn("model pull failed", logging.Model(req.From), logging.Duration(start), "error", err)
+			return "", err
+		}
+		m.log.Info("model pull completed", logging.Model(req.From), logging.Duration(start))
+		m.enforceStoreLimit(req.From)
+		return id, nil
+	})
+
 	if err != nil {
 		return fmt.Errorf("error while pulling model: %w", err)
 	}
 
+	// Tag outside the coalesced pull, since requests sharing a download may
+	// ask for different aliases. The pulled ID is tagged rather than req.From,
+	// which may have been moved by another pull since. Tag holds the model's
+	// lock, so the alias is either applied to the pulled model or not at all
+	// if it was deleted or purged in the meantime.
+	if req.As != "" {
+		if _, err := m.Tag(id, req.As); err != nil {
+			m.log.Warn("failed to tag pulled model", logging.Model(req.From), "target", utils.SanitizeForLog(req.As, -1), "error", err)
+			data, marshalErr := json.Marshal(oci.ProgressMessage{
+				Type:    oci.TypeError,
+				Message: fmt.Sprintf("Error: tagging pulled model as %s: %v", req.As, err),
+				Mode:    oci.ModePull,
+			})
+			if marshalErr == nil {
+				_, _ = progressWriter.Write(append(data, '\n'))
+			}
+			return fmt.Errorf("%w as %q: %w", ErrPulledModelNotTagged, req.As, err)
+		}
+		m.log.Info("tagged pulled model", logging.Model(req.From), logging.Digest(id), "target", utils.SanitizeForLog(req.As, -1))
+	}
+
 	return nil
 }
 
-func (m *Manager) Load(r io.Reader, progressWriter io.Writer) error {
+// pullKey identifies a pull for coalescing. Concurrent pulls of the same model
+// with the same options share a single download. The bearer token is part of
+// the key so that a pull is never served with another client's credentials.
+func (m *Manager) pullKey(req ModelCreateRequest, maxBytes int64) string {
+	from := req.From
+	if !req.RawReference {
+		from = m.distributionClient.NormalizeModelName(from)
+	}
+	return strings.Join([]string{
+		from,
+		strconv.FormatBool(req.RawReference),
+		req.BearerToken,
+		strconv.FormatBool(req.Force),
+		strconv.FormatInt(maxBytes, 10),
+	}, "\x00")
+}
+
+// Load imports a model tarball from r into the store, stopping if ctx is done.
+func (m *Manager) Load(ctx context.Context, r io.Reader, progressWriter io.Writer) error {
+	return m.load(ctx, r, progressWriter, distribution.LoadOptions{})
+}
+
+// load imports a model tarball from r into the store using the given options.
+func (m *Manager) load(ctx context.Context, r io.Reader, progressWriter io.Writer, opts distribution.LoadOptions) error {
 	if m.distributionClient == nil {
 		return fmt.Errorf("model distribution service unavailable")
 	}
-	_, err := m.distributionClient.LoadModel(r, progressWriter)
+	leave, err := m.storeGate.enter(ctx)
+	if err != nil {
+		return fmt.Errorf("error while loading model: %w", err)
+	}
+	defer leave()
+	_, err = m.distributionClient.LoadModelWithOptions(ctx, r, progressWriter, opts)
 	if err != nil {
 		return fmt.Errorf("error while loading model: %w", err)
 	}
 	return nil
 }
 
-func (m *Manager) Tag(ref, target string) error {
-	if m.distributionClient == nil {
-		return fmt.Errorf("model distribution service unavailable")
-	}
-
-	// First try to tag using the provided model reference as-is
-	err := m.distributionClient.Tag(ref, target)
-	if err != nil && errors.Is(err, distribution.ErrModelNotFound) {
-		// Check if the model parameter is a model ID (starts with sha256:) or is a partial name
-		var foundModelRef string
-		found := false
+// maxLoadRedirects is the number of redirects followed when fetching a model
+// archive to load.
+const maxLoadRedirects = 5
 
-		// If it looks like an ID, try to find the model by ID
-		if strings.HasPrefix(ref, "sha256:") || len(ref) == 12 { // 12-char short ID
-			// Get all models and find the one matching this ID
-			models, listErr := m.distributionClient.ListModels()
-			if listErr != nil {
-				return fmt.Errorf("error listing models: %w", listErr)
+// newLoadClient returns the client model archives are fetched with. Unless
+// allowPrivate is set, it only connects to hosts on public networks, since
+// the archive URL is chosen by the client of the API.
+func newLoadClient(transport http.RoundTripper, allowPrivate bool) *http.Client {
+	if !allowPrivate {
+		transport = remote.NewPublicTransport(transport)
+	}
+	return &http.Client{
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= maxLoadRedirects {
+				return fmt.Errorf("%w: stopped after %d redirects", ErrInvalidLoadSource, maxLoadRedirects)
 			}
+			return nil
+		},
+	}
+}
 
-			for _, mModel := range models {
-				modelID, idErr := mModel.ID()
-				if idErr != nil {
-					m.log.Warn("Failed to get model ID", "error", idErr)
-					continue
-				}
-
-				// Check if the model ID matches (can be full or short ID)
-				if modelID == ref || strings.HasPrefix(modelID, ref) {
-					// Use the first tag of this model as the source reference
-					tags := mModel.Tags()
-					if len(tags) > 0 {
-						foundModelRef = tags[0]
-						found = true
-						break
-					}
-				}
-			}
+// LoadFromURL imports the model tarball served at rawURL into the store, as
+// Load does, streaming it without a local copy. If digest is not empty, the
+// archive must match it, and a mismatching archive is discarded with an
+// *oci.DigestMismatchError before the model is committed. URLs and redirects
+// to private networks are rejected with ErrInvalidLoadSource unless
+// ClientConfig.AllowPrivateLoadURLs is set.
+func (m *Manager) LoadFromURL(ctx context.Context, rawURL, digest string, progressWriter io.Writer) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: URL must be an absolute http or https URL", ErrInvalidLoadSource)
+	}
+	var opts distribution.LoadOptions
+	if digest != "" {
+		if opts.Digest, err = oci.NewHash(digest); err != nil {
+			return fmt.Errorf("%w: digest %q: %w", ErrInvalidLoadSource, digest, err)
 		}
+	}
 
-		// If not found by ID, try partial name matching (similar to inspect)
-		if !found {
-			models, listErr := m.distributionClient.ListModels()
-			if listErr != nil {
-				return fmt.Errorf("error listing models: %w", listErr)
-			}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), http.NoBody)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLoadSource, err)
+	}
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return fmt.Errorf("error while loading model: %w", ctxErr)
+		}
+		if errors.Is(err, remote.ErrDisallowedHost) || errors.Is(err, ErrInvalidLoadSource) {
+			return fmt.Errorf("%w: %w", ErrInvalidLoadSource, err)
+		}
+		return fmt.Errorf("%w: %w", ErrLoadSourceUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %s", ErrLoadSourceUnavailable, u.Redacted(), resp.Status)
+	}
 
-			// Look for a model whose tags match the provided reference
-			for _, model := range models {
-				for _, tagStr := range model.Tags() {
-					// Extract the model name without tag part (e.g., from "ai/smollm2:latest" get "ai/smollm2")
-					tagWithoutVersion := tagStr
-					if idx := strings.LastIndex(tagStr, ":"); idx != -1 {
-						tagWithoutVersion = tagStr[:idx]
-					}
-
-					// Get just the name part without organization (e.g., from "ai/smollm2" get "smollm2")
-					namePart := tagWithoutVersion
-					if idx := strings.LastIndex(tagWithoutVersion, "/"); idx != -1 {
-						namePart = tagWithoutVersion[idx+1:]
-					}
-
-					// Check if the provided model matches the name part
-					if namePart == ref {
-						// Found a match - use the tag string that matched as the source reference
-						foundModelRef = tagStr
-						found = true
-						break
-					}
-				}
-				if found {
-					break
-				}
-			}
+	var body io.Reader = resp.Body
+	if m.maxLoadBytes > 0 {
+		if resp.ContentLength > m.maxLoadBytes {
+			return fmt.Errorf("error while loading model: %w", &http.MaxBytesError{Limit: m.maxLoadBytes})
 		}
+		body = http.MaxBytesReader(nil, resp.Body, m.maxLoadBytes)
+	}
+	return m.load(ctx, body, progressWriter, opts)
+}
+
+// Tag adds target as a tag of the model ref resolves to. The model is locked
+// against concurrent deletes from resolution until it is tagged, so the tag
+// never outlives the model. It returns the ID of the tagged model.
+func (m *Manager) Tag(ref, target string) (string, error) {
+	return m.tag(ref, target, distribution.TagOptions{})
+}
+
+// TagPinned tags the model ref resolves to with target, as Tag does, and
+// records ref as the reference target was pinned from. Later pulls of ref that
+// resolve to another model warn that target still points at this one.
+func (m *Manager) TagPinned(ref, target string) (string, error) {
+	return m.tag(ref, target, distribution.TagOptions{PinnedFrom: ref})
+}
+
+func (m *Manager) tag(ref, target string, opts distribution.TagOptions) (string, error) {
+	if m.distributionClient == nil {
+		return "", fmt.Errorf("model distribution service unavailable")
+	}
+	leave, err := m.storeGate.enter(context.Background())
+	if err != nil {
+		return "", err
+	}
+	defer leave()
+
+	id, err := m.resolveTagSource(ref)
+	if err != nil {
+		return "", err
+	}
+
+	unlock := m.lockForUpdate(id)
+	defer unlock()
 
-		if !found {
-			return distribution.ErrModelNotFound
+	// Tag by the full ID rather than one of the model's tags, so that the
+	// model resolved above is tagged even if ref has since moved, and models
+	// with several tags (or none) resolve to the same manifest
+	if err := m.distributionClient.TagWithOptions(id, target, opts); err != nil {
+		if errors.Is(err, distribution.ErrModelNotFound) {
+			// The model was deleted after it was resolved
+			return "", distribution.ErrModelNotFound
 		}
+		m.log.Warn("Failed to apply tag to resolved model", "target", utils.SanitizeForLog(target, -1), "model", utils.SanitizeForLog(ref, -1), "error", err)
+		return "", fmt.Errorf("error while tagging model: %w", err)
+	}
+	return id, nil
+}
 
-		// Now tag using the found model reference (the matching tag)
-		if tagErr := m.distributionClient.Tag(foundModelRef, target); tagErr != nil {
-			m.log.Warn("Failed to apply tag to resolved model", "target", utils.SanitizeForLog(target, -1), "model", utils.SanitizeForLog(foundModelRef, -1), "error", tagErr)
-			return fmt.Errorf("error while tagging model: %w", tagErr)
+// resolveTagSource returns the ID of the local model the model resolver
+// resolves ref to.
+func (m *Manager) resolveTagSource(ref string) (string, error) {
+	model, err := m.ResolveLocal(ref)
+	if err != nil {
+		if errors.Is(err, distribution.ErrModelNotFound) {
+			return "", distribution.ErrModelNotFound
 		}
-	} else if err != nil {
-		return fmt.Errorf("error while tagging model: %w", err)
+		return "", fmt.Errorf("error while tagging model: %w", err)
 	}
-	return nil
+	return model.ID()
 }
 
 // Push pushes a model from the store to the registry.
-func (m *Manager) Push(model string, bearerToken string, r *http.Request, w http.ResponseWriter) error {
+func (m *Manager) Push(model string, req ModelPushRequest, r *http.Request, w http.ResponseWriter) error {
 	// Set up response headers for streaming
 	w.Header().Set("Cache-Control", "no-cache")
 	w.Header().Set("Connection", "keep-alive")
@@ -393,29 +1119,78 @@ func (m *Manager) Push(model string, bearerToken string, r *http.Request, w http
 
 	// Create a progress writer that writes to the response
 	progressWriter := &progressResponseWriter{
-		writer:  w,
-		flusher: flusher,
-		isJSON:  isJSON,
+		writer:     w,
+		flusher:    flusher,
+		isJSON:     isJSON,
+		escapeHTML: escapeProgressHTML(r),
 	}
 
-	var err error
-	if bearerToken != "" {
+	ctx, done := m.pushes.start(r.Context(), pushKey(model))
+	defer done()
+
+	leave, err := m.storeGate.enter(ctx)
+	if err != nil {
+		return fmt.Errorf("error while pushing model: %w", err)
+	}
+	defer leave()
+
+	start := time.Now()
+	if req.BearerToken != "" {
 		m.log.Info("Using provided bearer token for push authentication")
-		err = m.distributionClient.PushModel(r.Context(), model, progressWriter, bearerToken)
-	} else {
-		err = m.distributionClient.PushModel(r.Context(), model, progressWriter)
 	}
+	err = m.distributionClient.PushModelWithOptions(ctx, model, progressWriter, distribution.PushOptions{
+		BearerToken: req.BearerToken,
+		Compression: distribution.Compression(req.Compression),
+	})
 	if err != nil {
+		m.log.Warn("model push failed", logging.Model(model), logging.Duration(start), "error", err)
 		return fmt.Errorf("error while pushing model: %w", err)
 	}
+	m.log.Info("model push completed", logging.Model(model), logging.Duration(start))
 
 	return nil
 }
 
+// CancelPush cancels the pushes of model in progress, reporting whether there
+// were any.
+func (m *Manager) CancelPush(model string) bool {
+	return m.pushes.cancel(pushKey(m.ResolvePushReference(model)))
+}
+
+// pushKey returns the key pushes of model are tracked by, so that a push can
+// be cancelled using any equivalent reference.
+func pushKey(model string) string {
+	if target, err := pushTarget(model); err == nil {
+		return target
+	}
+	return model
+}
+
+// Prune deletes every model without tags.
+func (m *Manager) Prune() (*distribution.PruneModelsResponse, error) {
+	if m.distributionClient == nil {
+		return nil, errors.New("model distribution service unavailable")
+	}
+	resp, err := m.distributionClient.PruneModels()
+	if err != nil {
+		return nil, fmt.Errorf("error while pruning models: %w", err)
+	}
+	return resp, nil
+}
+
+// Purge deletes every model. It blocks new operations writing to the store
+// and waits for the ones in progress to finish, failing with ErrStoreBusy if
+// they do not finish within the purge timeout.
 func (m *Manager) Purge() error {
 	if m.distributionClient == nil {
 		return fmt.Errorf("model distribution service unavailable")
 	}
+	release, err := m.storeGate.exclusive(m.purgeTimeout)
+	if err != nil {
+		m.log.Warn("Failed to lock model store for purge", "error", err)
+		return fmt.Errorf("error while purging models: %w", err)
+	}
+	defer release()
 	if err := m.distributionClient.ResetStore(); err != nil {
 		m.log.Warn("Failed to purge models", "error", err)
 		return fmt.Errorf("error while purging models: %w", err)
@@ -423,6 +1198,48 @@ func (m *Manager) Purge() error {
 	return nil
 }
 
+// SetBackendUnloader sets the function Shutdown unloads the models running on
+// the backends with, returning how many it unloaded. It must be called before
+// the manager is shut down.
+func (m *Manager) SetBackendUnloader(unload func(ctx context.Context) int) {
+	m.unloadBackends = unload
+}
+
+// Shutdown cancels the pulls and pushes in progress, unloads the backends and
+// waits for the operations writing to the store to finish, so that the
+// process can exit with the store consistent. Cancelled downloads are kept
+// for resuming once the model runner restarts. The store stays closed to
+// writes afterwards, so Shutdown should be called once the server stops
+// accepting requests. If ctx has no deadline, the purge timeout bounds the
+// wait for writes.
+func (m *Manager) Shutdown(ctx context.Context) error {
+	pullsFinished := m.pulls.cancelAll()
+	m.pushes.cancelAll()
+
+	if m.unloadBackends != nil {
+		if unloaded := m.unloadBackends(ctx); unloaded > 0 {
+			m.log.Info("Unloaded backend runners for shutdown", "count", unloaded)
+		}
+	}
+
+	select {
+	case <-pullsFinished:
+	case <-ctx.Done():
+		return fmt.Errorf("error while waiting for pulls to stop: %w", ctx.Err())
+	}
+
+	timeout := m.purgeTimeout
+	if deadline, ok := ctx.Deadline(); ok {
+		timeout = time.Until(deadline)
+	}
+	// The gate is never released, so nothing writes to the store once the
+	// operations in progress have finished.
+	if _, err := m.storeGate.exclusive(timeout); err != nil {
+		return fmt.Errorf("error while waiting for store writes to finish: %w", err)
+	}
+	return nil
+}
+
 func (m *Manager) Export(ref string, w io.Writer) error {
 	if m.distributionClient == nil {
 		return fmt.Errorf("model distribution service unavailable")
@@ -432,13 +1249,20 @@ func (m *Manager) Export(ref string, w io.Writer) error {
 
 type RepackageOptions struct {
 	ContextSize *uint64 `json:"context_size,omitempty"`
+	Force       bool    `json:"force,omitempty"`
 }
 
 func (m *Manager) Repackage(sourceRef string, targetRef string, opts RepackageOptions) error {
 	if m.distributionClient == nil {
 		return fmt.Errorf("model distribution service unavailable")
 	}
+	leave, err := m.storeGate.enter(context.Background())
+	if err != nil {
+		return err
+	}
+	defer leave()
 	return m.distributionClient.RepackageModel(sourceRef, targetRef, distribution.RepackageOptions{
 		ContextSize: opts.ContextSize,
+		Force:       opts.Force,
 	})
 }
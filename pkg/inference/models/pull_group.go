@@ -0,0 +1,134 @@
+package models
+
+import (
+	"context"
+	"io"
+	"sync"
+)
+
+// pullGroup coalesces concurrent pulls of the same model so that they share a
+// single download. Every subscriber receives the progress written from the
+// point it joined, and all of them receive the shared result.
+type pullGroup struct {
+	mu    sync.Mutex
+	pulls map[string]*sharedPull
+}
+
+// sharedPull is a pull in progress along with its subscribers. It implements
+// io.Writer by fanning writes out to every subscriber.
+type sharedPull struct {
+	// cancel cancels the pull once all subscribers have left.
+	cancel context.CancelFunc
+	// done is closed once the pull has finished and id and err are set.
+	done chan struct{}
+	id   string
+	err  error
+
+	// mu guards subscribers and serializes writes to them.
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber is a single caller waiting on a shared pull.
+type subscriber struct {
+	w io.Writer
+}
+
+func newPullGroup() *pullGroup {
+	return &pullGroup{pulls: make(map[string]*sharedPull)}
+}
+
+// do runs pull for key unless a pull for key is already in progress, in which
+// case it waits for that pull instead, and returns the ID of the pulled model.
+// Progress is written to w until the pull finishes or ctx is done. The shared
+// pull runs on a context detached from that of any caller and is only
+// cancelled once every subscriber has left.
+func (g *pullGroup) do(ctx context.Context, key string, w io.Writer, pull func(ctx context.Context, w io.Writer) (string, error)) (string, error) {
+	sub := &subscriber{w: w}
+
+	g.mu.Lock()
+	p, ok := g.pulls[key]
+	if !ok {
+		pullCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		p = &sharedPull{
+			cancel:      cancel,
+			done:        make(chan struct{}),
+			subscribers: make(map[*subscriber]struct{}),
+		}
+		g.pulls[key] = p
+		go func() {
+			defer cancel()
+			p.id, p.err = pull(pullCtx, p)
+			g.mu.Lock()
+			if g.pulls[key] == p {
+				delete(g.pulls, key)
+			}
+			g.mu.Unlock()
+			close(p.done)
+		}()
+	}
+	p.mu.Lock()
+	p.subscribers[sub] = struct{}{}
+	p.mu.Unlock()
+	g.mu.Unlock()
+
+	select {
+	case <-p.done:
+		g.leave(key, p, sub)
+		return p.id, p.err
+	case <-ctx.Done():
+		g.leave(key, p, sub)
+		return "", ctx.Err()
+	}
+}
+
+// cancelAll cancels every pull in progress and returns a channel that is
+// closed once they have all finished.
+func (g *pullGroup) cancelAll() <-chan struct{} {
+	g.mu.Lock()
+	pulls := make([]*sharedPull, 0, len(g.pulls))
+	for key, p := range g.pulls {
+		pulls = append(pulls, p)
+		delete(g.pulls, key)
+		p.cancel()
+	}
+	g.mu.Unlock()
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		for _, p := range pulls {
+			<-p.done
+		}
+	}()
+	return finished
+}
+
+// leave unsubscribes sub from p, cancelling p if it was the last subscriber.
+// Once leave returns, nothing more is written to the subscriber.
+func (g *pullGroup) leave(key string, p *sharedPull, sub *subscriber) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.subscribers, sub)
+	if len(p.subscribers) > 0 {
+		return
+	}
+	// Stop new callers from joining a cancelled pull.
+	if g.pulls[key] == p {
+		delete(g.pulls, key)
+	}
+	p.cancel()
+}
+
+// Write implements io.Writer. Errors from individual subscribers are ignored
+// so that a disconnected client does not fail the pull for the others.
+func (p *sharedPull) Write(b []byte) (int, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for sub := range p.subscribers {
+		_, _ = sub.w.Write(b)
+	}
+	return len(b), nil
+}
@@ -0,0 +1,277 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/model-runner/pkg/distribution/builder"
+	reg "github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// subscriberCount returns the number of subscribers of the pull for key.
+func (g *pullGroup) subscriberCount(key string) int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	p, ok := g.pulls[key]
+	if !ok {
+		return 0
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.subscribers)
+}
+
+// waitForSubscribers waits until the pull for key has n subscribers. It
+// reports an error and returns false if that does not happen in time.
+func waitForSubscribers(t *testing.T, g *pullGroup, key string, n int) bool {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for g.subscriberCount(key) != n {
+		if time.Now().After(deadline) {
+			t.Errorf("Timed out waiting for %d subscribers, have %d", n, g.subscriberCount(key))
+			return false
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return true
+}
+
+func TestPullGroupCoalescesConcurrentPulls(t *testing.T) {
+	g := newPullGroup()
+	var calls atomic.Int32
+	release := make(chan struct{})
+	errPull := errors.New("pull result")
+	pull := func(ctx context.Context, w io.Writer) (string, error) {
+		calls.Add(1)
+		<-release
+		_, _ = w.Write([]byte("progress\n"))
+		return "sha256:pulled", errPull
+	}
+
+	var outputs [2]syncBuffer
+	ids := make([]string, 2)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for i := range outputs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids[i], errs[i] = g.do(t.Context(), "model", &outputs[i], pull)
+		}()
+	}
+	if !waitForSubscribers(t, g, "model", 2) {
+		close(release)
+		t.FailNow()
+	}
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("Expected a single pull, got %d", n)
+	}
+	for i := range outputs {
+		if ids[i] != "sha256:pulled" || !errors.Is(errs[i], errPull) {
+			t.Errorf("Subscriber %d: expected shared result, got %q, %v", i, ids[i], errs[i])
+		}
+		if outputs[i].String() != "progress\n" {
+			t.Errorf("Subscriber %d: expected shared progress, got %q", i, outputs[i].String())
+		}
+	}
+}
+
+func TestPullGroupSubscriberCancellation(t *testing.T) {
+	g := newPullGroup()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	var pullCtx context.Context
+	pull := func(ctx context.Context, w io.Writer) (string, error) {
+		pullCtx = ctx
+		close(started)
+		select {
+		case <-release:
+			return "sha256:pulled", nil
+		case <-ctx.Done():
+			return "", ctx.Err()
+		}
+	}
+
+	firstCtx, cancelFirst := context.WithCancel(t.Context())
+	defer cancelFirst()
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(firstCtx, "model", io.Discard, pull)
+		firstErr <- err
+	}()
+	<-started
+	secondErr := make(chan error, 1)
+	go func() {
+		_, err := g.do(t.Context(), "model", io.Discard, pull)
+		secondErr <- err
+	}()
+	if !waitForSubscribers(t, g, "model", 2) {
+		t.FailNow()
+	}
+
+	// Cancelling one subscriber leaves the shared pull running.
+	cancelFirst()
+	if err := <-firstErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected cancelled subscriber to return context.Canceled, got %v", err)
+	}
+	if err := pullCtx.Err(); err != nil {
+		t.Fatalf("Expected shared pull to keep running, got %v", err)
+	}
+
+	close(release)
+	if err := <-secondErr; err != nil {
+		t.Errorf("Expected remaining subscriber to succeed, got %v", err)
+	}
+}
+
+func TestPullGroupCancelsWhenAllSubscribersLeave(t *testing.T) {
+	g := newPullGroup()
+	started := make(chan struct{}, 1)
+	stopped := make(chan struct{})
+	pull := func(ctx context.Context, w io.Writer) (string, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		close(stopped)
+		return "", ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(t.Context())
+	errCh := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, "model", io.Discard, pull)
+		errCh <- err
+	}()
+	<-started
+	cancel()
+	if err := <-errCh; !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected shared pull to be cancelled once all subscribers left")
+	}
+
+	// A later pull of the same key starts afresh.
+	var calls atomic.Int32
+	_, err := g.do(t.Context(), "model", io.Discard, func(ctx context.Context, w io.Writer) (string, error) {
+		calls.Add(1)
+		return "sha256:pulled", nil
+	})
+	if err != nil || calls.Load() != 1 {
+		t.Errorf("Expected a new pull to run, got err=%v calls=%d", err, calls.Load())
+	}
+}
+
+func TestManagerPullDeduplicatesConcurrentPulls(t *testing.T) {
+	var blobGets atomic.Int32
+	var manager *Manager
+	var key string
+	var subscribers int
+	registry := testregistry.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.Contains(r.URL.Path, "/blobs/") && manager != nil {
+			blobGets.Add(1)
+			// Hold downloads until all pulls have subscribed.
+			waitForSubscribers(t, manager.pulls, key, subscribers)
+		}
+		registry.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	// pullConcurrently pulls tag n times at once into a fresh store and
+	// returns the number of blob downloads made.
+	pullConcurrently := func(n int) int32 {
+		log := slog.Default()
+		manager = NewManager(log.With("component", "model-manager"), ClientConfig{
+			StoreRootPath: t.TempDir(),
+			Logger:        log.With("component", "model-manager"),
+			Transport:     http.DefaultTransport,
+			UserAgent:     "test-agent",
+			PlainHTTP:     true,
+		})
+		key = manager.pullKey(ModelCreateRequest{From: tag}, 0)
+		subscribers = n
+		blobGets.Store(0)
+
+		recorders := make([]*httptest.ResponseRecorder, n)
+		errs := make([]error, n)
+		var wg sync.WaitGroup
+		for i := range recorders {
+			recorders[i] = httptest.NewRecorder()
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+				errs[i] = manager.Pull(ModelCreateRequest{From: tag}, r, recorders[i])
+			}()
+		}
+		wg.Wait()
+
+		for i := range recorders {
+			if errs[i] != nil {
+				t.Fatalf("Pull %d failed: %v", i, errs[i])
+			}
+			if !strings.Contains(recorders[i].Body.String(), "Model pulled successfully") {
+				t.Errorf("Pull %d: expected success message, got %q", i, recorders[i].Body.String())
+			}
+		}
+		return blobGets.Load()
+	}
+
+	single := pullConcurrently(1)
+	if concurrent := pullConcurrently(2); concurrent != single {
+		t.Errorf("Expected concurrent pulls to download as many blobs as a single pull (%d), got %d", single, concurrent)
+	}
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"context"
+	"sync"
+)
+
+// pushTracker tracks the pushes in progress so that they can be cancelled.
+type pushTracker struct {
+	mu sync.Mutex
+	// pushes holds the cancel function of each push in progress, keyed by
+	// the reference the model is pushed to.
+	pushes map[string]map[*activePush]struct{}
+}
+
+// activePush is a single push in progress.
+type activePush struct {
+	cancel context.CancelFunc
+}
+
+func newPushTracker() *pushTracker {
+	return &pushTracker{pushes: make(map[string]map[*activePush]struct{})}
+}
+
+// start registers a push for key. It returns a context derived from ctx that
+// is also cancelled by cancel, and a function that must be called once the
+// push has finished.
+func (t *pushTracker) start(ctx context.Context, key string) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	push := &activePush{cancel: cancel}
+
+	t.mu.Lock()
+	if t.pushes[key] == nil {
+		t.pushes[key] = make(map[*activePush]struct{})
+	}
+	t.pushes[key][push] = struct{}{}
+	t.mu.Unlock()
+
+	return ctx, func() {
+		t.mu.Lock()
+		delete(t.pushes[key], push)
+		if len(t.pushes[key]) == 0 {
+			delete(t.pushes, key)
+		}
+		t.mu.Unlock()
+		cancel()
+	}
+}
+
+// cancel cancels every push for key, reporting whether there were any.
+func (t *pushTracker) cancel(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for push := range t.pushes[key] {
+		push.cancel()
+	}
+	return len(t.pushes[key]) > 0
+}
+
+// cancelAll cancels every push in progress.
+func (t *pushTracker) cancelAll() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, pushes := range t.pushes {
+		for push := range pushes {
+			push.cancel()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+	"github.com/docker/model-runner/pkg/distribution/registry"
+)
+
+// decodeRequest strictly decodes a single JSON object from body into v and
+// validates it. Unlike a plain json.Decoder, it rejects unknown fields and
+// trailing data, and it reports type mismatches by the offending field name.
+func decodeRequest(body io.Reader, v interface{ validate() error }) error {
+	dec := json.NewDecoder(body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return describeDecodeError(err)
+	}
+	if err := dec.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after the JSON object")
+	}
+	return v.validate()
+}
+
+// describeDecodeError turns a JSON decoding error into a message suitable for
+// the client.
+func describeDecodeError(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.Is(err, io.EOF):
+		return errors.New("request body is empty")
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return errors.New("malformed JSON: unexpected end of input")
+	case errors.As(err, &syntaxErr):
+		return fmt.Errorf("malformed JSON at offset %d: %s", syntaxErr.Offset, strings.TrimPrefix(syntaxErr.Error(), "json: "))
+	case errors.As(err, &typeErr):
+		if typeErr.Field == "" {
+			return errors.New("request body must be a JSON object")
+		}
+		return fmt.Errorf("field %q must be %s, got %s", typeErr.Field, jsonTypeName(typeErr.Type), typeErr.Value)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		// encoding/json does not export a type for this error.
+		return fmt.Errorf("unknown field %s", strings.TrimPrefix(err.Error(), "json: unknown field "))
+	}
+	return err
+}
+
+// jsonTypeName describes the JSON value expected for a Go type.
+func jsonTypeName(t reflect.Type) string {
+	if t == nil {
+		return "a valid value"
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return "an integer"
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "a non-negative integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	default:
+		return "a valid value"
+	}
+}
+
+func (r *ModelCreateRequest) validate() error {
+	if strings.TrimSpace(r.From) == "" {
+		return errors.New(`field "from" is required`)
+	}
+	if r.RawReference {
+		if _, err := reference.ParseReference(r.From, registry.GetDefaultRegistryOptions()...); err != nil {
+			return fmt.Errorf(`field "from" must be a valid reference: %w`, err)
+		}
+	}
+	if r.MaxModelBytes != nil && *r.MaxModelBytes < 0 {
+		return errors.New(`field "max-model-bytes" must not be negative`)
+	}
+	if r.As != "" {
+		if _, err := reference.NewTag(r.As, registry.GetDefaultRegistryOptions()...); err != nil {
+			return fmt.Errorf(`field "as" must be a tag reference: %w`, err)
+		}
+	}
+	return nil
+}
+
+func (r *RepackageRequest) validate() error {
+	if strings.TrimSpace(r.Target) == "" {
+		return errors.New(`field "target" is required`)
+	}
+	if r.ContextSize != nil && (*r.ContextSize == 0 || *r.ContextSize > math.MaxInt32) {
+		return fmt.Errorf(`field "context_size" must be between 1 and %d`, math.MaxInt32)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/docker/model-runner/pkg/distribution/distribution"
+	"github.com/docker/model-runner/pkg/distribution/oci/reference"
+)
+
+// ModelResolver resolves the references clients request models by to the
+// references the store and registries know them by. Embedders can add their
+// own resolution, such as catalog lookups, by wrapping the default resolver
+// with ClientConfig.Resolver.
+type ModelResolver interface {
+	// ResolveLocal returns the reference of the local model ref refers to.
+	// It returns an error wrapping distribution.ErrModelNotFound if there is
+	// none.
+	ResolveLocal(ref string) (string, error)
+	// ResolveRemote returns the reference the remote model ref refers to is
+	// fetched by.
+	ResolveRemote(ctx context.Context, ref string) (string, error)
+}
+
+// defaultResolver resolves local references by name or ID, then by ID prefix,
+// e.g. "sha256:0123456789ab", and then by partial name, e.g. "smollm2" for
+// "ai/smollm2:latest". Remote references are used as given and normalized by
+// the registry client.
+type defaultResolver struct {
+	manager *Manager
+}
+
+func (r defaultResolver) ResolveLocal(ref string) (string, error) {
+	_, err := r.manager.GetLocal(ref)
+	if err == nil {
+		return ref, nil
+	}
+	if !errors.Is(err, distribution.ErrModelNotFound) {
+		return "", err
+	}
+
+	models, err := r.manager.RawList()
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(ref, "sha256:") || len(ref) == 12 {
+		for _, model := range models {
+			id, err := model.ID()
+			if err != nil {
+				return "", fmt.Errorf("error while getting model ID: %w", err)
+			}
+			if strings.HasPrefix(id, ref) || strings.HasPrefix(strings.TrimPrefix(id, "sha256:"), ref) {
+				return id, nil
+			}
+		}
+	}
+	for _, model := range models {
+		// Like the name itself would, prefer the default tag when the model
+		// has several matching tags.
+		resolved := ""
+		for _, tag := range model.Tags() {
+			if candidate := reference.Parse(tag); candidate.Match(ref) {
+				if candidate.Tag == reference.DefaultTag {
+					return tag, nil
+				}
+				if resolved == "" {
+					resolved = tag
+				}
+			}
+		}
+		if resolved != "" {
+			return resolved, nil
+		}
+	}
+	return "", distribution.ErrModelNotFound
+}
+
+func (r defaultResolver) ResolveRemote(_ context.Context, ref string) (string, error) {
+	return ref, nil
+}
+
+// ResolvePushReference returns the tag of the local model a push of ref
+// reads, resolving the short names pulls and inspections accept, such as
+// "smollm2" for "ai/smollm2:latest". References with a registry, organization
+// or digest, and those not naming a local model by tag, are returned
+// unchanged for pushTarget to validate.
+func (m *Manager) ResolvePushReference(ref string) string {
+	parsed := reference.Parse(ref)
+	if parsed.Registry != "" || parsed.Org != "" || parsed.Digest != "" || m.distributionClient == nil {
+		return ref
+	}
+	if _, err := m.GetLocal(ref); err == nil {
+		// IDs normalize to digests, which cannot be pushed.
+		if normalized := m.distributionClient.NormalizeModelName(ref); reference.Parse(normalized).Name != "" {
+			return normalized
+		}
+		return ref
+	}
+	resolved, err := m.resolver.ResolveLocal(ref)
+	if err != nil || !reference.Parse(resolved).Match(ref) {
+		return ref
+	}
+	return resolved
+}
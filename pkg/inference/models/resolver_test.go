@@ -0,0 +1,173 @@
+package models
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/builder"
+	reg "github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/inference"
+)
+
+// vanityResolver resolves vanity names to the references they stand for,
+// and everything else with the resolver it wraps.
+type vanityResolver struct {
+	next  ModelResolver
+	names map[string]string
+}
+
+func (r vanityResolver) ResolveLocal(ref string) (string, error) {
+	if target, ok := r.names[ref]; ok {
+		ref = target
+	}
+	return r.next.ResolveLocal(ref)
+}
+
+func (r vanityResolver) ResolveRemote(ctx context.Context, ref string) (string, error) {
+	if target, ok := r.names[ref]; ok {
+		ref = target
+	}
+	return r.next.ResolveRemote(ctx, ref)
+}
+
+func TestCustomModelResolver(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+		Resolver: func(next ModelResolver) ModelResolver {
+			return vanityResolver{next: next, names: map[string]string{"catalog/vanity": tag}}
+		},
+	})
+	handler := NewHTTPHandler(log, manager, nil)
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	id := manager.ResolveID(tag)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{name: "vanity name", path: "/catalog/vanity", expectedCode: http.StatusOK},
+		{name: "remote vanity name", path: "/catalog/vanity?remote=true", expectedCode: http.StatusOK},
+		{name: "vanity name manifest", path: "/catalog/vanity/manifest", expectedCode: http.StatusOK},
+		{name: "default resolution", path: "/" + tag, expectedCode: http.StatusOK},
+		{name: "default partial resolution", path: "/model", expectedCode: http.StatusOK},
+		{name: "unknown name", path: "/catalog/unknown", expectedCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+tt.path, http.NoBody))
+			if w.Code != tt.expectedCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.expectedCode, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, inference.ModelsPrefix+"/catalog/vanity", http.NoBody))
+	var response Model
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response body: %v", err)
+	}
+	if response.ID != id {
+		t.Errorf("Expected vanity name to resolve to %s, got %s", id, response.ID)
+	}
+
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, inference.InferencePrefix+"/v1/models/catalog/vanity", http.NoBody))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected OpenAI model lookup by vanity name to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestManagerResolvesLocalReferences(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to build model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	id := manager.ResolveID(tag)
+	if _, err := manager.Tag(id, "other/name:v2"); err != nil {
+		t.Fatalf("Failed to tag model: %v", err)
+	}
+
+	for _, ref := range []string{"model", id[:19], id[7:19]} {
+		if got := manager.ResolveID(ref); got != id {
+			t.Errorf("Expected %q to resolve to %s, got %s", ref, id, got)
+		}
+	}
+	if _, err := manager.GetBundle("model"); err != nil {
+		t.Errorf("Failed to get bundle by partial name: %v", err)
+	}
+
+	// Deleting by partial name untags the matching tag only.
+	if _, err := manager.Delete("model", false); err != nil {
+		t.Fatalf("Failed to delete model by partial name: %v", err)
+	}
+	if _, err := manager.ResolveLocal(tag); err == nil {
+		t.Errorf("Expected %s to be untagged", tag)
+	}
+	if got := manager.ResolveID("other/name:v2"); got != id {
+		t.Errorf("Expected the model to keep its other tag, got %s", got)
+	}
+}
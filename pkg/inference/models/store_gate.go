@@ -0,0 +1,104 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrStoreBusy is returned when the store cannot be locked for a reset
+// because operations writing to it did not finish in time.
+var ErrStoreBusy = errors.New("model store is busy with other operations")
+
+// storeGate coordinates operations that write to the store, which may run
+// concurrently, with operations that replace it as a whole, which must run
+// alone. The zero value is ready to use.
+type storeGate struct {
+	mu sync.Mutex
+	// active is the number of shared holders.
+	active int
+	// closed is non-nil while the gate is held or waited for exclusively, and
+	// is closed when it is released.
+	closed chan struct{}
+	// idle is closed when the last shared holder leaves while an exclusive
+	// holder waits for them.
+	idle chan struct{}
+}
+
+// enter acquires the gate shared, blocking while it is held or waited for
+// exclusively, and returns the function that releases it.
+func (g *storeGate) enter(ctx context.Context) (func(), error) {
+	for {
+		g.mu.Lock()
+		closed := g.closed
+		if closed == nil {
+			g.active++
+			g.mu.Unlock()
+			return sync.OnceFunc(g.leave), nil
+		}
+		g.mu.Unlock()
+
+		select {
+		case <-closed:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// leave releases a shared hold of the gate.
+func (g *storeGate) leave() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.active--; g.active == 0 && g.idle != nil {
+		close(g.idle)
+		g.idle = nil
+	}
+}
+
+// exclusive acquires the gate exclusively, returning the function that
+// releases it. New shared holders wait from the moment it is called, while
+// the current ones are waited for. If the gate cannot be acquired within
+// timeout, exclusive returns ErrStoreBusy and lets the waiting holders in.
+func (g *storeGate) exclusive(timeout time.Duration) (func(), error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	g.mu.Lock()
+	for g.closed != nil {
+		closed := g.closed
+		g.mu.Unlock()
+		select {
+		case <-closed:
+		case <-timer.C:
+			return nil, ErrStoreBusy
+		}
+		g.mu.Lock()
+	}
+	closed := make(chan struct{})
+	g.closed = closed
+	var idle chan struct{}
+	if g.active > 0 {
+		idle = make(chan struct{})
+		g.idle = idle
+	}
+	g.mu.Unlock()
+
+	release := sync.OnceFunc(func() {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.closed = nil
+		g.idle = nil
+		close(closed)
+	})
+	if idle != nil {
+		select {
+		case <-idle:
+		case <-timer.C:
+			release()
+			return nil, ErrStoreBusy
+		}
+	}
+	return release, nil
+}
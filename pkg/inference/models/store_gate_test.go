@@ -0,0 +1,261 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/model-runner/pkg/distribution/builder"
+	reg "github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/inference"
+)
+
+func TestStoreGateExclusiveWaitsForHolders(t *testing.T) {
+	var g storeGate
+	leave, err := g.enter(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to enter gate: %v", err)
+	}
+
+	acquired := make(chan func())
+	go func() {
+		release, err := g.exclusive(time.Minute)
+		if err != nil {
+			t.Errorf("Failed to acquire gate exclusively: %v", err)
+		}
+		acquired <- release
+	}()
+
+	// New holders wait for the exclusive holder, even though it has not
+	// acquired the gate yet.
+	for !waitingExclusively(&g) {
+		time.Sleep(time.Millisecond)
+	}
+	entered := make(chan struct{})
+	go func() {
+		leave, err := g.enter(context.Background())
+		if err != nil {
+			t.Errorf("Failed to enter gate: %v", err)
+			return
+		}
+		leave()
+		close(entered)
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected exclusive hold to wait for the shared holder")
+	case <-entered:
+		t.Fatal("Expected new holders to wait for the exclusive holder")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	leave()
+	release := <-acquired
+	select {
+	case <-entered:
+		t.Fatal("Expected new holders to wait until the gate is released")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	<-entered
+}
+
+// waitingExclusively reports whether the gate is held or waited for
+// exclusively.
+func waitingExclusively(g *storeGate) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.closed != nil
+}
+
+func TestPurgeTimesOut(t *testing.T) {
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+	})
+	manager.purgeTimeout = 10 * time.Millisecond
+	handler := NewHTTPHandler(log, manager, nil)
+
+	leave, err := manager.storeGate.enter(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to enter gate: %v", err)
+	}
+	w := httptest.NewRecorder()
+	handler.handlePurge(w, httptest.NewRequest(http.MethodDelete, inference.ModelsPrefix+"/purge", http.NoBody))
+	if w.Code != http.StatusConflict {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusConflict, w.Code, w.Body.String())
+	}
+
+	// Operations are let in again after the purge gives up.
+	other, err := manager.storeGate.enter(t.Context())
+	if err != nil {
+		t.Fatalf("Failed to enter gate after a failed purge: %v", err)
+	}
+	other()
+	leave()
+
+	if err := manager.Purge(); err != nil {
+		t.Fatalf("Expected purge to succeed once the store is idle, got %v", err)
+	}
+}
+
+func TestPurgeWhilePulling(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+	tag := uri.Host + "/ai/model:v1.0.0"
+	model, err := builder.FromPath(filepath.Join(getProjectRoot(t), "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to create model builder: %v", err)
+	}
+	target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+	if err != nil {
+		t.Fatalf("Failed to create model target: %v", err)
+	}
+	if err := model.Build(t.Context(), target, nil); err != nil {
+		t.Fatalf("Failed to push model: %v", err)
+	}
+
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+				if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+					t.Errorf("Failed to pull model: %v", err)
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if err := manager.Purge(); err != nil && !errors.Is(err, ErrStoreBusy) {
+					t.Errorf("Failed to purge models: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// The store holds either nothing or a complete copy of the model.
+	report, err := manager.distributionClient.RepairStore(time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to check store: %v", err)
+	}
+	if len(report.RemovedBlobs) != 0 || len(report.MissingBlobs) != 0 {
+		t.Errorf("Expected a consistent store, got %+v", report)
+	}
+	models, err := manager.List()
+	if err != nil {
+		t.Fatalf("Failed to list models: %v", err)
+	}
+	for _, m := range models {
+		if _, err := manager.GetBundle(m.ID); err != nil {
+			t.Errorf("Expected model %s to be usable, got %v", m.ID, err)
+		}
+	}
+
+	// A final pull always succeeds and leaves a usable model.
+	r := httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody)
+	if err := manager.Pull(ModelCreateRequest{From: tag}, r, httptest.NewRecorder()); err != nil {
+		t.Fatalf("Failed to pull model: %v", err)
+	}
+	if _, err := manager.GetBundle(tag); err != nil {
+		t.Errorf("Expected pulled model to be usable, got %v", err)
+	}
+}
+
+func TestPurgeWithQueuedPull(t *testing.T) {
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+		PlainHTTP:     true,
+	})
+	manager.purgeTimeout = 100 * time.Millisecond
+
+	// Take every pull token so that the pull below stays queued.
+	tokens := len(manager.pullTokens)
+	for range tokens {
+		<-manager.pullTokens
+	}
+	defer func() {
+		for range tokens {
+			manager.pullTokens <- struct{}{}
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	pulled := make(chan struct{})
+	go func() {
+		defer close(pulled)
+		r := httptest.NewRequestWithContext(ctx, http.MethodPost, "/models/create", http.NoBody)
+		_ = manager.Pull(ModelCreateRequest{From: "localhost:1/ai/model"}, r, httptest.NewRecorder())
+	}()
+	defer func() {
+		cancel()
+		<-pulled
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	if err := manager.Purge(); err != nil {
+		t.Fatalf("Expected purge not to wait for a queued pull, got %v", err)
+	}
+}
+
+func TestTagWaitsForPurge(t *testing.T) {
+	log := slog.Default()
+	manager := NewManager(log.With("component", "model-manager"), ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        log.With("component", "model-manager"),
+	})
+
+	release, err := manager.storeGate.exclusive(time.Second)
+	if err != nil {
+		t.Fatalf("Failed to acquire gate: %v", err)
+	}
+	tagged := make(chan struct{})
+	go func() {
+		defer close(tagged)
+		_, _ = manager.Tag("ai/model", "ai/model:v2")
+	}()
+	deleted := make(chan struct{})
+	go func() {
+		defer close(deleted)
+		_, _ = manager.Delete("ai/model", false)
+	}()
+
+	select {
+	case <-tagged:
+		t.Fatal("Expected Tag to wait for the purge")
+	case <-deleted:
+		t.Fatal("Expected Delete to wait for the purge")
+	case <-time.After(50 * time.Millisecond):
+	}
+	release()
+	<-tagged
+	<-deleted
+}
@@ -0,0 +1,12 @@
+package platform
+
+import "runtime"
+
+// DetectGPUs returns the graphics devices found on the host. Apple Silicon
+// GPUs share the system's unified memory, so no dedicated VRAM is reported.
+func DetectGPUs() ([]GPU, error) {
+	if runtime.GOARCH != "arm64" {
+		return nil, nil
+	}
+	return []GPU{{Vendor: "Apple", Name: "Apple Silicon GPU"}}, nil
+}
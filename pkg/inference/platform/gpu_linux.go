@@ -0,0 +1,101 @@
+package platform
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pciVendors maps PCI vendor IDs to the names of common GPU vendors.
+var pciVendors = map[string]string{
+	"0x10de": "NVIDIA",
+	"0x1002": "AMD",
+	"0x8086": "Intel",
+}
+
+// DetectGPUs returns the graphics devices found on the host. It reads sysfs
+// directly rather than relying on a PCI ID database, which is often missing
+// from container images. NVIDIA devices that are not exposed through DRM are
+// found through the proprietary driver's procfs entries.
+func DetectGPUs() ([]GPU, error) {
+	var gpus []GPU
+	seen := make(map[string]bool)
+
+	cards, err := filepath.Glob("/sys/class/drm/card[0-9]*")
+	if err != nil {
+		return nil, err
+	}
+	for _, card := range cards {
+		// Skip connectors such as card0-HDMI-A-1.
+		if strings.Contains(filepath.Base(card), "-") {
+			continue
+		}
+		device := filepath.Join(card, "device")
+		vendorID := readSysfsString(filepath.Join(device, "vendor"))
+		if vendorID == "" {
+			continue
+		}
+		address := ""
+		if target, err := os.Readlink(device); err == nil {
+			address = filepath.Base(target)
+		}
+		if address != "" && seen[address] {
+			continue
+		}
+		seen[address] = true
+
+		vendor, ok := pciVendors[vendorID]
+		if !ok {
+			vendor = vendorID
+		}
+		vram, _ := strconv.ParseUint(readSysfsString(filepath.Join(device, "mem_info_vram_total")), 10, 64)
+		gpus = append(gpus, GPU{
+			Vendor: vendor,
+			Name:   nvidiaModel(address),
+			VRAM:   vram,
+		})
+	}
+
+	nvidiaGPUs, _ := filepath.Glob("/proc/driver/nvidia/gpus/*")
+	for _, dir := range nvidiaGPUs {
+		address := strings.ToLower(filepath.Base(dir))
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		gpus = append(gpus, GPU{Vendor: pciVendors["0x10de"], Name: nvidiaModel(address)})
+	}
+	return gpus, nil
+}
+
+// nvidiaModel returns the model name reported by the NVIDIA driver for the
+// device at the given PCI address, or "" if the driver does not manage it.
+func nvidiaModel(address string) string {
+	if address == "" {
+		return ""
+	}
+	raw, err := os.ReadFile(filepath.Join("/proc/driver/nvidia/gpus", address, "information"))
+	if err != nil {
+		return ""
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(raw))
+	for scanner.Scan() {
+		if model, ok := strings.CutPrefix(scanner.Text(), "Model:"); ok {
+			return strings.TrimSpace(model)
+		}
+	}
+	return ""
+}
+
+// readSysfsString returns the trimmed contents of a sysfs attribute, or "" if
+// it cannot be read.
+func readSysfsString(path string) string {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
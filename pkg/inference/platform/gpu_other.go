@@ -0,0 +1,9 @@
+//go:build !linux && !windows && !darwin
+
+package platform
+
+// DetectGPUs returns no devices because GPU detection is not implemented on
+// this platform.
+func DetectGPUs() ([]GPU, error) {
+	return nil, nil
+}
@@ -0,0 +1,30 @@
+package platform
+
+import (
+	"fmt"
+
+	"github.com/jaypipes/ghw"
+)
+
+// DetectGPUs returns the graphics devices found on the host. Device memory is
+// not reported on Windows.
+func DetectGPUs() ([]GPU, error) {
+	info, err := ghw.GPU(ghw.WithDisableWarnings())
+	if err != nil {
+		return nil, fmt.Errorf("detecting GPUs: %w", err)
+	}
+	gpus := make([]GPU, 0, len(info.GraphicsCards))
+	for _, card := range info.GraphicsCards {
+		var gpu GPU
+		if card.DeviceInfo != nil {
+			if card.DeviceInfo.Vendor != nil {
+				gpu.Vendor = card.DeviceInfo.Vendor.Name
+			}
+			if card.DeviceInfo.Product != nil {
+				gpu.Name = card.DeviceInfo.Product.Name
+			}
+		}
+		gpus = append(gpus, gpu)
+	}
+	return gpus, nil
+}
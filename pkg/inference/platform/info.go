@@ -0,0 +1,25 @@
+package platform
+
+// GPU describes a graphics device detected on the host.
+type GPU struct {
+	// Vendor is the device vendor, such as "NVIDIA Corporation".
+	Vendor string `json:"vendor"`
+	// Name is the device model, if known.
+	Name string `json:"name,omitempty"`
+	// VRAM is the dedicated device memory in bytes, or zero if unknown.
+	VRAM uint64 `json:"vram,omitempty"`
+}
+
+// BackendSupport reports whether an inference backend can run on the host.
+type BackendSupport struct {
+	Name      string `json:"name"`
+	Supported bool   `json:"supported"`
+}
+
+// Info describes the host platform and the inference backends it supports.
+type Info struct {
+	OS       string           `json:"os"`
+	Arch     string           `json:"arch"`
+	GPUs     []GPU            `json:"gpus"`
+	Backends []BackendSupport `json:"backends"`
+}
@@ -31,6 +31,53 @@ func ValidateRuntimeFlags(backendName string, flags []string) error {
 	return validatePathSafety(flags)
 }
 
+// ValidateExtraArgs validates extra backend arguments against the backend's
+// denylist, which covers the flags the backend sets itself (such as the
+// socket or model) and flags that read or write files, and applies the same
+// injection and path checks as ValidateRuntimeFlags.
+func ValidateExtraArgs(backendName string, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	deniedFlags, ok := DeniedExtraArgs[backendName]
+	if !ok {
+		return fmt.Errorf("extra arguments are not supported for backend %q", backendName)
+	}
+	for _, arg := range args {
+		if flagKey := ParseFlagKey(arg); isDeniedExtraArg(backendName, flagKey, deniedFlags) {
+			return fmt.Errorf("extra argument %q is not allowed for backend %q", flagKey, backendName)
+		}
+	}
+	if err := validateNoFlagInjection(args); err != nil {
+		return err
+	}
+	return validatePathSafety(args)
+}
+
+// isDeniedExtraArg reports whether flagKey sets one of the denied flags. Keys
+// are compared lowercased and with underscores as dashes, since vLLM's
+// parser accepts "--trust_remote_code" for "--trust-remote-code". For
+// backends that accept abbreviations of long flags, as Python's argparse
+// does, a key that is a prefix of a denied flag is denied as well.
+func isDeniedExtraArg(backendName, flagKey string, deniedFlags map[string]bool) bool {
+	if flagKey == "" {
+		return false
+	}
+	key := strings.ToLower(strings.ReplaceAll(flagKey, "_", "-"))
+	if deniedFlags[key] {
+		return true
+	}
+	if !AbbreviatedExtraArgs[backendName] || !strings.HasPrefix(key, "--") || len(key) <= len("--") {
+		return false
+	}
+	for denied := range deniedFlags {
+		if strings.HasPrefix(denied, key) {
+			return true
+		}
+	}
+	return false
+}
+
 // validatePathSafety ensures runtime flags don't contain paths (forward slash "/" or backslash "\")
 // to prevent malicious users from overwriting host files via arguments like
 // --log-file /some/path, --output-file /etc/passwd, or --log-file C:\Windows\file.
@@ -217,6 +217,78 @@ var AllowedFlags = map[string]map[string]bool{
 	"vllm":      VLLMAllowedFlags,
 }
 
+// LlamaCppDeniedExtraArgs contains the llama.cpp server flags that extra
+// arguments may not set: those the backend controls, such as the model and
+// listening address, and those that read or write files or fetch models.
+var LlamaCppDeniedExtraArgs = map[string]bool{
+	// Set by the backend
+	"-m": true, "--model": true,
+	"--host": true, "--port": true,
+	"-a": true, "--alias": true,
+	"--mmproj": true, "--chat-template-file": true,
+
+	// Remote models
+	"-mu": true, "--model-url": true,
+	"-hf": true, "-hfr": true, "--hf-repo": true,
+	"-hff": true, "--hf-file": true,
+	"-hfd": true, "-hfrd": true, "--hf-repo-draft": true,
+	"-hfv": true, "-hfrv": true, "--hf-repo-v": true,
+	"-hffv": true, "--hf-file-v": true,
+	"-hft": true, "--hf-token": true,
+	"-mmu": true, "--mmproj-url": true,
+
+	// File access
+	"--log-file": true, "--path": true,
+	"-md": true, "--model-draft": true,
+	"-mv": true, "--model-vocoder": true,
+	"--lora": true, "--lora-scaled": true,
+	"--control-vector": true, "--control-vector-scaled": true,
+	"--grammar-file": true,
+	"-jf":            true, "--json-schema-file": true,
+	"--chat-template-kwargs-file": true,
+	"--api-key-file":              true,
+	"--ssl-key-file":              true, "--ssl-cert-file": true,
+	"--slot-save-path": true,
+	"--media-path":     true,
+	"--models-dir":     true, "--models-preset": true,
+}
+
+// VLLMDeniedExtraArgs contains the vLLM flags that extra arguments may not
+// set: those the backend controls, such as the model and socket, and those
+// that read or write files, fetch models or run code from the model.
+var VLLMDeniedExtraArgs = map[string]bool{
+	// Set by the backend
+	"--model": true, "--uds": true,
+	"--host": true, "--port": true,
+	"--runner": true, "--hf-overrides": true,
+	"--served-model-name": true,
+
+	// Remote code and file access
+	"--trust-remote-code": true,
+	"--tokenizer":         true,
+	"--download-dir":      true,
+	"--chat-template":     true,
+	"--ssl-keyfile":       true, "--ssl-certfile": true, "--ssl-ca-certs": true,
+	"--allowed-local-media-path": true,
+	"--config":                   true,
+	"--lora-modules":             true,
+	"--root-path":                true,
+}
+
+// DeniedExtraArgs maps backend names to the flag keys extra arguments may not
+// set. Backends without an entry do not accept extra arguments.
+var DeniedExtraArgs = map[string]map[string]bool{
+	"llama.cpp": LlamaCppDeniedExtraArgs,
+	"vllm":      VLLMDeniedExtraArgs,
+}
+
+// AbbreviatedExtraArgs lists the backends whose argument parser accepts
+// unambiguous prefixes of long flags, such as "--trust-remote" for
+// "--trust-remote-code".
+var AbbreviatedExtraArgs = map[string]bool{
+	"vllm": true,
+}
+
 // ParseFlagKey extracts the flag key from a flag string.
 // "--threads=4" -> "--threads", "-t" -> "-t", "4" -> ""
 func ParseFlagKey(flag string) string {
@@ -293,6 +293,50 @@ func TestValidateRuntimeFlags_ErrorMessages(t *testing.T) {
 	})
 }
 
+func TestValidateExtraArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		backend string
+		args    []string
+		wantErr string
+	}{
+		{name: "nil args", backend: "sglang", args: nil},
+		{name: "llama.cpp: flag outside the allowlist", backend: "llama.cpp", args: []string{"--override-kv", "tokenizer.ggml.add_bos_token=bool:false"}},
+		{name: "vllm: flag outside the allowlist", backend: "vllm", args: []string{"--max-num-partial-prefills=2"}},
+		{name: "llama.cpp: socket", backend: "llama.cpp", args: []string{"--host", "other.sock"}, wantErr: `"--host" is not allowed`},
+		{name: "llama.cpp: model with equals", backend: "llama.cpp", args: []string{"--model=other.gguf"}, wantErr: `"--model" is not allowed`},
+		{name: "llama.cpp: log file", backend: "llama.cpp", args: []string{"--log-file", "out.log"}, wantErr: `"--log-file" is not allowed`},
+		{name: "vllm: socket", backend: "vllm", args: []string{"--uds", "other.sock"}, wantErr: `"--uds" is not allowed`},
+		{name: "vllm: remote code", backend: "vllm", args: []string{"--trust-remote-code"}, wantErr: `"--trust-remote-code" is not allowed`},
+		{name: "vllm: remote code with underscores", backend: "vllm", args: []string{"--trust_remote_code"}, wantErr: `"--trust_remote_code" is not allowed`},
+		{name: "vllm: download dir with underscores and equals", backend: "vllm", args: []string{"--download_dir=models"}, wantErr: `"--download_dir" is not allowed`},
+		{name: "vllm: served model name with underscores", backend: "vllm", args: []string{"--served_model_name", "other"}, wantErr: `"--served_model_name" is not allowed`},
+		{name: "vllm: uppercase", backend: "vllm", args: []string{"--Trust-Remote-Code"}, wantErr: `"--Trust-Remote-Code" is not allowed`},
+		{name: "vllm: abbreviated", backend: "vllm", args: []string{"--trust-remote"}, wantErr: `"--trust-remote" is not allowed`},
+		{name: "vllm: abbreviated with underscores", backend: "vllm", args: []string{"--download_d=models"}, wantErr: `"--download_d" is not allowed`},
+		{name: "vllm: longer flag", backend: "vllm", args: []string{"--tokenizer-mode", "auto"}},
+		{name: "llama.cpp: prefix of a denied flag", backend: "llama.cpp", args: []string{"--json-schema", "{}"}},
+		{name: "flag injection", backend: "llama.cpp", args: []string{"--seed=--host"}, wantErr: "value cannot start with '-'"},
+		{name: "path", backend: "llama.cpp", args: []string{"--override-kv", "/etc/passwd"}, wantErr: "paths are not allowed"},
+		{name: "unsupported backend", backend: "sglang", args: []string{"--foo"}, wantErr: "not supported"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtraArgs(tt.backend, tt.args)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
 func TestValidatePathSafety(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -16,6 +16,10 @@ const (
 
 	// modelCLIUserAgentPrefix is the user-agent prefix set by the model CLI.
 	modelCLIUserAgentPrefix = "docker-model-cli/"
+
+	// defaultWarmTimeout is how long a warm request waits for the model to be
+	// ready unless the request specifies a timeout.
+	defaultWarmTimeout = 5 * time.Minute
 )
 
 // trimRequestPathToOpenAIRoot trims a request path to start at the first
@@ -83,6 +87,44 @@ type BackendStatus struct {
 	// Loading indicates whether this backend is currently being initialized
 	Loading   bool                 `json:"loading,omitempty"`
 	KeepAlive *inference.KeepAlive `json:"keep_alive,omitempty"`
+	// Healthy indicates whether the backend responded successfully to its
+	// most recent health probe
+	Healthy bool `json:"healthy"`
+	// LastError is the error returned by the most recent health probe, if any
+	LastError string `json:"last_error,omitempty"`
+}
+
+// BackendEventType identifies a change in the lifecycle of a backend runner.
+type BackendEventType string
+
+const (
+	// BackendEventLoaded is sent when a runner has started and is ready to
+	// serve requests.
+	BackendEventLoaded BackendEventType = "loaded"
+	// BackendEventEvicted is sent when a runner has been terminated, whether
+	// because it was idle, defunct, explicitly unloaded or making room for
+	// another model.
+	BackendEventEvicted BackendEventType = "evicted"
+	// BackendEventError is sent when a runner failed to start.
+	BackendEventError BackendEventType = "error"
+)
+
+// BackendEvent describes a change in the lifecycle of a backend runner.
+type BackendEvent struct {
+	// Type is the kind of change.
+	Type BackendEventType `json:"type"`
+	// BackendName is the name of the backend
+	BackendName string `json:"backend_name"`
+	// ModelID is the ID of the model the runner serves
+	ModelID string `json:"model_id"`
+	// ModelName is the reference the model was loaded by
+	ModelName string `json:"model_name"`
+	// Mode is the mode the runner operates in
+	Mode string `json:"mode"`
+	// Error describes the failure for error events
+	Error string `json:"error,omitempty"`
+	// Timestamp is when the change happened
+	Timestamp time.Time `json:"timestamp"`
 }
 
 // DiskUsage represents the disk usage of the models and default backend.
@@ -119,3 +161,12 @@ type ModelConfigEntry struct {
 	Mode    inference.BackendMode
 	Config  inference.BackendConfiguration
 }
+
+// BackendArchitectures lists the model architectures a backend can run.
+type BackendArchitectures struct {
+	// Backend is the name of the backend.
+	Backend string `json:"backend"`
+	// Architectures are the supported architectures, as recorded in model
+	// configs.
+	Architectures []string `json:"architectures"`
+}
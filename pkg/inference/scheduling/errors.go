@@ -8,3 +8,7 @@ import (
 // returned in conjunction with an HTTP request, it should be paired with a
 // 404 response status.
 var ErrBackendNotFound = errors.New("backend not found")
+
+// errArchitecturesNotReported indicates that a backend does not report which
+// model architectures it supports.
+var errArchitecturesNotReported = errors.New("backend does not report supported architectures")
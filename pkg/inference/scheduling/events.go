@@ -0,0 +1,112 @@
+package scheduling
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/docker/model-runner/pkg/inference"
+)
+
+// eventSubscriberBuffer is the number of events buffered for each subscriber.
+// Events are dropped for subscribers that fall further behind.
+const eventSubscriberBuffer = 100
+
+// eventBroadcaster fans backend lifecycle events out to subscribers.
+type eventBroadcaster struct {
+	mu          sync.Mutex
+	subscribers map[chan BackendEvent]struct{}
+}
+
+// newEventBroadcaster creates a new event broadcaster.
+func newEventBroadcaster() *eventBroadcaster {
+	return &eventBroadcaster{subscribers: make(map[chan BackendEvent]struct{})}
+}
+
+// subscribe registers a new subscriber, returning its event channel and a
+// function that unregisters it.
+func (b *eventBroadcaster) subscribe() (<-chan BackendEvent, func()) {
+	ch := make(chan BackendEvent, eventSubscriberBuffer)
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+	return ch, func() {
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+	}
+}
+
+// publish sends event to all subscribers. It never blocks, so it is safe to
+// call with the loader lock held.
+func (b *eventBroadcaster) publish(event BackendEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			// The subscriber is not keeping up, skip it.
+		}
+	}
+}
+
+// newBackendEvent creates an event of the given type for a runner.
+func newBackendEvent(eventType BackendEventType, backend, modelID, modelRef string, mode inference.BackendMode, err error) BackendEvent {
+	event := BackendEvent{
+		Type:        eventType,
+		BackendName: backend,
+		ModelID:     modelID,
+		ModelName:   modelRef,
+		Mode:        mode.String(),
+		Timestamp:   time.Now(),
+	}
+	if err != nil {
+		event.Error = err.Error()
+	}
+	return event
+}
+
+// GetBackendEvents streams backend lifecycle events as server-sent events
+// until the client disconnects. Each event is sent with its type as the SSE
+// event name and the BackendEvent as JSON data.
+func (h *HTTPHandler) GetBackendEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, unsubscribe := h.scheduler.loader.events.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	// Send heartbeat to establish connection.
+	if _, err := fmt.Fprintf(w, "event: connected\ndata: {\"status\": \"connected\"}\n\n"); err != nil {
+		h.scheduler.log.Warn("Failed to write connected event to response", "error", err)
+		return
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.scheduler.log.Warn("Failed to marshal backend event", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
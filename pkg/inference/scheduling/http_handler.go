@@ -19,6 +19,7 @@ import (
 	"github.com/docker/model-runner/pkg/inference/models"
 	"github.com/docker/model-runner/pkg/metrics"
 	"github.com/docker/model-runner/pkg/middleware"
+	"github.com/docker/model-runner/pkg/tracing"
 )
 
 type contextKey bool
@@ -119,11 +120,17 @@ func (h *HTTPHandler) routeHandlers() map[string]http.HandlerFunc {
 	m["GET "+inference.InferencePrefix+"/status"] = h.GetBackendStatus
 	m["GET "+inference.InferencePrefix+"/ps"] = h.GetRunningBackends
 	m["GET "+inference.InferencePrefix+"/df"] = h.GetDiskUsage
+	m["GET "+inference.InferencePrefix+"/platform"] = h.GetPlatformInfo
+	m["GET "+inference.InferencePrefix+"/backends/{backend}/architectures"] = h.GetBackendArchitectures
 	m["POST "+inference.InferencePrefix+"/unload"] = h.Unload
+	m["POST "+inference.InferencePrefix+"/warm"] = h.Warm
 	m["POST "+inference.InferencePrefix+"/{backend}/_configure"] = h.Configure
 	m["POST "+inference.InferencePrefix+"/_configure"] = h.Configure
 	m["GET "+inference.InferencePrefix+"/_configure"] = h.GetModelConfigs
 	m["GET "+inference.InferencePrefix+"/requests"] = h.scheduler.openAIRecorder.GetRecordsHandler()
+	m["GET "+inference.InferencePrefix+"/events"] = h.GetBackendEvents
+	m["GET "+inference.InferencePrefix+"/stats"] = h.scheduler.openAIRecorder.Stats().GetStatsHandler()
+	m["DELETE "+inference.InferencePrefix+"/stats"] = h.scheduler.openAIRecorder.Stats().ResetStatsHandler()
 	return m
 }
 
@@ -179,9 +186,19 @@ func (h *HTTPHandler) handleOpenAIInference(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+		var span *tracing.Span
+		r, span = tracing.StartServer(r, tracing.SpanChat, request.Model)
+		span.SetBytes(int64(len(body)))
+		// Record the error responses below, and those of the backend.
+		tw := tracing.NewResponseWriter(w)
+		w = tw
+		defer func() { span.End(tw.Err()) }()
+	}
+
 	// Check if the shared model manager has the requested model available.
 	if !backend.UsesExternalModelManagement() {
-		model, err := h.scheduler.modelManager.GetLocal(request.Model)
+		model, err := h.scheduler.modelManager.ResolveLocal(request.Model)
 		if err != nil {
 			if errors.Is(err, distribution.ErrModelNotFound) {
 				http.Error(w, err.Error(), http.StatusNotFound)
@@ -209,13 +226,16 @@ func (h *HTTPHandler) handleOpenAIInference(w http.ResponseWriter, r *http.Reque
 		// Automatically select backend for given model.
 		backend = h.scheduler.selectBackendForModel(model, backend, request.Model)
 	}
+	h.scheduler.metrics.InferenceRequest(backend.Name(), backendMode.String())
 
 	// If a deferred backend needs on-demand installation and the request
 	// comes from the model CLI, stream progress messages so the user sees
 	// what is happening while the download runs.
+	// Preload requests have nobody to report progress to.
 	autoInstall := h.scheduler.installer.deferredBackends[backend.Name()] &&
 		!h.scheduler.installer.isInstalled(backend.Name()) &&
-		strings.Contains(r.UserAgent(), modelCLIUserAgentPrefix)
+		strings.Contains(r.UserAgent(), modelCLIUserAgentPrefix) &&
+		!isPreloadOnly(r)
 	if autoInstall {
 		fmt.Fprintf(w, "Installing %s backend...\n", backend.Name())
 		if f, ok := w.(http.Flusher); ok {
@@ -258,6 +278,22 @@ func (h *HTTPHandler) handleOpenAIInference(w http.ResponseWriter, r *http.Reque
 		}
 	}
 
+	// Bound the number of chat completions the backend serves at once.
+	// Preload requests don't run inference, so they don't count.
+	if strings.HasSuffix(r.URL.Path, "/chat/completions") && !isPreloadOnly(r) {
+		release, err := h.scheduler.inflight.acquire(r.Context(), backend.Name())
+		if err != nil {
+			if errors.Is(err, errInflightLimitReached) {
+				w.Header().Set("Retry-After", inflightRetryAfter)
+				http.Error(w, fmt.Sprintf("backend %q is busy: %v", backend.Name(), err), http.StatusServiceUnavailable)
+			} else {
+				http.Error(w, "service unavailable", http.StatusServiceUnavailable)
+			}
+			return
+		}
+		defer release()
+	}
+
 	modelID := h.scheduler.modelManager.ResolveID(request.Model)
 
 	// Request a runner to execute the request and defer its release.
@@ -270,10 +306,14 @@ func (h *HTTPHandler) handleOpenAIInference(w http.ResponseWriter, r *http.Reque
 
 	// If this is a preload-only request, return here without running inference.
 	// Can be triggered via context (internal) or X-Preload-Only header (external).
-	if r.Context().Value(preloadOnlyKey) != nil || r.Header.Get("X-Preload-Only") == "true" {
+	if isPreloadOnly(r) {
 		return
 	}
 
+	if err := h.scheduler.modelManager.MarkUsed(modelID); err != nil {
+		h.scheduler.log.Warn("Failed to record model use", "model", modelID, "error", err)
+	}
+
 	// Record the request in the OpenAI recorder.
 	recordID := h.scheduler.openAIRecorder.RecordRequest(request.Model, r, body)
 	w = h.scheduler.openAIRecorder.NewResponseRecorder(w)
@@ -296,6 +336,37 @@ func (h *HTTPHandler) handleOpenAIInference(w http.ResponseWriter, r *http.Reque
 	runner.ServeHTTP(w, upstreamRequest)
 }
 
+// isPreloadOnly reports whether r only asks for its model to be loaded.
+func isPreloadOnly(r *http.Request) bool {
+	return r.Context().Value(preloadOnlyKey) != nil || r.Header.Get("X-Preload-Only") == "true"
+}
+
+// preload loads model into backend (or the automatically selected backend if
+// backend is empty) without running inference, returning the recorded
+// response of the preload-only request.
+func (h *HTTPHandler) preload(ctx context.Context, model, backend, userAgent string) (*httptest.ResponseRecorder, error) {
+	preloadBody, err := json.Marshal(OpenAIInferenceRequest{Model: model})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling preload request body: %w", err)
+	}
+	preloadReq, err := http.NewRequestWithContext(
+		context.WithValue(ctx, preloadOnlyKey, true),
+		http.MethodPost,
+		inference.InferencePrefix+"/v1/chat/completions",
+		bytes.NewReader(preloadBody),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("creating preload request: %w", err)
+	}
+	preloadReq.Header.Set("User-Agent", userAgent)
+	if backend != "" {
+		preloadReq.SetPathValue("backend", backend)
+	}
+	recorder := httptest.NewRecorder()
+	h.handleOpenAIInference(recorder, preloadReq)
+	return recorder, nil
+}
+
 // handleModels handles GET /engines/{backend}/v1/models* requests
 // by delegating to the model manager
 func (h *HTTPHandler) handleModels(w http.ResponseWriter, r *http.Request) {
@@ -321,7 +392,7 @@ func (h *HTTPHandler) GetBackendStatus(w http.ResponseWriter, r *http.Request) {
 
 // GetRunningBackends returns information about all running backends
 func (h *HTTPHandler) GetRunningBackends(w http.ResponseWriter, r *http.Request) {
-	runningBackends := h.scheduler.getLoaderStatus(r.Context())
+	runningBackends := h.scheduler.GetRunningBackendsInfo(r.Context())
 
 	w.Header().Set("Content-Type", "application/json")
 	if err := json.NewEncoder(w).Encode(runningBackends); err != nil {
@@ -353,6 +424,36 @@ func (h *HTTPHandler) GetDiskUsage(w http.ResponseWriter, _ *http.Request) {
 	}
 }
 
+// GetPlatformInfo handles GET <inference-prefix>/platform requests, returning
+// the detected GPUs and which backends are supported on the host.
+func (h *HTTPHandler) GetPlatformInfo(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(h.scheduler.PlatformInfo()); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+// GetBackendArchitectures returns the model architectures a backend can run.
+func (h *HTTPHandler) GetBackendArchitectures(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("backend")
+	architectures, err := h.scheduler.SupportedArchitectures(name)
+	if err != nil {
+		if errors.Is(err, ErrBackendNotFound) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+		} else {
+			http.Error(w, fmt.Sprintf("backend %q does not report supported architectures", name), http.StatusNotImplemented)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(BackendArchitectures{Backend: name, Architectures: architectures}); err != nil {
+		http.Error(w, fmt.Sprintf("Failed to encode response: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
 // Unload unloads the specified runners (backend, model) from the backend.
 // Currently, this doesn't work for runners that are handling an OpenAI request.
 func (h *HTTPHandler) Unload(w http.ResponseWriter, r *http.Request) {
@@ -484,29 +585,17 @@ func (h *HTTPHandler) Configure(w http.ResponseWriter, r *http.Request) {
 	// Preload the model in the background by calling handleOpenAIInference with preload-only context.
 	// This makes Compose preload the model as well as it calls `configure` by default.
 	go func() {
-		preloadBody, err := json.Marshal(OpenAIInferenceRequest{Model: configureRequest.Model})
-		if err != nil {
-			h.scheduler.log.Warn("failed to marshal preload request body", "error", err)
-			return
-		}
 		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
 		defer cancel()
-		preloadReq, err := http.NewRequestWithContext(
-			context.WithValue(ctx, preloadOnlyKey, true),
-			http.MethodPost,
-			inference.InferencePrefix+"/v1/chat/completions",
-			bytes.NewReader(preloadBody),
-		)
+		var backendName string
+		if backend != nil {
+			backendName = backend.Name()
+		}
+		recorder, err := h.preload(ctx, configureRequest.Model, backendName, r.UserAgent())
 		if err != nil {
 			h.scheduler.log.Warn("failed to create preload request", "error", err)
 			return
 		}
-		preloadReq.Header.Set("User-Agent", r.UserAgent())
-		if backend != nil {
-			preloadReq.SetPathValue("backend", backend.Name())
-		}
-		recorder := httptest.NewRecorder()
-		h.handleOpenAIInference(recorder, preloadReq)
 		if recorder.Code != http.StatusOK {
 			h.scheduler.log.Warn("background model preload failed", "status", recorder.Code, "body", recorder.Body.String())
 		}
@@ -515,6 +604,48 @@ func (h *HTTPHandler) Configure(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusAccepted)
 }
 
+// Warm handles POST <inference-prefix>/warm requests, loading a model into a
+// backend ahead of any inference request. It responds once the model is ready
+// to serve requests. The query parameters are:
+// - model: the model to load (required)
+// - backend: the backend to load the model into (optional)
+// - timeout: how long to wait for the model to be ready (optional)
+// The model is a query parameter rather than part of the path, since a model
+// name in the path would collide with the <inference-prefix>/{backend} routes.
+func (h *HTTPHandler) Warm(w http.ResponseWriter, r *http.Request) {
+	model := r.URL.Query().Get("model")
+	if model == "" {
+		http.Error(w, "model is required", http.StatusBadRequest)
+		return
+	}
+
+	timeout := defaultWarmTimeout
+	if t := r.URL.Query().Get("timeout"); t != "" {
+		var err error
+		if timeout, err = time.ParseDuration(t); err != nil || timeout <= 0 {
+			http.Error(w, "invalid timeout", http.StatusBadRequest)
+			return
+		}
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	recorder, err := h.preload(ctx, model, r.URL.Query().Get("backend"), r.UserAgent())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		http.Error(w, fmt.Sprintf("model was not ready within %s", timeout), http.StatusGatewayTimeout)
+		return
+	}
+	if recorder.Code != http.StatusOK {
+		http.Error(w, strings.TrimSpace(recorder.Body.String()), recorder.Code)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
 // GetModelConfigs returns model configurations. If a model is specified in the query parameter,
 // returns only configs for that model; otherwise returns all configs.
 func (h *HTTPHandler) GetModelConfigs(w http.ResponseWriter, r *http.Request) {
@@ -552,7 +683,7 @@ func (h *HTTPHandler) RebuildRoutes(allowedOrigins []string) {
 	h.lock.Lock()
 	defer h.lock.Unlock()
 	// Update handlers that depend on the allowed origins.
-	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
+	h.httpHandler = middleware.LoggingMiddleware(h.scheduler.log, middleware.CorsMiddleware(allowedOrigins, h.router))
 }
 
 // GetLlamaCppSocket delegates to the scheduler's business logic.
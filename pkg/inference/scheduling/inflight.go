@@ -0,0 +1,85 @@
+package scheduling
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// inflightRetryAfter is the Retry-After value, in seconds, sent with chat
+// completion requests rejected because their backend is at its limit.
+const inflightRetryAfter = "1"
+
+// errInflightLimitReached indicates that a backend is already serving as many
+// chat completion requests as it is allowed to. If returned in conjunction
+// with an HTTP request, it should be paired with a 503 response status and a
+// Retry-After header.
+var errInflightLimitReached = errors.New("too many requests in flight")
+
+// InflightLimit configures how many chat completion requests each backend
+// serves at once.
+type InflightLimit struct {
+	// Max is the maximum number of concurrent chat completion requests per
+	// backend. Zero means no limit.
+	Max int
+	// Queue makes requests beyond Max wait for a slot instead of being
+	// rejected.
+	Queue bool
+}
+
+// inflightLimiter bounds the number of concurrent chat completion requests
+// per backend.
+type inflightLimiter struct {
+	// limit is the configured limit.
+	limit InflightLimit
+	// lock protects slots.
+	lock sync.Mutex
+	// slots holds a semaphore per backend name, created on first use.
+	slots map[string]chan struct{}
+}
+
+// newInflightLimiter creates a limiter enforcing limit.
+func newInflightLimiter(limit InflightLimit) *inflightLimiter {
+	return &inflightLimiter{
+		limit: limit,
+		slots: make(map[string]chan struct{}),
+	}
+}
+
+// semaphore returns the semaphore for backend, creating it if needed.
+func (l *inflightLimiter) semaphore(backend string) chan struct{} {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	slots, ok := l.slots[backend]
+	if !ok {
+		slots = make(chan struct{}, l.limit.Max)
+		l.slots[backend] = slots
+	}
+	return slots
+}
+
+// acquire claims a slot for a request to backend, returning a function that
+// releases it. If the backend has no free slot, acquire either waits for one
+// until ctx is done or fails with errInflightLimitReached, depending on the
+// configuration.
+func (l *inflightLimiter) acquire(ctx context.Context, backend string) (func(), error) {
+	if l == nil || l.limit.Max <= 0 {
+		return func() {}, nil
+	}
+	slots := l.semaphore(backend)
+	release := func() { <-slots }
+	if !l.limit.Queue {
+		select {
+		case slots <- struct{}{}:
+			return release, nil
+		default:
+			return nil, errInflightLimitReached
+		}
+	}
+	select {
+	case slots <- struct{}{}:
+		return release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"runtime"
 	"time"
 
+	"github.com/docker/model-runner/pkg/distribution/types"
 	"github.com/docker/model-runner/pkg/environment"
 	"github.com/docker/model-runner/pkg/inference"
 	"github.com/docker/model-runner/pkg/inference/models"
@@ -136,6 +137,8 @@ type loader struct {
 	runnerConfigs map[runnerKey]inference.BackendConfiguration
 	// openAIRecorder is used to record OpenAI API inference requests and responses.
 	openAIRecorder *metrics.OpenAIRecorder
+	// events broadcasts runner lifecycle events. It has its own lock.
+	events *eventBroadcaster
 }
 
 // newLoader creates a new loader.
@@ -178,6 +181,7 @@ func newLoader(
 		timestamps:        make([]time.Time, nSlots),
 		runnerConfigs:     make(map[runnerKey]inference.BackendConfiguration),
 		openAIRecorder:    openAIRecorder,
+		events:            newEventBroadcaster(),
 	}
 	l.guard <- struct{}{}
 	return l
@@ -212,10 +216,36 @@ func (l *loader) broadcast() {
 // freeRunnerSlot frees a runner slot.
 // The caller must hold the loader lock.
 func (l *loader) freeRunnerSlot(slot int, key runnerKey) {
+	modelRef := l.runners[key].modelRef
 	l.slots[slot].terminate()
 	l.slots[slot] = nil
 	l.timestamps[slot] = time.Time{}
 	delete(l.runners, key)
+	l.unlockModels(key.modelID, key.draftModelID)
+	l.events.publish(newBackendEvent(BackendEventEvicted, key.backend, key.modelID, modelRef, key.mode, nil))
+}
+
+// lockModels keeps the models used by a runner from being evicted from the
+// model store while the runner exists.
+func (l *loader) lockModels(modelID, draftModelID string) {
+	if l.modelManager == nil {
+		return
+	}
+	l.modelManager.LockModel(modelID)
+	if draftModelID != "" {
+		l.modelManager.LockModel(draftModelID)
+	}
+}
+
+// unlockModels releases the locks taken by lockModels.
+func (l *loader) unlockModels(modelID, draftModelID string) {
+	if l.modelManager == nil {
+		return
+	}
+	l.modelManager.UnlockModel(modelID)
+	if draftModelID != "" {
+		l.modelManager.UnlockModel(draftModelID)
+	}
 }
 
 // runnerIdleTimeoutFor returns the idle timeout for a runner, using its
@@ -509,21 +539,28 @@ func (l *loader) load(ctx context.Context, backendName, modelID, modelRef string
 		}
 	}
 
+	var modelConfig types.ModelConfig
+	if l.modelManager != nil && (runnerConfig == nil || runnerConfig.ContextSize == nil) {
+		if bundle, err := l.modelManager.GetBundle(modelID); err != nil {
+			l.log.Warn("Failed to get bundle for model to determine default context size", "model", modelID, "error", err)
+		} else {
+			modelConfig = bundle.RuntimeConfig()
+		}
+	}
+
 	// If no explicit config exists, create a default one with the model's context size
 	// so that the OpenAI recorder can report the actual configuration being used.
 	if runnerConfig == nil {
 		defaultConfig := inference.BackendConfiguration{}
-		if l.modelManager != nil {
-			if bundle, err := l.modelManager.GetBundle(modelID); err != nil {
-				l.log.Warn("Failed to get bundle for model to determine default context size", "model", modelID, "error", err)
-			} else if runtimeConfig := bundle.RuntimeConfig(); runtimeConfig != nil {
-				if ctxSize := runtimeConfig.GetContextSize(); ctxSize != nil {
-					defaultConfig.ContextSize = ctxSize
-				}
-			}
+		if modelConfig != nil {
+			defaultConfig.ContextSize = modelConfig.GetContextSize()
 		}
 		runnerConfig = &defaultConfig
 	}
+	// Neither the model nor the run sets a context size, so use the default.
+	if runnerConfig.ContextSize == nil && modelConfig != nil && modelConfig.GetContextSize() == nil {
+		runnerConfig.ContextSize = l.modelManager.DefaultContextSize(modelConfig)
+	}
 
 	// Create a polling channel that we can use to detect state changes and
 	// ensure that it's deregistered by the time we return.
@@ -614,11 +651,14 @@ func (l *loader) load(ctx context.Context, backendName, modelID, modelRef string
 				modelRef:     modelRef,
 				mode:         mode,
 			}
+			l.lockModels(modelID, draftModelID)
 			l.unlock()
 
 			newRunner, err := run(l.log, backend, modelID, modelRef, mode, slot, runnerConfig, l.openAIRecorder)
 			if err != nil {
 				l.log.Warn("Unable to start backend runner", "backend", backendName, "model", modelID, "mode", mode, "error", err)
+				l.events.publish(newBackendEvent(BackendEventError, backendName, modelID, modelRef, mode, err))
+				l.unlockModels(modelID, draftModelID)
 				l.lock(context.Background())
 				delete(l.loading, slot)
 				l.broadcast()
@@ -628,6 +668,8 @@ func (l *loader) load(ctx context.Context, backendName, modelID, modelRef string
 			if err := newRunner.wait(ctx); err != nil {
 				newRunner.terminate()
 				l.log.Warn("Backend runner initialization failed", "backend", backendName, "model", modelID, "mode", mode, "error", err)
+				l.events.publish(newBackendEvent(BackendEventError, backendName, modelID, modelRef, mode, err))
+				l.unlockModels(modelID, draftModelID)
 				l.lock(context.Background())
 				delete(l.loading, slot)
 				l.broadcast()
@@ -643,6 +685,7 @@ func (l *loader) load(ctx context.Context, backendName, modelID, modelRef string
 			l.slots[slot] = newRunner
 			l.references[slot] = 1
 			l.broadcast()
+			l.events.publish(newBackendEvent(BackendEventLoaded, backendName, modelID, modelRef, mode, nil))
 			return cleanupAndReturn(newRunner, nil)
 		}
 
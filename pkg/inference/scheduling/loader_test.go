@@ -1,15 +1,20 @@
 package scheduling
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"log/slog"
 	"net/http"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/docker/model-runner/pkg/distribution/builder"
+	"github.com/docker/model-runner/pkg/distribution/tarball"
 	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/models"
 )
 
 // mockBackend is a minimal backend implementation for testing
@@ -445,3 +450,116 @@ func TestUnusedRunnerEvictionTriggersRetry(t *testing.T) {
 		t.Error("Unexpected success; acceptable but unusual with fastFail backend")
 	}
 }
+
+// TestLoadFailurePublishesErrorEvent tests that a runner failing to start is
+// reported to event subscribers.
+func TestLoadFailurePublishesErrorEvent(t *testing.T) {
+	log := createTestLogger()
+	backend := &fastFailBackend{mockBackend: mockBackend{name: "test-backend"}}
+	loader := newLoader(log, map[string]inference.Backend{"test-backend": backend}, nil, nil)
+	if !loader.lock(t.Context()) {
+		t.Fatal("Failed to acquire loader lock to enable loads")
+	}
+	loader.loadsEnabled = true
+	loader.unlock()
+
+	events, unsubscribe := loader.events.subscribe()
+	defer unsubscribe()
+
+	if _, err := loader.load(t.Context(), "test-backend", "model1", "model1:latest", inference.BackendModeCompletion); err == nil {
+		t.Fatal("Expected load to fail")
+	}
+
+	select {
+	case event := <-events:
+		if event.Type != BackendEventError || event.ModelID != "model1" || event.ModelName != "model1:latest" || event.Error == "" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	default:
+		t.Fatal("Expected an error event")
+	}
+}
+
+// configRecordingBackend is a backend that records the configuration it is
+// run with and then fails.
+type configRecordingBackend struct {
+	mockBackend
+	config *inference.BackendConfiguration
+}
+
+func (b *configRecordingBackend) Run(ctx context.Context, socket, model string, modelRef string, mode inference.BackendMode, config *inference.BackendConfiguration) error {
+	b.config = config
+	return errors.New("boom")
+}
+
+func TestLoadDefaultContextSize(t *testing.T) {
+	int32Ptr := func(n int32) *int32 { return &n }
+	tests := []struct {
+		name             string
+		modelContextSize *int32
+		runConfig        *inference.BackendConfiguration
+		want             *int32
+	}{
+		{name: "model and run unset", want: int32Ptr(8192)},
+		{name: "run config without context size", runConfig: &inference.BackendConfiguration{KeepAlive: keepAlivePtr(inference.KeepAliveForever)}, want: int32Ptr(8192)},
+		{name: "model sets context size", modelContextSize: int32Ptr(2048), want: int32Ptr(2048)},
+		{name: "run sets context size", runConfig: &inference.BackendConfiguration{ContextSize: int32Ptr(1024)}, want: int32Ptr(1024)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := createTestLogger()
+			manager := models.NewManager(log, models.ClientConfig{
+				StoreRootPath:      t.TempDir(),
+				Logger:             log,
+				DefaultContextSize: 8192,
+			})
+			b, err := builder.FromPath(filepath.Join("..", "..", "..", "assets", "dummy.gguf"))
+			if err != nil {
+				t.Fatalf("Failed to create model builder: %v", err)
+			}
+			if tt.modelContextSize != nil {
+				b = b.WithContextSize(*tt.modelContextSize)
+			}
+			var archive bytes.Buffer
+			target, err := tarball.NewTarget(&archive)
+			if err != nil {
+				t.Fatalf("Failed to create target: %v", err)
+			}
+			if err := b.Build(t.Context(), target, nil); err != nil {
+				t.Fatalf("Failed to build model: %v", err)
+			}
+			if err := manager.Load(t.Context(), &archive, io.Discard); err != nil {
+				t.Fatalf("Failed to load model: %v", err)
+			}
+			stored, err := manager.List()
+			if err != nil || len(stored) != 1 {
+				t.Fatalf("Expected one stored model, got %d (error %v)", len(stored), err)
+			}
+			modelID := stored[0].ID
+
+			backend := &configRecordingBackend{mockBackend: mockBackend{name: "test-backend"}}
+			loader := newLoader(log, map[string]inference.Backend{"test-backend": backend}, manager, nil)
+			if !loader.lock(t.Context()) {
+				t.Fatal("Failed to acquire loader lock to enable loads")
+			}
+			loader.loadsEnabled = true
+			loader.unlock()
+			if tt.runConfig != nil {
+				if err := loader.setRunnerConfig(t.Context(), "test-backend", modelID, inference.BackendModeCompletion, *tt.runConfig); err != nil {
+					t.Fatalf("Failed to configure runner: %v", err)
+				}
+			}
+
+			if _, err := loader.load(t.Context(), "test-backend", modelID, modelID, inference.BackendModeCompletion); err == nil {
+				t.Fatal("Expected load to fail")
+			}
+			if backend.config == nil || backend.config.ContextSize == nil {
+				t.Fatalf("Expected context size %d, got none", *tt.want)
+			}
+			if got := *backend.config.ContextSize; got != *tt.want {
+				t.Errorf("Expected context size %d, got %d", *tt.want, got)
+			}
+		})
+	}
+}
@@ -27,6 +27,9 @@ const (
 	// readinessRetryInterval is the interval at which a runner will retry
 	// readiness checks for a backend.
 	readinessRetryInterval = 500 * time.Millisecond
+	// healthCheckTimeout is the maximum amount of time that a runner will wait
+	// for a backend to respond to a health probe.
+	healthCheckTimeout = 2 * time.Second
 	// tcpBackendBasePort is the base port number for TCP-based backends.
 	// Each slot gets a unique port: basePort + slot (e.g., 30000, 30001, 30002).
 	// Port 30000+ is used to avoid conflicts with common services.
@@ -252,6 +255,35 @@ func (r *runner) wait(ctx context.Context) error {
 	return errBackendNotReadyInTime
 }
 
+// checkHealth probes the backend's health endpoint. It returns nil if and only
+// if the backend is still running and responded with a 200 status.
+func (r *runner) checkHealth(ctx context.Context) error {
+	select {
+	case <-r.done:
+		if r.err == nil {
+			return errBackendQuitUnexpectedly
+		}
+		return r.err
+	default:
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	healthRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://localhost/health", http.NoBody)
+	if err != nil {
+		return fmt.Errorf("health request creation failed: %w", err)
+	}
+	response, err := r.client.Do(healthRequest)
+	if err != nil {
+		return fmt.Errorf("health request failed: %w", err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("health request returned status %s", response.Status)
+	}
+	return nil
+}
+
 // terminate stops the runner instance and waits for it to unload from memory.
 func (r *runner) terminate() {
 	// Signal termination and wait for the run loop to exit.
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"runtime"
 	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/types"
@@ -20,6 +24,7 @@ import (
 	"github.com/docker/model-runner/pkg/internal/utils"
 	"github.com/docker/model-runner/pkg/logging"
 	"github.com/docker/model-runner/pkg/metrics"
+	"github.com/docker/model-runner/pkg/servicemetrics"
 	"github.com/mattn/go-shellwords"
 	"golang.org/x/sync/errgroup"
 )
@@ -32,6 +37,7 @@ type PlatformSupport interface {
 	SupportsVLLMMetal() bool
 	SupportsSGLang() bool
 	SupportsDiffusers() bool
+	GPUs() ([]platform.GPU, error)
 }
 
 // defaultPlatformSupport delegates to the platform package.
@@ -42,6 +48,9 @@ func (defaultPlatformSupport) SupportsVLLM() bool      { return platform.Support
 func (defaultPlatformSupport) SupportsVLLMMetal() bool { return platform.SupportsVLLMMetal() }
 func (defaultPlatformSupport) SupportsSGLang() bool    { return platform.SupportsSGLang() }
 func (defaultPlatformSupport) SupportsDiffusers() bool { return platform.SupportsDiffusers() }
+func (defaultPlatformSupport) GPUs() ([]platform.GPU, error) {
+	return platform.DetectGPUs()
+}
 
 // Scheduler is used to coordinate inference scheduling across multiple backends
 // and models.
@@ -67,6 +76,10 @@ type Scheduler struct {
 	deferredBackends []string
 	// platformSupport provides platform capability checks for backend selection.
 	platformSupport PlatformSupport
+	// inflight limits concurrent chat completion requests per backend.
+	inflight *inflightLimiter
+	// metrics records inference requests. It may be nil.
+	metrics *servicemetrics.Metrics
 }
 
 // NewScheduler creates a new inference scheduler. Backends listed in
@@ -95,12 +108,25 @@ func NewScheduler(
 		openAIRecorder:   openAIRecorder,
 		deferredBackends: deferredBackends,
 		platformSupport:  defaultPlatformSupport{},
+		inflight:         newInflightLimiter(InflightLimit{}),
 	}
 
 	// Scheduler successfully initialized.
 	return s
 }
 
+// SetInflightLimit sets how many chat completion requests each backend serves
+// at once. It must be called before the scheduler starts serving requests.
+func (s *Scheduler) SetInflightLimit(limit InflightLimit) {
+	s.inflight = newInflightLimiter(limit)
+}
+
+// SetMetrics sets the metrics inference requests are recorded in. It must be
+// called before the scheduler starts serving requests.
+func (s *Scheduler) SetMetrics(m *servicemetrics.Metrics) {
+	s.metrics = m
+}
+
 // Run is the scheduler's main run loop. By the time it returns, all inference
 // backends will have been unloaded from memory.
 func (s *Scheduler) Run(ctx context.Context) error {
@@ -142,6 +168,30 @@ func (s *Scheduler) selectBackendForModel(model types.Model, backend inference.B
 		format = inferFormatFromModel(model)
 	}
 
+	if formatBackend := s.backendForFormat(format, backend); formatBackend != nil {
+		return formatBackend
+	}
+
+	backendName := "none"
+	if backend != nil {
+		backendName = backend.Name()
+	}
+	switch format {
+	case types.FormatSafetensors:
+		s.log.Warn("Model is in safetensors format but no compatible backend is available",
+			"model", utils.SanitizeForLog(modelRef), "backend", backendName)
+	case types.FormatDDUF, types.FormatDiffusers: //nolint:staticcheck // FormatDiffusers kept for backward compatibility
+		s.log.Warn("Model is in DDUF/diffusers format but no compatible backend is available",
+			"model", utils.SanitizeForLog(modelRef), "backend", backendName)
+	}
+
+	return backend
+}
+
+// backendForFormat returns the backend that runs models of the given format
+// on this platform, or nil if no registered backend can. Formats without a
+// dedicated backend (e.g. GGUF) run on defaultBackend.
+func (s *Scheduler) backendForFormat(format types.Format, defaultBackend inference.Backend) inference.Backend {
 	switch format {
 	case types.FormatSafetensors:
 		// Prefer vLLM for safetensors models (handles platform dispatch internally)
@@ -162,12 +212,7 @@ func (s *Scheduler) selectBackendForModel(model types.Model, backend inference.B
 				return sglangBackend
 			}
 		}
-		backendName := "none"
-		if backend != nil {
-			backendName = backend.Name()
-		}
-		s.log.Warn("Model is in safetensors format but no compatible backend is available",
-			"model", utils.SanitizeForLog(modelRef), "backend", backendName)
+		return nil
 
 	case types.FormatDDUF, types.FormatDiffusers: //nolint:staticcheck // FormatDiffusers kept for backward compatibility
 		// Select the diffusers backend for DDUF and legacy diffusers format models
@@ -176,21 +221,56 @@ func (s *Scheduler) selectBackendForModel(model types.Model, backend inference.B
 				return diffusersBackend
 			}
 		}
-		backendName := "none"
-		if backend != nil {
-			backendName = backend.Name()
-		}
-		s.log.Warn("Model is in DDUF/diffusers format but no compatible backend is available",
-			"model", utils.SanitizeForLog(modelRef), "backend", backendName)
-
-	case types.FormatGGUF:
-		// GGUF models use the default backend (llamacpp)
+		return nil
 
 	default:
-		// Unknown formats use the default backend
+		// GGUF and unknown formats use the default backend
+		return defaultBackend
 	}
+}
 
-	return backend
+// SupportsFormat reports whether a registered backend can run models of the
+// given format on this platform. Backends whose installation is deferred
+// count, since they are installed on first use.
+func (s *Scheduler) SupportsFormat(format types.Format) bool {
+	return s.backendForFormat(format, s.defaultBackend) != nil
+}
+
+// SupportedArchitectures returns the model architectures the named backend
+// can run. It returns ErrBackendNotFound for unknown backends and
+// errArchitecturesNotReported for backends that don't say which
+// architectures they support.
+func (s *Scheduler) SupportedArchitectures(name string) ([]string, error) {
+	backend, ok := s.backends[name]
+	if !ok || backend == nil {
+		return nil, ErrBackendNotFound
+	}
+	reporter, ok := backend.(inference.ArchitectureReporter)
+	if !ok {
+		return nil, errArchitecturesNotReported
+	}
+	return reporter.SupportedArchitectures(), nil
+}
+
+// SupportsArchitecture reports whether the backend that runs models of the
+// given format on this platform can run the given architecture. It reports
+// true if the architecture is unknown or the backend does not report its
+// architectures, so that only models known to be unsupported are flagged.
+func (s *Scheduler) SupportsArchitecture(format types.Format, architecture string) bool {
+	if architecture == "" {
+		return true
+	}
+	backend := s.backendForFormat(format, s.defaultBackend)
+	if backend == nil {
+		return true
+	}
+	reporter, ok := backend.(inference.ArchitectureReporter)
+	if !ok {
+		return true
+	}
+	return slices.ContainsFunc(reporter.SupportedArchitectures(), func(supported string) bool {
+		return strings.EqualFold(supported, architecture)
+	})
 }
 
 // inferFormatFromModel detects the model format by checking which file types
@@ -221,6 +301,12 @@ func (s *Scheduler) InstallBackend(ctx context.Context, name string) error {
 	return s.installer.installBackend(ctx, name)
 }
 
+// UnloadAll unloads the runners of every backend that are not serving
+// requests and returns the number of runners it unloaded.
+func (s *Scheduler) UnloadAll(ctx context.Context) int {
+	return s.loader.Unload(ctx, UnloadRequest{All: true})
+}
+
 // UninstallBackend unloads all runners for the backend and then removes its
 // local installation.
 func (s *Scheduler) UninstallBackend(ctx context.Context, name string) error {
@@ -228,22 +314,95 @@ func (s *Scheduler) UninstallBackend(ctx context.Context, name string) error {
 	return s.installer.uninstallBackend(ctx, name)
 }
 
-// GetRunningBackendsInfo returns information about all running backends as a slice
+// PlatformInfo describes the host platform, its GPUs, and whether each
+// registered backend can run on it. A GPU detection failure is logged and
+// reported as no GPUs, since backend support does not depend on it.
+func (s *Scheduler) PlatformInfo() platform.Info {
+	info := platform.Info{
+		OS:       runtime.GOOS,
+		Arch:     runtime.GOARCH,
+		GPUs:     []platform.GPU{},
+		Backends: []platform.BackendSupport{},
+	}
+	if gpus, err := s.platformSupport.GPUs(); err != nil {
+		s.log.Warn("failed to detect GPUs", "error", err)
+	} else if gpus != nil {
+		info.GPUs = gpus
+	}
+	for _, name := range slices.Sorted(maps.Keys(s.backends)) {
+		info.Backends = append(info.Backends, platform.BackendSupport{
+			Name:      name,
+			Supported: s.backendSupported(name),
+		})
+	}
+	return info
+}
+
+// backendSupported reports whether the named backend can run on the host.
+// Backends without platform restrictions, such as llama.cpp, are always
+// supported.
+func (s *Scheduler) backendSupported(name string) bool {
+	switch name {
+	case vllm.Name:
+		return s.platformSupport.SupportsVLLM() || s.platformSupport.SupportsVLLMMetal()
+	case mlx.Name:
+		return s.platformSupport.SupportsMLX()
+	case sglang.Name:
+		return s.platformSupport.SupportsSGLang()
+	case diffusers.Name:
+		return s.platformSupport.SupportsDiffusers()
+	default:
+		return true
+	}
+}
+
+// GetRunningBackendsInfo returns information about all running backends as a
+// slice, including the result of probing each loaded backend's health.
 func (s *Scheduler) GetRunningBackendsInfo(ctx context.Context) []BackendStatus {
-	return s.getLoaderStatus(ctx)
+	statuses, runners := s.loaderStatus(ctx)
+
+	// Probe backends concurrently and without holding the loader lock, since
+	// an unresponsive backend could otherwise stall all loader operations.
+	var wg sync.WaitGroup
+	for i, r := range runners {
+		if r == nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.checkHealth(ctx); err != nil {
+				statuses[i].LastError = err.Error()
+				return
+			}
+			statuses[i].Healthy = true
+		}()
+	}
+	wg.Wait()
+
+	return statuses
 }
 
 // getLoaderStatus returns information about all running backends managed by the loader
 func (s *Scheduler) getLoaderStatus(ctx context.Context) []BackendStatus {
+	statuses, _ := s.loaderStatus(ctx)
+	return statuses
+}
+
+// loaderStatus returns information about all running backends managed by the
+// loader, along with a parallel slice of the corresponding runners. Entries
+// for backends that are still loading have a nil runner.
+func (s *Scheduler) loaderStatus(ctx context.Context) ([]BackendStatus, []*runner) {
 	if !s.loader.lock(ctx) {
-		return []BackendStatus{}
+		return []BackendStatus{}, nil
 	}
 	defer s.loader.unlock()
 
 	result := make([]BackendStatus, 0, len(s.loader.runners)+len(s.loader.loading))
+	runners := make([]*runner, 0, len(s.loader.runners)+len(s.loader.loading))
 
 	for key, runnerInfo := range s.loader.runners {
-		if s.loader.slots[runnerInfo.slot] != nil {
+		if r := s.loader.slots[runnerInfo.slot]; r != nil {
 			status := BackendStatus{
 				BackendName: key.backend,
 				ModelName:   runnerInfo.modelRef,
@@ -262,6 +421,7 @@ func (s *Scheduler) getLoaderStatus(ctx context.Context) []BackendStatus {
 			}
 
 			result = append(result, status)
+			runners = append(runners, r)
 		}
 	}
 
@@ -273,9 +433,10 @@ func (s *Scheduler) getLoaderStatus(ctx context.Context) []BackendStatus {
 			Mode:        info.mode.String(),
 			Loading:     true,
 		})
+		runners = append(runners, nil)
 	}
 
-	return result
+	return result, runners
 }
 
 // GetAllActiveRunners returns information about all active runners
@@ -372,10 +533,21 @@ func (s *Scheduler) ConfigureRunner(ctx context.Context, backend inference.Backe
 		return nil, err
 	}
 
+	// Validate extra arguments against the backend denylist and path safety
+	if err := inference.ValidateExtraArgs(backend.Name(), req.ExtraArgs); err != nil {
+		return nil, err
+	}
+
+	if req.GPULayers != nil && *req.GPULayers < 0 {
+		return nil, fmt.Errorf("gpu-layers must not be negative, got %d", *req.GPULayers)
+	}
+
 	var runnerConfig inference.BackendConfiguration
 	runnerConfig.ContextSize = req.ContextSize
+	runnerConfig.GPULayers = req.GPULayers
 	runnerConfig.Speculative = req.Speculative
 	runnerConfig.RuntimeFlags = runtimeFlags
+	runnerConfig.ExtraArgs = req.ExtraArgs
 	runnerConfig.KeepAlive = req.KeepAlive
 
 	// Set vLLM-specific configuration if provided
@@ -408,7 +580,7 @@ func (s *Scheduler) ConfigureRunner(ctx context.Context, backend inference.Backe
 	}
 
 	// Get model, track usage, and select appropriate backend
-	if model, err := s.modelManager.GetLocal(req.Model); err == nil {
+	if model, err := s.modelManager.ResolveLocal(req.Model); err == nil {
 		// Configure is called by compose for each model
 		s.tracker.TrackModel(model, userAgent, "configure/"+mode.String())
 
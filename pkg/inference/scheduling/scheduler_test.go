@@ -1,10 +1,38 @@
 package scheduling
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/backends/diffusers"
+	"github.com/docker/model-runner/pkg/inference/backends/llamacpp"
+	"github.com/docker/model-runner/pkg/inference/backends/mlx"
+	"github.com/docker/model-runner/pkg/inference/backends/sglang"
+	"github.com/docker/model-runner/pkg/inference/backends/vllm"
+	"github.com/docker/model-runner/pkg/inference/models"
+	"github.com/docker/model-runner/pkg/inference/platform"
+	"github.com/docker/model-runner/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestCors(t *testing.T) {
@@ -42,3 +70,623 @@ func TestCors(t *testing.T) {
 		})
 	}
 }
+
+// newMockSocketRunner creates a runner whose client targets a mock backend
+// listening on a Unix domain socket and serving /health with the specified
+// status code.
+func newMockSocketRunner(t *testing.T, log *slog.Logger, backend inference.Backend, healthStatus int) *runner {
+	t.Helper()
+
+	socket := filepath.Join(t.TempDir(), "backend.sock")
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Fatalf("Failed to listen on mock backend socket: %v", err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/health" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(healthStatus)
+	})}
+	go func() { _ = server.Serve(listener) }()
+
+	dialer := &net.Dialer{}
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}
+	t.Cleanup(func() {
+		transport.CloseIdleConnections()
+		_ = server.Close()
+	})
+
+	return &runner{
+		log:       log,
+		backend:   backend,
+		model:     "model1",
+		mode:      inference.BackendModeCompletion,
+		cancel:    func() {},
+		done:      make(chan struct{}),
+		transport: transport,
+		client:    &http.Client{Transport: transport},
+		proxyLog:  io.NopCloser(nil),
+	}
+}
+
+// TestGetRunningBackendsInfoHealth tests that running backends are probed for
+// health and that the result is reported in their status.
+func TestGetRunningBackendsInfoHealth(t *testing.T) {
+	tests := []struct {
+		name          string
+		healthStatus  int
+		expectHealthy bool
+	}{
+		{
+			name:          "healthy",
+			healthStatus:  http.StatusOK,
+			expectHealthy: true,
+		},
+		{
+			name:          "unhealthy",
+			healthStatus:  http.StatusServiceUnavailable,
+			expectHealthy: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			log := createTestLogger()
+			backend := &mockBackend{name: "test-backend"}
+			s := NewScheduler(log, map[string]inference.Backend{"test-backend": backend}, backend, nil, nil, nil, nil)
+
+			if !s.loader.lock(t.Context()) {
+				t.Fatal("Failed to acquire loader lock")
+			}
+			s.loader.slots[0] = newMockSocketRunner(t, log, backend, tt.healthStatus)
+			s.loader.runners[makeRunnerKey("test-backend", "model1", "", inference.BackendModeCompletion)] = runnerInfo{slot: 0, modelRef: "model1:latest"}
+			s.loader.unlock()
+
+			statuses := s.GetRunningBackendsInfo(t.Context())
+			if len(statuses) != 1 {
+				t.Fatalf("Expected 1 backend status, got %d", len(statuses))
+			}
+			status := statuses[0]
+			if status.Healthy != tt.expectHealthy {
+				t.Errorf("Expected Healthy=%v, got %v", tt.expectHealthy, status.Healthy)
+			}
+			if tt.expectHealthy && status.LastError != "" {
+				t.Errorf("Expected no LastError for healthy backend, got %q", status.LastError)
+			}
+			if !tt.expectHealthy && status.LastError == "" {
+				t.Error("Expected LastError to be set for unhealthy backend")
+			}
+		})
+	}
+}
+
+// TestGetRunningBackendsInfoLoading tests that backends which are still loading
+// are not probed and are reported as not healthy.
+func TestGetRunningBackendsInfoLoading(t *testing.T) {
+	log := createTestLogger()
+	s := NewScheduler(log, nil, nil, nil, nil, nil, nil)
+
+	if !s.loader.lock(t.Context()) {
+		t.Fatal("Failed to acquire loader lock")
+	}
+	s.loader.loading[0] = loadingInfo{backendName: "test-backend", modelRef: "model1:latest", mode: inference.BackendModeCompletion}
+	s.loader.unlock()
+
+	statuses := s.GetRunningBackendsInfo(t.Context())
+	if len(statuses) != 1 {
+		t.Fatalf("Expected 1 backend status, got %d", len(statuses))
+	}
+	if !statuses[0].Loading || statuses[0].Healthy || statuses[0].LastError != "" {
+		t.Errorf("Unexpected status for loading backend: %+v", statuses[0])
+	}
+}
+
+// warmBackend is a mock backend whose runners serve /health, reporting
+// readiness only once ready is closed.
+type warmBackend struct {
+	mockBackend
+	// started is closed when the backend is first run.
+	started   chan struct{}
+	startOnce sync.Once
+	// ready is closed to make the backend report readiness.
+	ready chan struct{}
+}
+
+func (b *warmBackend) Run(ctx context.Context, socket, model string, modelRef string, mode inference.BackendMode, config *inference.BackendConfiguration) error {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	b.startOnce.Do(func() { close(b.started) })
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-b.ready:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})}
+	go func() { _ = server.Serve(listener) }()
+	<-ctx.Done()
+	return server.Close()
+}
+
+// newWarmTestHandler starts a scheduler running backend and returns its HTTP
+// handler.
+func newWarmTestHandler(t *testing.T, backend inference.Backend) *HTTPHandler {
+	t.Helper()
+
+	// Keep socket paths short enough for Unix domain sockets.
+	socketDir, err := os.MkdirTemp("", "warm")
+	if err != nil {
+		t.Fatalf("Failed to create socket directory: %v", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(socketDir) })
+	defaultSocketPath := RunnerSocketPath
+	RunnerSocketPath = func(slot int) (string, error) {
+		return filepath.Join(socketDir, fmt.Sprintf("runner-%d.sock", slot)), nil
+	}
+	t.Cleanup(func() { RunnerSocketPath = defaultSocketPath })
+
+	log := createTestLogger()
+	manager := models.NewManager(log, models.ClientConfig{StoreRootPath: t.TempDir(), Logger: log})
+	s := NewScheduler(log, map[string]inference.Backend{backend.Name(): backend}, backend, manager, nil, nil, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = s.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	for !s.installer.started.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	return NewHTTPHandler(s, nil, nil)
+}
+
+// TestWarm tests that warming a model starts its backend and responds only
+// once the backend is ready.
+func TestWarm(t *testing.T) {
+	backend := &warmBackend{
+		mockBackend: mockBackend{name: "test-backend", usesExternalModelMgmt: true},
+		started:     make(chan struct{}),
+		ready:       make(chan struct{}),
+	}
+	h := newWarmTestHandler(t, backend)
+
+	responded := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/warm?model=model1&backend=test-backend", http.NoBody)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		responded <- w
+	}()
+
+	select {
+	case <-backend.started:
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected warm request to start the backend")
+	}
+	select {
+	case w := <-responded:
+		t.Fatalf("Expected warm request to wait for readiness, got status %d: %s", w.Code, w.Body.String())
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	close(backend.ready)
+	select {
+	case w := <-responded:
+		if w.Code != http.StatusOK {
+			t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Expected warm request to respond once the backend was ready")
+	}
+
+	statuses := h.scheduler.GetRunningBackendsInfo(t.Context())
+	if len(statuses) != 1 || statuses[0].ModelName != "model1" {
+		t.Errorf("Expected model1 to be running, got %+v", statuses)
+	}
+}
+
+// TestWarmTimeout tests that warming a model reports a timeout if the backend
+// is not ready in time.
+func TestWarmTimeout(t *testing.T) {
+	backend := &warmBackend{
+		mockBackend: mockBackend{name: "test-backend", usesExternalModelMgmt: true},
+		started:     make(chan struct{}),
+		ready:       make(chan struct{}),
+	}
+	h := newWarmTestHandler(t, backend)
+
+	req := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/warm?model=model1&timeout=100ms", http.NoBody)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("Expected status 504, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+// TestWarmValidation tests that invalid warm requests are rejected.
+func TestWarmValidation(t *testing.T) {
+	s := NewScheduler(createTestLogger(), nil, nil, nil, nil, nil, nil)
+	h := NewHTTPHandler(s, nil, nil)
+
+	for _, query := range []string{"", "?model=model1&timeout=soon", "?model=model1&timeout=-1s"} {
+		req := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/warm"+query, http.NoBody)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("Query %q: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+// TestGetPlatformInfo tests that the platform endpoint reports detected GPUs
+// and backend support for the host's capabilities.
+func TestGetPlatformInfo(t *testing.T) {
+	backends := map[string]inference.Backend{}
+	for _, name := range []string{llamacpp.Name, vllm.Name, mlx.Name, sglang.Name, diffusers.Name} {
+		backends[name] = &mockBackend{name: name}
+	}
+
+	tests := []struct {
+		name          string
+		platform      mockPlatformSupport
+		wantGPUs      []platform.GPU
+		wantSupported map[string]bool
+	}{
+		{
+			name: "Linux with an NVIDIA GPU",
+			platform: mockPlatformSupport{
+				vllm:      true,
+				sglang:    true,
+				diffusers: true,
+				gpus:      []platform.GPU{{Vendor: "NVIDIA", Name: "NVIDIA GeForce RTX 4090", VRAM: 24 << 30}},
+			},
+			wantGPUs: []platform.GPU{{Vendor: "NVIDIA", Name: "NVIDIA GeForce RTX 4090", VRAM: 24 << 30}},
+			wantSupported: map[string]bool{
+				llamacpp.Name: true, vllm.Name: true, mlx.Name: false, sglang.Name: true, diffusers.Name: true,
+			},
+		},
+		{
+			name: "macOS on Apple Silicon",
+			platform: mockPlatformSupport{
+				mlx:       true,
+				vllmMetal: true,
+				diffusers: true,
+				gpus:      []platform.GPU{{Vendor: "Apple", Name: "Apple Silicon GPU"}},
+			},
+			wantGPUs: []platform.GPU{{Vendor: "Apple", Name: "Apple Silicon GPU"}},
+			wantSupported: map[string]bool{
+				llamacpp.Name: true, vllm.Name: true, mlx.Name: true, sglang.Name: false, diffusers.Name: true,
+			},
+		},
+		{
+			name:     "GPU detection failure",
+			platform: mockPlatformSupport{gpuErr: errors.New("no PCI database")},
+			wantGPUs: []platform.GPU{},
+			wantSupported: map[string]bool{
+				llamacpp.Name: true, vllm.Name: false, mlx.Name: false, sglang.Name: false, diffusers.Name: false,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestSchedulerWithPlatform(backends, backends[llamacpp.Name], tt.platform)
+			h := NewHTTPHandler(s, nil, nil)
+
+			req := httptest.NewRequest(http.MethodGet, inference.InferencePrefix+"/platform", http.NoBody)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+			}
+
+			var info platform.Info
+			if err := json.Unmarshal(w.Body.Bytes(), &info); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+				t.Errorf("Expected platform %s/%s, got %s/%s", runtime.GOOS, runtime.GOARCH, info.OS, info.Arch)
+			}
+			if !reflect.DeepEqual(info.GPUs, tt.wantGPUs) {
+				t.Errorf("Expected GPUs %+v, got %+v", tt.wantGPUs, info.GPUs)
+			}
+			if len(info.Backends) != len(tt.wantSupported) {
+				t.Fatalf("Expected %d backends, got %+v", len(tt.wantSupported), info.Backends)
+			}
+			for i, b := range info.Backends {
+				if i > 0 && info.Backends[i-1].Name >= b.Name {
+					t.Errorf("Expected backends sorted by name, got %+v", info.Backends)
+				}
+				if b.Supported != tt.wantSupported[b.Name] {
+					t.Errorf("Expected %s supported=%v, got %v", b.Name, tt.wantSupported[b.Name], b.Supported)
+				}
+			}
+		})
+	}
+}
+
+// TestGetBackendArchitectures tests that the architectures endpoint reports
+// the architectures a backend supports.
+func TestGetBackendArchitectures(t *testing.T) {
+	backends := map[string]inference.Backend{
+		"test-backend":  &archBackend{mockBackend: mockBackend{name: "test-backend"}, architectures: []string{"llama", "qwen3"}},
+		"other-backend": &mockBackend{name: "other-backend"},
+	}
+	s := NewScheduler(createTestLogger(), backends, backends["test-backend"], nil, nil, nil, nil)
+	h := NewHTTPHandler(s, nil, nil)
+
+	tests := []struct {
+		backend  string
+		wantCode int
+	}{
+		{backend: "test-backend", wantCode: http.StatusOK},
+		{backend: "other-backend", wantCode: http.StatusNotImplemented},
+		{backend: "missing", wantCode: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.backend, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, inference.InferencePrefix+"/backends/"+tt.backend+"/architectures", http.NoBody)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, req)
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusOK {
+				return
+			}
+			var got BackendArchitectures
+			if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			want := BackendArchitectures{Backend: "test-backend", Architectures: []string{"llama", "qwen3"}}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Expected %+v, got %+v", want, got)
+			}
+		})
+	}
+}
+
+// TestGetBackendEvents tests that evicting a runner is delivered to clients of
+// the events stream.
+func TestGetBackendEvents(t *testing.T) {
+	log := createTestLogger()
+	backend := &mockBackend{name: "test-backend"}
+	s := NewScheduler(log, map[string]inference.Backend{"test-backend": backend}, backend, nil, nil, nil, nil)
+	server := httptest.NewServer(NewHTTPHandler(s, nil, nil))
+	defer server.Close()
+
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodGet, server.URL+inference.InferencePrefix+"/events", http.NoBody)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Failed to connect to events stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected Content-Type text/event-stream, got %q", ct)
+	}
+
+	// readEvent reads the next event from the stream.
+	reader := bufio.NewReader(resp.Body)
+	readEvent := func() (name, data string) {
+		t.Helper()
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				t.Fatalf("Failed to read event: %v", err)
+			}
+			line = strings.TrimRight(line, "\n")
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && name != "":
+				return name, data
+			}
+		}
+	}
+	if name, _ := readEvent(); name != "connected" {
+		t.Fatalf("Expected connected event, got %q", name)
+	}
+
+	// Simulate an idle runner being evicted.
+	if !s.loader.lock(t.Context()) {
+		t.Fatal("Failed to acquire loader lock")
+	}
+	s.loader.slots[0] = createAliveTerminableMockRunner(t.Context(), log, backend)
+	s.loader.runners[makeRunnerKey("test-backend", "modelX", "", inference.BackendModeCompletion)] = runnerInfo{slot: 0, modelRef: "ai/model:latest"}
+	if remaining := s.loader.evict(false); remaining != 0 {
+		t.Errorf("Expected no remaining runners, got %d", remaining)
+	}
+	s.loader.unlock()
+
+	name, data := readEvent()
+	if name != string(BackendEventEvicted) {
+		t.Fatalf("Expected %s event, got %q", BackendEventEvicted, name)
+	}
+	var event BackendEvent
+	if err := json.Unmarshal([]byte(data), &event); err != nil {
+		t.Fatalf("Failed to decode event: %v", err)
+	}
+	if event.Type != BackendEventEvicted || event.BackendName != "test-backend" || event.ModelID != "modelX" ||
+		event.ModelName != "ai/model:latest" || event.Mode != inference.BackendModeCompletion.String() {
+		t.Errorf("Unexpected event: %+v", event)
+	}
+	if event.Timestamp.IsZero() {
+		t.Error("Expected event timestamp to be set")
+	}
+}
+
+// chatBackend is a mock backend whose runners hold chat completion requests
+// until released.
+type chatBackend struct {
+	mockBackend
+	// entered receives a value whenever a chat completion request reaches
+	// the backend.
+	entered chan struct{}
+	// release is closed to let held requests complete.
+	release chan struct{}
+}
+
+func (b *chatBackend) Run(ctx context.Context, socket, model string, modelRef string, mode inference.BackendMode, config *inference.BackendConfiguration) error {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			b.entered <- struct{}{}
+			<-b.release
+		}
+		w.WriteHeader(http.StatusOK)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	<-ctx.Done()
+	return server.Close()
+}
+
+// startChatCompletion sends a chat completion request to h in the background
+// and returns a channel receiving its response.
+func startChatCompletion(h *HTTPHandler) <-chan *httptest.ResponseRecorder {
+	responded := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		req := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/v1/chat/completions", strings.NewReader(`{"model":"model1"}`))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		responded <- w
+	}()
+	return responded
+}
+
+// TestInflightLimit tests that chat completion requests beyond the in-flight
+// limit of a backend are rejected or queued according to the configuration.
+func TestInflightLimit(t *testing.T) {
+	const limit = 2
+	for _, queue := range []bool{false, true} {
+		t.Run(fmt.Sprintf("queue=%t", queue), func(t *testing.T) {
+			backend := &chatBackend{
+				mockBackend: mockBackend{name: "test-backend", usesExternalModelMgmt: true},
+				entered:     make(chan struct{}, limit+1),
+				release:     make(chan struct{}),
+			}
+			h := newWarmTestHandler(t, backend)
+			h.scheduler.SetInflightLimit(InflightLimit{Max: limit, Queue: queue})
+
+			var held []<-chan *httptest.ResponseRecorder
+			for range limit {
+				held = append(held, startChatCompletion(h))
+				select {
+				case <-backend.entered:
+				case <-time.After(10 * time.Second):
+					t.Fatal("Expected request within the limit to reach the backend")
+				}
+			}
+
+			extra := startChatCompletion(h)
+			if queue {
+				select {
+				case <-backend.entered:
+					t.Fatal("Expected request beyond the limit to wait for a slot")
+				case w := <-extra:
+					t.Fatalf("Expected request beyond the limit to wait for a slot, got status %d: %s", w.Code, w.Body.String())
+				case <-time.After(200 * time.Millisecond):
+				}
+			} else {
+				select {
+				case w := <-extra:
+					if w.Code != http.StatusServiceUnavailable {
+						t.Errorf("Expected status 503, got %d: %s", w.Code, w.Body.String())
+					}
+					if got := w.Header().Get("Retry-After"); got != inflightRetryAfter {
+						t.Errorf("Expected Retry-After %q, got %q", inflightRetryAfter, got)
+					}
+				case <-time.After(10 * time.Second):
+					t.Fatal("Expected request beyond the limit to be rejected")
+				}
+			}
+
+			close(backend.release)
+			for _, responded := range held {
+				if w := <-responded; w.Code != http.StatusOK {
+					t.Errorf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+				}
+			}
+			if queue {
+				select {
+				case w := <-extra:
+					if w.Code != http.StatusOK {
+						t.Errorf("Expected queued request to succeed, got status %d: %s", w.Code, w.Body.String())
+					}
+				case <-time.After(10 * time.Second):
+					t.Fatal("Expected queued request to complete once a slot was free")
+				}
+			}
+		})
+	}
+}
+
+// failingChatBackend is a mock backend whose runners fail chat completions.
+type failingChatBackend struct {
+	mockBackend
+}
+
+func (b *failingChatBackend) Run(ctx context.Context, socket, model string, modelRef string, mode inference.BackendMode, config *inference.BackendConfiguration) error {
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/chat/completions") {
+			http.Error(w, "backend failure", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})}
+	go func() { _ = server.Serve(listener) }()
+	<-ctx.Done()
+	return server.Close()
+}
+
+// TestChatSpanRecordsError tests that the span of a failed chat completion
+// records the error the backend responded with.
+func TestChatSpanRecordsError(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	backend := &failingChatBackend{mockBackend: mockBackend{name: "test-backend", usesExternalModelMgmt: true}}
+	h := newWarmTestHandler(t, backend)
+	if w := <-startChatCompletion(h); w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status 500, got %d: %s", w.Code, w.Body.String())
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != tracing.SpanChat {
+		t.Fatalf("Expected a single %s span, got %+v", tracing.SpanChat, spans)
+	}
+	if spans[0].Status.Code != codes.Error || !strings.Contains(spans[0].Status.Description, "backend failure") {
+		t.Errorf("Expected the span to record the backend error, got status %+v", spans[0].Status)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/docker/model-runner/pkg/inference/backends/mlx"
 	"github.com/docker/model-runner/pkg/inference/backends/sglang"
 	"github.com/docker/model-runner/pkg/inference/backends/vllm"
+	"github.com/docker/model-runner/pkg/inference/platform"
 )
 
 // mockPlatformSupport allows tests to control platform capability checks.
@@ -19,6 +20,8 @@ type mockPlatformSupport struct {
 	vllmMetal bool
 	sglang    bool
 	diffusers bool
+	gpus      []platform.GPU
+	gpuErr    error
 }
 
 func (m mockPlatformSupport) SupportsMLX() bool       { return m.mlx }
@@ -26,6 +29,9 @@ func (m mockPlatformSupport) SupportsVLLM() bool      { return m.vllm }
 func (m mockPlatformSupport) SupportsVLLMMetal() bool { return m.vllmMetal }
 func (m mockPlatformSupport) SupportsSGLang() bool    { return m.sglang }
 func (m mockPlatformSupport) SupportsDiffusers() bool { return m.diffusers }
+func (m mockPlatformSupport) GPUs() ([]platform.GPU, error) {
+	return m.gpus, m.gpuErr
+}
 
 // mockModel is a minimal Model implementation for testing.
 type mockModel struct {
@@ -289,3 +295,105 @@ func TestSelectBackendForModel(t *testing.T) {
 		})
 	}
 }
+
+func TestSupportsFormat(t *testing.T) {
+	t.Parallel()
+
+	llamacppBackend := &mockBackend{name: "llamacpp"}
+	vllmBackend := &mockBackend{name: vllm.Name}
+
+	tests := []struct {
+		name     string
+		backends map[string]inference.Backend
+		platform mockPlatformSupport
+		format   types.Format
+		expected bool
+	}{
+		{
+			name:     "GGUF-only supports GGUF",
+			backends: map[string]inference.Backend{"llamacpp": llamacppBackend},
+			format:   types.FormatGGUF,
+			expected: true,
+		},
+		{
+			name:     "GGUF-only does not support safetensors",
+			backends: map[string]inference.Backend{"llamacpp": llamacppBackend},
+			format:   types.FormatSafetensors,
+			expected: false,
+		},
+		{
+			name:     "vLLM-capable supports GGUF",
+			backends: map[string]inference.Backend{"llamacpp": llamacppBackend, vllm.Name: vllmBackend},
+			platform: mockPlatformSupport{vllm: true},
+			format:   types.FormatGGUF,
+			expected: true,
+		},
+		{
+			name:     "vLLM-capable supports safetensors",
+			backends: map[string]inference.Backend{"llamacpp": llamacppBackend, vllm.Name: vllmBackend},
+			platform: mockPlatformSupport{vllm: true},
+			format:   types.FormatSafetensors,
+			expected: true,
+		},
+		{
+			name:     "vLLM installed on an unsupported platform does not support safetensors",
+			backends: map[string]inference.Backend{"llamacpp": llamacppBackend, vllm.Name: vllmBackend},
+			format:   types.FormatSafetensors,
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newTestSchedulerWithPlatform(tt.backends, llamacppBackend, tt.platform)
+			if got := s.SupportsFormat(tt.format); got != tt.expected {
+				t.Errorf("SupportsFormat(%q) = %v, want %v", tt.format, got, tt.expected)
+			}
+		})
+	}
+}
+
+// archBackend is a mock backend reporting a fixed set of supported
+// architectures.
+type archBackend struct {
+	mockBackend
+	architectures []string
+}
+
+func (b *archBackend) SupportedArchitectures() []string {
+	return b.architectures
+}
+
+func TestSupportsArchitecture(t *testing.T) {
+	t.Parallel()
+
+	llamacppBackend := &archBackend{mockBackend: mockBackend{name: "llamacpp"}, architectures: []string{"llama", "qwen3"}}
+	vllmBackend := &mockBackend{name: vllm.Name}
+	backends := map[string]inference.Backend{"llamacpp": llamacppBackend, vllm.Name: vllmBackend}
+
+	tests := []struct {
+		name         string
+		format       types.Format
+		architecture string
+		expected     bool
+	}{
+		{name: "supported architecture", format: types.FormatGGUF, architecture: "qwen3", expected: true},
+		{name: "architecture matched case-insensitively", format: types.FormatGGUF, architecture: "Llama", expected: true},
+		{name: "unsupported architecture", format: types.FormatGGUF, architecture: "mamba", expected: false},
+		{name: "unknown architecture", format: types.FormatGGUF, architecture: "", expected: true},
+		{name: "backend without architecture list", format: types.FormatSafetensors, architecture: "mamba", expected: true},
+	}
+
+	s := newTestSchedulerWithPlatform(backends, llamacppBackend, mockPlatformSupport{vllm: true})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := s.SupportsArchitecture(tt.format, tt.architecture); got != tt.expected {
+				t.Errorf("SupportsArchitecture(%q, %q) = %v, want %v", tt.format, tt.architecture, got, tt.expected)
+			}
+		})
+	}
+}
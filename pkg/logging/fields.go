@@ -0,0 +1,48 @@
+package logging
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/docker/model-runner/pkg/internal/utils"
+)
+
+// Attribute keys shared by model pull and push logs, so that operators can
+// query every stage of the lifecycle by the same field names.
+const (
+	// KeyModel is the model reference being pulled or pushed.
+	KeyModel = "model"
+	// KeyDigest is the resolved manifest digest of the model.
+	KeyDigest = "digest"
+	// KeyLayers is the number of layers in the model.
+	KeyLayers = "layers"
+	// KeyTotalBytes is the combined size of the model's layers.
+	KeyTotalBytes = "total_bytes"
+	// KeyDuration is the time taken by the operation.
+	KeyDuration = "duration"
+)
+
+// Model returns the model reference attribute, sanitized for logging.
+func Model(reference string) slog.Attr {
+	return slog.String(KeyModel, utils.SanitizeForLog(reference, -1))
+}
+
+// Digest returns the resolved manifest digest attribute.
+func Digest(digest string) slog.Attr {
+	return slog.String(KeyDigest, digest)
+}
+
+// Layers returns the layer count attribute.
+func Layers(count int) slog.Attr {
+	return slog.Int(KeyLayers, count)
+}
+
+// TotalBytes returns the combined layer size attribute.
+func TotalBytes(size int64) slog.Attr {
+	return slog.Int64(KeyTotalBytes, size)
+}
+
+// Duration returns the attribute for the time elapsed since start.
+func Duration(start time.Time) slog.Attr {
+	return slog.Duration(KeyDuration, time.Since(start))
+}
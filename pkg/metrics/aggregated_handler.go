@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/docker/model-runner/pkg/logging"
+	"github.com/docker/model-runner/pkg/servicemetrics"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/prometheus/common/expfmt"
 	"github.com/prometheus/common/model"
@@ -20,6 +21,9 @@ import (
 type AggregatedMetricsHandler struct {
 	log       logging.Logger
 	scheduler SchedulerInterface
+	// service holds the daemon's own metrics, served alongside the runners'.
+	// It may be nil.
+	service *servicemetrics.Metrics
 }
 
 // NewAggregatedMetricsHandler creates a new aggregated metrics handler
@@ -30,6 +34,12 @@ func NewAggregatedMetricsHandler(log logging.Logger, scheduler SchedulerInterfac
 	}
 }
 
+// SetServiceMetrics sets the daemon's own metrics to serve alongside the
+// runners' metrics.
+func (h *AggregatedMetricsHandler) SetServiceMetrics(m *servicemetrics.Metrics) {
+	h.service = m
+}
+
 // ServeHTTP implements http.Handler for aggregated metrics
 func (h *AggregatedMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
@@ -37,8 +47,9 @@ func (h *AggregatedMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	serviceFamilies := h.service.Families()
 	runners := h.scheduler.GetAllActiveRunners()
-	if len(runners) == 0 {
+	if len(runners) == 0 && len(serviceFamilies) == 0 {
 		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprintf(w, "# No active runners\n")
@@ -47,6 +58,9 @@ func (h *AggregatedMetricsHandler) ServeHTTP(w http.ResponseWriter, r *http.Requ
 
 	// Collect and aggregate metrics from all runners
 	allFamilies := h.collectAndAggregateMetrics(r.Context(), runners)
+	for _, family := range serviceFamilies {
+		allFamilies[family.GetName()] = family
+	}
 
 	// Write aggregated response using Prometheus encoder
 	h.writeAggregatedMetrics(w, allFamilies)
@@ -0,0 +1,56 @@
+package metrics
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/servicemetrics"
+)
+
+// idleScheduler is a scheduler without active runners.
+type idleScheduler struct{}
+
+func (idleScheduler) GetRunningBackends(http.ResponseWriter, *http.Request) {}
+
+func (idleScheduler) GetLlamaCppSocket() (string, error) { return "", nil }
+
+func (idleScheduler) GetAllActiveRunners() []ActiveRunner { return nil }
+
+func TestAggregatedMetricsHandlerServiceMetrics(t *testing.T) {
+	handler := NewAggregatedMetricsHandler(slog.Default(), idleScheduler{})
+	get := func() string {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status code %d, got %d", http.StatusOK, w.Code)
+		}
+		return w.Body.String()
+	}
+
+	if body := get(); !strings.Contains(body, "# No active runners") {
+		t.Errorf("Expected no metrics without service metrics, got %q", body)
+	}
+
+	service := servicemetrics.New()
+	handler.SetServiceMetrics(service)
+	service.PullStarted()(nil)
+	service.AddDownloadedBytes(1024)
+	service.InferenceRequest("llama.cpp", "completion")
+	service.SetStoreSize(func() (int64, error) { return 2048, nil })
+
+	body := get()
+	for _, want := range []string{
+		servicemetrics.PullsTotal + `{result="success"} 1`,
+		servicemetrics.ActivePulls + " 0",
+		servicemetrics.DownloadedBytesTotal + " 1024",
+		servicemetrics.StoreSizeBytes + " 2048",
+		servicemetrics.InferenceRequestsTotal + `{backend="llama.cpp",mode="completion"} 1`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+}
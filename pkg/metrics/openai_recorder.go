@@ -103,6 +103,9 @@ type OpenAIRecorder struct {
 	modelManager *models.Manager       // for resolving model tags to IDs
 	m            sync.RWMutex
 
+	// stats accumulates token usage across all requests.
+	stats *UsageStats
+
 	// streaming
 	subscribers map[string]chan []ModelRecordsResponse
 	subMutex    sync.RWMutex
@@ -113,10 +116,17 @@ func NewOpenAIRecorder(log logging.Logger, modelManager *models.Manager) *OpenAI
 		log:          log,
 		modelManager: modelManager,
 		records:      make(map[string]*ModelData),
+		stats:        NewUsageStats(),
 		subscribers:  make(map[string]chan []ModelRecordsResponse),
 	}
 }
 
+// Stats returns the token usage accumulated from recorded responses. Unlike
+// records, usage is kept when a model's runner is evicted.
+func (r *OpenAIRecorder) Stats() *UsageStats {
+	return r.stats
+}
+
 // truncateMediaFields truncates large base64 media data in image_url.url and input_audio.data fields
 // to reduce memory usage while preserving request structure information.
 func (r *OpenAIRecorder) truncateMediaFields(requestBody []byte) []byte {
@@ -376,6 +386,12 @@ func (r *OpenAIRecorder) RecordResponse(id, model string, rw http.ResponseWriter
 		response = responseBody
 	}
 
+	var usage *TokenUsage
+	if streamingErr == nil && statusCode < 400 {
+		usage = parseTokenUsage(response)
+	}
+	r.stats.Record(model, usage)
+
 	r.m.Lock()
 	defer r.m.Unlock()
 
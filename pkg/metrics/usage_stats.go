@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// TokenUsage is the number of tokens consumed by one or more completions.
+type TokenUsage struct {
+	PromptTokens     int64 `json:"prompt_tokens"`
+	CompletionTokens int64 `json:"completion_tokens"`
+	TotalTokens      int64 `json:"total_tokens"`
+}
+
+// ModelUsageStats is the usage accumulated for a single model.
+type ModelUsageStats struct {
+	Model    string `json:"model"`
+	Requests int64  `json:"requests"`
+	TokenUsage
+}
+
+// UsageStatsResponse is the body returned by the stats endpoint.
+type UsageStatsResponse struct {
+	Models []ModelUsageStats `json:"models"`
+	Total  ModelUsageStats   `json:"total"`
+}
+
+// UsageStats accumulates token usage per model across inference requests.
+type UsageStats struct {
+	m      sync.Mutex
+	models map[string]*ModelUsageStats // key is the model as requested
+}
+
+// NewUsageStats returns an empty UsageStats.
+func NewUsageStats() *UsageStats {
+	return &UsageStats{models: make(map[string]*ModelUsageStats)}
+}
+
+// Record counts a request for model, adding usage to its totals if the
+// backend reported any.
+func (s *UsageStats) Record(model string, usage *TokenUsage) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	stats := s.models[model]
+	if stats == nil {
+		stats = &ModelUsageStats{Model: model}
+		s.models[model] = stats
+	}
+	stats.Requests++
+	if usage != nil {
+		stats.PromptTokens += usage.PromptTokens
+		stats.CompletionTokens += usage.CompletionTokens
+		stats.TotalTokens += usage.TotalTokens
+	}
+}
+
+// Snapshot returns the usage of every model, sorted by model, along with the
+// totals across all models.
+func (s *UsageStats) Snapshot() UsageStatsResponse {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	response := UsageStatsResponse{Models: make([]ModelUsageStats, 0, len(s.models))}
+	for _, stats := range s.models {
+		response.Models = append(response.Models, *stats)
+		response.Total.Requests += stats.Requests
+		response.Total.PromptTokens += stats.PromptTokens
+		response.Total.CompletionTokens += stats.CompletionTokens
+		response.Total.TotalTokens += stats.TotalTokens
+	}
+	slices.SortFunc(response.Models, func(a, b ModelUsageStats) int {
+		return strings.Compare(a.Model, b.Model)
+	})
+	return response
+}
+
+// Reset discards all accumulated usage.
+func (s *UsageStats) Reset() {
+	s.m.Lock()
+	defer s.m.Unlock()
+	s.models = make(map[string]*ModelUsageStats)
+}
+
+// GetStatsHandler returns a handler serving the accumulated usage as JSON.
+func (s *UsageStats) GetStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(s.Snapshot()); err != nil {
+			http.Error(w, "Failed to encode stats", http.StatusInternalServerError)
+		}
+	}
+}
+
+// ResetStatsHandler returns a handler that discards the accumulated usage.
+func (s *UsageStats) ResetStatsHandler() http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		s.Reset()
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// parseTokenUsage extracts the token usage from a completion response body,
+// accepting both the OpenAI (prompt/completion tokens) and Anthropic
+// (input/output tokens) field names. It returns nil if the response reports
+// no usage.
+func parseTokenUsage(response string) *TokenUsage {
+	var body struct {
+		Usage *struct {
+			PromptTokens     int64 `json:"prompt_tokens"`
+			CompletionTokens int64 `json:"completion_tokens"`
+			TotalTokens      int64 `json:"total_tokens"`
+			InputTokens      int64 `json:"input_tokens"`
+			OutputTokens     int64 `json:"output_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.Unmarshal([]byte(response), &body); err != nil || body.Usage == nil {
+		return nil
+	}
+	usage := &TokenUsage{
+		PromptTokens:     body.Usage.PromptTokens + body.Usage.InputTokens,
+		CompletionTokens: body.Usage.CompletionTokens + body.Usage.OutputTokens,
+		TotalTokens:      body.Usage.TotalTokens,
+	}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+	return usage
+}
@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/models"
+)
+
+func TestUsageStatsAggregatesCompletions(t *testing.T) {
+	logger := slog.Default()
+	modelManager := models.NewManager(logger, models.ClientConfig{
+		StoreRootPath: t.TempDir(),
+		Logger:        logger,
+	})
+	recorder := NewOpenAIRecorder(logger, modelManager)
+
+	completions := []struct {
+		model      string
+		origin     string
+		statusCode int
+		response   string
+	}{
+		{
+			model:      "ai/smollm2",
+			statusCode: http.StatusOK,
+			response:   `{"object":"chat.completion","usage":{"prompt_tokens":10,"completion_tokens":5,"total_tokens":15}}`,
+		},
+		{
+			model:      "ai/smollm2",
+			statusCode: http.StatusOK,
+			response: "data: {\"choices\":[{\"delta\":{\"content\":\"Hi\"}}]}\n\n" +
+				"data: {\"choices\":[{\"delta\":{},\"finish_reason\":\"stop\"}],\"usage\":{\"prompt_tokens\":7,\"completion_tokens\":3,\"total_tokens\":10}}\n\n" +
+				"data: [DONE]\n\n",
+		},
+		{
+			model:      "ai/smollm2",
+			statusCode: http.StatusInternalServerError,
+			response:   `{"error":"backend failure","usage":{"prompt_tokens":100,"completion_tokens":100,"total_tokens":200}}`,
+		},
+		{
+			model:      "ai/qwen3",
+			origin:     inference.OriginAnthropicMessages,
+			statusCode: http.StatusOK,
+			response:   `{"type":"message","usage":{"input_tokens":4,"output_tokens":6}}`,
+		},
+	}
+
+	for _, c := range completions {
+		req := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/v1/chat/completions", http.NoBody)
+		if c.origin != "" {
+			req.Header.Set(inference.RequestOriginHeader, c.origin)
+		}
+		id := recorder.RecordRequest(c.model, req, []byte(`{"model":"`+c.model+`"}`))
+		w := recorder.NewResponseRecorder(httptest.NewRecorder())
+		w.WriteHeader(c.statusCode)
+		if _, err := w.Write([]byte(c.response)); err != nil {
+			t.Fatalf("Failed to write response: %v", err)
+		}
+		recorder.RecordResponse(id, c.model, w)
+	}
+
+	rec := httptest.NewRecorder()
+	recorder.Stats().GetStatsHandler()(rec, httptest.NewRequest(http.MethodGet, inference.InferencePrefix+"/stats", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	var stats UsageStatsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&stats); err != nil {
+		t.Fatalf("Failed to decode stats: %v", err)
+	}
+
+	expected := UsageStatsResponse{
+		Models: []ModelUsageStats{
+			{Model: "ai/qwen3", Requests: 1, TokenUsage: TokenUsage{PromptTokens: 4, CompletionTokens: 6, TotalTokens: 10}},
+			{Model: "ai/smollm2", Requests: 3, TokenUsage: TokenUsage{PromptTokens: 17, CompletionTokens: 8, TotalTokens: 25}},
+		},
+		Total: ModelUsageStats{Requests: 4, TokenUsage: TokenUsage{PromptTokens: 21, CompletionTokens: 14, TotalTokens: 35}},
+	}
+	if len(stats.Models) != len(expected.Models) {
+		t.Fatalf("Expected %d models, got %d: %+v", len(expected.Models), len(stats.Models), stats.Models)
+	}
+	for i := range expected.Models {
+		if stats.Models[i] != expected.Models[i] {
+			t.Errorf("Model %d: expected %+v, got %+v", i, expected.Models[i], stats.Models[i])
+		}
+	}
+	if stats.Total != expected.Total {
+		t.Errorf("Total: expected %+v, got %+v", expected.Total, stats.Total)
+	}
+
+	rec = httptest.NewRecorder()
+	recorder.Stats().ResetStatsHandler()(rec, httptest.NewRequest(http.MethodDelete, inference.InferencePrefix+"/stats", http.NoBody))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 on reset, got %d", rec.Code)
+	}
+	if after := recorder.Stats().Snapshot(); len(after.Models) != 0 || after.Total != (ModelUsageStats{}) {
+		t.Errorf("Expected empty stats after reset, got %+v", after)
+	}
+}
+
+func TestParseTokenUsage(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected *TokenUsage
+	}{
+		{
+			name:     "OpenAI usage",
+			response: `{"usage":{"prompt_tokens":3,"completion_tokens":2,"total_tokens":5}}`,
+			expected: &TokenUsage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+		{
+			name:     "Anthropic usage without total",
+			response: `{"usage":{"input_tokens":3,"output_tokens":2}}`,
+			expected: &TokenUsage{PromptTokens: 3, CompletionTokens: 2, TotalTokens: 5},
+		},
+		{
+			name:     "No usage",
+			response: `{"object":"chat.completion"}`,
+		},
+		{
+			name:     "Not JSON",
+			response: strings.Repeat("x", 10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			usage := parseTokenUsage(tt.response)
+			if tt.expected == nil {
+				if usage != nil {
+					t.Errorf("Expected no usage, got %+v", usage)
+				}
+				return
+			}
+			if usage == nil || *usage != *tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, usage)
+			}
+		})
+	}
+}
@@ -10,6 +10,10 @@ import (
 // If allowedOrigins is nil or empty, it falls back to envconfig.AllowedOrigins().
 // This middleware intercepts OPTIONS requests only if the Origin header is present and valid,
 // otherwise passing the request to the router (allowing 405/404 responses as appropriate).
+//
+// The ResponseWriter is passed to next unwrapped, so streaming handlers (model
+// pulls and pushes) can still type-assert it to http.Flusher and http.Hijacker.
+// Any future wrapping must preserve both interfaces.
 func CorsMiddleware(allowedOrigins []string, next http.Handler) http.Handler {
 	if len(allowedOrigins) == 0 {
 		allowedOrigins = envconfig.AllowedOrigins()
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -116,3 +118,88 @@ func TestOriginAllowed(t *testing.T) {
 		t.Errorf("expected originAllowed to return false")
 	}
 }
+
+// TestCorsMiddlewarePreservesFlusher guards the streaming contract the model
+// pull and push handlers depend on: they fail with "streaming not supported"
+// if the writer they receive cannot be asserted to http.Flusher.
+func TestCorsMiddlewarePreservesFlusher(t *testing.T) {
+	t.Parallel()
+
+	var isFlusher bool
+	handler := CorsMiddleware([]string{"*"}, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var flusher http.Flusher
+		flusher, isFlusher = w.(http.Flusher)
+		if isFlusher {
+			_, _ = w.Write([]byte("chunk"))
+			flusher.Flush()
+		}
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", http.NoBody)
+	req.Header.Set("Origin", "http://example.com")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if !isFlusher {
+		t.Fatal("expected response writer behind CorsMiddleware to implement http.Flusher")
+	}
+	if !rec.Flushed {
+		t.Error("expected response to be flushed")
+	}
+}
+
+func TestCorsMiddlewarePreservesHijacker(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		wrap func(http.Handler) http.Handler
+	}{
+		{
+			name: "Cors",
+			wrap: func(h http.Handler) http.Handler { return CorsMiddleware([]string{"*"}, h) },
+		},
+		{
+			name: "LoggingAndCors",
+			wrap: func(h http.Handler) http.Handler {
+				return LoggingMiddleware(slog.New(slog.DiscardHandler), CorsMiddleware([]string{"*"}, h))
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(tt.wrap(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				hijacker, ok := w.(http.Hijacker)
+				if !ok {
+					http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+					return
+				}
+				conn, buf, err := hijacker.Hijack()
+				if err != nil {
+					t.Errorf("Hijack() error = %v", err)
+					return
+				}
+				defer conn.Close()
+				_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+				_ = buf.Flush()
+			})))
+			defer server.Close()
+
+			resp, err := server.Client().Get(server.URL)
+			if err != nil {
+				t.Fatalf("GET error = %v", err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatalf("reading body: %v", err)
+			}
+			if resp.StatusCode != http.StatusOK || string(body) != "hijacked" {
+				t.Errorf("got status %d body %q, want 200 %q", resp.StatusCode, body, "hijacked")
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package middleware
+
+import (
+	"bytes"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLoggingMiddlewareLogsStatus(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := LoggingMiddleware(log, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+		_, _ = w.Write([]byte("short and stout"))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/models/foo", http.NoBody)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusTeapot)
+	}
+	line := buf.String()
+	for _, want := range []string{"method=GET", "path=/models/foo", "status=418", "bytes=15", "duration="} {
+		if !strings.Contains(line, want) {
+			t.Errorf("log line %q does not contain %q", line, want)
+		}
+	}
+}
+
+func TestLoggingMiddlewareDefaultStatus(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+
+	handler := LoggingMiddleware(log, http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", http.NoBody))
+
+	if !strings.Contains(buf.String(), "status=200") {
+		t.Errorf("log line %q does not contain status=200", buf.String())
+	}
+}
+
+func TestLoggingMiddlewareStreaming(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(slog.NewTextHandler(&buf, nil))
+
+	rec := httptest.NewRecorder()
+	handler := LoggingMiddleware(log, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			t.Fatal("response writer does not implement http.Flusher")
+		}
+		_, _ = w.Write([]byte("chunk 1\n"))
+		flusher.Flush()
+		// The first chunk must have reached the client before the handler returns.
+		if !rec.Flushed {
+			t.Error("expected response to be flushed")
+		}
+		if got := rec.Body.String(); got != "chunk 1\n" {
+			t.Errorf("body after flush = %q, want %q", got, "chunk 1\n")
+		}
+		_, _ = w.Write([]byte("chunk 2\n"))
+	}))
+
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/models/create", http.NoBody))
+
+	if got := rec.Body.String(); got != "chunk 1\nchunk 2\n" {
+		t.Errorf("body = %q, want %q", got, "chunk 1\nchunk 2\n")
+	}
+	if !strings.Contains(buf.String(), "status=200") {
+		t.Errorf("log line %q does not contain status=200", buf.String())
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/model-runner/pkg/distribution/oci"
@@ -31,6 +32,7 @@ const (
 type HTTPHandler struct {
 	log           logging.Logger
 	router        *http.ServeMux
+	lock          sync.RWMutex
 	httpHandler   http.Handler
 	modelManager  *models.Manager
 	scheduler     *scheduling.Scheduler
@@ -52,8 +54,7 @@ func NewHTTPHandler(log logging.Logger, scheduler *scheduling.Scheduler, schedul
 		h.router.HandleFunc(route, handler)
 	}
 
-	// Apply CORS middleware
-	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
+	h.RebuildRoutes(allowedOrigins)
 
 	return h
 }
@@ -63,7 +64,17 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	safeMethod := utils.SanitizeForLog(r.Method, -1)
 	safePath := utils.SanitizeForLog(r.URL.Path, -1)
 	h.log.Info("Ollama API request", "method", safeMethod, "path", safePath)
-	h.httpHandler.ServeHTTP(w, r)
+	h.lock.RLock()
+	handler := h.httpHandler
+	h.lock.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// RebuildRoutes replaces the allowed origins of the CORS middleware.
+func (h *HTTPHandler) RebuildRoutes(allowedOrigins []string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
 }
 
 // routeHandlers returns the mapping of routes to their handlers
@@ -322,7 +333,7 @@ func (h *HTTPHandler) handleShowModel(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Get model details
-	model, err := h.modelManager.GetLocal(modelName)
+	model, err := h.modelManager.ResolveLocal(modelName)
 	if err != nil {
 		h.log.Error("Failed to get model", "error", err)
 		http.Error(w, fmt.Sprintf("Model not found: %v", err), http.StatusNotFound)
@@ -658,7 +669,7 @@ func (h *HTTPHandler) handlePull(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Call the model manager's Pull method with the wrapped writer
-	if err := h.modelManager.Pull(modelName, "", r, ollamaWriter); err != nil {
+	if err := h.modelManager.Pull(models.ModelCreateRequest{From: modelName}, r, ollamaWriter); err != nil {
 		h.log.Error("Failed to pull model", "error", utils.SanitizeForLog(err.Error(), -1))
 
 		// Send error in Ollama JSON format
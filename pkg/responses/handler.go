@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/docker/model-runner/pkg/logging"
@@ -18,6 +19,7 @@ import (
 type HTTPHandler struct {
 	log                 logging.Logger
 	router              *http.ServeMux
+	lock                sync.RWMutex
 	httpHandler         http.Handler
 	schedulerHTTP       http.Handler
 	store               *Store
@@ -45,8 +47,7 @@ func NewHTTPHandler(log logging.Logger, schedulerHTTP http.Handler, allowedOrigi
 	h.router.HandleFunc("GET /v1"+APIPrefix+"/{id}/input_items", h.handleListInputItems)
 	h.router.HandleFunc("DELETE /v1"+APIPrefix+"/{id}", h.handleDelete)
 
-	// Apply CORS middleware
-	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
+	h.RebuildRoutes(allowedOrigins)
 
 	return h
 }
@@ -62,7 +63,17 @@ func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	cleanPath := strings.ReplaceAll(r.URL.Path, "\n", "")
 	cleanPath = strings.ReplaceAll(cleanPath, "\r", "")
 	h.log.Info("Responses API request", "method", r.Method, "path", cleanPath)
-	h.httpHandler.ServeHTTP(w, r)
+	h.lock.RLock()
+	handler := h.httpHandler
+	h.lock.RUnlock()
+	handler.ServeHTTP(w, r)
+}
+
+// RebuildRoutes replaces the allowed origins of the CORS middleware.
+func (h *HTTPHandler) RebuildRoutes(allowedOrigins []string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.httpHandler = middleware.CorsMiddleware(allowedOrigins, h.router)
 }
 
 // handleCreate handles POST /responses (or /v1/responses).
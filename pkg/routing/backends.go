@@ -9,6 +9,7 @@ import (
 	"github.com/docker/model-runner/pkg/inference/config"
 	"github.com/docker/model-runner/pkg/inference/models"
 	"github.com/docker/model-runner/pkg/logging"
+	"github.com/docker/model-runner/pkg/sandbox"
 )
 
 // BackendsConfig configures which inference backends to create and how.
@@ -32,6 +33,9 @@ type BackendsConfig struct {
 	IncludeVLLM   bool
 	VLLMPath      string
 	VLLMMetalPath string
+	// VLLMSandboxPolicy replaces the default sandbox policy of vLLM
+	// processes. It may be nil.
+	VLLMSandboxPolicy *sandbox.Policy
 
 	IncludeDiffusers bool
 	DiffusersPath    string
@@ -68,6 +72,7 @@ func DefaultBackendDefs(cfg BackendsConfig) []BackendDef {
 				return vllm.New(cfg.Log, mm, sl(vllm.Name), vllm.Options{
 					LinuxBinaryPath: cfg.VLLMPath,
 					MetalPythonPath: cfg.VLLMMetalPath,
+					SandboxPolicy:   cfg.VLLMSandboxPolicy,
 				})
 			},
 		})
@@ -0,0 +1,66 @@
+package routing
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/docker/model-runner/pkg/logging"
+)
+
+// maxCORSRequestSize bounds the size of the allowed origins update request
+// body.
+const maxCORSRequestSize = 1 << 20
+
+// OriginsRebuilder is implemented by handlers whose CORS origins can be
+// updated at runtime.
+type OriginsRebuilder interface {
+	RebuildRoutes(allowedOrigins []string)
+}
+
+// CORSRequest is the body of an allowed origins update. An empty list restores
+// the defaults from DMR_ORIGINS.
+type CORSRequest struct {
+	Origins []string `json:"origins"`
+}
+
+// NewCORSHandler returns a handler that replaces the allowed origins of the
+// given handlers with the origins in the request body. Each handler swaps its
+// routes under its own lock, so requests in flight finish with the old
+// origins.
+//
+// Requests carrying an Origin header are rejected: browsers send one on every
+// cross-origin request, and no web page, allowed or not, may change which
+// pages can reach the API. Operators and GUIs call the endpoint from outside
+// a browser, e.g. over the Unix socket.
+func NewCORSHandler(log logging.Logger, handlers ...OriginsRebuilder) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Origin") != "" {
+			http.Error(w, "allowed origins cannot be updated from a browser", http.StatusForbidden)
+			return
+		}
+
+		var request CORSRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxCORSRequestSize)).Decode(&request); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		origins := make([]string, 0, len(request.Origins))
+		for _, origin := range request.Origins {
+			if trimmed := strings.TrimSpace(origin); trimmed != "" {
+				origins = append(origins, trimmed)
+			}
+		}
+
+		for _, h := range handlers {
+			h.RebuildRoutes(origins)
+		}
+		log.Info("Updated allowed origins", "origins", origins)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(CORSRequest{Origins: origins}); err != nil {
+			log.Warn("failed to write allowed origins response", "error", err)
+		}
+	}
+}
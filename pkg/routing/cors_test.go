@@ -0,0 +1,116 @@
+package routing
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/backends/llamacpp"
+	"github.com/docker/model-runner/pkg/inference/models"
+)
+
+func TestCORSHandler(t *testing.T) {
+	log := slog.Default()
+	manager := models.NewManager(log, models.ClientConfig{StoreRootPath: t.TempDir(), Logger: log})
+	modelHandler := models.NewHTTPHandler(log, manager, []string{"http://old.example"})
+	handler := NewCORSHandler(log, modelHandler)
+
+	preflight := func(origin string) int {
+		r := httptest.NewRequest(http.MethodOptions, inference.ModelsPrefix, http.NoBody)
+		r.Header.Set("Origin", origin)
+		w := httptest.NewRecorder()
+		modelHandler.ServeHTTP(w, r)
+		return w.Code
+	}
+	update := func(body, origin string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/admin/cors", strings.NewReader(body))
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	if code := preflight("http://new.example"); code != http.StatusForbidden {
+		t.Fatalf("Expected preflight from new origin to be rejected before the update, got %d", code)
+	}
+
+	w := update(`{"origins": ["http://new.example", " "]}`, "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+	var response CORSRequest
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(response.Origins) != 1 || response.Origins[0] != "http://new.example" {
+		t.Errorf("Expected applied origins [http://new.example], got %v", response.Origins)
+	}
+
+	if code := preflight("http://new.example"); code != http.StatusNoContent {
+		t.Errorf("Expected preflight from new origin to succeed after the update, got %d", code)
+	}
+	if code := preflight("http://old.example"); code != http.StatusForbidden {
+		t.Errorf("Expected preflight from old origin to be rejected after the update, got %d", code)
+	}
+
+	if w := update(`{"origins": ["http://evil.example"]}`, "http://new.example"); w.Code != http.StatusForbidden {
+		t.Errorf("Expected update from a browser to be rejected, got %d", w.Code)
+	}
+	if code := preflight("http://evil.example"); code != http.StatusForbidden {
+		t.Errorf("Expected rejected update to leave origins unchanged, got %d", code)
+	}
+
+	if w := update(`{"origins": "http://evil.example"}`, ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected malformed update to be rejected, got %d", w.Code)
+	}
+}
+
+func TestServiceCORSUpdateReachesCompatibilityLayers(t *testing.T) {
+	log := slog.Default()
+	svc, err := NewService(ServiceConfig{
+		Log:            log,
+		ClientConfig:   models.ClientConfig{StoreRootPath: t.TempDir(), Logger: log},
+		AllowedOrigins: []string{"http://old.example"},
+		Backends: DefaultBackendDefs(BackendsConfig{
+			Log:                  log,
+			LlamaCppVendoredPath: t.TempDir(),
+			LlamaCppUpdatedPath:  t.TempDir(),
+		}),
+		DefaultBackendName:  llamacpp.Name,
+		IncludeResponsesAPI: true,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	r := httptest.NewRequest(http.MethodPost, inference.InferencePrefix+"/admin/cors", strings.NewReader(`{"origins": ["http://new.example"]}`))
+	w := httptest.NewRecorder()
+	svc.Router.ServeHTTP(w, r)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	for _, path := range []string{"/api/tags", "/anthropic/v1/messages", "/v1/responses"} {
+		t.Run(path, func(t *testing.T) {
+			preflight := func(origin string) int {
+				r := httptest.NewRequest(http.MethodOptions, path, http.NoBody)
+				r.Header.Set("Origin", origin)
+				w := httptest.NewRecorder()
+				svc.Router.ServeHTTP(w, r)
+				return w.Code
+			}
+			if code := preflight("http://new.example"); code != http.StatusNoContent {
+				t.Errorf("Expected preflight from new origin to succeed after the update, got %d", code)
+			}
+			if code := preflight("http://old.example"); code != http.StatusForbidden {
+				t.Errorf("Expected preflight from old origin to be rejected after the update, got %d", code)
+			}
+		})
+	}
+}
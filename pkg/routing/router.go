@@ -22,8 +22,8 @@ type RouterConfig struct {
 	ModelHandler  *models.HTTPHandler
 	ModelManager  *models.Manager
 
-	// AllowedOrigins is forwarded to the Ollama and Anthropic handlers
-	// for CORS support. It may be nil.
+	// AllowedOrigins is forwarded to the Ollama, Anthropic and Responses
+	// handlers for CORS support. It may be nil.
 	AllowedOrigins []string
 
 	// ModelHandlerMiddleware optionally wraps the model handler before
@@ -42,6 +42,13 @@ type RouterConfig struct {
 // path aliases (/v1/, /rerank, /score), Ollama compatibility, and
 // Anthropic compatibility.
 func NewRouter(cfg RouterConfig) *NormalizedServeMux {
+	router, _ := newRouter(cfg)
+	return router
+}
+
+// newRouter builds the router of NewRouter and also returns the compatibility
+// layers, so that their allowed origins can be updated at runtime.
+func newRouter(cfg RouterConfig) (*NormalizedServeMux, []OriginsRebuilder) {
 	router := NewNormalizedServeMux()
 
 	// Models endpoints – optionally wrapped by middleware.
@@ -68,6 +75,7 @@ func NewRouter(cfg RouterConfig) *NormalizedServeMux {
 	// Anthropic Messages API compatibility layer.
 	anthropicHandler := anthropic.NewHandler(cfg.Log, cfg.SchedulerHTTP, cfg.AllowedOrigins, cfg.ModelManager)
 	router.Handle(anthropic.APIPrefix+"/", anthropicHandler)
+	compatHandlers := []OriginsRebuilder{ollamaHandler, anthropicHandler}
 
 	// OpenAI Responses API compatibility layer.
 	if cfg.IncludeResponsesAPI {
@@ -78,7 +86,8 @@ func NewRouter(cfg RouterConfig) *NormalizedServeMux {
 		router.Handle("/v1"+responses.APIPrefix, responsesHandler)
 		router.Handle(inference.InferencePrefix+responses.APIPrefix+"/", responsesHandler)
 		router.Handle(inference.InferencePrefix+responses.APIPrefix, responsesHandler)
+		compatHandlers = append(compatHandlers, responsesHandler)
 	}
 
-	return router
+	return router, compatHandlers
 }
@@ -48,6 +48,10 @@ type ServiceConfig struct {
 	// MetricsTracker tracks inference metrics.
 	MetricsTracker *metrics.Tracker
 
+	// InflightLimit bounds the number of concurrent chat completion
+	// requests per backend. The zero value means no limit.
+	InflightLimit scheduling.InflightLimit
+
 	// AllowedOrigins is forwarded to model, scheduler, Ollama, and
 	// Anthropic handlers for CORS support. It may be nil.
 	AllowedOrigins []string
@@ -98,7 +102,12 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 		cfg.MetricsTracker,
 		deferredBackends,
 	)
+	scheduler.SetInflightLimit(cfg.InflightLimit)
+	scheduler.SetMetrics(cfg.ClientConfig.Metrics)
 
+	modelManager.SetBackendUnloader(scheduler.UnloadAll)
+	modelHandler.SetFormatSupport(scheduler.SupportsFormat)
+	modelHandler.SetArchitectureSupport(scheduler.SupportsArchitecture)
 	schedulerHTTP := scheduling.NewHTTPHandler(scheduler, modelHandler, cfg.AllowedOrigins)
 
 	svc := &Service{
@@ -109,7 +118,7 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 		Backends:      backends,
 	}
 
-	svc.Router = NewRouter(RouterConfig{
+	router, compatHandlers := newRouter(RouterConfig{
 		Log:                    cfg.Log,
 		Scheduler:              scheduler,
 		SchedulerHTTP:          schedulerHTTP,
@@ -120,6 +129,12 @@ func NewService(cfg ServiceConfig) (*Service, error) {
 		IncludeResponsesAPI:    cfg.IncludeResponsesAPI,
 	})
 
+	svc.Router = router
+
+	// Runtime CORS updates, applied to every handler that checks origins.
+	rebuilders := append([]OriginsRebuilder{modelHandler, schedulerHTTP}, compatHandlers...)
+	svc.Router.HandleFunc("POST "+inference.InferencePrefix+"/admin/cors", NewCORSHandler(cfg.Log, rebuilders...))
+
 	if cfg.ExtraRoutes != nil {
 		cfg.ExtraRoutes(svc.Router, svc)
 	}
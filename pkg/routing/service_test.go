@@ -0,0 +1,122 @@
+package routing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/docker/model-runner/pkg/distribution/builder"
+	reg "github.com/docker/model-runner/pkg/distribution/registry"
+	"github.com/docker/model-runner/pkg/distribution/registry/testregistry"
+	"github.com/docker/model-runner/pkg/inference"
+	"github.com/docker/model-runner/pkg/inference/backends/llamacpp"
+	"github.com/docker/model-runner/pkg/inference/models"
+)
+
+// writeGGUFWithArchitecture writes a copy of the dummy GGUF model that records
+// architecture as its general.architecture.
+func writeGGUFWithArchitecture(t *testing.T, architecture string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "assets", "dummy.gguf"))
+	if err != nil {
+		t.Fatalf("Failed to read dummy model: %v", err)
+	}
+	// The key-value pairs follow the magic, the version, the tensor count
+	// and the key-value count.
+	const headerLen = 4 + 4 + 8 + 8
+	var kv bytes.Buffer
+	writeString := func(s string) {
+		_ = binary.Write(&kv, binary.LittleEndian, uint64(len(s)))
+		kv.WriteString(s)
+	}
+	writeString("general.architecture")
+	_ = binary.Write(&kv, binary.LittleEndian, uint32(8)) // string value
+	writeString(architecture)
+	// Keep the tensor data aligned as it was.
+	if kv.Len()%32 != 0 {
+		t.Fatalf("Architecture %q must make the key-value pair a multiple of 32 bytes, got %d", architecture, kv.Len())
+	}
+
+	out := append([]byte{}, data[:headerLen]...)
+	binary.LittleEndian.PutUint64(out[16:], binary.LittleEndian.Uint64(data[16:])+1)
+	out = append(out, kv.Bytes()...)
+	out = append(out, data[headerLen:]...)
+	path := filepath.Join(t.TempDir(), "model.gguf")
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		t.Fatalf("Failed to write model: %v", err)
+	}
+	return path
+}
+
+func TestServicePreflightRejectsUnsupportedArchitecture(t *testing.T) {
+	server := httptest.NewServer(testregistry.New())
+	defer server.Close()
+	uri, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("Failed to parse registry URL: %v", err)
+	}
+
+	push := func(path, tag string) {
+		model, err := builder.FromPath(path)
+		if err != nil {
+			t.Fatalf("Failed to create model builder: %v", err)
+		}
+		target, err := reg.NewClient(reg.WithPlainHTTP(true)).NewTarget(tag)
+		if err != nil {
+			t.Fatalf("Failed to create model target: %v", err)
+		}
+		if err := model.Build(t.Context(), target, nil); err != nil {
+			t.Fatalf("Failed to push model: %v", err)
+		}
+	}
+	supported := uri.Host + "/ai/supported:latest"
+	push(filepath.Join("..", "..", "assets", "dummy.gguf"), supported)
+	unsupported := uri.Host + "/ai/unsupported:latest"
+	push(writeGGUFWithArchitecture(t, "unsupported-architecture"), unsupported)
+
+	log := slog.Default()
+	svc, err := NewService(ServiceConfig{
+		Log: log,
+		ClientConfig: models.ClientConfig{
+			StoreRootPath:  t.TempDir(),
+			Logger:         log,
+			PlainHTTP:      true,
+			PreflightPulls: true,
+		},
+		Backends: DefaultBackendDefs(BackendsConfig{
+			Log:                  log,
+			LlamaCppVendoredPath: t.TempDir(),
+			LlamaCppUpdatedPath:  t.TempDir(),
+		}),
+		DefaultBackendName: llamacpp.Name,
+	})
+	if err != nil {
+		t.Fatalf("Failed to create service: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		tag      string
+		wantCode int
+	}{
+		{name: "supported", tag: supported, wantCode: http.StatusOK},
+		{name: "unsupported", tag: unsupported, wantCode: http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, inference.ModelsPrefix+"/create", strings.NewReader(`{"from": "`+tt.tag+`"}`))
+			w := httptest.NewRecorder()
+			svc.Router.ServeHTTP(w, r)
+			if w.Code != tt.wantCode {
+				t.Fatalf("Expected status code %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package sandbox
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// ErrPolicyNotEnforced is returned when parsing a policy on a platform that
+// can't enforce it, so that a policy meant to confine processes is not
+// silently ignored.
+var ErrPolicyNotEnforced = errors.New("sandbox policies are not enforced on this platform")
+
+// Policy describes what a sandboxed process may access, independently of the
+// platform's configuration language. Backends supply a default policy, which
+// operators may replace, and Configuration renders it for the platform.
+type Policy struct {
+	// ReadPaths are the directories and files the process may read, in
+	// addition to the system directories.
+	ReadPaths []string `json:"read_paths,omitempty"`
+	// WritePaths are the directories and files the process may read and
+	// write.
+	WritePaths []string `json:"write_paths,omitempty"`
+	// Sockets are the Unix sockets the process may listen on.
+	Sockets []string `json:"sockets,omitempty"`
+	// Network allows network access beyond Sockets.
+	Network bool `json:"network,omitempty"`
+}
+
+// ParsePolicy parses a policy from its JSON encoding, returning nil if s is
+// empty. It returns ErrPolicyNotEnforced on platforms where Configuration
+// does not render the whole policy.
+func ParsePolicy(s string) (*Policy, error) {
+	if strings.TrimSpace(s) == "" {
+		return nil, nil
+	}
+	policy, err := parsePolicy(s)
+	if err != nil {
+		return nil, err
+	}
+	if !policyEnforced(*policy) {
+		return nil, fmt.Errorf("%w: %s", ErrPolicyNotEnforced, runtime.GOOS)
+	}
+	return policy, nil
+}
+
+// parsePolicy parses a policy from its JSON encoding.
+func parsePolicy(s string) (*Policy, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.DisallowUnknownFields()
+	var policy Policy
+	if err := decoder.Decode(&policy); err != nil {
+		return nil, fmt.Errorf("invalid sandbox policy: %w", err)
+	}
+	return &policy, nil
+}
+
+// profile renders the policy as a sandbox-exec profile. Like
+// ConfigurationLlamaCpp, it keeps a default allow policy for the process
+// machinery and denies what processes could be exploited through, but file
+// access is denied outside of the system directories and the policy's paths.
+func (p Policy) profile() string {
+	var b bytes.Buffer
+	b.WriteString(`(version 1)
+(allow default)
+`)
+	if !p.Network {
+		b.WriteString("(deny network*)\n")
+		if len(p.Sockets) > 0 {
+			b.WriteString("(allow network-bind network-inbound")
+			writeFilters(&b, "literal", p.Sockets)
+			b.WriteString(")\n")
+		}
+	}
+	b.WriteString(`(deny device*)
+(deny nvram*)
+(deny system*)
+(deny job-creation)
+(deny mach-lookup
+    (global-name "com.apple.launchservicesd")
+    (global-name "com.apple.coreservices.launchservicesd"))
+(deny dynamic-code-generation)
+(deny user-preference*)
+(deny file-read* file-write* file-map-executable)
+(allow file-read* file-map-executable
+    (literal "/")
+    (subpath "/usr")
+    (subpath "/System")
+    (subpath "/Library/Apple")
+    (subpath "/private/var/db/dyld")
+    (subpath "/dev"))
+(allow file-write*
+    (literal "/dev/null"))
+`)
+	if paths := append(append([]string{}, p.ReadPaths...), p.WritePaths...); len(paths) > 0 {
+		b.WriteString("(allow file-read* file-map-executable")
+		writeFilters(&b, "subpath", paths)
+		b.WriteString(")\n")
+	}
+	if len(p.WritePaths) > 0 {
+		b.WriteString("(allow file-write*")
+		writeFilters(&b, "subpath", p.WritePaths)
+		b.WriteString(")\n")
+	}
+	return b.String()
+}
+
+// writeFilters writes a sandbox-exec filter of the given kind for each path.
+func writeFilters(b *bytes.Buffer, kind string, paths []string) {
+	for _, path := range paths {
+		fmt.Fprintf(b, "\n    (%s %s)", kind, quoteProfileString(path))
+	}
+}
+
+// quoteProfileString quotes s as a sandbox-exec profile string.
+func quoteProfileString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,71 @@
+package sandbox
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPolicyProfile(t *testing.T) {
+	policy := Policy{
+		ReadPaths:  []string{"/opt/vllm-env", "/models/bundle"},
+		WritePaths: []string{"/tmp"},
+		Sockets:    []string{"/run/inference-runner-0.sock"},
+	}
+	profile := policy.profile()
+
+	for _, want := range []string{
+		"(deny network*)",
+		"(allow network-bind network-inbound\n    (literal \"/run/inference-runner-0.sock\"))",
+		"(deny file-read* file-write* file-map-executable)",
+		"(allow file-read* file-map-executable\n    (subpath \"/opt/vllm-env\")\n    (subpath \"/models/bundle\")\n    (subpath \"/tmp\"))",
+		"(allow file-write*\n    (subpath \"/tmp\"))",
+	} {
+		if !strings.Contains(profile, want) {
+			t.Errorf("Expected profile to contain %q, got:\n%s", want, profile)
+		}
+	}
+	for _, path := range policy.ReadPaths {
+		if strings.Contains(profile, "(allow file-write*\n    (subpath \""+path+"\")") {
+			t.Errorf("Expected %s not to be writable, got:\n%s", path, profile)
+		}
+	}
+
+	policy.Network = true
+	if profile := policy.profile(); strings.Contains(profile, "network") {
+		t.Errorf("Expected profile with network access not to restrict the network, got:\n%s", profile)
+	}
+
+	quoted := Policy{ReadPaths: []string{`/odd "dir"\`}}.profile()
+	if want := `(subpath "/odd \"dir\"\\")`; !strings.Contains(quoted, want) {
+		t.Errorf("Expected profile to contain %q, got:\n%s", want, quoted)
+	}
+}
+
+func TestParsePolicy(t *testing.T) {
+	if policy, err := ParsePolicy(" "); err != nil || policy != nil {
+		t.Errorf("Expected no policy for an empty string, got %v, %v", policy, err)
+	}
+
+	const encoded = `{"read_paths": ["/models"], "write_paths": ["/tmp"], "network": true}`
+	policy, err := parsePolicy(encoded)
+	if err != nil {
+		t.Fatalf("Failed to parse policy: %v", err)
+	}
+	if len(policy.ReadPaths) != 1 || policy.ReadPaths[0] != "/models" || len(policy.WritePaths) != 1 || !policy.Network {
+		t.Errorf("Unexpected policy: %+v", policy)
+	}
+
+	if _, err := parsePolicy(`{"readPaths": ["/models"]}`); err == nil {
+		t.Error("Expected unknown fields to be rejected")
+	}
+
+	// Policies are rejected where they would be ignored.
+	_, err = ParsePolicy(encoded)
+	if policyEnforced(*policy) && err != nil {
+		t.Errorf("Expected the policy to be accepted, got %v", err)
+	}
+	if !policyEnforced(*policy) && !errors.Is(err, ErrPolicyNotEnforced) {
+		t.Errorf("Expected ErrPolicyNotEnforced, got %v", err)
+	}
+}
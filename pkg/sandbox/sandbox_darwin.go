@@ -101,6 +101,17 @@ const ConfigurationLlamaCpp = `(version 1)
     (subpath "[WORKDIR]"))
 `
 
+// policyEnforced returns true since sandbox-exec profiles express every
+// policy.
+func policyEnforced(Policy) bool {
+	return true
+}
+
+// Configuration renders the policy as a sandbox-exec profile for Create.
+func (p Policy) Configuration() string {
+	return p.profile()
+}
+
 // sandbox is the Darwin sandbox implementation.
 type sandbox struct {
 	// cancel cancels the context associated with the process.
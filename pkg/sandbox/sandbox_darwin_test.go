@@ -0,0 +1,42 @@
+package sandbox
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestPolicyConfiguration tests that sandbox-exec accepts and enforces the
+// profile a policy renders.
+func TestPolicyConfiguration(t *testing.T) {
+	// Profiles match resolved paths, and temporary directories live behind
+	// the /var symlink.
+	writable, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal("unable to resolve temporary directory:", err)
+	}
+	other, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal("unable to resolve temporary directory:", err)
+	}
+	configuration := Policy{WritePaths: []string{writable}}.Configuration()
+
+	touch := func(path string) error {
+		sandbox, err := Create(t.Context(), configuration, nil, "", "/usr/bin/touch", path)
+		if err != nil {
+			t.Fatal("unable to create sandboxed process:", err)
+		}
+		defer sandbox.Close()
+		return sandbox.Command().Wait()
+	}
+
+	if err := touch(filepath.Join(writable, "allowed")); err != nil {
+		t.Error("expected writing to a write path to succeed:", err)
+	}
+	if err := touch(filepath.Join(other, "denied")); err == nil {
+		t.Error("expected writing outside of the write paths to fail")
+	}
+	if _, err := os.Stat(filepath.Join(other, "denied")); err == nil {
+		t.Error("expected the denied file not to be created")
+	}
+}
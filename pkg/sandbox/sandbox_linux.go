@@ -0,0 +1,184 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+const (
+	// landlockReadAccess are the Landlock rights to read and execute files.
+	landlockReadAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_DIR
+	// landlockFileAccess are the Landlock rights that apply to files rather
+	// than directories.
+	landlockFileAccess = unix.LANDLOCK_ACCESS_FS_EXECUTE |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_READ_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	// landlockDeviceAccess are the Landlock rights to use devices, e.g. GPUs.
+	landlockDeviceAccess = landlockReadAccess |
+		unix.LANDLOCK_ACCESS_FS_WRITE_FILE |
+		unix.LANDLOCK_ACCESS_FS_TRUNCATE |
+		unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	// landlockSocketAccess are the Landlock rights to create and remove Unix
+	// sockets in their directory.
+	landlockSocketAccess = unix.LANDLOCK_ACCESS_FS_MAKE_SOCK |
+		unix.LANDLOCK_ACCESS_FS_REMOVE_FILE
+)
+
+// landlockSystemPaths are the system directories sandboxed processes may
+// read.
+var landlockSystemPaths = []string{
+	"/bin", "/etc", "/lib", "/lib32", "/lib64", "/opt", "/proc", "/sbin", "/sys", "/usr",
+}
+
+// policyEnforced returns whether the running kernel supports Landlock and, if
+// the policy denies network access, Landlock's TCP restrictions.
+func policyEnforced(p Policy) bool {
+	abi := landlockABI()
+	return abi >= 1 && (p.Network || abi >= 4)
+}
+
+// Configuration renders the policy for Create, which enforces it with
+// Landlock. Denying network access denies TCP, and kernels without Landlock's
+// TCP restrictions only enforce the policy's file access.
+func (p Policy) Configuration() string {
+	configuration, err := json.Marshal(p)
+	if err != nil {
+		panic(fmt.Sprintf("unable to encode sandbox policy: %v", err))
+	}
+	return string(configuration)
+}
+
+// start starts the command confined by the policy the configuration encodes,
+// if any. Landlock confines threads, and Go can't run code between forking
+// and executing the process, so the policy is applied to a thread of its own
+// that then starts the command. That thread remains locked, so the runtime
+// terminates it rather than reusing it once the goroutine returns.
+func start(command *exec.Cmd, configuration string) error {
+	if configuration == "" {
+		return command.Start()
+	}
+	policy, err := parsePolicy(configuration)
+	if err != nil {
+		return err
+	}
+	abi := landlockABI()
+	if abi < 1 {
+		return command.Start()
+	}
+
+	result := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		if err := policy.restrictThread(abi); err != nil {
+			result <- fmt.Errorf("unable to apply sandbox policy: %w", err)
+			return
+		}
+		result <- command.Start()
+	}()
+	return <-result
+}
+
+// restrictThread confines the calling thread, and the processes it starts,
+// to the policy with the Landlock features of the given ABI version.
+func (p Policy) restrictThread(abi int) error {
+	handled := landlockHandledAccess(abi)
+	attr := unix.LandlockRulesetAttr{Access_fs: handled}
+	if !p.Network && abi >= 4 {
+		attr.Access_net = unix.LANDLOCK_ACCESS_NET_BIND_TCP | unix.LANDLOCK_ACCESS_NET_CONNECT_TCP
+	}
+	fd, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET,
+		uintptr(unsafe.Pointer(&attr)), unsafe.Sizeof(attr), 0)
+	if errno != 0 {
+		return fmt.Errorf("unable to create Landlock ruleset: %w", errno)
+	}
+	ruleset := int(fd)
+	defer unix.Close(ruleset)
+
+	rules := make(map[string]uint64)
+	for _, path := range append(append([]string{}, landlockSystemPaths...), p.ReadPaths...) {
+		rules[path] |= landlockReadAccess
+	}
+	rules["/dev"] |= landlockDeviceAccess
+	for _, path := range append([]string{"/dev/shm"}, p.WritePaths...) {
+		rules[path] |= handled
+	}
+	for _, socket := range p.Sockets {
+		rules[filepath.Dir(socket)] |= landlockSocketAccess
+	}
+	for path, access := range rules {
+		if err := addLandlockRule(ruleset, path, access&handled); err != nil {
+			return err
+		}
+	}
+
+	if err := unix.Prctl(unix.PR_SET_NO_NEW_PRIVS, 1, 0, 0, 0); err != nil {
+		return fmt.Errorf("unable to set no_new_privs: %w", err)
+	}
+	if _, _, errno := unix.Syscall(unix.SYS_LANDLOCK_RESTRICT_SELF, uintptr(ruleset), 0, 0); errno != 0 {
+		return fmt.Errorf("unable to enforce Landlock ruleset: %w", errno)
+	}
+	return nil
+}
+
+// addLandlockRule grants access beneath path, skipping paths that don't
+// exist.
+func addLandlockRule(ruleset int, path string, access uint64) error {
+	fd, err := unix.Open(path, unix.O_PATH|unix.O_CLOEXEC, 0)
+	if errors.Is(err, unix.ENOENT) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("unable to open %s: %w", path, err)
+	}
+	defer unix.Close(fd)
+
+	var stat unix.Stat_t
+	if err := unix.Fstat(fd, &stat); err != nil {
+		return fmt.Errorf("unable to stat %s: %w", path, err)
+	}
+	if stat.Mode&unix.S_IFMT != unix.S_IFDIR {
+		access &= landlockFileAccess
+	}
+	attr := unix.LandlockPathBeneathAttr{Allowed_access: access, Parent_fd: int32(fd)}
+	if _, _, errno := unix.Syscall6(unix.SYS_LANDLOCK_ADD_RULE, uintptr(ruleset),
+		unix.LANDLOCK_RULE_PATH_BENEATH, uintptr(unsafe.Pointer(&attr)), 0, 0, 0); errno != 0 {
+		return fmt.Errorf("unable to allow access to %s: %w", path, errno)
+	}
+	return nil
+}
+
+// landlockHandledAccess returns the Landlock file access rights of the given
+// ABI version.
+func landlockHandledAccess(abi int) uint64 {
+	access := uint64(unix.LANDLOCK_ACCESS_FS_MAKE_SYM<<1 - 1)
+	if abi >= 2 {
+		access |= unix.LANDLOCK_ACCESS_FS_REFER
+	}
+	if abi >= 3 {
+		access |= unix.LANDLOCK_ACCESS_FS_TRUNCATE
+	}
+	if abi >= 5 {
+		access |= unix.LANDLOCK_ACCESS_FS_IOCTL_DEV
+	}
+	return access
+}
+
+// landlockABI returns the Landlock ABI version of the running kernel, or 0 if
+// Landlock is unsupported or disabled.
+func landlockABI() int {
+	abi, _, errno := unix.Syscall(unix.SYS_LANDLOCK_CREATE_RULESET, 0, 0, unix.LANDLOCK_CREATE_RULESET_VERSION)
+	if errno != 0 {
+		return 0
+	}
+	return int(abi)
+}
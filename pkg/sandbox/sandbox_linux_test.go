@@ -0,0 +1,85 @@
+package sandbox
+
+import (
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestPolicyConfiguration tests that Create enforces the policy a
+// configuration renders, without confining the calling process.
+func TestPolicyConfiguration(t *testing.T) {
+	if landlockABI() < 1 {
+		t.Skip("Landlock is not supported by this kernel")
+	}
+	writable := t.TempDir()
+	other := t.TempDir()
+	configuration := Policy{WritePaths: []string{writable}}.Configuration()
+
+	touch := func(path string) error {
+		sandbox, err := Create(t.Context(), configuration, nil, "", "touch", path)
+		if err != nil {
+			t.Fatal("unable to create sandboxed process:", err)
+		}
+		defer sandbox.Close()
+		return sandbox.Command().Wait()
+	}
+
+	if err := touch(filepath.Join(writable, "allowed")); err != nil {
+		t.Error("expected writing to a write path to succeed:", err)
+	}
+	if err := touch(filepath.Join(other, "denied")); err == nil {
+		t.Error("expected writing outside of the write paths to fail")
+	}
+	if _, err := os.Stat(filepath.Join(other, "denied")); err == nil {
+		t.Error("expected the denied file not to be created")
+	}
+	if err := os.WriteFile(filepath.Join(other, "unconfined"), nil, 0o644); err != nil {
+		t.Error("expected the calling process not to be confined:", err)
+	}
+}
+
+// TestPolicyConfigurationNetwork tests that Create denies TCP connections
+// unless the policy allows network access.
+func TestPolicyConfigurationNetwork(t *testing.T) {
+	if landlockABI() < 4 {
+		t.Skip("Landlock TCP restrictions are not supported by this kernel")
+	}
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash is not available")
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal("unable to listen:", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			conn.Close()
+		}
+	}()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	connect := func(policy Policy) error {
+		sandbox, err := Create(t.Context(), policy.Configuration(), nil, "",
+			"bash", "-c", "exec 3<>/dev/tcp/127.0.0.1/"+port)
+		if err != nil {
+			t.Fatal("unable to create sandboxed process:", err)
+		}
+		defer sandbox.Close()
+		return sandbox.Command().Wait()
+	}
+
+	if err := connect(Policy{}); err == nil {
+		t.Error("expected connecting without network access to fail")
+	}
+	if err := connect(Policy{Network: true}); err != nil {
+		t.Error("expected connecting with network access to succeed:", err)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !darwin && !linux && !windows
+
+package sandbox
+
+import (
+	"os/exec"
+)
+
+// policyEnforced returns false since processes are not sandboxed on this
+// platform.
+func policyEnforced(Policy) bool {
+	return false
+}
+
+// Configuration renders the policy for Create. Processes are not sandboxed on
+// this platform yet, so the policy is not enforced.
+func (p Policy) Configuration() string {
+	return ""
+}
+
+// start starts the command, which is not sandboxed on this platform.
+func start(command *exec.Cmd, _ string) error {
+	return command.Start()
+}
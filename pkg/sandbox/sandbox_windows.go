@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os/exec"
 	"regexp"
+	"strings"
 
 	"github.com/kolesnikovae/go-winjob"
 )
@@ -41,6 +42,20 @@ const ConfigurationLlamaCpp = `(WithDesktopLimit)
 (WithWriteClipboardLimit)
 `
 
+// policyEnforced returns false since job objects can't restrict file access.
+func policyEnforced(Policy) bool {
+	return false
+}
+
+// Configuration renders the policy as job object limits for Create. Job
+// objects cannot restrict file access, so only Network is enforced.
+func (p Policy) Configuration() string {
+	if p.Network {
+		return strings.ReplaceAll(ConfigurationLlamaCpp, "(WithDisableOutgoingNetworking)\n", "")
+	}
+	return ConfigurationLlamaCpp
+}
+
 // sandbox is the Windows sandbox implementation.
 type sandbox struct {
 	// job is the Windows Job object that encapsulates the process.
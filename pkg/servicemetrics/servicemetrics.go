@@ -0,0 +1,183 @@
+// Package servicemetrics counts operations of the model runner daemon, such as
+// model pulls and inference requests, and renders them as Prometheus metric
+// families for the /metrics endpoint.
+//
+// All methods are no-ops on a nil *Metrics, so components record metrics
+// unconditionally and only pay for them when metrics are enabled.
+package servicemetrics
+
+import (
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+// Metric names exposed by Families.
+const (
+	PullsTotal             = "model_runner_pulls_total"
+	ActivePulls            = "model_runner_active_pulls"
+	DownloadedBytesTotal   = "model_runner_downloaded_bytes_total"
+	StoreSizeBytes         = "model_runner_store_size_bytes"
+	InferenceRequestsTotal = "model_runner_inference_requests_total"
+)
+
+// Pull results, used as the "result" label of PullsTotal.
+const (
+	PullSucceeded = "success"
+	PullFailed    = "failure"
+)
+
+// Metrics holds the daemon's counters and gauges.
+type Metrics struct {
+	pulls             labeledCounter
+	activePulls       atomic.Int64
+	downloadedBytes   atomic.Int64
+	inferenceRequests labeledCounter
+
+	// storeSizeMu protects storeSize.
+	storeSizeMu sync.Mutex
+	// storeSize reports the size of the model store when metrics are
+	// gathered. It may be nil.
+	storeSize func() (int64, error)
+}
+
+// New creates a new set of metrics with all counters at zero.
+func New() *Metrics {
+	return &Metrics{
+		pulls:             labeledCounter{labels: []string{"result"}},
+		inferenceRequests: labeledCounter{labels: []string{"backend", "mode"}},
+	}
+}
+
+// PullStarted records the start of a model download and returns a function
+// to call with its error, or nil, when it finishes.
+func (m *Metrics) PullStarted() func(err error) {
+	if m == nil {
+		return func(error) {}
+	}
+	m.activePulls.Add(1)
+	return func(err error) {
+		m.activePulls.Add(-1)
+		result := PullSucceeded
+		if err != nil {
+			result = PullFailed
+		}
+		m.pulls.inc(result)
+	}
+}
+
+// AddDownloadedBytes records n bytes received from a registry.
+func (m *Metrics) AddDownloadedBytes(n int64) {
+	if m == nil {
+		return
+	}
+	m.downloadedBytes.Add(n)
+}
+
+// InferenceRequest records an inference request served by backend in mode.
+func (m *Metrics) InferenceRequest(backend, mode string) {
+	if m == nil {
+		return
+	}
+	m.inferenceRequests.inc(backend, mode)
+}
+
+// SetStoreSize sets the function reporting the size of the model store in
+// bytes. It is called each time the metrics are gathered.
+func (m *Metrics) SetStoreSize(size func() (int64, error)) {
+	if m == nil {
+		return
+	}
+	m.storeSizeMu.Lock()
+	defer m.storeSizeMu.Unlock()
+	m.storeSize = size
+}
+
+// Families returns the current values of the metrics. Labeled counters
+// without values yet and the store size, if it cannot be determined, are
+// omitted, since families without metrics cannot be encoded.
+func (m *Metrics) Families() []*dto.MetricFamily {
+	if m == nil {
+		return nil
+	}
+	all := []*dto.MetricFamily{
+		m.pulls.family(PullsTotal, "Number of model downloads, by result."),
+		gauge(ActivePulls, "Number of model downloads in progress.", m.activePulls.Load()),
+		counter(DownloadedBytesTotal, "Number of bytes received from registries.", m.downloadedBytes.Load()),
+		m.inferenceRequests.family(InferenceRequestsTotal, "Number of inference requests, by backend and mode."),
+	}
+
+	m.storeSizeMu.Lock()
+	storeSize := m.storeSize
+	m.storeSizeMu.Unlock()
+	if storeSize != nil {
+		if size, err := storeSize(); err == nil {
+			all = append(all, gauge(StoreSizeBytes, "Size of the model store in bytes.", size))
+		}
+	}
+
+	families := make([]*dto.MetricFamily, 0, len(all))
+	for _, family := range all {
+		if len(family.Metric) > 0 {
+			families = append(families, family)
+		}
+	}
+	return families
+}
+
+// labeledCounter is a counter partitioned by the values of a fixed set of
+// labels.
+type labeledCounter struct {
+	labels []string
+	// mu protects counts.
+	mu sync.Mutex
+	// counts maps the label values, joined by a NUL byte, to their count.
+	counts map[string]int64
+}
+
+func (c *labeledCounter) inc(values ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[string]int64)
+	}
+	c.counts[strings.Join(values, "\x00")]++
+}
+
+func (c *labeledCounter) family(name, help string) *dto.MetricFamily {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	family := newFamily(name, help, dto.MetricType_COUNTER)
+	for key, count := range c.counts {
+		values := strings.Split(key, "\x00")
+		metric := &dto.Metric{Counter: &dto.Counter{Value: float64Ptr(count)}}
+		for i, label := range c.labels {
+			metric.Label = append(metric.Label, &dto.LabelPair{Name: &label, Value: &values[i]})
+		}
+		family.Metric = append(family.Metric, metric)
+	}
+	return family
+}
+
+func counter(name, help string, value int64) *dto.MetricFamily {
+	family := newFamily(name, help, dto.MetricType_COUNTER)
+	family.Metric = []*dto.Metric{{Counter: &dto.Counter{Value: float64Ptr(value)}}}
+	return family
+}
+
+func gauge(name, help string, value int64) *dto.MetricFamily {
+	family := newFamily(name, help, dto.MetricType_GAUGE)
+	family.Metric = []*dto.Metric{{Gauge: &dto.Gauge{Value: float64Ptr(value)}}}
+	return family
+}
+
+func newFamily(name, help string, typ dto.MetricType) *dto.MetricFamily {
+	return &dto.MetricFamily{Name: &name, Help: &help, Type: &typ}
+}
+
+func float64Ptr(v int64) *float64 {
+	f := float64(v)
+	return &f
+}
@@ -0,0 +1,132 @@
+package servicemetrics
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	dto "github.com/prometheus/client_model/go"
+)
+
+func familyByName(families []*dto.MetricFamily, name string) *dto.MetricFamily {
+	for _, family := range families {
+		if family.GetName() == name {
+			return family
+		}
+	}
+	return nil
+}
+
+func TestPulls(t *testing.T) {
+	m := New()
+	succeeded := m.PullStarted()
+	failed := m.PullStarted()
+
+	active := familyByName(m.Families(), ActivePulls)
+	if active == nil || active.Metric[0].GetGauge().GetValue() != 2 {
+		t.Fatalf("Expected 2 active pulls, got %v", active)
+	}
+	if familyByName(m.Families(), PullsTotal) != nil {
+		t.Errorf("Expected %s to be omitted before any pull finished", PullsTotal)
+	}
+
+	succeeded(nil)
+	failed(errors.New("pull failed"))
+
+	families := m.Families()
+	if got := familyByName(families, ActivePulls).Metric[0].GetGauge().GetValue(); got != 0 {
+		t.Errorf("Expected 0 active pulls, got %v", got)
+	}
+	pulls := familyByName(families, PullsTotal)
+	if pulls == nil || len(pulls.Metric) != 2 {
+		t.Fatalf("Expected pulls by 2 results, got %v", pulls)
+	}
+	for _, metric := range pulls.Metric {
+		if got := metric.GetCounter().GetValue(); got != 1 {
+			t.Errorf("Expected 1 pull with result %s, got %v", metric.Label[0].GetValue(), got)
+		}
+	}
+}
+
+func TestInferenceRequests(t *testing.T) {
+	m := New()
+	m.InferenceRequest("llama.cpp", "completion")
+	m.InferenceRequest("llama.cpp", "completion")
+	m.InferenceRequest("llama.cpp", "embedding")
+
+	requests := familyByName(m.Families(), InferenceRequestsTotal)
+	if requests == nil || len(requests.Metric) != 2 {
+		t.Fatalf("Expected requests in 2 modes, got %v", requests)
+	}
+	for _, metric := range requests.Metric {
+		labels := map[string]string{}
+		for _, label := range metric.Label {
+			labels[label.GetName()] = label.GetValue()
+		}
+		want := 1.0
+		if labels["mode"] == "completion" {
+			want = 2
+		}
+		if labels["backend"] != "llama.cpp" || metric.GetCounter().GetValue() != want {
+			t.Errorf("Expected %v requests for %v, got %v", want, labels, metric.GetCounter().GetValue())
+		}
+	}
+}
+
+func TestStoreSize(t *testing.T) {
+	m := New()
+	if familyByName(m.Families(), StoreSizeBytes) != nil {
+		t.Errorf("Expected %s to be omitted without a size function", StoreSizeBytes)
+	}
+
+	m.SetStoreSize(func() (int64, error) { return 0, errors.New("store unavailable") })
+	if familyByName(m.Families(), StoreSizeBytes) != nil {
+		t.Errorf("Expected %s to be omitted when the size cannot be determined", StoreSizeBytes)
+	}
+
+	m.SetStoreSize(func() (int64, error) { return 42, nil })
+	size := familyByName(m.Families(), StoreSizeBytes)
+	if size == nil || size.Metric[0].GetGauge().GetValue() != 42 {
+		t.Errorf("Expected store size 42, got %v", size)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, strings.Repeat("x", 1000))
+	}))
+	defer server.Close()
+
+	m := New()
+	client := &http.Client{Transport: m.Transport(http.DefaultTransport)}
+	resp, err := client.Get(server.URL)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	resp.Body.Close()
+
+	downloaded := familyByName(m.Families(), DownloadedBytesTotal)
+	if downloaded == nil || downloaded.Metric[0].GetCounter().GetValue() != 1000 {
+		t.Errorf("Expected 1000 downloaded bytes, got %v", downloaded)
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *Metrics
+	m.PullStarted()(nil)
+	m.AddDownloadedBytes(1)
+	m.InferenceRequest("llama.cpp", "completion")
+	m.SetStoreSize(func() (int64, error) { return 1, nil })
+	if families := m.Families(); families != nil {
+		t.Errorf("Expected no families, got %v", families)
+	}
+	if transport := m.Transport(http.DefaultTransport); transport != http.DefaultTransport {
+		t.Errorf("Expected the base transport to be returned unchanged")
+	}
+}
@@ -0,0 +1,42 @@
+package servicemetrics
+
+import (
+	"io"
+	"net/http"
+)
+
+// Transport returns a transport that sends requests with base and records the
+// bytes of the response bodies read as downloaded. It returns base unchanged
+// on a nil *Metrics.
+func (m *Metrics) Transport(base http.RoundTripper) http.RoundTripper {
+	if m == nil {
+		return base
+	}
+	return &countingTransport{base: base, metrics: m}
+}
+
+type countingTransport struct {
+	base    http.RoundTripper
+	metrics *Metrics
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil || resp.Body == nil {
+		return resp, err
+	}
+	resp.Body = &countingBody{ReadCloser: resp.Body, metrics: t.metrics}
+	return resp, nil
+}
+
+// countingBody records the bytes read from a response body.
+type countingBody struct {
+	io.ReadCloser
+	metrics *Metrics
+}
+
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.metrics.AddDownloadedBytes(int64(n))
+	return n, err
+}
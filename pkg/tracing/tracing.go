@@ -0,0 +1,181 @@
+// Package tracing records OpenTelemetry spans for model operations such as
+// pulls, pushes, loads and chats, and propagates them between the CLI and the
+// daemon using W3C traceparent headers.
+//
+// Spans are created with the global tracer provider, which discards them
+// unless Setup has installed an OTLP exporter, so tracing costs next to
+// nothing by default.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName identifies the spans created by this package.
+const tracerName = "github.com/docker/model-runner"
+
+// Span names for the traced operations.
+const (
+	SpanPull = "model.pull"
+	SpanPush = "model.push"
+	SpanLoad = "model.load"
+	SpanChat = "model.chat"
+)
+
+// Attribute keys recorded on spans.
+const (
+	// AttrModel is the model reference the operation targets.
+	AttrModel = attribute.Key("model.ref")
+	// AttrBytes is the number of bytes the operation transferred or, for
+	// pulls and pushes, the size of the model.
+	AttrBytes = attribute.Key("model.bytes")
+	// AttrDuration is the duration of the operation in milliseconds.
+	AttrDuration = attribute.Key("duration_ms")
+)
+
+// propagator carries span contexts in traceparent and tracestate headers. It
+// is used instead of the global propagator, which is a no-op by default.
+var propagator = propagation.TraceContext{}
+
+// Span is an in-progress traced operation.
+type Span struct {
+	span  trace.Span
+	start time.Time
+}
+
+// Start starts a span for an operation on model as a child of any span in
+// ctx, returning a context carrying the new span. model may be empty for
+// operations that do not target a known model.
+func Start(ctx context.Context, name, model string, kind trace.SpanKind) (context.Context, *Span) {
+	opts := []trace.SpanStartOption{trace.WithSpanKind(kind)}
+	if model != "" {
+		opts = append(opts, trace.WithAttributes(AttrModel.String(model)))
+	}
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name, opts...)
+	return ctx, &Span{span: span, start: time.Now()}
+}
+
+// StartServer starts a server span for an operation on model, continuing any
+// trace propagated in the headers of r. It returns r with a context carrying
+// the new span.
+func StartServer(r *http.Request, name, model string) (*http.Request, *Span) {
+	ctx := propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := Start(ctx, name, model, trace.SpanKindServer)
+	return r.WithContext(ctx), span
+}
+
+// Inject adds the headers propagating the span in ctx to header. It does
+// nothing if ctx carries no span.
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// SetBytes records the number of bytes the operation handled.
+func (s *Span) SetBytes(n int64) {
+	s.span.SetAttributes(AttrBytes.Int64(n))
+}
+
+// End records the duration of the operation and err, if any, and ends the
+// span.
+func (s *Span) End(err error) {
+	s.span.SetAttributes(AttrDuration.Int64(time.Since(s.start).Milliseconds()))
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+// maxErrorBodyLen is the number of bytes of an error response recorded as
+// the span's error.
+const maxErrorBodyLen = 512
+
+// ResponseWriter records the status of the response written through it and
+// the start of its body if the status is an error.
+type ResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   []byte
+}
+
+// NewResponseWriter returns a writer recording the response written to w.
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *ResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter.
+func (w *ResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if w.status >= http.StatusBadRequest && len(w.body) < maxErrorBodyLen {
+		w.body = append(w.body, p[:min(len(p), maxErrorBodyLen-len(w.body))]...)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher if the underlying writer does.
+func (w *ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer, for http.ResponseController.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Err returns an error describing the response if its status is an error,
+// and nil otherwise.
+func (w *ResponseWriter) Err() error {
+	if w.status < http.StatusBadRequest {
+		return nil
+	}
+	if msg := strings.TrimSpace(string(w.body)); msg != "" {
+		return fmt.Errorf("%d %s: %s", w.status, http.StatusText(w.status), msg)
+	}
+	return fmt.Errorf("%d %s", w.status, http.StatusText(w.status))
+}
+
+// CountingReader counts the bytes read through it.
+type CountingReader struct {
+	r io.Reader
+	n int64
+}
+
+// NewCountingReader returns a reader counting the bytes read from r.
+func NewCountingReader(r io.Reader) *CountingReader {
+	return &CountingReader{r: r}
+}
+
+// Read implements io.Reader.
+func (r *CountingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// N returns the number of bytes read so far.
+func (r *CountingReader) N() int64 {
+	return r.n
+}
@@ -0,0 +1,201 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider exporting to memory for the duration
+// of the test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestSpanAttributes(t *testing.T) {
+	exporter := recordSpans(t)
+
+	_, span := Start(context.Background(), SpanPull, "ai/smollm2", trace.SpanKindClient)
+	span.SetBytes(42)
+	span.End(nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Name != SpanPull {
+		t.Errorf("span name = %q, want %q", spans[0].Name, SpanPull)
+	}
+	if spans[0].SpanKind != trace.SpanKindClient {
+		t.Errorf("span kind = %v, want %v", spans[0].SpanKind, trace.SpanKindClient)
+	}
+	attrs := attributes(spans[0])
+	if got := attrs[AttrModel].AsString(); got != "ai/smollm2" {
+		t.Errorf("%s = %q, want %q", AttrModel, got, "ai/smollm2")
+	}
+	if got := attrs[AttrBytes].AsInt64(); got != 42 {
+		t.Errorf("%s = %d, want 42", AttrBytes, got)
+	}
+	if _, ok := attrs[AttrDuration]; !ok {
+		t.Errorf("%s not recorded", AttrDuration)
+	}
+	if spans[0].Status.Code != codes.Unset {
+		t.Errorf("status = %v, want unset", spans[0].Status.Code)
+	}
+}
+
+func TestSpanError(t *testing.T) {
+	exporter := recordSpans(t)
+
+	_, span := Start(context.Background(), SpanPush, "", trace.SpanKindClient)
+	span.End(errors.New("push failed"))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("got %d spans, want 1", len(spans))
+	}
+	if spans[0].Status.Code != codes.Error || spans[0].Status.Description != "push failed" {
+		t.Errorf("status = %+v, want error %q", spans[0].Status, "push failed")
+	}
+	if _, ok := attributes(spans[0])[AttrModel]; ok {
+		t.Errorf("%s recorded for an operation without a model", AttrModel)
+	}
+}
+
+func TestPropagation(t *testing.T) {
+	exporter := recordSpans(t)
+
+	ctx, client := Start(context.Background(), SpanChat, "ai/smollm2", trace.SpanKindClient)
+	req := httptest.NewRequest(http.MethodPost, "/engines/v1/chat/completions", http.NoBody)
+	Inject(ctx, req.Header)
+	if req.Header.Get("traceparent") == "" {
+		t.Fatal("traceparent header not set")
+	}
+
+	req, server := StartServer(req, SpanChat, "ai/smollm2")
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		t.Error("request context does not carry the server span")
+	}
+	server.End(nil)
+	client.End(nil)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	serverSpan, clientSpan := spans[0], spans[1]
+	if serverSpan.SpanKind != trace.SpanKindServer {
+		t.Errorf("span kind = %v, want %v", serverSpan.SpanKind, trace.SpanKindServer)
+	}
+	if serverSpan.Parent.SpanID() != clientSpan.SpanContext.SpanID() {
+		t.Errorf("server span parent = %s, want %s", serverSpan.Parent.SpanID(), clientSpan.SpanContext.SpanID())
+	}
+	if serverSpan.SpanContext.TraceID() != clientSpan.SpanContext.TraceID() {
+		t.Error("server span is not in the client's trace")
+	}
+}
+
+func TestInjectWithoutSpan(t *testing.T) {
+	header := make(http.Header)
+	Inject(context.Background(), header)
+	if len(header) != 0 {
+		t.Errorf("headers = %v, want none", header)
+	}
+}
+
+func TestCountingReader(t *testing.T) {
+	r := NewCountingReader(strings.NewReader("hello world"))
+	if _, err := io.Copy(io.Discard, r); err != nil {
+		t.Fatal(err)
+	}
+	if r.N() != 11 {
+		t.Errorf("N() = %d, want 11", r.N())
+	}
+}
+
+func TestResponseWriter(t *testing.T) {
+	tests := []struct {
+		name    string
+		write   func(w http.ResponseWriter)
+		wantErr string
+	}{
+		{name: "success", write: func(w http.ResponseWriter) { _, _ = w.Write([]byte("ok")) }},
+		{name: "error", write: func(w http.ResponseWriter) { http.Error(w, "model not found", http.StatusNotFound) }, wantErr: "404 Not Found: model not found"},
+		{name: "error without body", write: func(w http.ResponseWriter) { w.WriteHeader(http.StatusBadGateway) }, wantErr: "502 Bad Gateway"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			w := NewResponseWriter(rec)
+			tt.write(w)
+			err := w.Err()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Err() = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Err() = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetup(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "")
+	t.Setenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT", "")
+	shutdown, err := Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup() without an endpoint: %v", err)
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() = %v", err)
+	}
+	if _, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); ok {
+		t.Error("Setup() installed a provider without an endpoint")
+	}
+
+	t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://127.0.0.1:4317")
+	shutdown, err = Setup(context.Background(), "test")
+	if err != nil {
+		t.Fatalf("Setup() with an endpoint: %v", err)
+	}
+	if _, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); !ok {
+		t.Errorf("tracer provider = %T, want %T", otel.GetTracerProvider(), &sdktrace.TracerProvider{})
+	}
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("shutdown() = %v", err)
+	}
+}